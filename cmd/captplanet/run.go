@@ -11,13 +11,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/gc"
+	"storj.io/storj/pkg/gracefulexit"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/miniogw"
 	"storj.io/storj/pkg/overlay"
 	psserver "storj.io/storj/pkg/piecestore/rpc/server"
 	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/pool"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/statdb"
 )
 
 const (
@@ -34,6 +38,10 @@ type Satellite struct {
 		Enabled bool   `default:"true" help:"if false, use real overlay"`
 		Host    string `default:"" help:"if set, the mock overlay will return storage nodes with this host"`
 	}
+	Pool         pool.Config
+	GracefulExit gracefulexit.Config
+	GC           gc.Config
+	StatDB       statdb.Config
 }
 
 // StorageNode is for configuring storage nodes
@@ -106,7 +114,11 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		errch <- runCfg.Satellite.Identity.Run(ctx,
 			runCfg.Satellite.Kademlia,
 			runCfg.Satellite.PointerDB,
-			o)
+			runCfg.Satellite.StatDB,
+			o,
+			runCfg.Satellite.Pool,
+			runCfg.Satellite.GracefulExit,
+			runCfg.Satellite.GC)
 	}()
 
 	// start s3 uplink
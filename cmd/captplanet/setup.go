@@ -4,7 +4,6 @@
 package main
 
 import (
-	"crypto/rand"
 	"fmt"
 	"net"
 	"os"
@@ -14,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/macaroon"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 )
@@ -74,6 +74,14 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	// the storage nodes only accept retain filters from the satellite
+	satellite, err := provider.IdentityConfig{
+		CertPath: setupCfg.HCIdentity.CertPath,
+		KeyPath:  setupCfg.HCIdentity.KeyPath,
+	}.Load()
+	if err != nil {
+		return err
+	}
 
 	for i := 0; i < len(runCfg.StorageNodes); i++ {
 		storagenodePath := filepath.Join(setupCfg.BasePath, fmt.Sprintf("f%d", i))
@@ -111,7 +119,11 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 
 	startingPort := setupCfg.StartingPort
 
-	apiKey, err := newAPIKey()
+	apiSecret, apiKey, err := newAPIKey()
+	if err != nil {
+		return err
+	}
+	adminSecret, adminKey, err := newAPIKey()
 	if err != nil {
 		return err
 	}
@@ -129,18 +141,25 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 			setupCfg.BasePath, "satellite", "pointerdb.db"),
 		"satellite.overlay.database-url": "bolt://" + filepath.Join(
 			setupCfg.BasePath, "satellite", "overlay.db"),
-		"uplink.cert-path": setupCfg.ULIdentity.CertPath,
-		"uplink.key-path":  setupCfg.ULIdentity.KeyPath,
+		"satellite.stat-db.database-url": "sqlite3://" + filepath.Join(
+			setupCfg.BasePath, "satellite", "stats.db"),
+		"satellite.pointer-db.api-secret": apiSecret,
+		"satellite.stat-db.api-secret":    apiSecret,
+		"satellite.stat-db.admin-secret":  adminSecret,
+		"uplink.cert-path":                setupCfg.ULIdentity.CertPath,
+		"uplink.key-path":                 setupCfg.ULIdentity.KeyPath,
 		"uplink.address": joinHostPort(
 			setupCfg.ListenHost, startingPort),
 		"uplink.overlay-addr": joinHostPort(
 			setupCfg.ListenHost, startingPort+1),
 		"uplink.pointer-db-addr": joinHostPort(
 			setupCfg.ListenHost, startingPort+1),
+		"uplink.stat-db-addr": joinHostPort(
+			setupCfg.ListenHost, startingPort+1),
 		"uplink.minio-dir": filepath.Join(
 			setupCfg.BasePath, "uplink", "minio"),
-		"uplink.api-key":          apiKey,
-		"pointer-db.auth.api-key": apiKey,
+		"uplink.api-key":           apiKey,
+		"uplink.stat-db-admin-key": adminKey,
 	}
 
 	for i := 0; i < len(runCfg.StorageNodes); i++ {
@@ -157,6 +176,7 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 		overrides[storagenode+"kademlia.bootstrap-addr"] = joinHostPort(
 			setupCfg.ListenHost, startingPort+1)
 		overrides[storagenode+"storage.path"] = filepath.Join(storagenodePath, "data")
+		overrides[storagenode+"storage.satellites"] = satellite.ID.String()
 	}
 
 	return process.SaveConfig(runCmd.Flags(),
@@ -167,11 +187,16 @@ func joinHostPort(host string, port int) string {
 	return net.JoinHostPort(host, fmt.Sprint(port))
 }
 
-func newAPIKey() (string, error) {
-	var buf [20]byte
-	_, err := rand.Read(buf[:])
+// newAPIKey creates a satellite api secret and an unrestricted api key
+// signed with it
+func newAPIKey() (secret, key string, err error) {
+	rawSecret, err := macaroon.NewSecret()
+	if err != nil {
+		return "", "", err
+	}
+	apiKey, err := macaroon.NewAPIKey(rawSecret)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	return base58.Encode(buf[:]), nil
+	return base58.Encode(rawSecret), apiKey.Serialize(), nil
 }
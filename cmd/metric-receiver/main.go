@@ -0,0 +1,101 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/telemetry"
+	"storj.io/storj/pkg/telemetry/receiver"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "metric-receiver",
+		Short: "Receive, aggregate and store the telemetry of the Storj processes",
+		RunE:  cmdRun,
+	}
+
+	runCfg struct {
+		Addr           string        `help:"address to listen for metrics on" default:":9000"`
+		HTTPAddr       string        `help:"address to serve the queries of the aggregated metrics on" default:"localhost:9001"`
+		Interval       time.Duration `help:"duration of the windows aggregating the metrics" default:"1m"`
+		Windows        int           `help:"number of windows kept in memory per series" default:"60"`
+		Output         string        `help:"where to write the windows once they end: file:///path, influx+udp://host:port, influx+tcp://host:port or graphite://host:port. If empty, they're only kept in memory" default:""`
+		Rate           float64       `help:"metrics per second accepted from a remote address" default:"1000"`
+		Burst          int           `help:"metrics accepted at once from a remote address" default:"10000"`
+		MaxSources     int           `help:"maximum number of remote addresses tracked, the metrics of the others are dropped" default:"10000"`
+		MaxSeries      int           `help:"maximum number of keys tracked per application instance, the metrics of the others are dropped" default:"10000"`
+		MaxTotalSeries int           `help:"maximum number of keys tracked in all, the metrics of the others are dropped" default:"1000000"`
+		MaxBytes       int64         `help:"memory the keys tracked may use, roughly, the metrics of the others are dropped" default:"1073741824"`
+	}
+)
+
+func init() {
+	cfgstruct.Bind(rootCmd.Flags(), &runCfg)
+}
+
+func cmdRun(cmd *cobra.Command, args []string) (err error) {
+	ctx, cancel := context.WithCancel(process.Ctx(cmd))
+	defer cancel()
+
+	opts := receiver.Options{
+		Interval:       runCfg.Interval,
+		Windows:        runCfg.Windows,
+		Rate:           runCfg.Rate,
+		Burst:          runCfg.Burst,
+		MaxSources:     runCfg.MaxSources,
+		MaxSeries:      runCfg.MaxSeries,
+		MaxTotalSeries: runCfg.MaxTotalSeries,
+		MaxBytes:       runCfg.MaxBytes,
+	}
+	if runCfg.Output != "" {
+		opts.Output, err = receiver.OpenOutput(runCfg.Output)
+		if err != nil {
+			return err
+		}
+		defer func() { err = utils.CombineErrors(err, opts.Output.Close()) }()
+	}
+	aggregator, err := receiver.NewAggregator(opts)
+	if err != nil {
+		return err
+	}
+
+	s, err := telemetry.Listen(runCfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(s)
+
+	httpServer := &http.Server{Addr: runCfg.HTTPAddr, Handler: aggregator.Handler()}
+	defer utils.LogClose(httpServer)
+
+	errch := make(chan error, 3)
+	go func() { errch <- s.Serve(ctx, aggregator) }()
+	go func() { errch <- aggregator.Run(ctx) }()
+	go func() { errch <- httpServer.ListenAndServe() }()
+
+	fmt.Printf("listening for metrics on %s, serving queries on %s\n", s.Addr(), runCfg.HTTPAddr)
+	err = <-errch
+	cancel()
+
+	// the windows not ended yet are lost
+	if flushErr := aggregator.Flush(context.Background()); flushErr != nil {
+		zap.S().Errorf("failed to write the metrics: %v", flushErr)
+	}
+	return err
+}
+
+func main() {
+	process.Exec(rootCmd)
+}
@@ -41,6 +41,12 @@ func (c cacheConfig) open() (*overlay.Cache, error) {
 			return nil, err
 		}
 		zap.S().Info("Starting overlay cache with Redis")
+	case "postgres", "postgresql", "sqlite3":
+		cache, err = overlay.NewSQLOverlayCache(c.DatabaseURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		zap.S().Info("Starting overlay cache with SQL")
 	default:
 		return nil, Error.New("database scheme not supported: %s", dburl.Scheme)
 	}
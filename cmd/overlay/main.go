@@ -52,13 +52,14 @@ func cmdList(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	keys, err := c.DB.List(nil, 0)
+	ctx := process.Ctx(cmd)
+	keys, err := c.DB.List(ctx, nil, 0)
 	if err != nil {
 		return err
 	}
 
 	for _, k := range keys {
-		n, err := c.Get(process.Ctx(cmd), string(k))
+		n, err := c.Get(ctx, string(k))
 		if err != nil {
 			zap.S().Infof("ID: %s; error getting value\n", k)
 		}
@@ -88,9 +89,10 @@ func cmdAdd(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	ctx := process.Ctx(cmd)
 	for i, a := range nodes {
 		zap.S().Infof("adding node ID: %s; Address: %s", i, a)
-		err := c.Put(i, proto.Node{
+		err := c.Put(ctx, i, proto.Node{
 			Id: i,
 			Address: &proto.NodeAddress{
 				Transport: 0,
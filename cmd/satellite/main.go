@@ -8,13 +8,19 @@ import (
 	"os"
 	"path/filepath"
 
+	base58 "github.com/jbenet/go-base58"
 	"github.com/spf13/cobra"
 	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/gc"
+	"storj.io/storj/pkg/gracefulexit"
 	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/macaroon"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/pool"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/statdb"
 )
 
 var (
@@ -34,11 +40,15 @@ var (
 	}
 
 	runCfg struct {
-		Identity    provider.IdentityConfig
-		Kademlia    kademlia.Config
-		PointerDB   pointerdb.Config
-		Overlay     overlay.Config
-		MockOverlay overlay.MockConfig
+		Identity     provider.IdentityConfig
+		Kademlia     kademlia.Config
+		PointerDB    pointerdb.Config
+		Overlay      overlay.Config
+		MockOverlay  overlay.MockConfig
+		Pool         pool.Config
+		GracefulExit gracefulexit.Config
+		GC           gc.Config
+		StatDB       statdb.Config
 	}
 	setupCfg struct {
 		BasePath  string `default:"$CONFDIR" help:"base path for setup"`
@@ -63,7 +73,8 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		o = runCfg.MockOverlay
 	}
 	return runCfg.Identity.Run(process.Ctx(cmd),
-		runCfg.Kademlia, runCfg.PointerDB, o)
+		runCfg.Kademlia, runCfg.PointerDB, runCfg.StatDB, o, runCfg.Pool,
+		runCfg.GracefulExit, runCfg.GC)
 }
 
 func cmdSetup(cmd *cobra.Command, args []string) (err error) {
@@ -96,13 +107,40 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	secret, err := macaroon.NewSecret()
+	if err != nil {
+		return err
+	}
+	apiKey, err := macaroon.NewAPIKey(secret)
+	if err != nil {
+		return err
+	}
+	adminSecret, err := macaroon.NewSecret()
+	if err != nil {
+		return err
+	}
+	adminKey, err := macaroon.NewAPIKey(adminSecret)
+	if err != nil {
+		return err
+	}
+
 	o := map[string]interface{}{
-		"identity.cert-path": setupCfg.Identity.CertPath,
-		"identity.key-path":  setupCfg.Identity.KeyPath,
+		"identity.cert-path":    setupCfg.Identity.CertPath,
+		"identity.key-path":     setupCfg.Identity.KeyPath,
+		"pointer-db.api-secret": base58.Encode(secret),
+		"stat-db.api-secret":    base58.Encode(secret),
+		"stat-db.admin-secret":  base58.Encode(adminSecret),
 	}
 
-	return process.SaveConfig(runCmd.Flags(),
+	err = process.SaveConfig(runCmd.Flags(),
 		filepath.Join(setupCfg.BasePath, "config.yaml"), o)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Unrestricted API key: %s\n", apiKey.Serialize())
+	fmt.Printf("Stat DB admin API key: %s\n", adminKey.Serialize())
+	return nil
 }
 
 func main() {
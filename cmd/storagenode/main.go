@@ -8,12 +8,16 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 
 	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/gracefulexit"
 	"storj.io/storj/pkg/kademlia"
 	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+	"storj.io/storj/pkg/pool"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
 )
 
 var (
@@ -31,6 +35,12 @@ var (
 		Short: "Create config files",
 		RunE:  cmdSetup,
 	}
+	exitCmd = &cobra.Command{
+		Use:   "exit <satellite-address>",
+		Short: "Transfer all pieces to other nodes and leave the network",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdExit,
+	}
 
 	runCfg struct {
 		Identity provider.IdentityConfig
@@ -42,6 +52,12 @@ var (
 		CA       provider.CASetupConfig
 		Identity provider.IdentitySetupConfig
 	}
+	exitCfg struct {
+		Identity provider.IdentityConfig
+		Storage  psserver.Config
+		Limit    int `help:"the maximum number of pieces transferred per satellite request" default:"100"`
+		Pool     pool.Config
+	}
 
 	defaultConfDir = "$HOME/.storj/storagenode"
 )
@@ -49,14 +65,48 @@ var (
 func init() {
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(setupCmd)
+	rootCmd.AddCommand(exitCmd)
 	cfgstruct.Bind(runCmd.Flags(), &runCfg, cfgstruct.ConfDir(defaultConfDir))
 	cfgstruct.Bind(setupCmd.Flags(), &setupCfg, cfgstruct.ConfDir(defaultConfDir))
+	cfgstruct.Bind(exitCmd.Flags(), &exitCfg, cfgstruct.ConfDir(defaultConfDir))
 }
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
 	return runCfg.Identity.Run(process.Ctx(cmd), runCfg.Kademlia, runCfg.Storage)
 }
 
+func cmdExit(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	identity, err := exitCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+
+	store, err := psserver.Initialize(ctx, exitCfg.Storage, identity.Key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Stop(ctx) }()
+
+	satellite, err := gracefulexit.NewSatelliteClient(identity, args[0])
+	if err != nil {
+		return err
+	}
+
+	minDifficulty, err := exitCfg.Identity.Difficulty()
+	if err != nil {
+		return err
+	}
+
+	conns := pool.NewConnectionManager(
+		transport.NewClient(identity, minDifficulty), exitCfg.Pool)
+	defer func() { _ = conns.Close() }()
+
+	worker := gracefulexit.NewWorker(zap.L(), identity, satellite, conns, store)
+	return worker.Run(ctx, exitCfg.Limit)
+}
+
 func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 	setupCfg.BasePath, err = filepath.Abs(setupCfg.BasePath)
 	if err != nil {
@@ -91,5 +141,7 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 func main() {
 	runCmd.Flags().String("config",
 		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
+	exitCmd.Flags().String("config",
+		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
 	process.Exec(rootCmd)
 }
@@ -0,0 +1,103 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/lifecycle"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+var (
+	lifecyclePrefix      *string
+	lifecycleExpireDays  *int
+	lifecycleAbortDays   *int
+	lifecycleRemoveRule  *bool
+	lifecycleClearBucket *bool
+)
+
+func init() {
+	lifecycleCmd := addCmd(&cobra.Command{
+		Use:   "lifecycle",
+		Short: "Show or change the lifecycle rules of a bucket",
+		RunE:  bucketLifecycle,
+	})
+	flags := lifecycleCmd.Flags()
+	lifecyclePrefix = flags.String("prefix", "", "the prefix of the objects the rule applies to")
+	lifecycleExpireDays = flags.Int("expire-days", 0, "delete the objects this many days after they are created, and their previous versions this many days after they are replaced")
+	lifecycleAbortDays = flags.Int("abort-incomplete-days", 0, "delete the incomplete uploads this many days after they are started")
+	lifecycleRemoveRule = flags.Bool("remove", false, "remove the rule of the prefix")
+	lifecycleClearBucket = flags.Bool("clear", false, "remove all rules")
+}
+
+func bucketLifecycle(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	if len(args) == 0 {
+		return fmt.Errorf("No bucket specified. Usage: lifecycle sj://bucket/ [flags]")
+	}
+
+	u, err := utils.ParseURL(args[0])
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("No bucket specified. Please use format sj://bucket/")
+	}
+
+	bs, err := cfg.BucketStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := bs.Get(ctx, u.Host)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return fmt.Errorf("Bucket not found: %s", u.Host)
+		}
+		return err
+	}
+
+	changed := *lifecycleClearBucket || *lifecycleRemoveRule ||
+		*lifecycleExpireDays != 0 || *lifecycleAbortDays != 0
+	if changed {
+		// every prefix has got at most one rule, identified by the prefix
+		var rules []lifecycle.Rule
+		if !*lifecycleClearBucket {
+			for _, rule := range m.Lifecycle {
+				if rule.Prefix != *lifecyclePrefix {
+					rules = append(rules, rule)
+				}
+			}
+			if !*lifecycleRemoveRule {
+				rules = append(rules, lifecycle.Rule{
+					ID:                        *lifecyclePrefix,
+					Prefix:                    *lifecyclePrefix,
+					ExpirationDays:            *lifecycleExpireDays,
+					AbortIncompleteUploadDays: *lifecycleAbortDays,
+				})
+			}
+		}
+		m, err = bs.SetLifecycle(ctx, u.Host, rules)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(m.Lifecycle) == 0 {
+		fmt.Printf("Bucket %s has no lifecycle rules\n", u.Host)
+		return nil
+	}
+	for _, rule := range m.Lifecycle {
+		fmt.Printf("prefix %q: expire after %d days, abort incomplete uploads after %d days\n",
+			rule.Prefix, rule.ExpirationDays, rule.AbortIncompleteUploadDays)
+	}
+
+	return nil
+}
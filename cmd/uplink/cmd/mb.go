@@ -5,20 +5,32 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/objects"
 	"storj.io/storj/pkg/utils"
+	ppb "storj.io/storj/protos/pointerdb"
 	"storj.io/storj/storage"
 )
 
+var (
+	mbRedundancy  *string
+	mbCompression *string
+)
+
 func init() {
-	addCmd(&cobra.Command{
+	mbCmd := addCmd(&cobra.Command{
 		Use:   "mb",
 		Short: "Create a new bucket",
 		RunE:  makeBucket,
 	})
+	mbRedundancy = mbCmd.Flags().String("redundancy", "",
+		"the redundancy scheme of the objects: rs, replication or cauchy (default rs)")
+	mbCompression = mbCmd.Flags().String("compression", "",
+		"the default compression of the objects: zstd or none (default none)")
 }
 
 func makeBucket(cmd *cobra.Command, args []string) error {
@@ -35,6 +47,14 @@ func makeBucket(cmd *cobra.Command, args []string) error {
 	if u.Host == "" {
 		return fmt.Errorf("No bucket specified. Please use format sj://bucket/")
 	}
+	scheme := strings.ToUpper(*mbRedundancy)
+	if _, ok := ppb.RedundancyScheme_SchemeType_value[scheme]; scheme != "" && !ok {
+		return fmt.Errorf("Unknown redundancy scheme %q", *mbRedundancy)
+	}
+	compression := strings.ToLower(*mbCompression)
+	if _, err := objects.ParseCompression(compression); err != nil {
+		return fmt.Errorf("Unknown compression %q", *mbCompression)
+	}
 
 	bs, err := cfg.BucketStore(ctx)
 	if err != nil {
@@ -52,6 +72,18 @@ func makeBucket(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if scheme != "" {
+		_, err = bs.SetRedundancy(ctx, u.Host, scheme)
+		if err != nil {
+			return err
+		}
+	}
+	if compression != "" {
+		_, err = bs.SetCompression(ctx, u.Host, compression)
+		if err != nil {
+			return err
+		}
+	}
 
 	fmt.Printf("Bucket %s created\n", u.Host)
 
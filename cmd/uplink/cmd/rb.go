@@ -10,6 +10,7 @@ import (
 
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
 	"storj.io/storj/pkg/utils"
 	"storj.io/storj/storage"
 )
@@ -64,6 +65,16 @@ func deleteBucket(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Bucket not empty: %s", u.Host)
 	}
 
+	if vs, ok := o.(objects.VersionedStore); ok {
+		versions, _, err := vs.ListVersions(ctx, nil, nil, 1)
+		if err != nil {
+			return err
+		}
+		if len(versions) > 0 {
+			return fmt.Errorf("Bucket has previous versions of objects: %s", u.Host)
+		}
+	}
+
 	err = bs.Delete(ctx, u.Host)
 	if err != nil {
 		return err
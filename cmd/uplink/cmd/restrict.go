@@ -0,0 +1,79 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/utils"
+	pb "storj.io/storj/protos/macaroon"
+)
+
+var restrictCfg struct {
+	disallowReads   bool
+	disallowWrites  bool
+	disallowLists   bool
+	disallowDeletes bool
+	allowedPaths    []string
+	expires         time.Duration
+}
+
+func init() {
+	cmd := addCmd(&cobra.Command{
+		Use:   "restrict",
+		Short: "Print a restricted copy of the configured api key",
+		RunE:  restrictKey,
+	})
+	flags := cmd.Flags()
+	flags.BoolVar(&restrictCfg.disallowReads, "disallow-reads", false, "disallow reading objects")
+	flags.BoolVar(&restrictCfg.disallowWrites, "disallow-writes", false, "disallow writing objects")
+	flags.BoolVar(&restrictCfg.disallowLists, "disallow-lists", false, "disallow listing objects")
+	flags.BoolVar(&restrictCfg.disallowDeletes, "disallow-deletes", false, "disallow deleting objects")
+	flags.StringArrayVar(&restrictCfg.allowedPaths, "allowed-path", nil,
+		"only allow access to objects under sj://bucket/prefix, can be repeated")
+	flags.DurationVar(&restrictCfg.expires, "expires", 0, "how long the key stays valid, 0 means forever")
+}
+
+func restrictKey(cmd *cobra.Command, args []string) error {
+	key, err := macaroon.ParseAPIKey(cfg.APIKey)
+	if err != nil {
+		return err
+	}
+
+	caveat := pb.Caveat{
+		DisallowReads:   restrictCfg.disallowReads,
+		DisallowWrites:  restrictCfg.disallowWrites,
+		DisallowLists:   restrictCfg.disallowLists,
+		DisallowDeletes: restrictCfg.disallowDeletes,
+	}
+	if restrictCfg.expires > 0 {
+		caveat.NotAfter = time.Now().Add(restrictCfg.expires).Unix()
+	}
+	for _, path := range restrictCfg.allowedPaths {
+		u, err := utils.ParseURL(path)
+		if err != nil {
+			return err
+		}
+		if u.Host == "" {
+			return fmt.Errorf("No bucket specified. Please use format sj://bucket/prefix")
+		}
+		caveat.AllowedPaths = append(caveat.AllowedPaths, &pb.CaveatPath{
+			Bucket:     []byte(u.Host),
+			PathPrefix: []byte(strings.TrimPrefix(u.Path, "/")),
+		})
+	}
+
+	restricted, err := key.Restrict(caveat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(restricted.Serialize())
+	return nil
+}
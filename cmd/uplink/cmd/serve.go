@@ -0,0 +1,227 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/storage"
+)
+
+var (
+	serveAddr    *string
+	serveListing *bool
+)
+
+func init() {
+	serveCmd := addCmd(&cobra.Command{
+		Use:   "serve",
+		Short: "Serve the Storj objects over HTTP",
+		RunE:  serveMain,
+	})
+	serveAddr = serveCmd.Flags().String("addr", "localhost:8080", "address to serve the objects on")
+	serveListing = serveCmd.Flags().Bool("listing", true, "if true, list the buckets and the prefixes ending with /")
+}
+
+func serveMain(cmd *cobra.Command, args []string) error {
+	for _, flagname := range args {
+		return fmt.Errorf("Invalid argument %#v. Try 'uplink serve'", flagname)
+	}
+
+	ctx := process.Ctx(cmd)
+	bs, err := cfg.BucketStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Serving sj://bucket/path at http://%s/bucket/path\n", *serveAddr)
+	return http.ListenAndServe(*serveAddr, &objectServer{bs: bs, listing: *serveListing})
+}
+
+// objectServer serves the object sj://bucket/path at /bucket/path, with
+// ranged GET and HEAD requests, and lists the buckets at / and the
+// prefixes at /bucket/prefix/ if listing is enabled
+type objectServer struct {
+	bs      buckets.Store
+	listing bool
+}
+
+func (s *objectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	p := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, objPath := p, ""
+	if i := strings.Index(p, "/"); i >= 0 {
+		bucket, objPath = p[:i], p[i+1:]
+	}
+
+	var err error
+	switch {
+	case objPath == "" && !strings.HasSuffix(p, "/") && bucket != "":
+		http.Redirect(w, r, "/"+bucket+"/", http.StatusMovedPermanently)
+		return
+	case objPath == "" || strings.HasSuffix(objPath, "/"):
+		if !s.listing {
+			http.NotFound(w, r)
+			return
+		}
+		if bucket == "" {
+			err = s.listBuckets(ctx, w)
+		} else {
+			err = s.listObjects(ctx, w, bucket, objPath)
+		}
+	default:
+		err = s.serveObject(ctx, w, r, bucket, objPath)
+	}
+	if err != nil {
+		s.serveError(w, r, err)
+	}
+}
+
+// serveObject serves the object at objPath in bucket
+func (s *objectServer) serveObject(ctx context.Context, w http.ResponseWriter,
+	r *http.Request, bucket, objPath string) error {
+	o, err := s.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	rr, m, err := o.Get(ctx, paths.New(objPath))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Etag", etag(m))
+	if m.ContentType != "" {
+		w.Header().Set("Content-Type", m.ContentType)
+	}
+	ranger.ServeContent(ctx, w, r, objPath, m.Modified, rr)
+	return nil
+}
+
+// etag returns the ETag of an object: its checksum if known, else a weak
+// one changing with its modification time and size
+func etag(m objects.Meta) string {
+	if m.Checksum != "" {
+		return fmt.Sprintf("%q", m.Checksum)
+	}
+	return fmt.Sprintf("W/\"%x-%x\"", m.Modified.UnixNano(), m.Size)
+}
+
+// listingItem is a line of a listing page
+type listingItem struct {
+	name, size, modified string
+}
+
+// listBuckets writes the page listing the buckets
+func (s *objectServer) listBuckets(ctx context.Context, w http.ResponseWriter) error {
+	var listing []listingItem
+	startAfter := ""
+	for {
+		items, more, err := s.bs.List(ctx, startAfter, "", 0)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			listing = append(listing, listingItem{
+				name:     item.Bucket + "/",
+				modified: formatTime(item.Meta.Created),
+			})
+		}
+		if !more {
+			break
+		}
+		startAfter = items[len(items)-1].Bucket
+	}
+
+	writeListing(w, "/", listing)
+	return nil
+}
+
+// listObjects writes the page listing the objects and the prefixes in
+// bucket under prefix
+func (s *objectServer) listObjects(ctx context.Context, w http.ResponseWriter,
+	bucket, prefix string) error {
+	o, err := s.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	listing := []listingItem{{name: "../"}}
+	startAfter := paths.New("")
+	for {
+		items, more, err := o.List(ctx, paths.New(prefix), startAfter, nil, false, 0,
+			meta.Modified|meta.Size)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.IsPrefix {
+				listing = append(listing, listingItem{name: item.Path.String() + "/"})
+				continue
+			}
+			listing = append(listing, listingItem{
+				name:     item.Path.String(),
+				size:     fmt.Sprint(item.Meta.Size),
+				modified: formatTime(item.Meta.Modified),
+			})
+		}
+		if !more {
+			break
+		}
+		startAfter = items[len(items)-1].Path
+	}
+
+	writeListing(w, path.Join("/", bucket, prefix)+"/", listing)
+	return nil
+}
+
+// writeListing writes a listing page, with links relative to the listed
+// prefix
+func writeListing(w http.ResponseWriter, title string, listing []listingItem) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	title = html.EscapeString(title)
+	_, _ = fmt.Fprintf(w, "<!DOCTYPE html>\n<html><head><title>%s</title></head>"+
+		"<body><h1>%s</h1><pre>\n", title, title)
+	for _, item := range listing {
+		link := (&url.URL{Path: item.name}).String()
+		_, _ = fmt.Fprintf(w, "<a href=\"%s\">%s</a> %s %12s\n",
+			html.EscapeString(link), html.EscapeString(item.name), item.modified, item.size)
+	}
+	_, _ = fmt.Fprintln(w, "</pre></body></html>")
+}
+
+// serveError replies with the status of err
+func (s *objectServer) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, ok := err.(minio.BucketNotFound); ok {
+		http.NotFound(w, r)
+		return
+	}
+	if storage.ErrKeyNotFound.Has(err) || objects.NoPathError.Has(err) {
+		http.NotFound(w, r)
+		return
+	}
+	zap.S().Errorf("serving %s: %v", r.URL.Path, err)
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
@@ -0,0 +1,239 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/storage"
+)
+
+// fakeBuckets is a buckets.Store of in-memory object stores, only
+// implementing what the object server uses
+type fakeBuckets struct {
+	buckets.Store
+	stores map[string]*fakeObjects
+	err    error
+}
+
+func (b *fakeBuckets) List(ctx context.Context, startAfter, endBefore string, limit int) (
+	items []buckets.ListItem, more bool, err error) {
+	if b.err != nil {
+		return nil, false, b.err
+	}
+	for _, name := range []string{"logs", "<tmp>"} {
+		if _, ok := b.stores[name]; ok && name > startAfter {
+			items = append(items, buckets.ListItem{Bucket: name})
+		}
+	}
+	return items, false, nil
+}
+
+func (b *fakeBuckets) GetObjectStore(ctx context.Context, bucket string) (objects.Store, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	o, ok := b.stores[bucket]
+	if !ok {
+		return nil, minio.BucketNotFound{Bucket: bucket}
+	}
+	return o, nil
+}
+
+// fakeObjects is an in-memory objects.Store, only implementing what the
+// object server uses
+type fakeObjects struct {
+	objects.Store
+	data    map[string]string
+	meta    objects.Meta
+	listing map[string][]objects.ListItem // by prefix
+}
+
+func (o *fakeObjects) Get(ctx context.Context, path paths.Path) (ranger.Ranger, objects.Meta, error) {
+	data, ok := o.data[path.String()]
+	if !ok {
+		return nil, objects.Meta{}, storage.ErrKeyNotFound.New(path.String())
+	}
+	meta := o.meta
+	meta.Size = int64(len(data))
+	return ranger.ByteRanger([]byte(data)), meta, nil
+}
+
+func (o *fakeObjects) List(ctx context.Context, prefix, startAfter, endBefore paths.Path,
+	recursive bool, limit int, metaFlags uint32) ([]objects.ListItem, bool, error) {
+	return o.listing[prefix.String()], false, nil
+}
+
+// modified is when the test objects were modified
+var modified = time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestObjectServer(listing bool) (*objectServer, *fakeBuckets) {
+	logs := &fakeObjects{
+		data: map[string]string{"a": "0123456789", "dir/b": "b"},
+		meta: objects.Meta{
+			SerializableMeta: objects.SerializableMeta{ContentType: "text/plain"},
+			Modified:         modified,
+			Checksum:         "abc",
+		},
+		listing: map[string][]objects.ListItem{
+			"": {
+				{Path: paths.New("a"), Meta: objects.Meta{Size: 10, Modified: modified}},
+				{Path: paths.New("dir"), IsPrefix: true},
+				{Path: paths.New("x<y"), Meta: objects.Meta{Size: 1, Modified: modified}},
+			},
+			"dir": {
+				{Path: paths.New("b"), Meta: objects.Meta{Size: 1, Modified: modified}},
+			},
+		},
+	}
+	bs := &fakeBuckets{stores: map[string]*fakeObjects{"logs": logs, "<tmp>": {}}}
+	return &objectServer{bs: bs, listing: listing}, bs
+}
+
+// serve sends a request to the server and returns the response
+func serve(t *testing.T, s http.Handler, method, target string, header http.Header) (*http.Response, string) {
+	req := httptest.NewRequest(method, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	resp := rec.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestServeObject(t *testing.T) {
+	s, _ := newTestObjectServer(true)
+
+	resp, body := serve(t, s, http.MethodGet, "/logs/a", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "0123456789", body)
+	assert.Equal(t, `"abc"`, resp.Header.Get("Etag"))
+	assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "Mon, 01 Oct 2018 12:00:00 GMT", resp.Header.Get("Last-Modified"))
+
+	resp, body = serve(t, s, http.MethodHead, "/logs/dir/b", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Content-Length"))
+	assert.Empty(t, body)
+
+	resp, _ = serve(t, s, http.MethodGet, "/logs/a", http.Header{"If-None-Match": {`"abc"`}})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	assert.Equal(t, `W/"0-a"`, etag(objects.Meta{Modified: time.Unix(0, 0), Size: 10}))
+}
+
+func TestServeRanges(t *testing.T) {
+	s, _ := newTestObjectServer(true)
+
+	for i, tt := range []struct {
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"bytes=2-4", http.StatusPartialContent, "234", "bytes 2-4/10"},
+		{"bytes=7-", http.StatusPartialContent, "789", "bytes 7-9/10"},
+		{"bytes=-2", http.StatusPartialContent, "89", "bytes 8-9/10"},
+		{"bytes=5-100", http.StatusPartialContent, "56789", "bytes 5-9/10"},
+		{"bytes=10-", http.StatusRequestedRangeNotSatisfiable, "", "bytes */10"},
+	} {
+		resp, body := serve(t, s, http.MethodGet, "/logs/a", http.Header{"Range": {tt.rangeHeader}})
+		assert.Equal(t, tt.status, resp.StatusCode, i)
+		assert.Equal(t, tt.contentRange, resp.Header.Get("Content-Range"), i)
+		if tt.body != "" {
+			assert.Equal(t, tt.body, body, i)
+		}
+	}
+
+	// several ranges are sent as a multipart body
+	resp, body := serve(t, s, http.MethodGet, "/logs/a", http.Header{"Range": {"bytes=0-1,8-9"}})
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "multipart/byteranges"))
+	assert.Contains(t, body, "01")
+	assert.Contains(t, body, "89")
+}
+
+func TestServeListing(t *testing.T) {
+	s, _ := newTestObjectServer(true)
+
+	resp, body := serve(t, s, http.MethodGet, "/", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, body, `<a href="logs/">logs/</a>`)
+	// the names are escaped
+	assert.Contains(t, body, `<a href="%3Ctmp%3E/">&lt;tmp&gt;/</a>`)
+
+	resp, body = serve(t, s, http.MethodGet, "/logs/", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "<title>/logs/</title>")
+	assert.Contains(t, body, `<a href="../">../</a>`)
+	assert.Contains(t, body, `<a href="a">a</a> `+formatTime(modified)+`           10`)
+	assert.Contains(t, body, `<a href="dir/">dir/</a>`)
+	assert.Contains(t, body, `<a href="x%3Cy">x&lt;y</a>`)
+
+	resp, body = serve(t, s, http.MethodGet, "/logs/dir/", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "<title>/logs/dir/</title>")
+	assert.Contains(t, body, `<a href="b">b</a>`)
+
+	// a bucket is listed as a directory
+	resp, _ = serve(t, s, http.MethodGet, "/logs", nil)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/logs/", resp.Header.Get("Location"))
+
+	// without listing, only the objects are served
+	s.listing = false
+	for _, target := range []string{"/", "/logs/", "/logs/dir/"} {
+		resp, _ = serve(t, s, http.MethodGet, target, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, target)
+	}
+	resp, _ = serve(t, s, http.MethodGet, "/logs/a", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServeErrors(t *testing.T) {
+	s, bs := newTestObjectServer(true)
+
+	for _, tt := range []struct {
+		method, target string
+		status         int
+	}{
+		{http.MethodGet, "/logs/missing", http.StatusNotFound},
+		{http.MethodGet, "/missing/a", http.StatusNotFound},
+		{http.MethodGet, "/missing/", http.StatusNotFound},
+		{http.MethodPut, "/logs/a", http.StatusMethodNotAllowed},
+		{http.MethodDelete, "/logs/a", http.StatusMethodNotAllowed},
+	} {
+		resp, _ := serve(t, s, tt.method, tt.target, nil)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.method+" "+tt.target)
+		if tt.status == http.StatusMethodNotAllowed {
+			assert.Equal(t, "GET, HEAD", resp.Header.Get("Allow"))
+		}
+	}
+
+	// the other errors aren't disclosed
+	bs.err = errors.New("secret failure")
+	for _, target := range []string{"/", "/logs/", "/logs/a"} {
+		resp, body := serve(t, s, http.MethodGet, target, nil)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode, target)
+		assert.NotContains(t, body, "secret")
+	}
+}
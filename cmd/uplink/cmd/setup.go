@@ -87,6 +87,7 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 		"api-key":         setupCfg.APIKey,
 		"pointer-db-addr": setupCfg.SatelliteAddr,
 		"overlay-addr":    setupCfg.SatelliteAddr,
+		"stat-db-addr":    setupCfg.SatelliteAddr,
 		"access-key":      accessKey,
 		"secret-key":      secretKey,
 	}
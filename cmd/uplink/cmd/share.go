@@ -0,0 +1,132 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/utils"
+	pb "storj.io/storj/protos/macaroon"
+	"storj.io/storj/storage"
+)
+
+var shareCfg struct {
+	expires  time.Duration
+	method   string
+	endpoint string
+}
+
+func init() {
+	cmd := addCmd(&cobra.Command{
+		Use:   "share",
+		Short: "Print a presigned gateway URL and a restricted api key to share an object",
+		RunE:  shareObject,
+	})
+	flags := cmd.Flags()
+	flags.DurationVar(&shareCfg.expires, "expires", 24*time.Hour, "how long the URL and the api key stay valid, at most 168h")
+	flags.StringVar(&shareCfg.method, "method", http.MethodGet, "GET to share downloading the object, PUT to share uploading it")
+	flags.StringVar(&shareCfg.endpoint, "endpoint", "", "URL of the gateway, e.g. https://gateway.example.com. If empty, http:// and the gateway address")
+}
+
+func shareObject(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: uplink share sj://bucket/path")
+	}
+	u, err := utils.ParseURL(args[0])
+	if err != nil {
+		return err
+	}
+	objPath := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || objPath == "" || strings.HasSuffix(objPath, "/") {
+		return fmt.Errorf("No object specified. Please use format sj://bucket/path")
+	}
+
+	endpoint := shareCfg.endpoint
+	if endpoint == "" {
+		host, port, err := net.SplitHostPort(cfg.Address)
+		if err != nil {
+			return err
+		}
+		if host == "" {
+			host = "localhost"
+		}
+		endpoint = "http://" + net.JoinHostPort(host, port)
+	}
+
+	// the URL is checked with the keys of the gateway and served with its
+	// api key, so it stays valid until it expires unless the gateway's
+	// secret key changes. The restricted api key below is the credential
+	// to share when that matters.
+	method := strings.ToUpper(shareCfg.method)
+	if method == http.MethodPut {
+		bs, err := cfg.BucketStore(ctx)
+		if err != nil {
+			return err
+		}
+		if err = checkSharedUpload(ctx, bs, u.Host); err != nil {
+			return err
+		}
+	}
+	presigned, err := cfg.PresignURL(endpoint, method, u.Host, objPath, shareCfg.expires)
+	if err != nil {
+		return err
+	}
+
+	key, err := macaroon.ParseAPIKey(cfg.APIKey)
+	if err != nil {
+		return err
+	}
+	// the api key allows reading, and writing for PUT, the path of the
+	// object only, until the URL expires. The chunks of a deduplicated
+	// object are authorized through the path of the object.
+	caveat := pb.Caveat{
+		DisallowLists:   true,
+		DisallowDeletes: true,
+		DisallowWrites:  method != http.MethodPut,
+		NotAfter:        time.Now().Add(shareCfg.expires).Unix(),
+		AllowedPaths: []*pb.CaveatPath{{
+			Bucket:     []byte(u.Host),
+			PathPrefix: []byte(objPath),
+			Exact:      true,
+		}},
+	}
+	restricted, err := key.Restrict(caveat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("URL: %s\n", presigned)
+	fmt.Printf("API key: %s\n", restricted.Serialize())
+	return nil
+}
+
+// checkSharedUpload returns an error if the uploads to bucket can't be
+// shared with an api key restricted to the path of the object. Putting an
+// object in a versioned bucket moves the previous version to the history
+// of the object, which such a key doesn't allow.
+func checkSharedUpload(ctx context.Context, bs buckets.Store, bucket string) error {
+	m, err := bs.Get(ctx, bucket)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return fmt.Errorf("Bucket not found: %s", bucket)
+		}
+		return err
+	}
+	if m.Versioning {
+		return fmt.Errorf("Uploads to the versioned bucket %s can't be shared, as putting a version moves the previous one out of the path of the object", bucket)
+	}
+	return nil
+}
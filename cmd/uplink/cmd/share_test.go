@@ -0,0 +1,42 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/storage"
+)
+
+// metaBuckets is a buckets.Store of the metadata of buckets, only
+// implementing Get
+type metaBuckets struct {
+	buckets.Store
+	meta map[string]buckets.Meta
+}
+
+func (b *metaBuckets) Get(ctx context.Context, bucket string) (buckets.Meta, error) {
+	m, ok := b.meta[bucket]
+	if !ok {
+		return buckets.Meta{}, storage.ErrKeyNotFound.New(bucket)
+	}
+	return m, nil
+}
+
+func TestCheckSharedUpload(t *testing.T) {
+	bs := &metaBuckets{meta: map[string]buckets.Meta{
+		"plain":     {},
+		"versioned": {Versioning: true},
+	}}
+	ctx := context.Background()
+
+	assert.NoError(t, checkSharedUpload(ctx, bs, "plain"))
+	assert.EqualError(t, checkSharedUpload(ctx, bs, "versioned"),
+		"Uploads to the versioned bucket versioned can't be shared, as putting a version moves the previous one out of the path of the object")
+	assert.EqualError(t, checkSharedUpload(ctx, bs, "missing"), "Bucket not found: missing")
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+func init() {
+	addCmd(&cobra.Command{
+		Use:   "versioning",
+		Short: "Show, enable or disable versioning of a bucket",
+		RunE:  bucketVersioning,
+	})
+}
+
+func bucketVersioning(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	if len(args) == 0 {
+		return fmt.Errorf("No bucket specified. Usage: versioning sj://bucket/ [on|off]")
+	}
+
+	u, err := utils.ParseURL(args[0])
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("No bucket specified. Please use format sj://bucket/")
+	}
+
+	bs, err := cfg.BucketStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := bs.Get(ctx, u.Host)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return fmt.Errorf("Bucket not found: %s", u.Host)
+		}
+		return err
+	}
+
+	if len(args) > 1 {
+		switch args[1] {
+		case "on":
+			m, err = bs.SetVersioning(ctx, u.Host, true)
+		case "off":
+			m, err = bs.SetVersioning(ctx, u.Host, false)
+		default:
+			return fmt.Errorf("Invalid versioning state %q. Please use on or off", args[1])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	state := "off"
+	if m.Versioning {
+		state = "on"
+	}
+	fmt.Printf("Versioning of bucket %s is %s\n", u.Host, state)
+
+	return nil
+}
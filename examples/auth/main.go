@@ -5,43 +5,61 @@ package main
 
 import (
 	"fmt"
-	"net/http"
 	"os"
+	"time"
 
-	"github.com/spf13/pflag"
-	"github.com/spf13/viper"
-
-	"storj.io/storj/pkg/pointerdb/auth"
+	"storj.io/storj/pkg/macaroon"
+	pb "storj.io/storj/protos/macaroon"
 )
 
-// example of how the auth package is working.
-// see readme in auth/ for how to run
+// example of how api keys are created by the satellite, restricted by their
+// holder and checked by the satellite again
 func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-	pflag.String("key", "", "this is your API KEY")
-	viper.BindPFlag("key", pflag.Lookup("key"))
-	pflag.Parse()
-
-	viper.SetEnvPrefix("API")
-	os.Setenv("API_KEY", "12345")
-	viper.AutomaticEnv()
-
-	httpRequestHeaders := InitializeHeaders()
-	xAPIKey := httpRequestHeaders.Get("X-Api-Key")
+func run() error {
+	// the satellite creates a secret and an unrestricted key for the user
+	secret, err := macaroon.NewSecret()
+	if err != nil {
+		return err
+	}
+	root, err := macaroon.NewAPIKey(secret)
+	if err != nil {
+		return err
+	}
 
-	isAuthorized := auth.ValidateAPIKey(xAPIKey)
-	fmt.Println(isAuthorized)
-}
+	// the user restricts the key to reading the photos in a bucket for an
+	// hour, without asking the satellite
+	shared, err := root.Restrict(pb.Caveat{
+		DisallowWrites:  true,
+		DisallowDeletes: true,
+		AllowedPaths: []*pb.CaveatPath{
+			{Bucket: []byte("bucket"), PathPrefix: []byte("photos/")},
+		},
+		NotAfter: time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println("shared key:", shared.Serialize())
 
-// InitializeHeaders mocks HTTP headers to help test X-API-Key
-func InitializeHeaders() *http.Header {
-	httpHeaders := http.Header{
-		"Accept-Encoding": {"gzip, deflate"},
-		"Accept-Language": {"en-US,en;q=0.9"},
-		"X-Api-Key":       {"12345"},
-		"Cache-Control":   {"max-age=0"},
-		"Accept":          {"text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,image/apng,*/*;q=0.8"},
-		"Connection":      {"keep-alive"},
+	// the satellite checks the restricted key against requests
+	key, err := macaroon.ParseAPIKey(shared.Serialize())
+	if err != nil {
+		return err
+	}
+	for _, action := range []macaroon.Action{
+		{Op: macaroon.ActionRead, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg")},
+		{Op: macaroon.ActionWrite, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg")},
+		{Op: macaroon.ActionRead, Bucket: []byte("bucket"), Path: []byte("notes.txt")},
+	} {
+		action.Time = time.Now()
+		fmt.Printf("op %d on %s/%s: %v\n", action.Op, action.Bucket, action.Path,
+			key.Check(secret, action))
 	}
-	return &httpHeaders
+	return nil
 }
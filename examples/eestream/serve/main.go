@@ -73,7 +73,7 @@ func Main() error {
 		}
 		rrs[piecenum] = r
 	}
-	rc, err := eestream.Decode(rrs, es, 4*1024*1024)
+	rc, err := eestream.Decode(rrs, es, 4*1024*1024, nil)
 	if err != nil {
 		return err
 	}
@@ -23,11 +23,13 @@ import (
 
 var (
 	pointerdbClientPort string
+	apiKey              string
 	ctx                 = context.Background()
 )
 
 func initializeFlags() {
 	flag.StringVar(&pointerdbClientPort, "pointerdbPort", ":8080", "this is your port")
+	flag.StringVar(&apiKey, "apiKey", "", "the api key to use")
 	flag.Parse()
 }
 
@@ -47,7 +49,7 @@ func main() {
 		logger.Error("Failed to create full identity: ", zap.Error(err))
 		os.Exit(1)
 	}
-	APIKey := []byte("abc123")
+	APIKey := []byte(apiKey)
 	pdbclient, err := client.NewClient(identity, pointerdbClientPort, APIKey)
 
 	if err != nil {
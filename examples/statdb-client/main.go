@@ -18,12 +18,15 @@ import (
 
 var (
 	port   string
-	APIKey = []byte("abc123")
+	APIKey []byte
 )
 
 func initializeFlags() {
+	var apiKey string
 	flag.StringVar(&port, "port", ":8080", "port")
+	flag.StringVar(&apiKey, "apiKey", "", "the admin api key to use")
 	flag.Parse()
+	APIKey = []byte(apiKey)
 }
 
 func printNodeStats(ns proto.NodeStats, logger zap.Logger) {
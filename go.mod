@@ -6,6 +6,7 @@ require (
 	contrib.go.opencensus.io/exporter/stackdriver v0.6.0 // indirect
 	github.com/Azure/azure-sdk-for-go v19.1.0+incompatible // indirect
 	github.com/Azure/go-autorest v10.15.2+incompatible // indirect
+	github.com/DataDog/zstd v1.4.0
 	github.com/alecthomas/gometalinter v2.0.6+incompatible // indirect
 	github.com/alecthomas/units v0.0.0-20151022065526-2efee857e7cf // indirect
 	github.com/alicebob/gopher-json v0.0.0-20180125190556-5a6b3ba71ee6 // indirect
@@ -70,9 +71,9 @@ require (
 	github.com/klauspost/cpuid v0.0.0-20180405133222-e7e905edc00e // indirect
 	github.com/klauspost/crc32 v0.0.0-20170628072449-bab58d77464a // indirect
 	github.com/klauspost/pgzip v1.0.1 // indirect
-	github.com/klauspost/reedsolomon v0.0.0-20180704173009-925cb01d6510 // indirect
+	github.com/klauspost/reedsolomon v0.0.0-20180704173009-925cb01d6510
 	github.com/kurin/blazer v0.5.1 // indirect
-	github.com/lib/pq v0.0.0-20180523175426-90697d60dd84 // indirect
+	github.com/lib/pq v0.0.0-20180523175426-90697d60dd84
 	github.com/loov/hrtime v0.0.0-20180911122900-a9e82bc6c180
 	github.com/loov/plot v0.0.0-20180510142208-e59891ae1271
 	github.com/magiconair/properties v1.7.6 // indirect
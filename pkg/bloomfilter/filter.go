@@ -0,0 +1,119 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	"github.com/zeebo/errs"
+)
+
+const (
+	version = 1
+
+	// MaxHashCount is the largest number of hash functions a Filter uses
+	MaxHashCount = 32
+)
+
+// Error is the default bloomfilter errs class
+var Error = errs.Class("bloom filter error")
+
+// Filter is a Bloom filter for piece ids. It answers whether an id was
+// added to the set, with no false negatives and a bounded rate of false
+// positives.
+type Filter struct {
+	hashCount uint8
+	table     []byte
+}
+
+// NewOptimal returns a filter sized for the expected number of elements
+// and the requested false positive rate
+func NewOptimal(expectedElements int, falsePositiveRate float64) *Filter {
+	if expectedElements < 1 {
+		expectedElements = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.1
+	}
+
+	bits := -float64(expectedElements) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)
+	hashCount := int(math.Ceil(bits / float64(expectedElements) * math.Ln2))
+	if hashCount < 1 {
+		hashCount = 1
+	}
+	if hashCount > MaxHashCount {
+		hashCount = MaxHashCount
+	}
+
+	size := int(math.Ceil(bits / 8))
+	if size < 1 {
+		size = 1
+	}
+
+	return &Filter{
+		hashCount: uint8(hashCount),
+		table:     make([]byte, size),
+	}
+}
+
+// NewFromBytes parses a filter serialized with Bytes
+func NewFromBytes(data []byte) (*Filter, error) {
+	if len(data) < 3 {
+		return nil, Error.New("not enough data")
+	}
+	if data[0] != version {
+		return nil, Error.New("unsupported version %d", data[0])
+	}
+	hashCount := data[1]
+	if hashCount < 1 || hashCount > MaxHashCount {
+		return nil, Error.New("invalid hash count %d", hashCount)
+	}
+
+	table := make([]byte, len(data)-2)
+	copy(table, data[2:])
+	return &Filter{hashCount: hashCount, table: table}, nil
+}
+
+// Add adds id to the filter
+func (f *Filter) Add(id []byte) {
+	h1, h2 := hashes(id)
+	bits := uint64(len(f.table) * 8)
+	for i := uint64(0); i < uint64(f.hashCount); i++ {
+		bit := (h1 + i*h2) % bits
+		f.table[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Contains returns whether id may have been added to the filter
+func (f *Filter) Contains(id []byte) bool {
+	h1, h2 := hashes(id)
+	bits := uint64(len(f.table) * 8)
+	for i := uint64(0); i < uint64(f.hashCount); i++ {
+		bit := (h1 + i*h2) % bits
+		if f.table[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes serializes the filter
+func (f *Filter) Bytes() []byte {
+	data := make([]byte, 2+len(f.table))
+	data[0] = version
+	data[1] = f.hashCount
+	copy(data[2:], f.table)
+	return data
+}
+
+// hashes returns the two base hashes used for double hashing
+func hashes(id []byte) (h1, h2 uint64) {
+	sum := sha256.Sum256(id)
+	h1 = binary.BigEndian.Uint64(sum[0:8])
+	// h2 must be odd so the probes cover the whole table
+	h2 = binary.BigEndian.Uint64(sum[8:16]) | 1
+	return h1, h2
+}
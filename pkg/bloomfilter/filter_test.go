@@ -0,0 +1,56 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	const n = 10000
+	filter := NewOptimal(n, 0.01)
+
+	for i := 0; i < n; i++ {
+		filter.Add([]byte(fmt.Sprintf("piece-%d", i)))
+	}
+	for i := 0; i < n; i++ {
+		assert.True(t, filter.Contains([]byte(fmt.Sprintf("piece-%d", i))))
+	}
+
+	falsePositives := 0
+	for i := n; i < 2*n; i++ {
+		if filter.Contains([]byte(fmt.Sprintf("piece-%d", i))) {
+			falsePositives++
+		}
+	}
+	assert.True(t, falsePositives < n*3/100, "too many false positives: %d", falsePositives)
+}
+
+func TestSerialization(t *testing.T) {
+	filter := NewOptimal(100, 0.1)
+	filter.Add([]byte("a"))
+	filter.Add([]byte("b"))
+
+	parsed, err := NewFromBytes(filter.Bytes())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, filter, parsed)
+	assert.True(t, parsed.Contains([]byte("a")))
+	assert.True(t, parsed.Contains([]byte("b")))
+
+	for _, data := range [][]byte{
+		nil,
+		{version, 1},
+		{version + 1, 1, 0},
+		{version, 0, 0},
+		{version, MaxHashCount + 1, 0},
+	} {
+		_, err := NewFromBytes(data)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/klauspost/reedsolomon"
+	"github.com/vivint/infectious"
+)
+
+type cauchyScheme struct {
+	enc       reedsolomon.Encoder
+	required  int
+	total     int
+	blockSize int
+	stripes   sync.Pool // buffers reused between the stripes
+}
+
+// cauchyStripe holds the buffers to encode or decode a stripe
+type cauchyStripe struct {
+	shards [][]byte // shards passed to the encoder
+	spares [][]byte // owned buffers for the parity or the missing data
+}
+
+// NewCauchyScheme returns a systematic Reed-Solomon ErasureScheme built on
+// a Cauchy matrix, using the SIMD instructions of the CPU when available.
+// The first required pieces are the data itself, so decoding is a copy
+// when they are all available. The pieces beyond the required ones are used
+// to detect corrupted pieces, and to correct a single one when there are at
+// least two extra pieces.
+func NewCauchyScheme(required, total, blockSize int) (ErasureScheme, error) {
+	enc, err := reedsolomon.New(required, total-required, reedsolomon.WithCauchyMatrix())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	s := &cauchyScheme{
+		enc:       enc,
+		required:  required,
+		total:     total,
+		blockSize: blockSize,
+	}
+	s.stripes.New = func() interface{} {
+		return &cauchyStripe{
+			shards: make([][]byte, total),
+			spares: make([][]byte, total),
+		}
+	}
+	return s, nil
+}
+
+// getStripe takes stripe buffers from the pool
+func (s *cauchyScheme) getStripe() *cauchyStripe {
+	return s.stripes.Get().(*cauchyStripe)
+}
+
+// putStripe gives back the stripe buffers, without keeping the shards of
+// the caller alive in the pool
+func (s *cauchyScheme) putStripe(stripe *cauchyStripe) {
+	for i := range stripe.shards {
+		stripe.shards[i] = nil
+	}
+	s.stripes.Put(stripe)
+}
+
+// spare returns the num-th owned buffer of the stripe, with zero length
+func (s *cauchyScheme) spare(stripe *cauchyStripe, num int) []byte {
+	if cap(stripe.spares[num]) < s.blockSize {
+		stripe.spares[num] = make([]byte, s.blockSize)
+	}
+	return stripe.spares[num][:0]
+}
+
+func (s *cauchyScheme) Encode(input []byte, output func(num int, data []byte)) (
+	err error) {
+	if len(input) != s.DecodedBlockSize() {
+		return Error.New("input size (%d) doesn't match decoded block size (%d)",
+			len(input), s.DecodedBlockSize())
+	}
+	stripe := s.getStripe()
+	defer s.putStripe(stripe)
+	shards := stripe.shards
+	for i := 0; i < s.required; i++ {
+		shards[i] = input[i*s.blockSize : (i+1)*s.blockSize]
+	}
+	for i := s.required; i < s.total; i++ {
+		shards[i] = s.spare(stripe, i)[:s.blockSize]
+	}
+	if err := s.enc.Encode(shards); err != nil {
+		return Error.Wrap(err)
+	}
+	for num, shard := range shards {
+		output(num, shard)
+	}
+	return nil
+}
+
+func (s *cauchyScheme) Decode(out []byte, in map[int][]byte) ([]byte, error) {
+	out, _, err := s.DecodeCorrecting(out, in)
+	return out, err
+}
+
+// DecodeCorrecting is like Decode, but also returns the numbers of the
+// corrupted erasure shares. When there are more shares than required, the
+// stripe is verified against the extra ones. If they don't match, each
+// share is left out in turn to find a single corrupted one, which needs at
+// least two extra shares.
+func (s *cauchyScheme) DecodeCorrecting(out []byte, in map[int][]byte) (
+	_ []byte, corrupted []int, err error) {
+	stripe := s.getStripe()
+	defer s.putStripe(stripe)
+
+	var nums []int
+	for num := range in {
+		if num >= 0 && num < s.total {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+
+	if len(nums) <= s.required {
+		// nothing to verify the stripe against
+		if _, err = s.reconstruct(stripe, in, -1, false); err != nil {
+			return nil, nil, err
+		}
+		return s.output(out, stripe), nil, nil
+	}
+
+	ok, err := s.reconstruct(stripe, in, -1, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok {
+		return s.output(out, stripe), nil, nil
+	}
+	if len(nums) > s.required+1 {
+		for _, skip := range nums {
+			ok, err = s.reconstruct(stripe, in, skip, true)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ok {
+				return s.output(out, stripe), []int{skip}, nil
+			}
+		}
+	}
+	// more shares may tell the corrupted ones apart
+	return nil, nil, infectious.TooManyErrors.New("erasure shares don't match")
+}
+
+// reconstruct fills the shards of the stripe with the shares in in, leaving
+// out the share skip, and reconstructs the missing data shards. If verify
+// is true, the missing parity shards are reconstructed too and ok tells
+// whether all the shards match.
+func (s *cauchyScheme) reconstruct(stripe *cauchyStripe, in map[int][]byte,
+	skip int, verify bool) (ok bool, err error) {
+	shards := stripe.shards
+	for i := range shards {
+		shards[i] = nil
+	}
+	for num, data := range in {
+		if num >= 0 && num < s.total && num != skip {
+			shards[num] = data
+		}
+	}
+	limit := s.required
+	if verify {
+		limit = s.total
+	}
+	for i := 0; i < limit; i++ {
+		if shards[i] == nil {
+			// the missing pieces are reconstructed into the owned buffers,
+			// the available ones are only read
+			shards[i] = s.spare(stripe, i)
+		}
+	}
+
+	if !verify {
+		return true, Error.Wrap(s.enc.ReconstructData(shards))
+	}
+	if err = s.enc.Reconstruct(shards); err != nil {
+		return false, Error.Wrap(err)
+	}
+	ok, err = s.enc.Verify(shards)
+	return ok, Error.Wrap(err)
+}
+
+// output appends the data shards of the stripe to out
+func (s *cauchyScheme) output(out []byte, stripe *cauchyStripe) []byte {
+	for i := 0; i < s.required; i++ {
+		out = append(out, stripe.shards[i]...)
+	}
+	return out
+}
+
+func (s *cauchyScheme) EncodedBlockSize() int {
+	return s.blockSize
+}
+
+func (s *cauchyScheme) DecodedBlockSize() int {
+	return s.blockSize * s.required
+}
+
+func (s *cauchyScheme) TotalCount() int {
+	return s.total
+}
+
+func (s *cauchyScheme) RequiredCount() int {
+	return s.required
+}
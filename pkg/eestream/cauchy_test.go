@@ -0,0 +1,129 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vivint/infectious"
+
+	"storj.io/storj/pkg/ranger"
+)
+
+func TestCauchy(t *testing.T) {
+	ctx := context.Background()
+	data := randData(32 * 1024)
+	es, err := NewCauchyScheme(4, 8, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := NewRedundancyStrategy(es, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces, err := readAll(readers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the scheme is systematic, the first pieces are the data itself
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, data[i*1024:(i+1)*1024], pieces[i][:1024])
+	}
+
+	for i, available := range [][]int{
+		{0, 1, 2, 3},
+		{4, 5, 6, 7},
+		{1, 3, 5, 7},
+		{0, 2, 4, 5, 6},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		rrs := map[int]ranger.Ranger{}
+		for _, num := range available {
+			rrs[num] = ranger.ByteRanger(pieces[num])
+		}
+		rr, err := Decode(rrs, rs, 0, nil)
+		if !assert.NoError(t, err, errTag) {
+			continue
+		}
+		r, err := rr.Range(ctx, 0, rr.Size())
+		if !assert.NoError(t, err, errTag) {
+			continue
+		}
+		data2, err := ioutil.ReadAll(r)
+		assert.NoError(t, err, errTag)
+		assert.NoError(t, r.Close(), errTag)
+		assert.Equal(t, data, data2, errTag)
+	}
+}
+
+func TestCauchyCorrupted(t *testing.T) {
+	es, err := NewCauchyScheme(4, 8, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := randData(es.DecodedBlockSize())
+	shares := map[int][]byte{}
+	err = es.Encode(data, func(num int, share []byte) {
+		shares[num] = append([]byte(nil), share...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted := append([]byte(nil), shares[2]...)
+	corrupted[0]++
+
+	cs := es.(*cauchyScheme)
+	for i, tt := range []struct {
+		available []int
+		fixed     bool
+	}{
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, true},
+		{[]int{1, 2, 3, 5, 6, 7}, true},
+		// one extra share only tells that a share is corrupted
+		{[]int{1, 2, 3, 5, 7}, false},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		in := map[int][]byte{}
+		for _, num := range tt.available {
+			in[num] = shares[num]
+		}
+		in[2] = corrupted
+		out, fixed, err := cs.DecodeCorrecting(nil, in)
+		if !tt.fixed {
+			assert.True(t, infectious.TooManyErrors.Contains(err), errTag)
+			continue
+		}
+		assert.NoError(t, err, errTag)
+		assert.Equal(t, data, out, errTag)
+		assert.Equal(t, []int{2}, fixed, errTag)
+	}
+
+	// healthy shares are verified and nothing is corrected
+	out, fixed, err := cs.DecodeCorrecting(nil, shares)
+	assert.NoError(t, err)
+	assert.Equal(t, data, out)
+	assert.Empty(t, fixed)
+}
+
+func TestCauchyInvalid(t *testing.T) {
+	for i, tt := range []struct {
+		required, total int
+	}{
+		{0, 4},
+		{4, 2},
+		{200, 300},
+	} {
+		_, err := NewCauchyScheme(tt.required, tt.total, 1024)
+		assert.Error(t, err, fmt.Sprintf("Test case #%d", i))
+	}
+}
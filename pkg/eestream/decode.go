@@ -20,7 +20,8 @@ type decodedReader struct {
 	readers         map[int]io.ReadCloser
 	scheme          ErasureScheme
 	stripeReader    *StripeReader
-	outbuf          []byte
+	stripe          []byte // buffer for the stripes decoded for short reads
+	outbuf          []byte // unread part of the last stripe decoded
 	err             error
 	currentStripe   int64
 	expectedStripes int64
@@ -35,8 +36,10 @@ type decodedReader struct {
 // expectedSize is the number of bytes expected to be returned by the Reader.
 // mbm is the maximum memory (in bytes) to be allocated for read buffers. If
 // set to 0, the minimum possible memory will be used.
+// report, if not nil, collects the corrupted, missing and slow pieces as
+// the stripes are read.
 func DecodeReaders(ctx context.Context, rs map[int]io.ReadCloser,
-	es ErasureScheme, expectedSize int64, mbm int) io.ReadCloser {
+	es ErasureScheme, expectedSize int64, mbm int, report *PieceReport) io.ReadCloser {
 	if expectedSize < 0 {
 		return readcloser.FatalReadCloser(Error.New("negative expected size"))
 	}
@@ -52,9 +55,10 @@ func DecodeReaders(ctx context.Context, rs map[int]io.ReadCloser,
 		readers:         rs,
 		scheme:          es,
 		stripeReader:    NewStripeReader(rs, es, mbm),
-		outbuf:          make([]byte, 0, es.DecodedBlockSize()),
+		stripe:          make([]byte, 0, es.DecodedBlockSize()),
 		expectedStripes: expectedSize / int64(es.DecodedBlockSize()),
 	}
+	dr.stripeReader.report = report
 	dr.ctx, dr.cancel = context.WithCancel(ctx)
 	// Kick off a goroutine to watch for context cancelation.
 	go func() {
@@ -76,8 +80,22 @@ func (dr *decodedReader) Read(p []byte) (n int, err error) {
 			dr.err = io.EOF
 			return 0, dr.err
 		}
+		if len(p) >= dr.scheme.DecodedBlockSize() {
+			// p can hold the whole stripe, so decode it there directly
+			out, err := dr.stripeReader.ReadStripe(dr.currentStripe, p[:0])
+			if err != nil {
+				dr.err = err
+				return 0, dr.err
+			}
+			dr.currentStripe++
+			if len(out) > 0 && &out[0] != &p[0] {
+				// the erasure scheme didn't decode in place
+				copy(p, out)
+			}
+			return len(out), nil
+		}
 		// read the input buffers of the next stripe - may also decode it
-		dr.outbuf, dr.err = dr.stripeReader.ReadStripe(dr.currentStripe, dr.outbuf)
+		dr.outbuf, dr.err = dr.stripeReader.ReadStripe(dr.currentStripe, dr.stripe[:0])
 		if dr.err != nil {
 			return 0, dr.err
 		}
@@ -86,10 +104,8 @@ func (dr *decodedReader) Read(p []byte) (n int, err error) {
 
 	// copy what data we have to the output
 	n = copy(p, dr.outbuf)
-	// slide the remaining bytes to the beginning
-	copy(dr.outbuf, dr.outbuf[n:])
-	// shrink the remaining buffer
-	dr.outbuf = dr.outbuf[:len(dr.outbuf)-n]
+	// skip the bytes read, without moving the unread ones
+	dr.outbuf = dr.outbuf[n:]
 	return n, nil
 }
 
@@ -98,13 +114,13 @@ func (dr *decodedReader) Close() error {
 	dr.cancel()
 	// avoid double close of readers
 	dr.close.Do(func() {
-		errs := make([]error, len(dr.readers)+1)
-		// close the readers
-		for i, r := range dr.readers {
-			errs[i] = r.Close()
+		errs := make([]error, 0, len(dr.readers)+1)
+		// close the readers, the piece numbers may not be contiguous
+		for _, r := range dr.readers {
+			errs = append(errs, r.Close())
 		}
 		// close the stripe reader
-		errs[len(dr.readers)] = dr.stripeReader.Close()
+		errs = append(errs, dr.stripeReader.Close())
 		dr.closeErr = utils.CombineErrors(errs...)
 	})
 	return dr.closeErr
@@ -115,6 +131,7 @@ type decodedRanger struct {
 	rrs    map[int]ranger.Ranger
 	inSize int64
 	mbm    int // max buffer memory
+	report *PieceReport
 }
 
 // Decode takes a map of Rangers and an ErasureScheme and returns a combined
@@ -123,7 +140,9 @@ type decodedRanger struct {
 // rrs is a map of erasure piece numbers to erasure piece rangers.
 // mbm is the maximum memory (in bytes) to be allocated for read buffers. If
 // set to 0, the minimum possible memory will be used.
-func Decode(rrs map[int]ranger.Ranger, es ErasureScheme, mbm int) (ranger.Ranger, error) {
+// report, if not nil, collects the corrupted, missing and slow pieces of
+// every range read.
+func Decode(rrs map[int]ranger.Ranger, es ErasureScheme, mbm int, report *PieceReport) (ranger.Ranger, error) {
 	if err := checkMBM(mbm); err != nil {
 		return nil, err
 	}
@@ -154,6 +173,7 @@ func Decode(rrs map[int]ranger.Ranger, es ErasureScheme, mbm int) (ranger.Ranger
 		rrs:    rrs,
 		inSize: size,
 		mbm:    mbm,
+		report: report,
 	}, nil
 }
 
@@ -194,7 +214,7 @@ func (dr *decodedRanger) Range(ctx context.Context, offset, length int64) (io.Re
 		}
 	}
 	// decode from all those ranges
-	r := DecodeReaders(ctx, readers, dr.es, blockCount*int64(dr.es.DecodedBlockSize()), dr.mbm)
+	r := DecodeReaders(ctx, readers, dr.es, blockCount*int64(dr.es.DecodedBlockSize()), dr.mbm, dr.report)
 	// offset might start a few bytes in, potentially discard the initial bytes
 	_, err := io.CopyN(ioutil.Discard, r,
 		offset-firstBlock*int64(dr.es.DecodedBlockSize()))
@@ -102,6 +102,7 @@ type encodedReader struct {
 	r      io.Reader
 	rs     RedundancyStrategy
 	inbuf  []byte
+	pool   *bufferPool
 	eps    map[int](*encodedPiece)
 	mux    sync.Mutex
 	start  time.Time
@@ -109,17 +110,23 @@ type encodedReader struct {
 }
 
 type block struct {
-	i    int    // reader index in the map
-	num  int64  // block number
-	data []byte // block data
-	err  error  // error reading the block
+	i   int     // reader index in the map
+	num int64   // block number
+	buf *[]byte // block data, taken from the buffer pool
+	err error   // error reading the block
 }
 
 // EncodeReader takes a Reader and a RedundancyStrategy and returns a slice of
 // Readers.
 //
 // mbm is the maximum memory (in bytes) to be allocated for read buffers. If
-// set to 0, the minimum possible memory will be used.
+// set to 0, the minimum possible memory will be used. The encoded blocks are
+// recycled through a pool shared by the readers with the same encoded block
+// size, and at most mbm bytes of them, plus two blocks per piece, are held
+// at any time. The pools of the process share a memory budget, so encoding
+// waits for the blocks of the other streams to be read when it is spent.
+// The blocks of a Reader are given back once its context is canceled, so
+// the context should be canceled when the Readers are not used anymore.
 //
 // When the minimum threshold is reached a timer will be started with another
 // 1.5x the amount of time that took so far. The Readers will be aborted as
@@ -133,6 +140,7 @@ func EncodeReader(ctx context.Context, r io.Reader, rs RedundancyStrategy,
 		r:     r,
 		rs:    rs,
 		inbuf: make([]byte, rs.DecodedBlockSize()),
+		pool:  getBufferPool(rs.EncodedBlockSize()),
 		eps:   make(map[int](*encodedPiece), rs.TotalCount()),
 		start: time.Now(),
 	}
@@ -151,6 +159,7 @@ func EncodeReader(ctx context.Context, r io.Reader, rs RedundancyStrategy,
 	}
 	for i := 0; i < rs.TotalCount(); i++ {
 		er.eps[i].ch = make(chan block, chanSize)
+		go er.eps[i].release(er.eps[i].ch)
 	}
 	go er.fillBuffer()
 	return readers, nil
@@ -168,26 +177,39 @@ func (er *encodedReader) fillBuffer() {
 		// reader buffer
 		go er.copyData(i, copiers[i])
 	}
+	var blockNum int64
+	// the output function is made once for all blocks, so that it isn't
+	// allocated for every stripe
+	output := func(num int, data []byte) {
+		buf, err := er.pool.Get(er.ctx)
+		if err != nil {
+			// the readers are canceled, the stripe is dropped and the loop
+			// below stops
+			return
+		}
+		b := block{
+			i:   num,
+			num: blockNum,
+			buf: buf,
+		}
+		// data is reused by the erasure scheme, so add a copy to the channel
+		copy(*b.buf, data)
+		// send the block to the goroutine for adding it to the reader buffer
+		copiers[num] <- b
+	}
 	// read from the input and encode until EOF or error
-	for blockNum := int64(0); ; blockNum++ {
-		_, err := io.ReadFull(er.r, er.inbuf)
+	for ; ; blockNum++ {
+		err := er.ctx.Err()
+		if err == nil {
+			_, err = io.ReadFull(er.r, er.inbuf)
+		}
 		if err != nil {
 			for i := range copiers {
 				copiers[i] <- block{i: i, num: blockNum, err: err}
 			}
 			return
 		}
-		err = er.rs.Encode(er.inbuf, func(num int, data []byte) {
-			b := block{
-				i:    num,
-				num:  blockNum,
-				data: make([]byte, len(data)),
-			}
-			// data is reused by infecious, so add a copy to the channel
-			copy(b.data, data)
-			// send the block to the goroutine for adding it to the reader buffer
-			copiers[num] <- b
-		})
+		err = er.rs.Encode(er.inbuf, output)
 		if err != nil {
 			for i := range copiers {
 				copiers[i] <- block{i: i, num: blockNum, err: err}
@@ -206,28 +228,40 @@ func (er *encodedReader) copyData(num int, copier <-chan block) {
 			close(er.eps[num].ch)
 		}
 	}()
+	// the timer is reused for every block, so it starts stopped
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
 	// process the channel until closed
 	for b := range copier {
-		er.addToReader(b)
+		er.addToReader(b, timer)
 	}
 }
 
-func (er *encodedReader) addToReader(b block) {
+// addToReader adds the block to its reader buffer channel. timer must be
+// stopped and drained, and it is left so.
+func (er *encodedReader) addToReader(b block, timer *time.Timer) {
 	if er.eps[b.i].ch == nil {
 		// this channel is already closed for slowness - skip it
+		er.pool.Put(b.buf)
 		return
 	}
 	for {
-		// initialize timer
-		timer := time.NewTimer(50 * time.Millisecond)
-		defer timer.Stop()
+		timer.Reset(50 * time.Millisecond)
 		// add the encoded data to the respective reader buffer channel
 		select {
 		case er.eps[b.i].ch <- b:
+			if !timer.Stop() {
+				// drain the expiration not received by the select
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
 			return
 		// block for no more than 50 ms
 		case <-timer.C:
 			if er.checkSlowChannel(b.i) {
+				er.pool.Put(b.buf)
 				return
 			}
 		}
@@ -278,14 +312,23 @@ type encodedPiece struct {
 	cancel context.CancelFunc
 	er     *encodedReader
 	ch     chan block
-	outbuf []byte
+	mu     sync.Mutex // guards the current block against release
+	block  *[]byte    // pooled buffer of the current block
+	outbuf []byte     // unread part of the current block
 	err    error
 }
 
 func (ep *encodedPiece) Read(p []byte) (n int, err error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
 	if ep.err != nil {
 		return 0, ep.err
 	}
+	if ep.ctx.Err() != nil {
+		// the blocks of the piece are released, and the stripes encoded
+		// after the cancellation may be missing some of them
+		return 0, io.ErrUnexpectedEOF
+	}
 	if len(ep.outbuf) <= 0 {
 		// take the next block from the channel or block if channel is empty
 		select {
@@ -294,6 +337,10 @@ func (ep *encodedPiece) Read(p []byte) (n int, err error) {
 				// channel was closed due to slowness
 				return 0, io.ErrUnexpectedEOF
 			}
+			if ep.ctx.Err() != nil {
+				ep.er.pool.Put(b.buf)
+				return 0, io.ErrUnexpectedEOF
+			}
 			if b.err != nil {
 				ep.err = b.err
 				if ep.err == io.EOF {
@@ -301,7 +348,8 @@ func (ep *encodedPiece) Read(p []byte) (n int, err error) {
 				}
 				return 0, ep.err
 			}
-			ep.outbuf = b.data
+			ep.block = b.buf
+			ep.outbuf = *b.buf
 		case <-ep.ctx.Done():
 			// context was canceled due to:
 			//  - slowness
@@ -313,13 +361,32 @@ func (ep *encodedPiece) Read(p []byte) (n int, err error) {
 
 	// we have some buffer remaining for this piece. write it to the output
 	n = copy(p, ep.outbuf)
-	// slide the unused (if any) bytes to the beginning of the buffer
-	copy(ep.outbuf, ep.outbuf[n:])
-	// and shrink the buffer
-	ep.outbuf = ep.outbuf[:len(ep.outbuf)-n]
+	// skip the bytes read, without moving the unread ones
+	ep.outbuf = ep.outbuf[n:]
+	if len(ep.outbuf) == 0 {
+		// the block is consumed, so give it back for the next stripes
+		ep.er.pool.Put(ep.block)
+		ep.block = nil
+	}
 	return n, nil
 }
 
+// release gives back the blocks held by the piece, and the ones added to ch
+// until it is closed, once the piece is canceled. Otherwise the pieces
+// abandoned by their readers would keep their part of the budget of the
+// pools.
+func (ep *encodedPiece) release(ch chan block) {
+	<-ep.ctx.Done()
+	ep.mu.Lock()
+	ep.er.pool.Put(ep.block)
+	ep.block = nil
+	ep.outbuf = nil
+	ep.mu.Unlock()
+	for b := range ch {
+		ep.er.pool.Put(b.buf)
+	}
+}
+
 // EncodedRanger will take an existing Ranger and provide a means to get
 // multiple Ranged sub-Readers. EncodedRanger does not match the normal Ranger
 // interface.
@@ -122,16 +122,82 @@ func (b *PieceBuffer) Write(p []byte) (n int, err error) {
 		if err != nil {
 			return n, err
 		}
-		// Notify for new data only if a new complete erasure share is available
-		b.totalwr += int64(nn)
-		if b.totalwr/int64(b.shareSize)-b.lastwr/int64(b.shareSize) > 0 {
-			b.lastwr = b.totalwr
-			b.notifyNewData()
-		}
+		b.written(nn)
 	}
 	return n, nil
 }
 
+// ReadFrom reads data from r into the buffer until EOF or an error. The data
+// is read directly into the free space of the buffer, without going through
+// an intermediate buffer like io.Copy does. If the buffer is full it will
+// block until some data is read from it, or an error is set. The return
+// value n is the number of bytes read. EOF isn't returned as an error.
+func (b *PieceBuffer) ReadFrom(r io.Reader) (n int64, err error) {
+	for {
+		free, err := b.free()
+		if err != nil {
+			return n, err
+		}
+		nn, err := r.Read(free)
+		if nn > 0 {
+			b.commit(nn)
+			b.written(nn)
+			n += int64(nn)
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// written notifies for new data only if a new complete erasure share is
+// available after writing n bytes.
+func (b *PieceBuffer) written(n int) {
+	b.totalwr += int64(n)
+	if b.totalwr/int64(b.shareSize)-b.lastwr/int64(b.shareSize) > 0 {
+		b.lastwr = b.totalwr
+		b.notifyNewData()
+	}
+}
+
+// free returns the contiguous free space after the write pointer, blocking
+// while the buffer is full, or the error set. The space can be written to
+// without holding the lock, as the reads never go past the write pointer,
+// and must then be committed.
+func (b *PieceBuffer) free() ([]byte, error) {
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	for b.full && b.err == nil {
+		b.cond.Wait()
+	}
+	if b.err != nil {
+		// e.g. the buffer was closed, so stop reading
+		return nil, b.err
+	}
+
+	if b.wpos < b.rpos {
+		return b.buf[b.wpos:b.rpos], nil
+	}
+	return b.buf[b.wpos:], nil
+}
+
+// commit advances the write pointer after n bytes were written to the space
+// returned by free.
+func (b *PieceBuffer) commit(n int) {
+	defer b.cond.Broadcast()
+	b.cond.L.Lock()
+	defer b.cond.L.Unlock()
+
+	b.wpos = (b.wpos + n) % len(b.buf)
+	if b.wpos == b.rpos {
+		b.full = true
+	}
+}
+
 // write is a helper method that takes care for the locking on each copy
 // iteration.
 func (b *PieceBuffer) write(p []byte) (n int, err error) {
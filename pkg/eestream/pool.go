@@ -0,0 +1,132 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"context"
+	"flag"
+	"sync"
+)
+
+var (
+	poolMemory = flag.Int("eestream.pool_memory", 256<<20,
+		"maximum memory (in bytes) of the erasure share buffers held at once by all the streams of the process")
+
+	poolsMu sync.Mutex
+	pools   = make(map[int]*bufferPool)
+	memory  *budget // shared by all the pools, made once the flags are parsed
+)
+
+// bufferPool recycles the erasure share buffers of one size between the
+// stripes, and between the streams using the same erasure scheme, so that
+// the hot paths of encoding and decoding don't allocate. The buffers are
+// kept behind pointers to avoid an allocation when putting them back in the
+// underlying sync.Pool.
+//
+// The buffers taken and not put back yet are counted against a budget, so
+// that the streams of the process can't hold more memory than it altogether.
+type bufferPool struct {
+	size   int
+	weight int // bytes of the budget taken by a buffer
+	budget *budget
+	pool   sync.Pool
+}
+
+// getBufferPool returns the shared pool of buffers of the given size
+func getBufferPool(size int) *bufferPool {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	if memory == nil {
+		memory = newBudget(*poolMemory)
+	}
+	p, ok := pools[size]
+	if !ok {
+		p = newBufferPool(size, memory)
+		pools[size] = p
+	}
+	return p
+}
+
+func newBufferPool(size int, b *budget) *bufferPool {
+	p := &bufferPool{size: size, weight: size, budget: b}
+	if p.weight > b.limit {
+		// a buffer larger than the whole budget takes all of it, instead of
+		// blocking forever
+		p.weight = b.limit
+	}
+	p.pool.New = func() interface{} {
+		buf := make([]byte, size)
+		return &buf
+	}
+	return p
+}
+
+// Get returns a buffer of the size of the pool, with undefined content. It
+// blocks while the budget is spent, until buffers are put back or ctx is
+// done.
+func (p *bufferPool) Get(ctx context.Context) (*[]byte, error) {
+	if err := p.budget.acquire(ctx, p.weight); err != nil {
+		return nil, err
+	}
+	return p.pool.Get().(*[]byte), nil
+}
+
+// Put gives back a buffer taken with Get, which must not be used anymore
+func (p *bufferPool) Put(buf *[]byte) {
+	if buf == nil || len(*buf) != p.size {
+		return
+	}
+	p.pool.Put(buf)
+	p.budget.release(p.weight)
+}
+
+// budget is a semaphore counting bytes
+type budget struct {
+	mu    sync.Mutex
+	limit int
+	used  int
+	wait  chan struct{} // closed on release, only made while someone waits
+}
+
+func newBudget(limit int) *budget {
+	if limit < 1 {
+		limit = 1
+	}
+	return &budget{limit: limit}
+}
+
+// acquire takes n bytes of the budget, blocking until they are released or
+// ctx is done
+func (b *budget) acquire(ctx context.Context, n int) error {
+	for {
+		b.mu.Lock()
+		if b.used+n <= b.limit {
+			b.used += n
+			b.mu.Unlock()
+			return nil
+		}
+		if b.wait == nil {
+			b.wait = make(chan struct{})
+		}
+		wait := b.wait
+		b.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release gives back n bytes taken with acquire
+func (b *budget) release(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= n
+	if b.wait != nil {
+		close(b.wait)
+		b.wait = nil
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferPoolBudget(t *testing.T) {
+	p := newBufferPool(1024, newBudget(2048))
+	ctx := context.Background()
+
+	first, err := p.Get(ctx)
+	require.NoError(t, err)
+	_, err = p.Get(ctx)
+	require.NoError(t, err)
+
+	got := make(chan *[]byte)
+	go func() {
+		buf, err := p.Get(ctx)
+		assert.NoError(t, err)
+		got <- buf
+	}()
+	select {
+	case <-got:
+		t.Fatal("got a buffer over the budget")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	p.Put(first)
+	select {
+	case buf := <-got:
+		assert.Len(t, *buf, 1024)
+	case <-time.After(time.Second):
+		t.Fatal("still blocked after a buffer was put back")
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = p.Get(canceled)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestBufferPoolLargerThanBudget(t *testing.T) {
+	p := newBufferPool(4096, newBudget(1024))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	buf, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Len(t, *buf, 4096)
+	p.Put(buf)
+	_, err = p.Get(ctx)
+	assert.NoError(t, err)
+}
+
+func TestEncodeReaderReleasesBuffers(t *testing.T) {
+	es, err := NewCauchyScheme(2, 4, 1024)
+	require.NoError(t, err)
+	rs, err := NewRedundancyStrategy(es, 0, 0)
+	require.NoError(t, err)
+	pool := getBufferPool(rs.EncodedBlockSize())
+	used := func() int {
+		pool.budget.mu.Lock()
+		defer pool.budget.mu.Unlock()
+		return pool.budget.used
+	}
+	before := used()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	readers, err := EncodeReader(ctx, bytes.NewReader(make([]byte, 64*1024)), rs, 8*1024)
+	require.NoError(t, err)
+	// read a part of a block of one piece and abandon the others
+	_, err = readers[0].Read(make([]byte, 16))
+	require.NoError(t, err)
+	cancel()
+
+	for start := time.Now(); used() > before; {
+		if time.Since(start) > time.Second {
+			t.Fatalf("%d bytes of the budget still used", used()-before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/vivint/infectious"
+)
+
+type replicationScheme struct {
+	total     int
+	blockSize int
+}
+
+// NewReplicationScheme returns an ErasureScheme that stores total identical
+// copies of the data. Any piece is enough to decode, and the pieces that
+// disagree with the majority are reported as corrupted.
+func NewReplicationScheme(total, blockSize int) ErasureScheme {
+	return &replicationScheme{total: total, blockSize: blockSize}
+}
+
+func (s *replicationScheme) Encode(input []byte, output func(num int, data []byte)) (
+	err error) {
+	if len(input) != s.blockSize {
+		return Error.New("input size (%d) doesn't match block size (%d)",
+			len(input), s.blockSize)
+	}
+	for num := 0; num < s.total; num++ {
+		output(num, input)
+	}
+	return nil
+}
+
+func (s *replicationScheme) Decode(out []byte, in map[int][]byte) ([]byte, error) {
+	out, _, err := s.DecodeCorrecting(out, in)
+	return out, err
+}
+
+// DecodeCorrecting decodes the copy held by the strict majority of the
+// pieces in, and returns the numbers of the other pieces as corrupted.
+// Without a majority, the error tells the stripe reader to wait for more
+// pieces, like the errors of the Reed-Solomon decoder.
+func (s *replicationScheme) DecodeCorrecting(out []byte, in map[int][]byte) (
+	_ []byte, corrupted []int, err error) {
+	if len(in) == 0 {
+		return nil, nil, infectious.NotEnoughShares.New("no pieces to decode")
+	}
+	var majority []byte
+	votes := 0
+	for _, data := range in {
+		if votes == 0 {
+			majority = data
+		}
+		if bytes.Equal(data, majority) {
+			votes++
+		} else {
+			votes--
+		}
+	}
+	// the vote above only finds the candidate, so count again
+	votes = 0
+	for num, data := range in {
+		if bytes.Equal(data, majority) {
+			votes++
+		} else {
+			corrupted = append(corrupted, num)
+		}
+	}
+	if votes*2 <= len(in) && len(in) > 1 {
+		return nil, nil, infectious.TooManyErrors.New("no majority of the pieces agree")
+	}
+	sort.Ints(corrupted)
+	return append(out, majority...), corrupted, nil
+}
+
+func (s *replicationScheme) EncodedBlockSize() int {
+	return s.blockSize
+}
+
+func (s *replicationScheme) DecodedBlockSize() int {
+	return s.blockSize
+}
+
+func (s *replicationScheme) TotalCount() int {
+	return s.total
+}
+
+func (s *replicationScheme) RequiredCount() int {
+	return 1
+}
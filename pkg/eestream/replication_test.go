@@ -0,0 +1,79 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vivint/infectious"
+
+	"storj.io/storj/internal/pkg/readcloser"
+)
+
+func TestReplicationDecode(t *testing.T) {
+	good, bad := []byte("good"), []byte("bad!")
+	es := NewReplicationScheme(5, 4)
+
+	for i, tt := range []struct {
+		in        map[int][]byte
+		corrupted []int
+		wait      bool
+	}{
+		{map[int][]byte{0: good}, nil, false},
+		{map[int][]byte{0: good, 3: good}, nil, false},
+		{map[int][]byte{0: good, 3: bad}, nil, true},
+		{map[int][]byte{0: bad, 1: good, 3: good}, []int{0}, false},
+		{map[int][]byte{0: bad, 1: good, 2: bad, 3: good}, nil, true},
+		{map[int][]byte{0: bad, 1: good, 2: bad, 3: good, 4: good}, []int{0, 2}, false},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		out, corrupted, err := es.(correctingScheme).DecodeCorrecting(nil, tt.in)
+		if tt.wait {
+			assert.True(t, infectious.TooManyErrors.Contains(err), errTag)
+			continue
+		}
+		if assert.NoError(t, err, errTag) {
+			assert.Equal(t, good, out, errTag)
+			assert.Equal(t, tt.corrupted, corrupted, errTag)
+		}
+	}
+}
+
+func TestReplication(t *testing.T) {
+	ctx := context.Background()
+	data := randData(32 * 1024)
+	rs, err := NewRedundancyStrategy(NewReplicationScheme(3, 1024), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces, err := readAll(readers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, piece := range pieces {
+		assert.Equal(t, data, piece)
+	}
+	readerMap := map[int]io.ReadCloser{
+		0: readcloser.FatalReadCloser(errors.New("I am an error piece")),
+		1: ioutil.NopCloser(bytes.NewReader(pieces[1])),
+		2: ioutil.NopCloser(bytes.NewReader(pieces[2])),
+	}
+	decoder := DecodeReaders(ctx, readerMap, rs, int64(len(data)), 0, nil)
+	defer func() { assert.NoError(t, decoder.Close()) }()
+	data2, err := ioutil.ReadAll(decoder)
+	if assert.NoError(t, err) {
+		assert.Equal(t, data, data2)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"sort"
+	"sync"
+)
+
+// PieceReport collects what was learned about the health of the erasure
+// pieces while decoding them. The zero value is an empty report ready to
+// use. It is safe for concurrent use, so the same report can be passed to
+// several decodes of the same pieces.
+type PieceReport struct {
+	mu        sync.Mutex
+	stripes   int64
+	corrupted map[int]bool
+	missing   map[int]bool
+	late      map[int]int64
+}
+
+// Corrupted returns the numbers of the pieces that returned erasure shares
+// that had to be corrected
+func (r *PieceReport) Corrupted() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedKeys(r.corrupted)
+}
+
+// Missing returns the numbers of the pieces that failed to return their
+// erasure shares, because of an error or because they were too short
+func (r *PieceReport) Missing() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedKeys(r.missing)
+}
+
+// Slow returns the numbers of the pieces that were too late to be used for
+// any of the decoded stripes. The corrupted and missing pieces aren't
+// reported as slow.
+func (r *PieceReport) Slow() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	slow := make(map[int]bool)
+	for num, late := range r.late {
+		if late == r.stripes && !r.corrupted[num] && !r.missing[num] {
+			slow[num] = true
+		}
+	}
+	return sortedKeys(slow)
+}
+
+// Healthy tells whether no piece was corrupted, missing or slow
+func (r *PieceReport) Healthy() bool {
+	return len(r.Corrupted()) == 0 && len(r.Missing()) == 0 && len(r.Slow()) == 0
+}
+
+// addStripe records the outcome of decoding a stripe: the pieces that were
+// corrupted, the ones that failed and the ones not read yet
+func (r *PieceReport) addStripe(total int, corrupted []int, inmap map[int][]byte, errmap map[int]error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	r.stripes++
+	for _, num := range corrupted {
+		r.corrupted[num] = true
+	}
+	for num := range errmap {
+		r.missing[num] = true
+	}
+	for num := 0; num < total; num++ {
+		if inmap[num] == nil && errmap[num] == nil {
+			r.late[num]++
+		}
+	}
+}
+
+// addMissing records the pieces that failed before a stripe could be
+// decoded
+func (r *PieceReport) addMissing(errmap map[int]error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	for num := range errmap {
+		r.missing[num] = true
+	}
+}
+
+// init makes the maps of a zero report
+func (r *PieceReport) init() {
+	if r.corrupted == nil {
+		r.corrupted = make(map[int]bool)
+		r.missing = make(map[int]bool)
+		r.late = make(map[int]int64)
+	}
+}
+
+func sortedKeys(m map[int]bool) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -4,17 +4,27 @@
 package eestream
 
 import (
+	"bytes"
+	"sort"
+	"sync"
+
 	"github.com/vivint/infectious"
 )
 
 type rsScheme struct {
 	fc        *infectious.FEC
 	blockSize int
+	shares    sync.Pool // share slices reused by Decode
 }
 
 // NewRSScheme returns a Reed-Solomon-based ErasureScheme.
 func NewRSScheme(fc *infectious.FEC, blockSize int) ErasureScheme {
-	return &rsScheme{fc: fc, blockSize: blockSize}
+	s := &rsScheme{fc: fc, blockSize: blockSize}
+	s.shares.New = func() interface{} {
+		shares := make([]infectious.Share, 0, fc.Total())
+		return &shares
+	}
+	return s
 }
 
 func (s *rsScheme) Encode(input []byte, output func(num int, data []byte)) (
@@ -25,11 +35,40 @@ func (s *rsScheme) Encode(input []byte, output func(num int, data []byte)) (
 }
 
 func (s *rsScheme) Decode(out []byte, in map[int][]byte) ([]byte, error) {
-	shares := make([]infectious.Share, 0, len(in))
+	shares := s.shares.Get().(*[]infectious.Share)
+	defer s.shares.Put(shares)
+	*shares = (*shares)[:0]
+	for num, data := range in {
+		*shares = append(*shares, infectious.Share{Number: num, Data: data})
+	}
+	out, err := s.fc.Decode(out, *shares)
+	// don't keep the share data alive in the pool
+	for i := range *shares {
+		(*shares)[i].Data = nil
+	}
+	return out, err
+}
+
+// DecodeCorrecting is like Decode, but also returns the numbers of the
+// erasure shares fixed by the error correction. The decoding corrects the
+// shares in place, so they are compared to copies of the originals.
+func (s *rsScheme) DecodeCorrecting(out []byte, in map[int][]byte) (
+	_ []byte, corrupted []int, err error) {
+	originals := make(map[int][]byte, len(in))
+	for num, data := range in {
+		originals[num] = append([]byte(nil), data...)
+	}
+	out, err = s.Decode(out, in)
+	if err != nil {
+		return nil, nil, err
+	}
 	for num, data := range in {
-		shares = append(shares, infectious.Share{Number: num, Data: data})
+		if !bytes.Equal(originals[num], data) {
+			corrupted = append(corrupted, num)
+		}
 	}
-	return s.fc.Decode(out, shares)
+	sort.Ints(corrupted)
+	return out, corrupted, nil
 }
 
 func (s *rsScheme) EncodedBlockSize() int {
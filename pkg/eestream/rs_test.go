@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -42,7 +43,7 @@ func TestRS(t *testing.T) {
 	for i, reader := range readers {
 		readerMap[i] = ioutil.NopCloser(reader)
 	}
-	decoder := DecodeReaders(ctx, readerMap, rs, 32*1024, 0)
+	decoder := DecodeReaders(ctx, readerMap, rs, 32*1024, 0, nil)
 	defer func() { assert.NoError(t, decoder.Close()) }()
 	data2, err := ioutil.ReadAll(decoder)
 	if err != nil {
@@ -51,6 +52,62 @@ func TestRS(t *testing.T) {
 	assert.Equal(t, data, data2)
 }
 
+// chunkReader reads at most n bytes at once from r
+type chunkReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > c.n {
+		p = p[:c.n]
+	}
+	return c.r.Read(p)
+}
+
+// Check that the encoded pieces and the decoded data are the same whatever
+// the size of the reads, below, at or above the block sizes.
+func TestRSReadSizes(t *testing.T) {
+	ctx := context.Background()
+	fc, err := infectious.NewFEC(3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := NewRSScheme(fc, 512)
+	rs, err := NewRedundancyStrategy(es, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := randData(37 * es.DecodedBlockSize())
+	for _, size := range []int{1, 7, 512, 1000, 1536, 5000, 1 << 20} {
+		readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range readers {
+			readers[i] = &chunkReader{r: readers[i], n: size}
+		}
+		pieces, err := readAll(readers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readerMap := make(map[int]io.ReadCloser, len(pieces))
+		for i, piece := range pieces[1:] {
+			// leave the first piece out and hide the WriterTo of
+			// bytes.Reader, like a network stream
+			readerMap[i+1] = ioutil.NopCloser(struct{ io.Reader }{
+				iotest.HalfReader(bytes.NewReader(piece))})
+		}
+		decoder := DecodeReaders(ctx, readerMap, rs, int64(len(data)), 0, nil)
+		data2, err := ioutil.ReadAll(&chunkReader{r: decoder, n: size})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, data, data2, "read size %d", size)
+		assert.NoError(t, decoder.Close())
+	}
+}
+
 // Check that io.ReadFull will return io.ErrUnexpectedEOF
 // if DecodeReaders return less data than expected.
 func TestRSUnexpectedEOF(t *testing.T) {
@@ -73,7 +130,7 @@ func TestRSUnexpectedEOF(t *testing.T) {
 	for i, reader := range readers {
 		readerMap[i] = ioutil.NopCloser(reader)
 	}
-	decoder := DecodeReaders(ctx, readerMap, rs, 32*1024, 0)
+	decoder := DecodeReaders(ctx, readerMap, rs, 32*1024, 0, nil)
 	defer func() { assert.NoError(t, decoder.Close()) }()
 	// Try ReadFull more data from DecodeReaders than available
 	data2 := make([]byte, len(data)+1024)
@@ -117,7 +174,7 @@ func TestRSRanger(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	rc, err := Decode(rrs, rs, 0)
+	rc, err := Decode(rrs, rs, 0, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -414,6 +471,49 @@ func TestRSSlow(t *testing.T) {
 	}
 }
 
+func TestRSReport(t *testing.T) {
+	ctx := context.Background()
+	data := randData(8 * 1024)
+	fc, err := infectious.NewFEC(2, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := NewRSScheme(fc, 1024)
+	rs, err := NewRedundancyStrategy(es, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces, err := readAll(readers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the healthy pieces are a little late, so that the failing and the
+	// corrupted pieces are always read first
+	readerMap := map[int]io.ReadCloser{
+		0: readcloser.FatalReadCloser(errors.New("I am an error piece")),
+		1: ioutil.NopCloser(bytes.NewReader(randData(len(pieces[1])))),
+		2: ioutil.NopCloser(SlowReader(bytes.NewReader(pieces[2]), 1*time.Second)),
+	}
+	for i := 3; i < len(pieces); i++ {
+		readerMap[i] = ioutil.NopCloser(SlowReader(bytes.NewReader(pieces[i]), 10*time.Millisecond))
+	}
+	var report PieceReport
+	decoder := DecodeReaders(ctx, readerMap, rs, int64(len(data)), 0, &report)
+	defer func() { assert.NoError(t, decoder.Close()) }()
+	data2, err := ioutil.ReadAll(decoder)
+	if assert.NoError(t, err) {
+		assert.Equal(t, data, data2)
+	}
+	assert.Equal(t, []int{0}, report.Missing())
+	assert.Equal(t, []int{1}, report.Corrupted())
+	assert.Equal(t, []int{2}, report.Slow())
+	assert.False(t, report.Healthy())
+}
+
 type testCase struct {
 	dataSize    int
 	blockSize   int
@@ -457,7 +557,7 @@ func testRSProblematic(t *testing.T, tt testCase, i int, fn problematicReadClose
 	for i := tt.problematic; i < tt.total; i++ {
 		readerMap[i] = ioutil.NopCloser(bytes.NewReader(pieces[i]))
 	}
-	decoder := DecodeReaders(ctx, readerMap, rs, int64(tt.dataSize), 3*1024)
+	decoder := DecodeReaders(ctx, readerMap, rs, int64(tt.dataSize), 3*1024, nil)
 	defer func() { assert.NoError(t, decoder.Close()) }()
 	data2, err := ioutil.ReadAll(decoder)
 	if tt.fail {
@@ -586,6 +686,7 @@ func BenchmarkReedSolomonErasureScheme(b *testing.B) {
 
 			b.Run("Encode/"+confname+testname, func(b *testing.B) {
 				b.SetBytes(int64(dataSize))
+				b.ReportAllocs()
 				for i := 0; i < b.N; i++ {
 					err := erasureScheme.Encode(data[:dataSize], func(num int, data []byte) {
 						_, _ = num, data
@@ -609,6 +710,7 @@ func BenchmarkReedSolomonErasureScheme(b *testing.B) {
 
 			b.Run("Decode/"+confname+testname, func(b *testing.B) {
 				b.SetBytes(int64(dataSize))
+				b.ReportAllocs()
 				shareMap := make(map[int][]byte, conf.total*2)
 				for i := 0; i < b.N; i++ {
 					rand.Shuffle(len(shares), func(i, k int) {
@@ -637,3 +739,109 @@ func BenchmarkReedSolomonErasureScheme(b *testing.B) {
 		}
 	}
 }
+
+// benchmarkStreamConfs are the erasure schemes of the stream benchmarks.
+// Their decoded block sizes divide the 1 MiB of data streamed, so allocs/op
+// is the number of allocations per MB.
+var benchmarkStreamConfs = []struct{ required, total int }{
+	{2, 4},
+	{16, 40},
+}
+
+// benchmarkSchemes are the erasure schemes of the stream benchmarks
+var benchmarkSchemes = []struct {
+	name string
+	new  func(required, total, blockSize int) (ErasureScheme, error)
+}{
+	{"rs", func(required, total, blockSize int) (ErasureScheme, error) {
+		fc, err := infectious.NewFEC(required, total)
+		if err != nil {
+			return nil, err
+		}
+		return NewRSScheme(fc, blockSize), nil
+	}},
+	{"cauchy", NewCauchyScheme},
+}
+
+func BenchmarkEncodeReader(b *testing.B) {
+	ctx := context.Background()
+	data := randData(1 << 20)
+	for _, scheme := range benchmarkSchemes {
+		for _, conf := range benchmarkStreamConfs {
+			es, err := scheme.new(conf.required, conf.total, 1024)
+			if err != nil {
+				b.Fatal(err)
+			}
+			rs, err := NewRedundancyStrategy(es, 0, 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(fmt.Sprintf("%s/r%dt%d", scheme.name, conf.required, conf.total), func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 4<<20)
+					if err != nil {
+						b.Fatal(err)
+					}
+					errs := make(chan error, len(readers))
+					for _, r := range readers {
+						go func(r io.Reader) {
+							_, err := io.Copy(ioutil.Discard, r)
+							errs <- err
+						}(r)
+					}
+					for range readers {
+						if err := <-errs; err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDecodeReaders(b *testing.B) {
+	ctx := context.Background()
+	data := randData(1 << 20)
+	for _, scheme := range benchmarkSchemes {
+		for _, conf := range benchmarkStreamConfs {
+			es, err := scheme.new(conf.required, conf.total, 1024)
+			if err != nil {
+				b.Fatal(err)
+			}
+			rs, err := NewRedundancyStrategy(es, 0, 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+			readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+			pieces, err := readAll(readers)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(fmt.Sprintf("%s/r%dt%d", scheme.name, conf.required, conf.total), func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					rrs := make(map[int]io.ReadCloser, len(pieces))
+					for num, piece := range pieces {
+						// hide the WriterTo of bytes.Reader, like a network stream
+						rrs[num] = ioutil.NopCloser(struct{ io.Reader }{bytes.NewReader(piece)})
+					}
+					dr := DecodeReaders(ctx, rrs, es, int64(len(data)), 4<<20, nil)
+					_, err := io.Copy(ioutil.Discard, dr)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if err := dr.Close(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
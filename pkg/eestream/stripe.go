@@ -21,6 +21,15 @@ type StripeReader struct {
 	inbufs [][]byte
 	inmap  map[int][]byte
 	errmap map[int]error
+	report *PieceReport
+}
+
+// correctingScheme is an ErasureScheme that can tell which erasure shares it
+// had to correct while decoding
+type correctingScheme interface {
+	// DecodeCorrecting is like Decode, but also returns the numbers of the
+	// corrupted erasure shares
+	DecodeCorrecting(out []byte, in map[int][]byte) (_ []byte, corrupted []int, err error)
 }
 
 // NewStripeReader creates a new StripeReader from the given readers, erasure
@@ -48,6 +57,10 @@ func NewStripeReader(rs map[int]io.ReadCloser, es ErasureScheme, mbm int) *Strip
 
 	// Kick off a goroutine each reader to be copied into a PieceBuffer.
 	for i, buf := range r.bufs {
+		if rs[i] == nil {
+			buf.SetError(Error.New("missing piece reader"))
+			continue
+		}
 		go func(r io.Reader, buf *PieceBuffer) {
 			_, err := io.Copy(buf, r)
 			if err != nil {
@@ -94,20 +107,43 @@ func (r *StripeReader) ReadStripe(num int64, p []byte) ([]byte, error) {
 			r.cond.Wait()
 		}
 		if r.hasEnoughShares() {
-			out, err := r.scheme.Decode(p, r.inmap)
+			out, corrupted, err := r.decode(p)
 			if err != nil {
 				if r.shouldWaitForMore(err) {
 					continue
 				}
 				return nil, err
 			}
+			if r.report != nil {
+				r.report.addStripe(r.scheme.TotalCount(), corrupted, r.inmap, r.errmap)
+			}
 			return out, nil
 		}
 	}
+	if r.report != nil {
+		r.report.addMissing(r.errmap)
+	}
 	// could not read enough shares to attempt a decode
 	return nil, r.combineErrs()
 }
 
+// decode decodes the erasure shares in inmap. The corrupted shares are only
+// looked for if there is a report to add them to, as it costs a copy of the
+// shares.
+func (r *StripeReader) decode(p []byte) (out []byte, corrupted []int, err error) {
+	scheme := r.scheme
+	if rs, ok := scheme.(RedundancyStrategy); ok {
+		// the strategy embeds the scheme, but doesn't expose its
+		// DecodeCorrecting
+		scheme = rs.ErasureScheme
+	}
+	if cs, ok := scheme.(correctingScheme); ok && r.report != nil {
+		return cs.DecodeCorrecting(p, r.inmap)
+	}
+	out, err = r.scheme.Decode(p, r.inmap)
+	return out, nil, err
+}
+
 // readAvailableShares reads the available num-th erasure shares from the piece
 // buffers without blocking. The return value n is the number of erasure shares
 // read.
@@ -0,0 +1,15 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default garbage collection errs class
+	Error = errs.Class("garbage collection error")
+)
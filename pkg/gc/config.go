@@ -0,0 +1,67 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/pool"
+	"storj.io/storj/pkg/provider"
+)
+
+// Config contains everything needed to periodically send retain filters to
+// storage nodes
+type Config struct {
+	Interval          time.Duration `help:"how frequently retain filters are sent to storage nodes" default:"24h"`
+	FalsePositiveRate float64       `help:"the false positive rate of the retain filters" default:"0.1"`
+	GracePeriod       time.Duration `help:"how long before a retain filter is built the pieces it applies to were stored, longer than any upload or graceful exit transfer" default:"24h"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// PointerDB, Overlay and connection pool responsibilities have been started
+// before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointers := pointerdb.LoadFromContext(ctx)
+	if pointers == nil {
+		return Error.New("programmer error: pointerdb responsibility unstarted")
+	}
+	cache := overlay.LoadServerFromContext(ctx)
+	if cache == nil {
+		return Error.New("programmer error: overlay responsibility unstarted")
+	}
+	conns := pool.LoadFromContext(ctx)
+	if conns == nil {
+		return Error.New("programmer error: connection pool responsibility unstarted")
+	}
+
+	service := NewService(zap.L(), pointers, cache, conns, server.Identity(), c)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := service.Collect(ctx); err != nil {
+					zap.S().Error("Error with garbage collection: ", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return server.Run(ctx)
+}
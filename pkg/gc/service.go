@@ -0,0 +1,128 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+	opb "storj.io/storj/protos/overlay"
+)
+
+// Service builds per node retain filters from the pointers and sends them
+// to the storage nodes
+type Service struct {
+	logger    *zap.Logger
+	pointers  *pointerdb.Server
+	overlay   opb.OverlayServer
+	transport transport.Client
+	identity  *provider.FullIdentity
+	config    Config
+	now       func() time.Time
+}
+
+// NewService creates a new garbage collection service
+func NewService(logger *zap.Logger, pointers *pointerdb.Server, overlay opb.OverlayServer,
+	t transport.Client, identity *provider.FullIdentity, config Config) *Service {
+	return &Service{
+		logger:    logger,
+		pointers:  pointers,
+		overlay:   overlay,
+		transport: t,
+		identity:  identity,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Collect sends a retain filter to every storage node holding pieces
+func (s *Service) Collect(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	created, filters, err := s.buildFilters(ctx)
+	if err != nil {
+		return err
+	}
+
+	for nodeID, filter := range filters {
+		if err := s.retain(ctx, nodeID, created, filter); err != nil {
+			s.logger.Error("failed to send retain filter",
+				zap.String("node", nodeID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// buildFilters returns the filter of pieces to keep for every node, and the
+// time before which the pieces missing from them were created and may be
+// deleted. A piece may be stored well before its pointer is put, e.g.
+// during a long upload or a graceful exit transfer waiting to be verified,
+// and its pointer may be put after the scan passed its path. So the
+// filters only apply to the pieces stored a grace period before the scan
+// started.
+func (s *Service) buildFilters(ctx context.Context) (created time.Time, _ map[string]*bloomfilter.Filter, err error) {
+	defer mon.Task()(&ctx)(&err)
+	created = s.now().Add(-s.config.GracePeriod)
+	pieces := map[string][]client.PieceID{}
+	err = s.pointers.IteratePieces(ctx, func(nodeID, pieceID string) error {
+		derived, err := client.PieceID(pieceID).Derive([]byte(nodeID))
+		if err != nil {
+			return err
+		}
+		pieces[nodeID] = append(pieces[nodeID], derived)
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, nil, Error.Wrap(err)
+	}
+
+	filters := make(map[string]*bloomfilter.Filter, len(pieces))
+	for nodeID, ids := range pieces {
+		filter := bloomfilter.NewOptimal(len(ids), s.config.FalsePositiveRate)
+		for _, id := range ids {
+			filter.Add([]byte(id.String()))
+		}
+		filters[nodeID] = filter
+	}
+
+	return created, filters, nil
+}
+
+// retain sends filter to the node with nodeID
+func (s *Service) retain(ctx context.Context, nodeID string, created time.Time, filter *bloomfilter.Filter) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lookup, err := s.overlay.Lookup(ctx, &opb.LookupRequest{NodeID: nodeID})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if lookup.GetNode() == nil {
+		return Error.New("unknown node %s", nodeID)
+	}
+
+	conn, err := s.transport.DialNode(ctx, lookup.GetNode())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	// conn may be shared through the connection pool, so ps isn't closed
+	defer func() { err = utils.CombineErrors(err, transport.Release(s.transport, conn)) }()
+	ps, err := client.NewPSClient(conn, 0, s.identity.Key)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if err := ps.Retain(ctx, created, filter.Bytes()); err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
+}
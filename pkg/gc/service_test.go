@@ -0,0 +1,90 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/pointerdb"
+	ppb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage/teststore"
+)
+
+// testProject is the project the test pointers are stored in
+const testProject = "00112233445566778899aabbccddeeff"
+
+func TestBuildFilters(t *testing.T) {
+	ctx := context.Background()
+	pointers := pointerdb.NewServer(teststore.New(), zap.NewNop(), pointerdb.Config{})
+
+	root := client.NewPieceID()
+	pointer := &ppb.Pointer{
+		Type: ppb.Pointer_REMOTE,
+		Remote: &ppb.RemoteSegment{
+			PieceId: root.String(),
+			RemotePieces: []*ppb.RemotePiece{
+				{PieceNum: 0, NodeId: "node-a"},
+				{PieceNum: 1, NodeId: "node-b"},
+			},
+		},
+	}
+	assert.NoError(t, pointers.CompareAndSwapSegment(ctx, testProject+"/l/bucket/object", nil, pointer))
+	inline := &ppb.Pointer{Type: ppb.Pointer_INLINE, InlineSegment: []byte("data")}
+	assert.NoError(t, pointers.CompareAndSwapSegment(ctx, testProject+"/l/bucket/inline", nil, inline))
+
+	service := NewService(zap.NewNop(), pointers, nil, nil, nil, Config{FalsePositiveRate: 0.01})
+	_, filters, err := service.buildFilters(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, filters, 2)
+
+	for _, node := range []string{"node-a", "node-b"} {
+		derived, err := root.Derive([]byte(node))
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NotNil(t, filters[node]) {
+			assert.True(t, filters[node].Contains([]byte(derived.String())))
+		}
+	}
+}
+
+func TestBuildFiltersGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	pointers := pointerdb.NewServer(teststore.New(), zap.NewNop(), pointerdb.Config{})
+	service := NewService(zap.NewNop(), pointers, nil, nil, nil,
+		Config{FalsePositiveRate: 0.01, GracePeriod: time.Hour})
+
+	// the piece is stored before the scan, but its pointer is only put
+	// once the scan is done, e.g. at the end of a long upload
+	stored := time.Date(2018, time.October, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return stored.Add(10 * time.Minute) }
+	created, filters, err := service.buildFilters(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, filters, 0)
+
+	root := client.NewPieceID()
+	pointer := &ppb.Pointer{
+		Type: ppb.Pointer_REMOTE,
+		Remote: &ppb.RemoteSegment{
+			PieceId:      root.String(),
+			RemotePieces: []*ppb.RemotePiece{{PieceNum: 0, NodeId: "node-a"}},
+		},
+	}
+	assert.NoError(t, pointers.CompareAndSwapSegment(ctx, testProject+"/l/bucket/object", nil, pointer))
+
+	// the node only deletes the pieces missing from the filter created
+	// before the time sent with it, so the piece is kept
+	assert.True(t, created.Before(stored))
+	assert.Equal(t, stored.Add(10*time.Minute-time.Hour), created)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/pool"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/statdb"
+	pb "storj.io/storj/protos/gracefulexit"
+)
+
+// Config is a configuration struct for the satellite side of the graceful
+// exit protocol
+type Config struct {
+	MaxOrders int `help:"the maximum number of transfer orders handed out per request" default:"100"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// PointerDB, Overlay and connection pool responsibilities have been started
+// before this one. If a StatDB responsibility has been started before this
+// one, disqualified nodes can't exit gracefully nor receive pieces.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointers := pointerdb.LoadFromContext(ctx)
+	if pointers == nil {
+		return Error.New("programmer error: pointerdb responsibility unstarted")
+	}
+	cache := overlay.LoadServerFromContext(ctx)
+	if cache == nil {
+		return Error.New("programmer error: overlay responsibility unstarted")
+	}
+	conns := pool.LoadFromContext(ctx)
+	if conns == nil {
+		return Error.New("programmer error: connection pool responsibility unstarted")
+	}
+
+	endpoint := NewEndpoint(zap.L(), pointers, cache, conns, server.Identity(), c)
+	if sdb := statdb.LoadFromContext(ctx); sdb != nil {
+		endpoint.statdb = sdb
+	}
+	pb.RegisterGracefulExitServer(server.GRPC(), endpoint)
+
+	return server.Run(ctx)
+}
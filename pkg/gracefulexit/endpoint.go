@@ -0,0 +1,277 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+	pb "storj.io/storj/protos/gracefulexit"
+	opb "storj.io/storj/protos/overlay"
+	ppb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+)
+
+// Endpoint implements the satellite side of the graceful exit protocol
+type Endpoint struct {
+	logger    *zap.Logger
+	pointers  *pointerdb.Server
+	overlay   opb.OverlayServer
+	transport transport.Client
+	identity  *provider.FullIdentity
+	config    Config
+	// statdb, if not nil, excludes the disqualified nodes from the protocol
+	statdb overlay.StatDB
+}
+
+// NewEndpoint creates a new graceful exit endpoint
+func NewEndpoint(logger *zap.Logger, pointers *pointerdb.Server, overlay opb.OverlayServer,
+	t transport.Client, identity *provider.FullIdentity, config Config) *Endpoint {
+	return &Endpoint{
+		logger:    logger,
+		pointers:  pointers,
+		overlay:   overlay,
+		transport: t,
+		identity:  identity,
+		config:    config,
+	}
+}
+
+// Initiate returns the transfer orders for the pieces stored on the calling
+// storage node. Pieces leave the list once their transfer has been verified,
+// so a node keeps calling Initiate until no orders are returned.
+func (e *Endpoint) Initiate(ctx context.Context, req *pb.InitiateRequest) (resp *pb.InitiateResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pi, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	nodeID := pi.ID.String()
+
+	// the pieces of disqualified nodes are repaired instead
+	if err = e.checkQualified(ctx, nodeID); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, err.Error())
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > e.config.MaxOrders {
+		limit = e.config.MaxOrders
+	}
+
+	e.logger.Info("graceful exit initiated", zap.String("node", nodeID))
+
+	segments, more, err := e.pointers.SegmentsOnNode(ctx, nodeID, "", limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp = &pb.InitiateResponse{More: more}
+	for _, segment := range segments {
+		if pieceOnNode(segment.Pointer, nodeID) == nil {
+			continue
+		}
+		order, err := e.makeOrder(ctx, segment.Key, segment.Pointer, nodeID)
+		if err != nil {
+			e.logger.Error("failed to create transfer order",
+				zap.String("path", segment.Key), zap.Error(err))
+			continue
+		}
+		resp.Orders = append(resp.Orders, order)
+	}
+
+	return resp, nil
+}
+
+// makeOrder picks a replacement node for the piece nodeID holds in pointer
+func (e *Endpoint) makeOrder(ctx context.Context, path string, pointer *ppb.Pointer, nodeID string) (*pb.TransferOrder, error) {
+	remote := pointer.GetRemote()
+	piece := pieceOnNode(pointer, nodeID)
+
+	excluded := map[string]bool{nodeID: true}
+	for _, p := range remote.GetRemotePieces() {
+		excluded[p.GetNodeId()] = true
+	}
+
+	found, err := e.overlay.FindStorageNodes(ctx, &opb.FindStorageNodesRequest{
+		Opts: &opb.OverlayOptions{
+			Amount: int64(len(excluded) + 1),
+			Restrictions: &opb.NodeRestrictions{
+				FreeDisk: pieceSize(pointer),
+			},
+		},
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	for _, node := range found.GetNodes() {
+		if excluded[node.GetId()] {
+			continue
+		}
+		return &pb.TransferOrder{
+			Path:               path,
+			PieceId:            remote.GetPieceId(),
+			PieceNum:           piece.GetPieceNum(),
+			PieceSize:          pieceSize(pointer),
+			ExpirationUnixSec:  pointer.GetExpirationDate().GetSeconds(),
+			ReplacementId:      node.GetId(),
+			ReplacementAddress: node.GetAddress().GetAddress(),
+		}, nil
+	}
+
+	return nil, Error.New("no replacement node available")
+}
+
+// Verify checks that the replacement node holds the transferred piece and
+// points the pointer at the replacement node
+func (e *Endpoint) Verify(ctx context.Context, req *pb.VerifyRequest) (resp *pb.VerifyResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pi, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	nodeID := pi.ID.String()
+
+	if err = e.checkQualified(ctx, nodeID); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, err.Error())
+	}
+	if err = e.checkQualified(ctx, req.GetReplacementId()); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	}
+
+	old, err := e.pointers.GetSegment(ctx, req.GetPath())
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	pointer := proto.Clone(old).(*ppb.Pointer)
+
+	var piece *ppb.RemotePiece
+	for _, p := range pointer.GetRemote().GetRemotePieces() {
+		if p.GetNodeId() == req.GetReplacementId() {
+			return nil, status.Errorf(codes.FailedPrecondition, "replacement already holds a piece of this segment")
+		}
+		if p.GetNodeId() == nodeID && p.GetPieceNum() == req.GetPieceNum() {
+			piece = p
+		}
+	}
+	if piece == nil {
+		return nil, status.Errorf(codes.NotFound, "piece %d is not stored on %s", req.GetPieceNum(), nodeID)
+	}
+
+	if err = e.verifyTransfer(ctx, pointer, req.GetReplacementId()); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	}
+
+	piece.NodeId = req.GetReplacementId()
+
+	// the segment may have been changed while the transfer was verified
+	if err = e.pointers.CompareAndSwapSegment(ctx, req.GetPath(), old, pointer); err != nil {
+		if storage.ErrValueChanged.Has(err) {
+			return nil, status.Errorf(codes.Aborted, "pointer changed during the transfer")
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	e.logger.Debug("piece transferred",
+		zap.String("path", req.GetPath()),
+		zap.String("from", nodeID),
+		zap.String("to", req.GetReplacementId()))
+
+	return &pb.VerifyResponse{}, nil
+}
+
+// checkQualified returns an error if nodeID is disqualified
+func (e *Endpoint) checkQualified(ctx context.Context, nodeID string) error {
+	if e.statdb == nil {
+		return nil
+	}
+	_, failed, err := e.statdb.ValidNodes(ctx, [][]byte{[]byte(nodeID)}, nil, true)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if len(failed) > 0 {
+		return Error.New("node %s is disqualified", nodeID)
+	}
+	return nil
+}
+
+// verifyTransfer asks the replacement node about the transferred piece
+func (e *Endpoint) verifyTransfer(ctx context.Context, pointer *ppb.Pointer, replacementID string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lookup, err := e.overlay.Lookup(ctx, &opb.LookupRequest{NodeID: replacementID})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if lookup.GetNode() == nil {
+		return Error.New("unknown replacement node %s", replacementID)
+	}
+
+	derived, err := client.PieceID(pointer.GetRemote().GetPieceId()).Derive([]byte(replacementID))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	conn, err := e.transport.DialNode(ctx, lookup.GetNode())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	// conn may be shared through the connection pool, so ps isn't closed
+	defer func() { err = utils.CombineErrors(err, transport.Release(e.transport, conn)) }()
+	ps, err := client.NewPSClient(conn, 0, e.identity.Key)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	summary, err := ps.Meta(ctx, derived)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if expected := pieceSize(pointer); summary.GetSize() != expected {
+		return Error.New("piece size mismatch: got %d, expected %d", summary.GetSize(), expected)
+	}
+
+	return nil
+}
+
+// pieceOnNode returns the piece of pointer stored on nodeID or nil
+func pieceOnNode(pointer *ppb.Pointer, nodeID string) *ppb.RemotePiece {
+	if pointer.GetType() != ppb.Pointer_REMOTE {
+		return nil
+	}
+	for _, p := range pointer.GetRemote().GetRemotePieces() {
+		if p.GetNodeId() == nodeID {
+			return p
+		}
+	}
+	return nil
+}
+
+// pieceSize calculates the size of a single erasure share stream of the
+// segment, including the padding added before encoding
+func pieceSize(pointer *ppb.Pointer) int64 {
+	rs := pointer.GetRemote().GetRedundancy()
+	shareSize := int64(rs.GetErasureShareSize())
+	blockSize := shareSize * int64(rs.GetMinReq())
+	if blockSize <= 0 {
+		return 0
+	}
+	blocks := (pointer.GetSize() + 4 + blockSize - 1) / blockSize
+	return blocks * shareSize
+}
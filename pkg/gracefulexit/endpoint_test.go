@@ -0,0 +1,245 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/pointerdb"
+	statpb "storj.io/storj/pkg/statdb/proto"
+	pb "storj.io/storj/protos/gracefulexit"
+	opb "storj.io/storj/protos/overlay"
+	ppb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func remotePointer(size int64, nodes ...string) *ppb.Pointer {
+	var pieces []*ppb.RemotePiece
+	for i, node := range nodes {
+		pieces = append(pieces, &ppb.RemotePiece{PieceNum: int32(i), NodeId: node})
+	}
+	return &ppb.Pointer{
+		Type: ppb.Pointer_REMOTE,
+		Size: size,
+		Remote: &ppb.RemoteSegment{
+			Redundancy: &ppb.RedundancyScheme{
+				MinReq:           2,
+				Total:            4,
+				ErasureShareSize: 1024,
+			},
+			PieceId:      "piece",
+			RemotePieces: pieces,
+		},
+	}
+}
+
+func TestPieceOnNode(t *testing.T) {
+	pointer := remotePointer(100, "a", "b", "c")
+
+	piece := pieceOnNode(pointer, "b")
+	if assert.NotNil(t, piece) {
+		assert.Equal(t, int32(1), piece.GetPieceNum())
+	}
+	assert.Nil(t, pieceOnNode(pointer, "d"))
+	assert.Nil(t, pieceOnNode(&ppb.Pointer{Type: ppb.Pointer_INLINE}, "a"))
+}
+
+func TestPieceSize(t *testing.T) {
+	for i, tt := range []struct {
+		size     int64
+		expected int64
+	}{
+		{0, 1024},
+		{2044, 1024},
+		{2045, 2048},
+		{4092, 2048},
+		{4093, 3072},
+	} {
+		assert.Equal(t, tt.expected, pieceSize(remotePointer(tt.size)), "test %d", i)
+	}
+	assert.Equal(t, int64(0), pieceSize(&ppb.Pointer{}))
+}
+
+// testProject is the project the test pointers are stored in
+const testProject = "00112233445566778899aabbccddeeff"
+
+func newTestEndpoint(t *testing.T, maxOrders int, nodes ...*opb.Node) (*Endpoint, *pointerdb.Server, func()) {
+	pointers := pointerdb.NewServer(teststore.New(), zap.NewNop(), pointerdb.Config{})
+	tt := &testTransport{}
+	endpoint := NewEndpoint(zap.NewNop(), pointers, overlay.NewMockOverlay(nodes), tt,
+		newTestIdentity(t), Config{MaxOrders: maxOrders})
+	return endpoint, pointers, tt.Close
+}
+
+func putPointer(t *testing.T, pointers *pointerdb.Server, key string, pointer *ppb.Pointer) {
+	old, err := pointers.GetSegment(context.Background(), key)
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		assert.NoError(t, err)
+		return
+	}
+	assert.NoError(t, pointers.CompareAndSwapSegment(context.Background(), key, old, pointer))
+}
+
+func getPointer(t *testing.T, pointers *pointerdb.Server, key string) *ppb.Pointer {
+	pointer, err := pointers.GetSegment(context.Background(), key)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return pointer
+}
+
+func TestInitiate(t *testing.T) {
+	exiting := newTestIdentity(t)
+	nodeID := exiting.ID.String()
+	endpoint, pointers, cleanup := newTestEndpoint(t, 3,
+		testNode("b", "127.0.0.1:1"), testNode("c", "127.0.0.1:2"), testNode("r", "127.0.0.1:3"))
+	defer cleanup()
+
+	putPointer(t, pointers, testProject+"/p/a", remotePointer(100, nodeID, "b"))
+	putPointer(t, pointers, testProject+"/p/b", remotePointer(100, "b", nodeID))
+	putPointer(t, pointers, testProject+"/p/c", remotePointer(100, nodeID))
+	putPointer(t, pointers, testProject+"/p/d", remotePointer(100, "b", "c"))
+
+	_, err := endpoint.Initiate(context.Background(), &pb.InitiateRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := peerContext(context.Background(), exiting)
+	resp, err := endpoint.Initiate(ctx, &pb.InitiateRequest{Limit: 2})
+	if assert.NoError(t, err) {
+		assert.Len(t, resp.GetOrders(), 2)
+		assert.True(t, resp.GetMore())
+	}
+
+	// the limit can't exceed MaxOrders
+	resp, err = endpoint.Initiate(ctx, &pb.InitiateRequest{Limit: 10})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, resp.GetMore())
+	if !assert.Len(t, resp.GetOrders(), 3) {
+		return
+	}
+	for i, order := range resp.GetOrders() {
+		pointer := getPointer(t, pointers, order.GetPath())
+		piece := pieceOnNode(pointer, nodeID)
+		if !assert.NotNil(t, piece, "order %d", i) {
+			continue
+		}
+		assert.Equal(t, piece.GetPieceNum(), order.GetPieceNum(), "order %d", i)
+		assert.Equal(t, "piece", order.GetPieceId(), "order %d", i)
+		assert.Equal(t, int64(1024), order.GetPieceSize(), "order %d", i)
+		assert.Nil(t, pieceOnNode(pointer, order.GetReplacementId()), "order %d", i)
+		assert.NotEqual(t, nodeID, order.GetReplacementId(), "order %d", i)
+		assert.NotEmpty(t, order.GetReplacementAddress(), "order %d", i)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	exiting := newTestIdentity(t)
+	nodeID := exiting.ID.String()
+
+	pieces := newTestPieceStore()
+	addr, stop := pieces.start(t)
+	defer stop()
+
+	endpoint, pointers, cleanup := newTestEndpoint(t, 10,
+		testNode("b", addr), testNode("c", addr), testNode("r", addr))
+	defer cleanup()
+
+	derived, err := client.PieceID("piece").Derive([]byte("r"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	pieces.put(derived.String(), make([]byte, 1024))
+
+	putPointer(t, pointers, testProject+"/p/a", remotePointer(100, nodeID, "b"))
+	putPointer(t, pointers, testProject+"/p/b", remotePointer(3000, nodeID, "b"))
+	putPointer(t, pointers, testProject+"/p/c", remotePointer(100, "b", nodeID))
+
+	ctx := peerContext(context.Background(), exiting)
+
+	_, err = endpoint.Verify(context.Background(), &pb.VerifyRequest{Path: testProject + "/p/a", ReplacementId: "r"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	for i, tt := range []struct {
+		req  pb.VerifyRequest
+		code codes.Code
+	}{
+		{pb.VerifyRequest{Path: testProject + "/p/missing", PieceNum: 0, ReplacementId: "r"}, codes.NotFound},
+		{pb.VerifyRequest{Path: testProject + "/p/a", PieceNum: 1, ReplacementId: "r"}, codes.NotFound},
+		{pb.VerifyRequest{Path: testProject + "/p/a", PieceNum: 0, ReplacementId: "b"}, codes.FailedPrecondition},
+		// the replacement doesn't hold the piece
+		{pb.VerifyRequest{Path: testProject + "/p/a", PieceNum: 0, ReplacementId: "c"}, codes.FailedPrecondition},
+		// the replacement is unknown
+		{pb.VerifyRequest{Path: testProject + "/p/a", PieceNum: 0, ReplacementId: "x"}, codes.FailedPrecondition},
+		// the piece stored is too small
+		{pb.VerifyRequest{Path: testProject + "/p/b", PieceNum: 0, ReplacementId: "r"}, codes.FailedPrecondition},
+	} {
+		_, err := endpoint.Verify(ctx, &tt.req)
+		assert.Equal(t, tt.code, status.Code(err), "test %d", i)
+	}
+	assert.Equal(t, nodeID, getPointer(t, pointers, testProject+"/p/a").GetRemote().GetRemotePieces()[0].GetNodeId())
+
+	_, err = endpoint.Verify(ctx, &pb.VerifyRequest{Path: testProject + "/p/a", PieceNum: 0, ReplacementId: "r"})
+	if assert.NoError(t, err) {
+		remote := getPointer(t, pointers, testProject+"/p/a").GetRemote()
+		assert.Equal(t, "r", remote.GetRemotePieces()[0].GetNodeId())
+		assert.Equal(t, "b", remote.GetRemotePieces()[1].GetNodeId())
+	}
+
+	// the pointer is changed while the transfer is verified
+	pieces.setOnPiece(func() {
+		putPointer(t, pointers, testProject+"/p/c", remotePointer(100, "b", nodeID, "c"))
+	})
+	_, err = endpoint.Verify(ctx, &pb.VerifyRequest{Path: testProject + "/p/c", PieceNum: 1, ReplacementId: "r"})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Len(t, getPointer(t, pointers, testProject+"/p/c").GetRemote().GetRemotePieces(), 3)
+}
+
+// disqualifiedStatDB fails the disqualified nodes
+type disqualifiedStatDB map[string]bool
+
+func (sdb disqualifiedStatDB) ValidNodes(ctx context.Context, nodeIDs [][]byte, minStats *statpb.NodeStats, allowUnknown bool) (passed, failed [][]byte, err error) {
+	for _, id := range nodeIDs {
+		if sdb[string(id)] {
+			failed = append(failed, id)
+		} else {
+			passed = append(passed, id)
+		}
+	}
+	return passed, failed, nil
+}
+
+func TestDisqualified(t *testing.T) {
+	exiting := newTestIdentity(t)
+	nodeID := exiting.ID.String()
+	endpoint, pointers, cleanup := newTestEndpoint(t, 10, testNode("r", "127.0.0.1:1"))
+	defer cleanup()
+	sdb := disqualifiedStatDB{"d": true}
+	endpoint.statdb = sdb
+
+	putPointer(t, pointers, testProject+"/p/a", remotePointer(100, nodeID))
+	ctx := peerContext(context.Background(), exiting)
+
+	// the pieces can't be transferred to a disqualified node
+	_, err := endpoint.Verify(ctx, &pb.VerifyRequest{Path: testProject + "/p/a", PieceNum: 0, ReplacementId: "d"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// a disqualified node can't exit gracefully
+	sdb[nodeID] = true
+	_, err = endpoint.Initiate(ctx, &pb.InitiateRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = endpoint.Verify(ctx, &pb.VerifyRequest{Path: testProject + "/p/a", PieceNum: 0, ReplacementId: "r"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, nodeID, getPointer(t, pointers, testProject+"/p/a").GetRemote().GetRemotePieces()[0].GetNodeId())
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"storj.io/storj/pkg/provider"
+	opb "storj.io/storj/protos/overlay"
+	pspb "storj.io/storj/protos/piecestore"
+)
+
+func newTestIdentity(t *testing.T) *provider.FullIdentity {
+	ca, err := provider.NewCA(context.Background(), 12, 4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	identity, err := ca.NewIdentity()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return identity
+}
+
+// peerContext returns a context of a request sent by identity
+func peerContext(ctx context.Context, identity *provider.FullIdentity) context.Context {
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{identity.Leaf, identity.CA},
+		}},
+	})
+}
+
+// testPieceStore is a piece store node keeping the pieces in memory
+type testPieceStore struct {
+	pspb.PieceStoreRoutesServer
+
+	mu     sync.Mutex
+	pieces map[string][]byte
+	// onPiece, if not nil, is called when a piece is looked up
+	onPiece func()
+}
+
+func newTestPieceStore() *testPieceStore {
+	return &testPieceStore{pieces: make(map[string][]byte)}
+}
+
+func (s *testPieceStore) put(id string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pieces[id] = data
+}
+
+func (s *testPieceStore) get(id string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.pieces[id]
+	return data, ok
+}
+
+func (s *testPieceStore) setOnPiece(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onPiece = fn
+}
+
+func (s *testPieceStore) Piece(ctx context.Context, in *pspb.PieceId) (*pspb.PieceSummary, error) {
+	s.mu.Lock()
+	onPiece := s.onPiece
+	s.mu.Unlock()
+	if onPiece != nil {
+		onPiece()
+	}
+	data, ok := s.get(in.GetId())
+	if !ok {
+		return nil, Error.New("piece %s not found", in.GetId())
+	}
+	return &pspb.PieceSummary{Id: in.GetId(), Size: int64(len(data))}, nil
+}
+
+func (s *testPieceStore) Store(stream pspb.PieceStoreRoutes_StoreServer) error {
+	var id string
+	var data bytes.Buffer
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if pieceID := msg.GetPiecedata().GetId(); pieceID != "" {
+			id = pieceID
+		}
+		_, _ = data.Write(msg.GetPiecedata().GetContent())
+	}
+	s.put(id, data.Bytes())
+	return stream.SendAndClose(&pspb.PieceStoreSummary{TotalReceived: int64(data.Len())})
+}
+
+// start serves the piece store on a local address until stop is called
+func (s *testPieceStore) start(t *testing.T) (addr string, stop func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	server := grpc.NewServer()
+	pspb.RegisterPieceStoreRoutesServer(server, s)
+	go func() { _ = server.Serve(lis) }()
+	return lis.Addr().String(), server.Stop
+}
+
+// testTransport dials the nodes without authenticating them
+type testTransport struct {
+	mu    sync.Mutex
+	conns []*grpc.ClientConn
+}
+
+func (tt *testTransport) DialNode(ctx context.Context, node *opb.Node) (*grpc.ClientConn, error) {
+	return tt.DialUnauthenticated(ctx, *node.GetAddress())
+}
+
+func (tt *testTransport) DialUnauthenticated(ctx context.Context, addr opb.NodeAddress) (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(addr.Address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	tt.mu.Lock()
+	tt.conns = append(tt.conns, conn)
+	tt.mu.Unlock()
+	return conn, nil
+}
+
+// Close closes the connections dialed, which the code under test leaves
+// to the connection pool
+func (tt *testTransport) Close() {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	for _, conn := range tt.conns {
+		_ = conn.Close()
+	}
+	tt.conns = nil
+}
+
+func testNode(id, addr string) *opb.Node {
+	return &opb.Node{
+		Id:      id,
+		Address: &opb.NodeAddress{Transport: opb.NodeTransport_TCP, Address: addr},
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	pstore "storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+	pb "storj.io/storj/protos/gracefulexit"
+	opb "storj.io/storj/protos/overlay"
+	pspb "storj.io/storj/protos/piecestore"
+)
+
+// Worker performs the storage node side of the graceful exit protocol
+type Worker struct {
+	logger    *zap.Logger
+	identity  *provider.FullIdentity
+	satellite pb.GracefulExitClient
+	transport transport.Client
+	store     *psserver.Server
+}
+
+// NewWorker creates a new graceful exit worker for the pieces in store
+func NewWorker(logger *zap.Logger, identity *provider.FullIdentity, satellite pb.GracefulExitClient,
+	t transport.Client, store *psserver.Server) *Worker {
+	return &Worker{
+		logger:    logger,
+		identity:  identity,
+		satellite: satellite,
+		transport: t,
+		store:     store,
+	}
+}
+
+// NewSatelliteClient dials the graceful exit endpoint of a satellite
+func NewSatelliteClient(identity *provider.FullIdentity, address string) (pb.GracefulExitClient, error) {
+	dialOpt, err := identity.DialOption()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(address, dialOpt)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return pb.NewGracefulExitClient(conn), nil
+}
+
+// Run requests transfer orders from the satellite and hands the pieces off
+// until no pieces are left or no further progress can be made
+func (w *Worker) Run(ctx context.Context, limit int) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		resp, err := w.satellite.Initiate(ctx, &pb.InitiateRequest{Limit: int32(limit)})
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		transferred := 0
+		for _, order := range resp.GetOrders() {
+			if err := w.transfer(ctx, order); err != nil {
+				w.logger.Error("piece transfer failed",
+					zap.String("path", order.GetPath()),
+					zap.String("replacement", order.GetReplacementId()),
+					zap.Error(err))
+				continue
+			}
+			transferred++
+		}
+
+		if !resp.GetMore() && transferred == len(resp.GetOrders()) {
+			w.logger.Info("graceful exit finished")
+			return nil
+		}
+		if transferred == 0 {
+			return Error.New("no pieces could be transferred")
+		}
+	}
+}
+
+// transfer sends a single piece to its replacement node, has the satellite
+// verify it and deletes the local copy
+func (w *Worker) transfer(ctx context.Context, order *pb.TransferOrder) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	root := client.PieceID(order.GetPieceId())
+	localID, err := root.Derive(w.identity.ID.Bytes())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	remoteID, err := root.Derive([]byte(order.GetReplacementId()))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	data, err := pstore.RetrieveReader(ctx, localID.String(), 0, -1, w.store.DataDir)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = data.Close() }()
+
+	conn, err := w.transport.DialNode(ctx, &opb.Node{
+		Id: order.GetReplacementId(),
+		Address: &opb.NodeAddress{
+			Transport: opb.NodeTransport_TCP,
+			Address:   order.GetReplacementAddress(),
+		},
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	// conn may be shared through the connection pool, so ps isn't closed
+	defer func() { err = utils.CombineErrors(err, transport.Release(w.transport, conn)) }()
+	ps, err := client.NewPSClient(conn, 0, w.identity.Key)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	var expiration time.Time
+	if order.GetExpirationUnixSec() > 0 {
+		expiration = time.Unix(order.GetExpirationUnixSec(), 0)
+	}
+
+	err = ps.Put(ctx, remoteID, data, expiration, &pspb.PayerBandwidthAllocation{})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	_, err = w.satellite.Verify(ctx, &pb.VerifyRequest{
+		Path:          order.GetPath(),
+		PieceNum:      order.GetPieceNum(),
+		ReplacementId: order.GetReplacementId(),
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	_, err = w.store.Delete(ctx, &pspb.PieceDelete{Id: localID.String()})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	pstore "storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+	"storj.io/storj/pkg/piecestore/rpc/server/psdb"
+	"storj.io/storj/pkg/provider"
+	pb "storj.io/storj/protos/gracefulexit"
+)
+
+// testSatellite hands out the scripted transfer orders and records the
+// verified transfers
+type testSatellite struct {
+	mu        sync.Mutex
+	responses []*pb.InitiateResponse
+	verified  []*pb.VerifyRequest
+	verifyErr error
+}
+
+func (s *testSatellite) Initiate(ctx context.Context, in *pb.InitiateRequest, opts ...grpc.CallOption) (*pb.InitiateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.responses) == 0 {
+		return &pb.InitiateResponse{}, nil
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func (s *testSatellite) Verify(ctx context.Context, in *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.verifyErr != nil {
+		return nil, s.verifyErr
+	}
+	s.verified = append(s.verified, in)
+	return &pb.VerifyResponse{}, nil
+}
+
+func newTestStore(t *testing.T) (*psserver.Server, func()) {
+	ctx := context.Background()
+	tmp, err := ioutil.TempDir("", "storj-gracefulexit")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	dataDir := filepath.Join(tmp, "data")
+	db, err := psdb.Open(ctx, dataDir, filepath.Join(tmp, "piecestore.db"))
+	if !assert.NoError(t, err) {
+		_ = os.RemoveAll(tmp)
+		t.FailNow()
+	}
+	store := &psserver.Server{DataDir: dataDir, DB: db}
+	return store, func() {
+		assert.NoError(t, store.Stop(ctx))
+		_ = os.RemoveAll(tmp)
+	}
+}
+
+// storePiece stores the piece of identity derived from pieceID
+func storePiece(t *testing.T, store *psserver.Server, identity *provider.FullIdentity, pieceID string, data []byte) client.PieceID {
+	id, err := client.PieceID(pieceID).Derive(identity.ID.Bytes())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	w, err := pstore.StoreWriter(id.String(), store.DataDir)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = w.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return id
+}
+
+func hasPiece(store *psserver.Server, id client.PieceID) bool {
+	r, err := pstore.RetrieveReader(context.Background(), id.String(), 0, -1, store.DataDir)
+	if err != nil {
+		return false
+	}
+	_ = r.Close()
+	return true
+}
+
+func TestWorkerRun(t *testing.T) {
+	identity := newTestIdentity(t)
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	replacement := newTestPieceStore()
+	addr, stop := replacement.start(t)
+	defer stop()
+
+	data := map[string][]byte{"piece1": []byte("first piece"), "piece2": []byte("second piece")}
+	var local []client.PieceID
+	var orders []*pb.TransferOrder
+	for i, pieceID := range []string{"piece1", "piece2"} {
+		local = append(local, storePiece(t, store, identity, pieceID, data[pieceID]))
+		orders = append(orders, &pb.TransferOrder{
+			Path:               "p/" + pieceID,
+			PieceId:            pieceID,
+			PieceNum:           int32(i),
+			PieceSize:          int64(len(data[pieceID])),
+			ReplacementId:      "r",
+			ReplacementAddress: addr,
+		})
+	}
+
+	satellite := &testSatellite{responses: []*pb.InitiateResponse{
+		{Orders: orders, More: true},
+		{},
+	}}
+	tt := &testTransport{}
+	defer tt.Close()
+
+	worker := NewWorker(zap.NewNop(), identity, satellite, tt, store)
+	if !assert.NoError(t, worker.Run(context.Background(), 2)) {
+		return
+	}
+
+	if assert.Len(t, satellite.verified, 2) {
+		for i, req := range satellite.verified {
+			assert.Equal(t, orders[i].GetPath(), req.GetPath())
+			assert.Equal(t, orders[i].GetPieceNum(), req.GetPieceNum())
+			assert.Equal(t, "r", req.GetReplacementId())
+		}
+	}
+	for i, pieceID := range []string{"piece1", "piece2"} {
+		remote, err := client.PieceID(pieceID).Derive([]byte("r"))
+		if !assert.NoError(t, err) {
+			continue
+		}
+		stored, ok := replacement.get(remote.String())
+		assert.True(t, ok, pieceID)
+		assert.Equal(t, data[pieceID], stored, pieceID)
+		assert.False(t, hasPiece(store, local[i]), pieceID)
+	}
+}
+
+func TestWorkerVerifyFails(t *testing.T) {
+	identity := newTestIdentity(t)
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	replacement := newTestPieceStore()
+	addr, stop := replacement.start(t)
+	defer stop()
+
+	local := storePiece(t, store, identity, "piece", []byte("data"))
+	satellite := &testSatellite{
+		responses: []*pb.InitiateResponse{{Orders: []*pb.TransferOrder{{
+			Path:               "p/piece",
+			PieceId:            "piece",
+			PieceSize:          4,
+			ReplacementId:      "r",
+			ReplacementAddress: addr,
+		}}}},
+		verifyErr: Error.New("pointer changed"),
+	}
+	tt := &testTransport{}
+	defer tt.Close()
+
+	worker := NewWorker(zap.NewNop(), identity, satellite, tt, store)
+	assert.Error(t, worker.Run(context.Background(), 1))
+
+	// the piece is only deleted once the satellite moved it
+	assert.True(t, hasPiece(store, local))
+}
@@ -4,6 +4,7 @@
 package kademlia
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"sync"
@@ -128,7 +129,7 @@ func (rt *RoutingTable) GetBucket(id string) (bucket dht.Bucket, ok bool) {
 // GetBuckets retrieves all buckets from the local node
 func (rt *RoutingTable) GetBuckets() (k []dht.Bucket, err error) {
 	bs := []dht.Bucket{}
-	kbuckets, err := rt.kadBucketDB.List(nil, 0)
+	kbuckets, err := rt.kadBucketDB.List(context.TODO(), nil, 0)
 	if err != nil {
 		return bs, RoutingErr.New("could not get bucket ids %s", err)
 	}
@@ -146,7 +147,7 @@ func (rt *RoutingTable) GetBuckets() (k []dht.Bucket, err error) {
 // otherwise returns all Nodes closest via XOR to the provided nodeID up to the provided limit
 func (rt *RoutingTable) FindNear(id dht.NodeID, limit int) ([]*proto.Node, error) {
 	//if id is in the routing table
-	n, err := rt.nodeBucketDB.Get(id.Bytes())
+	n, err := rt.nodeBucketDB.Get(context.TODO(), id.Bytes())
 	if n != nil {
 		ns, err := unmarshalNodes(storage.Keys{id.Bytes()}, []storage.Value{n})
 		if err != nil {
@@ -158,7 +159,7 @@ func (rt *RoutingTable) FindNear(id dht.NodeID, limit int) ([]*proto.Node, error
 		return []*proto.Node{}, RoutingErr.New("could not get key from rt %s", err)
 	}
 	// if id is not in the routing table
-	nodeIDs, err := rt.nodeBucketDB.List(nil, 0)
+	nodeIDs, err := rt.nodeBucketDB.List(context.TODO(), nil, 0)
 	if err != nil {
 		return []*proto.Node{}, RoutingErr.New("could not get node ids %s", err)
 	}
@@ -180,7 +181,7 @@ func (rt *RoutingTable) FindNear(id dht.NodeID, limit int) ([]*proto.Node, error
 // ConnectionSuccess updates or adds a node to the routing table when
 // a successful connection is made to the node on the network
 func (rt *RoutingTable) ConnectionSuccess(node *proto.Node) error {
-	v, err := rt.nodeBucketDB.Get(storage.Key(node.Id))
+	v, err := rt.nodeBucketDB.Get(context.TODO(), storage.Key(node.Id))
 	if err != nil && !storage.ErrKeyNotFound.Has(err) {
 		return RoutingErr.New("could not get node %s", err)
 	}
@@ -226,7 +227,7 @@ func (rt *RoutingTable) SetBucketTimestamp(id string, now time.Time) error {
 
 // GetBucketTimestamp retrieves the last updated time for a bucket
 func (rt *RoutingTable) GetBucketTimestamp(id string, bucket dht.Bucket) (time.Time, error) {
-	t, err := rt.kadBucketDB.Get([]byte(id))
+	t, err := rt.kadBucketDB.Get(context.TODO(), []byte(id))
 	if err != nil {
 		return time.Now(), RoutingErr.New("could not get bucket timestamp %s", err)
 	}
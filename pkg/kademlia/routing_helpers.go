@@ -5,6 +5,7 @@ package kademlia
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"math/rand"
 	"time"
@@ -114,13 +115,13 @@ func (rt *RoutingTable) updateNode(node *proto.Node) error {
 
 // removeNode will remove churned nodes and replace those entries with nodes from the replacement cache.
 func (rt *RoutingTable) removeNode(kadBucketID storage.Key, nodeID storage.Key) error {
-	_, err := rt.nodeBucketDB.Get(nodeID)
+	_, err := rt.nodeBucketDB.Get(context.TODO(), nodeID)
 	if storage.ErrKeyNotFound.Has(err) {
 		return nil
 	} else if err != nil {
 		return RoutingErr.New("could not get node %s", err)
 	}
-	err = rt.nodeBucketDB.Delete(nodeID)
+	err = rt.nodeBucketDB.Delete(context.TODO(), nodeID)
 	if err != nil {
 		return RoutingErr.New("could not delete node %s", err)
 	}
@@ -153,7 +154,7 @@ func marshalNode(node proto.Node) ([]byte, error) {
 
 // putNode: helper, adds or updates proto Node and ID to nodeBucketDB
 func (rt *RoutingTable) putNode(nodeKey storage.Key, nodeValue storage.Value) error {
-	err := rt.nodeBucketDB.Put(nodeKey, nodeValue)
+	err := rt.nodeBucketDB.Put(context.TODO(), nodeKey, nodeValue)
 	if err != nil {
 		return RoutingErr.New("could not add key value pair to nodeBucketDB: %s", err)
 	}
@@ -164,7 +165,7 @@ func (rt *RoutingTable) putNode(nodeKey storage.Key, nodeValue storage.Value) er
 func (rt *RoutingTable) createOrUpdateKBucket(bucketID storage.Key, now time.Time) error {
 	dateTime := make([]byte, binary.MaxVarintLen64)
 	binary.PutVarint(dateTime, now.UnixNano())
-	err := rt.kadBucketDB.Put(bucketID, dateTime)
+	err := rt.kadBucketDB.Put(context.TODO(), bucketID, dateTime)
 	if err != nil {
 		return RoutingErr.New("could not add or update k bucket: %s", err)
 	}
@@ -174,7 +175,7 @@ func (rt *RoutingTable) createOrUpdateKBucket(bucketID storage.Key, now time.Tim
 // getKBucketID: helper, returns the id of the corresponding k bucket given a node id.
 // The node doesn't have to be in the routing table at time of search
 func (rt *RoutingTable) getKBucketID(nodeID storage.Key) (storage.Key, error) {
-	kadBucketIDs, err := rt.kadBucketDB.List(nil, 0)
+	kadBucketIDs, err := rt.kadBucketDB.List(context.TODO(), nil, 0)
 	if err != nil {
 		return nil, RoutingErr.New("could not list all k bucket ids: %s", err)
 	}
@@ -235,7 +236,7 @@ func xorTwoIds(id []byte, comparisonID []byte) []byte {
 
 // nodeIsWithinNearestK: helper, returns true if the node in question is within the nearest k from local node
 func (rt *RoutingTable) nodeIsWithinNearestK(nodeID storage.Key) (bool, error) {
-	nodes, err := rt.nodeBucketDB.List(nil, 0)
+	nodes, err := rt.nodeBucketDB.List(context.TODO(), nil, 0)
 	if err != nil {
 		return false, RoutingErr.New("could not get nodes: %s", err)
 	}
@@ -287,7 +288,7 @@ func (rt *RoutingTable) getNodeIDsWithinKBucket(bucketID storage.Key) (storage.K
 	left := endpoints[0]
 	right := endpoints[1]
 	var nodeIDs storage.Keys
-	allNodeIDs, err := rt.nodeBucketDB.List(nil, 0)
+	allNodeIDs, err := rt.nodeBucketDB.List(context.TODO(), nil, 0)
 	if err != nil {
 		return nil, RoutingErr.New("could not list nodes %s", err)
 	}
@@ -309,7 +310,7 @@ func (rt *RoutingTable) getNodeIDsWithinKBucket(bucketID storage.Key) (storage.K
 func (rt *RoutingTable) getNodesFromIDs(nodeIDs storage.Keys) (storage.Keys, []storage.Value, error) {
 	var nodes []storage.Value
 	for _, v := range nodeIDs {
-		n, err := rt.nodeBucketDB.Get(v)
+		n, err := rt.nodeBucketDB.Get(context.TODO(), v)
 		if err != nil {
 			return nodeIDs, nodes, RoutingErr.New("could not get node id %v, %s", v, err)
 		}
@@ -356,7 +357,7 @@ func (rt *RoutingTable) getUnmarshaledNodesFromBucket(bucketID storage.Key) ([]*
 // getKBucketRange: helper, returns the left and right endpoints of the range of node ids contained within the bucket
 func (rt *RoutingTable) getKBucketRange(bucketID storage.Key) (storage.Keys, error) {
 	key := bucketID
-	kadIDs, err := rt.kadBucketDB.ReverseList(key, 2)
+	kadIDs, err := rt.kadBucketDB.ReverseList(context.TODO(), key, 2)
 	if err != nil {
 		return nil, RoutingErr.New("could not reverse list k bucket ids %s", err)
 	}
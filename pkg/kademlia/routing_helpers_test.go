@@ -4,6 +4,7 @@
 package kademlia
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -16,6 +17,8 @@ import (
 	"storj.io/storj/storage"
 )
 
+var ctx = context.Background()
+
 func tempdir(t testing.TB) (dir string, cleanup func()) {
 	dir, err := ioutil.TempDir("", "storj-kademlia")
 	if err != nil {
@@ -64,7 +67,7 @@ func mockNode(id string) *proto.Node {
 func TestAddNode(t *testing.T) {
 	rt, cleanup := createRoutingTable(t, []byte("OO")) //localNode [79, 79] or [01001111, 01001111]
 	defer cleanup()
-	bucket, err := rt.kadBucketDB.Get(storage.Key([]byte{255, 255}))
+	bucket, err := rt.kadBucketDB.Get(ctx, storage.Key([]byte{255, 255}))
 	assert.NoError(t, err)
 	assert.NotNil(t, bucket)
 	var ok bool
@@ -73,9 +76,9 @@ func TestAddNode(t *testing.T) {
 	ok, err = rt.addNode(node1)
 	assert.True(t, ok)
 	assert.NoError(t, err)
-	kadKeys, err := rt.kadBucketDB.List(nil, 0)
+	kadKeys, err := rt.kadBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
-	nodeKeys, err := rt.nodeBucketDB.List(nil, 0)
+	nodeKeys, err := rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(kadKeys))
 	assert.Equal(t, 2, len(nodeKeys))
@@ -101,9 +104,9 @@ func TestAddNode(t *testing.T) {
 	assert.True(t, ok)
 	assert.NoError(t, err)
 
-	kadKeys, err = rt.kadBucketDB.List(nil, 0)
+	kadKeys, err = rt.kadBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
-	nodeKeys, err = rt.nodeBucketDB.List(nil, 0)
+	nodeKeys, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(kadKeys))
 	assert.Equal(t, 6, len(nodeKeys))
@@ -114,9 +117,9 @@ func TestAddNode(t *testing.T) {
 	assert.True(t, ok)
 	assert.NoError(t, err)
 
-	kadKeys, err = rt.kadBucketDB.List(nil, 0)
+	kadKeys, err = rt.kadBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
-	nodeKeys, err = rt.nodeBucketDB.List(nil, 0)
+	nodeKeys, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 5, len(kadKeys))
 	assert.Equal(t, 7, len(nodeKeys))
@@ -169,9 +172,9 @@ func TestAddNode(t *testing.T) {
 	assert.True(t, ok)
 	assert.NoError(t, err)
 
-	kadKeys, err = rt.kadBucketDB.List(nil, 0)
+	kadKeys, err = rt.kadBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
-	nodeKeys, err = rt.nodeBucketDB.List(nil, 0)
+	nodeKeys, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 5, len(kadKeys))
 	assert.Equal(t, 13, len(nodeKeys))
@@ -189,9 +192,9 @@ func TestAddNode(t *testing.T) {
 	ns := rt.replacementCache[string([]byte{63, 255})]
 	assert.Equal(t, node13.Id, ns[0].Id)
 
-	kadKeys, err = rt.kadBucketDB.List(nil, 0)
+	kadKeys, err = rt.kadBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
-	nodeKeys, err = rt.nodeBucketDB.List(nil, 0)
+	nodeKeys, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 5, len(kadKeys))
 	assert.Equal(t, 13, len(nodeKeys))
@@ -230,9 +233,9 @@ func TestAddNode(t *testing.T) {
 	c, err = rt.getNodeIDsWithinKBucket(kadKeys[2])
 	assert.NoError(t, err)
 	assert.Equal(t, 6, len(c))
-	kadKeys, err = rt.kadBucketDB.List(nil, 0)
+	kadKeys, err = rt.kadBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
-	nodeKeys, err = rt.nodeBucketDB.List(nil, 0)
+	nodeKeys, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 5, len(kadKeys))
 	assert.Equal(t, 18, len(nodeKeys))
@@ -242,9 +245,9 @@ func TestAddNode(t *testing.T) {
 	ok, err = rt.addNode(node19)
 	assert.True(t, ok)
 	assert.NoError(t, err)
-	kadKeys, err = rt.kadBucketDB.List(nil, 0)
+	kadKeys, err = rt.kadBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
-	nodeKeys, err = rt.nodeBucketDB.List(nil, 0)
+	nodeKeys, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 6, len(kadKeys))
@@ -258,7 +261,7 @@ func TestUpdateNode(t *testing.T) {
 	ok, err := rt.addNode(node)
 	assert.True(t, ok)
 	assert.NoError(t, err)
-	val, err := rt.nodeBucketDB.Get(storage.Key(node.Id))
+	val, err := rt.nodeBucketDB.Get(ctx, storage.Key(node.Id))
 	assert.NoError(t, err)
 	unmarshaled, err := unmarshalNodes(storage.Keys{storage.Key(node.Id)}, []storage.Value{val})
 	assert.NoError(t, err)
@@ -268,7 +271,7 @@ func TestUpdateNode(t *testing.T) {
 	node.Address = &proto.NodeAddress{Address: "BB"}
 	err = rt.updateNode(node)
 	assert.NoError(t, err)
-	val, err = rt.nodeBucketDB.Get(storage.Key(node.Id))
+	val, err = rt.nodeBucketDB.Get(ctx, storage.Key(node.Id))
 	assert.NoError(t, err)
 	unmarshaled, err = unmarshalNodes(storage.Keys{storage.Key(node.Id)}, []storage.Value{val})
 	assert.NoError(t, err)
@@ -284,17 +287,17 @@ func TestRemoveNode(t *testing.T) {
 	ok, err := rt.addNode(node)
 	assert.True(t, ok)
 	assert.NoError(t, err)
-	val, err := rt.nodeBucketDB.Get(storage.Key(node.Id))
+	val, err := rt.nodeBucketDB.Get(ctx, storage.Key(node.Id))
 	assert.NoError(t, err)
 	assert.NotNil(t, val)
 	node2 := mockNode("CC")
 	rt.addToReplacementCache(kadBucketID, node2)
 	err = rt.removeNode(kadBucketID, storage.Key(node.Id))
 	assert.NoError(t, err)
-	val, err = rt.nodeBucketDB.Get(storage.Key(node.Id))
+	val, err = rt.nodeBucketDB.Get(ctx, storage.Key(node.Id))
 	assert.Nil(t, val)
 	assert.Error(t, err)
-	val2, err := rt.nodeBucketDB.Get(storage.Key(node2.Id))
+	val2, err := rt.nodeBucketDB.Get(ctx, storage.Key(node2.Id))
 	assert.NoError(t, err)
 	assert.NotNil(t, val2)
 	assert.Equal(t, 0, len(rt.replacementCache[string(kadBucketID)]))
@@ -310,7 +313,7 @@ func TestCreateOrUpdateKBucket(t *testing.T) {
 	defer cleanup()
 	err := rt.createOrUpdateKBucket(storage.Key(id), time.Now())
 	assert.NoError(t, err)
-	val, e := rt.kadBucketDB.Get(storage.Key(id))
+	val, e := rt.kadBucketDB.Get(ctx, storage.Key(id))
 	assert.NotNil(t, val)
 	assert.NoError(t, e)
 
@@ -336,21 +339,21 @@ func TestSortByXOR(t *testing.T) {
 	rt, cleanup := createRoutingTable(t, node1)
 	defer cleanup()
 	node2 := []byte{143, 255} //xor 240
-	assert.NoError(t, rt.nodeBucketDB.Put(node2, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node2, []byte("")))
 	node3 := []byte{255, 255} //xor 128
-	assert.NoError(t, rt.nodeBucketDB.Put(node3, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node3, []byte("")))
 	node4 := []byte{191, 255} //xor 192
-	assert.NoError(t, rt.nodeBucketDB.Put(node4, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node4, []byte("")))
 	node5 := []byte{133, 255} //xor 250
-	assert.NoError(t, rt.nodeBucketDB.Put(node5, []byte("")))
-	nodes, err := rt.nodeBucketDB.List(nil, 0)
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node5, []byte("")))
+	nodes, err := rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	expectedNodes := storage.Keys{node1, node5, node2, node4, node3}
 	assert.Equal(t, expectedNodes, nodes)
 	sortedNodes := sortByXOR(nodes, node1)
 	expectedSorted := storage.Keys{node1, node3, node4, node2, node5}
 	assert.Equal(t, expectedSorted, sortedNodes)
-	nodes, err = rt.nodeBucketDB.List(nil, 0)
+	nodes, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedNodes, nodes)
 }
@@ -360,45 +363,45 @@ func TestDetermineFurthestIDWithinK(t *testing.T) {
 	rt, cleanup := createRoutingTable(t, node1)
 	defer cleanup()
 	rt.self.Id = string(node1)
-	assert.NoError(t, rt.nodeBucketDB.Put(node1, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node1, []byte("")))
 	expectedFurthest := node1
-	nodes, err := rt.nodeBucketDB.List(nil, 0)
+	nodes, err := rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	furthest, err := rt.determineFurthestIDWithinK(nodes)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFurthest, furthest)
 
 	node2 := []byte{143, 255} //xor 240
-	assert.NoError(t, rt.nodeBucketDB.Put(node2, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node2, []byte("")))
 	expectedFurthest = node2
-	nodes, err = rt.nodeBucketDB.List(nil, 0)
+	nodes, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	furthest, err = rt.determineFurthestIDWithinK(nodes)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFurthest, furthest)
 
 	node3 := []byte{255, 255} //xor 128
-	assert.NoError(t, rt.nodeBucketDB.Put(node3, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node3, []byte("")))
 	expectedFurthest = node2
-	nodes, err = rt.nodeBucketDB.List(nil, 0)
+	nodes, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	furthest, err = rt.determineFurthestIDWithinK(nodes)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFurthest, furthest)
 
 	node4 := []byte{191, 255} //xor 192
-	assert.NoError(t, rt.nodeBucketDB.Put(node4, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node4, []byte("")))
 	expectedFurthest = node2
-	nodes, err = rt.nodeBucketDB.List(nil, 0)
+	nodes, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	furthest, err = rt.determineFurthestIDWithinK(nodes)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFurthest, furthest)
 
 	node5 := []byte{133, 255} //xor 250
-	assert.NoError(t, rt.nodeBucketDB.Put(node5, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node5, []byte("")))
 	expectedFurthest = node5
-	nodes, err = rt.nodeBucketDB.List(nil, 0)
+	nodes, err = rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	furthest, err = rt.determineFurthestIDWithinK(nodes)
 	assert.NoError(t, err)
@@ -418,19 +421,19 @@ func TestNodeIsWithinNearestK(t *testing.T) {
 	expectTrue, err = rt.nodeIsWithinNearestK(furthestNode)
 	assert.NoError(t, err)
 	assert.True(t, expectTrue)
-	assert.NoError(t, rt.nodeBucketDB.Put(furthestNode, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, furthestNode, []byte("")))
 
 	node1 := []byte{255, 255}
 	expectTrue, err = rt.nodeIsWithinNearestK(node1)
 	assert.NoError(t, err)
 	assert.True(t, expectTrue)
-	assert.NoError(t, rt.nodeBucketDB.Put(node1, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node1, []byte("")))
 
 	node2 := []byte{191, 255}
 	expectTrue, err = rt.nodeIsWithinNearestK(node2)
 	assert.NoError(t, err)
 	assert.True(t, expectTrue)
-	assert.NoError(t, rt.nodeBucketDB.Put(node1, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node1, []byte("")))
 
 	node3 := []byte{133, 255}
 	expectFalse, err := rt.nodeIsWithinNearestK(node3)
@@ -468,11 +471,11 @@ func TestKadBucketHasRoom(t *testing.T) {
 	resultA, err := rt.kadBucketHasRoom(kadIDA)
 	assert.NoError(t, err)
 	assert.True(t, resultA)
-	assert.NoError(t, rt.nodeBucketDB.Put(node2, []byte("")))
-	assert.NoError(t, rt.nodeBucketDB.Put(node3, []byte("")))
-	assert.NoError(t, rt.nodeBucketDB.Put(node4, []byte("")))
-	assert.NoError(t, rt.nodeBucketDB.Put(node5, []byte("")))
-	assert.NoError(t, rt.nodeBucketDB.Put(node6, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node2, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node3, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node4, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node5, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, node6, []byte("")))
 	resultB, err := rt.kadBucketHasRoom(kadIDA)
 	assert.NoError(t, err)
 	assert.False(t, resultB)
@@ -490,8 +493,8 @@ func TestGetNodeIDsWithinKBucket(t *testing.T) {
 	nodeIDB := []byte{111, 255} //[01101111, 1111111]
 	nodeIDC := []byte{47, 255}  //[00101111, 1111111]
 
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDB, []byte("")))
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDC, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDB, []byte("")))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDC, []byte("")))
 
 	expectedA := storage.Keys{nodeIDA}
 	expectedB := storage.Keys{nodeIDC, nodeIDB}
@@ -521,12 +524,12 @@ func TestGetNodesFromIDs(t *testing.T) {
 	rt, cleanup := createRoutingTable(t, nodeIDA)
 	defer cleanup()
 
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDA, a))
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDB, b))
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDC, c))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDA, a))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDB, b))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDC, c))
 	expected := []storage.Value{a, b, c}
 
-	nodeIDs, err := rt.nodeBucketDB.List(nil, 0)
+	nodeIDs, err := rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	_, values, err := rt.getNodesFromIDs(nodeIDs)
 	assert.NoError(t, err)
@@ -549,10 +552,10 @@ func TestUnmarshalNodes(t *testing.T) {
 	assert.NoError(t, err)
 	rt, cleanup := createRoutingTable(t, nodeIDA)
 	defer cleanup()
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDA, a))
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDB, b))
-	assert.NoError(t, rt.nodeBucketDB.Put(nodeIDC, c))
-	nodeIDs, err := rt.nodeBucketDB.List(nil, 0)
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDA, a))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDB, b))
+	assert.NoError(t, rt.nodeBucketDB.Put(ctx, nodeIDC, c))
+	nodeIDs, err := rt.nodeBucketDB.List(ctx, nil, 0)
 	assert.NoError(t, err)
 	ids, values, err := rt.getNodesFromIDs(nodeIDs)
 	assert.NoError(t, err)
@@ -590,9 +593,9 @@ func TestGetKBucketRange(t *testing.T) {
 	idA := []byte{255, 255}
 	idB := []byte{127, 255}
 	idC := []byte{63, 255}
-	assert.NoError(t, rt.kadBucketDB.Put(idA, []byte("")))
-	assert.NoError(t, rt.kadBucketDB.Put(idB, []byte("")))
-	assert.NoError(t, rt.kadBucketDB.Put(idC, []byte("")))
+	assert.NoError(t, rt.kadBucketDB.Put(ctx, idA, []byte("")))
+	assert.NoError(t, rt.kadBucketDB.Put(ctx, idB, []byte("")))
+	assert.NoError(t, rt.kadBucketDB.Put(ctx, idC, []byte("")))
 	expectedA := storage.Keys{idB, idA}
 	expectedB := storage.Keys{idC, idB}
 	expectedC := storage.Keys{rt.createZeroAsStorageKey(), idC}
@@ -631,21 +634,21 @@ func TestDetermineLeafDepth(t *testing.T) {
 	idB := []byte{127, 255}
 	idC := []byte{63, 255}
 
-	err := rt.kadBucketDB.Put(idA, []byte(""))
+	err := rt.kadBucketDB.Put(ctx, idA, []byte(""))
 	assert.NoError(t, err)
 
 	first, err := rt.determineLeafDepth(idA)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, first)
 
-	err = rt.kadBucketDB.Put(idB, []byte(""))
+	err = rt.kadBucketDB.Put(ctx, idB, []byte(""))
 	assert.NoError(t, err)
 
 	second, err := rt.determineLeafDepth(idB)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, second)
 
-	err = rt.kadBucketDB.Put(idC, []byte(""))
+	err = rt.kadBucketDB.Put(ctx, idC, []byte(""))
 	assert.NoError(t, err)
 
 	one, err := rt.determineLeafDepth(idA)
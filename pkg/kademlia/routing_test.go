@@ -135,7 +135,7 @@ func TestConnectionSuccess(t *testing.T) {
 	//Updates node
 	err := rt.ConnectionSuccess(node1)
 	assert.NoError(t, err)
-	v, err := rt.nodeBucketDB.Get([]byte(id))
+	v, err := rt.nodeBucketDB.Get(ctx, []byte(id))
 	assert.NoError(t, err)
 	n, err := unmarshalNodes(storage.Keys{storage.Key(id)}, []storage.Value{v})
 	assert.NoError(t, err)
@@ -144,7 +144,7 @@ func TestConnectionSuccess(t *testing.T) {
 	//Add Node
 	err = rt.ConnectionSuccess(node2)
 	assert.NoError(t, err)
-	v, err = rt.nodeBucketDB.Get([]byte(id2))
+	v, err = rt.nodeBucketDB.Get(ctx, []byte(id2))
 	assert.NoError(t, err)
 	n, err = unmarshalNodes(storage.Keys{storage.Key(id2)}, []storage.Value{v})
 	assert.NoError(t, err)
@@ -158,7 +158,7 @@ func TestConnectionFailed(t *testing.T) {
 	defer cleanup()
 	err := rt.ConnectionFailed(node)
 	assert.NoError(t, err)
-	v, err := rt.nodeBucketDB.Get([]byte(id))
+	v, err := rt.nodeBucketDB.Get(ctx, []byte(id))
 	assert.Error(t, err)
 	assert.Nil(t, v)
 }
@@ -0,0 +1,177 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	base58 "github.com/jbenet/go-base58"
+
+	pb "storj.io/storj/protos/macaroon"
+)
+
+// ActionType is the type of operation an API key is used for
+type ActionType int
+
+const (
+	_ ActionType = iota
+	// ActionRead is reading a path
+	ActionRead
+	// ActionWrite is writing a path
+	ActionWrite
+	// ActionList is listing the paths under a prefix
+	ActionList
+	// ActionDelete is deleting a path
+	ActionDelete
+)
+
+// Action is a request an API key is checked against. Path is the path
+// within Bucket, for lists it is the prefix being listed.
+type Action struct {
+	Op     ActionType
+	Bucket []byte
+	Path   []byte
+	Time   time.Time
+}
+
+// APIKey is a macaroon whose caveats are Caveat protobufs
+type APIKey struct {
+	mac *Macaroon
+}
+
+// NewAPIKey creates an unrestricted API key signed with secret
+func NewAPIKey(secret []byte) (*APIKey, error) {
+	mac, err := NewUnrestricted(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &APIKey{mac: mac}, nil
+}
+
+// ParseAPIKey decodes an API key encoded with Serialize
+func ParseAPIKey(key string) (*APIKey, error) {
+	data := base58.Decode(key)
+	if len(data) == 0 {
+		return nil, ErrFormat.New("invalid api key encoding")
+	}
+	mac, err := ParseMacaroon(data)
+	if err != nil {
+		return nil, err
+	}
+	return &APIKey{mac: mac}, nil
+}
+
+// Serialize encodes the API key as a string
+func (a *APIKey) Serialize() string {
+	return base58.Encode(a.mac.Serialize())
+}
+
+// ProjectID identifies the project the API key belongs to. Restricted
+// copies of a key belong to the same project as the key itself.
+func (a *APIKey) ProjectID() string {
+	hash := sha256.Sum256(a.mac.Head())
+	return hex.EncodeToString(hash[:16])
+}
+
+// Restrict returns a copy of the API key further restricted by caveat. The
+// secret isn't needed, so keys can be restricted offline by their holder.
+func (a *APIKey) Restrict(caveat pb.Caveat) (*APIKey, error) {
+	if len(caveat.Nonce) == 0 {
+		nonce, err := NewSecret()
+		if err != nil {
+			return nil, err
+		}
+		caveat.Nonce = nonce
+	}
+	data, err := proto.Marshal(&caveat)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &APIKey{mac: a.mac.AddFirstPartyCaveat(data)}, nil
+}
+
+// Caveats returns the caveats restricting the API key
+func (a *APIKey) Caveats() (caveats []pb.Caveat, err error) {
+	for _, data := range a.mac.Caveats() {
+		var caveat pb.Caveat
+		if err := proto.Unmarshal(data, &caveat); err != nil {
+			return nil, ErrFormat.Wrap(err)
+		}
+		caveats = append(caveats, caveat)
+	}
+	return caveats, nil
+}
+
+// Check returns an error if the API key wasn't signed with secret or if any
+// of its caveats disallows action
+func (a *APIKey) Check(secret []byte, action Action) error {
+	if !a.mac.Validate(secret) {
+		return ErrUnauthorized.New("invalid api key signature")
+	}
+	caveats, err := a.Caveats()
+	if err != nil {
+		return err
+	}
+	for _, caveat := range caveats {
+		if !allows(caveat, action) {
+			return ErrUnauthorized.New("action disallowed")
+		}
+	}
+	return nil
+}
+
+// allows returns whether caveat allows action
+func allows(caveat pb.Caveat, action Action) bool {
+	switch action.Op {
+	case ActionRead:
+		if caveat.DisallowReads {
+			return false
+		}
+	case ActionWrite:
+		if caveat.DisallowWrites {
+			return false
+		}
+	case ActionList:
+		if caveat.DisallowLists {
+			return false
+		}
+	case ActionDelete:
+		if caveat.DisallowDeletes {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if caveat.NotAfter != 0 && action.Time.Unix() > caveat.NotAfter {
+		return false
+	}
+
+	if len(caveat.AllowedPaths) == 0 {
+		return true
+	}
+	for _, allowed := range caveat.AllowedPaths {
+		if !bytes.Equal(allowed.Bucket, action.Bucket) || len(action.Bucket) == 0 {
+			continue
+		}
+		if allowed.Exact {
+			// an exact path isn't a prefix, so nothing can be listed
+			// under it
+			if action.Op != ActionList && bytes.Equal(action.Path, allowed.PathPrefix) {
+				return true
+			}
+		} else if bytes.HasPrefix(action.Path, allowed.PathPrefix) {
+			return true
+		}
+		// the bucket itself has to be readable to access anything inside it
+		if action.Op == ActionRead && len(action.Path) == 0 {
+			return true
+		}
+	}
+	return false
+}
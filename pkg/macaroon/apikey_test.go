@@ -0,0 +1,108 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "storj.io/storj/protos/macaroon"
+)
+
+func TestAPIKey(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+
+	root, err := NewAPIKey(secret)
+	assert.NoError(t, err)
+
+	photos := []*pb.CaveatPath{{Bucket: []byte("bucket"), PathPrefix: []byte("photos/")}}
+	cat := []*pb.CaveatPath{{Bucket: []byte("bucket"), PathPrefix: []byte("photos/cat.jpg"), Exact: true}}
+
+	for i, tt := range []struct {
+		caveats []pb.Caveat
+		action  Action
+		allowed bool
+	}{
+		{nil, Action{Op: ActionWrite, Time: now}, true},
+		{[]pb.Caveat{{DisallowWrites: true}}, Action{Op: ActionRead, Time: now}, true},
+		{[]pb.Caveat{{DisallowWrites: true}}, Action{Op: ActionWrite, Time: now}, false},
+		{[]pb.Caveat{{DisallowReads: true}}, Action{Op: ActionRead, Time: now}, false},
+		{[]pb.Caveat{{DisallowLists: true}}, Action{Op: ActionList, Time: now}, false},
+		{[]pb.Caveat{{DisallowDeletes: true}}, Action{Op: ActionDelete, Time: now}, false},
+		{[]pb.Caveat{{NotAfter: now.Add(time.Hour).Unix()}}, Action{Op: ActionRead, Time: now}, true},
+		{[]pb.Caveat{{NotAfter: now.Add(-time.Hour).Unix()}}, Action{Op: ActionRead, Time: now}, false},
+		{[]pb.Caveat{{AllowedPaths: photos}},
+			Action{Op: ActionRead, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg"), Time: now}, true},
+		{[]pb.Caveat{{AllowedPaths: photos}},
+			Action{Op: ActionRead, Bucket: []byte("bucket"), Time: now}, true},
+		{[]pb.Caveat{{AllowedPaths: photos}},
+			Action{Op: ActionList, Bucket: []byte("bucket"), Time: now}, false},
+		{[]pb.Caveat{{AllowedPaths: photos}},
+			Action{Op: ActionRead, Bucket: []byte("bucket"), Path: []byte("notes.txt"), Time: now}, false},
+		{[]pb.Caveat{{AllowedPaths: photos}},
+			Action{Op: ActionRead, Bucket: []byte("other"), Path: []byte("photos/cat.jpg"), Time: now}, false},
+		{[]pb.Caveat{{AllowedPaths: photos}}, Action{Op: ActionRead, Time: now}, false},
+		// an exact path doesn't allow the paths it's a prefix of
+		{[]pb.Caveat{{AllowedPaths: cat}},
+			Action{Op: ActionRead, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg"), Time: now}, true},
+		{[]pb.Caveat{{AllowedPaths: cat}},
+			Action{Op: ActionWrite, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg"), Time: now}, true},
+		{[]pb.Caveat{{AllowedPaths: cat}},
+			Action{Op: ActionRead, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg.bak"), Time: now}, false},
+		{[]pb.Caveat{{AllowedPaths: cat}},
+			Action{Op: ActionRead, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg/secret"), Time: now}, false},
+		{[]pb.Caveat{{AllowedPaths: cat}},
+			Action{Op: ActionList, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg"), Time: now}, false},
+		{[]pb.Caveat{{AllowedPaths: cat}},
+			Action{Op: ActionRead, Bucket: []byte("bucket"), Time: now}, true},
+		// every caveat has to allow the action
+		{[]pb.Caveat{{AllowedPaths: photos}, {DisallowWrites: true}},
+			Action{Op: ActionWrite, Bucket: []byte("bucket"), Path: []byte("photos/cat.jpg"), Time: now}, false},
+	} {
+		key := root
+		for _, caveat := range tt.caveats {
+			key, err = key.Restrict(caveat)
+			assert.NoError(t, err, i)
+		}
+
+		parsed, err := ParseAPIKey(key.Serialize())
+		assert.NoError(t, err, i)
+
+		err = parsed.Check(secret, tt.action)
+		if tt.allowed {
+			assert.NoError(t, err, i)
+		} else {
+			assert.True(t, ErrUnauthorized.Has(err), i)
+		}
+	}
+}
+
+func TestAPIKeyInvalid(t *testing.T) {
+	key, err := NewAPIKey([]byte("secret"))
+	assert.NoError(t, err)
+
+	err = key.Check([]byte("other secret"), Action{Op: ActionRead, Time: time.Now()})
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	_, err = ParseAPIKey("")
+	assert.True(t, ErrFormat.Has(err))
+	_, err = ParseAPIKey("0OIl")
+	assert.True(t, ErrFormat.Has(err))
+}
+
+func TestAPIKeyProjectID(t *testing.T) {
+	root, err := NewAPIKey([]byte("secret"))
+	assert.NoError(t, err)
+	other, err := NewAPIKey([]byte("secret"))
+	assert.NoError(t, err)
+
+	restricted, err := root.Restrict(pb.Caveat{DisallowWrites: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, root.ProjectID(), restricted.ProjectID())
+	assert.NotEqual(t, root.ProjectID(), other.ProjectID())
+}
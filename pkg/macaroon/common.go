@@ -0,0 +1,17 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"github.com/zeebo/errs"
+)
+
+var (
+	// Error is a macaroon error
+	Error = errs.Class("macaroon error")
+	// ErrFormat is used when a macaroon or API key can't be parsed
+	ErrFormat = errs.Class("api key format error")
+	// ErrUnauthorized is used when an API key doesn't allow an action
+	ErrUnauthorized = errs.Class("api key unauthorized error")
+)
@@ -0,0 +1,120 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Macaroon is a bearer token with a chain of first party caveats. Every
+// caveat added to the macaroon is signed with the signature of the previous
+// one, so caveats can be added by anyone holding the macaroon but never
+// removed without knowing the root secret.
+type Macaroon struct {
+	head    []byte
+	caveats [][]byte
+	tail    []byte
+}
+
+// NewUnrestricted creates a macaroon without caveats signed with secret
+func NewUnrestricted(secret []byte) (*Macaroon, error) {
+	head, err := NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	return &Macaroon{
+		head: head,
+		tail: sign(secret, head),
+	}, nil
+}
+
+// NewSecret generates a new random secret suitable for signing macaroons
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return secret, nil
+}
+
+// AddFirstPartyCaveat returns a copy of the macaroon with caveat added
+func (m *Macaroon) AddFirstPartyCaveat(caveat []byte) *Macaroon {
+	caveats := make([][]byte, 0, len(m.caveats)+1)
+	caveats = append(caveats, m.caveats...)
+	caveats = append(caveats, append([]byte(nil), caveat...))
+	return &Macaroon{
+		head:    m.head,
+		caveats: caveats,
+		tail:    sign(m.tail, caveat),
+	}
+}
+
+// Validate returns whether the macaroon was derived from a macaroon signed
+// with secret
+func (m *Macaroon) Validate(secret []byte) bool {
+	tail := sign(secret, m.head)
+	for _, caveat := range m.caveats {
+		tail = sign(tail, caveat)
+	}
+	return hmac.Equal(tail, m.tail)
+}
+
+// Head returns the identifier of the macaroon
+func (m *Macaroon) Head() []byte { return m.head }
+
+// Caveats returns the caveats of the macaroon in the order they were added
+func (m *Macaroon) Caveats() [][]byte { return m.caveats }
+
+// Tail returns the signature of the macaroon
+func (m *Macaroon) Tail() []byte { return m.tail }
+
+// Serialize encodes the macaroon as the length prefixed head, caveats and
+// tail
+func (m *Macaroon) Serialize() []byte {
+	var data []byte
+	data = appendField(data, m.head)
+	for _, caveat := range m.caveats {
+		data = appendField(data, caveat)
+	}
+	return appendField(data, m.tail)
+}
+
+// ParseMacaroon decodes a macaroon encoded with Serialize
+func ParseMacaroon(data []byte) (*Macaroon, error) {
+	var fields [][]byte
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return nil, ErrFormat.New("invalid field length")
+		}
+		data = data[n:]
+		fields = append(fields, data[:size])
+		data = data[size:]
+	}
+	if len(fields) < 2 {
+		return nil, ErrFormat.New("missing head or tail")
+	}
+	return &Macaroon{
+		head:    fields[0],
+		caveats: fields[1 : len(fields)-1],
+		tail:    fields[len(fields)-1],
+	}, nil
+}
+
+func appendField(data, field []byte) []byte {
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(field)))
+	data = append(data, size[:n]...)
+	return append(data, field...)
+}
+
+func sign(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMacaroon(t *testing.T) {
+	secret := []byte("secret")
+
+	mac, err := NewUnrestricted(secret)
+	assert.NoError(t, err)
+	assert.True(t, mac.Validate(secret))
+	assert.False(t, mac.Validate([]byte("other secret")))
+
+	restricted := mac.AddFirstPartyCaveat([]byte("first"))
+	restricted = restricted.AddFirstPartyCaveat([]byte("second"))
+	assert.True(t, restricted.Validate(secret))
+	assert.Equal(t, [][]byte{[]byte("first"), []byte("second")}, restricted.Caveats())
+	assert.Empty(t, mac.Caveats())
+
+	// removing or changing a caveat invalidates the signature
+	removed := &Macaroon{head: restricted.head, caveats: restricted.caveats[:1], tail: restricted.tail}
+	assert.False(t, removed.Validate(secret))
+	changed := &Macaroon{head: restricted.head, caveats: [][]byte{[]byte("first"), []byte("other")}, tail: restricted.tail}
+	assert.False(t, changed.Validate(secret))
+}
+
+func TestMacaroonSerialize(t *testing.T) {
+	secret := []byte("secret")
+
+	mac, err := NewUnrestricted(secret)
+	assert.NoError(t, err)
+	mac = mac.AddFirstPartyCaveat([]byte("caveat"))
+
+	parsed, err := ParseMacaroon(mac.Serialize())
+	assert.NoError(t, err)
+	assert.Equal(t, mac.Head(), parsed.Head())
+	assert.Equal(t, mac.Caveats(), parsed.Caveats())
+	assert.Equal(t, mac.Tail(), parsed.Tail())
+	assert.True(t, parsed.Validate(secret))
+
+	for _, data := range [][]byte{nil, {1, 1}, {5, 1}} {
+		_, err := ParseMacaroon(data)
+		assert.True(t, ErrFormat.Has(err))
+	}
+}
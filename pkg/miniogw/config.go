@@ -5,25 +5,35 @@ package miniogw
 
 import (
 	"context"
+	"net"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/minio/cli"
 	minio "github.com/minio/minio/cmd"
-	"github.com/vivint/infectious"
+	"go.uber.org/zap"
 
 	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/miniogw/logging"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
+	"storj.io/storj/pkg/pool"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/statdb/sdbclient"
 	"storj.io/storj/pkg/storage/buckets"
 	ecclient "storj.io/storj/pkg/storage/ec"
 	"storj.io/storj/pkg/storage/objects"
 	segment "storj.io/storj/pkg/storage/segments"
 	streams "storj.io/storj/pkg/storage/streams"
 	"storj.io/storj/pkg/transport"
+	ppb "storj.io/storj/protos/pointerdb"
 )
 
+// latencyBatchSize is the number of storage node latencies collected before
+// they are reported to the stats db
+const latencyBatchSize = 100
+
 // RSConfig is a configuration struct that keeps details about default
 // redundancy strategy information
 type RSConfig struct {
@@ -33,6 +43,7 @@ type RSConfig struct {
 	RepairThreshold  int `help:"the minimum safe pieces before a repair is triggered. m." default:"30"`
 	SuccessThreshold int `help:"the desired total pieces for a segment. o." default:"40"`
 	MaxThreshold     int `help:"the largest amount of pieces to encode to. n." default:"50"`
+	Replicas         int `help:"the number of copies stored by the buckets using replication" default:"3"`
 }
 
 // MinioConfig is a configuration struct that keeps details about starting
@@ -47,12 +58,18 @@ type MinioConfig struct {
 // the miniogw figures out how to talk to the rest of the network.
 type ClientConfig struct {
 	// TODO(jt): these should probably be the same
-	OverlayAddr   string `help:"Address to contact overlay server through"`
-	PointerDBAddr string `help:"Address to contact pointerdb server through"`
+	OverlayAddr    string        `help:"Address to contact overlay server through"`
+	PointerDBAddr  string        `help:"Address to contact pointerdb server through"`
+	StatDBAddr     string        `help:"Address to report storage node latencies and failures to. If empty, they aren't reported" default:""`
+	StatDBAdminKey string        `help:"the stats db admin api key to report storage node latencies with" default:""`
+	MaxLatency     time.Duration `help:"the maximum 90th percentile latency of the storage nodes uploaded to, 0 for any" default:"0"`
 
-	APIKey        string `help:"API Key (TODO: this needs to change to macaroons somehow)"`
+	APIKey        string `help:"the api key to use for the satellite"`
 	MaxInlineSize int    `help:"max inline segment size in bytes" default:"4096"`
 	SegmentSize   int64  `help:"the size of a segment in bytes" default:"64000000"`
+	EncKey        string `help:"root key for encrypting the data. If empty, new objects aren't encrypted" default:""`
+	EncBlockSize  int    `help:"size (in bytes) of encrypted blocks, which are the unit of ranged reads" default:"1024"`
+	DedupSecret   string `help:"secret deduplicating the new objects in content-defined chunks shared within their bucket. If empty, new objects aren't deduplicated" default:""`
 }
 
 // Config is a general miniogw configuration struct. This should be everything
@@ -62,6 +79,7 @@ type Config struct {
 	MinioConfig
 	ClientConfig
 	RSConfig
+	Pool pool.Config
 }
 
 // Run starts a Minio Gateway given proper config
@@ -73,11 +91,23 @@ func (c Config) Run(ctx context.Context) (err error) {
 		return err
 	}
 
+	// minio can't serve every S3 request with the gateway, so the S3
+	// router listens at the address and minio behind it on the loopback
+	lis, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		return err
+	}
+	minioAddr, err := loopbackAddress()
+	if err != nil {
+		_ = lis.Close()
+		return err
+	}
+
 	err = minio.RegisterGatewayCommand(cli.Command{
 		Name:  "storj",
 		Usage: "Storj",
 		Action: func(cliCtx *cli.Context) error {
-			return c.action(ctx, cliCtx, identity)
+			return c.action(ctx, cliCtx, identity, lis, minioAddr)
 		},
 		HideHelpCommand: true,
 	})
@@ -96,61 +126,131 @@ func (c Config) Run(ctx context.Context) (err error) {
 	}
 
 	minio.Main([]string{"storj", "gateway", "storj",
-		"--address", c.Address, "--config-dir", c.MinioDir, "--quiet"})
+		"--address", minioAddr, "--config-dir", c.MinioDir, "--quiet"})
 	return Error.New("unexpected minio exit")
 }
 
-func (c Config) action(ctx context.Context, cliCtx *cli.Context, identity *provider.FullIdentity) (err error) {
+func (c Config) action(ctx context.Context, cliCtx *cli.Context, identity *provider.FullIdentity,
+	lis net.Listener, minioAddr string) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	gw, err := c.NewGateway(ctx, identity)
+	bs, err := c.GetBucketStore(ctx, identity)
+	if err != nil {
+		return err
+	}
+	gw := NewStorjGateway(bs)
+
+	router, err := newS3Router(&storjObjects{storj: gw}, c.AccessKey, c.SecretKey,
+		"http://"+minioAddr)
 	if err != nil {
 		return err
 	}
+	go func() {
+		zap.S().Errorf("s3 router exited: %v", http.Serve(lis, router))
+	}()
 
 	minio.StartGateway(cliCtx, logging.Gateway(gw))
 	return Error.New("unexpected minio exit")
 }
 
+// loopbackAddress returns a free address on the loopback interface
+func loopbackAddress() (string, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = lis.Close() }()
+	return lis.Addr().String(), nil
+}
+
 // GetBucketStore returns an implementation of buckets.Store
 func (c Config) GetBucketStore(ctx context.Context, identity *provider.FullIdentity) (bs buckets.Store, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	t := transport.NewClient(identity)
-
-	var oc overlay.Client
-	oc, err = overlay.NewOverlayClient(identity, c.OverlayAddr)
+	minDifficulty, err := c.Difficulty()
 	if err != nil {
 		return nil, err
 	}
 
-	pdb, err := pdbclient.NewClient(identity, c.PointerDBAddr, []byte(c.APIKey))
+	t := pool.NewConnectionManager(
+		transport.NewClient(identity, minDifficulty), c.Pool)
+
+	oc, err := overlay.NewOverlayClient(identity, c.OverlayAddr)
 	if err != nil {
 		return nil, err
 	}
+	oc.MaxLatency = c.MaxLatency
 
-	ec := ecclient.NewClient(identity, t, c.MaxBufferMem)
-	fc, err := infectious.NewFEC(c.MinThreshold, c.MaxThreshold)
+	pdb, err := pdbclient.NewClient(identity, c.PointerDBAddr, []byte(c.APIKey))
 	if err != nil {
 		return nil, err
 	}
-	rs, err := eestream.NewRedundancyStrategy(
-		eestream.NewRSScheme(fc, c.ErasureShareSize),
-		c.RepairThreshold, c.SuccessThreshold)
-	if err != nil {
-		return nil, err
+
+	// the interfaces are only set when statdb is configured, so that they
+	// aren't non-nil interfaces holding a nil recorder
+	var lr ecclient.LatencyRecorder
+	var nr segment.NodeReporter
+	if c.StatDBAddr != "" {
+		sdb, err := sdbclient.NewClient(identity, c.StatDBAddr, []byte(c.StatDBAdminKey))
+		if err != nil {
+			return nil, err
+		}
+		recorder := sdbclient.NewLatencyRecorder(sdb, latencyBatchSize)
+		lr, nr = recorder, recorder
 	}
 
-	segments := segment.NewSegmentStore(oc, ec, pdb, rs, c.MaxInlineSize)
+	ec := ecclient.NewClient(identity, t, c.MaxBufferMem, lr)
 
-	// segment size 64MB
-	stream, err := streams.NewStreamStore(segments, c.SegmentSize)
-	if err != nil {
-		return nil, err
+	var rootKey *[32]byte
+	if c.EncKey != "" {
+		rootKey = streams.RootKey(c.EncKey)
+	}
+	var dedupKey *[32]byte
+	if c.DedupSecret != "" {
+		dedupKey = streams.DedupKey(c.DedupSecret)
 	}
-	obj := objects.NewStore(stream)
 
-	return buckets.NewStore(obj), nil
+	// every redundancy scheme gets its own stack of stores, so that the
+	// buckets can select one
+	schemes := make(map[string]objects.Store)
+	for _, st := range []ppb.RedundancyScheme_SchemeType{
+		ppb.RedundancyScheme_RS,
+		ppb.RedundancyScheme_REPLICATION,
+		ppb.RedundancyScheme_CAUCHY,
+	} {
+		rs, err := c.redundancyStrategy(st)
+		if err != nil {
+			return nil, err
+		}
+		segments := segment.NewSegmentStore(oc, ec, pdb, rs, st, c.MaxInlineSize, nr)
+
+		// segment size 64MB
+		stream, err := streams.NewStreamStore(segments, c.SegmentSize, rootKey, dedupKey, c.EncBlockSize)
+		if err != nil {
+			return nil, err
+		}
+		schemes[st.String()] = objects.NewStore(stream)
+	}
+
+	return buckets.NewStoreWithSchemes(schemes[ppb.RedundancyScheme_RS.String()], schemes), nil
+}
+
+// redundancyStrategy returns the configured redundancy strategy of the
+// scheme type. Replication stores Replicas copies and needs all of them to
+// be uploaded, the other schemes use the thresholds.
+func (c Config) redundancyStrategy(st ppb.RedundancyScheme_SchemeType) (eestream.RedundancyStrategy, error) {
+	if st == ppb.RedundancyScheme_REPLICATION {
+		es, err := segment.NewErasureScheme(st, 1, c.Replicas, c.ErasureShareSize)
+		if err != nil {
+			return eestream.RedundancyStrategy{}, err
+		}
+		return eestream.NewRedundancyStrategy(es, 0, 0)
+	}
+	es, err := segment.NewErasureScheme(st, c.MinThreshold, c.MaxThreshold, c.ErasureShareSize)
+	if err != nil {
+		return eestream.RedundancyStrategy{}, err
+	}
+	return eestream.NewRedundancyStrategy(es, c.RepairThreshold, c.SuccessThreshold)
 }
 
 // NewGateway creates a new minio Gateway
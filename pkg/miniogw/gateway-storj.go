@@ -80,6 +80,15 @@ func (s *storjObjects) DeleteBucket(ctx context.Context, bucket string) (err err
 	if len(items) > 0 {
 		return minio.BucketNotEmpty{Bucket: bucket}
 	}
+	if vs, ok := o.(objects.VersionedStore); ok {
+		versions, _, err := vs.ListVersions(ctx, nil, nil, 1)
+		if err != nil {
+			return err
+		}
+		if len(versions) > 0 {
+			return minio.BucketNotEmpty{Bucket: bucket}
+		}
+	}
 	return s.storj.bs.Delete(ctx, bucket)
 }
 
@@ -0,0 +1,45 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+
+	minio "github.com/minio/minio/cmd"
+
+	"storj.io/storj/pkg/storage/lifecycle"
+	"storj.io/storj/storage"
+)
+
+// GetBucketLifecycle returns the lifecycle rules of the bucket
+func (s *storjObjects) GetBucketLifecycle(ctx context.Context, bucket string) (
+	rules []lifecycle.Rule, err error) {
+	defer mon.Task()(&ctx)(&err)
+	m, err := s.storj.bs.Get(ctx, bucket)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, minio.BucketNotFound{Bucket: bucket}
+		}
+		return nil, err
+	}
+	return m.Lifecycle, nil
+}
+
+// PutBucketLifecycle replaces the lifecycle rules of the bucket. The rules
+// are applied by the satellite.
+func (s *storjObjects) PutBucketLifecycle(ctx context.Context, bucket string,
+	rules []lifecycle.Rule) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	_, err = s.storj.bs.SetLifecycle(ctx, bucket, rules)
+	if err != nil && storage.ErrKeyNotFound.Has(err) {
+		return minio.BucketNotFound{Bucket: bucket}
+	}
+	return err
+}
+
+// DeleteBucketLifecycle removes the lifecycle rules of the bucket
+func (s *storjObjects) DeleteBucketLifecycle(ctx context.Context, bucket string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.PutBucketLifecycle(ctx, bucket, nil)
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storage/lifecycle"
+	"storj.io/storj/storage"
+)
+
+func TestBucketLifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	rules := []lifecycle.Rule{{ID: "logs", Prefix: "logs/", ExpirationDays: 30}}
+
+	mockBS.EXPECT().SetLifecycle(gomock.Any(), "mybucket", rules).Return(buckets.Meta{Lifecycle: rules}, nil)
+	assert.NoError(t, storjObj.PutBucketLifecycle(ctx, "mybucket", rules))
+
+	mockBS.EXPECT().Get(gomock.Any(), "mybucket").Return(buckets.Meta{Lifecycle: rules}, nil)
+	got, err := storjObj.GetBucketLifecycle(ctx, "mybucket")
+	assert.NoError(t, err)
+	assert.Equal(t, rules, got)
+
+	mockBS.EXPECT().SetLifecycle(gomock.Any(), "mybucket", []lifecycle.Rule(nil)).Return(buckets.Meta{}, nil)
+	assert.NoError(t, storjObj.DeleteBucketLifecycle(ctx, "mybucket"))
+
+	mockBS.EXPECT().Get(gomock.Any(), "nobucket").Return(buckets.Meta{}, storage.ErrKeyNotFound.New("nobucket"))
+	_, err = storjObj.GetBucketLifecycle(ctx, "nobucket")
+	assert.Equal(t, minio.BucketNotFound{Bucket: "nobucket"}, err)
+}
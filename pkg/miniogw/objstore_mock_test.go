@@ -96,6 +96,30 @@ func (mr *MockStoreMockRecorder) Meta(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Meta", reflect.TypeOf((*MockStore)(nil).Meta), arg0, arg1)
 }
 
+// Move mocks base method
+func (m *MockStore) Move(arg0 context.Context, arg1, arg2 paths.Path) error {
+	ret := m.ctrl.Call(m, "Move", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move
+func (mr *MockStoreMockRecorder) Move(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockStore)(nil).Move), arg0, arg1, arg2)
+}
+
+// MoveBatch mocks base method
+func (m *MockStore) MoveBatch(arg0 context.Context, arg1, arg2 []paths.Path) error {
+	ret := m.ctrl.Call(m, "MoveBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveBatch indicates an expected call of MoveBatch
+func (mr *MockStoreMockRecorder) MoveBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveBatch", reflect.TypeOf((*MockStore)(nil).MoveBatch), arg0, arg1, arg2)
+}
+
 // Put mocks base method
 func (m *MockStore) Put(arg0 context.Context, arg1 paths.Path, arg2 io.Reader, arg3 objects.SerializableMeta, arg4 time.Time) (objects.Meta, error) {
 	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2, arg3, arg4)
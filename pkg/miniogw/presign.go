@@ -0,0 +1,89 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxPresignExpiry is the longest validity of a presigned URL accepted by
+	// the S3 signature version 4
+	MaxPresignExpiry = 7 * 24 * time.Hour
+
+	// presignRegion is the region the gateway checks the signatures with
+	presignRegion = "us-east-1"
+	amzDate       = "20060102T150405Z"
+)
+
+// PresignURL returns a URL of the gateway at endpoint, e.g.
+// http://localhost:7777, allowing whoever has it to GET or PUT the object
+// at path in bucket until expires passes. The URL is signed with the keys
+// of the gateway, which it doesn't disclose, and is only valid for this
+// method and object.
+//
+// The gateway serves the URL with its own API key, not a restricted one,
+// so the URL grants the request with the full access of the gateway. It
+// can't be revoked before it expires, other than by changing the secret
+// key of the gateway, which revokes all the URLs signed with it.
+func (c MinioConfig) PresignURL(endpoint, method, bucket, path string,
+	expires time.Duration) (string, error) {
+	return presignURL(endpoint, c.AccessKey, c.SecretKey, method, bucket, path,
+		time.Now(), expires)
+}
+
+// presignURL signs the request as the S3 signature version 4 does in a
+// query string, as of now
+func presignURL(endpoint, accessKey, secretKey, method, bucket, path string,
+	now time.Time, expires time.Duration) (string, error) {
+	if method != http.MethodGet && method != http.MethodPut {
+		return "", Error.New("presigned URLs only allow GET or PUT, not %s", method)
+	}
+	if expires < time.Second || expires > MaxPresignExpiry {
+		return "", Error.New("a presigned URL expires after 1s to %s, not %s",
+			MaxPresignExpiry, expires)
+	}
+	if bucket == "" || path == "" {
+		return "", Error.New("a presigned URL needs a bucket and an object path")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", Error.New("invalid endpoint %q, e.g. http://localhost:7777", endpoint)
+	}
+
+	uri := "/" + uriEncode(bucket, true) + "/" + uriEncode(strings.TrimPrefix(path, "/"), false)
+	return u.Scheme + "://" + u.Host + uri + "?" +
+		signQuery(method, u.Host, uri, accessKey, secretKey, now, expires), nil
+}
+
+// signQuery returns the query string authenticating the request of method
+// on uri at host, as of now and until expires passes
+func signQuery(method, host, uri, accessKey, secretKey string,
+	now time.Time, expires time.Duration) string {
+	date := now.UTC().Format(amzDate)
+	query := canonicalQuery(url.Values{
+		"X-Amz-Algorithm":     {signAlgorithm},
+		"X-Amz-Credential":    {accessKey + "/" + credentialScope(date[:8], presignRegion)},
+		"X-Amz-Date":          {date},
+		"X-Amz-Expires":       {fmt.Sprint(int64(expires / time.Second))},
+		"X-Amz-SignedHeaders": {"host"},
+	})
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		uri,
+		query,
+		"host:" + host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	return query + "&X-Amz-Signature=" + sign(secretKey, date, presignRegion, canonicalRequest)
+}
@@ -0,0 +1,570 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	minio "github.com/minio/minio/cmd"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/lifecycle"
+)
+
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// maxVersionKeys is the largest number of versions listed at once
+const maxVersionKeys = 1000
+
+// routedLayer is the part of the gateway layer serving the S3 requests on
+// versions and lifecycle rules, which minio doesn't route to the gateways
+type routedLayer interface {
+	GetBucketVersioning(ctx context.Context, bucket string) (bool, error)
+	SetBucketVersioning(ctx context.Context, bucket string, enabled bool) error
+	GetObjectVersion(ctx context.Context, bucket, object, version string,
+		startOffset int64, length int64, writer io.Writer) error
+	GetObjectVersionInfo(ctx context.Context, bucket, object, version string) (
+		ObjectVersionInfo, error)
+	DeleteObjectVersion(ctx context.Context, bucket, object, version string) error
+	ListObjectVersions(ctx context.Context, bucket, prefix, keyMarker string,
+		maxKeys int) (ListObjectVersionsInfo, error)
+	GetBucketLifecycle(ctx context.Context, bucket string) ([]lifecycle.Rule, error)
+	PutBucketLifecycle(ctx context.Context, bucket string, rules []lifecycle.Rule) error
+	DeleteBucketLifecycle(ctx context.Context, bucket string) error
+}
+
+// s3Router serves the S3 requests minio doesn't route to the gateways with
+// the layer, and passes the others on to minio. It only understands the
+// path-style requests, as minio does without a domain configured.
+type s3Router struct {
+	layer     routedLayer
+	accessKey string
+	secretKey string
+	next      http.Handler
+	now       func() time.Time
+}
+
+// newS3Router returns a router passing the requests it doesn't serve to
+// the minio server at minioURL
+func newS3Router(layer routedLayer, accessKey, secretKey, minioURL string) (*s3Router, error) {
+	u, err := url.Parse(minioURL)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &s3Router{
+		layer:     layer,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		next:      httputil.NewSingleHostReverseProxy(u),
+		now:       time.Now,
+	}, nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *s3Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, object := splitBucketPath(r.URL.Path)
+	query := r.URL.Query()
+	_, versions := query["versions"]
+	_, versioning := query["versioning"]
+	_, versionID := query["versionId"]
+	_, rules := query["lifecycle"]
+
+	var handler func(http.ResponseWriter, *http.Request, string, string) error
+	switch {
+	case bucket == "":
+	case object == "" && versions && r.Method == http.MethodGet:
+		handler = s.listVersions
+	case object == "" && versioning && r.Method == http.MethodGet:
+		handler = s.getVersioning
+	case object == "" && versioning && r.Method == http.MethodPut:
+		handler = s.putVersioning
+	case object == "" && rules && r.Method == http.MethodGet:
+		handler = s.getLifecycle
+	case object == "" && rules && r.Method == http.MethodPut:
+		handler = s.putLifecycle
+	case object == "" && rules && r.Method == http.MethodDelete:
+		handler = s.deleteLifecycle
+	case object != "" && versionID && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		handler = s.getVersion
+	case object != "" && versionID && r.Method == http.MethodDelete:
+		handler = s.deleteVersion
+	}
+	if handler == nil {
+		s.next.ServeHTTP(w, r)
+		return
+	}
+
+	err := verifySignature(r, s.accessKey, s.secretKey, s.now())
+	if err == nil {
+		err = handler(w, r, bucket, object)
+	}
+	if err != nil {
+		writeError(w, r, err)
+	}
+}
+
+// listVersionsResult is the response of ListObjectVersions
+type listVersionsResult struct {
+	XMLName       xml.Name       `xml:"ListVersionsResult"`
+	Xmlns         string         `xml:"xmlns,attr"`
+	Name          string         `xml:"Name"`
+	Prefix        string         `xml:"Prefix"`
+	KeyMarker     string         `xml:"KeyMarker"`
+	NextKeyMarker string         `xml:"NextKeyMarker,omitempty"`
+	MaxKeys       int            `xml:"MaxKeys"`
+	IsTruncated   bool           `xml:"IsTruncated"`
+	Versions      []versionEntry `xml:",any"`
+}
+
+// versionEntry is a Version or a DeleteMarker of the list of versions. The
+// two are interleaved in the order of the keys.
+type versionEntry struct {
+	XMLName      xml.Name
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag,omitempty"`
+	Size         *int64 `xml:"Size,omitempty"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+}
+
+// listVersions serves ListObjectVersions. The key markers are relative to
+// the prefix, as the markers of the objects listed by the gateway are.
+func (s *s3Router) listVersions(w http.ResponseWriter, r *http.Request, bucket, _ string) error {
+	query := r.URL.Query()
+	if query.Get("delimiter") != "" {
+		return apiError{http.StatusNotImplemented, "NotImplemented",
+			"listing versions with a delimiter isn't supported"}
+	}
+	maxKeys := maxVersionKeys
+	if value := query.Get("max-keys"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return apiError{http.StatusBadRequest, "InvalidArgument",
+				fmt.Sprintf("invalid max-keys %q", value)}
+		}
+		if n < maxKeys {
+			maxKeys = n
+		}
+	}
+
+	prefix, keyMarker := query.Get("prefix"), query.Get("key-marker")
+	info, err := s.layer.ListObjectVersions(r.Context(), bucket, prefix, keyMarker, maxKeys)
+	if err != nil {
+		return err
+	}
+
+	result := listVersionsResult{
+		Xmlns:         s3Namespace,
+		Name:          bucket,
+		Prefix:        prefix,
+		KeyMarker:     keyMarker,
+		NextKeyMarker: info.NextKeyMarker,
+		MaxKeys:       maxKeys,
+		IsTruncated:   info.IsTruncated,
+	}
+	for _, version := range info.Versions {
+		entry := versionEntry{
+			XMLName:      xml.Name{Local: "Version"},
+			Key:          version.Name,
+			VersionID:    version.VersionID,
+			IsLatest:     version.IsLatest,
+			LastModified: version.ModTime.UTC().Format(time.RFC3339),
+		}
+		if version.DeleteMarker {
+			entry.XMLName.Local = "DeleteMarker"
+		} else {
+			size := version.Size
+			entry.ETag = quoteETag(version.ETag)
+			entry.Size = &size
+			entry.StorageClass = "STANDARD"
+		}
+		result.Versions = append(result.Versions, entry)
+	}
+	return writeXML(w, http.StatusOK, result)
+}
+
+// versioningConfiguration is the versioning status of a bucket
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
+// getVersioning serves GetBucketVersioning. Suspended versioning can't be
+// told apart from versioning never enabled, so neither has a status.
+func (s *s3Router) getVersioning(w http.ResponseWriter, r *http.Request, bucket, _ string) error {
+	enabled, err := s.layer.GetBucketVersioning(r.Context(), bucket)
+	if err != nil {
+		return err
+	}
+	config := versioningConfiguration{Xmlns: s3Namespace}
+	if enabled {
+		config.Status = "Enabled"
+	}
+	return writeXML(w, http.StatusOK, config)
+}
+
+// putVersioning serves PutBucketVersioning
+func (s *s3Router) putVersioning(w http.ResponseWriter, r *http.Request, bucket, _ string) error {
+	var config versioningConfiguration
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSignedBody))
+	if err != nil {
+		return err
+	}
+	if err = xml.Unmarshal(body, &config); err != nil {
+		return apiError{http.StatusBadRequest, "MalformedXML", err.Error()}
+	}
+	if config.Status != "Enabled" && config.Status != "Suspended" {
+		return apiError{http.StatusBadRequest, "MalformedXML",
+			fmt.Sprintf("invalid versioning status %q", config.Status)}
+	}
+
+	err = s.layer.SetBucketVersioning(r.Context(), bucket, config.Status == "Enabled")
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// lifecycleConfiguration is the lifecycle rules of a bucket
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+// lifecycleRule is a lifecycle rule. The prefix is either in the filter or,
+// in the older form of the rules, in the rule itself.
+type lifecycleRule struct {
+	ID     string           `xml:"ID,omitempty"`
+	Prefix *string          `xml:"Prefix"`
+	Filter *lifecycleFilter `xml:"Filter"`
+	Status string           `xml:"Status"`
+
+	Expiration                     *lifecycleExpiration `xml:"Expiration"`
+	AbortIncompleteMultipartUpload *lifecycleAbort      `xml:"AbortIncompleteMultipartUpload"`
+
+	// the actions and filters that aren't supported
+	NoncurrentVersionExpiration *struct{} `xml:"NoncurrentVersionExpiration"`
+	Transition                  *struct{} `xml:"Transition"`
+}
+
+// lifecycleExpiration is the age in days of the objects a rule deletes
+type lifecycleExpiration struct {
+	Days int `xml:"Days"`
+}
+
+// lifecycleAbort is the age in days of the uploads a rule aborts
+type lifecycleAbort struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+// lifecycleFilter selects the objects a rule applies to
+type lifecycleFilter struct {
+	Prefix string    `xml:"Prefix"`
+	Tag    *struct{} `xml:"Tag"`
+	And    *struct{} `xml:"And"`
+}
+
+// getLifecycle serves GetBucketLifecycleConfiguration
+func (s *s3Router) getLifecycle(w http.ResponseWriter, r *http.Request, bucket, _ string) error {
+	rules, err := s.layer.GetBucketLifecycle(r.Context(), bucket)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return apiError{http.StatusNotFound, "NoSuchLifecycleConfiguration",
+			"the bucket has no lifecycle rules"}
+	}
+
+	config := lifecycleConfiguration{Xmlns: s3Namespace}
+	for _, rule := range rules {
+		xmlRule := lifecycleRule{
+			ID:     rule.ID,
+			Filter: &lifecycleFilter{Prefix: rule.Prefix},
+			Status: "Enabled",
+		}
+		if rule.ExpirationDays > 0 {
+			xmlRule.Expiration = &lifecycleExpiration{Days: rule.ExpirationDays}
+		}
+		if rule.AbortIncompleteUploadDays > 0 {
+			xmlRule.AbortIncompleteMultipartUpload = &lifecycleAbort{
+				DaysAfterInitiation: rule.AbortIncompleteUploadDays,
+			}
+		}
+		config.Rules = append(config.Rules, xmlRule)
+	}
+	return writeXML(w, http.StatusOK, config)
+}
+
+// putLifecycle serves PutBucketLifecycleConfiguration. The rules expire the
+// previous versions of the objects as long after they were replaced as the
+// objects after their creation.
+func (s *s3Router) putLifecycle(w http.ResponseWriter, r *http.Request, bucket, _ string) error {
+	var config lifecycleConfiguration
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSignedBody))
+	if err != nil {
+		return err
+	}
+	if err = xml.Unmarshal(body, &config); err != nil {
+		return apiError{http.StatusBadRequest, "MalformedXML", err.Error()}
+	}
+
+	var rules []lifecycle.Rule
+	for _, xmlRule := range config.Rules {
+		unsupported := func(what string) error {
+			return apiError{http.StatusNotImplemented, "NotImplemented",
+				fmt.Sprintf("rule %q: %s isn't supported", xmlRule.ID, what)}
+		}
+		switch {
+		case xmlRule.Status != "Enabled":
+			return unsupported("a rule not enabled")
+		case xmlRule.NoncurrentVersionExpiration != nil:
+			return unsupported("NoncurrentVersionExpiration")
+		case xmlRule.Transition != nil:
+			return unsupported("Transition")
+		case xmlRule.Filter != nil && (xmlRule.Filter.Tag != nil || xmlRule.Filter.And != nil):
+			return unsupported("filtering by tag")
+		}
+
+		rule := lifecycle.Rule{ID: xmlRule.ID}
+		if xmlRule.Filter != nil {
+			rule.Prefix = xmlRule.Filter.Prefix
+		} else if xmlRule.Prefix != nil {
+			rule.Prefix = *xmlRule.Prefix
+		}
+		if xmlRule.Expiration != nil {
+			rule.ExpirationDays = xmlRule.Expiration.Days
+		}
+		if xmlRule.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteUploadDays = xmlRule.AbortIncompleteMultipartUpload.DaysAfterInitiation
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return apiError{http.StatusBadRequest, "MalformedXML", "no rules"}
+	}
+	if err = lifecycle.Validate(rules); err != nil {
+		return apiError{http.StatusBadRequest, "InvalidArgument", err.Error()}
+	}
+
+	if err = s.layer.PutBucketLifecycle(r.Context(), bucket, rules); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// deleteLifecycle serves DeleteBucketLifecycle
+func (s *s3Router) deleteLifecycle(w http.ResponseWriter, r *http.Request, bucket, _ string) error {
+	if err := s.layer.DeleteBucketLifecycle(r.Context(), bucket); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// getVersion serves GetObject and HeadObject with a version ID
+func (s *s3Router) getVersion(w http.ResponseWriter, r *http.Request, bucket, object string) error {
+	version := r.URL.Query().Get("versionId")
+	info, err := s.layer.GetObjectVersionInfo(r.Context(), bucket, object, version)
+	if err != nil {
+		return err
+	}
+
+	header := w.Header()
+	header.Set("x-amz-version-id", info.VersionID)
+	if info.DeleteMarker {
+		header.Set("x-amz-delete-marker", "true")
+		return apiError{http.StatusMethodNotAllowed, "MethodNotAllowed",
+			"the version is a delete marker"}
+	}
+	start, length, err := parseRange(r.Header.Get("Range"), info.Size)
+	if err != nil {
+		return err
+	}
+
+	header.Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	header.Set("ETag", quoteETag(info.ETag))
+	header.Set("Accept-Ranges", "bytes")
+	if info.ContentType != "" {
+		header.Set("Content-Type", info.ContentType)
+	}
+	for key, value := range info.UserDefined {
+		if strings.HasPrefix(strings.ToLower(key), "x-amz-meta-") {
+			header.Set(key, value)
+		}
+	}
+
+	header.Set("Content-Length", strconv.FormatInt(length, 10))
+	status := http.StatusOK
+	if length != info.Size {
+		status = http.StatusPartialContent
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d",
+			start, start+length-1, info.Size))
+	}
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead || length == 0 {
+		return nil
+	}
+
+	err = s.layer.GetObjectVersion(r.Context(), bucket, object, version, start, length, w)
+	if err != nil {
+		// the status is sent already, the client sees a short body
+		zap.S().Errorf("getting %s/%s version %s: %v", bucket, object, version, err)
+	}
+	return nil
+}
+
+// deleteVersion serves DeleteObject with a version ID
+func (s *s3Router) deleteVersion(w http.ResponseWriter, r *http.Request, bucket, object string) error {
+	version := r.URL.Query().Get("versionId")
+	err := s.layer.DeleteObjectVersion(r.Context(), bucket, object, version)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("x-amz-version-id", version)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// parseRange returns the start and the length of the bytes of an object of
+// size selected by the Range header value spec. Without a range the whole
+// object is selected.
+func parseRange(spec string, size int64) (start, length int64, err error) {
+	if spec == "" {
+		return 0, size, nil
+	}
+	invalid := apiError{http.StatusRequestedRangeNotSatisfiable, "InvalidRange",
+		fmt.Sprintf("invalid range %q for %d bytes", spec, size)}
+
+	bounds := strings.SplitN(strings.TrimPrefix(spec, "bytes="), "-", 2)
+	if !strings.HasPrefix(spec, "bytes=") || len(bounds) != 2 {
+		return 0, 0, invalid
+	}
+	end := size - 1
+	switch {
+	case bounds[0] == "":
+		// the last bytes
+		suffix, err := strconv.ParseInt(bounds[1], 10, 64)
+		if err != nil || suffix <= 0 {
+			return 0, 0, invalid
+		}
+		if suffix < size {
+			start = size - suffix
+		}
+	default:
+		start, err = strconv.ParseInt(bounds[0], 10, 64)
+		if err != nil || start < 0 || start >= size {
+			return 0, 0, invalid
+		}
+		if bounds[1] != "" {
+			end, err = strconv.ParseInt(bounds[1], 10, 64)
+			if err != nil || end < start {
+				return 0, 0, invalid
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+	}
+	return start, end - start + 1, nil
+}
+
+// splitBucketPath splits the path of a path-style request into the bucket
+// and the object
+func splitBucketPath(path string) (bucket, object string) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// quoteETag returns the ETag header value of an object checksum
+func quoteETag(etag string) string {
+	if etag == "" {
+		return ""
+	}
+	return `"` + etag + `"`
+}
+
+// apiError is an error response of the S3 API
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+// Error implements error
+func (e apiError) Error() string {
+	return e.code + ": " + e.message
+}
+
+// errorResponse is the body of the S3 API error responses
+type errorResponse struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource"`
+}
+
+// writeError writes the S3 API error response of err to the request r
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var resp apiError
+	switch e := err.(type) {
+	case apiError:
+		resp = e
+	case minio.BucketNotFound:
+		resp = apiError{http.StatusNotFound, "NoSuchBucket", e.Error()}
+	case minio.ObjectNotFound:
+		resp = apiError{http.StatusNotFound, "NoSuchVersion", e.Error()}
+		if r.URL.Query().Get("versionId") == "" {
+			resp.code = "NoSuchKey"
+		}
+	default:
+		if errSignature.Has(err) {
+			resp = apiError{http.StatusForbidden, "AccessDenied", err.Error()}
+		} else {
+			resp = apiError{http.StatusInternalServerError, "InternalError", err.Error()}
+		}
+	}
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(resp.status)
+		return
+	}
+	_ = writeXML(w, resp.status, errorResponse{
+		Code:     resp.code,
+		Message:  resp.message,
+		Resource: r.URL.Path,
+	})
+}
+
+// writeXML writes the response with status and the XML encoding of v. It
+// only fails before anything is written.
+func writeXML(w http.ResponseWriter, status int, v interface{}) error {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	// the status is sent, the client sees a short body if the write fails
+	_, _ = w.Write(append([]byte(xml.Header), body...))
+	return nil
+}
@@ -0,0 +1,363 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storage/lifecycle"
+)
+
+var routerTestTime = time.Date(2018, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeLayer is a routedLayer with the versions and the lifecycle rules of
+// a single bucket
+type fakeLayer struct {
+	versioning bool
+	rules      []lifecycle.Rule
+	versions   []ObjectVersionInfo
+	data       map[string]string
+	deleted    []string
+	listed     []string
+}
+
+func (l *fakeLayer) checkBucket(bucket string) error {
+	if bucket != "bucket" {
+		return minio.BucketNotFound{Bucket: bucket}
+	}
+	return nil
+}
+
+func (l *fakeLayer) GetBucketVersioning(ctx context.Context, bucket string) (bool, error) {
+	return l.versioning, l.checkBucket(bucket)
+}
+
+func (l *fakeLayer) SetBucketVersioning(ctx context.Context, bucket string, enabled bool) error {
+	if err := l.checkBucket(bucket); err != nil {
+		return err
+	}
+	l.versioning = enabled
+	return nil
+}
+
+func (l *fakeLayer) GetObjectVersion(ctx context.Context, bucket, object, version string,
+	startOffset int64, length int64, writer io.Writer) error {
+	data := l.data[object+"@"+version]
+	_, err := io.WriteString(writer, data[startOffset:startOffset+length])
+	return err
+}
+
+func (l *fakeLayer) GetObjectVersionInfo(ctx context.Context, bucket, object, version string) (
+	ObjectVersionInfo, error) {
+	if err := l.checkBucket(bucket); err != nil {
+		return ObjectVersionInfo{}, err
+	}
+	for _, info := range l.versions {
+		if info.Name == object && info.VersionID == version {
+			return info, nil
+		}
+	}
+	return ObjectVersionInfo{}, minio.ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+func (l *fakeLayer) DeleteObjectVersion(ctx context.Context, bucket, object, version string) error {
+	if _, err := l.GetObjectVersionInfo(ctx, bucket, object, version); err != nil {
+		return err
+	}
+	l.deleted = append(l.deleted, object+"@"+version)
+	return nil
+}
+
+func (l *fakeLayer) GetBucketLifecycle(ctx context.Context, bucket string) ([]lifecycle.Rule, error) {
+	return l.rules, l.checkBucket(bucket)
+}
+
+func (l *fakeLayer) PutBucketLifecycle(ctx context.Context, bucket string, rules []lifecycle.Rule) error {
+	if err := l.checkBucket(bucket); err != nil {
+		return err
+	}
+	l.rules = rules
+	return nil
+}
+
+func (l *fakeLayer) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	return l.PutBucketLifecycle(ctx, bucket, nil)
+}
+
+func (l *fakeLayer) ListObjectVersions(ctx context.Context, bucket, prefix, keyMarker string,
+	maxKeys int) (ListObjectVersionsInfo, error) {
+	if err := l.checkBucket(bucket); err != nil {
+		return ListObjectVersionsInfo{}, err
+	}
+	l.listed = append(l.listed, prefix+"|"+keyMarker)
+	if maxKeys < len(l.versions) {
+		return ListObjectVersionsInfo{
+			IsTruncated:   true,
+			NextKeyMarker: l.versions[maxKeys-1].Name,
+			Versions:      l.versions[:maxKeys],
+		}, nil
+	}
+	return ListObjectVersionsInfo{Versions: l.versions}, nil
+}
+
+// newTestRouter returns a router serving a fakeLayer
+// and answering the requests passed on to minio with 299
+func newTestRouter() (*s3Router, *fakeLayer) {
+	layer := &fakeLayer{
+		versioning: true,
+		versions: []ObjectVersionInfo{
+			{
+				ObjectInfo: minio.ObjectInfo{Name: "a", Size: 6, ETag: "etag", ModTime: routerTestTime,
+					ContentType: "text/plain", UserDefined: map[string]string{"X-Amz-Meta-Color": "blue"}},
+				VersionID: "v2",
+				IsLatest:  true,
+			},
+			{ObjectInfo: minio.ObjectInfo{Name: "a", ModTime: routerTestTime}, VersionID: "v1", DeleteMarker: true},
+		},
+		data: map[string]string{"a@v2": "abcdef"},
+	}
+	router := &s3Router{
+		layer:     layer,
+		accessKey: "access",
+		secretKey: "secret",
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(299)
+		}),
+		now: func() time.Time { return routerTestTime },
+	}
+	return router, layer
+}
+
+// serve returns the response of the router to the signed request
+func serve(router http.Handler, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "http://localhost:7777"+target, strings.NewReader(body))
+	for key, values := range header {
+		r.Header[key] = values
+	}
+	signRequest(r, "access", "secret", []byte(body), routerTestTime)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestRouterListVersions(t *testing.T) {
+	router, layer := newTestRouter()
+
+	w := serve(router, "GET", "/bucket?versions&prefix=dir&key-marker=m", "", nil)
+	if !assert.Equal(t, http.StatusOK, w.Code) {
+		return
+	}
+	assert.Equal(t, []string{"dir|m"}, layer.listed)
+	assert.Equal(t, xml.Header+`<ListVersionsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`+
+		`<Name>bucket</Name><Prefix>dir</Prefix><KeyMarker>m</KeyMarker><MaxKeys>1000</MaxKeys>`+
+		`<IsTruncated>false</IsTruncated>`+
+		`<Version><Key>a</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest>`+
+		`<LastModified>2018-10-01T12:00:00Z</LastModified><ETag>&#34;etag&#34;</ETag>`+
+		`<Size>6</Size><StorageClass>STANDARD</StorageClass></Version>`+
+		`<DeleteMarker><Key>a</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest>`+
+		`<LastModified>2018-10-01T12:00:00Z</LastModified></DeleteMarker>`+
+		`</ListVersionsResult>`, w.Body.String())
+
+	w = serve(router, "GET", "/bucket?versions&max-keys=1", "", nil)
+	var result listVersionsResult
+	if assert.NoError(t, xml.Unmarshal(w.Body.Bytes(), &result)) {
+		assert.True(t, result.IsTruncated)
+		assert.Equal(t, "a", result.NextKeyMarker)
+		assert.Len(t, result.Versions, 1)
+	}
+
+	w = serve(router, "GET", "/bucket?versions&delimiter=/", "", nil)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+	w = serve(router, "GET", "/bucket?versions&max-keys=-1", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve(router, "GET", "/other?versions", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "<Code>NoSuchBucket</Code>")
+}
+
+func TestRouterVersioning(t *testing.T) {
+	router, layer := newTestRouter()
+
+	w := serve(router, "PUT", "/bucket?versioning",
+		`<VersioningConfiguration><Status>Suspended</Status></VersioningConfiguration>`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, layer.versioning)
+	w = serve(router, "GET", "/bucket?versioning", "", nil)
+	assert.Equal(t, xml.Header+`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`+
+		`</VersioningConfiguration>`, w.Body.String())
+
+	w = serve(router, "PUT", "/bucket?versioning",
+		`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`+
+			`<Status>Enabled</Status></VersioningConfiguration>`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, layer.versioning)
+	w = serve(router, "GET", "/bucket?versioning", "", nil)
+	assert.Contains(t, w.Body.String(), "<Status>Enabled</Status>")
+
+	for _, body := range []string{"<VersioningConfiguration>", "<VersioningConfiguration/>"} {
+		w = serve(router, "PUT", "/bucket?versioning", body, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		assert.Contains(t, w.Body.String(), "<Code>MalformedXML</Code>", body)
+	}
+	assert.True(t, layer.versioning)
+}
+
+func TestRouterLifecycle(t *testing.T) {
+	router, layer := newTestRouter()
+
+	w := serve(router, "GET", "/bucket?lifecycle", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "<Code>NoSuchLifecycleConfiguration</Code>")
+
+	w = serve(router, "PUT", "/bucket?lifecycle", `<LifecycleConfiguration>`+
+		`<Rule><ID>tmp</ID><Filter><Prefix>tmp/</Prefix></Filter><Status>Enabled</Status>`+
+		`<Expiration><Days>1</Days></Expiration></Rule>`+
+		`<Rule><ID>uploads</ID><Prefix></Prefix><Status>Enabled</Status>`+
+		`<AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule>`+
+		`</LifecycleConfiguration>`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []lifecycle.Rule{
+		{ID: "tmp", Prefix: "tmp/", ExpirationDays: 1},
+		{ID: "uploads", AbortIncompleteUploadDays: 7},
+	}, layer.rules)
+
+	w = serve(router, "GET", "/bucket?lifecycle", "", nil)
+	assert.Equal(t, xml.Header+`<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`+
+		`<Rule><ID>tmp</ID><Filter><Prefix>tmp/</Prefix></Filter><Status>Enabled</Status>`+
+		`<Expiration><Days>1</Days></Expiration></Rule>`+
+		`<Rule><ID>uploads</ID><Filter><Prefix></Prefix></Filter><Status>Enabled</Status>`+
+		`<AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule>`+
+		`</LifecycleConfiguration>`, w.Body.String())
+
+	for _, tt := range []struct {
+		rule string
+		code int
+	}{
+		{`<Status>Disabled</Status><Expiration><Days>1</Days></Expiration>`, http.StatusNotImplemented},
+		{`<Status>Enabled</Status><Transition><Days>1</Days></Transition>`, http.StatusNotImplemented},
+		{`<Status>Enabled</Status><Filter><Tag><Key>a</Key></Tag></Filter>`, http.StatusNotImplemented},
+		{`<Status>Enabled</Status><Expiration><Date>2019-01-01T00:00:00Z</Date></Expiration>`, http.StatusBadRequest},
+		{`<Status>Enabled</Status><Expiration><Days>-1</Days></Expiration>`, http.StatusBadRequest},
+	} {
+		w = serve(router, "PUT", "/bucket?lifecycle",
+			"<LifecycleConfiguration><Rule>"+tt.rule+"</Rule></LifecycleConfiguration>", nil)
+		assert.Equal(t, tt.code, w.Code, tt.rule)
+	}
+	assert.Len(t, layer.rules, 2)
+
+	w = serve(router, "DELETE", "/bucket?lifecycle", "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, layer.rules)
+}
+
+func TestRouterGetVersion(t *testing.T) {
+	router, _ := newTestRouter()
+
+	w := serve(router, "GET", "/bucket/a?versionId=v2", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abcdef", w.Body.String())
+	assert.Equal(t, "v2", w.Header().Get("x-amz-version-id"))
+	assert.Equal(t, `"etag"`, w.Header().Get("ETag"))
+	assert.Equal(t, "6", w.Header().Get("Content-Length"))
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, "blue", w.Header().Get("X-Amz-Meta-Color"))
+	assert.Equal(t, "Mon, 01 Oct 2018 12:00:00 GMT", w.Header().Get("Last-Modified"))
+
+	w = serve(router, "HEAD", "/bucket/a?versionId=v2", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "6", w.Header().Get("Content-Length"))
+	assert.Empty(t, w.Body.String())
+
+	for _, tt := range []struct {
+		spec, data, contentRange string
+	}{
+		{"bytes=1-2", "bc", "bytes 1-2/6"},
+		{"bytes=4-", "ef", "bytes 4-5/6"},
+		{"bytes=-3", "def", "bytes 3-5/6"},
+		{"bytes=2-100", "cdef", "bytes 2-5/6"},
+		{"bytes=0-5", "abcdef", ""},
+	} {
+		w = serve(router, "GET", "/bucket/a?versionId=v2", "", http.Header{"Range": {tt.spec}})
+		assert.Equal(t, tt.data, w.Body.String(), tt.spec)
+		assert.Equal(t, tt.contentRange, w.Header().Get("Content-Range"), tt.spec)
+		if tt.contentRange != "" {
+			assert.Equal(t, http.StatusPartialContent, w.Code, tt.spec)
+		}
+	}
+	for _, spec := range []string{"bytes=6-", "bytes=3-1", "bytes=-0", "items=0-1", "bytes=1"} {
+		w = serve(router, "GET", "/bucket/a?versionId=v2", "", http.Header{"Range": {spec}})
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code, spec)
+	}
+
+	w = serve(router, "GET", "/bucket/a?versionId=v1", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "true", w.Header().Get("x-amz-delete-marker"))
+
+	w = serve(router, "GET", "/bucket/a?versionId=v3", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "<Code>NoSuchVersion</Code>")
+}
+
+func TestRouterDeleteVersion(t *testing.T) {
+	router, layer := newTestRouter()
+
+	w := serve(router, "DELETE", "/bucket/a?versionId=v1", "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "v1", w.Header().Get("x-amz-version-id"))
+	assert.Equal(t, []string{"a@v1"}, layer.deleted)
+
+	w = serve(router, "DELETE", "/bucket/b?versionId=v1", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouterPassesOn(t *testing.T) {
+	router, _ := newTestRouter()
+
+	// the requests minio serves aren't checked by the router
+	for _, target := range []string{"/", "/bucket", "/bucket?versions", "/bucket/a", "/bucket/a?versioning"} {
+		r := httptest.NewRequest("GET", "http://localhost:7777"+target, nil)
+		if target == "/bucket?versions" {
+			r.Method = "POST"
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, 299, w.Code, target)
+	}
+}
+
+func TestRouterAuthentication(t *testing.T) {
+	router, layer := newTestRouter()
+
+	r := httptest.NewRequest("DELETE", "http://localhost:7777/bucket/a?versionId=v2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "<Code>AccessDenied</Code>")
+
+	r = httptest.NewRequest("DELETE", "http://localhost:7777/bucket/a?versionId=v2", nil)
+	signRequest(r, "access", "wrong secret", nil, routerTestTime)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// the version ID is signed
+	r = httptest.NewRequest("DELETE", "http://localhost:7777/bucket/a?versionId=v1", nil)
+	signRequest(r, "access", "secret", nil, routerTestTime)
+	r.URL.RawQuery = "versionId=v2"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	assert.Empty(t, layer.deleted)
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+const (
+	signAlgorithm   = "AWS4-HMAC-SHA256"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// maxClockSkew is the largest difference accepted between the date of a
+	// request signed in its headers and the time it's received
+	maxClockSkew = 15 * time.Minute
+	// maxSignedBody is the size of the largest body the gateway checks the
+	// hash of itself
+	maxSignedBody = 1 << 20
+)
+
+// errSignature is an error class for the requests failing the checks of
+// the S3 signature version 4
+var errSignature = errs.Class("signature error")
+
+// verifySignature checks that r is signed with the S3 signature version 4
+// in its Authorization header or in its query string, with accessKey and
+// secretKey, and that the signature is valid at now. The body of r is
+// read to check its hash, if it's signed, and replaced.
+func verifySignature(r *http.Request, accessKey, secretKey string, now time.Time) error {
+	query := r.URL.Query()
+
+	var credential, signedHeaders, signature, date, payloadHash string
+	expires := maxClockSkew
+	switch {
+	case r.Header.Get("Authorization") != "":
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, signAlgorithm+" ") {
+			return errSignature.New("unsupported authorization")
+		}
+		fields := map[string]string{}
+		for _, field := range strings.Split(strings.TrimPrefix(auth, signAlgorithm+" "), ",") {
+			kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+			if len(kv) == 2 {
+				fields[kv[0]] = kv[1]
+			}
+		}
+		credential = fields["Credential"]
+		signedHeaders = fields["SignedHeaders"]
+		signature = fields["Signature"]
+		date = r.Header.Get("X-Amz-Date")
+		payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+	case query.Get("X-Amz-Algorithm") == signAlgorithm:
+		credential = query.Get("X-Amz-Credential")
+		signedHeaders = query.Get("X-Amz-SignedHeaders")
+		signature = query.Get("X-Amz-Signature")
+		date = query.Get("X-Amz-Date")
+		payloadHash = unsignedPayload
+		seconds, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+		if err != nil || seconds < 1 || time.Duration(seconds)*time.Second > MaxPresignExpiry {
+			return errSignature.New("invalid expiry %q", query.Get("X-Amz-Expires"))
+		}
+		expires = time.Duration(seconds) * time.Second
+	default:
+		return errSignature.New("request not signed")
+	}
+
+	signedAt, err := time.Parse(amzDate, date)
+	if err != nil {
+		return errSignature.New("invalid date %q", date)
+	}
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 || scope[0] != accessKey || scope[1] != date[:8] ||
+		scope[3] != "s3" || scope[4] != "aws4_request" {
+		return errSignature.New("invalid credential")
+	}
+	if signedAt.After(now.Add(maxClockSkew)) || now.After(signedAt.Add(expires)) {
+		return errSignature.New("signature expired or not yet valid")
+	}
+
+	headers, err := canonicalHeaders(r, signedHeaders)
+	if err != nil {
+		return err
+	}
+	if err = checkPayload(r, payloadHash); err != nil {
+		return err
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		uriEncode(r.URL.Path, false),
+		canonicalQuery(query),
+		headers,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	expected := sign(secretKey, date, scope[2], canonicalRequest)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errSignature.New("signature mismatch")
+	}
+	return nil
+}
+
+// canonicalHeaders returns the headers of r named by signedHeaders as they
+// appear in the canonical request. The host must be signed.
+func canonicalHeaders(r *http.Request, signedHeaders string) (string, error) {
+	var b strings.Builder
+	host := false
+	for _, name := range strings.Split(signedHeaders, ";") {
+		values := r.Header[http.CanonicalHeaderKey(name)]
+		if name == "host" {
+			values, host = []string{r.Host}, true
+		}
+		for i, value := range values {
+			values[i] = strings.Join(strings.Fields(value), " ")
+		}
+		b.WriteString(name + ":" + strings.Join(values, ",") + "\n")
+	}
+	if !host {
+		return "", errSignature.New("host not signed")
+	}
+	return b.String(), nil
+}
+
+// checkPayload checks that the body of r has the signed hash, unless the
+// payload isn't signed, and replaces the body read
+func checkPayload(r *http.Request, payloadHash string) error {
+	switch {
+	case payloadHash == unsignedPayload:
+		return nil
+	case payloadHash == "":
+		return errSignature.New("missing payload hash")
+	case strings.HasPrefix(payloadHash, "STREAMING-"):
+		return errSignature.New("unsupported payload signature %s", payloadHash)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(io.LimitReader(r.Body, maxSignedBody+1))
+		if err != nil {
+			return err
+		}
+		if len(body) > maxSignedBody {
+			return errSignature.New("body larger than %d bytes", maxSignedBody)
+		}
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.Sum256(body)
+	if hex.EncodeToString(hash[:]) != payloadHash {
+		return errSignature.New("payload hash mismatch")
+	}
+	return nil
+}
+
+// canonicalQuery returns the query string of the canonical request of the
+// query values, without the signature
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if key != "X-Amz-Signature" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var params []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// credentialScope returns the scope of the signatures made on day, of the
+// form 20060102, in region
+func credentialScope(day, region string) string {
+	return strings.Join([]string{day, region, "s3", "aws4_request"}, "/")
+}
+
+// sign returns the signature of canonicalRequest made at date, of the form
+// 20060102T150405Z, in region with secretKey
+func sign(secretKey, date, region, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		date,
+		credentialScope(date[:8], region),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date[:8], region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode percent-encodes s as the S3 signatures expect: everything but
+// the unreserved characters, and the slashes if encodeSlash
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
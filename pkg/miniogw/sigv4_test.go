@@ -0,0 +1,114 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signRequest signs r in its Authorization header as an S3 client does, as
+// of now
+func signRequest(r *http.Request, accessKey, secretKey string, body []byte, now time.Time) {
+	date := now.UTC().Format(amzDate)
+	hash := sha256.Sum256(body)
+	r.Header.Set("X-Amz-Date", date)
+	r.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(hash[:]))
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	headers, _ := canonicalHeaders(r, signedHeaders)
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		uriEncode(r.URL.Path, false),
+		canonicalQuery(r.URL.Query()),
+		headers,
+		signedHeaders,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+	r.Header.Set("Authorization", signAlgorithm+
+		" Credential="+accessKey+"/"+credentialScope(date[:8], "eu-west-1")+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+sign(secretKey, date, "eu-west-1", canonicalRequest))
+}
+
+func TestVerifyPresignedURL(t *testing.T) {
+	now := time.Date(2018, time.October, 1, 12, 0, 0, 0, time.UTC)
+	u, err := presignURL("http://localhost:7777", "access", "secret", "GET",
+		"bucket", "dir/a file+1.txt", now, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for i, tt := range []struct {
+		url       string
+		secretKey string
+		now       time.Time
+		valid     bool
+	}{
+		{u, "secret", now, true},
+		{u, "secret", now.Add(59 * time.Minute), true},
+		{u, "secret", now.Add(61 * time.Minute), false},
+		{u, "secret", now.Add(-time.Hour), false},
+		{u, "other secret", now, false},
+		{strings.Replace(u, "a%20file", "b%20file", 1), "secret", now, false},
+		{strings.Replace(u, "X-Amz-Expires=3600", "X-Amz-Expires=7200", 1), "secret", now, false},
+		{"http://localhost:7777/bucket/dir/a%20file%2B1.txt", "secret", now, false},
+	} {
+		r := httptest.NewRequest("GET", tt.url, nil)
+		err := verifySignature(r, "access", tt.secretKey, tt.now)
+		if tt.valid {
+			assert.NoError(t, err, i)
+		} else {
+			assert.True(t, errSignature.Has(err), i)
+		}
+	}
+
+	// the method is signed
+	r := httptest.NewRequest("PUT", u, nil)
+	assert.True(t, errSignature.Has(verifySignature(r, "access", "secret", now)))
+}
+
+func TestVerifySignedHeaders(t *testing.T) {
+	now := time.Date(2018, time.October, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte("<VersioningConfiguration/>")
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest("PUT", "http://localhost:7777/bucket?versioning", strings.NewReader(string(body)))
+		signRequest(r, "access", "secret", body, now)
+		return r
+	}
+
+	r := newRequest()
+	if assert.NoError(t, verifySignature(r, "access", "secret", now.Add(10*time.Minute))) {
+		// the body read is replaced
+		read, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, read)
+	}
+
+	r = newRequest()
+	assert.True(t, errSignature.Has(verifySignature(r, "other", "secret", now)))
+
+	r = newRequest()
+	assert.True(t, errSignature.Has(verifySignature(r, "access", "secret", now.Add(20*time.Minute))))
+
+	r = newRequest()
+	r.Body = ioutil.NopCloser(strings.NewReader("<VersioningConfiguration>"))
+	assert.True(t, errSignature.Has(verifySignature(r, "access", "secret", now)))
+
+	r = newRequest()
+	r.Header.Set("X-Amz-Date", now.Add(time.Second).Format(amzDate))
+	assert.True(t, errSignature.Has(verifySignature(r, "access", "secret", now)))
+
+	r = httptest.NewRequest("GET", "http://localhost:7777/bucket?versioning", nil)
+	assert.True(t, errSignature.Has(verifySignature(r, "access", "secret", now)))
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"io"
+
+	minio "github.com/minio/minio/cmd"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+// ObjectVersionInfo is a single version of an object, as returned by
+// ListObjectVersions
+type ObjectVersionInfo struct {
+	minio.ObjectInfo
+	VersionID    string
+	IsLatest     bool
+	DeleteMarker bool
+}
+
+// ListObjectVersionsInfo is the result of ListObjectVersions
+type ListObjectVersionsInfo struct {
+	IsTruncated   bool
+	NextKeyMarker string
+	Versions      []ObjectVersionInfo
+}
+
+// GetBucketVersioning returns whether versioning is enabled for the bucket
+func (s *storjObjects) GetBucketVersioning(ctx context.Context, bucket string) (
+	enabled bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+	m, err := s.storj.bs.Get(ctx, bucket)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return false, minio.BucketNotFound{Bucket: bucket}
+		}
+		return false, err
+	}
+	return m.Versioning, nil
+}
+
+// SetBucketVersioning enables or disables versioning for the bucket
+func (s *storjObjects) SetBucketVersioning(ctx context.Context, bucket string,
+	enabled bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	_, err = s.storj.bs.SetVersioning(ctx, bucket, enabled)
+	if err != nil && storage.ErrKeyNotFound.Has(err) {
+		return minio.BucketNotFound{Bucket: bucket}
+	}
+	return err
+}
+
+// GetObjectVersion writes the given version of the object to writer. The
+// buckets without versioning only have the null version.
+func (s *storjObjects) GetObjectVersion(ctx context.Context, bucket, object,
+	version string, startOffset int64, length int64, writer io.Writer) (
+	err error) {
+	defer mon.Task()(&ctx)(&err)
+	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	var rr ranger.Ranger
+	if vs, ok := o.(objects.VersionedStore); ok {
+		rr, _, err = vs.GetVersion(ctx, paths.New(object), version)
+	} else if isNullVersion(version) {
+		rr, _, err = o.Get(ctx, paths.New(object))
+	} else {
+		err = storage.ErrKeyNotFound.New(object)
+	}
+	if err != nil {
+		return versionError(err, bucket, object)
+	}
+
+	if length == -1 {
+		length = rr.Size() - startOffset
+	}
+
+	r, err := rr.Range(ctx, startOffset, length)
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(r)
+
+	_, err = io.Copy(writer, r)
+
+	return err
+}
+
+// GetObjectVersionInfo returns the info of the given version of the object
+func (s *storjObjects) GetObjectVersionInfo(ctx context.Context, bucket,
+	object, version string) (objInfo ObjectVersionInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return objInfo, err
+	}
+
+	var m objects.Meta
+	if vs, ok := o.(objects.VersionedStore); ok {
+		m, err = vs.MetaVersion(ctx, paths.New(object), version)
+	} else if isNullVersion(version) {
+		m, err = o.Meta(ctx, paths.New(object))
+		m.VersionID = objects.NullVersion
+	} else {
+		err = storage.ErrKeyNotFound.New(object)
+	}
+	if err != nil {
+		return objInfo, versionError(err, bucket, object)
+	}
+
+	return ObjectVersionInfo{
+		ObjectInfo:   objectInfo(bucket, object, m),
+		VersionID:    m.VersionID,
+		DeleteMarker: m.DeleteMarker,
+	}, nil
+}
+
+// DeleteObjectVersion deletes the given version of the object permanently.
+// Without a version a delete marker is created in versioned buckets.
+func (s *storjObjects) DeleteObjectVersion(ctx context.Context, bucket,
+	object, version string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	if vs, ok := o.(objects.VersionedStore); ok {
+		err = vs.DeleteVersion(ctx, paths.New(object), version)
+	} else if isNullVersion(version) {
+		err = o.Delete(ctx, paths.New(object))
+	} else {
+		err = storage.ErrKeyNotFound.New(object)
+	}
+	if err != nil {
+		return versionError(err, bucket, object)
+	}
+	return nil
+}
+
+// ListObjectVersions lists the versions of the objects with prefix and
+// names after keyMarker. The objects of buckets without versioning are
+// listed with the null version.
+func (s *storjObjects) ListObjectVersions(ctx context.Context, bucket, prefix,
+	keyMarker string, maxKeys int) (result ListObjectVersionsInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return result, err
+	}
+
+	vs, ok := o.(objects.VersionedStore)
+	if !ok {
+		items, more, err := o.List(ctx, paths.New(prefix), paths.New(keyMarker),
+			nil, true, maxKeys, meta.All)
+		if err != nil {
+			return result, err
+		}
+		for _, item := range items {
+			name := item.Path.Prepend(prefix).String()
+			result.Versions = append(result.Versions, ObjectVersionInfo{
+				ObjectInfo: objectInfo(bucket, name, item.Meta),
+				VersionID:  objects.NullVersion,
+				IsLatest:   true,
+			})
+		}
+		result.IsTruncated = more
+		if more && len(items) > 0 {
+			result.NextKeyMarker = items[len(items)-1].Path.String()
+		}
+		return result, nil
+	}
+
+	versions, more, err := vs.ListVersions(ctx, paths.New(prefix),
+		paths.New(keyMarker), maxKeys)
+	if err != nil {
+		return result, err
+	}
+	for _, version := range versions {
+		result.Versions = append(result.Versions, ObjectVersionInfo{
+			ObjectInfo:   objectInfo(bucket, version.Path.String(), version.Meta),
+			VersionID:    version.VersionID,
+			IsLatest:     version.IsLatest,
+			DeleteMarker: version.DeleteMarker,
+		})
+	}
+	result.IsTruncated = more
+	if more && len(versions) > 0 {
+		last := versions[len(versions)-1].Path
+		// the marker is relative to the prefix
+		result.NextKeyMarker = paths.New(last[len(paths.New(prefix)):]...).String()
+	}
+	return result, nil
+}
+
+// objectInfo returns the info of the object with metadata m
+func objectInfo(bucket, object string, m objects.Meta) minio.ObjectInfo {
+	return minio.ObjectInfo{
+		Name:        object,
+		Bucket:      bucket,
+		ModTime:     m.Modified,
+		Size:        m.Size,
+		ETag:        m.Checksum,
+		ContentType: m.ContentType,
+		UserDefined: m.UserDefined,
+	}
+}
+
+// isNullVersion tells whether version is the only version of the objects
+// in buckets without versioning
+func isNullVersion(version string) bool {
+	return version == "" || version == objects.NullVersion
+}
+
+// versionError converts the errors of missing object versions
+func versionError(err error, bucket, object string) error {
+	if storage.ErrKeyNotFound.Has(err) || objects.ErrDeleteMarker.Has(err) ||
+		objects.ErrInvalidVersion.Has(err) {
+		return minio.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return err
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/storage"
+)
+
+const testVersion = "7fffffffffffffff00000000"
+
+func TestGetObjectVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockVS := NewMockVersionedStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	for i, example := range []struct {
+		version string
+		data    string
+		err     error
+	}{
+		{testVersion, "abcdef", nil},
+		{testVersion, "", objects.ErrDeleteMarker.New(testVersion)},
+		{"invalid", "", objects.ErrInvalidVersion.New("invalid")},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+
+		mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockVS, nil)
+		mockVS.EXPECT().GetVersion(gomock.Any(), paths.New("myobject"), example.version).
+			Return(ranger.ByteRanger([]byte(example.data)), objects.Meta{}, example.err)
+
+		var buf bytes.Buffer
+		err := storjObj.GetObjectVersion(ctx, "mybucket", "myobject", example.version, 0, -1, &buf)
+		if example.err != nil {
+			assert.Equal(t, minio.ObjectNotFound{Bucket: "mybucket", Object: "myobject"}, err, errTag)
+		} else {
+			assert.NoError(t, err, errTag)
+			assert.Equal(t, example.data, buf.String(), errTag)
+		}
+	}
+}
+
+func TestGetObjectVersionInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+	mockVS := NewMockVersionedStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	m := objects.Meta{Size: 42, Checksum: "checksum"}
+	m.VersionID = testVersion
+
+	// versioned bucket
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockVS, nil)
+	mockVS.EXPECT().MetaVersion(gomock.Any(), paths.New("myobject"), testVersion).Return(m, nil)
+
+	info, err := storjObj.GetObjectVersionInfo(ctx, "mybucket", "myobject", testVersion)
+	assert.NoError(t, err)
+	assert.Equal(t, testVersion, info.VersionID)
+	assert.Equal(t, m.Size, info.Size)
+	assert.Equal(t, m.Checksum, info.ETag)
+
+	// bucket without versioning has got only the null version
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockOS, nil)
+	mockOS.EXPECT().Meta(gomock.Any(), paths.New("myobject")).Return(objects.Meta{Size: 42}, nil)
+
+	info, err = storjObj.GetObjectVersionInfo(ctx, "mybucket", "myobject", objects.NullVersion)
+	assert.NoError(t, err)
+	assert.Equal(t, objects.NullVersion, info.VersionID)
+	assert.Equal(t, int64(42), info.Size)
+
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockOS, nil)
+
+	_, err = storjObj.GetObjectVersionInfo(ctx, "mybucket", "myobject", testVersion)
+	assert.Equal(t, minio.ObjectNotFound{Bucket: "mybucket", Object: "myobject"}, err)
+}
+
+func TestDeleteObjectVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+	mockVS := NewMockVersionedStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockVS, nil)
+	mockVS.EXPECT().DeleteVersion(gomock.Any(), paths.New("myobject"), testVersion).Return(nil)
+	assert.NoError(t, storjObj.DeleteObjectVersion(ctx, "mybucket", "myobject", testVersion))
+
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockVS, nil)
+	mockVS.EXPECT().DeleteVersion(gomock.Any(), paths.New("myobject"), testVersion).
+		Return(storage.ErrKeyNotFound.New("myobject"))
+	err := storjObj.DeleteObjectVersion(ctx, "mybucket", "myobject", testVersion)
+	assert.Equal(t, minio.ObjectNotFound{Bucket: "mybucket", Object: "myobject"}, err)
+
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockOS, nil)
+	mockOS.EXPECT().Delete(gomock.Any(), paths.New("myobject")).Return(nil)
+	assert.NoError(t, storjObj.DeleteObjectVersion(ctx, "mybucket", "myobject", objects.NullVersion))
+}
+
+func TestListObjectVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+	mockVS := NewMockVersionedStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	versions := []objects.Version{
+		{Path: paths.New("dir/a"), VersionID: testVersion, IsLatest: true, DeleteMarker: true},
+		{Path: paths.New("dir/a"), VersionID: "7ffffffffffffffe00000000"},
+	}
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockVS, nil)
+	mockVS.EXPECT().ListVersions(gomock.Any(), paths.New("dir"), paths.New(""), 1).Return(versions, true, nil)
+
+	result, err := storjObj.ListObjectVersions(ctx, "mybucket", "dir", "", 1)
+	assert.NoError(t, err)
+	assert.True(t, result.IsTruncated)
+	assert.Equal(t, "a", result.NextKeyMarker)
+	if assert.Len(t, result.Versions, 2) {
+		assert.Equal(t, "dir/a", result.Versions[0].Name)
+		assert.Equal(t, testVersion, result.Versions[0].VersionID)
+		assert.True(t, result.Versions[0].IsLatest)
+		assert.True(t, result.Versions[0].DeleteMarker)
+		assert.False(t, result.Versions[1].IsLatest)
+	}
+
+	// bucket without versioning
+	items := []objects.ListItem{{Path: paths.New("a")}, {Path: paths.New("b")}}
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockOS, nil)
+	mockOS.EXPECT().List(gomock.Any(), paths.New("dir"), paths.New(""), gomock.Any(), true, 2, gomock.Any()).
+		Return(items, false, nil)
+
+	result, err = storjObj.ListObjectVersions(ctx, "mybucket", "dir", "", 2)
+	assert.NoError(t, err)
+	assert.False(t, result.IsTruncated)
+	if assert.Len(t, result.Versions, 2) {
+		assert.Equal(t, "dir/b", result.Versions[1].Name)
+		assert.Equal(t, objects.NullVersion, result.Versions[1].VersionID)
+		assert.True(t, result.Versions[1].IsLatest)
+	}
+}
+
+func TestDeleteVersionedBucket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockVS := NewMockVersionedStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	versions := []objects.Version{{Path: paths.New("a"), VersionID: testVersion, DeleteMarker: true}}
+	mockBS.EXPECT().Get(gomock.Any(), "mybucket").Return(buckets.Meta{Versioning: true}, nil)
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "mybucket").Return(mockVS, nil)
+	mockVS.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil)
+	mockVS.EXPECT().ListVersions(gomock.Any(), gomock.Any(), gomock.Any(), 1).Return(versions, false, nil)
+
+	err := storjObj.DeleteBucket(ctx, "mybucket")
+	assert.Equal(t, minio.BucketNotEmpty{Bucket: "mybucket"}, err)
+}
+
+func TestBucketVersioning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	mockBS.EXPECT().SetVersioning(gomock.Any(), "mybucket", true).Return(buckets.Meta{Versioning: true}, nil)
+	assert.NoError(t, storjObj.SetBucketVersioning(ctx, "mybucket", true))
+
+	mockBS.EXPECT().Get(gomock.Any(), "mybucket").Return(buckets.Meta{Versioning: true}, nil)
+	enabled, err := storjObj.GetBucketVersioning(ctx, "mybucket")
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	mockBS.EXPECT().SetVersioning(gomock.Any(), "nobucket", false).
+		Return(buckets.Meta{}, storage.ErrKeyNotFound.New("nobucket"))
+	err = storjObj.SetBucketVersioning(ctx, "nobucket", false)
+	assert.Equal(t, minio.BucketNotFound{Bucket: "nobucket"}, err)
+}
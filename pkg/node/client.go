@@ -8,8 +8,6 @@ import (
 
 	"github.com/zeebo/errs"
 
-	"storj.io/storj/pkg/pool"
-	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/transport"
 	proto "storj.io/storj/protos/overlay"
 )
@@ -17,13 +15,12 @@ import (
 //NodeClientErr is the class for all errors pertaining to node client operations
 var NodeClientErr = errs.Class("node client error")
 
-// NewNodeClient instantiates a node client
-func NewNodeClient(identity *provider.FullIdentity, self proto.Node) (Client, error) {
-	client := transport.NewClient(identity)
+// NewNodeClient instantiates a node client dialing nodes with tc, usually
+// the shared pool.ConnectionManager
+func NewNodeClient(tc transport.Client, self proto.Node) (Client, error) {
 	return &Node{
-		self:  self,
-		tc:    client,
-		cache: pool.NewConnectionPool(),
+		self: self,
+		tc:   tc,
 	}, nil
 }
 
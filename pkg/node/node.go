@@ -6,36 +6,24 @@ package node
 import (
 	"context"
 
-	"google.golang.org/grpc"
-	"storj.io/storj/pkg/pool"
 	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
 	proto "storj.io/storj/protos/overlay"
 )
 
 // Node is the storj definition for a node in the network
 type Node struct {
-	self  proto.Node
-	tc    transport.Client
-	cache pool.Pool
+	self proto.Node
+	tc   transport.Client
 }
 
 // Lookup queries nodes looking for a particular node in the network
-func (n *Node) Lookup(ctx context.Context, to proto.Node, find proto.Node) ([]*proto.Node, error) {
-	v, err := n.cache.Get(ctx, to.GetId())
+func (n *Node) Lookup(ctx context.Context, to proto.Node, find proto.Node) (_ []*proto.Node, err error) {
+	conn, err := n.tc.DialNode(ctx, &to)
 	if err != nil {
 		return nil, err
 	}
-
-	var conn *grpc.ClientConn
-	if c, ok := v.(*grpc.ClientConn); ok {
-		conn = c
-	} else {
-		c, err := n.tc.DialNode(ctx, &to)
-		if err != nil {
-			return nil, err
-		}
-		conn = c
-	}
+	defer func() { err = utils.CombineErrors(err, transport.Release(n.tc, conn)) }()
 
 	c := proto.NewNodesClient(conn)
 	resp, err := c.Query(ctx, &proto.QueryRequest{Sender: &n.self, Target: &find})
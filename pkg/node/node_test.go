@@ -12,7 +12,9 @@ import (
 	"google.golang.org/grpc"
 
 	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/pool"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
 	proto "storj.io/storj/protos/overlay"
 )
 
@@ -37,10 +39,10 @@ func TestLookup(t *testing.T) {
 		lis, err := net.Listen("tcp", "127.0.0.1:0")
 		assert.NoError(t, err)
 
-		v.to = proto.Node{Id: NewNodeID(t), Address: &proto.NodeAddress{Address: lis.Addr().String()}}
-
-		srv, mock, err := newTestServer(ctx)
+		srv, mock, serverID, err := newTestServer(ctx)
 		assert.NoError(t, err)
+
+		v.to = proto.Node{Id: serverID, Address: &proto.NodeAddress{Address: lis.Addr().String()}}
 		go func() { assert.NoError(t, srv.Serve(lis)) }()
 		defer srv.Stop()
 
@@ -49,7 +51,10 @@ func TestLookup(t *testing.T) {
 		identity, err := ca.NewIdentity()
 		assert.NoError(t, err)
 
-		nc, err := NewNodeClient(identity, v.self)
+		conns := pool.NewConnectionManager(transport.NewClient(identity, 0), pool.Config{})
+		defer func() { assert.NoError(t, conns.Close()) }()
+
+		nc, err := NewNodeClient(conns, v.self)
 		assert.NoError(t, err)
 
 		_, err = nc.Lookup(ctx, v.to, v.find)
@@ -58,18 +63,18 @@ func TestLookup(t *testing.T) {
 	}
 }
 
-func newTestServer(ctx context.Context) (*grpc.Server, *mockNodeServer, error) {
+func newTestServer(ctx context.Context) (*grpc.Server, *mockNodeServer, string, error) {
 	ca, err := provider.NewCA(ctx, 12, 4)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	identity, err := ca.NewIdentity()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	identOpt, err := identity.ServerOption()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 
 	grpcServer := grpc.NewServer(identOpt)
@@ -77,7 +82,7 @@ func newTestServer(ctx context.Context) (*grpc.Server, *mockNodeServer, error) {
 
 	proto.RegisterNodesServer(grpcServer, mn)
 
-	return grpcServer, mn, nil
+	return grpcServer, mn, identity.ID.String(), nil
 
 }
 
@@ -18,6 +18,7 @@ import (
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/boltdb"
 	"storj.io/storj/storage/redis"
+	"storj.io/storj/storage/sqlkv"
 	"storj.io/storj/storage/storelogger"
 )
 
@@ -64,9 +65,23 @@ func NewBoltOverlayCache(dbPath string, DHT dht.DHT) (*Cache, error) {
 	}, nil
 }
 
+// NewSQLOverlayCache returns a pointer to a new Cache instance with an
+// initialized connection to the PostgreSQL or SQLite db at databaseURL.
+func NewSQLOverlayCache(databaseURL string, DHT dht.DHT) (*Cache, error) {
+	sc, err := sqlkv.OpenURL(databaseURL, OverlayBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{
+		DB:  storelogger.New(zap.L(), sc),
+		DHT: DHT,
+	}, nil
+}
+
 // Get looks up the provided nodeID from the overlay cache
 func (o *Cache) Get(ctx context.Context, key string) (*overlay.Node, error) {
-	b, err := o.DB.Get([]byte(key))
+	b, err := o.DB.Get(ctx, []byte(key))
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +107,7 @@ func (o *Cache) GetAll(ctx context.Context, keys []string) ([]*overlay.Node, err
 	for _, v := range keys {
 		ks = append(ks, storage.Key(v))
 	}
-	vs, err := o.DB.GetAll(ks)
+	vs, err := o.DB.GetAll(ctx, ks)
 	if err != nil {
 		return nil, err
 	}
@@ -113,13 +128,13 @@ func (o *Cache) GetAll(ctx context.Context, keys []string) ([]*overlay.Node, err
 }
 
 // Put adds a nodeID to the redis cache with a binary representation of proto defined Node
-func (o *Cache) Put(nodeID string, value overlay.Node) error {
+func (o *Cache) Put(ctx context.Context, nodeID string, value overlay.Node) error {
 	data, err := proto.Marshal(&value)
 	if err != nil {
 		return err
 	}
 
-	return o.DB.Put(kademlia.StringToNodeID(nodeID).Bytes(), data)
+	return o.DB.Put(ctx, kademlia.StringToNodeID(nodeID).Bytes(), data)
 }
 
 // Bootstrap walks the initialized network and populates the cache
@@ -140,7 +155,7 @@ func (o *Cache) Bootstrap(ctx context.Context) error {
 			return err
 		}
 
-		if err := o.DB.Put(kademlia.StringToNodeID(found.Id).Bytes(), node); err != nil {
+		if err := o.DB.Put(ctx, kademlia.StringToNodeID(found.Id).Bytes(), node); err != nil {
 			return err
 		}
 	}
@@ -169,7 +184,7 @@ func (o *Cache) Refresh(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		err = o.DB.Put([]byte(pinged.Id), []byte(pinged.Address.Address))
+		err = o.DB.Put(ctx, []byte(pinged.Id), []byte(pinged.Address.Address))
 		if err != nil {
 			return err
 		}
@@ -187,7 +202,7 @@ func (o *Cache) Refresh(ctx context.Context) error {
 			zap.Error(ErrNodeNotFound)
 			return err
 		}
-		err = o.DB.Put([]byte(pinged.Id), []byte(pinged.Address.Address))
+		err = o.DB.Put(ctx, []byte(pinged.Id), []byte(pinged.Address.Address))
 		if err != nil {
 			return err
 		}
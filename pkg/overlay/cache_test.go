@@ -5,6 +5,7 @@ package overlay
 
 import (
 	"context"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -383,7 +384,7 @@ func redisTestClient(t *testing.T, addr string, items []storage.ListItem) storag
 		t.Fatal(err)
 	}
 
-	if err := storage.PutAll(client, items...); err != nil {
+	if err := storage.PutAll(ctx, client, items...); err != nil {
 		t.Fatal(err)
 	}
 
@@ -402,7 +403,7 @@ func boltTestClient(t *testing.T, items []storage.ListItem) (_ storage.KeyValueS
 		assert.NoError(t, os.Remove(boltPath))
 	}
 
-	if err := storage.PutAll(client, items...); err != nil {
+	if err := storage.PutAll(ctx, client, items...); err != nil {
 		t.Fatal(err)
 	}
 
@@ -467,10 +468,10 @@ func TestRedisPut(t *testing.T) {
 			db := redisTestClient(t, redisAddr, c.data)
 			oc := Cache{DB: db}
 
-			err := oc.Put(c.key, c.value)
+			err := oc.Put(ctx, c.key, c.value)
 			assertErrClass(t, c.expectedErrors[_redis], err)
 
-			v, err := db.Get([]byte(c.key))
+			v, err := db.Get(ctx, []byte(c.key))
 			assert.NoError(t, err)
 
 			na := &overlay.Node{}
@@ -515,10 +516,10 @@ func TestBoltPut(t *testing.T) {
 
 			oc := Cache{DB: db}
 
-			err := oc.Put(c.key, c.value)
+			err := oc.Put(ctx, c.key, c.value)
 			assertErrClass(t, c.expectedErrors[_redis], err)
 
-			v, err := db.Get([]byte(c.key))
+			v, err := db.Get(ctx, []byte(c.key))
 			assert.NoError(t, err)
 			na := &overlay.Node{}
 
@@ -533,7 +534,7 @@ func TestMockGet(t *testing.T) {
 		t.Run(c.testID, func(t *testing.T) {
 
 			db := teststore.New()
-			if err := storage.PutAll(db, c.data...); err != nil {
+			if err := storage.PutAll(ctx, db, c.data...); err != nil {
 				t.Fatal(err)
 			}
 			oc := Cache{DB: db}
@@ -560,7 +561,7 @@ func TestMockGetAll(t *testing.T) {
 		t.Run(c.testID, func(t *testing.T) {
 
 			db := teststore.New()
-			if err := storage.PutAll(db, c.data...); err != nil {
+			if err := storage.PutAll(ctx, db, c.data...); err != nil {
 				t.Fatal(err)
 			}
 			oc := Cache{DB: db}
@@ -579,18 +580,18 @@ func TestMockPut(t *testing.T) {
 	for _, c := range putCases {
 		t.Run(c.testID, func(t *testing.T) {
 			db := teststore.New()
-			if err := storage.PutAll(db, c.data...); err != nil {
+			if err := storage.PutAll(ctx, db, c.data...); err != nil {
 				t.Fatal(err)
 			}
 			db.CallCount.Put = 0
 
 			oc := Cache{DB: db}
 
-			err := oc.Put(c.key, c.value)
+			err := oc.Put(ctx, c.key, c.value)
 			assertErrClass(t, c.expectedErrors[mock], err)
 			assert.Equal(t, c.expectedTimesCalled, db.CallCount.Put)
 
-			v, err := db.Get(storage.Key(c.key))
+			v, err := db.Get(ctx, storage.Key(c.key))
 			assert.NoError(t, err)
 
 			na := &overlay.Node{}
@@ -608,7 +609,7 @@ func TestRefresh(t *testing.T) {
 			ctx := context.Background()
 
 			db := teststore.New()
-			if err := storage.PutAll(db, c.data...); err != nil {
+			if err := storage.PutAll(ctx, db, c.data...); err != nil {
 				t.Fatal(err)
 			}
 
@@ -664,3 +665,25 @@ func TestNewRedisOverlayCache(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSQLOverlayCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storj-overlay")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	cache, err := NewSQLOverlayCache("sqlite3://"+filepath.Join(dir, "overlay.db"), nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, cache)
+
+	node := overlay.Node{Id: "foo", Address: &overlay.NodeAddress{Transport: overlay.NodeTransport_TCP, Address: "127.0.0.1:9090"}}
+	assert.NoError(t, cache.Put(ctx, node.Id, node))
+
+	got, err := cache.Get(ctx, node.Id)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&node, got))
+	assert.NoError(t, cache.DB.Close())
+
+	cache, err = NewSQLOverlayCache("mysql://localhost/overlay", nil)
+	assert.Error(t, err)
+	assert.Nil(t, cache)
+}
@@ -5,7 +5,9 @@ package overlay
 
 import (
 	"context"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/zeebo/errs"
 
 	"storj.io/storj/pkg/dht"
@@ -34,6 +36,9 @@ type Client interface {
 // Overlay is the overlay concrete implementation of the client interface
 type Overlay struct {
 	client proto.OverlayClient
+	// MaxLatency, if not zero, is the maximum 90th percentile latency of
+	// the nodes chosen
+	MaxLatency time.Duration
 }
 
 // NewOverlayClient returns a new intialized Overlay Client
@@ -57,12 +62,13 @@ var _ Client = (*Overlay)(nil)
 
 // Choose implements the client.Choose interface
 func (o *Overlay) Choose(ctx context.Context, amount int, space int64) ([]*proto.Node, error) {
-	// TODO(coyle): We will also need to communicate with the reputation service here
-	resp, err := o.client.FindStorageNodes(ctx, &proto.FindStorageNodesRequest{
-		Opts: &proto.OverlayOptions{Amount: int64(amount), Restrictions: &proto.NodeRestrictions{
-			FreeDisk: space,
-		}},
-	})
+	opts := &proto.OverlayOptions{Amount: int64(amount), Restrictions: &proto.NodeRestrictions{
+		FreeDisk: space,
+	}}
+	if o.MaxLatency > 0 {
+		opts.MaxLatency = ptypes.DurationProto(o.MaxLatency)
+	}
+	resp, err := o.client.FindStorageNodes(ctx, &proto.FindStorageNodesRequest{Opts: opts})
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
@@ -7,7 +7,9 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/zeebo/errs"
 	"google.golang.org/grpc"
@@ -90,6 +92,14 @@ func TestChoose(t *testing.T) {
 		_, err = oc.Choose(ctx, v.limit, v.space)
 		assert.NoError(t, err)
 		assert.Equal(t, mock.FindStorageNodesCalled, v.expectedCalls)
+		assert.Nil(t, mock.opts.GetMaxLatency())
+
+		// the max latency is requested too if set
+		oc.MaxLatency = 500 * time.Millisecond
+		_, err = oc.Choose(ctx, v.limit, v.space)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(v.limit), mock.opts.GetAmount())
+		assert.Equal(t, ptypes.DurationProto(500*time.Millisecond), mock.opts.GetMaxLatency())
 	}
 }
 
@@ -196,7 +206,7 @@ func TestBulkLookupV2(t *testing.T) {
 	n3 := &proto.Node{Id: "n3"}
 	nodes := []*proto.Node{n1, n2, n3}
 	for _, n := range nodes {
-		assert.NoError(t, s.cache.Put(n.Id, *n))
+		assert.NoError(t, s.cache.Put(ctx, n.Id, *n))
 	}
 
 	cases := []struct {
@@ -305,6 +315,8 @@ type mockOverlayServer struct {
 	lookupCalled           int
 	bulkLookupCalled       int
 	FindStorageNodesCalled int
+	// opts are the options of the last FindStorageNodes request
+	opts *proto.OverlayOptions
 }
 
 func (o *mockOverlayServer) Lookup(ctx context.Context, req *proto.LookupRequest) (*proto.LookupResponse, error) {
@@ -314,6 +326,7 @@ func (o *mockOverlayServer) Lookup(ctx context.Context, req *proto.LookupRequest
 
 func (o *mockOverlayServer) FindStorageNodes(ctx context.Context, req *proto.FindStorageNodesRequest) (*proto.FindStorageNodesResponse, error) {
 	o.FindStorageNodesCalled++
+	o.opts = req.GetOpts()
 	return &proto.FindStorageNodesResponse{}, nil
 }
 
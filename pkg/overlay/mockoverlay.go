@@ -83,6 +83,7 @@ func (c MockConfig) Run(ctx context.Context, server *provider.Provider) error {
 			}})
 	}
 
-	proto.RegisterOverlayServer(server.GRPC(), NewMockOverlay(nodes))
-	return server.Run(ctx)
+	srv := NewMockOverlay(nodes)
+	proto.RegisterOverlayServer(server.GRPC(), srv)
+	return server.Run(context.WithValue(ctx, ctxKeyOverlay, proto.OverlayServer(srv)))
 }
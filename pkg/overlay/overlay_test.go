@@ -7,13 +7,22 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
+	protob "github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/kademlia"
+	statpb "storj.io/storj/pkg/statdb/proto"
 	proto "storj.io/storj/protos/overlay" // naming proto to avoid confusion with this package
 	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
 )
 
 func TestFindStorageNodes(t *testing.T) {
@@ -105,3 +114,69 @@ func TestOverlayBulkLookup(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, r)
 }
+
+// mockStatDB passes the nodes in the order of passed
+type mockStatDB struct {
+	passed   []string
+	minStats *statpb.NodeStats
+}
+
+func (sdb *mockStatDB) ValidNodes(ctx context.Context, nodeIDs [][]byte, minStats *statpb.NodeStats, allowUnknown bool) (passed, failed [][]byte, err error) {
+	sdb.minStats = minStats
+	requested := map[string]bool{}
+	for _, id := range nodeIDs {
+		requested[string(id)] = true
+	}
+	for _, id := range sdb.passed {
+		if requested[id] {
+			passed = append(passed, []byte(id))
+			delete(requested, id)
+		}
+	}
+	for id := range requested {
+		failed = append(failed, []byte(id))
+	}
+	return passed, failed, nil
+}
+
+func TestFindStorageNodesByStats(t *testing.T) {
+	ctx := context.Background()
+
+	var items []storage.ListItem
+	for _, id := range []string{"a", "b", "c", "d"} {
+		value, err := protob.Marshal(&proto.Node{Id: id})
+		assert.NoError(t, err)
+		items = append(items, storage.ListItem{Key: storage.Key(id), Value: value})
+	}
+	cache := &Cache{DB: teststore.New(), DHT: kademlia.NewMockKademlia()}
+	assert.NoError(t, storage.PutAll(ctx, cache.DB, items...))
+
+	sdb := &mockStatDB{passed: []string{"d", "b", "a"}}
+	s := &Server{
+		cache:    cache,
+		logger:   zap.NewNop(),
+		metrics:  monkit.Default,
+		statdb:   sdb,
+		minStats: &statpb.NodeStats{AuditSuccessRatio: 0.9},
+	}
+
+	resp, err := s.FindStorageNodes(ctx, &proto.FindStorageNodesRequest{
+		Opts: &proto.OverlayOptions{Amount: 2, MaxLatency: ptypes.DurationProto(2 * time.Second)},
+	})
+	if assert.NoError(t, err) {
+		var ids []string
+		for _, n := range resp.GetNodes() {
+			ids = append(ids, n.GetId())
+		}
+		// the fastest nodes are selected
+		assert.Equal(t, []string{"d", "b"}, ids)
+	}
+	assert.Equal(t, int64(2000), sdb.minStats.GetLatency_90())
+	assert.Equal(t, 0.9, sdb.minStats.GetAuditSuccessRatio())
+
+	// the nodes failing aren't selected
+	_, err = s.FindStorageNodes(ctx, &proto.FindStorageNodesRequest{
+		Opts: &proto.OverlayOptions{Amount: 4},
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
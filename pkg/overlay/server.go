@@ -6,8 +6,10 @@ package overlay
 import (
 	"context"
 	"fmt"
-	
+	"time"
+
 	protob "github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
@@ -16,6 +18,7 @@ import (
 	"gopkg.in/spacemonkeygo/monkit.v2"
 	"storj.io/storj/pkg/dht"
 
+	statpb "storj.io/storj/pkg/statdb/proto"
 	proto "storj.io/storj/protos/overlay" // naming proto to avoid confusion with this package
 	"storj.io/storj/storage"
 )
@@ -23,12 +26,23 @@ import (
 // ServerError creates class of errors for stack traces
 var ServerError = errs.Class("Server Error")
 
+// StatDB selects storage nodes by their stats
+type StatDB interface {
+	// ValidNodes returns which of the storage nodes have at least minStats,
+	// the fastest first, and which don't
+	ValidNodes(ctx context.Context, nodeIDs [][]byte, minStats *statpb.NodeStats, allowUnknown bool) (passed, failed [][]byte, err error)
+}
+
 // Server implements our overlay RPC service
 type Server struct {
 	dht     dht.DHT
 	cache   *Cache
 	logger  *zap.Logger
 	metrics *monkit.Registry
+	// statdb, if not nil, filters the storage nodes found by their stats.
+	// The nodes selected have at least minStats.
+	statdb   StatDB
+	minStats *statpb.NodeStats
 }
 
 // Lookup finds the address of a node in our overlay network
@@ -63,11 +77,24 @@ func (o *Server) FindStorageNodes(ctx context.Context, req *proto.FindStorageNod
 	restrictedBandwidth := restrictions.GetFreeBandwidth()
 	restrictedSpace := restrictions.GetFreeDisk()
 
+	minStats := &statpb.NodeStats{
+		AuditSuccessRatio: o.minStats.GetAuditSuccessRatio(),
+		UptimeRatio:       o.minStats.GetUptimeRatio(),
+	}
+	if opts.GetMaxLatency() != nil {
+		maxLatency, err := ptypes.Duration(opts.GetMaxLatency())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		// the stats db keeps the latencies in milliseconds
+		minStats.Latency_90 = int64(maxLatency / time.Millisecond)
+	}
+
 	var start storage.Key
 	result := []*proto.Node{}
 	for {
 		var nodes []*proto.Node
-		nodes, start, err = o.populate(ctx, start, maxNodes, restrictedBandwidth, restrictedSpace)
+		nodes, start, err = o.populate(ctx, start, maxNodes, restrictedBandwidth, restrictedSpace, minStats)
 		if err != nil {
 			return nil, Error.Wrap(err)
 		}
@@ -98,7 +125,7 @@ func (o *Server) FindStorageNodes(ctx context.Context, req *proto.FindStorageNod
 }
 
 func (o *Server) getNodes(ctx context.Context, keys storage.Keys) ([]*proto.Node, error) {
-	values, err := o.cache.DB.GetAll(keys)
+	values, err := o.cache.DB.GetAll(ctx, keys)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
@@ -117,9 +144,9 @@ func (o *Server) getNodes(ctx context.Context, keys storage.Keys) ([]*proto.Node
 
 }
 
-func (o *Server) populate(ctx context.Context, starting storage.Key, maxNodes, restrictedBandwidth, restrictedSpace int64) ([]*proto.Node, storage.Key, error) {
+func (o *Server) populate(ctx context.Context, starting storage.Key, maxNodes, restrictedBandwidth, restrictedSpace int64, minStats *statpb.NodeStats) ([]*proto.Node, storage.Key, error) {
 	limit := int(maxNodes * 2)
-	keys, err := o.cache.DB.List(starting, limit)
+	keys, err := o.cache.DB.List(ctx, starting, limit)
 	if err != nil {
 		o.logger.Error("Error listing nodes", zap.Error(err))
 		return nil, nil, Error.Wrap(err)
@@ -146,6 +173,12 @@ func (o *Server) populate(ctx context.Context, starting storage.Key, maxNodes, r
 		result = append(result, v)
 	}
 
+	result, err = o.filterByStats(ctx, result, minStats)
+	if err != nil {
+		o.logger.Error("Error filtering nodes by stats", zap.Error(err))
+		return nil, nil, Error.Wrap(err)
+	}
+
 	nextStart := keys[len(keys)-1]
 	if len(keys) < limit {
 		nextStart = nil
@@ -154,6 +187,33 @@ func (o *Server) populate(ctx context.Context, starting storage.Key, maxNodes, r
 	return result, nextStart, nil
 }
 
+// filterByStats returns the nodes with at least minStats, the fastest
+// first. The nodes without stats yet are kept after the others, so that
+// new nodes get selected too.
+func (o *Server) filterByStats(ctx context.Context, nodes []*proto.Node, minStats *statpb.NodeStats) ([]*proto.Node, error) {
+	if o.statdb == nil || len(nodes) == 0 {
+		return nodes, nil
+	}
+
+	byID := make(map[string]*proto.Node, len(nodes))
+	ids := make([][]byte, 0, len(nodes))
+	for _, n := range nodes {
+		byID[n.GetId()] = n
+		ids = append(ids, []byte(n.GetId()))
+	}
+
+	passed, _, err := o.statdb.ValidNodes(ctx, ids, minStats, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*proto.Node, 0, len(passed))
+	for _, id := range passed {
+		result = append(result, byID[string(id)])
+	}
+	return result, nil
+}
+
 //lookupRequestsToNodeIDs returns the nodeIDs from the LookupRequests
 func lookupRequestsToNodeIDs(reqs *proto.LookupRequests) []string {
 	var ids []string
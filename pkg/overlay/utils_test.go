@@ -31,7 +31,7 @@ func NewMockServer(items []storage.ListItem) *grpc.Server {
 		DHT: k,
 	}
 
-	_ = storage.PutAll(c.DB, items...)
+	_ = storage.PutAll(ctx, c.DB, items...)
 
 	s := Server{
 		dht:     k,
@@ -13,6 +13,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
@@ -43,6 +44,7 @@ type PSClient interface {
 	Get(ctx context.Context, id PieceID, size int64, ba *pb.PayerBandwidthAllocation) (ranger.Ranger, error)
 	Delete(ctx context.Context, pieceID PieceID) error
 	Stats(ctx context.Context) error
+	Retain(ctx context.Context, created time.Time, filter []byte) error
 	io.Closer
 }
 
@@ -169,6 +171,29 @@ func (client *Client) Stats(ctx context.Context) error {
 	return nil
 }
 
+// Retain sends a filter of the pieces to keep to a piece storage node. Pieces
+// stored before created that do not match the filter are garbage collected.
+// The filter is signed with the client key, which must be the key of a
+// satellite trusted by the node.
+func (client *Client) Retain(ctx context.Context, created time.Time, filter []byte) error {
+	req := &pb.RetainRequest{
+		CreationUnixSec: created.Unix(),
+		Filter:          filter,
+	}
+
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return ClientError.Wrap(err)
+	}
+	req.Signature, err = client.sign(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.route.Retain(ctx, req)
+	return err
+}
+
 // sign a message using the clients private key
 func (client *Client) sign(msg []byte) (signature []byte, err error) {
 	if client.prikey == nil {
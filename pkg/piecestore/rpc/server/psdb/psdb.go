@@ -236,3 +236,25 @@ func (db *DB) DeleteTTLByID(id string) error {
 	}
 	return err
 }
+
+// GetIDsCreatedBefore returns the ids of all pieces stored before created
+func (db *DB) GetIDsCreatedBefore(created int64) (ids []string, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT id FROM ttl WHERE created < ?`, created)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
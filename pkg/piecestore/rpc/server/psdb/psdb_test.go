@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	_ "github.com/mattn/go-sqlite3"
@@ -184,6 +185,33 @@ func TestHappyPath(t *testing.T) {
 	})
 }
 
+func TestGetIDsCreatedBefore(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := db.AddTTL(id, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids, err := db.GetIDsCreatedBefore(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no ids got %v", ids)
+	}
+
+	ids, err = db.GetIDsCreatedBefore(time.Now().Add(time.Minute).Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 ids got %v", ids)
+	}
+}
+
 func BenchmarkWriteBandwidthAllocation(b *testing.B) {
 	db, cleanup := openTest(b)
 	defer cleanup()
@@ -0,0 +1,110 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	pb "storj.io/storj/protos/piecestore"
+)
+
+// RetainError is a type of error for failures in Server.Retain()
+var RetainError = errs.Class("retain error")
+
+// Retain schedules the removal of all pieces stored before the filter
+// creation time that are not contained in the filter. Only the filters
+// signed by a trusted satellite are accepted.
+func (s *Server) Retain(ctx context.Context, in *pb.RetainRequest) (*pb.RetainSummary, error) {
+	pi, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, RetainError.Wrap(err)
+	}
+
+	if err := s.verifyRetain(pi, in); err != nil {
+		return nil, err
+	}
+
+	filter, err := bloomfilter.NewFromBytes(in.GetFilter())
+	if err != nil {
+		return nil, RetainError.Wrap(err)
+	}
+
+	created := in.GetCreationUnixSec()
+	zap.S().Infof("Received retain filter from %s, collecting garbage in %v", pi.ID, s.retainDelay)
+
+	time.AfterFunc(s.retainDelay, func() {
+		if err := s.collectGarbage(context.Background(), filter, created); err != nil {
+			zap.S().Errorf("Failed collecting garbage: %v", err)
+		}
+	})
+
+	return &pb.RetainSummary{Message: OK}, nil
+}
+
+// verifyRetain checks that the retain request was sent by a trusted
+// satellite and signed by it
+func (s *Server) verifyRetain(pi *provider.PeerIdentity, in *pb.RetainRequest) error {
+	if !s.satellites[pi.ID.String()] {
+		return RetainError.New("%s isn't a trusted satellite", pi.ID)
+	}
+
+	k, ok := pi.Leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return peertls.ErrUnsupportedKey.New("%T", pi.Leaf.PublicKey)
+	}
+
+	// the signature covers the request without the signature
+	data, err := proto.Marshal(&pb.RetainRequest{
+		CreationUnixSec: in.GetCreationUnixSec(),
+		Filter:          in.GetFilter(),
+	})
+	if err != nil {
+		return RetainError.Wrap(err)
+	}
+
+	if ok := cryptopasta.Verify(data, in.GetSignature(), k); !ok {
+		return RetainError.New("failed to verify the filter signature")
+	}
+	return nil
+}
+
+// collectGarbage deletes pieces stored before created that filter doesn't contain
+func (s *Server) collectGarbage(ctx context.Context, filter *bloomfilter.Filter, created int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ids, err := s.DB.GetIDsCreatedBefore(created)
+	if err != nil {
+		return RetainError.Wrap(err)
+	}
+
+	deleted := 0
+	var errlist []error
+	for _, id := range ids {
+		if filter.Contains([]byte(id)) {
+			continue
+		}
+		if err := s.deleteByID(id); err != nil {
+			errlist = append(errlist, err)
+			continue
+		}
+		deleted++
+	}
+
+	zap.S().Infof("Garbage collection deleted %d of %d pieces", deleted, len(ids))
+	if len(errlist) > 0 {
+		return RetainError.Wrap(utils.CombineErrors(errlist...))
+	}
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/gtank/cryptopasta"
 	"github.com/zeebo/errs"
@@ -32,7 +34,9 @@ var (
 
 // Config contains everything necessary for a server
 type Config struct {
-	Path string `help:"path to store data in" default:"$CONFDIR"`
+	Path        string        `help:"path to store data in" default:"$CONFDIR"`
+	RetainDelay time.Duration `help:"how long to wait after receiving a retain filter before collecting garbage" default:"1h"`
+	Satellites  string        `help:"comma-separated ids of the satellites trusted to send retain filters" default:""`
 }
 
 // Run implements provider.Responsibility
@@ -58,6 +62,9 @@ type Server struct {
 	DataDir string
 	DB      *psdb.DB
 	pkey    crypto.PrivateKey
+
+	retainDelay time.Duration
+	satellites  map[string]bool
 }
 
 // Initialize -- initializes a server struct
@@ -70,7 +77,20 @@ func Initialize(ctx context.Context, config Config, pkey crypto.PrivateKey) (*Se
 		return nil, err
 	}
 
-	return &Server{DataDir: dataDir, DB: db, pkey: pkey}, nil
+	satellites := map[string]bool{}
+	for _, id := range strings.Split(config.Satellites, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			satellites[id] = true
+		}
+	}
+
+	return &Server{
+		DataDir:     dataDir,
+		DB:          db,
+		pkey:        pkey,
+		retainDelay: config.RetainDelay,
+		satellites:  satellites,
+	}, nil
 }
 
 // Stop the piececstore node
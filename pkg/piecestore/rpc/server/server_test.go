@@ -18,6 +18,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gtank/cryptopasta"
@@ -26,6 +27,7 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
+	"storj.io/storj/pkg/bloomfilter"
 	pstore "storj.io/storj/pkg/piecestore"
 	"storj.io/storj/pkg/piecestore/rpc/server/psdb"
 	"storj.io/storj/pkg/provider"
@@ -478,6 +480,70 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestRetain(t *testing.T) {
+	TS := NewTestServer(t)
+	defer TS.Stop()
+
+	TS.s.retainDelay = time.Hour
+
+	filter := bloomfilter.NewOptimal(10, 0.1)
+	filter.Add([]byte("11111111111111111111"))
+	sign := func(req *pb.RetainRequest) []byte {
+		data, err := proto.Marshal(req)
+		assert.NoError(t, err)
+		signature, err := cryptopasta.Sign(data, TS.k.(*ecdsa.PrivateKey))
+		assert.NoError(t, err)
+		return signature
+	}
+	req := &pb.RetainRequest{CreationUnixSec: 1234567890, Filter: filter.Bytes()}
+	signature := sign(req)
+	forged := sign(&pb.RetainRequest{CreationUnixSec: 1234567891, Filter: filter.Bytes()})
+
+	for i, tt := range []struct {
+		trusted   bool
+		signature []byte
+		err       string
+	}{
+		{ // should reject filters from untrusted peers
+			trusted:   false,
+			signature: signature,
+			err:       "rpc error: code = Unknown desc = retain error: " + TS.id + " isn't a trusted satellite",
+		},
+		{ // should reject unsigned filters
+			trusted:   true,
+			signature: nil,
+			err:       "rpc error: code = Unknown desc = retain error: failed to verify the filter signature",
+		},
+		{ // should reject filters whose signature doesn't match
+			trusted:   true,
+			signature: forged,
+			err:       "rpc error: code = Unknown desc = retain error: failed to verify the filter signature",
+		},
+		{ // should accept filters signed by trusted satellites
+			trusted:   true,
+			signature: signature,
+		},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		TS.s.satellites = map[string]bool{TS.id: tt.trusted}
+
+		resp, err := TS.c.Retain(ctx, &pb.RetainRequest{
+			CreationUnixSec: req.CreationUnixSec,
+			Filter:          req.Filter,
+			Signature:       tt.signature,
+		})
+		if tt.err != "" {
+			if assert.Error(t, err, errTag) {
+				assert.Equal(t, tt.err, err.Error(), errTag)
+			}
+			continue
+		}
+		if assert.NoError(t, err, errTag) {
+			assert.Equal(t, OK, resp.GetMessage(), errTag)
+		}
+	}
+}
+
 func newTestServerStruct(t *testing.T) (*Server, func()) {
 	tmp, err := ioutil.TempDir("", "storj-piecestore")
 	if err != nil {
@@ -523,6 +589,7 @@ type TestServer struct {
 	conn     *grpc.ClientConn
 	c        pb.PieceStoreRoutesClient
 	k        crypto.PrivateKey
+	id       string
 }
 
 func NewTestServer(t *testing.T) *TestServer {
@@ -551,7 +618,7 @@ func NewTestServer(t *testing.T) *TestServer {
 
 	k, ok := fiC.Key.(*ecdsa.PrivateKey)
 	assert.True(t, ok)
-	ts := &TestServer{s: s, scleanup: cleanup, grpcs: grpcs, k: k}
+	ts := &TestServer{s: s, scleanup: cleanup, grpcs: grpcs, k: k, id: fiC.ID.String()}
 	addr := ts.start()
 	ts.c, ts.conn = connect(addr, co)
 
@@ -5,13 +5,17 @@ package pointerdb
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 
+	"storj.io/storj/pkg/macaroon"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/utils"
 	proto "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
 	"storj.io/storj/storage/boltdb"
+	"storj.io/storj/storage/sqlkv"
 	"storj.io/storj/storage/storelogger"
 )
 
@@ -20,32 +24,80 @@ const (
 	PointerBucket = "pointers"
 )
 
+// CtxKey is the type of the pointerdb context keys
+type CtxKey int
+
+const (
+	ctxKeyPointerDB CtxKey = iota
+)
+
 // Config is a configuration struct that is everything you need to start a
 // PointerDB responsibility
 type Config struct {
-	DatabaseURL          string `help:"the database connection string to use" default:"bolt://$CONFDIR/pointerdb.db"`
-	MinInlineSegmentSize int64  `default:"1240" help:"minimum inline segment size"`
-	MaxInlineSegmentSize int    `default:"8000" help:"maximum inline segment size"`
+	DatabaseURL          string        `help:"the database connection string to use" default:"bolt://$CONFDIR/pointerdb.db"`
+	MinInlineSegmentSize int64         `default:"1240" help:"minimum inline segment size"`
+	MaxInlineSegmentSize int           `default:"8000" help:"maximum inline segment size"`
+	APISecret            string        `help:"the base58 encoded secret api keys are signed with" default:""`
+	LifecycleInterval    time.Duration `help:"how frequently the lifecycle rules of the buckets are applied, 0 to disable" default:"1h"`
+	OrphanChunkAge       time.Duration `help:"how long a chunk may stay unreferenced before it is deleted with the lifecycle rules, 0 to keep them" default:"24h"`
+	LegacyAPIKey         string        `help:"an api key of the project the pointers stored before the projects existed are moved to" default:""`
 }
 
 // Run implements the provider.Responsibility interface
 func (c Config) Run(ctx context.Context, server *provider.Provider) error {
+	if c.APISecret == "" {
+		return Error.New("no api secret configured")
+	}
+
 	dburl, err := utils.ParseURL(c.DatabaseURL)
 	if err != nil {
 		return err
 	}
-	if dburl.Scheme != "bolt" {
-		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+
+	var legacyProjectID string
+	if c.LegacyAPIKey != "" {
+		key, err := macaroon.ParseAPIKey(c.LegacyAPIKey)
+		if err != nil {
+			return err
+		}
+		legacyProjectID = key.ProjectID()
 	}
 
-	bdb, err := boltdb.New(dburl.Path, PointerBucket)
+	var db storage.KeyValueStore
+	switch dburl.Scheme {
+	case "bolt":
+		db, err = boltdb.New(dburl.Path, PointerBucket)
+	case "postgres", "postgresql", "sqlite3":
+		db, err = sqlkv.OpenURL(c.DatabaseURL, PointerBucket)
+	default:
+		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
 	if err != nil {
 		return err
 	}
-	defer func() { _ = bdb.Close() }()
+	defer func() { _ = db.Close() }()
+
+	dblogged := storelogger.New(zap.L(), db)
+	s := NewServer(dblogged, zap.L(), c)
+	if err = s.migrate(ctx, legacyProjectID); err != nil {
+		return err
+	}
+	proto.RegisterPointerDBServer(server.GRPC(), s)
 
-	bdblogged := storelogger.New(zap.L(), bdb)
-	proto.RegisterPointerDBServer(server.GRPC(), NewServer(bdblogged, zap.L(), c))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if c.LifecycleInterval > 0 {
+		go s.runLifecycle(ctx, c.LifecycleInterval)
+	}
+
+	return server.Run(context.WithValue(ctx, ctxKeyPointerDB, s))
+}
 
-	return server.Run(ctx)
+// LoadFromContext loads the pointerdb server from the Provider context
+// stack if one exists.
+func LoadFromContext(ctx context.Context) *Server {
+	if v, ok := ctx.Value(ctxKeyPointerDB).(*Server); ok {
+		return v
+	}
+	return nil
 }
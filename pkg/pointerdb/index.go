@@ -0,0 +1,189 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+)
+
+const (
+	// expirationIndex is the part of the index keys of the expiration index
+	expirationIndex = "e"
+	// nodeIndex is the part of the index keys of the node index
+	nodeIndex = "n"
+)
+
+// The secondary indexes of the pointers are kept in the pointer store as
+//
+//	_index/<projectID>/e/<expiration>/<path>
+//	_index/n/<nodeID>/<projectID>/<path>
+//
+// so that the segments of a project expiring before a given time, and the
+// segments of every project or of a single one having a piece on a given
+// node, can be listed without scanning every pointer. The entries of the
+// node index have the piece ID of the segment as value, the others are
+// empty.
+
+// updateIndex replaces the index entries of the changed pointers of the
+// project in txn
+func updateIndex(txn storage.Txn, projectID string, changes []pointerChange) error {
+	for _, change := range changes {
+		oldEntries := indexEntries(projectID, change.path, change.old)
+		newEntries := indexEntries(projectID, change.path, change.new)
+		for _, entry := range oldEntries {
+			if _, ok := findEntry(newEntries, entry.Key); ok {
+				continue
+			}
+			if err := txn.Delete(entry.Key); err != nil && !storage.ErrKeyNotFound.Has(err) {
+				return err
+			}
+		}
+		for _, entry := range newEntries {
+			if old, ok := findEntry(oldEntries, entry.Key); ok && bytes.Equal(old.Value, entry.Value) {
+				continue
+			}
+			if err := txn.Put(entry.Key, entry.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// listExpiring lists the paths of the segments of the project expiring
+// before the given time, ordered by expiration. startAfter is the cursor
+// returned with the last path of the previous page.
+func (s *Server) listExpiring(ctx context.Context, projectID string, before time.Time, startAfter string, limit int) (paths, cursors []string, more bool, err error) {
+	items, more, err := storage.ListV2(ctx, s.DB, storage.ListOptions{
+		Prefix:     indexPrefix(projectID, expirationIndex),
+		StartAfter: storage.Key(startAfter),
+		Recursive:  true,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	end := storage.Key(encodeExpiration(before))
+	for _, item := range items {
+		if !item.Key.Less(end) {
+			// the rest of the segments expire later
+			return paths, cursors, false, nil
+		}
+		_, path, ok := splitIndexKey(item.Key)
+		if !ok {
+			continue
+		}
+		paths = append(paths, path)
+		cursors = append(cursors, item.Key.String())
+	}
+	return paths, cursors, more, nil
+}
+
+// listByNode lists the paths of the segments of the project with a piece
+// on the node. startAfter is the last path of the previous page.
+func (s *Server) listByNode(ctx context.Context, projectID, nodeID, startAfter string, limit int) (paths []string, more bool, err error) {
+	items, more, err := storage.ListV2(ctx, s.DB, storage.ListOptions{
+		Prefix:     indexPrefix(nodeIndex, nodeID, projectID),
+		StartAfter: storage.Key(startAfter),
+		Recursive:  true,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, item := range items {
+		paths = append(paths, item.Key.String())
+	}
+	return paths, more, nil
+}
+
+// indexEntries returns the sorted index entries of the pointer at path of
+// the project. A nil pointer has no entries.
+func indexEntries(projectID, path string, pointer *pb.Pointer) storage.Items {
+	if pointer == nil {
+		return nil
+	}
+
+	var entries storage.Items
+	if expiration, ok := expirationOf(pointer); ok {
+		entries = append(entries, storage.ListItem{
+			Key:   indexKey(path, projectID, expirationIndex, encodeExpiration(expiration)),
+			Value: storage.Value{},
+		})
+	}
+	pieceID := storage.Value(pointer.GetRemote().GetPieceId())
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		if piece.GetNodeId() == "" {
+			continue
+		}
+		key := indexKey(path, nodeIndex, piece.GetNodeId(), projectID)
+		if _, ok := findEntry(entries, key); !ok {
+			entries = append(entries, storage.ListItem{Key: key, Value: pieceID})
+		}
+	}
+
+	sort.Sort(entries)
+	return entries
+}
+
+// expirationOf returns the expiration of the pointer. Pointers without an
+// expiration date, or with one before the unix epoch as clients send for
+// the zero time, never expire.
+func expirationOf(pointer *pb.Pointer) (time.Time, bool) {
+	ts := pointer.GetExpirationDate()
+	if ts == nil || ts.GetSeconds() <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC(), true
+}
+
+// encodeExpiration encodes t with a fixed width, so that the index keys
+// are sorted by expiration
+func encodeExpiration(t time.Time) string {
+	seconds := t.Unix()
+	if seconds < 0 {
+		return fmt.Sprintf("%020d.%09d", 0, 0)
+	}
+	return fmt.Sprintf("%020d.%09d", seconds, t.Nanosecond())
+}
+
+// indexPrefix returns the prefix of the index entries named by parts
+func indexPrefix(parts ...string) storage.Key {
+	return append(reservedKey(append([]string{"index"}, parts...)...), storage.Delimiter)
+}
+
+// indexKey returns the entry of path in the index named by parts
+func indexKey(path string, parts ...string) storage.Key {
+	return append(indexPrefix(parts...), path...)
+}
+
+// splitIndexKey splits a key of the form <value>/<path>, relative to the
+// prefix of an index
+func splitIndexKey(key storage.Key) (value, path string, ok bool) {
+	for i, b := range key {
+		if b == storage.Delimiter {
+			return string(key[:i]), string(key[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// findEntry returns the entry of entries at key
+func findEntry(entries storage.Items, key storage.Key) (storage.ListItem, bool) {
+	for _, entry := range entries {
+		if entry.Key.Equal(key) {
+			return entry, true
+		}
+	}
+	return storage.ListItem{}, false
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"storj.io/storj/storage"
+)
+
+// reservedPrefix is the prefix of the keys of the pointer store that aren't
+// pointers: the usage counters, the indexes and the metadata of the store.
+// Project IDs are hex encoded, so no pointer key starts with it.
+const reservedPrefix = "_"
+
+// projectKey returns the key path is stored at for the project
+func projectKey(projectID, path string) storage.Key {
+	return storage.Key(projectID + string(storage.Delimiter) + path)
+}
+
+// reservedKey returns the key of the reserved keyspace named by parts
+func reservedKey(parts ...string) storage.Key {
+	key := reservedPrefix
+	for i, part := range parts {
+		if i > 0 {
+			key += string(storage.Delimiter)
+		}
+		key += part
+	}
+	return storage.Key(key)
+}
+
+// IsPointerKey returns whether key of the pointer store is the key of a
+// pointer, rather than of the usage counters or the indexes kept next to
+// the pointers
+func IsPointerKey(key storage.Key) bool {
+	return !bytes.HasPrefix(key, []byte(reservedPrefix))
+}
+
+// splitProjectKey splits a pointer key into the project ID and the path. ok
+// is false for the keys of the pointers stored before they were namespaced
+// by project.
+func splitProjectKey(key storage.Key) (projectID, path string, ok bool) {
+	i := bytes.IndexByte(key, storage.Delimiter)
+	if i < 0 || !isProjectID(string(key[:i])) {
+		return "", "", false
+	}
+	return string(key[:i]), string(key[i+1:]), true
+}
+
+// isProjectID returns whether id has the form of the project IDs of the API
+// keys
+func isProjectID(id string) bool {
+	decoded, err := hex.DecodeString(id)
+	return err == nil && len(decoded) == 16
+}
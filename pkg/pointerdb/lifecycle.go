@@ -0,0 +1,410 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/lifecycle"
+	"storj.io/storj/pkg/storage/objects"
+	pb "storj.io/storj/protos/pointerdb"
+	streamspb "storj.io/storj/protos/streams"
+	"storj.io/storj/storage"
+)
+
+// lifecycleLease is the key of the lease of the server applying the
+// lifecycle rules, so that the servers sharing the store don't all apply
+// them
+var lifecycleLease = reservedKey("meta", "lifecycle-lease")
+
+// runLifecycle applies the lifecycle rules every interval until ctx is
+// canceled, while it holds the lifecycle lease. The lease lasts two
+// intervals, so another server takes over when it stops renewing it.
+func (s *Server) runLifecycle(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	holder := make([]byte, 8)
+	if _, err := rand.Read(holder); err != nil {
+		s.logger.Error("failed to name the lifecycle lease holder", zap.Error(err))
+		return
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			held, err := acquireLease(ctx, s.DB, lifecycleLease, hex.EncodeToString(holder),
+				time.Now(), 2*interval)
+			if err != nil {
+				s.logger.Error("failed to acquire the lifecycle lease", zap.Error(err))
+				continue
+			}
+			if !held {
+				continue
+			}
+			if err := s.ApplyLifecycle(ctx, time.Now()); err != nil {
+				s.logger.Error("failed to apply lifecycle rules", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// acquireLease takes or renews the lease at key for holder until duration
+// passes, unless another holder has it past now. It returns whether holder
+// has the lease.
+func acquireLease(ctx context.Context, db storage.KeyValueStore, key storage.Key, holder string,
+	now time.Time, duration time.Duration) (held bool, err error) {
+	err = storage.RetryTxn(ctx, db, func(txn storage.Txn) error {
+		held = false
+		value, err := txn.Get(key)
+		switch {
+		case err == nil:
+			fields := strings.Fields(string(value))
+			if len(fields) == 2 && fields[0] != holder {
+				until, err := strconv.ParseInt(fields[1], 10, 64)
+				if err == nil && now.Before(time.Unix(0, until)) {
+					return nil
+				}
+			}
+		case !storage.ErrKeyNotFound.Has(err):
+			return err
+		}
+		held = true
+		return txn.Put(key, storage.Value(fmt.Sprintf("%s %d", holder, now.Add(duration).UnixNano())))
+	})
+	return held, err
+}
+
+// ApplyLifecycle applies the lifecycle rules of every bucket at now. The
+// objects expired by the rules are deleted with all their segments, and so
+// are the segments of the uploads abandoned for longer than the rules
+// allow. The previous versions of the objects of versioned buckets expire
+// as long after they were replaced as the objects do after their creation.
+// The chunks left unreferenced for longer than Config.OrphanChunkAge are
+// deleted too. Only the pointers are deleted, the pieces are left to the
+// garbage collection.
+func (s *Server) ApplyLifecycle(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return s.forEach(ctx, nil, false, func(item storage.ListItem) error {
+		if !item.IsPrefix || !IsPointerKey(item.Key) {
+			return nil
+		}
+		projectID := strings.TrimSuffix(item.Key.String(), string(storage.Delimiter))
+		if s.config.OrphanChunkAge > 0 {
+			err := s.collectOrphanChunks(ctx, projectID, now.Add(-s.config.OrphanChunkAge))
+			if err != nil {
+				s.logger.Error("failed to collect orphan chunks", zap.String("project", projectID),
+					zap.Error(err))
+			}
+		}
+		return s.forEach(ctx, projectKey(projectID, "l/"), false, func(item storage.ListItem) error {
+			if item.IsPrefix {
+				return nil
+			}
+			bucket := item.Key.String()
+			pointer := &pb.Pointer{}
+			if err := proto.Unmarshal(item.Value, pointer); err != nil {
+				return Error.Wrap(err)
+			}
+			rules, err := lifecycle.FromStreamMetadata(pointer.GetMetadata())
+			if err != nil {
+				s.logger.Warn("invalid lifecycle rules", zap.String("project", projectID),
+					zap.String("bucket", bucket), zap.Error(err))
+				return nil
+			}
+			for _, rule := range rules {
+				// a failing rule doesn't keep the others from being applied
+				if err := s.applyRule(ctx, projectID, bucket, rule, now); err != nil {
+					s.logger.Error("failed to apply lifecycle rule", zap.String("project", projectID),
+						zap.String("bucket", bucket), zap.String("rule", rule.ID), zap.Error(err))
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// applyRule applies a lifecycle rule to the objects of the bucket
+func (s *Server) applyRule(ctx context.Context, projectID, bucket string, rule lifecycle.Rule, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// the paths to delete are collected while listing and deleted a batch
+	// at a time
+	var paths []string
+	remove := func(force bool, removed ...string) error {
+		paths = append(paths, removed...)
+		if len(paths) < storage.LookupLimit && !force {
+			return nil
+		}
+		err := s.deleteAll(ctx, projectID, paths)
+		paths = nil
+		return err
+	}
+
+	prefix := bucket + "/" + rule.Prefix
+	if rule.ExpirationDays > 0 {
+		// the previous versions go first, as they expire after the
+		// creation of the versions replacing them
+		err = s.expireVersions(ctx, projectID, bucket, rule, now, remove)
+		if err != nil {
+			return err
+		}
+
+		history := bucket + "/" + objects.VersionsDir + "/"
+		err = s.forEach(ctx, projectKey(projectID, "l/"+prefix), true, func(item storage.ListItem) error {
+			path := prefix + item.Key.String()
+			if strings.HasPrefix(path, history) {
+				return nil
+			}
+			pointer := &pb.Pointer{}
+			if err := proto.Unmarshal(item.Value, pointer); err != nil {
+				return Error.Wrap(err)
+			}
+			created, err := ptypes.Timestamp(pointer.GetCreationDate())
+			if err != nil || !rule.Expired(created, now) {
+				return nil
+			}
+			segments, err := objectSegments(path, pointer)
+			if err != nil {
+				return err
+			}
+			return remove(false, segments...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if rule.AbortIncompleteUploadDays > 0 {
+		err = s.forEach(ctx, projectKey(projectID, "s0/"+prefix), true, func(item storage.ListItem) error {
+			pointer := &pb.Pointer{}
+			if err := proto.Unmarshal(item.Value, pointer); err != nil {
+				return Error.Wrap(err)
+			}
+			started, err := ptypes.Timestamp(pointer.GetCreationDate())
+			if err != nil || !rule.Abandoned(started, now) {
+				return nil
+			}
+			path := prefix + item.Key.String()
+			_, err = s.DB.Get(ctx, projectKey(projectID, "l/"+path))
+			switch {
+			case err == nil:
+				// the upload has completed
+				return nil
+			case !storage.ErrKeyNotFound.Has(err):
+				return err
+			}
+			segments, err := s.uploadedSegments(ctx, projectID, path)
+			if err != nil {
+				return err
+			}
+			return remove(false, segments...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return remove(true)
+}
+
+// expireVersions removes the previous versions of the objects of the bucket
+// matching the rule that were replaced for longer than the rule allows.
+// They are kept at <bucket>/<objects.VersionsDir>/<path>/<versionID> and
+// listed newest first, so each was replaced when the version listed before
+// it was created, and the newest when the latest version was.
+func (s *Server) expireVersions(ctx context.Context, projectID, bucket string, rule lifecycle.Rule,
+	now time.Time, remove func(bool, ...string) error) error {
+	history := bucket + "/" + objects.VersionsDir + "/"
+
+	// the versions of an object are listed in order, but may be interleaved
+	// with those of the objects below its path, so the objects whose
+	// versions are listed are kept as a stack of the ancestors of the last
+	type listed struct {
+		path     string
+		replaced time.Time
+	}
+	var stack []listed
+
+	return s.forEach(ctx, projectKey(projectID, "l/"+history+rule.Prefix), true, func(item storage.ListItem) error {
+		key := rule.Prefix + item.Key.String()
+		i := strings.LastIndex(key, "/")
+		if i < 0 {
+			return nil
+		}
+		path, id := key[:i], key[i+1:]
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1].path
+			if top == path || strings.HasPrefix(path, top+"/") {
+				break
+			}
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 || stack[len(stack)-1].path != path {
+			replaced, err := s.replacedAt(ctx, projectID, bucket+"/"+path, id)
+			if err != nil {
+				return err
+			}
+			stack = append(stack, listed{path: path, replaced: replaced})
+		}
+		top := &stack[len(stack)-1]
+
+		expired := !top.replaced.IsZero() && rule.Expired(top.replaced, now)
+		top.replaced = objects.VersionTime(id)
+		if !expired {
+			return nil
+		}
+		pointer := &pb.Pointer{}
+		if err := proto.Unmarshal(item.Value, pointer); err != nil {
+			return Error.Wrap(err)
+		}
+		segments, err := objectSegments(history+key, pointer)
+		if err != nil {
+			return err
+		}
+		return remove(false, segments...)
+	})
+}
+
+// collectOrphanChunks deletes the chunks of the project created before
+// the given time that no segment references. They're left behind by the
+// streams whose upload failed after their chunks were put. A chunk
+// referenced again since it was listed is kept.
+func (s *Server) collectOrphanChunks(ctx context.Context, projectID string, before time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return s.forEach(ctx, projectKey(projectID, chunkPrefix), true, func(item storage.ListItem) error {
+		pointer := &pb.Pointer{}
+		if err := proto.Unmarshal(item.Value, pointer); err != nil {
+			return Error.Wrap(err)
+		}
+		if pointer.GetReferenceCount() > 0 {
+			return nil
+		}
+		created, err := ptypes.Timestamp(pointer.GetCreationDate())
+		if err != nil || !created.Before(before) {
+			return nil
+		}
+		err = s.deletePointers(ctx, projectID, []string{chunkPrefix + item.Key.String()})
+		if ErrChunkReferenced.Has(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// replacedAt returns when the newest previous version id of the object at
+// path of a bucket was replaced, which is when the latest version was
+// created. Without a latest version, the creation of the previous version
+// is used.
+func (s *Server) replacedAt(ctx context.Context, projectID, path, id string) (time.Time, error) {
+	value, err := s.DB.Get(ctx, projectKey(projectID, "l/"+path))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return objects.VersionTime(id), nil
+		}
+		return time.Time{}, err
+	}
+	pointer := &pb.Pointer{}
+	if err = proto.Unmarshal(value, pointer); err != nil {
+		return time.Time{}, Error.Wrap(err)
+	}
+	created, err := ptypes.Timestamp(pointer.GetCreationDate())
+	if err != nil {
+		// without a creation date, the version never expires
+		return time.Time{}, nil
+	}
+	return created, nil
+}
+
+// objectSegments returns the paths of the segments of the object at path
+// of a bucket with the last segment pointer
+func objectSegments(path string, pointer *pb.Pointer) ([]string, error) {
+	msi := streamspb.MetaStreamInfo{}
+	if err := proto.Unmarshal(pointer.GetMetadata(), &msi); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	segments := make([]string, 0, msi.NumberOfSegments+1)
+	for i := int64(0); i < msi.NumberOfSegments; i++ {
+		segments = append(segments, fmt.Sprintf("s%d/%s", i, path))
+	}
+	return append(segments, "l/"+path), nil
+}
+
+// uploadedSegments returns the paths of the segments s0/<path>, s1/<path>,
+// ... uploaded so far
+func (s *Server) uploadedSegments(ctx context.Context, projectID, path string) ([]string, error) {
+	var segments []string
+	for {
+		keys := make(storage.Keys, storage.LookupLimit)
+		for i := range keys {
+			keys[i] = projectKey(projectID, fmt.Sprintf("s%d/%s", len(segments)+i, path))
+		}
+		values, err := s.DB.GetAll(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			if value == nil {
+				return segments, nil
+			}
+			segments = append(segments, string(keys[i][len(projectID)+1:]))
+		}
+	}
+}
+
+// deleteAll deletes the pointers at paths of the project in batches
+func (s *Server) deleteAll(ctx context.Context, projectID string, paths []string) error {
+	for len(paths) > 0 {
+		n := len(paths)
+		if n > storage.LookupLimit {
+			n = storage.LookupLimit
+		}
+		if err := s.deletePointers(ctx, projectID, paths[:n]); err != nil {
+			return err
+		}
+		paths = paths[n:]
+	}
+	return nil
+}
+
+// forEach calls fn with every item listed under prefix. The items are read
+// a page at a time, so fn may change the pointers.
+func (s *Server) forEach(ctx context.Context, prefix storage.Key, recursive bool, fn func(storage.ListItem) error) error {
+	var startAfter storage.Key
+	for {
+		items, more, err := storage.ListV2(ctx, s.DB, storage.ListOptions{
+			Prefix:       prefix,
+			StartAfter:   startAfter,
+			Recursive:    recursive,
+			IncludeValue: true,
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if !more || len(items) == 0 {
+			return nil
+		}
+		startAfter = items[len(items)-1].Key
+	}
+}
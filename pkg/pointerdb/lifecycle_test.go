@@ -0,0 +1,230 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	base58 "github.com/jbenet/go-base58"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/storage/lifecycle"
+	"storj.io/storj/pkg/storage/objects"
+	pb "storj.io/storj/protos/pointerdb"
+	streamspb "storj.io/storj/protos/streams"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestApplyLifecycle(t *testing.T) {
+	s := newTestServer(teststore.New())
+	alice := newTestAPIKey(t, newTestRootKey(t))
+	bob := newTestAPIKey(t, newTestRootKey(t))
+
+	// streamMetadata returns the metadata of the last segment of a stream of
+	// segments with the user-defined metadata
+	streamMetadata := func(segments int64, userDefined map[string]string) []byte {
+		metadata, err := gogoproto.Marshal(&objects.SerializableMeta{UserDefined: userDefined})
+		assert.NoError(t, err)
+		data, err := gogoproto.Marshal(&streamspb.MetaStreamInfo{
+			NumberOfSegments: segments,
+			Metadata:         metadata,
+		})
+		assert.NoError(t, err)
+		return data
+	}
+	put := func(apiKey []byte, path string, metadata []byte) {
+		_, err := s.Put(ctx, &pb.PutRequest{
+			Path:    path,
+			Pointer: &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte(path), Metadata: metadata},
+			APIKey:  apiKey,
+		})
+		assert.NoError(t, err)
+	}
+	exists := func(apiKey []byte, path string) bool {
+		_, err := s.Get(ctx, &pb.GetRequest{Path: path, APIKey: apiKey})
+		if status.Code(err) == codes.NotFound {
+			return false
+		}
+		assert.NoError(t, err)
+		return true
+	}
+
+	rules, err := lifecycle.Encode([]lifecycle.Rule{
+		{ID: "tmp", Prefix: "tmp/", ExpirationDays: 1},
+		{ID: "uploads", AbortIncompleteUploadDays: 7},
+	})
+	assert.NoError(t, err)
+	withRules := map[string]string{lifecycle.MetadataKey: rules}
+
+	put(alice, "l/logs", streamMetadata(0, withRules))
+	put(alice, "s0/logs/tmp/a", nil)
+	put(alice, "l/logs/tmp/a", streamMetadata(1, nil))
+	put(alice, "l/logs/b", streamMetadata(0, nil))
+	put(alice, "s0/logs/c", nil)
+	put(alice, "s1/logs/c", nil)
+	put(alice, "l/other", streamMetadata(0, nil))
+	put(alice, "l/other/tmp/a", streamMetadata(0, nil))
+	put(bob, "l/logs", streamMetadata(0, nil))
+	put(bob, "l/logs/tmp/a", streamMetadata(0, nil))
+
+	// nothing has expired yet
+	assert.NoError(t, s.ApplyLifecycle(ctx, time.Now()))
+	assert.True(t, exists(alice, "l/logs/tmp/a"))
+	assert.True(t, exists(alice, "s0/logs/c"))
+
+	assert.NoError(t, s.ApplyLifecycle(ctx, time.Now().Add(2*lifecycle.Day)))
+	assert.False(t, exists(alice, "s0/logs/tmp/a"))
+	assert.False(t, exists(alice, "l/logs/tmp/a"))
+	assert.True(t, exists(alice, "l/logs/b"))
+	assert.True(t, exists(alice, "s0/logs/c"))
+	assert.True(t, exists(alice, "l/other/tmp/a"))
+	assert.True(t, exists(bob, "l/logs/tmp/a"))
+
+	assert.NoError(t, s.ApplyLifecycle(ctx, time.Now().Add(8*lifecycle.Day)))
+	assert.False(t, exists(alice, "s0/logs/c"))
+	assert.False(t, exists(alice, "s1/logs/c"))
+	assert.True(t, exists(alice, "l/logs"))
+	assert.True(t, exists(alice, "l/logs/b"))
+
+	usage, err := s.Usage(ctx, &pb.UsageRequest{APIKey: alice})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), usage.GetObjectCount())
+}
+
+func TestApplyLifecycleVersions(t *testing.T) {
+	s := newTestServer(teststore.New())
+	apiKey := newTestAPIKey(t, newTestRootKey(t))
+	now := time.Now()
+
+	rules, err := lifecycle.Encode([]lifecycle.Rule{{ID: "versions", Prefix: "ver/", ExpirationDays: 5}})
+	assert.NoError(t, err)
+	metadata, err := gogoproto.Marshal(&objects.SerializableMeta{
+		UserDefined: map[string]string{lifecycle.MetadataKey: rules},
+	})
+	assert.NoError(t, err)
+	bucketMetadata, err := gogoproto.Marshal(&streamspb.MetaStreamInfo{Metadata: metadata})
+	assert.NoError(t, err)
+
+	put := func(path string, metadata []byte) {
+		_, err := s.Put(ctx, &pb.PutRequest{
+			Path:    path,
+			Pointer: &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte(path), Metadata: metadata},
+			APIKey:  apiKey,
+		})
+		assert.NoError(t, err)
+	}
+	exists := func(path string) bool {
+		_, err := s.Get(ctx, &pb.GetRequest{Path: path, APIKey: apiKey})
+		if status.Code(err) == codes.NotFound {
+			return false
+		}
+		assert.NoError(t, err)
+		return true
+	}
+	// versionID returns the ID of a version created the given number of
+	// days ago
+	versionID := func(days int) string {
+		created := now.Add(-time.Duration(days) * lifecycle.Day)
+		return fmt.Sprintf("%016x%08x", uint64(math.MaxInt64-created.UnixNano()), 0)
+	}
+
+	history := "l/logs/" + objects.VersionsDir + "/"
+	put("l/logs", bucketMetadata)
+	put("l/logs/ver/a", nil)
+	// replaced by the latest version, created now
+	put(history+"ver/a/"+versionID(10), nil)
+	// replaced 10 days ago, with the only reference to a chunk
+	put("c/logs/x", nil)
+	_, err = s.Put(ctx, &pb.PutRequest{
+		Path:    history + "ver/a/" + versionID(20),
+		Pointer: &pb.Pointer{References: []string{"c/logs/x"}},
+		APIKey:  apiKey,
+	})
+	assert.NoError(t, err)
+	// without a latest version, replaced when created
+	put(history+"ver/a/b/"+versionID(3), nil)
+	put(history+"ver/a/c/"+versionID(6), nil)
+	put(history+"other/"+versionID(30), nil)
+
+	assert.NoError(t, s.ApplyLifecycle(ctx, now))
+	assert.True(t, exists("l/logs/ver/a"))
+	assert.True(t, exists(history+"ver/a/"+versionID(10)))
+	assert.False(t, exists(history+"ver/a/"+versionID(20)))
+	assert.False(t, exists("c/logs/x"))
+	assert.True(t, exists(history+"ver/a/b/"+versionID(3)))
+	assert.False(t, exists(history+"ver/a/c/"+versionID(6)))
+	assert.True(t, exists(history+"other/"+versionID(30)))
+}
+
+func TestApplyLifecycleOrphanChunks(t *testing.T) {
+	s := NewServer(teststore.New(), zap.NewNop(), Config{
+		APISecret:            base58.Encode(testSecret),
+		MaxInlineSegmentSize: 8000,
+		OrphanChunkAge:       lifecycle.Day,
+	})
+	apiKey := newTestAPIKey(t, newTestRootKey(t))
+
+	put := func(path string, references ...string) {
+		_, err := s.Put(ctx, &pb.PutRequest{
+			Path:    path,
+			Pointer: &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte(path), References: references},
+			APIKey:  apiKey,
+		})
+		assert.NoError(t, err)
+	}
+	exists := func(path string) bool {
+		_, err := s.Get(ctx, &pb.GetRequest{Path: path, APIKey: apiKey})
+		if status.Code(err) == codes.NotFound {
+			return false
+		}
+		assert.NoError(t, err)
+		return true
+	}
+
+	put("c/bucket/orphan")
+	put("c/bucket/used")
+	put("l/bucket/a", "c/bucket/used")
+
+	// the orphans are given time to be referenced
+	assert.NoError(t, s.ApplyLifecycle(ctx, time.Now()))
+	assert.True(t, exists("c/bucket/orphan"))
+
+	assert.NoError(t, s.ApplyLifecycle(ctx, time.Now().Add(2*lifecycle.Day)))
+	assert.False(t, exists("c/bucket/orphan"))
+	assert.True(t, exists("c/bucket/used"))
+	assert.True(t, exists("l/bucket/a"))
+}
+
+func TestAcquireLease(t *testing.T) {
+	db := teststore.New()
+	key := lifecycleLease
+	now := time.Now()
+
+	for i, tt := range []struct {
+		holder string
+		now    time.Time
+		held   bool
+	}{
+		{"a", now, true},
+		{"b", now.Add(time.Minute), false},
+		// renewed
+		{"a", now.Add(time.Hour), true},
+		{"b", now.Add(time.Hour + time.Minute), false},
+		// expired
+		{"b", now.Add(3 * time.Hour), true},
+		{"a", now.Add(3 * time.Hour), false},
+	} {
+		held, err := acquireLease(ctx, db, key, tt.holder, tt.now, time.Hour)
+		if assert.NoError(t, err, i) {
+			assert.Equal(t, tt.held, held, i)
+		}
+	}
+}
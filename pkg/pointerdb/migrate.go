@@ -0,0 +1,192 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+)
+
+// layoutVersion is the version of the layout of the pointer store the
+// server expects
+const layoutVersion = 3
+
+// versionKey is the key the layout version of the pointer store is kept at
+var versionKey = reservedKey("meta", "version")
+
+// migrate brings the layout of the pointer store up to date. The pointers
+// stored before they were namespaced by project are moved to the project
+// legacyProjectID, which may only be empty when there are none, and the
+// usage of every project is computed. Then the pointers stored before the
+// indexes were kept with them are indexed, and the usage counters stored
+// before they were sharded are moved to the first shard.
+func (s *Server) migrate(ctx context.Context, legacyProjectID string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	version := 0
+	value, err := s.DB.Get(ctx, versionKey)
+	switch {
+	case err == nil:
+		version, err = strconv.Atoi(string(value))
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	case !storage.ErrKeyNotFound.Has(err):
+		return err
+	}
+	if version >= layoutVersion {
+		return nil
+	}
+
+	if version < 1 {
+		if err = s.moveLegacyPointers(ctx, legacyProjectID); err != nil {
+			return err
+		}
+		if err = s.computeUsage(ctx); err != nil {
+			return err
+		}
+	}
+	if version < 2 {
+		if err = s.buildIndex(ctx); err != nil {
+			return err
+		}
+	}
+	if version < 3 {
+		if err = s.shardUsage(ctx); err != nil {
+			return err
+		}
+	}
+	return s.DB.Put(ctx, versionKey, storage.Value(strconv.Itoa(layoutVersion)))
+}
+
+// moveLegacyPointers moves the pointers without a project to the project
+// projectID
+func (s *Server) moveLegacyPointers(ctx context.Context, projectID string) error {
+	moved := 0
+	err := s.forEach(ctx, nil, true, func(item storage.ListItem) error {
+		if !IsPointerKey(item.Key) {
+			return nil
+		}
+		if _, _, ok := splitProjectKey(item.Key); ok {
+			return nil
+		}
+		if projectID == "" {
+			return Error.New("%s isn't in a project, configure the legacy api key to migrate it", item.Key)
+		}
+
+		path := item.Key.String()
+		err := storage.RetryTxn(ctx, s.DB, func(txn storage.Txn) error {
+			value, err := txn.Get(item.Key)
+			if err != nil {
+				return err
+			}
+			if err = txn.Delete(item.Key); err != nil {
+				return err
+			}
+			return txn.Put(projectKey(projectID, path), value)
+		})
+		if err != nil {
+			return err
+		}
+		moved++
+		return nil
+	})
+	if moved > 0 {
+		s.logger.Sugar().Infof("moved %d pointers to project %s", moved, projectID)
+	}
+	return err
+}
+
+// computeUsage sets the usage counters of every project to the usage of its
+// pointers
+func (s *Server) computeUsage(ctx context.Context) error {
+	usages := map[string]*pb.UsageResponse{}
+	err := s.forEach(ctx, nil, true, func(item storage.ListItem) error {
+		projectID, path, ok := splitProjectKey(item.Key)
+		if !ok {
+			return nil
+		}
+		pointer := &pb.Pointer{}
+		if err := proto.Unmarshal(item.Value, pointer); err != nil {
+			return Error.Wrap(err)
+		}
+		usage, ok := usages[projectID]
+		if !ok {
+			usage = &pb.UsageResponse{}
+			usages[projectID] = usage
+		}
+		delta := usageDelta(path, nil, pointer)
+		usage.StoredBytes += delta.StoredBytes
+		usage.ObjectCount += delta.ObjectCount
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for projectID, usage := range usages {
+		value, err := proto.Marshal(usage)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		// the whole usage goes to the first shard, the others are reset
+		items := storage.Items{{Key: usageKey(projectID, 0), Value: value}}
+		for shard := 1; shard < usageShards; shard++ {
+			items = append(items, storage.ListItem{Key: usageKey(projectID, shard)})
+		}
+		if err = s.DB.PutBatch(ctx, items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shardUsage moves the usage counters kept in a single key per project to
+// the first shard of the project
+func (s *Server) shardUsage(ctx context.Context) error {
+	return s.forEach(ctx, usagePrefix, false, func(item storage.ListItem) error {
+		if item.IsPrefix {
+			// the shards of a project
+			return nil
+		}
+		projectID := item.Key.String()
+		usage, err := unmarshalUsage(item.Value, nil)
+		if err != nil {
+			return err
+		}
+		return storage.RetryTxn(ctx, s.DB, func(txn storage.Txn) error {
+			if err := txn.Delete(reservedKey("usage", projectID)); err != nil {
+				return err
+			}
+			return addUsage(txn, projectID, 0, usage)
+		})
+	})
+}
+
+// buildIndex adds the index entries of every pointer. The entries already
+// there are kept.
+func (s *Server) buildIndex(ctx context.Context) error {
+	return s.forEach(ctx, nil, true, func(item storage.ListItem) error {
+		projectID, path, ok := splitProjectKey(item.Key)
+		if !ok {
+			return nil
+		}
+		return storage.RetryTxn(ctx, s.DB, func(txn storage.Txn) error {
+			pointer, err := getPointer(txn, item.Key)
+			if err != nil {
+				if storage.ErrKeyNotFound.Has(err) {
+					// deleted since it was listed
+					return nil
+				}
+				return err
+			}
+			return updateIndex(txn, projectID, []pointerChange{{path: path, new: pointer}})
+		})
+	})
+}
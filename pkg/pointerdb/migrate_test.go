@@ -0,0 +1,111 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestMigrate(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
+	other := newTestAPIKey(t, newTestRootKey(t))
+
+	// the pointers stored before the projects existed
+	for path, pointer := range map[string]*pb.Pointer{
+		"l/bucket": {},
+		"s0/bucket/a": {Size: 10, Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{
+			RemotePieces: []*pb.RemotePiece{{NodeId: "node"}},
+		}},
+		"l/bucket/a": {Size: 5},
+	} {
+		value, err := proto.Marshal(pointer)
+		assert.NoError(t, err)
+		assert.NoError(t, db.Put(ctx, storage.Key(path), value))
+	}
+	_, err := s.Put(ctx, &pb.PutRequest{Path: "l/bucket/b", Pointer: &pb.Pointer{Size: 1}, APIKey: other})
+	assert.NoError(t, err)
+
+	// they can't be moved without a project
+	assert.Error(t, s.migrate(ctx, ""))
+
+	assert.NoError(t, s.migrate(ctx, root.ProjectID()))
+	for _, path := range []string{"l/bucket", "s0/bucket/a", "l/bucket/a"} {
+		_, err := s.Get(ctx, &pb.GetRequest{Path: path, APIKey: apiKey})
+		assert.NoError(t, err, path)
+		_, err = db.Get(ctx, storage.Key(path))
+		assert.True(t, storage.ErrKeyNotFound.Has(err), path)
+	}
+
+	usage, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 15, ObjectCount: 1}, usage)
+	usage, err = s.Usage(ctx, &pb.UsageRequest{APIKey: other})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 1, ObjectCount: 1}, usage)
+
+	byNode, err := s.IterateByNode(ctx, &pb.IterateByNodeRequest{NodeId: "node", APIKey: apiKey})
+	assert.NoError(t, err)
+	if assert.Len(t, byNode.GetItems(), 1) {
+		assert.Equal(t, "s0/bucket/a", byNode.GetItems()[0].GetPath())
+	}
+
+	// a migrated store isn't migrated again
+	assert.NoError(t, db.Put(ctx, storage.Key("l/later"), nil))
+	assert.NoError(t, s.migrate(ctx, ""))
+}
+
+func TestMigrateIndex(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	root := newTestRootKey(t)
+
+	// a pointer stored before the indexes were kept with the pointers
+	value, err := proto.Marshal(remoteTestPointer("piece", "node"))
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(ctx, projectKey(root.ProjectID(), "s0/bucket/a"), value))
+	assert.NoError(t, db.Put(ctx, versionKey, storage.Value("1")))
+
+	assert.NoError(t, s.migrate(ctx, ""))
+	segments, _, err := s.SegmentsOnNode(ctx, "node", "", 10)
+	if assert.NoError(t, err) && assert.Len(t, segments, 1) {
+		assert.Equal(t, root.ProjectID()+"/s0/bucket/a", segments[0].Key)
+	}
+	version, err := db.Get(ctx, versionKey)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Value("3"), version)
+}
+
+func TestMigrateUsageShards(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
+
+	// a usage counter stored before it was sharded
+	value, err := proto.Marshal(&pb.UsageResponse{StoredBytes: 10, ObjectCount: 1})
+	assert.NoError(t, err)
+	legacyKey := reservedKey("usage", root.ProjectID())
+	assert.NoError(t, db.Put(ctx, legacyKey, value))
+	assert.NoError(t, db.Put(ctx, versionKey, storage.Value("2")))
+
+	assert.NoError(t, s.migrate(ctx, ""))
+	_, err = db.Get(ctx, legacyKey)
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/a", Pointer: &pb.Pointer{Size: 5}, APIKey: apiKey})
+	assert.NoError(t, err)
+	usage, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 15, ObjectCount: 2}, usage)
+}
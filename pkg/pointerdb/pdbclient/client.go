@@ -5,8 +5,11 @@ package pdbclient
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -38,6 +41,14 @@ type ListItem struct {
 	IsPrefix bool
 }
 
+// IterateItem is a single segment returned by IterateExpiring and
+// IterateByNode. Cursor is the startAfter of the page following the item.
+type IterateItem struct {
+	Path    p.Path
+	Pointer *pb.Pointer
+	Cursor  string
+}
+
 // Client services offerred for the interface
 type Client interface {
 	Put(ctx context.Context, path p.Path, pointer *pb.Pointer) error
@@ -46,6 +57,32 @@ type Client interface {
 		recursive bool, limit int, metaFlags uint32) (
 		items []ListItem, more bool, err error)
 	Delete(ctx context.Context, path p.Path) error
+	Usage(ctx context.Context) (storedBytes, objectCount int64, err error)
+	IterateExpiring(ctx context.Context, before time.Time, startAfter string, limit int) (
+		items []IterateItem, more bool, err error)
+	IterateByNode(ctx context.Context, nodeID, startAfter string, limit int) (
+		items []IterateItem, more bool, err error)
+	Iterate(ctx context.Context, prefix p.Path, startAfter string, limit int,
+		fn func(item IterateItem) error) (more bool, err error)
+	BatchGet(ctx context.Context, paths []p.Path) ([]*pb.Pointer, error)
+	BatchDelete(ctx context.Context, paths []p.Path) error
+	BatchMove(ctx context.Context, from, to []p.Path) error
+}
+
+type referrerKey struct{}
+
+// WithReferrer returns a context whose requests for chunk segments are
+// authorized through the segment at path referring to them, as the path
+// caveats of api keys can't cover the chunks shared within a bucket
+func WithReferrer(ctx context.Context, path p.Path) context.Context {
+	return context.WithValue(ctx, referrerKey{}, path.String())
+}
+
+// referrer returns the path of the segment referring to the chunk segments
+// of the requests made with ctx, empty if none
+func referrer(ctx context.Context) string {
+	path, _ := ctx.Value(referrerKey{}).(string)
+	return path
 }
 
 // NewClient initializes a new pointerdb client
@@ -82,7 +119,14 @@ func clientConnection(serverAddr string, opts ...grpc.DialOption) (pb.PointerDBC
 func (pdb *PointerDB) Put(ctx context.Context, path p.Path, pointer *pb.Pointer) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	_, err = pdb.grpcClient.Put(ctx, &pb.PutRequest{Path: path.String(), Pointer: pointer, APIKey: pdb.APIKey})
+	_, err = pdb.grpcClient.Put(ctx, &pb.PutRequest{Path: path.String(), Pointer: pointer,
+		APIKey: pdb.APIKey, Referrer: referrer(ctx)})
+	switch status.Code(err) {
+	case codes.AlreadyExists:
+		return ErrChunkExists.Wrap(err)
+	case codes.FailedPrecondition:
+		return ErrMissingChunk.Wrap(err)
+	}
 
 	return err
 }
@@ -91,7 +135,8 @@ func (pdb *PointerDB) Put(ctx context.Context, path p.Path, pointer *pb.Pointer)
 func (pdb *PointerDB) Get(ctx context.Context, path p.Path) (pointer *pb.Pointer, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	res, err := pdb.grpcClient.Get(ctx, &pb.GetRequest{Path: path.String(), APIKey: pdb.APIKey})
+	res, err := pdb.grpcClient.Get(ctx, &pb.GetRequest{Path: path.String(), APIKey: pdb.APIKey,
+		Referrer: referrer(ctx)})
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			return nil, storage.ErrKeyNotFound.Wrap(err)
@@ -145,6 +190,205 @@ func (pdb *PointerDB) Delete(ctx context.Context, path p.Path) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	_, err = pdb.grpcClient.Delete(ctx, &pb.DeleteRequest{Path: path.String(), APIKey: pdb.APIKey})
+	if status.Code(err) == codes.FailedPrecondition {
+		return ErrChunkReferenced.Wrap(err)
+	}
 
 	return err
 }
+
+// Usage returns the stored bytes and object count of the project of the
+// APIKey
+func (pdb *PointerDB) Usage(ctx context.Context) (storedBytes, objectCount int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := pdb.grpcClient.Usage(ctx, &pb.UsageRequest{APIKey: pdb.APIKey})
+	if err != nil {
+		return 0, 0, Error.Wrap(err)
+	}
+
+	return res.GetStoredBytes(), res.GetObjectCount(), nil
+}
+
+// IterateExpiring returns the segments of the project of the APIKey
+// expiring before the given time, ordered by expiration
+func (pdb *PointerDB) IterateExpiring(ctx context.Context, before time.Time, startAfter string, limit int) (
+	items []IterateItem, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ts, err := ptypes.TimestampProto(before)
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	res, err := pdb.grpcClient.IterateExpiring(ctx, &pb.IterateExpiringRequest{
+		Before:     ts,
+		StartAfter: startAfter,
+		Limit:      int32(limit),
+		APIKey:     pdb.APIKey,
+	})
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	return iterateItems(res), res.GetMore(), nil
+}
+
+// IterateByNode returns the segments of the project of the APIKey with a
+// piece on the node
+func (pdb *PointerDB) IterateByNode(ctx context.Context, nodeID, startAfter string, limit int) (
+	items []IterateItem, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := pdb.grpcClient.IterateByNode(ctx, &pb.IterateByNodeRequest{
+		NodeId:     nodeID,
+		StartAfter: startAfter,
+		Limit:      int32(limit),
+		APIKey:     pdb.APIKey,
+	})
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	return iterateItems(res), res.GetMore(), nil
+}
+
+// Iterate calls fn with the full pointers under prefix, up to limit of them
+// or all of them when limit is 0. The paths are relative to prefix. When fn
+// or the stream fail, the iteration can be resumed by passing the cursor of
+// the last item as startAfter.
+func (pdb *PointerDB) Iterate(ctx context.Context, prefix p.Path, startAfter string, limit int,
+	fn func(item IterateItem) error) (more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := pdb.grpcClient.Iterate(ctx, &pb.IterateRequest{
+		Prefix:     prefix.String(),
+		StartAfter: startAfter,
+		Limit:      int32(limit),
+		APIKey:     pdb.APIKey,
+	})
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return more, nil
+		}
+		if err != nil {
+			return false, Error.Wrap(err)
+		}
+
+		for _, item := range iterateItems(res) {
+			if err := fn(item); err != nil {
+				return false, err
+			}
+		}
+		more = res.GetMore()
+	}
+}
+
+// BatchGet returns the pointers at paths, with nil for paths without a
+// pointer
+func (pdb *PointerDB) BatchGet(ctx context.Context, paths []p.Path) (pointers []*pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, batch := range batchPaths(paths) {
+		res, err := pdb.grpcClient.BatchGet(ctx, &pb.BatchGetRequest{Paths: batch, APIKey: pdb.APIKey,
+			Referrer: referrer(ctx)})
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		if len(res.GetItems()) != len(batch) {
+			return nil, Error.New("expected %d pointers, got %d", len(batch), len(res.GetItems()))
+		}
+		for _, item := range res.GetItems() {
+			pointers = append(pointers, item.GetPointer())
+		}
+	}
+
+	return pointers, nil
+}
+
+// BatchDelete deletes the pointers at paths, paths without a pointer are
+// ignored
+func (pdb *PointerDB) BatchDelete(ctx context.Context, paths []p.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, batch := range batchPaths(paths) {
+		_, err = pdb.grpcClient.BatchDelete(ctx, &pb.BatchDeleteRequest{Paths: batch, APIKey: pdb.APIKey,
+			Referrer: referrer(ctx)})
+		if status.Code(err) == codes.FailedPrecondition {
+			return ErrChunkReferenced.Wrap(err)
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// BatchMove moves the pointers at from to the paths at the same index of
+// to, replacing the pointers there. Either all pointers are moved or none
+// is, so at most storage.LookupLimit pointers can be moved at once.
+func (pdb *PointerDB) BatchMove(ctx context.Context, from, to []p.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(from) > storage.LookupLimit {
+		return Error.New("can't move more than %d pointers at once", storage.LookupLimit)
+	}
+	req := &pb.BatchMoveRequest{APIKey: pdb.APIKey}
+	for _, path := range from {
+		req.FromPaths = append(req.FromPaths, path.String())
+	}
+	for _, path := range to {
+		req.ToPaths = append(req.ToPaths, path.String())
+	}
+
+	_, err = pdb.grpcClient.BatchMove(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound:
+		return storage.ErrKeyNotFound.Wrap(err)
+	case codes.FailedPrecondition:
+		return ErrChunkReferenced.Wrap(err)
+	default:
+		return Error.Wrap(err)
+	}
+}
+
+// batchPaths splits paths into batches the server accepts
+func batchPaths(paths []p.Path) (batches [][]string) {
+	for len(paths) > 0 {
+		n := len(paths)
+		if n > storage.LookupLimit {
+			n = storage.LookupLimit
+		}
+		batch := make([]string, n)
+		for i, path := range paths[:n] {
+			batch[i] = path.String()
+		}
+		batches = append(batches, batch)
+		paths = paths[n:]
+	}
+	return batches
+}
+
+func iterateItems(res *pb.IterateResponse) []IterateItem {
+	list := res.GetItems()
+	items := make([]IterateItem, len(list))
+	for i, itm := range list {
+		items[i] = IterateItem{
+			Path:    p.New(itm.GetPath()),
+			Pointer: itm.GetPointer(),
+			Cursor:  itm.GetCursor(),
+		}
+	}
+	return items
+}
@@ -7,18 +7,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	p "storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/storage/meta"
 	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
 )
 
 const (
@@ -279,3 +285,329 @@ func TestDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for i, tt := range []struct {
+		APIKey    []byte
+		resp      *pb.UsageResponse
+		err       error
+		errString string
+	}{
+		{[]byte("wrong key"), nil, ErrUnauthenticated, Error.Wrap(ErrUnauthenticated).Error()},
+		{[]byte("abc123"), &pb.UsageResponse{StoredBytes: 1024, ObjectCount: 2}, nil, ""},
+	} {
+		usageRequest := pb.UsageRequest{APIKey: tt.APIKey}
+
+		errTag := fmt.Sprintf("Test case #%d", i)
+		gc := NewMockPointerDBClient(ctrl)
+		pdb := PointerDB{grpcClient: gc, APIKey: tt.APIKey}
+
+		gc.EXPECT().Usage(gomock.Any(), &usageRequest).Return(tt.resp, tt.err)
+
+		storedBytes, objectCount, err := pdb.Usage(ctx)
+
+		if err != nil {
+			assert.EqualError(t, err, tt.errString, errTag)
+		} else {
+			assert.NoError(t, err, errTag)
+			assert.Equal(t, tt.resp.StoredBytes, storedBytes, errTag)
+			assert.Equal(t, tt.resp.ObjectCount, objectCount, errTag)
+		}
+	}
+}
+
+func TestIterateExpiring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	before := time.Now()
+	ts, err := ptypes.TimestampProto(before)
+	assert.NoError(t, err)
+
+	for i, tt := range []struct {
+		APIKey    []byte
+		resp      *pb.IterateResponse
+		err       error
+		errString string
+	}{
+		{[]byte("wrong key"), nil, ErrUnauthenticated, Error.Wrap(ErrUnauthenticated).Error()},
+		{[]byte("abc123"), &pb.IterateResponse{}, nil, ""},
+		{[]byte("abc123"), &pb.IterateResponse{
+			Items: []*pb.IterateResponse_Item{
+				{Path: "l/bucket/a", Pointer: &pb.Pointer{Size: 1}, Cursor: "cursor-a"},
+				{Path: "l/bucket/b", Pointer: &pb.Pointer{Size: 2}, Cursor: "cursor-b"},
+			},
+			More: true,
+		}, nil, ""},
+	} {
+		request := pb.IterateExpiringRequest{Before: ts, StartAfter: "start", Limit: 2, APIKey: tt.APIKey}
+
+		errTag := fmt.Sprintf("Test case #%d", i)
+		gc := NewMockPointerDBClient(ctrl)
+		pdb := PointerDB{grpcClient: gc, APIKey: tt.APIKey}
+
+		gc.EXPECT().IterateExpiring(gomock.Any(), &request).Return(tt.resp, tt.err)
+
+		items, more, err := pdb.IterateExpiring(ctx, before, "start", 2)
+
+		if err != nil {
+			assert.EqualError(t, err, tt.errString, errTag)
+		} else {
+			assert.NoError(t, err, errTag)
+			assert.Equal(t, tt.resp.GetMore(), more, errTag)
+			assert.Equal(t, len(tt.resp.GetItems()), len(items), errTag)
+			for i, item := range items {
+				assert.Equal(t, tt.resp.GetItems()[i].GetPath(), item.Path.String(), errTag)
+				assert.Equal(t, tt.resp.GetItems()[i].GetPointer(), item.Pointer, errTag)
+				assert.Equal(t, tt.resp.GetItems()[i].GetCursor(), item.Cursor, errTag)
+			}
+		}
+	}
+}
+
+func TestIterateByNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for i, tt := range []struct {
+		APIKey    []byte
+		resp      *pb.IterateResponse
+		err       error
+		errString string
+	}{
+		{[]byte("wrong key"), nil, ErrUnauthenticated, Error.Wrap(ErrUnauthenticated).Error()},
+		{[]byte("abc123"), &pb.IterateResponse{
+			Items: []*pb.IterateResponse_Item{
+				{Path: "l/bucket/a", Pointer: &pb.Pointer{Size: 1}, Cursor: "l/bucket/a"},
+			},
+		}, nil, ""},
+	} {
+		request := pb.IterateByNodeRequest{NodeId: "node", Limit: 10, APIKey: tt.APIKey}
+
+		errTag := fmt.Sprintf("Test case #%d", i)
+		gc := NewMockPointerDBClient(ctrl)
+		pdb := PointerDB{grpcClient: gc, APIKey: tt.APIKey}
+
+		gc.EXPECT().IterateByNode(gomock.Any(), &request).Return(tt.resp, tt.err)
+
+		items, more, err := pdb.IterateByNode(ctx, "node", "", 10)
+
+		if err != nil {
+			assert.EqualError(t, err, tt.errString, errTag)
+		} else {
+			assert.NoError(t, err, errTag)
+			assert.False(t, more, errTag)
+			assert.Equal(t, []IterateItem{
+				{Path: p.New("l/bucket/a"), Pointer: &pb.Pointer{Size: 1}, Cursor: "l/bucket/a"},
+			}, items, errTag)
+		}
+	}
+}
+
+// iterateClient replays responses as the stream of the Iterate rpc
+type iterateClient struct {
+	grpc.ClientStream
+	responses []*pb.IterateResponse
+	err       error
+}
+
+func (stream *iterateClient) Recv() (*pb.IterateResponse, error) {
+	if len(stream.responses) == 0 {
+		if stream.err != nil {
+			return nil, stream.err
+		}
+		return nil, io.EOF
+	}
+	resp := stream.responses[0]
+	stream.responses = stream.responses[1:]
+	return resp, nil
+}
+
+func TestIterate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	responses := []*pb.IterateResponse{
+		{Items: []*pb.IterateResponse_Item{
+			{Path: "a", Pointer: &pb.Pointer{Size: 1}, Cursor: "a"},
+			{Path: "b", Pointer: &pb.Pointer{Size: 2}, Cursor: "b"},
+		}, More: true},
+		{Items: []*pb.IterateResponse_Item{
+			{Path: "c", Pointer: &pb.Pointer{Size: 3}, Cursor: "c"},
+		}, More: true},
+	}
+
+	for i, tt := range []struct {
+		responses []*pb.IterateResponse
+		streamErr error
+		paths     []string
+		more      bool
+		errString string
+	}{
+		{nil, nil, nil, false, ""},
+		{responses, nil, []string{"a", "b", "c"}, true, ""},
+		{responses[:1], ErrUnauthenticated, []string{"a", "b"}, false, Error.Wrap(ErrUnauthenticated).Error()},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		gc := NewMockPointerDBClient(ctrl)
+		pdb := PointerDB{grpcClient: gc, APIKey: []byte("abc123")}
+
+		request := pb.IterateRequest{Prefix: "l/bucket", StartAfter: "start", Limit: 3, APIKey: []byte("abc123")}
+		gc.EXPECT().Iterate(gomock.Any(), &request).Return(&iterateClient{responses: tt.responses, err: tt.streamErr}, nil)
+
+		var paths []string
+		more, err := pdb.Iterate(ctx, p.New("l/bucket"), "start", 3, func(item IterateItem) error {
+			paths = append(paths, item.Path.String())
+			assert.Equal(t, item.Path.String(), item.Cursor, errTag)
+			return nil
+		})
+
+		assert.Equal(t, tt.paths, paths, errTag)
+		if tt.errString != "" {
+			assert.EqualError(t, err, tt.errString, errTag)
+		} else {
+			assert.NoError(t, err, errTag)
+			assert.Equal(t, tt.more, more, errTag)
+		}
+	}
+}
+
+func TestBatchGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockPointerDBClient(ctrl)
+	pdb := PointerDB{grpcClient: gc, APIKey: []byte("abc123")}
+
+	// more paths than the server accepts at once are split into batches
+	var paths []p.Path
+	var first, second []string
+	for i := 0; i < storage.LookupLimit+1; i++ {
+		path := p.New(fmt.Sprintf("s%d/bucket/object", i))
+		paths = append(paths, path)
+		if i < storage.LookupLimit {
+			first = append(first, path.String())
+		} else {
+			second = append(second, path.String())
+		}
+	}
+
+	items := func(paths []string) *pb.BatchGetResponse {
+		resp := &pb.BatchGetResponse{}
+		for i, path := range paths {
+			item := &pb.BatchGetResponse_Item{Path: path}
+			if i%2 == 0 {
+				item.Pointer = &pb.Pointer{Size: int64(i)}
+			}
+			resp.Items = append(resp.Items, item)
+		}
+		return resp
+	}
+
+	gomock.InOrder(
+		gc.EXPECT().BatchGet(gomock.Any(), &pb.BatchGetRequest{Paths: first, APIKey: []byte("abc123")}).Return(items(first), nil),
+		gc.EXPECT().BatchGet(gomock.Any(), &pb.BatchGetRequest{Paths: second, APIKey: []byte("abc123")}).Return(items(second), nil),
+	)
+
+	pointers, err := pdb.BatchGet(ctx, paths)
+	assert.NoError(t, err)
+	if assert.Len(t, pointers, storage.LookupLimit+1) {
+		assert.Equal(t, int64(2), pointers[2].GetSize())
+		assert.Nil(t, pointers[1])
+		assert.Equal(t, int64(0), pointers[storage.LookupLimit].GetSize())
+	}
+
+	gc.EXPECT().BatchGet(gomock.Any(), gomock.Any()).Return(nil, ErrUnauthenticated)
+	_, err = pdb.BatchGet(ctx, paths[:1])
+	assert.EqualError(t, err, Error.Wrap(ErrUnauthenticated).Error())
+
+	gc.EXPECT().BatchGet(gomock.Any(), gomock.Any()).Return(&pb.BatchGetResponse{}, nil)
+	_, err = pdb.BatchGet(ctx, paths[:1])
+	assert.Error(t, err)
+
+	pointers, err = pdb.BatchGet(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, pointers)
+}
+
+func TestBatchDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for i, tt := range []struct {
+		APIKey    []byte
+		err       error
+		errString string
+	}{
+		{[]byte("wrong key"), ErrUnauthenticated, Error.Wrap(ErrUnauthenticated).Error()},
+		{[]byte("abc123"), nil, ""},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		gc := NewMockPointerDBClient(ctrl)
+		pdb := PointerDB{grpcClient: gc, APIKey: tt.APIKey}
+
+		request := pb.BatchDeleteRequest{Paths: []string{"s0/bucket/a", "s1/bucket/a"}, APIKey: tt.APIKey}
+		gc.EXPECT().BatchDelete(gomock.Any(), &request).Return(&pb.BatchDeleteResponse{}, tt.err)
+
+		err := pdb.BatchDelete(ctx, []p.Path{p.New("s0/bucket/a"), p.New("s1/bucket/a")})
+		if tt.errString != "" {
+			assert.EqualError(t, err, tt.errString, errTag)
+		} else {
+			assert.NoError(t, err, errTag)
+		}
+	}
+}
+
+func TestChunkErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockPointerDBClient(ctrl)
+	pdb := PointerDB{grpcClient: gc}
+	path := p.New("c/chunk")
+
+	gc.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.AlreadyExists, "exists"))
+	assert.True(t, ErrChunkExists.Has(pdb.Put(ctx, path, &pb.Pointer{})))
+
+	gc.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.FailedPrecondition, "missing"))
+	assert.True(t, ErrMissingChunk.Has(pdb.Put(ctx, p.New("l/bucket/file"), &pb.Pointer{})))
+
+	gc.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.FailedPrecondition, "referenced"))
+	assert.True(t, ErrChunkReferenced.Has(pdb.Delete(ctx, path)))
+
+	gc.EXPECT().BatchDelete(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.FailedPrecondition, "referenced"))
+	assert.True(t, ErrChunkReferenced.Has(pdb.BatchDelete(ctx, []p.Path{path})))
+}
+
+func TestReferrer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockPointerDBClient(ctrl)
+	pdb := PointerDB{grpcClient: gc, APIKey: []byte("abc123")}
+	chunk := p.New("c/bucket/x")
+	ctx := WithReferrer(ctx, p.New("l/bucket/file"))
+
+	gomock.InOrder(
+		gc.EXPECT().Put(gomock.Any(), &pb.PutRequest{Path: "c/bucket/x", Pointer: &pb.Pointer{},
+			APIKey: []byte("abc123"), Referrer: "l/bucket/file"}).Return(&pb.PutResponse{}, nil),
+		gc.EXPECT().Get(gomock.Any(), &pb.GetRequest{Path: "c/bucket/x",
+			APIKey: []byte("abc123"), Referrer: "l/bucket/file"}).Return(nil, status.Error(codes.NotFound, "missing")),
+		gc.EXPECT().BatchGet(gomock.Any(), &pb.BatchGetRequest{Paths: []string{"c/bucket/x"},
+			APIKey: []byte("abc123"), Referrer: "l/bucket/file"}).Return(&pb.BatchGetResponse{
+			Items: []*pb.BatchGetResponse_Item{{Path: "c/bucket/x"}},
+		}, nil),
+		gc.EXPECT().BatchDelete(gomock.Any(), &pb.BatchDeleteRequest{Paths: []string{"c/bucket/x"},
+			APIKey: []byte("abc123"), Referrer: "l/bucket/file"}).Return(&pb.BatchDeleteResponse{}, nil),
+	)
+
+	assert.NoError(t, pdb.Put(ctx, chunk, &pb.Pointer{}))
+	_, err := pdb.Get(ctx, chunk)
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+	_, err = pdb.BatchGet(ctx, []p.Path{chunk})
+	assert.NoError(t, err)
+	assert.NoError(t, pdb.BatchDelete(ctx, []p.Path{chunk}))
+}
@@ -9,3 +9,15 @@ import (
 
 // Error is the pdbclient error class
 var Error = errs.Class("pointerdb client error")
+
+var (
+	// ErrChunkExists is returned by Put when the chunk segment at the path
+	// exists already
+	ErrChunkExists = errs.Class("chunk exists")
+	// ErrMissingChunk is returned by Put when a chunk segment referenced by
+	// the pointer doesn't exist
+	ErrMissingChunk = errs.Class("missing chunk")
+	// ErrChunkReferenced is returned by Delete and BatchDelete when a chunk
+	// segment to delete is still referenced
+	ErrChunkReferenced = errs.Class("chunk referenced")
+)
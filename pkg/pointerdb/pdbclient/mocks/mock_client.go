@@ -7,6 +7,7 @@ package mock_pointerdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	paths "storj.io/storj/pkg/paths"
@@ -37,6 +38,43 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// BatchDelete mocks base method
+func (m *MockClient) BatchDelete(arg0 context.Context, arg1 []paths.Path) error {
+	ret := m.ctrl.Call(m, "BatchDelete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchDelete indicates an expected call of BatchDelete
+func (mr *MockClientMockRecorder) BatchDelete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDelete", reflect.TypeOf((*MockClient)(nil).BatchDelete), arg0, arg1)
+}
+
+// BatchGet mocks base method
+func (m *MockClient) BatchGet(arg0 context.Context, arg1 []paths.Path) ([]*pointerdb.Pointer, error) {
+	ret := m.ctrl.Call(m, "BatchGet", arg0, arg1)
+	ret0, _ := ret[0].([]*pointerdb.Pointer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGet indicates an expected call of BatchGet
+func (mr *MockClientMockRecorder) BatchGet(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGet", reflect.TypeOf((*MockClient)(nil).BatchGet), arg0, arg1)
+}
+
+// BatchMove mocks base method
+func (m *MockClient) BatchMove(arg0 context.Context, arg1, arg2 []paths.Path) error {
+	ret := m.ctrl.Call(m, "BatchMove", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchMove indicates an expected call of BatchMove
+func (mr *MockClientMockRecorder) BatchMove(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchMove", reflect.TypeOf((*MockClient)(nil).BatchMove), arg0, arg1, arg2)
+}
+
 // Delete mocks base method
 func (m *MockClient) Delete(arg0 context.Context, arg1 paths.Path) error {
 	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
@@ -62,6 +100,47 @@ func (mr *MockClientMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0, arg1)
 }
 
+// Iterate mocks base method
+func (m *MockClient) Iterate(arg0 context.Context, arg1 paths.Path, arg2 string, arg3 int, arg4 func(pdbclient.IterateItem) error) (bool, error) {
+	ret := m.ctrl.Call(m, "Iterate", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Iterate indicates an expected call of Iterate
+func (mr *MockClientMockRecorder) Iterate(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockClient)(nil).Iterate), arg0, arg1, arg2, arg3, arg4)
+}
+
+// IterateByNode mocks base method
+func (m *MockClient) IterateByNode(arg0 context.Context, arg1, arg2 string, arg3 int) ([]pdbclient.IterateItem, bool, error) {
+	ret := m.ctrl.Call(m, "IterateByNode", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]pdbclient.IterateItem)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// IterateByNode indicates an expected call of IterateByNode
+func (mr *MockClientMockRecorder) IterateByNode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateByNode", reflect.TypeOf((*MockClient)(nil).IterateByNode), arg0, arg1, arg2, arg3)
+}
+
+// IterateExpiring mocks base method
+func (m *MockClient) IterateExpiring(arg0 context.Context, arg1 time.Time, arg2 string, arg3 int) ([]pdbclient.IterateItem, bool, error) {
+	ret := m.ctrl.Call(m, "IterateExpiring", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]pdbclient.IterateItem)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// IterateExpiring indicates an expected call of IterateExpiring
+func (mr *MockClientMockRecorder) IterateExpiring(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateExpiring", reflect.TypeOf((*MockClient)(nil).IterateExpiring), arg0, arg1, arg2, arg3)
+}
+
 // List mocks base method
 func (m *MockClient) List(arg0 context.Context, arg1, arg2, arg3 paths.Path, arg4 bool, arg5 int, arg6 uint32) ([]pdbclient.ListItem, bool, error) {
 	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
@@ -87,3 +166,17 @@ func (m *MockClient) Put(arg0 context.Context, arg1 paths.Path, arg2 *pointerdb.
 func (mr *MockClientMockRecorder) Put(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockClient)(nil).Put), arg0, arg1, arg2)
 }
+
+// Usage mocks base method
+func (m *MockClient) Usage(arg0 context.Context) (int64, int64, error) {
+	ret := m.ctrl.Call(m, "Usage", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Usage indicates an expected call of Usage
+func (mr *MockClientMockRecorder) Usage(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockClient)(nil).Usage), arg0)
+}
@@ -39,6 +39,60 @@ func (m *MockPointerDBClient) EXPECT() *MockPointerDBClientMockRecorder {
 	return m.recorder
 }
 
+// BatchDelete mocks base method
+func (m *MockPointerDBClient) BatchDelete(arg0 context.Context, arg1 *pointerdb.BatchDeleteRequest, arg2 ...grpc.CallOption) (*pointerdb.BatchDeleteResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchDelete", varargs...)
+	ret0, _ := ret[0].(*pointerdb.BatchDeleteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDelete indicates an expected call of BatchDelete
+func (mr *MockPointerDBClientMockRecorder) BatchDelete(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDelete", reflect.TypeOf((*MockPointerDBClient)(nil).BatchDelete), varargs...)
+}
+
+// BatchGet mocks base method
+func (m *MockPointerDBClient) BatchGet(arg0 context.Context, arg1 *pointerdb.BatchGetRequest, arg2 ...grpc.CallOption) (*pointerdb.BatchGetResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGet", varargs...)
+	ret0, _ := ret[0].(*pointerdb.BatchGetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGet indicates an expected call of BatchGet
+func (mr *MockPointerDBClientMockRecorder) BatchGet(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGet", reflect.TypeOf((*MockPointerDBClient)(nil).BatchGet), varargs...)
+}
+
+// BatchMove mocks base method
+func (m *MockPointerDBClient) BatchMove(arg0 context.Context, arg1 *pointerdb.BatchMoveRequest, arg2 ...grpc.CallOption) (*pointerdb.BatchMoveResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchMove", varargs...)
+	ret0, _ := ret[0].(*pointerdb.BatchMoveResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchMove indicates an expected call of BatchMove
+func (mr *MockPointerDBClientMockRecorder) BatchMove(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchMove", reflect.TypeOf((*MockPointerDBClient)(nil).BatchMove), varargs...)
+}
+
 // Delete mocks base method
 func (m *MockPointerDBClient) Delete(arg0 context.Context, arg1 *pointerdb.DeleteRequest, arg2 ...grpc.CallOption) (*pointerdb.DeleteResponse, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -75,6 +129,60 @@ func (mr *MockPointerDBClientMockRecorder) Get(arg0, arg1 interface{}, arg2 ...i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPointerDBClient)(nil).Get), varargs...)
 }
 
+// Iterate mocks base method
+func (m *MockPointerDBClient) Iterate(arg0 context.Context, arg1 *pointerdb.IterateRequest, arg2 ...grpc.CallOption) (pointerdb.PointerDB_IterateClient, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Iterate", varargs...)
+	ret0, _ := ret[0].(pointerdb.PointerDB_IterateClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Iterate indicates an expected call of Iterate
+func (mr *MockPointerDBClientMockRecorder) Iterate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockPointerDBClient)(nil).Iterate), varargs...)
+}
+
+// IterateByNode mocks base method
+func (m *MockPointerDBClient) IterateByNode(arg0 context.Context, arg1 *pointerdb.IterateByNodeRequest, arg2 ...grpc.CallOption) (*pointerdb.IterateResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IterateByNode", varargs...)
+	ret0, _ := ret[0].(*pointerdb.IterateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IterateByNode indicates an expected call of IterateByNode
+func (mr *MockPointerDBClientMockRecorder) IterateByNode(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateByNode", reflect.TypeOf((*MockPointerDBClient)(nil).IterateByNode), varargs...)
+}
+
+// IterateExpiring mocks base method
+func (m *MockPointerDBClient) IterateExpiring(arg0 context.Context, arg1 *pointerdb.IterateExpiringRequest, arg2 ...grpc.CallOption) (*pointerdb.IterateResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IterateExpiring", varargs...)
+	ret0, _ := ret[0].(*pointerdb.IterateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IterateExpiring indicates an expected call of IterateExpiring
+func (mr *MockPointerDBClientMockRecorder) IterateExpiring(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateExpiring", reflect.TypeOf((*MockPointerDBClient)(nil).IterateExpiring), varargs...)
+}
+
 // List mocks base method
 func (m *MockPointerDBClient) List(arg0 context.Context, arg1 *pointerdb.ListRequest, arg2 ...grpc.CallOption) (*pointerdb.ListResponse, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -120,3 +228,21 @@ func (mr *MockPointerDBClientMockRecorder) Put(arg0, arg1 interface{}, arg2 ...i
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockPointerDBClient)(nil).Put), varargs...)
 }
+
+// Usage mocks base method
+func (m *MockPointerDBClient) Usage(arg0 context.Context, arg1 *pointerdb.UsageRequest, arg2 ...grpc.CallOption) (*pointerdb.UsageResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Usage", varargs...)
+	ret0, _ := ret[0].(*pointerdb.UsageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage
+func (mr *MockPointerDBClientMockRecorder) Usage(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockPointerDBClient)(nil).Usage), varargs...)
+}
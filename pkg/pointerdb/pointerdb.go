@@ -5,16 +5,20 @@ package pointerdb
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	base58 "github.com/jbenet/go-base58"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
-	"storj.io/storj/pkg/pointerdb/auth"
+	"storj.io/storj/pkg/macaroon"
 	"storj.io/storj/pkg/storage/meta"
 	pb "storj.io/storj/protos/pointerdb"
 	"storj.io/storj/storage"
@@ -25,11 +29,14 @@ var (
 	segmentError = errs.Class("segment error")
 )
 
-// Server implements the network state RPC service
+// Server implements the network state RPC service. Pointers are stored
+// under the ID of the project of the API key used to put them, next to the
+// usage counters and the indexes of the projects.
 type Server struct {
 	DB     storage.KeyValueStore
 	logger *zap.Logger
 	config Config
+	secret []byte
 }
 
 // NewServer creates instance of Server
@@ -38,15 +45,66 @@ func NewServer(db storage.KeyValueStore, logger *zap.Logger, c Config) *Server {
 		DB:     db,
 		logger: logger,
 		config: c,
+		secret: base58.Decode(c.APISecret),
 	}
 }
 
-func (s *Server) validateAuth(APIKey []byte) error {
-	if !auth.ValidateAPIKey(string(APIKey)) {
-		s.logger.Error("unauthorized request: ", zap.Error(status.Errorf(codes.Unauthenticated, "Invalid API credential")))
-		return status.Errorf(codes.Unauthenticated, "Invalid API credential")
+// validateAuth checks that APIKey is signed by the satellite and its caveats
+// allow op on path. It returns the ID of the project of the API key.
+func (s *Server) validateAuth(APIKey []byte, op macaroon.ActionType, path string) (projectID string, err error) {
+	key, err := macaroon.ParseAPIKey(string(APIKey))
+	if err == nil {
+		err = s.checkPath(key, op, path)
 	}
-	return nil
+	if err != nil {
+		s.logger.Error("unauthorized request: ", zap.Error(err))
+		return "", status.Errorf(codes.Unauthenticated, "Invalid API credential")
+	}
+	return key.ProjectID(), nil
+}
+
+// validatePath checks that APIKey is signed by the satellite and allows op
+// on path. If path is a chunk segment and referrer isn't empty, the access
+// to the chunk is checked through referrer instead. It returns the ID of
+// the project of the API key.
+func (s *Server) validatePath(ctx context.Context, APIKey []byte, op macaroon.ActionType, path, referrer string) (projectID string, err error) {
+	if referrer == "" || !isChunk(path) {
+		return s.validateAuth(APIKey, op, path)
+	}
+	key, err := macaroon.ParseAPIKey(string(APIKey))
+	if err == nil {
+		err = s.checkChunk(ctx, key, op, path, referrer)
+	}
+	if err != nil {
+		s.logger.Error("unauthorized request: ", zap.Error(err))
+		return "", status.Errorf(codes.Unauthenticated, "Invalid API credential")
+	}
+	return key.ProjectID(), nil
+}
+
+// checkPath returns an error if key wasn't signed by the satellite or its
+// caveats disallow op on path
+func (s *Server) checkPath(key *macaroon.APIKey, op macaroon.ActionType, path string) error {
+	bucket, bucketPath := splitPath(path)
+	return key.Check(s.secret, macaroon.Action{
+		Op:     op,
+		Bucket: bucket,
+		Path:   bucketPath,
+		Time:   time.Now(),
+	})
+}
+
+// splitPath splits a path of the form segment/bucket/path into the bucket
+// and the path within the bucket
+func splitPath(path string) (bucket, bucketPath []byte) {
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) > 1 {
+		bucket = []byte(parts[1])
+	}
+	if len(parts) > 2 {
+		bucketPath = []byte(parts[2])
+	}
+	return bucket, bucketPath
 }
 
 func (s *Server) validateSegment(req *pb.PutRequest) error {
@@ -72,11 +130,15 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutRespo
 	s.logger.Debug("entering pointerdb put")
 
 	err = s.validateSegment(req)
+	if err == nil {
+		err = validateReferences(req.GetPath(), req.GetPointer())
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, err.Error())
 	}
 
-	if err = s.validateAuth(req.GetAPIKey()); err != nil {
+	projectID, err := s.validatePath(ctx, req.GetAPIKey(), macaroon.ActionWrite, req.GetPath(), req.GetReferrer())
+	if err != nil {
 		return nil, err
 	}
 
@@ -92,7 +154,10 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutRespo
 	// TODO(kaloyan): make sure that we know we are overwriting the pointer!
 	// In such case we should delete the pieces of the old segment if it was
 	// a remote one.
-	if err = s.DB.Put([]byte(req.GetPath()), pointerBytes); err != nil {
+	if err = s.putPointer(ctx, projectID, req.GetPath(), req.GetPointer(), pointerBytes); err != nil {
+		if code := referenceCode(err); code != codes.OK {
+			return nil, status.Errorf(code, err.Error())
+		}
 		s.logger.Error("err putting pointer", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
@@ -106,11 +171,12 @@ func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (resp *pb.GetRespo
 	defer mon.Task()(&ctx)(&err)
 	s.logger.Debug("entering pointerdb get")
 
-	if err = s.validateAuth(req.GetAPIKey()); err != nil {
+	projectID, err := s.validatePath(ctx, req.GetAPIKey(), macaroon.ActionRead, req.GetPath(), req.GetReferrer())
+	if err != nil {
 		return nil, err
 	}
 
-	pointerBytes, err := s.DB.Get([]byte(req.GetPath()))
+	pointerBytes, err := s.DB.Get(ctx, projectKey(projectID, req.GetPath()))
 	if err != nil {
 		if storage.ErrKeyNotFound.Has(err) {
 			return nil, status.Errorf(codes.NotFound, err.Error())
@@ -129,10 +195,6 @@ func (s *Server) List(ctx context.Context, req *pb.ListRequest) (resp *pb.ListRe
 	defer mon.Task()(&ctx)(&err)
 	s.logger.Debug("entering pointerdb list")
 
-	if err = s.validateAuth(req.APIKey); err != nil {
-		return nil, err
-	}
-
 	var prefix storage.Key
 	if req.Prefix != "" {
 		prefix = storage.Key(req.Prefix)
@@ -141,8 +203,14 @@ func (s *Server) List(ctx context.Context, req *pb.ListRequest) (resp *pb.ListRe
 		}
 	}
 
-	rawItems, more, err := storage.ListV2(s.DB, storage.ListOptions{
-		Prefix:       prefix, //storage.Key(req.Prefix),
+	projectID, err := s.validateAuth(req.APIKey, macaroon.ActionList, prefix.String())
+	if err != nil {
+		return nil, err
+	}
+	prefix = projectKey(projectID, prefix.String())
+
+	rawItems, more, err := storage.ListV2(ctx, s.DB, storage.ListOptions{
+		Prefix:       prefix,
 		StartAfter:   storage.Key(req.StartAfter),
 		EndBefore:    storage.Key(req.EndBefore),
 		Recursive:    req.Recursive,
@@ -216,15 +284,468 @@ func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (resp *pb.De
 	defer mon.Task()(&ctx)(&err)
 	s.logger.Debug("entering pointerdb delete")
 
-	if err = s.validateAuth(req.GetAPIKey()); err != nil {
+	projectID, err := s.validateAuth(req.GetAPIKey(), macaroon.ActionDelete, req.GetPath())
+	if err != nil {
 		return nil, err
 	}
 
-	err = s.DB.Delete([]byte(req.GetPath()))
+	err = s.putPointer(ctx, projectID, req.GetPath(), nil, nil)
 	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		if code := referenceCode(err); code != codes.OK {
+			return nil, status.Errorf(code, err.Error())
+		}
 		s.logger.Error("err deleting path and pointer", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 	s.logger.Debug("deleted pointer at path: " + req.GetPath())
 	return &pb.DeleteResponse{}, nil
 }
+
+// Usage returns the stored bytes and object count of the project of the API
+// key
+func (s *Server) Usage(ctx context.Context, req *pb.UsageRequest) (resp *pb.UsageResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb usage")
+
+	projectID, err := s.validateAuth(req.GetAPIKey(), macaroon.ActionRead, "")
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := getUsage(ctx, s.DB, projectID)
+	if err != nil {
+		s.logger.Error("err getting usage", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return usage, nil
+}
+
+// IterateExpiring returns the segments of the project of the API key
+// expiring before req.Before
+func (s *Server) IterateExpiring(ctx context.Context, req *pb.IterateExpiringRequest) (resp *pb.IterateResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb iterate expiring")
+
+	projectID, err := s.validateAuth(req.GetAPIKey(), macaroon.ActionList, "")
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := ptypes.Timestamp(req.GetBefore())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	paths, cursors, more, err := s.listExpiring(ctx, projectID, before, req.GetStartAfter(), int(req.GetLimit()))
+	if err != nil {
+		s.logger.Error("err listing expiring segments", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return s.iterateResponse(ctx, projectID, paths, cursors, more)
+}
+
+// IterateByNode returns the segments of the project of the API key with a
+// piece on req.NodeId
+func (s *Server) IterateByNode(ctx context.Context, req *pb.IterateByNodeRequest) (resp *pb.IterateResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb iterate by node")
+
+	projectID, err := s.validateAuth(req.GetAPIKey(), macaroon.ActionList, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetNodeId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "missing node id")
+	}
+
+	paths, more, err := s.listByNode(ctx, projectID, req.GetNodeId(), req.GetStartAfter(), int(req.GetLimit()))
+	if err != nil {
+		s.logger.Error("err listing segments of node", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return s.iterateResponse(ctx, projectID, paths, paths, more)
+}
+
+// iterateResponse looks up the pointers at paths of the project. Paths
+// deleted since they were listed are skipped.
+func (s *Server) iterateResponse(ctx context.Context, projectID string, paths, cursors []string, more bool) (*pb.IterateResponse, error) {
+	resp := &pb.IterateResponse{More: more}
+	for i, path := range paths {
+		pointerBytes, err := s.DB.Get(ctx, projectKey(projectID, path))
+		if err != nil {
+			if storage.ErrKeyNotFound.Has(err) {
+				continue
+			}
+			s.logger.Error("err getting pointer", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+
+		pointer := &pb.Pointer{}
+		if err = proto.Unmarshal(pointerBytes, pointer); err != nil {
+			s.logger.Error("err unmarshaling pointer", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		resp.Items = append(resp.Items, &pb.IterateResponse_Item{
+			Path:    path,
+			Pointer: pointer,
+			Cursor:  cursors[i],
+		})
+	}
+	return resp, nil
+}
+
+// Iterate streams the full pointers under the prefix of the request in
+// batches. The path of every item is relative to the prefix and is also its
+// cursor, so an interrupted stream can be resumed after the last item
+// received.
+func (s *Server) Iterate(req *pb.IterateRequest, stream pb.PointerDB_IterateServer) (err error) {
+	ctx := stream.Context()
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb iterate")
+
+	var prefix storage.Key
+	if req.GetPrefix() != "" {
+		prefix = storage.Key(req.GetPrefix())
+		if prefix[len(prefix)-1] != storage.Delimiter {
+			prefix = append(prefix, storage.Delimiter)
+		}
+	}
+
+	projectID, err := s.validateAuth(req.GetAPIKey(), macaroon.ActionList, prefix.String())
+	if err != nil {
+		return err
+	}
+	prefix = projectKey(projectID, prefix.String())
+
+	startAfter := req.GetStartAfter()
+	remaining := int(req.GetLimit())
+	for {
+		limit := storage.LookupLimit
+		if remaining > 0 && remaining < limit {
+			limit = remaining
+		}
+
+		rawItems, more, err := storage.ListV2(ctx, s.DB, storage.ListOptions{
+			Prefix:       prefix,
+			StartAfter:   storage.Key(startAfter),
+			Recursive:    true,
+			Limit:        limit,
+			IncludeValue: true,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "ListV2: %v", err)
+		}
+		if len(rawItems) == 0 {
+			return nil
+		}
+
+		resp := &pb.IterateResponse{More: more}
+		for _, rawItem := range rawItems {
+			pointer := &pb.Pointer{}
+			if err = proto.Unmarshal(rawItem.Value, pointer); err != nil {
+				s.logger.Error("err unmarshaling pointer", zap.Error(err))
+				return status.Errorf(codes.Internal, err.Error())
+			}
+			resp.Items = append(resp.Items, &pb.IterateResponse_Item{
+				Path:    rawItem.Key.String(),
+				Pointer: pointer,
+				Cursor:  rawItem.Key.String(),
+			})
+		}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+
+		if remaining > 0 {
+			remaining -= len(rawItems)
+			if remaining == 0 {
+				return nil
+			}
+		}
+		if !more {
+			return nil
+		}
+		startAfter = rawItems[len(rawItems)-1].Key.String()
+	}
+}
+
+// BatchGet returns the pointers at the paths of the request at once
+func (s *Server) BatchGet(ctx context.Context, req *pb.BatchGetRequest) (resp *pb.BatchGetResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb batch get")
+
+	projectID, err := s.validateBatch(ctx, req.GetAPIKey(), macaroon.ActionRead, req.GetPaths(), req.GetReferrer())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(storage.Keys, len(req.GetPaths()))
+	for i, path := range req.GetPaths() {
+		keys[i] = projectKey(projectID, path)
+	}
+	values, err := s.DB.GetAll(ctx, keys)
+	if err != nil {
+		s.logger.Error("err getting pointers", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp = &pb.BatchGetResponse{}
+	for i, path := range req.GetPaths() {
+		item := &pb.BatchGetResponse_Item{Path: path}
+		if values[i] != nil {
+			item.Pointer = &pb.Pointer{}
+			if err = proto.Unmarshal(values[i], item.Pointer); err != nil {
+				s.logger.Error("err unmarshaling pointer", zap.Error(err))
+				return nil, status.Errorf(codes.Internal, err.Error())
+			}
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
+
+// BatchDelete deletes the pointers at the paths of the request at once.
+// Paths without a pointer are ignored.
+func (s *Server) BatchDelete(ctx context.Context, req *pb.BatchDeleteRequest) (resp *pb.BatchDeleteResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb batch delete")
+
+	projectID, err := s.validateBatch(ctx, req.GetAPIKey(), macaroon.ActionDelete, req.GetPaths(), req.GetReferrer())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.deletePointers(ctx, projectID, req.GetPaths()); err != nil {
+		if code := referenceCode(err); code != codes.OK {
+			return nil, status.Errorf(code, err.Error())
+		}
+		s.logger.Error("err deleting pointers", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	s.logger.Debug(fmt.Sprintf("deleted %d pointers", len(req.GetPaths())))
+	return &pb.BatchDeleteResponse{}, nil
+}
+
+// BatchMove moves the pointers at the from paths of the request to the to
+// paths at the same index, replacing the pointers there, in a single
+// transaction. Either all pointers are moved or none is.
+func (s *Server) BatchMove(ctx context.Context, req *pb.BatchMoveRequest) (resp *pb.BatchMoveResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb batch move")
+
+	from, to := req.GetFromPaths(), req.GetToPaths()
+	if len(from) != len(to) {
+		return nil, status.Errorf(codes.InvalidArgument, "moving %d paths to %d paths", len(from), len(to))
+	}
+	// a path may be moved from and to, e.g. to swap versions, but not moved
+	// from or to twice
+	for _, batch := range [][]string{from, to} {
+		seen := make(map[string]bool, len(batch))
+		for _, path := range batch {
+			if seen[path] {
+				return nil, status.Errorf(codes.InvalidArgument, "path %s is moved more than once", path)
+			}
+			seen[path] = true
+		}
+	}
+
+	projectID, err := s.validateBatch(ctx, req.GetAPIKey(), macaroon.ActionDelete, from, "")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.validateBatch(ctx, req.GetAPIKey(), macaroon.ActionWrite, to, ""); err != nil {
+		return nil, err
+	}
+
+	if err = s.movePointers(ctx, projectID, from, to); err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		if code := referenceCode(err); code != codes.OK {
+			return nil, status.Errorf(code, err.Error())
+		}
+		s.logger.Error("err moving pointers", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	s.logger.Debug(fmt.Sprintf("moved %d pointers", len(from)))
+	return &pb.BatchMoveResponse{}, nil
+}
+
+// validateBatch checks that APIKey allows op on all paths, which are at
+// most storage.LookupLimit, the chunk segments through referrer if not
+// empty. It returns the ID of the project of the API key.
+func (s *Server) validateBatch(ctx context.Context, APIKey []byte, op macaroon.ActionType, paths []string, referrer string) (projectID string, err error) {
+	if len(paths) > storage.LookupLimit {
+		return "", status.Errorf(codes.InvalidArgument, "too many paths: %d > %d", len(paths), storage.LookupLimit)
+	}
+	if len(paths) == 0 {
+		return s.validateAuth(APIKey, op, "")
+	}
+	for _, path := range paths {
+		projectID, err = s.validatePath(ctx, APIKey, op, path, referrer)
+		if err != nil {
+			return "", err
+		}
+	}
+	return projectID, nil
+}
+
+// pointerChange replaces the pointer old at path with new, encoded as
+// value. Either pointer may be nil.
+type pointerChange struct {
+	path     string
+	old, new *pb.Pointer
+	value    storage.Value
+}
+
+// putPointer replaces the pointer at path of the project with pointer and
+// updates the usage, the indexes and the chunk references of the project
+// accordingly, in a single transaction retried on conflicts with the other
+// servers sharing the store. If pointer is nil, the pointer at path is
+// deleted.
+func (s *Server) putPointer(ctx context.Context, projectID, path string, pointer *pb.Pointer, pointerBytes []byte) error {
+	return storage.RetryTxn(ctx, s.DB, func(txn storage.Txn) error {
+		old, err := getPointer(txn, projectKey(projectID, path))
+		if err != nil && (pointer == nil || !storage.ErrKeyNotFound.Has(err)) {
+			return err
+		}
+		if err = checkChunkChange(path, old, pointer); err != nil {
+			return err
+		}
+		return applyChanges(txn, projectID, []pointerChange{{path, old, pointer, pointerBytes}})
+	})
+}
+
+// deletePointers deletes the pointers at paths of the project at once and
+// updates the usage, the indexes and the chunk references of the project
+// accordingly, in a single transaction. Paths without a pointer are
+// ignored. Nothing is deleted if one of the paths is a chunk still
+// referenced.
+func (s *Server) deletePointers(ctx context.Context, projectID string, paths []string) error {
+	return storage.RetryTxn(ctx, s.DB, func(txn storage.Txn) error {
+		var changes []pointerChange
+		seen := make(map[string]bool, len(paths))
+		for _, path := range paths {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			old, err := getPointer(txn, projectKey(projectID, path))
+			if err != nil {
+				if storage.ErrKeyNotFound.Has(err) {
+					continue
+				}
+				return err
+			}
+			if err = checkChunkChange(path, old, nil); err != nil {
+				return err
+			}
+			changes = append(changes, pointerChange{path: path, old: old})
+		}
+		return applyChanges(txn, projectID, changes)
+	})
+}
+
+// movePointers moves the pointers at from of the project to the paths at
+// the same index of to, replacing the pointers there, and updates the
+// usage, the indexes and the chunk references of the project accordingly,
+// in a single transaction. The paths of from and those of to must be
+// distinct, a path may be in both, and every path of from must have a
+// pointer.
+func (s *Server) movePointers(ctx context.Context, projectID string, from, to []string) error {
+	vacated := make(map[string]bool, len(from))
+	for _, path := range from {
+		vacated[path] = true
+	}
+	return storage.RetryTxn(ctx, s.DB, func(txn storage.Txn) error {
+		// the pointers are removed from all paths before any is put, so a
+		// path moved from and to ends up with the pointer moved to it
+		removals := make([]pointerChange, 0, len(from))
+		puts := make([]pointerChange, 0, len(to))
+		for i := range from {
+			value, err := txn.Get(projectKey(projectID, from[i]))
+			if err != nil {
+				return err
+			}
+			pointer := &pb.Pointer{}
+			if err = proto.Unmarshal(value, pointer); err != nil {
+				return Error.Wrap(err)
+			}
+			var replaced *pb.Pointer
+			if !vacated[to[i]] {
+				replaced, err = getPointer(txn, projectKey(projectID, to[i]))
+				if err != nil && !storage.ErrKeyNotFound.Has(err) {
+					return err
+				}
+			}
+			if err = checkChunkChange(from[i], pointer, nil); err != nil {
+				return err
+			}
+			if err = checkChunkChange(to[i], replaced, pointer); err != nil {
+				return err
+			}
+			removals = append(removals, pointerChange{path: from[i], old: pointer})
+			puts = append(puts, pointerChange{path: to[i], old: replaced, new: pointer, value: value})
+		}
+		return applyChanges(txn, projectID, append(removals, puts...))
+	})
+}
+
+// getPointer returns the pointer at key as seen by txn
+func getPointer(txn storage.Txn, key storage.Key) (*pb.Pointer, error) {
+	value, err := txn.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	pointer := &pb.Pointer{}
+	if err = proto.Unmarshal(value, pointer); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return pointer, nil
+}
+
+// applyChanges writes the pointer changes of the project in txn, with the
+// usage, the index entries and the chunk references they change. The
+// chunks no longer referenced are deleted too.
+func applyChanges(txn storage.Txn, projectID string, changes []pointerChange) error {
+	var added, removed []string
+	for _, change := range changes {
+		var err error
+		if change.new != nil {
+			err = txn.Put(projectKey(projectID, change.path), change.value)
+		} else {
+			err = txn.Delete(projectKey(projectID, change.path))
+		}
+		if err != nil {
+			return err
+		}
+		added = append(added, change.new.GetReferences()...)
+		removed = append(removed, change.old.GetReferences()...)
+	}
+
+	released, err := updateReferences(txn, projectID, added, removed)
+	if err != nil {
+		return err
+	}
+	changes = append(changes, released...)
+
+	delta := &pb.UsageResponse{}
+	for _, change := range changes {
+		d := usageDelta(change.path, change.old, change.new)
+		delta.StoredBytes += d.StoredBytes
+		delta.ObjectCount += d.ObjectCount
+	}
+	shard := 0
+	if len(changes) > 0 {
+		shard = usageShard(changes[0].path)
+	}
+	if err := addUsage(txn, projectID, shard, delta); err != nil {
+		return err
+	}
+	return updateIndex(txn, projectID, changes)
+}
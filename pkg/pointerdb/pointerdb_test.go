@@ -4,45 +4,83 @@
 package pointerdb
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
+	base58 "github.com/jbenet/go-base58"
 	"github.com/stretchr/testify/assert"
 
+	"storj.io/storj/pkg/macaroon"
 	"storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/storage/meta"
+	macaroonpb "storj.io/storj/protos/macaroon"
 	pb "storj.io/storj/protos/pointerdb"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/teststore"
 )
 
 var (
-	ctx = context.Background()
+	ctx        = context.Background()
+	testSecret = []byte("test secret")
 )
 
+func newTestServer(db storage.KeyValueStore) *Server {
+	return NewServer(db, zap.NewNop(), Config{
+		APISecret:            base58.Encode(testSecret),
+		MaxInlineSegmentSize: 8000,
+	})
+}
+
+func newTestRootKey(t *testing.T) *macaroon.APIKey {
+	key, err := macaroon.NewAPIKey(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// newTestAPIKey returns a key of the project of root restricted by caveats
+func newTestAPIKey(t *testing.T, root *macaroon.APIKey, caveats ...macaroonpb.Caveat) []byte {
+	key := root
+	for _, caveat := range caveats {
+		var err error
+		key, err = key.Restrict(caveat)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return []byte(key.Serialize())
+}
+
 func TestServicePut(t *testing.T) {
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
 	for i, tt := range []struct {
 		apiKey    []byte
 		err       error
 		errString string
 	}{
-		{nil, nil, ""},
+		{apiKey, nil, ""},
 		{[]byte("wrong key"), nil, status.Errorf(codes.Unauthenticated, "Invalid API credential").Error()},
-		{nil, errors.New("put error"), status.Errorf(codes.Internal, "internal error").Error()},
+		{apiKey, errors.New("put error"), status.Errorf(codes.Internal, "internal error").Error()},
 	} {
 		errTag := fmt.Sprintf("Test case #%d", i)
 
 		db := teststore.New()
-		s := Server{DB: db, logger: zap.NewNop()}
+		s := newTestServer(db)
 
 		path := "a/b/c"
 		pr := pb.Pointer{}
@@ -63,19 +101,21 @@ func TestServicePut(t *testing.T) {
 }
 
 func TestServiceGet(t *testing.T) {
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
 	for i, tt := range []struct {
 		apiKey    []byte
 		err       error
 		errString string
 	}{
-		{nil, nil, ""},
+		{apiKey, nil, ""},
 		{[]byte("wrong key"), nil, status.Errorf(codes.Unauthenticated, "Invalid API credential").Error()},
-		{nil, errors.New("get error"), status.Errorf(codes.Internal, "internal error").Error()},
+		{apiKey, errors.New("get error"), status.Errorf(codes.Internal, "internal error").Error()},
 	} {
 		errTag := fmt.Sprintf("Test case #%d", i)
 
 		db := teststore.New()
-		s := Server{DB: db, logger: zap.NewNop()}
+		s := newTestServer(db)
 
 		path := "a/b/c"
 
@@ -83,7 +123,7 @@ func TestServiceGet(t *testing.T) {
 		prBytes, err := proto.Marshal(pr)
 		assert.NoError(t, err, errTag)
 
-		_ = db.Put(storage.Key(path), storage.Value(prBytes))
+		_ = db.Put(ctx, projectKey(root.ProjectID(), path), storage.Value(prBytes))
 
 		if tt.err != nil {
 			db.ForceError++
@@ -105,29 +145,34 @@ func TestServiceGet(t *testing.T) {
 }
 
 func TestServiceDelete(t *testing.T) {
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
 	for i, tt := range []struct {
 		apiKey    []byte
 		err       error
 		errString string
 	}{
-		{nil, nil, ""},
+		{apiKey, nil, ""},
 		{[]byte("wrong key"), nil, status.Errorf(codes.Unauthenticated, "Invalid API credential").Error()},
-		{nil, errors.New("delete error"), status.Errorf(codes.Internal, "internal error").Error()},
+		{apiKey, errors.New("delete error"), status.Errorf(codes.Internal, "internal error").Error()},
 	} {
 		errTag := fmt.Sprintf("Test case #%d", i)
 
 		path := "a/b/c"
 
+		prBytes, err := proto.Marshal(&pb.Pointer{Size: 123})
+		assert.NoError(t, err, errTag)
+
 		db := teststore.New()
-		_ = db.Put(storage.Key(path), storage.Value("hello"))
-		s := Server{DB: db, logger: zap.NewNop()}
+		_ = db.Put(ctx, projectKey(root.ProjectID(), path), storage.Value(prBytes))
+		s := newTestServer(db)
 
 		if tt.err != nil {
 			db.ForceError++
 		}
 
 		req := pb.DeleteRequest{Path: path, APIKey: tt.apiKey}
-		_, err := s.Delete(ctx, &req)
+		_, err = s.Delete(ctx, &req)
 
 		if err != nil {
 			assert.EqualError(t, err, tt.errString, errTag)
@@ -139,10 +184,12 @@ func TestServiceDelete(t *testing.T) {
 
 func TestServiceList(t *testing.T) {
 	db := teststore.New()
-	server := Server{DB: db, logger: zap.NewNop()}
+	server := newTestServer(db)
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
 
 	key := func(s string) storage.Key {
-		return storage.Key(paths.New(s).Bytes())
+		return projectKey(root.ProjectID(), string(paths.New(s).Bytes()))
 	}
 
 	pointer := &pb.Pointer{}
@@ -154,7 +201,7 @@ func TestServiceList(t *testing.T) {
 	}
 	pointerValue := storage.Value(pointerBytes)
 
-	err = storage.PutAll(db, []storage.ListItem{
+	err = storage.PutAll(ctx, db, []storage.ListItem{
 		{Key: key("sample.😶"), Value: pointerValue},
 		{Key: key("müsic"), Value: pointerValue},
 		{Key: key("müsic/söng1.mp3"), Value: pointerValue},
@@ -305,6 +352,9 @@ func TestServiceList(t *testing.T) {
 	//    pb.ListRequest{Prefix: "müsic/", StartAfter: "söng1.mp3", EndBefore: "söng4.mp3"},
 	//    failing database
 	for i, test := range tests {
+		if test.Request.APIKey == nil {
+			test.Request.APIKey = apiKey
+		}
 		resp, err := server.List(ctx, &test.Request)
 		if test.Error == nil {
 			if err != nil {
@@ -319,3 +369,885 @@ func TestServiceList(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceCaveats(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	root := newTestRootKey(t)
+
+	photos := macaroonpb.Caveat{
+		AllowedPaths: []*macaroonpb.CaveatPath{
+			{Bucket: []byte("bucket"), PathPrefix: []byte("photos/")},
+		},
+	}
+	readOnly := macaroonpb.Caveat{DisallowWrites: true, DisallowDeletes: true}
+	expired := macaroonpb.Caveat{NotAfter: time.Now().Add(-time.Hour).Unix()}
+
+	otherKey, err := macaroon.NewAPIKey([]byte("other secret"))
+	assert.NoError(t, err)
+
+	for i, tt := range []struct {
+		apiKey  []byte
+		path    string
+		allowed bool
+	}{
+		{newTestAPIKey(t, root), "l/bucket/notes.txt", true},
+		{newTestAPIKey(t, root, photos), "l/bucket/photos/cat.jpg", true},
+		{newTestAPIKey(t, root, photos), "s0/bucket/photos/cat.jpg", true},
+		{newTestAPIKey(t, root, photos), "l/bucket/notes.txt", false},
+		{newTestAPIKey(t, root, photos), "l/other/photos/cat.jpg", false},
+		{newTestAPIKey(t, root, photos, readOnly), "l/bucket/photos/cat.jpg", false},
+		{newTestAPIKey(t, root, expired), "l/bucket/notes.txt", false},
+		{[]byte(otherKey.Serialize()), "l/bucket/notes.txt", false},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+
+		_, err := s.Put(ctx, &pb.PutRequest{Path: tt.path, Pointer: &pb.Pointer{}, APIKey: tt.apiKey})
+		if tt.allowed {
+			assert.NoError(t, err, errTag)
+		} else {
+			assert.Equal(t, codes.Unauthenticated, status.Code(err), errTag)
+		}
+	}
+
+	// a key restricted to a prefix can read the bucket and list the prefix,
+	// but nothing outside of it
+	key := newTestAPIKey(t, root, photos, readOnly)
+	_ = db.Put(ctx, projectKey(root.ProjectID(), "l/bucket"), storage.Value(nil))
+
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/bucket", APIKey: key})
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/bucket/photos/cat.jpg", APIKey: key})
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/bucket/notes.txt", APIKey: key})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.List(ctx, &pb.ListRequest{Prefix: "l/bucket/photos", APIKey: key})
+	assert.NoError(t, err)
+	_, err = s.List(ctx, &pb.ListRequest{Prefix: "l/bucket", APIKey: key})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.Delete(ctx, &pb.DeleteRequest{Path: "l/bucket/photos/cat.jpg", APIKey: key})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServiceProjects(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+
+	alice := newTestAPIKey(t, newTestRootKey(t))
+	bob := newTestAPIKey(t, newTestRootKey(t))
+
+	put := func(apiKey []byte, path string, size int64) {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: &pb.Pointer{Size: size}, APIKey: apiKey})
+		assert.NoError(t, err)
+	}
+	usage := func(apiKey []byte) *pb.UsageResponse {
+		resp, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+		assert.NoError(t, err)
+		return resp
+	}
+
+	// both projects can have a bucket with the same name
+	put(alice, "l/photos", 0)
+	put(alice, "s0/photos/cat.jpg", 100)
+	put(alice, "l/photos/cat.jpg", 10)
+	put(bob, "l/photos", 0)
+	put(bob, "l/photos/cat.jpg", 20)
+
+	resp, err := s.Get(ctx, &pb.GetRequest{Path: "l/photos/cat.jpg", APIKey: bob})
+	assert.NoError(t, err)
+	pointer := &pb.Pointer{}
+	assert.NoError(t, proto.Unmarshal(resp.GetPointer(), pointer))
+	assert.Equal(t, int64(20), pointer.GetSize())
+
+	list, err := s.List(ctx, &pb.ListRequest{Prefix: "l", APIKey: bob})
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.ListResponse_Item{
+		{Path: "photos"},
+		{Path: "photos/", IsPrefix: true},
+	}, list.GetItems())
+
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 110, ObjectCount: 1}, usage(alice))
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 20, ObjectCount: 1}, usage(bob))
+
+	// overwriting a pointer only counts the size difference
+	put(alice, "l/photos/cat.jpg", 15)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 115, ObjectCount: 1}, usage(alice))
+
+	for _, path := range []string{"s0/photos/cat.jpg", "l/photos/cat.jpg"} {
+		_, err = s.Delete(ctx, &pb.DeleteRequest{Path: path, APIKey: alice})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, &pb.UsageResponse{}, usage(alice))
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 20, ObjectCount: 1}, usage(bob))
+
+	_, err = s.Delete(ctx, &pb.DeleteRequest{Path: "l/photos/cat.jpg", APIKey: alice})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// conflictingStore fails the transactions with a conflict until conflicts
+// reaches 0
+type conflictingStore struct {
+	storage.KeyValueStore
+	mu        sync.Mutex
+	conflicts int
+}
+
+func (store *conflictingStore) Txn(ctx context.Context, fn func(storage.Txn) error) error {
+	store.mu.Lock()
+	conflict := store.conflicts > 0
+	if conflict {
+		store.conflicts--
+	}
+	store.mu.Unlock()
+	if conflict {
+		return storage.ErrTxnConflict.New("conflict")
+	}
+	return store.KeyValueStore.Txn(ctx, fn)
+}
+
+func TestServiceConcurrent(t *testing.T) {
+	db := &conflictingStore{KeyValueStore: teststore.New(), conflicts: storage.TxnRetries - 1}
+	s := newTestServer(db)
+	apiKey := newTestAPIKey(t, newTestRootKey(t))
+
+	// the conflicting changes are retried and none of them is lost
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.Put(ctx, &pb.PutRequest{
+				Path:    fmt.Sprintf("l/bucket/%d", i),
+				Pointer: &pb.Pointer{Size: 10},
+				APIKey:  apiKey,
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	usage, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 100, ObjectCount: 10}, usage)
+
+	// the changes conflicting too often fail
+	db.conflicts = storage.TxnRetries
+	_, err = s.Delete(ctx, &pb.DeleteRequest{Path: "l/bucket/0", APIKey: apiKey})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+// optimisticStore runs the transactions without locking, and fails the ones
+// whose reads were changed by another transaction before they commit. The
+// first overlap transactions wait for each other before committing.
+type optimisticStore struct {
+	*teststore.Client
+	mu        sync.Mutex
+	overlap   sync.WaitGroup
+	waiting   int
+	conflicts int
+}
+
+func newOptimisticStore(overlap int) *optimisticStore {
+	store := &optimisticStore{Client: teststore.New(), waiting: overlap}
+	store.overlap.Add(overlap)
+	return store
+}
+
+func (store *optimisticStore) Txn(ctx context.Context, fn func(storage.Txn) error) error {
+	txn := &optimisticTxn{
+		ctx:    ctx,
+		store:  store.Client,
+		reads:  map[string]optimisticValue{},
+		writes: map[string]optimisticValue{},
+	}
+	if err := fn(txn); err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	wait := store.waiting > 0
+	if wait {
+		store.waiting--
+	}
+	store.mu.Unlock()
+	if wait {
+		store.overlap.Done()
+		store.overlap.Wait()
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for key, read := range txn.reads {
+		current, err := txn.get(storage.Key(key))
+		if err != nil {
+			return err
+		}
+		if current.found != read.found || !bytes.Equal(current.value, read.value) {
+			store.conflicts++
+			return storage.ErrTxnConflict.New("%s changed", key)
+		}
+	}
+	for key, write := range txn.writes {
+		var err error
+		if write.found {
+			err = store.Client.Put(ctx, storage.Key(key), write.value)
+		} else {
+			err = store.Client.Delete(ctx, storage.Key(key))
+		}
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+type optimisticValue struct {
+	value storage.Value
+	found bool
+}
+
+type optimisticTxn struct {
+	ctx    context.Context
+	store  *teststore.Client
+	reads  map[string]optimisticValue
+	writes map[string]optimisticValue
+}
+
+func (txn *optimisticTxn) get(key storage.Key) (optimisticValue, error) {
+	value, err := txn.store.Get(txn.ctx, key)
+	if storage.ErrKeyNotFound.Has(err) {
+		return optimisticValue{}, nil
+	}
+	return optimisticValue{value: value, found: true}, err
+}
+
+func (txn *optimisticTxn) Get(key storage.Key) (storage.Value, error) {
+	read, ok := txn.writes[string(key)]
+	if !ok {
+		var err error
+		if read, err = txn.get(key); err != nil {
+			return nil, err
+		}
+		txn.reads[string(key)] = read
+	}
+	if !read.found {
+		return nil, storage.ErrKeyNotFound.New(key.String())
+	}
+	return read.value, nil
+}
+
+func (txn *optimisticTxn) Put(key storage.Key, value storage.Value) error {
+	txn.writes[string(key)] = optimisticValue{value: value, found: true}
+	return nil
+}
+
+func (txn *optimisticTxn) Delete(key storage.Key) error {
+	txn.writes[string(key)] = optimisticValue{}
+	return nil
+}
+
+func TestServiceConcurrentWriters(t *testing.T) {
+	// paths of a project whose usage is kept in different shards
+	var paths []string
+	shards := map[int]bool{}
+	for i := 0; len(paths) < 8; i++ {
+		path := fmt.Sprintf("l/bucket/%d", i)
+		if shard := usageShard(path); !shards[shard] {
+			shards[shard] = true
+			paths = append(paths, path)
+		}
+	}
+	db := newOptimisticStore(len(paths))
+	s := newTestServer(db)
+	apiKey := newTestAPIKey(t, newTestRootKey(t))
+
+	// the writers of different paths of a project don't conflict on its
+	// usage
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			_, err := s.Put(ctx, &pb.PutRequest{
+				Path:    path,
+				Pointer: &pb.Pointer{Size: 10},
+				APIKey:  apiKey,
+			})
+			assert.NoError(t, err)
+		}(path)
+	}
+	wg.Wait()
+	assert.Equal(t, 0, db.conflicts)
+
+	usage, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 80, ObjectCount: 8}, usage)
+}
+
+func TestServiceIndexes(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+
+	alice := newTestAPIKey(t, newTestRootKey(t))
+	bob := newTestAPIKey(t, newTestRootKey(t))
+
+	now := time.Now()
+	pointer := func(expiration time.Time, nodes ...string) *pb.Pointer {
+		pointer := &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{}}
+		if !expiration.IsZero() {
+			pointer.ExpirationDate, _ = ptypes.TimestampProto(expiration)
+		}
+		for i, node := range nodes {
+			pointer.Remote.RemotePieces = append(pointer.Remote.RemotePieces,
+				&pb.RemotePiece{PieceNum: int32(i), NodeId: node})
+		}
+		return pointer
+	}
+	put := func(apiKey []byte, path string, pointer *pb.Pointer) {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: pointer, APIKey: apiKey})
+		assert.NoError(t, err)
+	}
+	paths := func(resp *pb.IterateResponse, err error) []string {
+		assert.NoError(t, err)
+		var paths []string
+		for _, item := range resp.GetItems() {
+			paths = append(paths, item.GetPath())
+		}
+		return paths
+	}
+	expiring := func(apiKey []byte, before time.Time) []string {
+		ts, err := ptypes.TimestampProto(before)
+		assert.NoError(t, err)
+		return paths(s.IterateExpiring(ctx, &pb.IterateExpiringRequest{Before: ts, APIKey: apiKey}))
+	}
+	byNode := func(apiKey []byte, nodeID string) []string {
+		return paths(s.IterateByNode(ctx, &pb.IterateByNodeRequest{NodeId: nodeID, APIKey: apiKey}))
+	}
+
+	put(alice, "l/bucket/a", pointer(now.Add(3*time.Hour), "node1", "node2"))
+	put(alice, "l/bucket/b", pointer(now.Add(time.Hour), "node2"))
+	put(alice, "l/bucket/c", pointer(time.Time{}, "node1"))
+	put(bob, "l/bucket/a", pointer(now.Add(time.Hour), "node1"))
+
+	assert.Equal(t, []string{"l/bucket/b", "l/bucket/a"}, expiring(alice, now.Add(4*time.Hour)))
+	assert.Equal(t, []string{"l/bucket/b"}, expiring(alice, now.Add(2*time.Hour)))
+	assert.Equal(t, []string(nil), expiring(alice, now))
+	assert.Equal(t, []string{"l/bucket/a", "l/bucket/c"}, byNode(alice, "node1"))
+	assert.Equal(t, []string{"l/bucket/a", "l/bucket/b"}, byNode(alice, "node2"))
+	assert.Equal(t, []string{"l/bucket/a"}, byNode(bob, "node1"))
+	assert.Equal(t, []string(nil), byNode(bob, "node2"))
+
+	// overwriting a pointer replaces its index entries
+	put(alice, "l/bucket/a", pointer(time.Time{}, "node3"))
+	assert.Equal(t, []string{"l/bucket/b"}, expiring(alice, now.Add(4*time.Hour)))
+	assert.Equal(t, []string{"l/bucket/c"}, byNode(alice, "node1"))
+	assert.Equal(t, []string{"l/bucket/a"}, byNode(alice, "node3"))
+
+	// deleting a pointer removes its index entries
+	_, err := s.Delete(ctx, &pb.DeleteRequest{Path: "l/bucket/b", APIKey: alice})
+	assert.NoError(t, err)
+	assert.Equal(t, []string(nil), expiring(alice, now.Add(4*time.Hour)))
+	assert.Equal(t, []string(nil), byNode(alice, "node2"))
+
+	// pages continue after the cursor of their last item
+	for _, path := range []string{"l/bucket/d", "l/bucket/e", "l/bucket/f"} {
+		put(alice, path, pointer(now.Add(time.Hour), "node4"))
+	}
+	var iterated []string
+	var startAfter string
+	for {
+		ts, _ := ptypes.TimestampProto(now.Add(2 * time.Hour))
+		resp, err := s.IterateExpiring(ctx, &pb.IterateExpiringRequest{
+			Before: ts, StartAfter: startAfter, Limit: 2, APIKey: alice,
+		})
+		assert.NoError(t, err)
+		iterated = append(iterated, paths(resp, err)...)
+		if !resp.GetMore() {
+			break
+		}
+		startAfter = resp.GetItems()[len(resp.GetItems())-1].GetCursor()
+	}
+	assert.Equal(t, []string{"l/bucket/d", "l/bucket/e", "l/bucket/f"}, iterated)
+
+	// a failed put changes neither the pointer, the usage nor the indexes
+	before, err := s.Usage(ctx, &pb.UsageRequest{APIKey: alice})
+	assert.NoError(t, err)
+	db.ForceError++
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/g", Pointer: pointer(time.Time{}, "node5"), APIKey: alice})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/bucket/g", APIKey: alice})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	after, err := s.Usage(ctx, &pb.UsageRequest{APIKey: alice})
+	assert.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.Equal(t, []string(nil), byNode(alice, "node5"))
+
+	_, err = s.IterateByNode(ctx, &pb.IterateByNodeRequest{APIKey: alice})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.IterateExpiring(ctx, &pb.IterateExpiringRequest{APIKey: []byte("wrong key")})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// iterateStream collects the responses of the Iterate rpc
+type iterateStream struct {
+	grpc.ServerStream
+	responses []*pb.IterateResponse
+}
+
+func (stream *iterateStream) Context() context.Context { return ctx }
+
+func (stream *iterateStream) Send(resp *pb.IterateResponse) error {
+	stream.responses = append(stream.responses, resp)
+	return nil
+}
+
+func TestServiceIterate(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	apiKey := newTestAPIKey(t, newTestRootKey(t))
+
+	var expected []string
+	for i := 0; i < storage.LookupLimit+10; i++ {
+		path := fmt.Sprintf("l/bucket/%04d", i)
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: &pb.Pointer{Size: int64(i)}, APIKey: apiKey})
+		assert.NoError(t, err)
+		expected = append(expected, path[len("l/bucket/"):])
+	}
+	_, err := s.Put(ctx, &pb.PutRequest{Path: "l/other", Pointer: &pb.Pointer{}, APIKey: apiKey})
+	assert.NoError(t, err)
+
+	iterate := func(req *pb.IterateRequest) (paths []string, more bool) {
+		req.APIKey = apiKey
+		stream := &iterateStream{}
+		assert.NoError(t, s.Iterate(req, stream))
+		for _, resp := range stream.responses {
+			for _, item := range resp.GetItems() {
+				assert.Equal(t, item.GetPath(), item.GetCursor())
+				paths = append(paths, item.GetPath())
+			}
+			more = resp.GetMore()
+		}
+		return paths, more
+	}
+
+	paths, more := iterate(&pb.IterateRequest{Prefix: "l/bucket"})
+	assert.Equal(t, expected, paths)
+	assert.False(t, more)
+
+	paths, more = iterate(&pb.IterateRequest{Prefix: "l/bucket", Limit: 5})
+	assert.Equal(t, expected[:5], paths)
+	assert.True(t, more)
+
+	paths, more = iterate(&pb.IterateRequest{Prefix: "l/bucket", StartAfter: expected[4], Limit: 5})
+	assert.Equal(t, expected[5:10], paths)
+	assert.True(t, more)
+
+	paths, more = iterate(&pb.IterateRequest{Prefix: "l/missing"})
+	assert.Equal(t, []string(nil), paths)
+	assert.False(t, more)
+
+	// the full pointers are returned
+	stream := &iterateStream{}
+	assert.NoError(t, s.Iterate(&pb.IterateRequest{Prefix: "l/bucket", Limit: 2, APIKey: apiKey}, stream))
+	assert.Equal(t, int64(1), stream.responses[0].GetItems()[1].GetPointer().GetSize())
+
+	err = s.Iterate(&pb.IterateRequest{APIKey: []byte("wrong key")}, &iterateStream{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServiceBatch(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
+
+	pointer := func(size int64, node string) *pb.Pointer {
+		return &pb.Pointer{
+			Type: pb.Pointer_REMOTE,
+			Size: size,
+			Remote: &pb.RemoteSegment{
+				RemotePieces: []*pb.RemotePiece{{NodeId: node}},
+			},
+		}
+	}
+	for i, path := range []string{"s0/bucket/a", "s1/bucket/a", "l/bucket/a"} {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: pointer(int64(i+1), "node"), APIKey: apiKey})
+		assert.NoError(t, err)
+	}
+
+	get, err := s.BatchGet(ctx, &pb.BatchGetRequest{
+		Paths:  []string{"l/bucket/a", "s2/bucket/a", "s0/bucket/a"},
+		APIKey: apiKey,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, get.GetItems(), 3) {
+		assert.Equal(t, "l/bucket/a", get.GetItems()[0].GetPath())
+		assert.Equal(t, int64(3), get.GetItems()[0].GetPointer().GetSize())
+		assert.Nil(t, get.GetItems()[1].GetPointer())
+		assert.Equal(t, int64(1), get.GetItems()[2].GetPointer().GetSize())
+	}
+
+	_, err = s.BatchDelete(ctx, &pb.BatchDeleteRequest{
+		Paths:  []string{"s0/bucket/a", "s1/bucket/a", "s1/bucket/a", "s2/bucket/a"},
+		APIKey: apiKey,
+	})
+	assert.NoError(t, err)
+
+	get, err = s.BatchGet(ctx, &pb.BatchGetRequest{
+		Paths:  []string{"s0/bucket/a", "s1/bucket/a", "l/bucket/a"},
+		APIKey: apiKey,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, get.GetItems()[0].GetPointer())
+	assert.Nil(t, get.GetItems()[1].GetPointer())
+	assert.NotNil(t, get.GetItems()[2].GetPointer())
+
+	resp, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 3, ObjectCount: 1}, resp)
+
+	byNode, err := s.IterateByNode(ctx, &pb.IterateByNodeRequest{NodeId: "node", APIKey: apiKey})
+	assert.NoError(t, err)
+	if assert.Len(t, byNode.GetItems(), 1) {
+		assert.Equal(t, "l/bucket/a", byNode.GetItems()[0].GetPath())
+	}
+
+	// a failed delete deletes nothing
+	db.ForceError++
+	_, err = s.BatchDelete(ctx, &pb.BatchDeleteRequest{Paths: []string{"l/bucket/a"}, APIKey: apiKey})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/bucket/a", APIKey: apiKey})
+	assert.NoError(t, err)
+
+	tooMany := make([]string, storage.LookupLimit+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("l/bucket/%d", i)
+	}
+	_, err = s.BatchGet(ctx, &pb.BatchGetRequest{Paths: tooMany, APIKey: apiKey})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	readOnly := newTestAPIKey(t, root, macaroonpb.Caveat{DisallowDeletes: true})
+	_, err = s.BatchDelete(ctx, &pb.BatchDeleteRequest{Paths: []string{"l/bucket/a"}, APIKey: readOnly})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServiceBatchMove(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
+
+	for path, pointer := range map[string]*pb.Pointer{
+		"s0/bucket/a": {Size: 1, Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{
+			RemotePieces: []*pb.RemotePiece{{NodeId: "node"}},
+		}},
+		"l/bucket/a": {Size: 2},
+		"l/bucket/b": {Size: 4},
+	} {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: pointer, APIKey: apiKey})
+		assert.NoError(t, err)
+	}
+
+	// the object at l/bucket/b is replaced
+	_, err := s.BatchMove(ctx, &pb.BatchMoveRequest{
+		FromPaths: []string{"s0/bucket/a", "l/bucket/a"},
+		ToPaths:   []string{"s0/bucket/b", "l/bucket/b"},
+		APIKey:    apiKey,
+	})
+	assert.NoError(t, err)
+
+	get, err := s.BatchGet(ctx, &pb.BatchGetRequest{
+		Paths:  []string{"s0/bucket/a", "l/bucket/a", "s0/bucket/b", "l/bucket/b"},
+		APIKey: apiKey,
+	})
+	if assert.NoError(t, err) && assert.Len(t, get.GetItems(), 4) {
+		assert.Nil(t, get.GetItems()[0].GetPointer())
+		assert.Nil(t, get.GetItems()[1].GetPointer())
+		assert.Equal(t, int64(1), get.GetItems()[2].GetPointer().GetSize())
+		assert.Equal(t, int64(2), get.GetItems()[3].GetPointer().GetSize())
+	}
+
+	usage, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 3, ObjectCount: 1}, usage)
+
+	byNode, err := s.IterateByNode(ctx, &pb.IterateByNodeRequest{NodeId: "node", APIKey: apiKey})
+	assert.NoError(t, err)
+	if assert.Len(t, byNode.GetItems(), 1) {
+		assert.Equal(t, "s0/bucket/b", byNode.GetItems()[0].GetPath())
+	}
+
+	// nothing is moved when a pointer is missing
+	_, err = s.BatchMove(ctx, &pb.BatchMoveRequest{
+		FromPaths: []string{"l/bucket/b", "s0/bucket/a"},
+		ToPaths:   []string{"l/bucket/c", "s0/bucket/c"},
+		APIKey:    apiKey,
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/bucket/b", APIKey: apiKey})
+	assert.NoError(t, err)
+
+	for i, req := range []*pb.BatchMoveRequest{
+		{FromPaths: []string{"l/bucket/b"}, ToPaths: nil},
+		{FromPaths: []string{"l/bucket/b", "l/bucket/b"}, ToPaths: []string{"l/bucket/c", "l/bucket/d"}},
+		{FromPaths: []string{"l/bucket/b", "s0/bucket/b"}, ToPaths: []string{"l/bucket/c", "l/bucket/c"}},
+	} {
+		req.APIKey = apiKey
+		_, err = s.BatchMove(ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), i)
+	}
+
+	// a path can be moved from and to at once, whatever the order
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/new", Pointer: &pb.Pointer{Size: 8}, APIKey: apiKey})
+	assert.NoError(t, err)
+	_, err = s.BatchMove(ctx, &pb.BatchMoveRequest{
+		FromPaths: []string{"l/bucket/new", "l/bucket/b"},
+		ToPaths:   []string{"l/bucket/b", "l/bucket/old"},
+		APIKey:    apiKey,
+	})
+	assert.NoError(t, err)
+	get, err = s.BatchGet(ctx, &pb.BatchGetRequest{
+		Paths:  []string{"l/bucket/new", "l/bucket/b", "l/bucket/old"},
+		APIKey: apiKey,
+	})
+	if assert.NoError(t, err) && assert.Len(t, get.GetItems(), 3) {
+		assert.Nil(t, get.GetItems()[0].GetPointer())
+		assert.Equal(t, int64(8), get.GetItems()[1].GetPointer().GetSize())
+		assert.Equal(t, int64(2), get.GetItems()[2].GetPointer().GetSize())
+	}
+	usage, err = s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 11, ObjectCount: 2}, usage)
+
+	// the pointers can't be moved out of a bucket the api key is restricted to
+	restricted := newTestAPIKey(t, root, macaroonpb.Caveat{
+		AllowedPaths: []*macaroonpb.CaveatPath{{Bucket: []byte("bucket")}},
+	})
+	_, err = s.BatchMove(ctx, &pb.BatchMoveRequest{
+		FromPaths: []string{"l/bucket/b"},
+		ToPaths:   []string{"l/other/b"},
+		APIKey:    restricted,
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServiceReferences(t *testing.T) {
+	db := teststore.New()
+	s := newTestServer(db)
+	apiKey := newTestAPIKey(t, newTestRootKey(t))
+
+	put := func(path string, pointer *pb.Pointer) error {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: pointer, APIKey: apiKey})
+		return err
+	}
+	del := func(path string) error {
+		_, err := s.Delete(ctx, &pb.DeleteRequest{Path: path, APIKey: apiKey})
+		return err
+	}
+	references := func(path string) int64 {
+		resp, err := s.Get(ctx, &pb.GetRequest{Path: path, APIKey: apiKey})
+		if !assert.NoError(t, err, path) {
+			return -1
+		}
+		pointer := &pb.Pointer{}
+		assert.NoError(t, proto.Unmarshal(resp.GetPointer(), pointer))
+		return pointer.GetReferenceCount()
+	}
+
+	// only chunks can be referenced, and the server keeps the counts
+	assert.Equal(t, codes.InvalidArgument, status.Code(put("l/bucket/a", &pb.Pointer{References: []string{"s0/bucket/b"}})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(put("c/bucket/x", &pb.Pointer{Size: 10, ReferenceCount: 5})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(put("c/bucket/x", &pb.Pointer{References: []string{"c/bucket/y"}})))
+	assert.Equal(t, codes.InvalidArgument, status.Code(put("c/x", &pb.Pointer{Size: 10})))
+	// the chunks are shared within their bucket only
+	assert.Equal(t, codes.InvalidArgument, status.Code(put("l/other/a", &pb.Pointer{References: []string{"c/bucket/x"}})))
+
+	// referencing a missing chunk fails
+	assert.Equal(t, codes.FailedPrecondition, status.Code(put("l/bucket/a", &pb.Pointer{References: []string{"c/bucket/x"}})))
+
+	assert.NoError(t, put("c/bucket/x", &pb.Pointer{Size: 10}))
+	assert.NoError(t, put("c/bucket/y", &pb.Pointer{Size: 20}))
+	// a chunk is immutable
+	assert.Equal(t, codes.AlreadyExists, status.Code(put("c/bucket/x", &pb.Pointer{Size: 30})))
+
+	assert.NoError(t, put("l/bucket/a", &pb.Pointer{References: []string{"c/bucket/x", "c/bucket/y", "c/bucket/x"}}))
+	assert.NoError(t, put("l/bucket/b", &pb.Pointer{References: []string{"c/bucket/x"}}))
+	assert.Equal(t, int64(3), references("c/bucket/x"))
+	assert.Equal(t, int64(1), references("c/bucket/y"))
+
+	// the data of the chunks is only counted once
+	resp, err := s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 30, ObjectCount: 2}, resp)
+
+	// a referenced chunk can't be deleted
+	assert.Equal(t, codes.FailedPrecondition, status.Code(del("c/bucket/x")))
+	_, err = s.BatchDelete(ctx, &pb.BatchDeleteRequest{Paths: []string{"l/bucket/b", "c/bucket/y"}, APIKey: apiKey})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, int64(3), references("c/bucket/x"))
+
+	// replacing a pointer moves its references
+	assert.NoError(t, put("l/bucket/a", &pb.Pointer{References: []string{"c/bucket/y"}}))
+	assert.Equal(t, int64(1), references("c/bucket/x"))
+	assert.Equal(t, int64(1), references("c/bucket/y"))
+
+	// the chunks are deleted with their last reference
+	_, err = s.BatchDelete(ctx, &pb.BatchDeleteRequest{Paths: []string{"l/bucket/b"}, APIKey: apiKey})
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "c/bucket/x", APIKey: apiKey})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, int64(1), references("c/bucket/y"))
+	resp, err = s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{StoredBytes: 20, ObjectCount: 1}, resp)
+
+	// a failed delete keeps the references
+	db.ForceError++
+	assert.Equal(t, codes.Internal, status.Code(del("l/bucket/a")))
+	assert.Equal(t, int64(1), references("c/bucket/y"))
+	assert.NoError(t, del("l/bucket/a"))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "c/bucket/y", APIKey: apiKey})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// unreferenced chunks can be deleted
+	assert.NoError(t, put("c/bucket/x", &pb.Pointer{Size: 10}))
+	assert.NoError(t, del("c/bucket/x"))
+	resp, err = s.Usage(ctx, &pb.UsageRequest{APIKey: apiKey})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{}, resp)
+}
+
+func TestServiceChunkReferrer(t *testing.T) {
+	s := newTestServer(teststore.New())
+	root := newTestRootKey(t)
+	restrict := func(disallowWrites bool) []byte {
+		return newTestAPIKey(t, root, macaroonpb.Caveat{
+			DisallowWrites: disallowWrites,
+			AllowedPaths: []*macaroonpb.CaveatPath{{
+				Bucket:     []byte("bucket"),
+				PathPrefix: []byte("prefix/"),
+			}},
+		})
+	}
+	writer, reader := restrict(false), restrict(true)
+
+	// the chunks are outside of the prefix, so a restricted key can only
+	// put them through a segment of the prefix
+	chunk := &pb.Pointer{Size: 10}
+	_, err := s.Put(ctx, &pb.PutRequest{Path: "c/bucket/x", Pointer: chunk, APIKey: writer})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	for i, referrer := range []string{"l/bucket/a", "l/other/prefix/a", "c/bucket/y"} {
+		_, err = s.Put(ctx, &pb.PutRequest{Path: "c/bucket/x", Pointer: chunk, APIKey: writer, Referrer: referrer})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), i)
+	}
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "c/bucket/x", Pointer: chunk, APIKey: writer, Referrer: "l/bucket/prefix/a"})
+	assert.NoError(t, err)
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "c/bucket/x", Pointer: chunk, APIKey: reader, Referrer: "l/bucket/prefix/a"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// a writer may look the chunks of the bucket up to deduplicate against
+	// them, a reader only those referenced by a readable segment
+	get := func(apiKey []byte, referrer string, paths ...string) error {
+		_, err := s.BatchGet(ctx, &pb.BatchGetRequest{Paths: paths, APIKey: apiKey, Referrer: referrer})
+		return err
+	}
+	assert.NoError(t, get(writer, "l/bucket/prefix/a", "c/bucket/x", "c/bucket/y"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(get(reader, "l/bucket/prefix/a", "c/bucket/x")))
+
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/prefix/a", Pointer: &pb.Pointer{
+		References: []string{"c/bucket/x"},
+	}, APIKey: writer})
+	assert.NoError(t, err)
+	assert.NoError(t, get(reader, "l/bucket/prefix/a", "c/bucket/x"))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "c/bucket/x", APIKey: reader, Referrer: "l/bucket/prefix/a"})
+	assert.NoError(t, err)
+	// not the other chunks, nor without the referrer
+	assert.Equal(t, codes.Unauthenticated, status.Code(get(reader, "l/bucket/prefix/a", "c/bucket/x", "c/bucket/y")))
+	assert.Equal(t, codes.Unauthenticated, status.Code(get(reader, "", "c/bucket/x")))
+	// the referrer only authorizes chunks, the other paths are checked as is
+	assert.Equal(t, codes.Unauthenticated, status.Code(get(reader, "l/bucket/prefix/a", "l/bucket/b")))
+
+	// only the unreferenced chunks are deleted through the referrer
+	_, err = s.BatchDelete(ctx, &pb.BatchDeleteRequest{
+		Paths: []string{"c/bucket/x"}, APIKey: writer, Referrer: "l/bucket/prefix/a",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "c/bucket/y", Pointer: chunk, APIKey: writer, Referrer: "l/bucket/prefix/a"})
+	assert.NoError(t, err)
+	_, err = s.BatchDelete(ctx, &pb.BatchDeleteRequest{
+		Paths: []string{"c/bucket/y"}, APIKey: writer, Referrer: "l/bucket/prefix/a",
+	})
+	assert.NoError(t, err)
+}
+
+func TestServiceSharedKeys(t *testing.T) {
+	s := newTestServer(teststore.New())
+	root := newTestRootKey(t)
+	apiKey := newTestAPIKey(t, root)
+	// the keys of uplink share, for GET and PUT
+	share := func(disallowWrites bool) []byte {
+		return newTestAPIKey(t, root, macaroonpb.Caveat{
+			DisallowLists:   true,
+			DisallowDeletes: true,
+			DisallowWrites:  disallowWrites,
+			AllowedPaths: []*macaroonpb.CaveatPath{{
+				Bucket:     []byte("bucket"),
+				PathPrefix: []byte("obj"),
+				Exact:      true,
+			}},
+		})
+	}
+	get, put := share(true), share(false)
+
+	_, err := s.Put(ctx, &pb.PutRequest{Path: "c/bucket/x", Pointer: &pb.Pointer{Size: 10}, APIKey: apiKey})
+	assert.NoError(t, err)
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/obj", Pointer: &pb.Pointer{
+		References: []string{"c/bucket/x"},
+	}, APIKey: apiKey})
+	assert.NoError(t, err)
+
+	// a deduplicated object is read with its chunks
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/bucket/obj", APIKey: get})
+	assert.NoError(t, err)
+	_, err = s.BatchGet(ctx, &pb.BatchGetRequest{Paths: []string{"c/bucket/x"}, APIKey: get, Referrer: "l/bucket/obj"})
+	assert.NoError(t, err)
+
+	// and put with new chunks, the replaced ones being released
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "c/bucket/y", Pointer: &pb.Pointer{Size: 20}, APIKey: put, Referrer: "l/bucket/obj"})
+	assert.NoError(t, err)
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/obj", Pointer: &pb.Pointer{
+		References: []string{"c/bucket/y"},
+	}, APIKey: put})
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "c/bucket/x", APIKey: apiKey})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// a version can't be put, which is why uplink share refuses to share
+	// the uploads to versioned buckets
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/.versions/obj/1", Pointer: &pb.Pointer{}, APIKey: put})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.BatchMove(ctx, &pb.BatchMoveRequest{
+		FromPaths: []string{"l/bucket/obj"},
+		ToPaths:   []string{"l/bucket/.versions/obj/0"},
+		APIKey:    put,
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestUpdateReferencesInconsistent(t *testing.T) {
+	db := teststore.New()
+	projectID := newTestRootKey(t).ProjectID()
+	value, err := proto.Marshal(&pb.Pointer{Size: 10, ReferenceCount: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(ctx, projectKey(projectID, "c/bucket/x"), value))
+
+	// removing more references than the chunk has is an error, not a
+	// deletion
+	err = storage.RetryTxn(ctx, db, func(txn storage.Txn) error {
+		_, err := updateReferences(txn, projectID, nil, []string{"c/bucket/x", "c/bucket/x"})
+		return err
+	})
+	assert.True(t, Error.Has(err))
+	_, err = db.Get(ctx, projectKey(projectID, "c/bucket/x"))
+	assert.NoError(t, err)
+}
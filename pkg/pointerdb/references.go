@@ -0,0 +1,195 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/zeebo/errs"
+	"google.golang.org/grpc/codes"
+
+	"storj.io/storj/pkg/macaroon"
+	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+)
+
+// chunkPrefix is the prefix of the paths of the chunk segments, whose data
+// is shared by the segments of their bucket referencing them. They're kept
+// at c/<bucket>/<id>, outside of the paths of the objects, so they're
+// authorized through the segments referring to them, see checkChunk.
+const chunkPrefix = "c/"
+
+var (
+	// ErrChunkExists is returned when putting a chunk segment over an
+	// existing one, which may be referenced
+	ErrChunkExists = errs.Class("chunk exists")
+	// ErrChunkReferenced is returned when deleting a chunk segment still
+	// referenced
+	ErrChunkReferenced = errs.Class("chunk referenced")
+	// ErrMissingChunk is returned when putting a pointer referencing a
+	// chunk segment that doesn't exist
+	ErrMissingChunk = errs.Class("missing chunk")
+)
+
+// isChunk returns whether path is the path of a chunk segment
+func isChunk(path string) bool {
+	return strings.HasPrefix(path, chunkPrefix)
+}
+
+// validateReferences checks that the pointer put at path only references
+// chunk segments of its bucket, and that chunk segments don't reference
+// anything nor come with a reference count of their own
+func validateReferences(path string, pointer *pb.Pointer) error {
+	if pointer.GetReferenceCount() != 0 {
+		return segmentError.New("the reference count is maintained by the server")
+	}
+	if isChunk(path) {
+		if bucket, id := splitPath(path); len(bucket) == 0 || len(id) == 0 {
+			return segmentError.New("invalid chunk path %s", path)
+		}
+		if len(pointer.GetReferences()) > 0 {
+			return segmentError.New("chunk %s references other segments", path)
+		}
+	}
+	bucket, _ := splitPath(path)
+	for _, reference := range pointer.GetReferences() {
+		if !isChunk(reference) {
+			return segmentError.New("%s isn't a chunk", reference)
+		}
+		if chunkBucket, _ := splitPath(reference); !bytes.Equal(chunkBucket, bucket) {
+			return segmentError.New("chunk %s isn't in the bucket of %s", reference, path)
+		}
+	}
+	return nil
+}
+
+// checkChunk returns an error if key doesn't allow op on the chunk segment
+// at path through referrer, a segment of the same bucket. A path caveat
+// can't cover the chunks shared within the bucket, so they're authorized
+// like their referrer. A key allowed to write the referrer may read, put
+// and delete the chunks of its bucket, to deduplicate the referrer against
+// them. A key allowed to read the referrer may read the chunks it
+// references, and one allowed to delete it may delete chunks. Chunks are
+// immutable and only deleted once unreferenced, so this doesn't allow
+// changing the data of the other segments.
+func (s *Server) checkChunk(ctx context.Context, key *macaroon.APIKey, op macaroon.ActionType, path, referrer string) error {
+	chunkBucket, _ := splitPath(path)
+	bucket, _ := splitPath(referrer)
+	if isChunk(referrer) || len(bucket) == 0 || !bytes.Equal(bucket, chunkBucket) {
+		return Error.New("%s doesn't refer to the chunks of %s", referrer, chunkBucket)
+	}
+
+	err := s.checkPath(key, macaroon.ActionWrite, referrer)
+	if err == nil || op == macaroon.ActionWrite {
+		return err
+	}
+	if err = s.checkPath(key, op, referrer); err != nil || op != macaroon.ActionRead {
+		return err
+	}
+
+	value, err := s.DB.Get(ctx, projectKey(key.ProjectID(), referrer))
+	if err != nil {
+		return err
+	}
+	pointer := &pb.Pointer{}
+	if err = proto.Unmarshal(value, pointer); err != nil {
+		return Error.Wrap(err)
+	}
+	for _, reference := range pointer.GetReferences() {
+		if reference == path {
+			return nil
+		}
+	}
+	return Error.New("%s doesn't reference %s", referrer, path)
+}
+
+// checkChunkChange checks that replacing the pointer old at path with new
+// doesn't replace or delete a chunk segment in use. A chunk segment is
+// immutable once put, as the segments referencing it rely on its content.
+func checkChunkChange(path string, old, new *pb.Pointer) error {
+	if !isChunk(path) || old == nil {
+		return nil
+	}
+	if new != nil {
+		return ErrChunkExists.New(path)
+	}
+	if old.GetReferenceCount() > 0 {
+		return ErrChunkReferenced.New("%s has %d references", path, old.GetReferenceCount())
+	}
+	return nil
+}
+
+// updateReferences adds a reference to the chunk segments of the project
+// at add and removes one from those at remove in txn. The chunks at add
+// must exist. A path may be repeated to add or remove several references
+// at once. The chunks losing their last reference are deleted with it and
+// returned as released, for their deletion to be accounted for.
+func updateReferences(txn storage.Txn, projectID string, add, remove []string) (released []pointerChange, err error) {
+	deltas := make(map[string]int64, len(add)+len(remove))
+	for _, path := range add {
+		deltas[path]++
+	}
+	for _, path := range remove {
+		deltas[path]--
+	}
+
+	for path, delta := range deltas {
+		if delta == 0 {
+			continue
+		}
+		key := projectKey(projectID, path)
+		chunk, err := getPointer(txn, key)
+		if err != nil {
+			if storage.ErrKeyNotFound.Has(err) {
+				if delta > 0 {
+					return nil, ErrMissingChunk.New(path)
+				}
+				// the chunk was already deleted
+				continue
+			}
+			return nil, err
+		}
+
+		count := chunk.ReferenceCount + delta
+		if count < 0 {
+			// the counts are only changed with the references, so the
+			// store is inconsistent
+			return nil, Error.New("chunk %s has %d references, %d removed",
+				path, chunk.ReferenceCount, -delta)
+		}
+		if count == 0 && delta < 0 {
+			if err = txn.Delete(key); err != nil {
+				return nil, err
+			}
+			released = append(released, pointerChange{path: path, old: chunk})
+			continue
+		}
+
+		chunk.ReferenceCount = count
+		value, err := proto.Marshal(chunk)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		if err = txn.Put(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return released, nil
+}
+
+// referenceCode returns the gRPC code of the errors of the references
+// changes, codes.OK for the other errors
+func referenceCode(err error) codes.Code {
+	switch {
+	case ErrChunkExists.Has(err):
+		return codes.AlreadyExists
+	case ErrChunkReferenced.Has(err), ErrMissingChunk.Has(err):
+		return codes.FailedPrecondition
+	default:
+		return codes.OK
+	}
+}
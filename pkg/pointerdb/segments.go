@@ -0,0 +1,132 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+)
+
+// The methods below are used by the other services of the satellite. They
+// aren't scoped to a project, so the segments are named by their key in the
+// pointer store, of the form <projectID>/<path>.
+
+// Segment is a segment of any project
+type Segment struct {
+	Key     string
+	Pointer *pb.Pointer
+}
+
+// IteratePieces calls fn with the node and the piece ID of every piece of
+// every segment, a node at a time. fn must not use the server.
+func (s *Server) IteratePieces(ctx context.Context, fn func(nodeID, pieceID string) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	prefix := indexPrefix(nodeIndex)
+	return s.DB.Iterate(ctx, storage.IterateOptions{Prefix: prefix, Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				nodeID, _, ok := splitIndexKey(item.Key[len(prefix):])
+				if !ok {
+					continue
+				}
+				if err := fn(nodeID, string(item.Value)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+// SegmentsOnNode returns the segments of every project with a piece on the
+// node, ordered by key. startAfter is the key of the last segment of the
+// previous page.
+func (s *Server) SegmentsOnNode(ctx context.Context, nodeID, startAfter string, limit int) (segments []Segment, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	items, more, err := storage.ListV2(ctx, s.DB, storage.ListOptions{
+		Prefix:     indexPrefix(nodeIndex, nodeID),
+		StartAfter: storage.Key(startAfter),
+		Recursive:  true,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the keys of the entries relative to the node are the segment keys
+	values, err := s.DB.GetAll(ctx, items.GetKeys())
+	if err != nil {
+		return nil, false, err
+	}
+	for i, value := range values {
+		if value == nil {
+			// deleted since it was listed
+			continue
+		}
+		pointer := &pb.Pointer{}
+		if err = proto.Unmarshal(value, pointer); err != nil {
+			return nil, false, Error.Wrap(err)
+		}
+		segments = append(segments, Segment{Key: items[i].Key.String(), Pointer: pointer})
+	}
+	return segments, more, nil
+}
+
+// GetSegment returns the pointer of the segment at key
+func (s *Server) GetSegment(ctx context.Context, key string) (_ *pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if _, _, ok := splitProjectKey(storage.Key(key)); !ok {
+		return nil, Error.New("invalid segment key %q", key)
+	}
+	value, err := s.DB.Get(ctx, storage.Key(key))
+	if err != nil {
+		return nil, err
+	}
+	pointer := &pb.Pointer{}
+	if err = proto.Unmarshal(value, pointer); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return pointer, nil
+}
+
+// CompareAndSwapSegment replaces the pointer of the segment at key with new
+// if it is old, otherwise it returns a storage.ErrValueChanged error. A nil
+// old pointer expects the segment not to exist and a nil new pointer
+// deletes it. The usage, the indexes and the chunk references are updated
+// in the same transaction.
+func (s *Server) CompareAndSwapSegment(ctx context.Context, key string, old, new *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	projectID, path, ok := splitProjectKey(storage.Key(key))
+	if !ok {
+		return Error.New("invalid segment key %q", key)
+	}
+	var value storage.Value
+	if new != nil {
+		if value, err = proto.Marshal(new); err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	return storage.RetryTxn(ctx, s.DB, func(txn storage.Txn) error {
+		current, err := getPointer(txn, storage.Key(key))
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return err
+		}
+		if (current == nil) != (old == nil) || (current != nil && !proto.Equal(current, old)) {
+			return storage.ErrValueChanged.New(key)
+		}
+		if current == nil && new == nil {
+			return nil
+		}
+		return applyChanges(txn, projectID, []pointerChange{{path, current, new, value}})
+	})
+}
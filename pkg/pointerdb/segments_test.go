@@ -0,0 +1,92 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func remoteTestPointer(pieceID string, nodes ...string) *pb.Pointer {
+	var pieces []*pb.RemotePiece
+	for i, node := range nodes {
+		pieces = append(pieces, &pb.RemotePiece{PieceNum: int32(i), NodeId: node})
+	}
+	return &pb.Pointer{
+		Type:   pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{PieceId: pieceID, RemotePieces: pieces},
+	}
+}
+
+func TestSegments(t *testing.T) {
+	s := newTestServer(teststore.New())
+	first := newTestRootKey(t).ProjectID()
+	second := newTestRootKey(t).ProjectID()
+
+	a := remoteTestPointer("piece-a", "node-1", "node-2")
+	b := remoteTestPointer("piece-b", "node-2")
+	for key, pointer := range map[string]*pb.Pointer{
+		first + "/s0/bucket/a":  a,
+		second + "/s0/bucket/b": b,
+	} {
+		assert.NoError(t, s.CompareAndSwapSegment(ctx, key, nil, pointer))
+	}
+
+	// the segment doesn't match the old pointer
+	err := s.CompareAndSwapSegment(ctx, first+"/s0/bucket/a", nil, b)
+	assert.True(t, storage.ErrValueChanged.Has(err))
+	assert.Error(t, s.CompareAndSwapSegment(ctx, "s0/bucket/a", nil, a))
+
+	pointer, err := s.GetSegment(ctx, first+"/s0/bucket/a")
+	if assert.NoError(t, err) {
+		assert.True(t, proto.Equal(a, pointer))
+	}
+
+	pieces := map[string][]string{}
+	assert.NoError(t, s.IteratePieces(ctx, func(nodeID, pieceID string) error {
+		pieces[nodeID] = append(pieces[nodeID], pieceID)
+		return nil
+	}))
+	// the pieces of a node are ordered by project
+	sort.Strings(pieces["node-2"])
+	assert.Equal(t, map[string][]string{
+		"node-1": {"piece-a"},
+		"node-2": {"piece-a", "piece-b"},
+	}, pieces)
+
+	// the segments of every project are listed, a page at a time
+	if first > second {
+		first, second = second, first
+	}
+	segments, more, err := s.SegmentsOnNode(ctx, "node-2", "", 1)
+	if assert.NoError(t, err) && assert.Len(t, segments, 1) {
+		assert.True(t, more)
+		assert.Equal(t, first, segments[0].Key[:len(first)])
+		segments, more, err = s.SegmentsOnNode(ctx, "node-2", segments[0].Key, 1)
+		if assert.NoError(t, err) && assert.Len(t, segments, 1) {
+			assert.False(t, more)
+			assert.Equal(t, second, segments[0].Key[:len(second)])
+		}
+	}
+
+	// moving the piece to another node moves it in the index
+	moved := remoteTestPointer("piece-b", "node-3")
+	assert.NoError(t, s.CompareAndSwapSegment(ctx, segments[0].Key, segments[0].Pointer, moved))
+	segments, _, err = s.SegmentsOnNode(ctx, "node-3", "", 10)
+	if assert.NoError(t, err) && assert.Len(t, segments, 1) {
+		assert.True(t, proto.Equal(moved, segments[0].Pointer))
+	}
+
+	assert.NoError(t, s.CompareAndSwapSegment(ctx, segments[0].Key, moved, nil))
+	segments, _, err = s.SegmentsOnNode(ctx, "node-3", "", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, segments)
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
+)
+
+// usageShards is the number of counters the usage of a project is split in.
+// Every change of a pointer updates the usage, so with a single counter all
+// the concurrent writes of a project would conflict on it.
+const usageShards = 16
+
+// usagePrefix is the prefix of the keys of the usage counters
+var usagePrefix = reservedKey("usage", "")
+
+// usageKey returns the key the shard of the usage counters of the project is
+// stored at
+func usageKey(projectID string, shard int) storage.Key {
+	return reservedKey("usage", projectID, strconv.Itoa(shard))
+}
+
+// usageShard returns the shard of the usage counters updated by the changes
+// of path
+func usageShard(path string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(path))
+	return int(h.Sum32() % usageShards)
+}
+
+// getUsage returns the usage of the project, the sum of its shards
+func getUsage(ctx context.Context, db storage.KeyValueStore, projectID string) (*pb.UsageResponse, error) {
+	keys := make(storage.Keys, 0, usageShards)
+	for shard := 0; shard < usageShards; shard++ {
+		keys = append(keys, usageKey(projectID, shard))
+	}
+	values, err := db.GetAll(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	usage := &pb.UsageResponse{}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		shard, err := unmarshalUsage(value, nil)
+		if err != nil {
+			return nil, err
+		}
+		usage.StoredBytes += shard.StoredBytes
+		usage.ObjectCount += shard.ObjectCount
+	}
+	return usage, nil
+}
+
+// addUsage adds delta to the shard of the usage of the project in txn
+func addUsage(txn storage.Txn, projectID string, shard int, delta *pb.UsageResponse) error {
+	if delta.StoredBytes == 0 && delta.ObjectCount == 0 {
+		return nil
+	}
+	key := usageKey(projectID, shard)
+	usage, err := unmarshalUsage(txn.Get(key))
+	if err != nil {
+		return err
+	}
+	usage.StoredBytes += delta.StoredBytes
+	usage.ObjectCount += delta.ObjectCount
+
+	value, err := proto.Marshal(usage)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return txn.Put(key, value)
+}
+
+// unmarshalUsage decodes a usage read from the store, a missing usage is
+// empty
+func unmarshalUsage(value storage.Value, err error) (*pb.UsageResponse, error) {
+	usage := &pb.UsageResponse{}
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return usage, nil
+		}
+		return nil, err
+	}
+	if err = proto.Unmarshal(value, usage); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return usage, nil
+}
+
+// usageDelta returns the change of usage caused by replacing the pointer old
+// at path with new. Either pointer may be nil.
+func usageDelta(path string, old, new *pb.Pointer) *pb.UsageResponse {
+	delta := &pb.UsageResponse{}
+	if old != nil {
+		delta.StoredBytes -= old.GetSize()
+		if isObject(path) {
+			delta.ObjectCount--
+		}
+	}
+	if new != nil {
+		delta.StoredBytes += new.GetSize()
+		if isObject(path) {
+			delta.ObjectCount++
+		}
+	}
+	return delta
+}
+
+// isObject returns whether path is the last segment of an object. Last
+// segments of the form l/bucket are the buckets themselves.
+func isObject(path string) bool {
+	parts := strings.SplitN(path, "/", 3)
+	return len(parts) == 3 && parts[0] == "l" && parts[2] != ""
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package pool
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the errs class of the connection pool errors
+	Error = errs.Class("connection pool error")
+)
+
+// CtxKey Used as connection pool key
+type CtxKey int
+
+const (
+	ctxKeyPool CtxKey = iota
+)
+
+// Config is a configuration struct for the node connection pool shared by
+// the other responsibilities
+type Config struct {
+	MaxOpen     int           `help:"the maximum number of open node connections, dials wait for one to be released beyond it" default:"500"`
+	IdleTimeout time.Duration `help:"how long unused node connections are kept open" default:"5m"`
+}
+
+// Run implements the provider.Responsibility interface
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cm := NewConnectionManager(
+		transport.NewClient(server.Identity(), server.MinDifficulty()), c)
+	defer func() { _ = cm.Close() }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if c.IdleTimeout > 0 {
+		ticker := time.NewTicker(c.IdleTimeout)
+		defer ticker.Stop()
+
+		go func() {
+			for {
+				select {
+				case <-ticker.C:
+					cm.Sweep()
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return server.Run(context.WithValue(ctx, ctxKeyPool, cm))
+}
+
+// LoadFromContext gives access to the connection pool from the context, or
+// returns nil
+func LoadFromContext(ctx context.Context) *ConnectionManager {
+	if v, ok := ctx.Value(ctxKeyPool).(*ConnectionManager); ok {
+		return v
+	}
+	return nil
+}
@@ -16,7 +16,7 @@ type ConnectionPool struct {
 
 // NewConnectionPool initializes a new in memory pool
 func NewConnectionPool() Pool {
-	return &ConnectionPool{}
+	return &ConnectionPool{cache: map[string]interface{}{}}
 }
 
 // Add takes a node ID as the key and a node client as the value to store
@@ -41,7 +41,7 @@ func (mp *ConnectionPool) Get(ctx context.Context, key string) (interface{}, err
 func (mp *ConnectionPool) Remove(ctx context.Context, key string) error {
 	mp.mu.Lock()
 	defer mp.mu.Unlock()
-	mp.cache[key] = nil
+	delete(mp.cache, key)
 
 	return nil
 }
@@ -0,0 +1,287 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package pool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+	proto "storj.io/storj/protos/overlay"
+)
+
+// ConnectionManager is a transport.Client sharing one connection per node.
+// Connections returned by DialNode are owned by the ConnectionManager: the
+// callers release them with Release once done instead of closing them.
+type ConnectionManager struct {
+	transport transport.Client
+	config    Config
+
+	mu    sync.Mutex
+	conns map[string]*managedConn
+	// dialed are the dialed connections still open, including the ones
+	// removed from conns while in use
+	dialed map[*grpc.ClientConn]*managedConn
+	// open counts the connections dialing or open
+	open int
+	// freed is closed and replaced when a connection is released or closed
+	freed chan struct{}
+}
+
+// managedConn is a connection to a single node. ready is closed once the
+// dial finished, after that conn or err is set.
+type managedConn struct {
+	ready    chan struct{}
+	conn     *grpc.ClientConn
+	err      error
+	lastUsed time.Time
+	// refs counts the callers using the connection
+	refs int
+	// removed is set once the connection is no longer handed out. It's
+	// closed when the last caller releases it.
+	removed bool
+}
+
+// NewConnectionManager creates a ConnectionManager dialing nodes with t
+func NewConnectionManager(t transport.Client, config Config) *ConnectionManager {
+	return &ConnectionManager{
+		transport: t,
+		config:    config,
+		conns:     map[string]*managedConn{},
+		dialed:    map[*grpc.ClientConn]*managedConn{},
+		freed:     make(chan struct{}),
+	}
+}
+
+// a compiler trick to make sure *ConnectionManager implements transport.Client
+// and transport.Releaser
+var (
+	_ transport.Client   = (*ConnectionManager)(nil)
+	_ transport.Releaser = (*ConnectionManager)(nil)
+)
+
+// DialNode returns the connection to node, dialing it if there is no healthy
+// connection yet. If MaxOpen connections are in use, DialNode waits until
+// one is released.
+func (cm *ConnectionManager) DialNode(ctx context.Context, node *proto.Node) (conn *grpc.ClientConn, err error) {
+	defer mon.Task()(&ctx)(&err)
+	id := node.GetId()
+
+	cm.mu.Lock()
+	for {
+		now := time.Now()
+		cm.sweep(now)
+
+		mc, ok := cm.conns[id]
+		if ok && mc.conn != nil && !healthy(mc.conn) {
+			cm.removeLocked(id, mc)
+			ok = false
+		}
+		if ok {
+			mc.refs++
+			mc.lastUsed = now
+			cm.mu.Unlock()
+			return cm.wait(ctx, mc)
+		}
+
+		if cm.config.MaxOpen > 0 && cm.open >= cm.config.MaxOpen && !cm.evictLocked() {
+			freed := cm.freed
+			cm.mu.Unlock()
+			select {
+			case <-freed:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			cm.mu.Lock()
+			continue
+		}
+		break
+	}
+
+	mc := &managedConn{ready: make(chan struct{}), lastUsed: time.Now(), refs: 1}
+	cm.conns[id] = mc
+	cm.open++
+	cm.mu.Unlock()
+
+	conn, err = cm.transport.DialNode(ctx, node)
+
+	cm.mu.Lock()
+	mc.conn, mc.err = conn, err
+	if err != nil {
+		if cm.conns[id] == mc {
+			delete(cm.conns, id)
+		}
+		cm.open--
+		cm.signalLocked()
+	} else {
+		cm.dialed[conn] = mc
+		if mc.removed && mc.refs == 0 {
+			cm.closeLocked(mc)
+		}
+	}
+	cm.mu.Unlock()
+	close(mc.ready)
+	return conn, err
+}
+
+// DialUnauthenticated dials addr without sharing the connection
+func (cm *ConnectionManager) DialUnauthenticated(ctx context.Context, addr proto.NodeAddress) (*grpc.ClientConn, error) {
+	return cm.transport.DialUnauthenticated(ctx, addr)
+}
+
+// Release hands back conn returned by DialNode. The connection stays open
+// for the next callers until it's idle for longer than the idle timeout.
+func (cm *ConnectionManager) Release(conn *grpc.ClientConn) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	mc, ok := cm.dialed[conn]
+	if !ok || mc.refs <= 0 {
+		return Error.New("released connection not in use")
+	}
+	mc.refs--
+	mc.lastUsed = time.Now()
+	if mc.refs > 0 {
+		return nil
+	}
+	if mc.removed {
+		cm.closeLocked(mc)
+	}
+	cm.signalLocked()
+	return nil
+}
+
+// Sweep closes connections that are unhealthy or idle for longer than the
+// configured idle timeout
+func (cm *ConnectionManager) Sweep() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.sweep(time.Now())
+}
+
+// Len returns the number of connections handed out by DialNode
+func (cm *ConnectionManager) Len() int {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return len(cm.conns)
+}
+
+// Close closes all connections, including the ones in use
+func (cm *ConnectionManager) Close() error {
+	cm.mu.Lock()
+	conns := cm.conns
+	cm.conns = map[string]*managedConn{}
+	cm.mu.Unlock()
+
+	for _, mc := range conns {
+		<-mc.ready
+	}
+
+	cm.mu.Lock()
+	dialed := cm.dialed
+	cm.dialed = map[*grpc.ClientConn]*managedConn{}
+	cm.open = 0
+	cm.signalLocked()
+	cm.mu.Unlock()
+
+	var errs []error
+	for conn := range dialed {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utils.CombineErrors(errs...)
+}
+
+// wait waits until mc is dialed
+func (cm *ConnectionManager) wait(ctx context.Context, mc *managedConn) (*grpc.ClientConn, error) {
+	select {
+	case <-mc.ready:
+		return mc.conn, mc.err
+	case <-ctx.Done():
+		// the dial may still succeed, so the reference is released then
+		go func() {
+			<-mc.ready
+			if mc.conn != nil {
+				_ = cm.Release(mc.conn)
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// sweep removes dialed connections that are unhealthy, or idle and unused
+func (cm *ConnectionManager) sweep(now time.Time) {
+	for id, mc := range cm.conns {
+		if mc.conn == nil {
+			continue
+		}
+		idle := mc.refs == 0 && cm.config.IdleTimeout > 0 && now.Sub(mc.lastUsed) > cm.config.IdleTimeout
+		if idle || !healthy(mc.conn) {
+			cm.removeLocked(id, mc)
+		}
+	}
+}
+
+// evictLocked removes the least recently used connection not in use and
+// returns whether there was one
+func (cm *ConnectionManager) evictLocked() bool {
+	var oldestID string
+	var oldest *managedConn
+	for id, mc := range cm.conns {
+		if mc.conn == nil || mc.refs > 0 {
+			continue
+		}
+		if oldest == nil || mc.lastUsed.Before(oldest.lastUsed) {
+			oldestID, oldest = id, mc
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	cm.removeLocked(oldestID, oldest)
+	return true
+}
+
+// removeLocked stops handing out mc. mc is closed once dialed and released
+// by every caller.
+func (cm *ConnectionManager) removeLocked(id string, mc *managedConn) {
+	delete(cm.conns, id)
+	mc.removed = true
+	if mc.conn != nil && mc.refs == 0 {
+		cm.closeLocked(mc)
+	}
+}
+
+// closeLocked closes the dialed connection mc
+func (cm *ConnectionManager) closeLocked(mc *managedConn) {
+	if _, ok := cm.dialed[mc.conn]; !ok {
+		return
+	}
+	delete(cm.dialed, mc.conn)
+	cm.open--
+	cm.signalLocked()
+	go utils.LogClose(mc.conn)
+}
+
+// signalLocked wakes up the dials waiting for a connection to be freed
+func (cm *ConnectionManager) signalLocked() {
+	close(cm.freed)
+	cm.freed = make(chan struct{})
+}
+
+// healthy returns whether conn can still be used
+func healthy(conn *grpc.ClientConn) bool {
+	switch conn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	proto "storj.io/storj/protos/overlay"
+)
+
+var (
+	ctx           = context.Background()
+	errDialFailed = errors.New("dial failed")
+)
+
+// countingTransport dials without tls and counts the dials per node
+type countingTransport struct {
+	mu    sync.Mutex
+	dials map[string]int
+	err   error
+}
+
+func (t *countingTransport) DialNode(ctx context.Context, node *proto.Node) (*grpc.ClientConn, error) {
+	t.mu.Lock()
+	t.dials[node.GetId()]++
+	err := t.err
+	t.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Dial(node.GetAddress().GetAddress(), grpc.WithInsecure())
+}
+
+func (t *countingTransport) DialUnauthenticated(ctx context.Context, addr proto.NodeAddress) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr.Address, grpc.WithInsecure())
+}
+
+func (t *countingTransport) count(id string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.dials[id]
+}
+
+func newTestManager(t *testing.T, config Config) (*ConnectionManager, *countingTransport, func(id string) *proto.Node, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	go func() { _ = server.Serve(lis) }()
+
+	tc := &countingTransport{dials: map[string]int{}}
+	cm := NewConnectionManager(tc, config)
+
+	node := func(id string) *proto.Node {
+		return &proto.Node{
+			Id:      id,
+			Address: &proto.NodeAddress{Address: lis.Addr().String()},
+		}
+	}
+	cleanup := func() {
+		assert.NoError(t, cm.Close())
+		server.Stop()
+	}
+	return cm, tc, node, cleanup
+}
+
+func TestConnectionManagerShares(t *testing.T) {
+	cm, tc, node, cleanup := newTestManager(t, Config{})
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	conns := make([]*grpc.ClientConn, 10)
+	for i := range conns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn, err := cm.DialNode(ctx, node("a"))
+			assert.NoError(t, err)
+			conns[i] = conn
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, tc.count("a"))
+	for _, conn := range conns {
+		assert.Equal(t, conns[0], conn)
+	}
+
+	_, err := cm.DialNode(ctx, node("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tc.count("b"))
+	assert.Equal(t, 2, cm.Len())
+}
+
+func TestConnectionManagerMaxOpen(t *testing.T) {
+	cm, tc, node, cleanup := newTestManager(t, Config{MaxOpen: 2})
+	defer cleanup()
+
+	dial := func(id string) *grpc.ClientConn {
+		conn, err := cm.DialNode(ctx, node(id))
+		assert.NoError(t, err)
+		return conn
+	}
+
+	a, b := dial("a"), dial("b")
+	// make "b" the least recently used connection
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, cm.Release(b))
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, cm.Release(dial("a")))
+
+	// "a" is still in use, so "b" is evicted
+	c := dial("c")
+	assert.Equal(t, 2, cm.Len())
+	assert.Equal(t, connectivity.Shutdown, waitClosed(b))
+	assert.Equal(t, 1, tc.count("a"))
+
+	// both connections are in use, so the dial waits for a release
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	_, err := cm.DialNode(timeoutCtx, node("b"))
+	cancel()
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, tc.count("b"))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, cm.Release(c))
+	}()
+	dial("b")
+	assert.Equal(t, 2, tc.count("b"))
+	assert.Equal(t, 2, cm.Len())
+	assert.NotEqual(t, connectivity.Shutdown, a.GetState())
+}
+
+func TestConnectionManagerRelease(t *testing.T) {
+	cm, _, node, cleanup := newTestManager(t, Config{IdleTimeout: time.Millisecond})
+	defer cleanup()
+
+	conn, err := cm.DialNode(ctx, node("a"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = cm.DialNode(ctx, node("a"))
+	assert.NoError(t, err)
+
+	// connections in use are never idle
+	time.Sleep(5 * time.Millisecond)
+	cm.Sweep()
+	assert.Equal(t, 1, cm.Len())
+
+	assert.NoError(t, cm.Release(conn))
+	cm.Sweep()
+	assert.Equal(t, 1, cm.Len())
+
+	assert.NoError(t, cm.Release(conn))
+	time.Sleep(5 * time.Millisecond)
+	cm.Sweep()
+	assert.Equal(t, 0, cm.Len())
+	assert.Equal(t, connectivity.Shutdown, waitClosed(conn))
+
+	assert.Error(t, cm.Release(conn))
+}
+
+func TestConnectionManagerIdleTimeout(t *testing.T) {
+	cm, _, node, cleanup := newTestManager(t, Config{IdleTimeout: time.Millisecond})
+	defer cleanup()
+
+	conn, err := cm.DialNode(ctx, node("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cm.Len())
+	assert.NoError(t, cm.Release(conn))
+
+	time.Sleep(5 * time.Millisecond)
+	cm.Sweep()
+	assert.Equal(t, 0, cm.Len())
+}
+
+func TestConnectionManagerDialError(t *testing.T) {
+	cm, tc, node, cleanup := newTestManager(t, Config{})
+	defer cleanup()
+
+	tc.err = errDialFailed
+	_, err := cm.DialNode(ctx, node("a"))
+	assert.Equal(t, errDialFailed, err)
+	assert.Equal(t, 0, cm.Len())
+
+	// failed dials aren't cached
+	tc.err = nil
+	_, err = cm.DialNode(ctx, node("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tc.count("a"))
+}
+
+// waitClosed returns the state of conn once it's shut down or after a second
+func waitClosed(conn *grpc.ClientConn) connectivity.State {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	for state := conn.GetState(); state != connectivity.Shutdown; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			return state
+		}
+	}
+	return connectivity.Shutdown
+}
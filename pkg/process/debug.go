@@ -35,6 +35,9 @@ func initDebug(logger *zap.Logger, r *monkit.Registry) (
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	mux.Handle("/mon/", http.StripPrefix("/mon", present.HTTP(r)))
+	if *prometheusEnabled {
+		mux.Handle("/metrics", prometheusHandler(r, *prometheusPrefix))
+	}
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = fmt.Fprintln(w, "OK")
 	})
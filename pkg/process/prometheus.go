@@ -0,0 +1,283 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	prometheusEnabled = flag.Bool("metrics.prometheus", true,
+		"if true, serve the metrics in the OpenMetrics format at /metrics on the debug address")
+	prometheusPrefix = flag.String("metrics.prometheus_prefix", "storj",
+		"prefix of the names of the metrics served at /metrics")
+)
+
+// openMetricsType is the content type of the OpenMetrics text format
+const openMetricsType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// summaryQuantiles are the quantiles of the function times reported
+var summaryQuantiles = []float64{0.5, 0.9, 0.99}
+
+// metricFamily is a metric and its samples, with their labels
+type metricFamily struct {
+	name    string
+	typ     string
+	samples []metricSample
+}
+
+// familyKey identifies a metric by its name before it's mangled and its
+// type, as different names may be mangled the same
+type familyKey struct {
+	name, typ string
+}
+
+type metricSample struct {
+	suffix string
+	labels []metricLabel
+	value  float64
+}
+
+type metricLabel struct {
+	name, value string
+}
+
+// metricFamilies gathers the metrics of a registry
+type metricFamilies struct {
+	prefix   string
+	families map[familyKey]*metricFamily
+}
+
+// newMetricFamilies returns an empty set of metrics named with prefix
+func newMetricFamilies(prefix string) *metricFamilies {
+	return &metricFamilies{prefix: prefix, families: make(map[familyKey]*metricFamily)}
+}
+
+// add adds a sample to the metric name of type typ
+func (m *metricFamilies) add(name, typ, suffix string, value float64, labels ...metricLabel) {
+	key := familyKey{m.prefix + "_" + name, typ}
+	family, ok := m.families[key]
+	if !ok {
+		family = &metricFamily{name: metricName(key.name), typ: typ}
+		m.families[key] = family
+	}
+	family.samples = append(family.samples, metricSample{
+		suffix: suffix, labels: labels, value: value})
+}
+
+// addDurations adds the summary of a distribution of durations in seconds
+func (m *metricFamilies) addDurations(name string, dist *monkit.DurationDist, labels ...metricLabel) {
+	for _, q := range summaryQuantiles {
+		m.add(name, "summary", "", dist.Query(q).Seconds(), withLabel(labels,
+			"quantile", strconv.FormatFloat(q, 'g', -1, 64))...)
+	}
+	m.add(name, "summary", "_sum", dist.Sum.Seconds(), labels...)
+	m.add(name, "summary", "_count", float64(dist.Count), labels...)
+}
+
+// withLabel returns a copy of labels with another label
+func withLabel(labels []metricLabel, name, value string) []metricLabel {
+	return append(append([]metricLabel(nil), labels...), metricLabel{name, value})
+}
+
+// collectMetrics gathers the metrics of r. The functions are reported with
+// typed metrics labeled with their scope and name, the other stats with
+// the mangled names of the stats, labeled with their scope.
+func collectMetrics(r *monkit.Registry, prefix string) *metricFamilies {
+	m := newMetricFamilies(prefix)
+	r.Scopes(func(s *monkit.Scope) {
+		scope := metricLabel{"scope", s.Name()}
+
+		var funcs []string
+		s.Funcs(func(f *monkit.Func) {
+			funcs = append(funcs, f.ShortName())
+			labels := []metricLabel{scope, {"function", f.ShortName()}}
+			m.add("function_current", "gauge", "", float64(f.Current()), labels...)
+			m.add("function_highwater", "gauge", "", float64(f.Highwater()), labels...)
+			m.add("function_successes", "counter", "_total", float64(f.Success()), labels...)
+			m.add("function_panics", "counter", "_total", float64(f.Panics()), labels...)
+			errors := f.Errors()
+			classes := make([]string, 0, len(errors))
+			for class := range errors {
+				classes = append(classes, class)
+			}
+			sort.Strings(classes)
+			for _, class := range classes {
+				m.add("function_errors", "counter", "_total", float64(errors[class]),
+					withLabel(labels, "error", class)...)
+			}
+			m.addDurations("function_success_seconds", f.SuccessTimes(), labels...)
+			m.addDurations("function_failure_seconds", f.FailureTimes(), labels...)
+		})
+
+		s.Stats(func(name string, val float64) {
+			for _, f := range funcs {
+				if strings.HasPrefix(name, f+".") {
+					// reported above
+					return
+				}
+			}
+			m.add(name, "unknown", "", val, scope)
+		})
+	})
+	return m
+}
+
+// byName returns the metrics by their mangled name. The metrics whose
+// names, or the names of their samples, collide with those of another
+// metric are told apart by a number appended to their name. The typed
+// metrics come first and keep their name, then the others in the order of
+// their names before they were mangled, so a metric is named the same way
+// as long as the same metrics are reported.
+func (m *metricFamilies) byName() map[string]*metricFamily {
+	keys := make([]familyKey, 0, len(m.families))
+	for key := range m.families {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if (a.typ == "unknown") != (b.typ == "unknown") {
+			return b.typ == "unknown"
+		}
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		return a.typ < b.typ
+	})
+
+	families := make(map[string]*metricFamily, len(keys))
+	taken := make(map[string]bool)
+	for _, key := range keys {
+		family := m.families[key]
+		base := metricName(key.name)
+		family.name = base
+		for n := 2; family.collides(taken); n++ {
+			family.name = base + "_" + strconv.Itoa(n)
+		}
+		taken[family.name] = true
+		for _, sample := range family.samples {
+			taken[family.name+sample.suffix] = true
+		}
+		families[family.name] = family
+	}
+	return families
+}
+
+// collides returns whether the name of the metric or of one of its
+// samples is taken
+func (f *metricFamily) collides(taken map[string]bool) bool {
+	if taken[f.name] {
+		return true
+	}
+	for _, sample := range f.samples {
+		if taken[f.name+sample.suffix] {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTo writes the metrics in the OpenMetrics text format
+func (m *metricFamilies) writeTo(w io.Writer) error {
+	families := m.byName()
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		family := families[name]
+		_, _ = fmt.Fprintf(bw, "# TYPE %s %s\n", name, family.typ)
+		for _, sample := range family.samples {
+			_, _ = bw.WriteString(name + sample.suffix)
+			if len(sample.labels) > 0 {
+				_ = bw.WriteByte('{')
+				for i, label := range sample.labels {
+					if i > 0 {
+						_ = bw.WriteByte(',')
+					}
+					_, _ = fmt.Fprintf(bw, "%s=\"%s\"", label.name, escapeLabel(label.value))
+				}
+				_ = bw.WriteByte('}')
+			}
+			_, _ = fmt.Fprintf(bw, " %s\n", formatValue(sample.value))
+		}
+	}
+	_, _ = bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+// metricName mangles name into a valid metric name: the characters other
+// than letters, digits and underscores become underscores, at most one in
+// a row, and camel case becomes snake case
+func metricName(name string) string {
+	var b strings.Builder
+	underscore := false
+	var prev rune
+	for i, c := range name {
+		switch {
+		case 'A' <= c && c <= 'Z':
+			if i > 0 && ('a' <= prev && prev <= 'z' || '0' <= prev && prev <= '9') && !underscore {
+				b.WriteByte('_')
+			}
+			b.WriteRune(c - 'A' + 'a')
+			underscore = false
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+			if i == 0 && c <= '9' {
+				b.WriteByte('_')
+			}
+			b.WriteRune(c)
+			underscore = false
+		default:
+			if !underscore && b.Len() > 0 {
+				b.WriteByte('_')
+				underscore = true
+			}
+		}
+		prev = c
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// escapeLabel escapes a label value of the text format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatValue formats a sample value of the text format
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// prometheusHandler serves the metrics of r in the OpenMetrics text format
+func prometheusHandler(r *monkit.Registry, prefix string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", openMetricsType)
+		start := time.Now()
+		m := collectMetrics(r, prefix)
+		m.add("metrics_collect_seconds", "gauge", "", time.Since(start).Seconds())
+		_ = m.writeTo(w)
+	})
+}
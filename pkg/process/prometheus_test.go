@@ -0,0 +1,113 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricName(t *testing.T) {
+	for i, tt := range []struct {
+		name, mangled string
+	}{
+		{"storj_function_current", "storj_function_current"},
+		{"storj_pkg.piecestore.upload", "storj_pkg_piecestore_upload"},
+		{"storj_bytesUploaded", "storj_bytes_uploaded"},
+		{"storj_HTTPServer", "storj_httpserver"},
+		{"storj_total.bytes-sent..count", "storj_total_bytes_sent_count"},
+		{"storj_v2Requests", "storj_v2_requests"},
+		{"storj_trailing.", "storj_trailing"},
+		{"9lives", "_9lives"},
+		{".leading", "leading"},
+	} {
+		assert.Equal(t, tt.mangled, metricName(tt.name), i)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	assert.Equal(t, `plain`, escapeLabel("plain"))
+	assert.Equal(t, `a\\b`, escapeLabel(`a\b`))
+	assert.Equal(t, `say \"hi\"`, escapeLabel(`say "hi"`))
+	assert.Equal(t, `two\nlines`, escapeLabel("two\nlines"))
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "1.5", formatValue(1.5))
+	assert.Equal(t, "0", formatValue(0))
+	assert.Equal(t, "1e+21", formatValue(1e21))
+	assert.Equal(t, "NaN", formatValue(math.NaN()))
+	assert.Equal(t, "+Inf", formatValue(math.Inf(1)))
+	assert.Equal(t, "-Inf", formatValue(math.Inf(-1)))
+}
+
+func TestWriteMetrics(t *testing.T) {
+	m := newMetricFamilies("storj")
+	scope := metricLabel{"scope", "pkg/piecestore"}
+	labels := []metricLabel{scope, {"function", "upload"}}
+	m.add("function_successes", "counter", "_total", 3, labels...)
+	m.add("function_current", "gauge", "", 1, labels...)
+	m.add("function_errors", "counter", "_total", 2,
+		withLabel(labels, "error", `say "no"`)...)
+	m.add("bytes.sent", "unknown", "", math.Inf(1), scope)
+	m.add("metrics_collect_seconds", "gauge", "", 0.25)
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.writeTo(&buf))
+	assert.Equal(t, `# TYPE storj_bytes_sent unknown
+storj_bytes_sent{scope="pkg/piecestore"} +Inf
+# TYPE storj_function_current gauge
+storj_function_current{scope="pkg/piecestore",function="upload"} 1
+# TYPE storj_function_errors counter
+storj_function_errors_total{scope="pkg/piecestore",function="upload",error="say \"no\""} 2
+# TYPE storj_function_successes counter
+storj_function_successes_total{scope="pkg/piecestore",function="upload"} 3
+# TYPE storj_metrics_collect_seconds gauge
+storj_metrics_collect_seconds 0.25
+# EOF
+`, buf.String())
+
+	// the output ends with the end marker even without metrics
+	buf.Reset()
+	assert.NoError(t, newMetricFamilies("storj").writeTo(&buf))
+	assert.Equal(t, "# EOF\n", buf.String())
+}
+
+func TestWriteMetricsCollisions(t *testing.T) {
+	m := newMetricFamilies("storj")
+	scope := metricLabel{"scope", "pkg/kademlia"}
+	// names which are mangled the same, with different types
+	m.add("function_current", "gauge", "", 1, scope)
+	m.add("function.current", "unknown", "", 2, scope)
+	m.add("function-current", "unknown", "", 3, scope)
+	// a stat named like the samples of a counter
+	m.add("lookups", "counter", "_total", 4, scope)
+	m.add("lookups.total", "unknown", "", 5, scope)
+	// samples of the same metric are still merged
+	m.add("function_current", "gauge", "", 6, scope)
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.writeTo(&buf))
+	assert.Equal(t, `# TYPE storj_function_current gauge
+storj_function_current{scope="pkg/kademlia"} 1
+storj_function_current{scope="pkg/kademlia"} 6
+# TYPE storj_function_current_2 unknown
+storj_function_current_2{scope="pkg/kademlia"} 3
+# TYPE storj_function_current_3 unknown
+storj_function_current_3{scope="pkg/kademlia"} 2
+# TYPE storj_lookups counter
+storj_lookups_total{scope="pkg/kademlia"} 4
+# TYPE storj_lookups_total_2 unknown
+storj_lookups_total_2{scope="pkg/kademlia"} 5
+# EOF
+`, buf.String())
+
+	// the metrics are named the same way each time they are written
+	var again bytes.Buffer
+	assert.NoError(t, m.writeTo(&again))
+	assert.Equal(t, buf.String(), again.String())
+}
@@ -13,4 +13,8 @@ var (
 
 	// Error is a provider error
 	Error = errs.Class("provider error")
+	// ErrNodeID is used when a peer's identity doesn't match the expected node ID
+	ErrNodeID = errs.Class("node id verification error")
+	// ErrDifficulty is used when a peer's node ID is below the minimum difficulty
+	ErrDifficulty = errs.Class("node id difficulty error")
 )
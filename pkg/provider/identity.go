@@ -20,6 +20,7 @@ import (
 
 	"encoding/base64"
 	"fmt"
+	"math"
 	"math/bits"
 
 	"storj.io/storj/pkg/peertls"
@@ -71,6 +72,17 @@ type IdentityConfig struct {
 	CertPath string `help:"path to the certificate chain for this identity" default:"$CONFDIR/identity.cert"`
 	KeyPath  string `help:"path to the private key for this identity" default:"$CONFDIR/identity.key"`
 	Address  string `help:"address to listen on" default:":7777"`
+
+	MinDifficulty uint64 `help:"the minimum node ID difficulty required of dialed nodes" default:"0"`
+}
+
+// Difficulty returns MinDifficulty as a node ID difficulty, failing if it's
+// out of range
+func (ic IdentityConfig) Difficulty() (uint16, error) {
+	if ic.MinDifficulty > math.MaxUint16 {
+		return 0, errs.New("minimum difficulty %d is out of range", ic.MinDifficulty)
+	}
+	return uint16(ic.MinDifficulty), nil
 }
 
 // FullIdentityFromPEM loads a FullIdentity from a certificate chain and
@@ -234,7 +246,12 @@ func (ic IdentityConfig) Run(ctx context.Context,
 	}
 	defer func() { _ = lis.Close() }()
 
-	s, err := NewProvider(pi, lis, responsibilities...)
+	minDifficulty, err := ic.Difficulty()
+	if err != nil {
+		return err
+	}
+
+	s, err := NewProvider(pi, lis, minDifficulty, responsibilities...)
 	if err != nil {
 		return err
 	}
@@ -286,6 +303,50 @@ func (fi *FullIdentity) DialOption() (grpc.DialOption, error) {
 	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
 }
 
+// NodeTLSConfig returns a tls config for making outgoing connections to the
+// node with the given id. The handshake fails with ErrNodeID if the peer's
+// CA doesn't derive id and with ErrDifficulty if id is below minDifficulty.
+func (fi *FullIdentity) NodeTLSConfig(id string, minDifficulty uint16) (*tls.Config, error) {
+	ch := [][]byte{fi.Leaf.Raw, fi.CA.Raw}
+	c, err := peertls.TLSCert(ch, fi.Leaf, fi.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates:       []tls.Certificate{*c},
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: peertls.VerifyPeerFunc(
+			peertls.VerifyPeerCertChains,
+			verifyPeerID(id, minDifficulty),
+		),
+	}, nil
+}
+
+// verifyPeerID returns a verification func checking that the peer's CA
+// derives id and that id has at least minDifficulty
+func verifyPeerID(id string, minDifficulty uint16) peertls.PeerCertVerificationFunc {
+	return func(_ [][]byte, parsedChains [][]*x509.Certificate) error {
+		chain := parsedChains[0]
+		if len(chain) < 2 {
+			return ErrNodeID.New("certificate chain has no CA")
+		}
+
+		peerID, err := idFromKey(chain[1].PublicKey)
+		if err != nil {
+			return ErrNodeID.Wrap(err)
+		}
+		if peerID.String() != id {
+			return ErrNodeID.New("expected node %s, got %s", id, peerID)
+		}
+		if difficulty := peerID.Difficulty(); difficulty < minDifficulty {
+			return ErrDifficulty.New("node %s has difficulty %d, required %d",
+				peerID, difficulty, minDifficulty)
+		}
+		return nil
+	}
+}
+
 type nodeID string
 
 func (n nodeID) String() string { return string(n) }
@@ -233,3 +233,12 @@ func TestVerifyPeer(t *testing.T) {
 	err = peertls.VerifyPeerFunc(peertls.VerifyPeerCertChains)([][]byte{fi.Leaf.Raw, fi.CA.Raw}, nil)
 	assert.NoError(t, err)
 }
+
+func TestIdentityConfig_Difficulty(t *testing.T) {
+	difficulty, err := IdentityConfig{MinDifficulty: 12}.Difficulty()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(12), difficulty)
+
+	_, err = IdentityConfig{MinDifficulty: 1 << 16}.Difficulty()
+	assert.Error(t, err)
+}
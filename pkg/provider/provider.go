@@ -32,15 +32,16 @@ type Responsibility interface {
 // Provider represents a bundle of responsibilities defined by a specific ID.
 // Examples of providers are the heavy client, the storagenode, and the gateway.
 type Provider struct {
-	lis      net.Listener
-	g        *grpc.Server
-	next     []Responsibility
-	identity *FullIdentity
+	lis           net.Listener
+	g             *grpc.Server
+	next          []Responsibility
+	identity      *FullIdentity
+	minDifficulty uint16
 }
 
-// NewProvider creates a Provider out of an Identity, a net.Listener, and a set
-// of responsibilities.
-func NewProvider(identity *FullIdentity, lis net.Listener,
+// NewProvider creates a Provider out of an Identity, a net.Listener, the
+// minimum node ID difficulty of nodes it dials, and a set of responsibilities.
+func NewProvider(identity *FullIdentity, lis net.Listener, minDifficulty uint16,
 	responsibilities ...Responsibility) (*Provider, error) {
 	// NB: talk to anyone with an identity
 	ident, err := identity.ServerOption()
@@ -55,8 +56,9 @@ func NewProvider(identity *FullIdentity, lis net.Listener,
 			grpc.UnaryInterceptor(unaryInterceptor),
 			ident,
 		),
-		next:     responsibilities,
-		identity: identity,
+		next:          responsibilities,
+		identity:      identity,
+		minDifficulty: minDifficulty,
 	}, nil
 }
 
@@ -91,6 +93,10 @@ func SetupIdentity(ctx context.Context, c CASetupConfig, i IdentitySetupConfig)
 // Identity returns the provider's identity
 func (p *Provider) Identity() *FullIdentity { return p.identity }
 
+// MinDifficulty returns the minimum node ID difficulty of nodes the provider
+// dials
+func (p *Provider) MinDifficulty() uint16 { return p.minDifficulty }
+
 // GRPC returns the provider's gRPC server for registration purposes
 func (p *Provider) GRPC() *grpc.Server { return p.g }
 
@@ -0,0 +1,69 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+import (
+	"context"
+	"strings"
+
+	base58 "github.com/jbenet/go-base58"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/provider"
+	pb "storj.io/storj/pkg/statdb/proto"
+	"storj.io/storj/pkg/utils"
+)
+
+// CtxKey is the type of the statdb context keys
+type CtxKey int
+
+const (
+	ctxKeyStatDB CtxKey = iota
+)
+
+// Config is a configuration struct that is everything you need to start a
+// StatDB responsibility
+type Config struct {
+	DatabaseURL string `help:"the database connection string to use" default:"sqlite3://$CONFDIR/stats.db"`
+	APISecret   string `help:"the base58 encoded secret api keys are signed with" default:""`
+	AdminSecret string `help:"the base58 encoded secret the admin api keys, the only ones allowed to change the stats, are signed with. If empty, the stats can't be changed remotely" default:""`
+	Audit       ReputationConfig
+	Uptime      ReputationConfig
+}
+
+// Run implements the provider.Responsibility interface
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	if c.APISecret == "" {
+		return Error.New("no api secret configured")
+	}
+
+	dburl, err := utils.ParseURL(c.DatabaseURL)
+	if err != nil {
+		return err
+	}
+
+	if dburl.Scheme != "sqlite3" {
+		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+	source := strings.TrimPrefix(c.DatabaseURL, dburl.Scheme+"://")
+
+	ns, err := NewServer(dburl.Scheme, source, base58.Decode(c.APISecret), base58.Decode(c.AdminSecret), c.Audit, c.Uptime, zap.L())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ns.DB.Close() }()
+
+	pb.RegisterStatDBServer(server.GRPC(), ns)
+
+	return server.Run(context.WithValue(ctx, ctxKeyStatDB, ns))
+}
+
+// LoadFromContext loads the stats db server from the Provider context stack
+// if one exists.
+func LoadFromContext(ctx context.Context) *Server {
+	if v, ok := ctx.Value(ctxKeyStatDB).(*Server); ok {
+		return v
+	}
+	return nil
+}
@@ -271,9 +271,16 @@ func (obj *sqlite3DB) Schema() string {
 	audit_success_count INTEGER NOT NULL,
 	total_audit_count INTEGER NOT NULL,
 	audit_success_ratio REAL NOT NULL,
+	audit_reputation_alpha REAL NOT NULL,
+	audit_reputation_beta REAL NOT NULL,
 	uptime_success_count INTEGER NOT NULL,
 	total_uptime_count INTEGER NOT NULL,
 	uptime_ratio REAL NOT NULL,
+	uptime_reputation_alpha REAL NOT NULL,
+	uptime_reputation_beta REAL NOT NULL,
+	latency_samples BLOB NOT NULL,
+	latency_90 INTEGER NOT NULL,
+	disqualified TIMESTAMP,
 	created_at TIMESTAMP NOT NULL,
 	updated_at TIMESTAMP NOT NULL,
 	PRIMARY KEY ( id )
@@ -341,26 +348,40 @@ nextval:
 }
 
 type Node struct {
-	Id                 string
-	AuditSuccessCount  int64
-	TotalAuditCount    int64
-	AuditSuccessRatio  float64
-	UptimeSuccessCount int64
-	TotalUptimeCount   int64
-	UptimeRatio        float64
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	Id                    string
+	AuditSuccessCount     int64
+	TotalAuditCount       int64
+	AuditSuccessRatio     float64
+	AuditReputationAlpha  float64
+	AuditReputationBeta   float64
+	UptimeSuccessCount    int64
+	TotalUptimeCount      int64
+	UptimeRatio           float64
+	UptimeReputationAlpha float64
+	UptimeReputationBeta  float64
+	LatencySamples        []byte
+	Latency90             int64
+	Disqualified          *time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 }
 
 func (Node) _Table() string { return "nodes" }
 
 type Node_Update_Fields struct {
-	AuditSuccessCount  Node_AuditSuccessCount_Field
-	TotalAuditCount    Node_TotalAuditCount_Field
-	AuditSuccessRatio  Node_AuditSuccessRatio_Field
-	UptimeSuccessCount Node_UptimeSuccessCount_Field
-	TotalUptimeCount   Node_TotalUptimeCount_Field
-	UptimeRatio        Node_UptimeRatio_Field
+	AuditSuccessCount     Node_AuditSuccessCount_Field
+	TotalAuditCount       Node_TotalAuditCount_Field
+	AuditSuccessRatio     Node_AuditSuccessRatio_Field
+	AuditReputationAlpha  Node_AuditReputationAlpha_Field
+	AuditReputationBeta   Node_AuditReputationBeta_Field
+	UptimeSuccessCount    Node_UptimeSuccessCount_Field
+	TotalUptimeCount      Node_TotalUptimeCount_Field
+	UptimeRatio           Node_UptimeRatio_Field
+	UptimeReputationAlpha Node_UptimeReputationAlpha_Field
+	UptimeReputationBeta  Node_UptimeReputationBeta_Field
+	LatencySamples        Node_LatencySamples_Field
+	Latency90             Node_Latency90_Field
+	Disqualified          Node_Disqualified_Field
 }
 
 type Node_Id_Field struct {
@@ -435,6 +456,42 @@ func (f Node_AuditSuccessRatio_Field) value() interface{} {
 
 func (Node_AuditSuccessRatio_Field) _Column() string { return "audit_success_ratio" }
 
+type Node_AuditReputationAlpha_Field struct {
+	_set   bool
+	_value float64
+}
+
+func Node_AuditReputationAlpha(v float64) Node_AuditReputationAlpha_Field {
+	return Node_AuditReputationAlpha_Field{_set: true, _value: v}
+}
+
+func (f Node_AuditReputationAlpha_Field) value() interface{} {
+	if !f._set {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_AuditReputationAlpha_Field) _Column() string { return "audit_reputation_alpha" }
+
+type Node_AuditReputationBeta_Field struct {
+	_set   bool
+	_value float64
+}
+
+func Node_AuditReputationBeta(v float64) Node_AuditReputationBeta_Field {
+	return Node_AuditReputationBeta_Field{_set: true, _value: v}
+}
+
+func (f Node_AuditReputationBeta_Field) value() interface{} {
+	if !f._set {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_AuditReputationBeta_Field) _Column() string { return "audit_reputation_beta" }
+
 type Node_UptimeSuccessCount_Field struct {
 	_set   bool
 	_value int64
@@ -489,6 +546,110 @@ func (f Node_UptimeRatio_Field) value() interface{} {
 
 func (Node_UptimeRatio_Field) _Column() string { return "uptime_ratio" }
 
+type Node_UptimeReputationAlpha_Field struct {
+	_set   bool
+	_value float64
+}
+
+func Node_UptimeReputationAlpha(v float64) Node_UptimeReputationAlpha_Field {
+	return Node_UptimeReputationAlpha_Field{_set: true, _value: v}
+}
+
+func (f Node_UptimeReputationAlpha_Field) value() interface{} {
+	if !f._set {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_UptimeReputationAlpha_Field) _Column() string { return "uptime_reputation_alpha" }
+
+type Node_UptimeReputationBeta_Field struct {
+	_set   bool
+	_value float64
+}
+
+func Node_UptimeReputationBeta(v float64) Node_UptimeReputationBeta_Field {
+	return Node_UptimeReputationBeta_Field{_set: true, _value: v}
+}
+
+func (f Node_UptimeReputationBeta_Field) value() interface{} {
+	if !f._set {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_UptimeReputationBeta_Field) _Column() string { return "uptime_reputation_beta" }
+
+type Node_LatencySamples_Field struct {
+	_set   bool
+	_value []byte
+}
+
+func Node_LatencySamples(v []byte) Node_LatencySamples_Field {
+	return Node_LatencySamples_Field{_set: true, _value: v}
+}
+
+func (f Node_LatencySamples_Field) value() interface{} {
+	if !f._set {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_LatencySamples_Field) _Column() string { return "latency_samples" }
+
+type Node_Latency90_Field struct {
+	_set   bool
+	_value int64
+}
+
+func Node_Latency90(v int64) Node_Latency90_Field {
+	return Node_Latency90_Field{_set: true, _value: v}
+}
+
+func (f Node_Latency90_Field) value() interface{} {
+	if !f._set {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_Latency90_Field) _Column() string { return "latency_90" }
+
+type Node_Disqualified_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func Node_Disqualified(v time.Time) Node_Disqualified_Field {
+	return Node_Disqualified_Field{_set: true, _value: &v}
+}
+
+func Node_Disqualified_Raw(v *time.Time) Node_Disqualified_Field {
+	if v == nil {
+		return Node_Disqualified_Null()
+	}
+	return Node_Disqualified(*v)
+}
+
+func Node_Disqualified_Null() Node_Disqualified_Field {
+	return Node_Disqualified_Field{_set: true, _null: true}
+}
+
+func (f Node_Disqualified_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f Node_Disqualified_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_Disqualified_Field) _Column() string { return "disqualified" }
+
 type Node_CreatedAt_Field struct {
 	_set   bool
 	_value time.Time
@@ -698,9 +859,16 @@ func (obj *sqlite3Impl) Create_Node(ctx context.Context,
 	node_audit_success_count Node_AuditSuccessCount_Field,
 	node_total_audit_count Node_TotalAuditCount_Field,
 	node_audit_success_ratio Node_AuditSuccessRatio_Field,
+	node_audit_reputation_alpha Node_AuditReputationAlpha_Field,
+	node_audit_reputation_beta Node_AuditReputationBeta_Field,
 	node_uptime_success_count Node_UptimeSuccessCount_Field,
 	node_total_uptime_count Node_TotalUptimeCount_Field,
-	node_uptime_ratio Node_UptimeRatio_Field) (
+	node_uptime_ratio Node_UptimeRatio_Field,
+	node_uptime_reputation_alpha Node_UptimeReputationAlpha_Field,
+	node_uptime_reputation_beta Node_UptimeReputationBeta_Field,
+	node_latency_samples Node_LatencySamples_Field,
+	node_latency_90 Node_Latency90_Field,
+	node_disqualified Node_Disqualified_Field) (
 	node *Node, err error) {
 
 	__now := obj.db.Hooks.Now().UTC()
@@ -708,18 +876,25 @@ func (obj *sqlite3Impl) Create_Node(ctx context.Context,
 	__audit_success_count_val := node_audit_success_count.value()
 	__total_audit_count_val := node_total_audit_count.value()
 	__audit_success_ratio_val := node_audit_success_ratio.value()
+	__audit_reputation_alpha_val := node_audit_reputation_alpha.value()
+	__audit_reputation_beta_val := node_audit_reputation_beta.value()
 	__uptime_success_count_val := node_uptime_success_count.value()
 	__total_uptime_count_val := node_total_uptime_count.value()
 	__uptime_ratio_val := node_uptime_ratio.value()
+	__uptime_reputation_alpha_val := node_uptime_reputation_alpha.value()
+	__uptime_reputation_beta_val := node_uptime_reputation_beta.value()
+	__latency_samples_val := node_latency_samples.value()
+	__latency_90_val := node_latency_90.value()
+	__disqualified_val := node_disqualified.value()
 	__created_at_val := __now
 	__updated_at_val := __now
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO nodes ( id, audit_success_count, total_audit_count, audit_success_ratio, uptime_success_count, total_uptime_count, uptime_ratio, created_at, updated_at ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO nodes ( id, audit_success_count, total_audit_count, audit_success_ratio, audit_reputation_alpha, audit_reputation_beta, uptime_success_count, total_uptime_count, uptime_ratio, uptime_reputation_alpha, uptime_reputation_beta, latency_samples, latency_90, disqualified, created_at, updated_at ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? )")
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
-	obj.logStmt(__stmt, __id_val, __audit_success_count_val, __total_audit_count_val, __audit_success_ratio_val, __uptime_success_count_val, __total_uptime_count_val, __uptime_ratio_val, __created_at_val, __updated_at_val)
+	obj.logStmt(__stmt, __id_val, __audit_success_count_val, __total_audit_count_val, __audit_success_ratio_val, __audit_reputation_alpha_val, __audit_reputation_beta_val, __uptime_success_count_val, __total_uptime_count_val, __uptime_ratio_val, __uptime_reputation_alpha_val, __uptime_reputation_beta_val, __latency_samples_val, __latency_90_val, __disqualified_val, __created_at_val, __updated_at_val)
 
-	__res, err := obj.driver.Exec(__stmt, __id_val, __audit_success_count_val, __total_audit_count_val, __audit_success_ratio_val, __uptime_success_count_val, __total_uptime_count_val, __uptime_ratio_val, __created_at_val, __updated_at_val)
+	__res, err := obj.driver.Exec(__stmt, __id_val, __audit_success_count_val, __total_audit_count_val, __audit_success_ratio_val, __audit_reputation_alpha_val, __audit_reputation_beta_val, __uptime_success_count_val, __total_uptime_count_val, __uptime_ratio_val, __uptime_reputation_alpha_val, __uptime_reputation_beta_val, __latency_samples_val, __latency_90_val, __disqualified_val, __created_at_val, __updated_at_val)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -735,7 +910,7 @@ func (obj *sqlite3Impl) Get_Node_By_Id(ctx context.Context,
 	node_id Node_Id_Field) (
 	node *Node, err error) {
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.audit_success_count, nodes.total_audit_count, nodes.audit_success_ratio, nodes.uptime_success_count, nodes.total_uptime_count, nodes.uptime_ratio, nodes.created_at, nodes.updated_at FROM nodes WHERE nodes.id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.audit_success_count, nodes.total_audit_count, nodes.audit_success_ratio, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.uptime_success_count, nodes.total_uptime_count, nodes.uptime_ratio, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.latency_samples, nodes.latency_90, nodes.disqualified, nodes.created_at, nodes.updated_at FROM nodes WHERE nodes.id = ?")
 
 	var __values []interface{}
 	__values = append(__values, node_id.value())
@@ -744,7 +919,7 @@ func (obj *sqlite3Impl) Get_Node_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	node = &Node{}
-	err = obj.driver.QueryRow(__stmt, __values...).Scan(&node.Id, &node.AuditSuccessCount, &node.TotalAuditCount, &node.AuditSuccessRatio, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.UptimeRatio, &node.CreatedAt, &node.UpdatedAt)
+	err = obj.driver.QueryRow(__stmt, __values...).Scan(&node.Id, &node.AuditSuccessCount, &node.TotalAuditCount, &node.AuditSuccessRatio, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.UptimeRatio, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.LatencySamples, &node.Latency90, &node.Disqualified, &node.CreatedAt, &node.UpdatedAt)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -779,6 +954,16 @@ func (obj *sqlite3Impl) Update_Node_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("audit_success_ratio = ?"))
 	}
 
+	if update.AuditReputationAlpha._set {
+		__values = append(__values, update.AuditReputationAlpha.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("audit_reputation_alpha = ?"))
+	}
+
+	if update.AuditReputationBeta._set {
+		__values = append(__values, update.AuditReputationBeta.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("audit_reputation_beta = ?"))
+	}
+
 	if update.UptimeSuccessCount._set {
 		__values = append(__values, update.UptimeSuccessCount.value())
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("uptime_success_count = ?"))
@@ -794,6 +979,31 @@ func (obj *sqlite3Impl) Update_Node_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("uptime_ratio = ?"))
 	}
 
+	if update.UptimeReputationAlpha._set {
+		__values = append(__values, update.UptimeReputationAlpha.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("uptime_reputation_alpha = ?"))
+	}
+
+	if update.UptimeReputationBeta._set {
+		__values = append(__values, update.UptimeReputationBeta.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("uptime_reputation_beta = ?"))
+	}
+
+	if update.LatencySamples._set {
+		__values = append(__values, update.LatencySamples.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("latency_samples = ?"))
+	}
+
+	if update.Latency90._set {
+		__values = append(__values, update.Latency90.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("latency_90 = ?"))
+	}
+
+	if update.Disqualified._set {
+		__values = append(__values, update.Disqualified.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("disqualified = ?"))
+	}
+
 	__now := obj.db.Hooks.Now().UTC()
 
 	__values = append(__values, __now)
@@ -813,12 +1023,12 @@ func (obj *sqlite3Impl) Update_Node_By_Id(ctx context.Context,
 		return nil, obj.makeErr(err)
 	}
 
-	var __embed_stmt_get = __sqlbundle_Literal("SELECT nodes.id, nodes.audit_success_count, nodes.total_audit_count, nodes.audit_success_ratio, nodes.uptime_success_count, nodes.total_uptime_count, nodes.uptime_ratio, nodes.created_at, nodes.updated_at FROM nodes WHERE nodes.id = ?")
+	var __embed_stmt_get = __sqlbundle_Literal("SELECT nodes.id, nodes.audit_success_count, nodes.total_audit_count, nodes.audit_success_ratio, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.uptime_success_count, nodes.total_uptime_count, nodes.uptime_ratio, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.latency_samples, nodes.latency_90, nodes.disqualified, nodes.created_at, nodes.updated_at FROM nodes WHERE nodes.id = ?")
 
 	var __stmt_get = __sqlbundle_Render(obj.dialect, __embed_stmt_get)
 	obj.logStmt("(IMPLIED) "+__stmt_get, __args...)
 
-	err = obj.driver.QueryRow(__stmt_get, __args...).Scan(&node.Id, &node.AuditSuccessCount, &node.TotalAuditCount, &node.AuditSuccessRatio, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.UptimeRatio, &node.CreatedAt, &node.UpdatedAt)
+	err = obj.driver.QueryRow(__stmt_get, __args...).Scan(&node.Id, &node.AuditSuccessCount, &node.TotalAuditCount, &node.AuditSuccessRatio, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.UptimeRatio, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.LatencySamples, &node.Latency90, &node.Disqualified, &node.CreatedAt, &node.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -858,13 +1068,13 @@ func (obj *sqlite3Impl) getLastNode(ctx context.Context,
 	pk int64) (
 	node *Node, err error) {
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.audit_success_count, nodes.total_audit_count, nodes.audit_success_ratio, nodes.uptime_success_count, nodes.total_uptime_count, nodes.uptime_ratio, nodes.created_at, nodes.updated_at FROM nodes WHERE _rowid_ = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.audit_success_count, nodes.total_audit_count, nodes.audit_success_ratio, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.uptime_success_count, nodes.total_uptime_count, nodes.uptime_ratio, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.latency_samples, nodes.latency_90, nodes.disqualified, nodes.created_at, nodes.updated_at FROM nodes WHERE _rowid_ = ?")
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, pk)
 
 	node = &Node{}
-	err = obj.driver.QueryRow(__stmt, pk).Scan(&node.Id, &node.AuditSuccessCount, &node.TotalAuditCount, &node.AuditSuccessRatio, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.UptimeRatio, &node.CreatedAt, &node.UpdatedAt)
+	err = obj.driver.QueryRow(__stmt, pk).Scan(&node.Id, &node.AuditSuccessCount, &node.TotalAuditCount, &node.AuditSuccessRatio, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.UptimeRatio, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.LatencySamples, &node.Latency90, &node.Disqualified, &node.CreatedAt, &node.UpdatedAt)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -952,15 +1162,22 @@ func (rx *Rx) Create_Node(ctx context.Context,
 	node_audit_success_count Node_AuditSuccessCount_Field,
 	node_total_audit_count Node_TotalAuditCount_Field,
 	node_audit_success_ratio Node_AuditSuccessRatio_Field,
+	node_audit_reputation_alpha Node_AuditReputationAlpha_Field,
+	node_audit_reputation_beta Node_AuditReputationBeta_Field,
 	node_uptime_success_count Node_UptimeSuccessCount_Field,
 	node_total_uptime_count Node_TotalUptimeCount_Field,
-	node_uptime_ratio Node_UptimeRatio_Field) (
+	node_uptime_ratio Node_UptimeRatio_Field,
+	node_uptime_reputation_alpha Node_UptimeReputationAlpha_Field,
+	node_uptime_reputation_beta Node_UptimeReputationBeta_Field,
+	node_latency_samples Node_LatencySamples_Field,
+	node_latency_90 Node_Latency90_Field,
+	node_disqualified Node_Disqualified_Field) (
 	node *Node, err error) {
 	var tx *Tx
 	if tx, err = rx.getTx(ctx); err != nil {
 		return
 	}
-	return tx.Create_Node(ctx, node_id, node_audit_success_count, node_total_audit_count, node_audit_success_ratio, node_uptime_success_count, node_total_uptime_count, node_uptime_ratio)
+	return tx.Create_Node(ctx, node_id, node_audit_success_count, node_total_audit_count, node_audit_success_ratio, node_audit_reputation_alpha, node_audit_reputation_beta, node_uptime_success_count, node_total_uptime_count, node_uptime_ratio, node_uptime_reputation_alpha, node_uptime_reputation_beta, node_latency_samples, node_latency_90, node_disqualified)
 
 }
 
@@ -1001,9 +1218,16 @@ type Methods interface {
 		node_audit_success_count Node_AuditSuccessCount_Field,
 		node_total_audit_count Node_TotalAuditCount_Field,
 		node_audit_success_ratio Node_AuditSuccessRatio_Field,
+		node_audit_reputation_alpha Node_AuditReputationAlpha_Field,
+		node_audit_reputation_beta Node_AuditReputationBeta_Field,
 		node_uptime_success_count Node_UptimeSuccessCount_Field,
 		node_total_uptime_count Node_TotalUptimeCount_Field,
-		node_uptime_ratio Node_UptimeRatio_Field) (
+		node_uptime_ratio Node_UptimeRatio_Field,
+		node_uptime_reputation_alpha Node_UptimeReputationAlpha_Field,
+		node_uptime_reputation_beta Node_UptimeReputationBeta_Field,
+		node_latency_samples Node_LatencySamples_Field,
+		node_latency_90 Node_Latency90_Field,
+		node_disqualified Node_Disqualified_Field) (
 		node *Node, err error)
 
 	Delete_Node_By_Id(ctx context.Context,
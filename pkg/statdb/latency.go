@@ -0,0 +1,132 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// maxLatencySamples is the number of latency samples kept per node
+	maxLatencySamples = 100
+	// latencyHalfLife is the age at which a sample counts half as much as a
+	// fresh one when computing latency percentiles
+	latencyHalfLife = 24 * time.Hour
+	// maxLatencyAge is the age at which samples are dropped
+	maxLatencyAge = 10 * latencyHalfLife
+)
+
+// latencySample is a single latency measurement of a storage node
+type latencySample struct {
+	Time    int64 // unix seconds
+	Latency int64
+}
+
+// decodeLatencies parses samples serialized with encodeLatencies
+func decodeLatencies(data []byte) ([]latencySample, error) {
+	var samples []latencySample
+	for len(data) > 0 {
+		when, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, Error.New("invalid latency samples")
+		}
+		data = data[n:]
+
+		latency, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, Error.New("invalid latency samples")
+		}
+		data = data[n:]
+
+		samples = append(samples, latencySample{Time: when, Latency: latency})
+	}
+	return samples, nil
+}
+
+// encodeLatencies serializes samples for storing them in the db
+func encodeLatencies(samples []latencySample) []byte {
+	data := make([]byte, 0, len(samples)*2*binary.MaxVarintLen64)
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, sample := range samples {
+		n := binary.PutVarint(buf, sample.Time)
+		data = append(data, buf[:n]...)
+		n = binary.PutVarint(buf, sample.Latency)
+		data = append(data, buf[:n]...)
+	}
+	return data
+}
+
+// addLatencies appends the new latencies measured at now to samples,
+// dropping samples that are too old or exceed maxLatencySamples
+func addLatencies(samples []latencySample, latencies []int64, now time.Time) []latencySample {
+	cutoff := now.Add(-maxLatencyAge).Unix()
+
+	kept := make([]latencySample, 0, len(samples)+len(latencies))
+	for _, sample := range samples {
+		if sample.Time >= cutoff {
+			kept = append(kept, sample)
+		}
+	}
+	for _, latency := range latencies {
+		kept = append(kept, latencySample{Time: now.Unix(), Latency: latency})
+	}
+
+	if len(kept) > maxLatencySamples {
+		kept = kept[len(kept)-maxLatencySamples:]
+	}
+	return kept
+}
+
+// latencyPercentile returns the p-th percentile of samples, where each
+// sample is weighted by its age using latencyHalfLife
+func latencyPercentile(samples []latencySample, p float64, now time.Time) int64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	type weighted struct {
+		latency int64
+		weight  float64
+	}
+	sorted := make([]weighted, 0, len(samples))
+	total := 0.0
+	for _, sample := range samples {
+		age := now.Sub(time.Unix(sample.Time, 0))
+		if age < 0 {
+			age = 0
+		}
+		weight := math.Pow(0.5, float64(age)/float64(latencyHalfLife))
+		sorted = append(sorted, weighted{latency: sample.Latency, weight: weight})
+		total += weight
+	}
+	sort.Slice(sorted, func(i, k int) bool {
+		return sorted[i].latency < sorted[k].latency
+	})
+
+	// the threshold is lowered by a tiny fraction of the total, so that
+	// rounding errors don't skip the sample reaching it exactly
+	threshold := total * (p - 1e-9)
+	cumulative := 0.0
+	for _, s := range sorted {
+		cumulative += s.weight
+		if cumulative >= threshold {
+			return s.latency
+		}
+	}
+	return sorted[len(sorted)-1].latency
+}
+
+// updateLatencyVars adds latencies to the serialized samples and returns the
+// new serialized samples with their 90th percentile
+func updateLatencyVars(data []byte, latencies []int64, now time.Time) ([]byte, int64, error) {
+	samples, err := decodeLatencies(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	samples = addLatencies(samples, latencies, now)
+	return encodeLatencies(samples), latencyPercentile(samples, 0.9, now), nil
+}
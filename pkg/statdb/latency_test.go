@@ -0,0 +1,71 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyEncoding(t *testing.T) {
+	samples := []latencySample{
+		{Time: 1, Latency: 10},
+		{Time: 1538000000, Latency: 250},
+		{Time: -5, Latency: 0},
+	}
+
+	decoded, err := decodeLatencies(encodeLatencies(samples))
+	assert.NoError(t, err)
+	assert.Equal(t, samples, decoded)
+
+	decoded, err = decodeLatencies(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, decoded)
+
+	_, err = decodeLatencies([]byte{0x80})
+	assert.Error(t, err)
+}
+
+func TestAddLatencies(t *testing.T) {
+	now := time.Now()
+
+	old := []latencySample{{Time: now.Add(-maxLatencyAge - time.Hour).Unix(), Latency: 1000}}
+	samples := addLatencies(old, []int64{1, 2}, now)
+	assert.Equal(t, []latencySample{
+		{Time: now.Unix(), Latency: 1},
+		{Time: now.Unix(), Latency: 2},
+	}, samples)
+
+	var many []int64
+	for i := 0; i < 2*maxLatencySamples; i++ {
+		many = append(many, int64(i))
+	}
+	samples = addLatencies(samples, many, now)
+	assert.Len(t, samples, maxLatencySamples)
+	assert.Equal(t, int64(2*maxLatencySamples-1), samples[len(samples)-1].Latency)
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, int64(0), latencyPercentile(nil, 0.9, now))
+
+	var samples []latencySample
+	for i := int64(1); i <= 100; i++ {
+		samples = append(samples, latencySample{Time: now.Unix(), Latency: i})
+	}
+	assert.Equal(t, int64(90), latencyPercentile(samples, 0.9, now))
+
+	// slow samples from long ago barely count
+	var decayed []latencySample
+	for i := 0; i < 10; i++ {
+		decayed = append(decayed, latencySample{Time: now.Add(-9 * latencyHalfLife).Unix(), Latency: 5000})
+	}
+	for i := 0; i < 20; i++ {
+		decayed = append(decayed, latencySample{Time: now.Unix(), Latency: 100})
+	}
+	assert.Equal(t, int64(100), latencyPercentile(decayed, 0.9, now))
+}
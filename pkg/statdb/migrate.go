@@ -0,0 +1,108 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+import (
+	"fmt"
+
+	dbx "storj.io/storj/pkg/statdb/dbx"
+	"storj.io/storj/pkg/utils"
+)
+
+// column is a column of the nodes table added after the table was first
+// released
+type column struct {
+	name string
+	// kind is the dbx type of the column
+	kind string
+	// constraint follows the type in the column definition. The NOT NULL
+	// columns need a default for the existing nodes.
+	constraint string
+	// fill, if not nil, returns the SQL expression the column of the
+	// existing nodes is set to once added
+	fill func(audit, uptime ReputationConfig) string
+}
+
+// columnTypes are the SQL types of the dbx types per driver
+var columnTypes = map[string]map[string]string{
+	"sqlite3": {
+		"blob":      "BLOB",
+		"int64":     "INTEGER",
+		"float64":   "REAL",
+		"timestamp": "TIMESTAMP",
+	},
+	"postgres": {
+		"blob":      "bytea",
+		"int64":     "bigint",
+		"float64":   "double precision",
+		"timestamp": "timestamp with time zone",
+	},
+}
+
+// columns are the columns the nodes tables created by earlier versions
+// lack, in the order they were added
+var columns = []column{
+	// the reputations of the existing nodes start from their undecayed
+	// results
+	{name: "audit_reputation_alpha", kind: "float64", constraint: "NOT NULL DEFAULT 0",
+		fill: func(audit, uptime ReputationConfig) string {
+			return fmt.Sprintf("%v + audit_success_count", audit.Alpha0)
+		}},
+	{name: "audit_reputation_beta", kind: "float64", constraint: "NOT NULL DEFAULT 0",
+		fill: func(audit, uptime ReputationConfig) string {
+			return fmt.Sprintf("%v + total_audit_count - audit_success_count", audit.Beta0)
+		}},
+	{name: "uptime_reputation_alpha", kind: "float64", constraint: "NOT NULL DEFAULT 0",
+		fill: func(audit, uptime ReputationConfig) string {
+			return fmt.Sprintf("%v + uptime_success_count", uptime.Alpha0)
+		}},
+	{name: "uptime_reputation_beta", kind: "float64", constraint: "NOT NULL DEFAULT 0",
+		fill: func(audit, uptime ReputationConfig) string {
+			return fmt.Sprintf("%v + total_uptime_count - uptime_success_count", uptime.Beta0)
+		}},
+	{name: "latency_samples", kind: "blob", constraint: "NOT NULL DEFAULT ''"},
+	{name: "latency_90", kind: "int64", constraint: "NOT NULL DEFAULT 0"},
+	{name: "disqualified", kind: "timestamp"},
+}
+
+// migrate adds the missing columns to the nodes table of db, filling them
+// for the existing nodes with the reputation configs
+func migrate(db *dbx.DB, driver string, audit, uptime ReputationConfig) error {
+	for _, c := range columns {
+		if _, err := db.Exec(fmt.Sprintf("SELECT %s FROM nodes LIMIT 0", c.name)); err == nil {
+			continue
+		}
+		kind, ok := columnTypes[driver][c.kind]
+		if !ok {
+			return Error.New("can't migrate %s: unsupported driver %s", c.name, driver)
+		}
+		if err := addColumn(db, c, kind, audit, uptime); err != nil {
+			return Error.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// addColumn adds and fills c in a transaction, so that a column is never
+// left unfilled
+func addColumn(db *dbx.DB, c column, kind string, audit, uptime ReputationConfig) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = utils.CombineErrors(err, tx.Rollback())
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE nodes ADD COLUMN %s %s %s", c.name, kind, c.constraint))
+	if err != nil || c.fill == nil {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("UPDATE nodes SET %s = %s", c.name, c.fill(audit, uptime)))
+	return err
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	dbx "storj.io/storj/pkg/statdb/dbx"
+)
+
+// oldSchema is the nodes table before the reputations, the latency and the
+// disqualification were tracked
+const oldSchema = `CREATE TABLE nodes (
+	id TEXT NOT NULL,
+	audit_success_count INTEGER NOT NULL,
+	total_audit_count INTEGER NOT NULL,
+	audit_success_ratio REAL NOT NULL,
+	uptime_success_count INTEGER NOT NULL,
+	total_uptime_count INTEGER NOT NULL,
+	uptime_ratio REAL NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY ( id )
+);
+INSERT INTO nodes VALUES ('old', 3, 4, 0.75, 1, 1, 1,
+	'2018-09-01 00:00:00', '2018-09-01 00:00:00');`
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "statdb")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "stats.db")
+
+	db, err := sql.Open("sqlite3", path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = db.Exec(oldSchema)
+	assert.NoError(t, db.Close())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// opening the db twice checks that the migration is only applied once
+	for i := 0; i < 2; i++ {
+		s, err := NewServer("sqlite3", path, nil, nil, defaultReputation, defaultReputation, zap.NewNop())
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		node, err := s.DB.Get_Node_By_Id(ctx, dbx.Node_Id("old"))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, int64(4), node.TotalAuditCount)
+		assert.Equal(t, defaultReputation.Alpha0+3, node.AuditReputationAlpha)
+		assert.Equal(t, defaultReputation.Beta0+1, node.AuditReputationBeta)
+		assert.Equal(t, defaultReputation.Alpha0+1, node.UptimeReputationAlpha)
+		assert.Equal(t, defaultReputation.Beta0, node.UptimeReputationBeta)
+		assert.Nil(t, node.Disqualified)
+		if i == 0 {
+			assert.Empty(t, node.LatencySamples)
+			assert.Equal(t, int64(0), node.Latency90)
+		} else {
+			assert.Equal(t, int64(100), node.Latency90)
+		}
+
+		latencySamples, latency90, err := updateLatencyVars(node.LatencySamples, []int64{100}, node.UpdatedAt)
+		assert.NoError(t, err)
+		_, err = s.DB.Update_Node_By_Id(ctx, dbx.Node_Id("old"), dbx.Node_Update_Fields{
+			LatencySamples: dbx.Node_LatencySamples(latencySamples),
+			Latency90:      dbx.Node_Latency90(latency90),
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, s.DB.Close())
+	}
+}
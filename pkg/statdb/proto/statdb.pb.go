@@ -41,7 +41,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{0}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{0}
 }
 func (m *Node) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Node.Unmarshal(m, b)
@@ -116,6 +116,7 @@ type NodeStats struct {
 	Latency_90           int64    `protobuf:"varint,2,opt,name=latency_90,json=latency90,proto3" json:"latency_90,omitempty"`
 	AuditSuccessRatio    float64  `protobuf:"fixed64,3,opt,name=audit_success_ratio,json=auditSuccessRatio,proto3" json:"audit_success_ratio,omitempty"`
 	UptimeRatio          float64  `protobuf:"fixed64,4,opt,name=uptime_ratio,json=uptimeRatio,proto3" json:"uptime_ratio,omitempty"`
+	Disqualified         bool     `protobuf:"varint,5,opt,name=disqualified,proto3" json:"disqualified,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -125,7 +126,7 @@ func (m *NodeStats) Reset()         { *m = NodeStats{} }
 func (m *NodeStats) String() string { return proto.CompactTextString(m) }
 func (*NodeStats) ProtoMessage()    {}
 func (*NodeStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{1}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{1}
 }
 func (m *NodeStats) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeStats.Unmarshal(m, b)
@@ -173,6 +174,13 @@ func (m *NodeStats) GetUptimeRatio() float64 {
 	return 0
 }
 
+func (m *NodeStats) GetDisqualified() bool {
+	if m != nil {
+		return m.Disqualified
+	}
+	return false
+}
+
 // CreateRequest is a request message for the Create rpc call
 type CreateRequest struct {
 	Node                 *Node    `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
@@ -186,7 +194,7 @@ func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
 func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRequest) ProtoMessage()    {}
 func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{2}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{2}
 }
 func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateRequest.Unmarshal(m, b)
@@ -232,7 +240,7 @@ func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
 func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResponse) ProtoMessage()    {}
 func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{3}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{3}
 }
 func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateResponse.Unmarshal(m, b)
@@ -272,7 +280,7 @@ func (m *GetRequest) Reset()         { *m = GetRequest{} }
 func (m *GetRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRequest) ProtoMessage()    {}
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{4}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{4}
 }
 func (m *GetRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
@@ -318,7 +326,7 @@ func (m *GetResponse) Reset()         { *m = GetResponse{} }
 func (m *GetResponse) String() string { return proto.CompactTextString(m) }
 func (*GetResponse) ProtoMessage()    {}
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{5}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{5}
 }
 func (m *GetResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
@@ -358,7 +366,7 @@ func (m *UpdateRequest) Reset()         { *m = UpdateRequest{} }
 func (m *UpdateRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateRequest) ProtoMessage()    {}
 func (*UpdateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{6}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{6}
 }
 func (m *UpdateRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateRequest.Unmarshal(m, b)
@@ -404,7 +412,7 @@ func (m *UpdateResponse) Reset()         { *m = UpdateResponse{} }
 func (m *UpdateResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateResponse) ProtoMessage()    {}
 func (*UpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{7}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{7}
 }
 func (m *UpdateResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateResponse.Unmarshal(m, b)
@@ -444,7 +452,7 @@ func (m *UpdateBatchRequest) Reset()         { *m = UpdateBatchRequest{} }
 func (m *UpdateBatchRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateBatchRequest) ProtoMessage()    {}
 func (*UpdateBatchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{8}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{8}
 }
 func (m *UpdateBatchRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateBatchRequest.Unmarshal(m, b)
@@ -490,7 +498,7 @@ func (m *UpdateBatchResponse) Reset()         { *m = UpdateBatchResponse{} }
 func (m *UpdateBatchResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateBatchResponse) ProtoMessage()    {}
 func (*UpdateBatchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_statdb_c54091604b1717e8, []int{9}
+	return fileDescriptor_statdb_571afded7ea5609b, []int{9}
 }
 func (m *UpdateBatchResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateBatchResponse.Unmarshal(m, b)
@@ -517,6 +525,108 @@ func (m *UpdateBatchResponse) GetStatsList() []*NodeStats {
 	return nil
 }
 
+// FindValidNodesRequest is a request message for the FindValidNodes rpc call
+type FindValidNodesRequest struct {
+	NodeIds              [][]byte   `protobuf:"bytes,1,rep,name=node_ids,json=nodeIds,proto3" json:"node_ids,omitempty"`
+	MinStats             *NodeStats `protobuf:"bytes,2,opt,name=min_stats,json=minStats,proto3" json:"min_stats,omitempty"`
+	APIKey               []byte     `protobuf:"bytes,3,opt,name=APIKey,proto3" json:"APIKey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *FindValidNodesRequest) Reset()         { *m = FindValidNodesRequest{} }
+func (m *FindValidNodesRequest) String() string { return proto.CompactTextString(m) }
+func (*FindValidNodesRequest) ProtoMessage()    {}
+func (*FindValidNodesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_statdb_571afded7ea5609b, []int{10}
+}
+func (m *FindValidNodesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindValidNodesRequest.Unmarshal(m, b)
+}
+func (m *FindValidNodesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindValidNodesRequest.Marshal(b, m, deterministic)
+}
+func (dst *FindValidNodesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindValidNodesRequest.Merge(dst, src)
+}
+func (m *FindValidNodesRequest) XXX_Size() int {
+	return xxx_messageInfo_FindValidNodesRequest.Size(m)
+}
+func (m *FindValidNodesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindValidNodesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindValidNodesRequest proto.InternalMessageInfo
+
+func (m *FindValidNodesRequest) GetNodeIds() [][]byte {
+	if m != nil {
+		return m.NodeIds
+	}
+	return nil
+}
+
+func (m *FindValidNodesRequest) GetMinStats() *NodeStats {
+	if m != nil {
+		return m.MinStats
+	}
+	return nil
+}
+
+func (m *FindValidNodesRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+// FindValidNodesResponse is a response message for the FindValidNodes rpc call
+type FindValidNodesResponse struct {
+	PassedIds            [][]byte `protobuf:"bytes,1,rep,name=passed_ids,json=passedIds,proto3" json:"passed_ids,omitempty"`
+	FailedIds            [][]byte `protobuf:"bytes,2,rep,name=failed_ids,json=failedIds,proto3" json:"failed_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindValidNodesResponse) Reset()         { *m = FindValidNodesResponse{} }
+func (m *FindValidNodesResponse) String() string { return proto.CompactTextString(m) }
+func (*FindValidNodesResponse) ProtoMessage()    {}
+func (*FindValidNodesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_statdb_571afded7ea5609b, []int{11}
+}
+func (m *FindValidNodesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindValidNodesResponse.Unmarshal(m, b)
+}
+func (m *FindValidNodesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindValidNodesResponse.Marshal(b, m, deterministic)
+}
+func (dst *FindValidNodesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindValidNodesResponse.Merge(dst, src)
+}
+func (m *FindValidNodesResponse) XXX_Size() int {
+	return xxx_messageInfo_FindValidNodesResponse.Size(m)
+}
+func (m *FindValidNodesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindValidNodesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindValidNodesResponse proto.InternalMessageInfo
+
+func (m *FindValidNodesResponse) GetPassedIds() [][]byte {
+	if m != nil {
+		return m.PassedIds
+	}
+	return nil
+}
+
+func (m *FindValidNodesResponse) GetFailedIds() [][]byte {
+	if m != nil {
+		return m.FailedIds
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Node)(nil), "statdb.Node")
 	proto.RegisterType((*NodeStats)(nil), "statdb.NodeStats")
@@ -528,6 +638,8 @@ func init() {
 	proto.RegisterType((*UpdateResponse)(nil), "statdb.UpdateResponse")
 	proto.RegisterType((*UpdateBatchRequest)(nil), "statdb.UpdateBatchRequest")
 	proto.RegisterType((*UpdateBatchResponse)(nil), "statdb.UpdateBatchResponse")
+	proto.RegisterType((*FindValidNodesRequest)(nil), "statdb.FindValidNodesRequest")
+	proto.RegisterType((*FindValidNodesResponse)(nil), "statdb.FindValidNodesResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -550,6 +662,8 @@ type StatDBClient interface {
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	// UpdateBatch updates storagenode stats for multiple farmers at a time
 	UpdateBatch(ctx context.Context, in *UpdateBatchRequest, opts ...grpc.CallOption) (*UpdateBatchResponse, error)
+	// FindValidNodes filters a list of storagenodes by minimum stats
+	FindValidNodes(ctx context.Context, in *FindValidNodesRequest, opts ...grpc.CallOption) (*FindValidNodesResponse, error)
 }
 
 type statDBClient struct {
@@ -596,6 +710,15 @@ func (c *statDBClient) UpdateBatch(ctx context.Context, in *UpdateBatchRequest,
 	return out, nil
 }
 
+func (c *statDBClient) FindValidNodes(ctx context.Context, in *FindValidNodesRequest, opts ...grpc.CallOption) (*FindValidNodesResponse, error) {
+	out := new(FindValidNodesResponse)
+	err := c.cc.Invoke(ctx, "/statdb.StatDB/FindValidNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StatDBServer is the server API for StatDB service.
 type StatDBServer interface {
 	// Create a db entry for the provided storagenode ID
@@ -606,6 +729,8 @@ type StatDBServer interface {
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
 	// UpdateBatch updates storagenode stats for multiple farmers at a time
 	UpdateBatch(context.Context, *UpdateBatchRequest) (*UpdateBatchResponse, error)
+	// FindValidNodes filters a list of storagenodes by minimum stats
+	FindValidNodes(context.Context, *FindValidNodesRequest) (*FindValidNodesResponse, error)
 }
 
 func RegisterStatDBServer(s *grpc.Server, srv StatDBServer) {
@@ -684,6 +809,24 @@ func _StatDB_UpdateBatch_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatDB_FindValidNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindValidNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatDBServer).FindValidNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/statdb.StatDB/FindValidNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatDBServer).FindValidNodes(ctx, req.(*FindValidNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _StatDB_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "statdb.StatDB",
 	HandlerType: (*StatDBServer)(nil),
@@ -704,45 +847,56 @@ var _StatDB_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateBatch",
 			Handler:    _StatDB_UpdateBatch_Handler,
 		},
+		{
+			MethodName: "FindValidNodes",
+			Handler:    _StatDB_FindValidNodes_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "statdb.proto",
 }
 
-func init() { proto.RegisterFile("statdb.proto", fileDescriptor_statdb_c54091604b1717e8) }
-
-var fileDescriptor_statdb_c54091604b1717e8 = []byte{
-	// 499 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xc1, 0x6a, 0xdb, 0x40,
-	0x10, 0x45, 0x91, 0xac, 0xc4, 0x23, 0x39, 0x90, 0x71, 0x9b, 0x0a, 0x97, 0x82, 0xaa, 0x50, 0xea,
-	0x5e, 0x8c, 0x49, 0xa1, 0xc1, 0x87, 0x1e, 0x92, 0x96, 0x1a, 0xd3, 0x50, 0xca, 0x06, 0xd3, 0xa3,
-	0x50, 0xac, 0x85, 0x0a, 0x52, 0x4b, 0xf5, 0x8e, 0x0e, 0xf9, 0x91, 0x7e, 0x68, 0x8f, 0x3d, 0x95,
-	0x9d, 0x5d, 0x61, 0x29, 0x8d, 0x0f, 0x86, 0x1e, 0xf7, 0xbd, 0xd9, 0xf7, 0xde, 0xbe, 0xb1, 0x0c,
-	0xa1, 0xa2, 0x8c, 0xf2, 0xdb, 0x49, 0xb5, 0x29, 0xa9, 0x44, 0xdf, 0x9c, 0x92, 0x3f, 0x0e, 0x78,
-	0x5f, 0xca, 0x5c, 0xe2, 0x33, 0x38, 0x5c, 0x97, 0xb9, 0x4c, 0x8b, 0x3c, 0x72, 0x62, 0x67, 0x1c,
-	0x0a, 0x5f, 0x1f, 0x17, 0x39, 0xbe, 0x84, 0xf0, 0x2e, 0x23, 0xb9, 0x5e, 0xdd, 0xa7, 0x77, 0x85,
-	0xa2, 0xe8, 0x20, 0x76, 0xc7, 0xae, 0x08, 0x2c, 0x76, 0x5d, 0x28, 0xc2, 0x33, 0x18, 0x64, 0x75,
-	0x5e, 0x50, 0xaa, 0xea, 0xd5, 0x4a, 0x2a, 0x15, 0xb9, 0xb1, 0x33, 0x3e, 0x12, 0x21, 0x83, 0x37,
-	0x06, 0xc3, 0x21, 0xf4, 0x0a, 0x95, 0xd6, 0x55, 0xe4, 0x31, 0xe9, 0x15, 0x6a, 0x59, 0xe1, 0x2b,
-	0x38, 0xae, 0xab, 0x3c, 0x23, 0x99, 0x5a, 0xbd, 0xa8, 0xc7, 0xec, 0xc0, 0xa0, 0xd7, 0x06, 0xc4,
-	0x29, 0x3c, 0xb1, 0x63, 0x5d, 0x1f, 0x9f, 0x87, 0xd1, 0x70, 0x97, 0x6d, 0xb7, 0x33, 0xb0, 0x12,
-	0x69, 0x5d, 0x51, 0xf1, 0x43, 0x46, 0x87, 0x26, 0x92, 0x01, 0x97, 0x8c, 0x25, 0xbf, 0x1c, 0xe8,
-	0xeb, 0xc7, 0xdf, 0x50, 0x46, 0x6a, 0x77, 0x03, 0x2f, 0x00, 0x9a, 0x06, 0x66, 0xd3, 0xe8, 0x20,
-	0x76, 0xc6, 0xae, 0xe8, 0x5b, 0x64, 0x36, 0xc5, 0x09, 0x0c, 0x3b, 0xa9, 0xd2, 0x4d, 0x46, 0x45,
-	0xc9, 0x1d, 0x38, 0xe2, 0xa4, 0xdd, 0x81, 0xd0, 0x84, 0x2e, 0xd4, 0x64, 0xb2, 0x83, 0x1e, 0x0f,
-	0x06, 0x06, 0xe3, 0x91, 0x64, 0x01, 0x83, 0x0f, 0x1b, 0x99, 0x91, 0x14, 0xf2, 0x67, 0x2d, 0x15,
-	0x61, 0x0c, 0x9e, 0x0e, 0xc3, 0xc1, 0x82, 0xf3, 0x70, 0x62, 0x77, 0xa9, 0xc3, 0x0b, 0x66, 0xf0,
-	0x14, 0xfc, 0xcb, 0xaf, 0x8b, 0xcf, 0xf2, 0x9e, 0x03, 0x86, 0xc2, 0x9e, 0x92, 0x19, 0x1c, 0x37,
-	0x52, 0xaa, 0x2a, 0xd7, 0x4a, 0xe2, 0x6b, 0xe8, 0xe9, 0xeb, 0xca, 0x8a, 0x9d, 0xb4, 0xc5, 0xb8,
-	0x09, 0x61, 0xf8, 0xe4, 0x3d, 0xc0, 0x5c, 0x52, 0x13, 0x61, 0x67, 0x3d, 0xbb, 0x9c, 0xdf, 0x41,
-	0xc0, 0xd7, 0xf7, 0xb5, 0x5d, 0xc0, 0x60, 0xc9, 0x5b, 0xfa, 0x2f, 0x8f, 0x6f, 0xa4, 0xf6, 0x4d,
-	0xf1, 0x0d, 0xd0, 0x5c, 0xbd, 0xca, 0x68, 0xf5, 0xbd, 0x89, 0xf2, 0x06, 0xfa, 0x5c, 0x02, 0x7f,
-	0x09, 0x4e, 0xec, 0xfe, 0x93, 0xe7, 0x48, 0xd3, 0xfc, 0x51, 0xec, 0xca, 0x34, 0x87, 0x61, 0x47,
-	0xd8, 0x06, 0x9b, 0x02, 0xb0, 0x71, 0x5b, 0xfa, 0x91, 0x74, 0x7d, 0x1e, 0xd2, 0x06, 0xe7, 0xbf,
-	0x1d, 0xf0, 0x35, 0xf8, 0xf1, 0x0a, 0x2f, 0xc0, 0x37, 0x4b, 0xc6, 0xa7, 0xcd, 0x95, 0xce, 0xef,
-	0x67, 0x74, 0xfa, 0x10, 0xb6, 0xae, 0x13, 0x70, 0xe7, 0x92, 0x10, 0x1b, 0x7a, 0xbb, 0xef, 0xd1,
-	0xb0, 0x83, 0xd9, 0xf9, 0x0b, 0xf0, 0x4d, 0xf8, 0xad, 0x51, 0x67, 0x57, 0x5b, 0xa3, 0x07, 0xbd,
-	0x7f, 0x82, 0xa0, 0xf5, 0x6a, 0x1c, 0x75, 0xc7, 0xda, 0x1d, 0x8f, 0x9e, 0x3f, 0xca, 0x19, 0x9d,
-	0x5b, 0x9f, 0xff, 0xbe, 0xde, 0xfe, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x3d, 0x96, 0xbe, 0xeb, 0xce,
-	0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("statdb.proto", fileDescriptor_statdb_571afded7ea5609b) }
+
+var fileDescriptor_statdb_571afded7ea5609b = []byte{
+	// 612 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4f, 0x6b, 0xdb, 0x4e,
+	0x10, 0x45, 0x96, 0xa3, 0x58, 0x63, 0xd9, 0x90, 0xf5, 0x2f, 0xfe, 0xa9, 0x2a, 0x2e, 0xaa, 0x42,
+	0xa9, 0x7b, 0x31, 0x26, 0x85, 0x06, 0x1f, 0x7a, 0x48, 0x5a, 0x62, 0x4c, 0x43, 0x5b, 0x14, 0x9c,
+	0x1e, 0xc5, 0xc6, 0xbb, 0xa1, 0x0b, 0xb6, 0xa4, 0x78, 0x57, 0x87, 0xf4, 0xa3, 0xf5, 0xda, 0x6f,
+	0xd5, 0x53, 0xd9, 0x3f, 0x22, 0x92, 0x6b, 0x1d, 0x02, 0xbd, 0x59, 0xef, 0xcd, 0xbe, 0x79, 0xf3,
+	0x66, 0x17, 0x83, 0xc7, 0x05, 0x16, 0xe4, 0x76, 0x92, 0x6f, 0x33, 0x91, 0x21, 0x47, 0x7f, 0x45,
+	0xbf, 0x2d, 0x68, 0x7f, 0xce, 0x08, 0x45, 0xff, 0xc3, 0x61, 0x9a, 0x11, 0x9a, 0x30, 0xe2, 0x5b,
+	0xa1, 0x35, 0xf6, 0x62, 0x47, 0x7e, 0x2e, 0x08, 0x7a, 0x09, 0xde, 0x1a, 0x0b, 0x9a, 0xae, 0x1e,
+	0x92, 0x35, 0xe3, 0xc2, 0x6f, 0x85, 0xf6, 0xd8, 0x8e, 0xbb, 0x06, 0xbb, 0x62, 0x5c, 0xa0, 0x13,
+	0xe8, 0xe1, 0x82, 0x30, 0x91, 0xf0, 0x62, 0xb5, 0xa2, 0x9c, 0xfb, 0x76, 0x68, 0x8d, 0x3b, 0xb1,
+	0xa7, 0xc0, 0x6b, 0x8d, 0xa1, 0x01, 0x1c, 0x30, 0x9e, 0x14, 0xb9, 0xdf, 0x56, 0x64, 0x9b, 0xf1,
+	0x65, 0x8e, 0x5e, 0x41, 0xbf, 0xc8, 0x09, 0x16, 0x34, 0x31, 0x7a, 0xfe, 0x81, 0x62, 0x7b, 0x1a,
+	0xbd, 0xd2, 0x20, 0x9a, 0xc2, 0x7f, 0xa6, 0xac, 0xde, 0xc7, 0x51, 0xc5, 0x48, 0x73, 0xe7, 0xd5,
+	0x6e, 0x27, 0x60, 0x24, 0x92, 0x22, 0x17, 0x6c, 0x43, 0xfd, 0x43, 0x6d, 0x49, 0x83, 0x4b, 0x85,
+	0x45, 0x3f, 0x2d, 0x70, 0xe5, 0xf0, 0xd7, 0x02, 0x0b, 0xde, 0x9c, 0xc0, 0x08, 0xa0, 0x4c, 0x60,
+	0x36, 0xf5, 0x5b, 0xa1, 0x35, 0xb6, 0x63, 0xd7, 0x20, 0xb3, 0x29, 0x9a, 0xc0, 0xa0, 0xe6, 0x2a,
+	0xd9, 0x62, 0xc1, 0x32, 0x95, 0x81, 0x15, 0x1f, 0x55, 0x33, 0x88, 0x25, 0x21, 0x03, 0xd5, 0x9e,
+	0x4c, 0x61, 0x5b, 0x15, 0x76, 0x35, 0xa6, 0x4b, 0x22, 0xf0, 0x08, 0xe3, 0xf7, 0x05, 0x5e, 0xb3,
+	0x3b, 0x46, 0x89, 0x09, 0xa5, 0x86, 0x45, 0x0b, 0xe8, 0x7d, 0xd8, 0x52, 0x2c, 0x68, 0x4c, 0xef,
+	0x0b, 0xca, 0x05, 0x0a, 0xa1, 0x2d, 0x0d, 0x2b, 0xf3, 0xdd, 0x53, 0x6f, 0x62, 0xf6, 0x2d, 0x07,
+	0x8c, 0x15, 0x83, 0x86, 0xe0, 0x9c, 0x7f, 0x5d, 0x7c, 0xa2, 0x0f, 0x6a, 0x08, 0x2f, 0x36, 0x5f,
+	0xd1, 0x0c, 0xfa, 0xa5, 0x14, 0xcf, 0xb3, 0x94, 0x53, 0xf4, 0x1a, 0x0e, 0xe4, 0x71, 0x6e, 0xc4,
+	0x8e, 0xaa, 0x62, 0x2a, 0xad, 0x58, 0xf3, 0xd1, 0x7b, 0x80, 0x39, 0x15, 0xa5, 0x85, 0xc6, 0x08,
+	0x9b, 0x3a, 0xbf, 0x83, 0xae, 0x3a, 0xfe, 0xd4, 0xb6, 0x0b, 0xe8, 0x2d, 0xd5, 0x26, 0xff, 0xc9,
+	0xf0, 0xa5, 0xd4, 0x53, 0x5d, 0x7c, 0x03, 0xa4, 0x8f, 0x5e, 0x60, 0xb1, 0xfa, 0x5e, 0x5a, 0x79,
+	0x03, 0xae, 0x0a, 0x41, 0xbd, 0x16, 0x2b, 0xb4, 0xff, 0xf2, 0xd3, 0x91, 0xb4, 0x7a, 0x38, 0x4d,
+	0x9e, 0xe6, 0x30, 0xa8, 0x09, 0x1b, 0x63, 0x53, 0x00, 0xd5, 0xb8, 0x2a, 0xbd, 0xc7, 0x9d, 0xab,
+	0x8a, 0x64, 0x83, 0xe8, 0x07, 0x1c, 0x5f, 0xb2, 0x94, 0xdc, 0xe0, 0x35, 0x23, 0xb2, 0x80, 0x97,
+	0x26, 0x9f, 0x41, 0xc7, 0x6c, 0x8a, 0x2b, 0x21, 0x2f, 0x3e, 0xd4, 0xab, 0xe2, 0x68, 0x02, 0xee,
+	0x86, 0xa5, 0x89, 0x8e, 0xa0, 0xd5, 0x14, 0x41, 0x67, 0xc3, 0x52, 0xf5, 0xab, 0x32, 0x84, 0x5d,
+	0x1b, 0xe2, 0x06, 0x86, 0xbb, 0xbd, 0xcd, 0x1c, 0x23, 0x80, 0x1c, 0x73, 0x4e, 0x49, 0xa5, 0xbd,
+	0xab, 0x11, 0x69, 0x60, 0x04, 0x70, 0x87, 0xd9, 0xda, 0xd0, 0x2d, 0x4d, 0x6b, 0x64, 0x41, 0xf8,
+	0xe9, 0xaf, 0x16, 0x38, 0xb2, 0xf3, 0xc7, 0x0b, 0x74, 0x06, 0x8e, 0xbe, 0xb8, 0xe8, 0xb8, 0x74,
+	0x58, 0x7b, 0x13, 0xc1, 0x70, 0x17, 0x36, 0x0e, 0x26, 0x60, 0xcf, 0xa9, 0x40, 0xa8, 0xa4, 0x1f,
+	0xef, 0x70, 0x30, 0xa8, 0x61, 0xa6, 0xfe, 0x0c, 0x1c, 0xbd, 0x90, 0xc7, 0x46, 0xb5, 0xfb, 0x17,
+	0x0c, 0x77, 0x61, 0x73, 0xf0, 0x12, 0xba, 0x95, 0x4d, 0xa2, 0xa0, 0x5e, 0x56, 0xbd, 0x37, 0xc1,
+	0xf3, 0xbd, 0x9c, 0xd1, 0xf9, 0x02, 0xfd, 0x7a, 0x98, 0x68, 0x54, 0x96, 0xef, 0x5d, 0x70, 0xf0,
+	0xa2, 0x89, 0xd6, 0x82, 0xb7, 0x8e, 0xfa, 0x1f, 0x78, 0xfb, 0x67, 0x00, 0x89, 0x48, 0xb3, 0xae,
+	0x17, 0x06, 0x00, 0x00,
 }
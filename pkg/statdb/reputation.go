@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+// ReputationConfig configures the time-decayed reputation computed from one
+// kind of storagenode check, e.g. audits.
+//
+// Every result decays the previous alpha and beta by Lambda and adds Weight
+// to alpha on success or to beta on failure. The reputation is
+// alpha / (alpha + beta).
+type ReputationConfig struct {
+	Alpha0 float64 `help:"the initial alpha of new storagenodes" default:"20"`
+	Beta0  float64 `help:"the initial beta of new storagenodes" default:"0"`
+	Lambda float64 `help:"the factor previous results are decayed by on every new result" default:"0.95"`
+	Weight float64 `help:"the weight of a new result" default:"1"`
+	DQ     float64 `help:"the reputation below which storagenodes are disqualified" default:"0.6"`
+}
+
+// defaultReputation matches the defaults of ReputationConfig
+var defaultReputation = ReputationConfig{
+	Alpha0: 20,
+	Beta0:  0,
+	Lambda: 0.95,
+	Weight: 1,
+	DQ:     0.6,
+}
+
+// reputation returns the reputation for alpha and beta. Without any
+// evidence the reputation is perfect.
+func reputation(alpha, beta float64) float64 {
+	if alpha+beta <= 0 {
+		return 1
+	}
+	return alpha / (alpha + beta)
+}
+
+// updateReputation adds a single result to alpha and beta and returns the
+// new alpha, beta and reputation
+func updateReputation(success bool, alpha, beta float64, config ReputationConfig) (float64, float64, float64) {
+	v := -1.0
+	if success {
+		v = 1
+	}
+	alpha = config.Lambda*alpha + config.Weight*(1+v)/2
+	beta = config.Lambda*beta + config.Weight*(1-v)/2
+	return alpha, beta, reputation(alpha, beta)
+}
+
+// initReputation returns alpha, beta and the reputation of a new storagenode,
+// including its first result if shouldUpdate is set
+func initReputation(shouldUpdate, success bool, config ReputationConfig) (float64, float64, float64) {
+	if shouldUpdate {
+		return updateReputation(success, config.Alpha0, config.Beta0, config)
+	}
+	return config.Alpha0, config.Beta0, reputation(config.Alpha0, config.Beta0)
+}
+
+// isDisqualified returns whether the reputations are below their
+// disqualification thresholds
+func isDisqualified(auditReputation, uptimeReputation float64, audit, uptime ReputationConfig) bool {
+	return auditReputation < audit.DQ || uptimeReputation < uptime.DQ
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitReputation(t *testing.T) {
+	alpha, beta, rep := initReputation(false, false, defaultReputation)
+	assert.Equal(t, defaultReputation.Alpha0, alpha)
+	assert.Equal(t, defaultReputation.Beta0, beta)
+	assert.Equal(t, float64(1), rep)
+
+	alpha, beta, rep = initReputation(true, true, defaultReputation)
+	assert.InDelta(t, 20*0.95+1, alpha, 1e-9)
+	assert.Equal(t, float64(0), beta)
+	assert.Equal(t, float64(1), rep)
+
+	// no evidence at all isn't a bad reputation
+	_, _, rep = initReputation(false, false, ReputationConfig{})
+	assert.Equal(t, float64(1), rep)
+}
+
+func TestReputationDecay(t *testing.T) {
+	alpha, beta := defaultReputation.Alpha0, defaultReputation.Beta0
+
+	// a long perfect history doesn't shield a node from recent failures
+	for i := 0; i < 1000; i++ {
+		alpha, beta, _ = updateReputation(true, alpha, beta, defaultReputation)
+	}
+
+	var rep float64
+	for i := 1; i <= 10; i++ {
+		alpha, beta, rep = updateReputation(false, alpha, beta, defaultReputation)
+		assert.InDelta(t, math.Pow(0.95, float64(i)), rep, 1e-9, i)
+
+		disqualified := isDisqualified(rep, 1, defaultReputation, defaultReputation)
+		assert.Equal(t, i >= 10, disqualified, i)
+	}
+
+	// and recovers with new successes
+	for i := 0; i < 100; i++ {
+		alpha, beta, rep = updateReputation(true, alpha, beta, defaultReputation)
+	}
+	assert.True(t, rep > 0.99)
+	assert.False(t, isDisqualified(1, rep, defaultReputation, defaultReputation))
+}
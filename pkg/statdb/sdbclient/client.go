@@ -0,0 +1,142 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sdbclient
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/provider"
+	pb "storj.io/storj/pkg/statdb/proto"
+)
+
+var (
+	mon = monkit.Package()
+)
+
+// StatDB creates a grpcClient
+type StatDB struct {
+	grpcClient pb.StatDBClient
+	APIKey     []byte
+}
+
+// Client services offerred for the interface
+type Client interface {
+	Create(ctx context.Context, nodeID []byte) (*pb.NodeStats, error)
+	Get(ctx context.Context, nodeID []byte) (*pb.NodeStats, error)
+	Update(ctx context.Context, node *pb.Node) (*pb.NodeStats, error)
+	UpdateBatch(ctx context.Context, nodes []*pb.Node) ([]*pb.NodeStats, error)
+	FindValidNodes(ctx context.Context, nodeIDs [][]byte, minStats *pb.NodeStats) (
+		passed, failed [][]byte, err error)
+}
+
+// NewClient initializes a new statdb client
+func NewClient(identity *provider.FullIdentity, address string, APIKey []byte) (*StatDB, error) {
+	dialOpt, err := identity.DialOption()
+	if err != nil {
+		return nil, err
+	}
+	c, err := clientConnection(address, dialOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatDB{
+		grpcClient: c,
+		APIKey:     APIKey,
+	}, nil
+}
+
+// a compiler trick to make sure *StatDB implements Client
+var _ Client = (*StatDB)(nil)
+
+// ClientConnection makes a server connection
+func clientConnection(serverAddr string, opts ...grpc.DialOption) (pb.StatDBClient, error) {
+	conn, err := grpc.Dial(serverAddr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return pb.NewStatDBClient(conn), nil
+}
+
+// Create is used for creating a new entry in the stats db with default reputation
+func (sdb *StatDB) Create(ctx context.Context, nodeID []byte) (stats *pb.NodeStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := sdb.grpcClient.Create(ctx, &pb.CreateRequest{
+		Node:   &pb.Node{NodeId: nodeID},
+		APIKey: sdb.APIKey,
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return res.GetStats(), nil
+}
+
+// Get is used for retrieving a node's stats
+func (sdb *StatDB) Get(ctx context.Context, nodeID []byte) (stats *pb.NodeStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := sdb.grpcClient.Get(ctx, &pb.GetRequest{
+		NodeId: nodeID,
+		APIKey: sdb.APIKey,
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return res.GetStats(), nil
+}
+
+// Update is used for updating a node's stats
+func (sdb *StatDB) Update(ctx context.Context, node *pb.Node) (stats *pb.NodeStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := sdb.grpcClient.Update(ctx, &pb.UpdateRequest{
+		Node:   node,
+		APIKey: sdb.APIKey,
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return res.GetStats(), nil
+}
+
+// UpdateBatch is used for updating multiple nodes' stats at once. Nodes
+// unknown to the stats db are created.
+func (sdb *StatDB) UpdateBatch(ctx context.Context, nodes []*pb.Node) (statsList []*pb.NodeStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := sdb.grpcClient.UpdateBatch(ctx, &pb.UpdateBatchRequest{
+		NodeList: nodes,
+		APIKey:   sdb.APIKey,
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return res.GetStatsList(), nil
+}
+
+// FindValidNodes splits nodeIDs into the nodes that have at least minStats
+// and the nodes that don't
+func (sdb *StatDB) FindValidNodes(ctx context.Context, nodeIDs [][]byte, minStats *pb.NodeStats) (
+	passed, failed [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := sdb.grpcClient.FindValidNodes(ctx, &pb.FindValidNodesRequest{
+		NodeIds:  nodeIDs,
+		MinStats: minStats,
+		APIKey:   sdb.APIKey,
+	})
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	return res.GetPassedIds(), res.GetFailedIds(), nil
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sdbclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	pb "storj.io/storj/pkg/statdb/proto"
+)
+
+var (
+	ctx    = context.Background()
+	apiKey = []byte("abc123")
+)
+
+func TestNewStatDBClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockStatDBClient(ctrl)
+	sdb := StatDB{grpcClient: gc}
+
+	assert.NotNil(t, sdb)
+	assert.NotNil(t, sdb.grpcClient)
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockStatDBClient(ctrl)
+	sdb := StatDB{grpcClient: gc, APIKey: apiKey}
+
+	nodeID := []byte("node1")
+	stats := &pb.NodeStats{NodeId: nodeID, AuditSuccessRatio: 1, UptimeRatio: 1}
+
+	for i, tt := range []struct {
+		err    error
+		errStr string
+	}{
+		{nil, ""},
+		{errors.New("some error"), "statdb client error: some error"},
+	} {
+		errTag := i
+		req := &pb.CreateRequest{Node: &pb.Node{NodeId: nodeID}, APIKey: apiKey}
+		gc.EXPECT().Create(gomock.Any(), req).Return(&pb.CreateResponse{Stats: stats}, tt.err)
+
+		res, err := sdb.Create(ctx, nodeID)
+		if tt.err != nil {
+			assert.EqualError(t, err, tt.errStr, errTag)
+			assert.Nil(t, res, errTag)
+		} else {
+			assert.NoError(t, err, errTag)
+			assert.Equal(t, stats, res, errTag)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockStatDBClient(ctrl)
+	sdb := StatDB{grpcClient: gc, APIKey: apiKey}
+
+	nodeID := []byte("node1")
+	stats := &pb.NodeStats{NodeId: nodeID, Latency_90: 100}
+
+	req := &pb.GetRequest{NodeId: nodeID, APIKey: apiKey}
+	gc.EXPECT().Get(gomock.Any(), req).Return(&pb.GetResponse{Stats: stats}, nil)
+
+	res, err := sdb.Get(ctx, nodeID)
+	assert.NoError(t, err)
+	assert.Equal(t, stats, res)
+}
+
+func TestUpdateBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockStatDBClient(ctrl)
+	sdb := StatDB{grpcClient: gc, APIKey: apiKey}
+
+	nodes := []*pb.Node{
+		{NodeId: []byte("node1"), IsUp: true, UpdateUptime: true},
+		{NodeId: []byte("node2"), AuditSuccess: true, UpdateAuditSuccess: true},
+	}
+	statsList := []*pb.NodeStats{
+		{NodeId: []byte("node1"), UptimeRatio: 1},
+		{NodeId: []byte("node2"), AuditSuccessRatio: 1},
+	}
+
+	req := &pb.UpdateBatchRequest{NodeList: nodes, APIKey: apiKey}
+	gc.EXPECT().UpdateBatch(gomock.Any(), req).Return(&pb.UpdateBatchResponse{StatsList: statsList}, nil)
+
+	res, err := sdb.UpdateBatch(ctx, nodes)
+	assert.NoError(t, err)
+	assert.Equal(t, statsList, res)
+}
+
+func TestFindValidNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockStatDBClient(ctrl)
+	sdb := StatDB{grpcClient: gc, APIKey: apiKey}
+
+	nodeIDs := [][]byte{[]byte("node1"), []byte("node2"), []byte("node3")}
+	minStats := &pb.NodeStats{AuditSuccessRatio: 0.5, UptimeRatio: 0.5, Latency_90: 200}
+
+	req := &pb.FindValidNodesRequest{NodeIds: nodeIDs, MinStats: minStats, APIKey: apiKey}
+	gc.EXPECT().FindValidNodes(gomock.Any(), req).Return(&pb.FindValidNodesResponse{
+		PassedIds: nodeIDs[:2],
+		FailedIds: nodeIDs[2:],
+	}, nil)
+
+	passed, failed, err := sdb.FindValidNodes(ctx, nodeIDs, minStats)
+	assert.NoError(t, err)
+	assert.Equal(t, nodeIDs[:2], passed)
+	assert.Equal(t, nodeIDs[2:], failed)
+
+	gc.EXPECT().FindValidNodes(gomock.Any(), req).Return(nil, errors.New("some error"))
+
+	passed, failed, err = sdb.FindValidNodes(ctx, nodeIDs, minStats)
+	assert.EqualError(t, err, "statdb client error: some error")
+	assert.Nil(t, passed)
+	assert.Nil(t, failed)
+}
+
+func TestLatencyRecorderFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockStatDBClient(ctrl)
+	sdb := &StatDB{grpcClient: gc, APIKey: apiKey}
+	lr := NewLatencyRecorder(sdb, 10)
+
+	// nothing buffered, nothing sent
+	assert.NoError(t, lr.Flush(ctx))
+
+	lr.RecordLatency("node1", 15*time.Millisecond)
+	lr.RecordLatency("node1", 25*time.Millisecond)
+
+	req := &pb.UpdateBatchRequest{
+		NodeList: []*pb.Node{{
+			NodeId:        []byte("node1"),
+			LatencyList:   []int64{15, 25},
+			UpdateLatency: true,
+		}},
+		APIKey: apiKey,
+	}
+	gc.EXPECT().UpdateBatch(gomock.Any(), req).Return(&pb.UpdateBatchResponse{}, nil)
+
+	assert.NoError(t, lr.Flush(ctx))
+	// the buffer is emptied by a flush
+	assert.NoError(t, lr.Flush(ctx))
+}
+
+func TestLatencyRecorderFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockStatDBClient(ctrl)
+	sdb := &StatDB{grpcClient: gc, APIKey: apiKey}
+	lr := NewLatencyRecorder(sdb, 10)
+
+	// no failures, nothing buffered
+	lr.RecordFailures(nil, nil)
+	assert.NoError(t, lr.Flush(ctx))
+
+	lr.RecordFailures([]string{"node1"}, []string{"node2"})
+
+	req := &pb.UpdateBatchRequest{
+		NodeList: []*pb.Node{{
+			NodeId:             []byte("node1"),
+			AuditSuccess:       false,
+			UpdateAuditSuccess: true,
+		}, {
+			NodeId:       []byte("node2"),
+			IsUp:         false,
+			UpdateUptime: true,
+		}},
+		APIKey: apiKey,
+	}
+	gc.EXPECT().UpdateBatch(gomock.Any(), req).Return(&pb.UpdateBatchResponse{}, nil)
+
+	assert.NoError(t, lr.Flush(ctx))
+}
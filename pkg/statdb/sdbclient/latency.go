@@ -0,0 +1,115 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sdbclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "storj.io/storj/pkg/statdb/proto"
+)
+
+// LatencyRecorder buffers storage node latencies and failures and reports
+// them to the stats db in batches
+type LatencyRecorder struct {
+	client    Client
+	batchSize int
+
+	mu        sync.Mutex
+	latencies map[string][]int64
+	failures  []*pb.Node
+	count     int
+}
+
+// NewLatencyRecorder creates a LatencyRecorder reporting to client once
+// batchSize latencies are buffered
+func NewLatencyRecorder(client Client, batchSize int) *LatencyRecorder {
+	return &LatencyRecorder{
+		client:    client,
+		batchSize: batchSize,
+		latencies: map[string][]int64{},
+	}
+}
+
+// RecordLatency buffers the latency of the node with nodeID in milliseconds
+func (lr *LatencyRecorder) RecordLatency(nodeID string, latency time.Duration) {
+	lr.mu.Lock()
+	lr.latencies[nodeID] = append(lr.latencies[nodeID], int64(latency/time.Millisecond))
+	lr.count++
+	lr.flushIfFull()
+}
+
+// RecordFailures buffers a failed audit for each node that returned
+// corrupted data and a failed uptime check for each node that failed to
+// return its piece
+func (lr *LatencyRecorder) RecordFailures(corrupted, missing []string) {
+	if len(corrupted) == 0 && len(missing) == 0 {
+		return
+	}
+
+	lr.mu.Lock()
+	for _, nodeID := range corrupted {
+		lr.failures = append(lr.failures, &pb.Node{
+			NodeId:             []byte(nodeID),
+			AuditSuccess:       false,
+			UpdateAuditSuccess: true,
+		})
+	}
+	for _, nodeID := range missing {
+		lr.failures = append(lr.failures, &pb.Node{
+			NodeId:       []byte(nodeID),
+			IsUp:         false,
+			UpdateUptime: true,
+		})
+	}
+	lr.count += len(corrupted) + len(missing)
+	lr.flushIfFull()
+}
+
+// flushIfFull reports the buffered updates in the background once there
+// are batchSize of them. lr.mu must be held and is released.
+func (lr *LatencyRecorder) flushIfFull() {
+	full := lr.count >= lr.batchSize
+	lr.mu.Unlock()
+
+	if full {
+		go func() {
+			if err := lr.Flush(context.Background()); err != nil {
+				zap.S().Errorf("failed to report latencies: %v", err)
+			}
+		}()
+	}
+}
+
+// Flush reports all buffered latencies and failures to the stats db
+func (lr *LatencyRecorder) Flush(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lr.mu.Lock()
+	latencies, failures := lr.latencies, lr.failures
+	lr.latencies = map[string][]int64{}
+	lr.failures = nil
+	lr.count = 0
+	lr.mu.Unlock()
+
+	if len(latencies) == 0 && len(failures) == 0 {
+		return nil
+	}
+
+	nodes := make([]*pb.Node, 0, len(latencies)+len(failures))
+	nodes = append(nodes, failures...)
+	for nodeID, list := range latencies {
+		nodes = append(nodes, &pb.Node{
+			NodeId:        []byte(nodeID),
+			LatencyList:   list,
+			UpdateLatency: true,
+		})
+	}
+
+	_, err = lr.client.UpdateBatch(ctx, nodes)
+	return err
+}
@@ -9,8 +9,8 @@ import (
 	"fmt"
 	"net"
 
+	base58 "github.com/jbenet/go-base58"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
@@ -21,12 +21,14 @@ import (
 var (
 	addr   = flag.String("addr", ":8080", "listen address")
 	dbPath = flag.String("statdb", "stats.db", "stats db path")
+	secret = flag.String("api-secret", "", "the base58 encoded secret api keys are signed with")
+	admin  = flag.String("admin-secret", "", "the base58 encoded secret admin api keys, allowed to change the stats, are signed with")
 )
 
 // Process fits the `Process` interface for services
 func (s *Service) Process(ctx context.Context, _ *cobra.Command, _ []string) error {
-	if err := setEnv(); err != nil {
-		return err
+	if *secret == "" {
+		return Error.New("no api secret configured")
 	}
 
 	// start grpc server
@@ -37,7 +39,7 @@ func (s *Service) Process(ctx context.Context, _ *cobra.Command, _ []string) err
 
 	grpcServer := grpc.NewServer()
 
-	ns, err := NewServer("sqlite3", *dbPath, s.logger)
+	ns, err := NewServer("sqlite3", *dbPath, base58.Decode(*secret), base58.Decode(*admin), defaultReputation, defaultReputation, s.logger)
 	if err != nil {
 		return err
 	}
@@ -59,12 +61,6 @@ func (s *Service) SetLogger(l *zap.Logger) {
 	s.logger = l
 }
 
-func setEnv() error {
-	viper.SetEnvPrefix("API")
-	viper.AutomaticEnv()
-	return nil
-}
-
 // SetMetricHandler for  process
 func (s *Service) SetMetricHandler(m *monkit.Registry) {
 	s.metrics = m
@@ -5,25 +5,34 @@ package statdb
 
 import (
 	"context"
+	"sort"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
-	"storj.io/storj/pkg/pointerdb/auth"
+	"storj.io/storj/pkg/macaroon"
 	dbx "storj.io/storj/pkg/statdb/dbx"
 	pb "storj.io/storj/pkg/statdb/proto"
+	"storj.io/storj/pkg/utils"
 )
 
 // Server implements the statdb RPC service
 type Server struct {
 	DB     *dbx.DB
 	logger *zap.Logger
+	audit  ReputationConfig
+	uptime ReputationConfig
+	secret []byte
+	// adminSecret signs the admin api keys, the only ones allowed to change
+	// the stats. Without it the stats can't be changed through the RPCs.
+	adminSecret []byte
 }
 
 // NewServer creates instance of Server
-func NewServer(driver, source string, logger *zap.Logger) (*Server, error) {
+func NewServer(driver, source string, secret, adminSecret []byte, audit, uptime ReputationConfig, logger *zap.Logger) (*Server, error) {
 	db, err := dbx.Open(driver, source)
 	if err != nil {
 		return nil, err
@@ -34,15 +43,51 @@ func NewServer(driver, source string, logger *zap.Logger) (*Server, error) {
 		return nil, err
 	}
 
+	if err = migrate(db, driver, audit, uptime); err != nil {
+		return nil, utils.CombineErrors(err, db.Close())
+	}
+
 	return &Server{
-		DB:     db,
-		logger: logger,
+		DB:          db,
+		logger:      logger,
+		audit:       audit,
+		uptime:      uptime,
+		secret:      secret,
+		adminSecret: adminSecret,
 	}, nil
 }
 
+// validateAuth checks that APIKeyBytes is signed by the satellite and its
+// caveats allow reads. Both the api keys and the admin api keys can read
+// the stats. Keys restricted to paths can't access the statdb.
 func (s *Server) validateAuth(APIKeyBytes []byte) error {
-	if !auth.ValidateAPIKey(string(APIKeyBytes)) {
-		s.logger.Error("unauthorized request: ", zap.Error(status.Errorf(codes.Unauthenticated, "Invalid API credential")))
+	key, err := macaroon.ParseAPIKey(string(APIKeyBytes))
+	if err == nil {
+		action := macaroon.Action{Op: macaroon.ActionRead, Time: time.Now()}
+		err = key.Check(s.secret, action)
+		if err != nil && len(s.adminSecret) > 0 && key.Check(s.adminSecret, action) == nil {
+			err = nil
+		}
+	}
+	if err != nil {
+		s.logger.Error("unauthorized request: ", zap.Error(err))
+		return status.Errorf(codes.Unauthenticated, "Invalid API credential")
+	}
+	return nil
+}
+
+// validateAdmin checks that APIKeyBytes is an admin api key allowing writes,
+// as the stats decide which storagenodes are used and disqualified
+func (s *Server) validateAdmin(APIKeyBytes []byte) error {
+	if len(s.adminSecret) == 0 {
+		return status.Errorf(codes.PermissionDenied, "no admin api keys accepted")
+	}
+	key, err := macaroon.ParseAPIKey(string(APIKeyBytes))
+	if err == nil {
+		err = key.Check(s.adminSecret, macaroon.Action{Op: macaroon.ActionWrite, Time: time.Now()})
+	}
+	if err != nil {
+		s.logger.Error("unauthorized request: ", zap.Error(err))
 		return status.Errorf(codes.Unauthenticated, "Invalid API credential")
 	}
 	return nil
@@ -53,24 +98,47 @@ func (s *Server) Create(ctx context.Context, createReq *pb.CreateRequest) (resp
 	s.logger.Debug("entering statdb Create")
 
 	APIKeyBytes := createReq.APIKey
-	if err := s.validateAuth(APIKeyBytes); err != nil {
+	if err := s.validateAdmin(APIKeyBytes); err != nil {
 		return nil, err
 	}
 
 	node := createReq.Node
 
-	auditSuccessCount, totalAuditCount, auditSuccessRatio := initRatioVars(node.UpdateAuditSuccess, node.AuditSuccess)
-	uptimeSuccessCount, totalUptimeCount, uptimeRatio := initRatioVars(node.UpdateUptime, node.IsUp)
+	auditSuccessCount, totalAuditCount := initCountVars(node.UpdateAuditSuccess, node.AuditSuccess)
+	uptimeSuccessCount, totalUptimeCount := initCountVars(node.UpdateUptime, node.IsUp)
+	auditAlpha, auditBeta, auditReputation := initReputation(node.UpdateAuditSuccess, node.AuditSuccess, s.audit)
+	uptimeAlpha, uptimeBeta, uptimeReputation := initReputation(node.UpdateUptime, node.IsUp, s.uptime)
+
+	var latencies []int64
+	if node.UpdateLatency {
+		latencies = node.LatencyList
+	}
+	latencySamples, latency90, err := updateLatencyVars(nil, latencies, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	disqualified := dbx.Node_Disqualified_Null()
+	if isDisqualified(auditReputation, uptimeReputation, s.audit, s.uptime) {
+		disqualified = dbx.Node_Disqualified(time.Now())
+	}
 
 	dbNode, err := s.DB.Create_Node(
 		ctx,
 		dbx.Node_Id(string(node.NodeId)),
 		dbx.Node_AuditSuccessCount(auditSuccessCount),
 		dbx.Node_TotalAuditCount(totalAuditCount),
-		dbx.Node_AuditSuccessRatio(auditSuccessRatio),
+		dbx.Node_AuditSuccessRatio(auditReputation),
+		dbx.Node_AuditReputationAlpha(auditAlpha),
+		dbx.Node_AuditReputationBeta(auditBeta),
 		dbx.Node_UptimeSuccessCount(uptimeSuccessCount),
 		dbx.Node_TotalUptimeCount(totalUptimeCount),
-		dbx.Node_UptimeRatio(uptimeRatio),
+		dbx.Node_UptimeRatio(uptimeReputation),
+		dbx.Node_UptimeReputationAlpha(uptimeAlpha),
+		dbx.Node_UptimeReputationBeta(uptimeBeta),
+		dbx.Node_LatencySamples(latencySamples),
+		dbx.Node_Latency90(latency90),
+		disqualified,
 	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
@@ -79,8 +147,10 @@ func (s *Server) Create(ctx context.Context, createReq *pb.CreateRequest) (resp
 
 	nodeStats := &pb.NodeStats{
 		NodeId:            []byte(dbNode.Id),
+		Latency_90:        dbNode.Latency90,
 		AuditSuccessRatio: dbNode.AuditSuccessRatio,
 		UptimeRatio:       dbNode.UptimeRatio,
+		Disqualified:      dbNode.Disqualified != nil,
 	}
 	return &pb.CreateResponse{
 		Stats: nodeStats,
@@ -98,14 +168,19 @@ func (s *Server) Get(ctx context.Context, getReq *pb.GetRequest) (resp *pb.GetRe
 	}
 
 	dbNode, err := s.DB.Get_Node_By_Id(ctx, dbx.Node_Id(string(getReq.NodeId)))
+	if isNotFound(err) {
+		return nil, status.Errorf(codes.NotFound, "node %s not found", getReq.NodeId)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
 	nodeStats := &pb.NodeStats{
 		NodeId:            []byte(dbNode.Id),
+		Latency_90:        dbNode.Latency90,
 		AuditSuccessRatio: dbNode.AuditSuccessRatio,
 		UptimeRatio:       dbNode.UptimeRatio,
+		Disqualified:      dbNode.Disqualified != nil,
 	}
 	return &pb.GetResponse{
 		Stats: nodeStats,
@@ -117,7 +192,7 @@ func (s *Server) Update(ctx context.Context, updateReq *pb.UpdateRequest) (resp
 	s.logger.Debug("entering statdb Update")
 
 	APIKeyBytes := updateReq.APIKey
-	err = s.validateAuth(APIKeyBytes)
+	err = s.validateAdmin(APIKeyBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -129,36 +204,63 @@ func (s *Server) Update(ctx context.Context, updateReq *pb.UpdateRequest) (resp
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
-	auditSuccessCount := dbNode.AuditSuccessCount
-	totalAuditCount := dbNode.TotalAuditCount
-	var auditSuccessRatio float64
-	var uptimeSuccessCount int64
-	totalUptimeCount := dbNode.TotalUptimeCount
-	var uptimeRatio float64
+	auditReputation := dbNode.AuditSuccessRatio
+	uptimeReputation := dbNode.UptimeRatio
 
 	updateFields := dbx.Node_Update_Fields{}
 
 	if node.UpdateAuditSuccess {
-		auditSuccessCount, totalAuditCount, auditSuccessRatio = updateRatioVars(
+		auditSuccessCount, totalAuditCount := updateCountVars(
+			node.AuditSuccess,
+			dbNode.AuditSuccessCount,
+			dbNode.TotalAuditCount,
+		)
+		var auditAlpha, auditBeta float64
+		auditAlpha, auditBeta, auditReputation = updateReputation(
 			node.AuditSuccess,
-			auditSuccessCount,
-			totalAuditCount,
+			dbNode.AuditReputationAlpha,
+			dbNode.AuditReputationBeta,
+			s.audit,
 		)
 
 		updateFields.AuditSuccessCount = dbx.Node_AuditSuccessCount(auditSuccessCount)
 		updateFields.TotalAuditCount = dbx.Node_TotalAuditCount(totalAuditCount)
-		updateFields.AuditSuccessRatio = dbx.Node_AuditSuccessRatio(auditSuccessRatio)
+		updateFields.AuditSuccessRatio = dbx.Node_AuditSuccessRatio(auditReputation)
+		updateFields.AuditReputationAlpha = dbx.Node_AuditReputationAlpha(auditAlpha)
+		updateFields.AuditReputationBeta = dbx.Node_AuditReputationBeta(auditBeta)
 	}
 	if node.UpdateUptime {
-		uptimeSuccessCount, totalUptimeCount, uptimeRatio = updateRatioVars(
+		uptimeSuccessCount, totalUptimeCount := updateCountVars(
 			node.IsUp,
-			uptimeSuccessCount,
-			totalUptimeCount,
+			dbNode.UptimeSuccessCount,
+			dbNode.TotalUptimeCount,
+		)
+		var uptimeAlpha, uptimeBeta float64
+		uptimeAlpha, uptimeBeta, uptimeReputation = updateReputation(
+			node.IsUp,
+			dbNode.UptimeReputationAlpha,
+			dbNode.UptimeReputationBeta,
+			s.uptime,
 		)
 
 		updateFields.UptimeSuccessCount = dbx.Node_UptimeSuccessCount(uptimeSuccessCount)
 		updateFields.TotalUptimeCount = dbx.Node_TotalUptimeCount(totalUptimeCount)
-		updateFields.UptimeRatio = dbx.Node_UptimeRatio(uptimeRatio)
+		updateFields.UptimeRatio = dbx.Node_UptimeRatio(uptimeReputation)
+		updateFields.UptimeReputationAlpha = dbx.Node_UptimeReputationAlpha(uptimeAlpha)
+		updateFields.UptimeReputationBeta = dbx.Node_UptimeReputationBeta(uptimeBeta)
+	}
+	// disqualification is permanent, so only the first time is recorded
+	if dbNode.Disqualified == nil && isDisqualified(auditReputation, uptimeReputation, s.audit, s.uptime) {
+		updateFields.Disqualified = dbx.Node_Disqualified(time.Now())
+	}
+	if node.UpdateLatency {
+		latencySamples, latency90, err := updateLatencyVars(dbNode.LatencySamples, node.LatencyList, time.Now())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+
+		updateFields.LatencySamples = dbx.Node_LatencySamples(latencySamples)
+		updateFields.Latency90 = dbx.Node_Latency90(latency90)
 	}
 
 	dbNode, err = s.DB.Update_Node_By_Id(ctx, dbx.Node_Id(string(node.NodeId)), updateFields)
@@ -168,21 +270,46 @@ func (s *Server) Update(ctx context.Context, updateReq *pb.UpdateRequest) (resp
 
 	nodeStats := &pb.NodeStats{
 		NodeId:            []byte(dbNode.Id),
+		Latency_90:        dbNode.Latency90,
 		AuditSuccessRatio: dbNode.AuditSuccessRatio,
 		UptimeRatio:       dbNode.UptimeRatio,
+		Disqualified:      dbNode.Disqualified != nil,
 	}
 	return &pb.UpdateResponse{
 		Stats: nodeStats,
 	}, nil
 }
 
-// UpdateBatch for updating  multiple farmers' stats in the db
+// UpdateBatch for updating  multiple farmers' stats in the db. Farmers
+// without stats are created.
 func (s *Server) UpdateBatch(ctx context.Context, updateBatchReq *pb.UpdateBatchRequest) (resp *pb.UpdateBatchResponse, err error) {
 	s.logger.Debug("entering statdb UpdateBatch")
 
 	APIKeyBytes := updateBatchReq.APIKey
+	err = s.validateAdmin(APIKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	nodeStatsList := make([]*pb.NodeStats, len(updateBatchReq.NodeList))
 	for i, node := range updateBatchReq.NodeList {
+		_, err := s.DB.Get_Node_By_Id(ctx, dbx.Node_Id(string(node.NodeId)))
+		if isNotFound(err) {
+			createRes, err := s.Create(ctx, &pb.CreateRequest{
+				Node:   node,
+				APIKey: APIKeyBytes,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			nodeStatsList[i] = createRes.Stats
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+
 		updateReq := &pb.UpdateRequest{
 			Node:   node,
 			APIKey: APIKeyBytes,
@@ -190,7 +317,7 @@ func (s *Server) UpdateBatch(ctx context.Context, updateBatchReq *pb.UpdateBatch
 
 		updateRes, err := s.Update(ctx, updateReq)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, err.Error())
+			return nil, err
 		}
 
 		nodeStatsList[i] = updateRes.Stats
@@ -202,25 +329,105 @@ func (s *Server) UpdateBatch(ctx context.Context, updateBatchReq *pb.UpdateBatch
 	return updateBatchRes, nil
 }
 
-func initRatioVars(shouldUpdate, status bool) (int64, int64, float64) {
-	var (
-		successCount int64
-		totalCount   int64
-		ratio        float64
-	)
+// FindValidNodes returns which of the given storagenodes have at least the
+// requested stats, the passed ones by increasing latency. Storagenodes
+// without stats fail.
+func (s *Server) FindValidNodes(ctx context.Context, findReq *pb.FindValidNodesRequest) (resp *pb.FindValidNodesResponse, err error) {
+	s.logger.Debug("entering statdb FindValidNodes")
 
-	if shouldUpdate {
-		return updateRatioVars(status, successCount, totalCount)
+	err = s.validateAuth(findReq.APIKey)
+	if err != nil {
+		return nil, err
 	}
 
-	return successCount, totalCount, ratio
+	passed, failed, err := s.ValidNodes(ctx, findReq.NodeIds, findReq.GetMinStats(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.FindValidNodesResponse{
+		PassedIds: passed,
+		FailedIds: failed,
+	}, nil
+}
+
+// ValidNodes returns which of the given storagenodes have at least minStats
+// and which don't. The storagenodes passing are ordered by increasing
+// latency, so that the fastest are preferred. Storagenodes without stats
+// pass after the others if allowUnknown is set, and fail otherwise.
+func (s *Server) ValidNodes(ctx context.Context, nodeIDs [][]byte, minStats *pb.NodeStats, allowUnknown bool) (passed, failed [][]byte, err error) {
+	type node struct {
+		id      []byte
+		latency int64
+	}
+	var known, unknown []node
+	for _, nodeID := range nodeIDs {
+		dbNode, err := s.DB.Get_Node_By_Id(ctx, dbx.Node_Id(string(nodeID)))
+		if isNotFound(err) {
+			if allowUnknown {
+				unknown = append(unknown, node{id: nodeID})
+			} else {
+				failed = append(failed, nodeID)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, nil, status.Errorf(codes.Internal, err.Error())
+		}
+
+		if meetsMinStats(dbNode, minStats) {
+			known = append(known, node{id: nodeID, latency: dbNode.Latency90})
+		} else {
+			failed = append(failed, nodeID)
+		}
+	}
+
+	sort.SliceStable(known, func(i, k int) bool {
+		return known[i].latency < known[k].latency
+	})
+	for _, n := range append(known, unknown...) {
+		passed = append(passed, n.id)
+	}
+	return passed, failed, nil
+}
+
+// meetsMinStats checks the stats of node against minStats. Disqualified
+// nodes never pass. A zero minStats.Latency_90 means latency is not checked.
+func meetsMinStats(node *dbx.Node, minStats *pb.NodeStats) bool {
+	if node.Disqualified != nil {
+		return false
+	}
+	if node.AuditSuccessRatio < minStats.GetAuditSuccessRatio() {
+		return false
+	}
+	if node.UptimeRatio < minStats.GetUptimeRatio() {
+		return false
+	}
+	if maxLatency := minStats.GetLatency_90(); maxLatency > 0 && node.Latency90 > maxLatency {
+		return false
+	}
+	return true
+}
+
+// isNotFound returns whether err is caused by a missing db entry
+func isNotFound(err error) bool {
+	if dbxErr, ok := err.(*dbx.Error); ok {
+		return dbxErr.Code == dbx.ErrorCode_NoRows
+	}
+	return false
+}
+
+func initCountVars(shouldUpdate, status bool) (int64, int64) {
+	if shouldUpdate {
+		return updateCountVars(status, 0, 0)
+	}
+	return 0, 0
 }
 
-func updateRatioVars(newStatus bool, successCount, totalCount int64) (int64, int64, float64) {
+func updateCountVars(newStatus bool, successCount, totalCount int64) (int64, int64) {
 	totalCount++
 	if newStatus {
 		successCount++
 	}
-	newRatio := float64(successCount) / float64(totalCount)
-	return successCount, totalCount, newRatio
+	return successCount, totalCount
 }
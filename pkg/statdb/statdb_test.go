@@ -0,0 +1,247 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package statdb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/macaroon"
+	pb "storj.io/storj/pkg/statdb/proto"
+)
+
+var (
+	ctx             = context.Background()
+	testSecret      = []byte("statdb test secret")
+	testAdminSecret = []byte("statdb test admin secret")
+)
+
+// newTestServer returns a server with a new db, an api key and an admin api
+// key it accepts
+func newTestServer(t *testing.T) (s *Server, apiKey, adminKey []byte, cleanup func()) {
+	dir, err := ioutil.TempDir("", "statdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = NewServer("sqlite3", filepath.Join(dir, "stats.db"), testSecret, testAdminSecret,
+		defaultReputation, defaultReputation, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := macaroon.NewAPIKey(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := macaroon.NewAPIKey(testAdminSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, []byte(key.Serialize()), []byte(admin.Serialize()), func() {
+		_ = s.DB.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestCreateGet(t *testing.T) {
+	s, apiKey, adminKey, cleanup := newTestServer(t)
+	defer cleanup()
+
+	_, err := s.Create(ctx, &pb.CreateRequest{
+		Node:   &pb.Node{NodeId: []byte("a")},
+		APIKey: []byte("wrong key"),
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// only the admin api keys can change the stats
+	_, err = s.Create(ctx, &pb.CreateRequest{
+		Node:   &pb.Node{NodeId: []byte("a")},
+		APIKey: apiKey,
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	created, err := s.Create(ctx, &pb.CreateRequest{
+		Node: &pb.Node{
+			NodeId:             []byte("a"),
+			AuditSuccess:       false,
+			UpdateAuditSuccess: true,
+			LatencyList:        []int64{100, 200},
+			UpdateLatency:      true,
+		},
+		APIKey: adminKey,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []byte("a"), created.Stats.NodeId)
+	assert.True(t, created.Stats.AuditSuccessRatio < 1)
+	assert.Equal(t, float64(1), created.Stats.UptimeRatio)
+	assert.Equal(t, int64(200), created.Stats.Latency_90)
+	assert.False(t, created.Stats.Disqualified)
+
+	for _, key := range [][]byte{apiKey, adminKey} {
+		got, err := s.Get(ctx, &pb.GetRequest{NodeId: []byte("a"), APIKey: key})
+		if assert.NoError(t, err) {
+			assert.Equal(t, created.Stats, got.Stats)
+		}
+	}
+
+	_, err = s.Get(ctx, &pb.GetRequest{NodeId: []byte("b"), APIKey: apiKey})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUpdate(t *testing.T) {
+	s, apiKey, adminKey, cleanup := newTestServer(t)
+	defer cleanup()
+
+	_, err := s.Create(ctx, &pb.CreateRequest{Node: &pb.Node{NodeId: []byte("a")}, APIKey: adminKey})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	update := func(node *pb.Node) *pb.NodeStats {
+		resp, err := s.Update(ctx, &pb.UpdateRequest{Node: node, APIKey: adminKey})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return resp.Stats
+	}
+
+	stats := update(&pb.Node{NodeId: []byte("a"), LatencyList: []int64{50}, UpdateLatency: true})
+	assert.Equal(t, int64(50), stats.Latency_90)
+	assert.Equal(t, float64(1), stats.AuditSuccessRatio)
+
+	// only the fields to update change
+	stats = update(&pb.Node{NodeId: []byte("a"), IsUp: false, UpdateUptime: true})
+	assert.True(t, stats.UptimeRatio < 1)
+	assert.Equal(t, float64(1), stats.AuditSuccessRatio)
+	assert.Equal(t, int64(50), stats.Latency_90)
+
+	// failing every audit disqualifies the node for good
+	for !stats.Disqualified {
+		stats = update(&pb.Node{NodeId: []byte("a"), AuditSuccess: false, UpdateAuditSuccess: true})
+	}
+	for i := 0; i < 100; i++ {
+		stats = update(&pb.Node{NodeId: []byte("a"), AuditSuccess: true, UpdateAuditSuccess: true})
+	}
+	assert.True(t, stats.AuditSuccessRatio > defaultReputation.DQ)
+	assert.True(t, stats.Disqualified)
+
+	// disqualified nodes are never valid
+	passed, failed, err := s.ValidNodes(ctx, [][]byte{[]byte("a")}, nil, true)
+	if assert.NoError(t, err) {
+		assert.Empty(t, passed)
+		assert.Equal(t, []string{"a"}, toStrings(failed))
+	}
+
+	for _, key := range [][]byte{[]byte("wrong key"), apiKey} {
+		_, err = s.Update(ctx, &pb.UpdateRequest{Node: &pb.Node{NodeId: []byte("a")}, APIKey: key})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+}
+
+func TestUpdateBatch(t *testing.T) {
+	s, apiKey, adminKey, cleanup := newTestServer(t)
+	defer cleanup()
+
+	_, err := s.Create(ctx, &pb.CreateRequest{
+		Node:   &pb.Node{NodeId: []byte("a"), LatencyList: []int64{10}, UpdateLatency: true},
+		APIKey: adminKey,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	nodes := []*pb.Node{
+		{NodeId: []byte("a"), LatencyList: []int64{30}, UpdateLatency: true},
+		{NodeId: []byte("b"), LatencyList: []int64{20}, UpdateLatency: true},
+	}
+
+	_, err = s.UpdateBatch(ctx, &pb.UpdateBatchRequest{NodeList: nodes, APIKey: apiKey})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the known nodes are updated and the others created
+	resp, err := s.UpdateBatch(ctx, &pb.UpdateBatchRequest{NodeList: nodes, APIKey: adminKey})
+	if assert.NoError(t, err) && assert.Len(t, resp.StatsList, 2) {
+		assert.Equal(t, []byte("a"), resp.StatsList[0].NodeId)
+		assert.Equal(t, int64(30), resp.StatsList[0].Latency_90)
+		assert.Equal(t, []byte("b"), resp.StatsList[1].NodeId)
+		assert.Equal(t, int64(20), resp.StatsList[1].Latency_90)
+	}
+}
+
+func TestFindValidNodes(t *testing.T) {
+	s, apiKey, adminKey, cleanup := newTestServer(t)
+	defer cleanup()
+
+	for _, node := range []*pb.Node{
+		{NodeId: []byte("slow"), LatencyList: []int64{900}, UpdateLatency: true},
+		{NodeId: []byte("fast"), LatencyList: []int64{10}, UpdateLatency: true},
+		{NodeId: []byte("medium"), LatencyList: []int64{300}, UpdateLatency: true},
+		{NodeId: []byte("unreliable"), AuditSuccess: false, UpdateAuditSuccess: true},
+	} {
+		_, err := s.Create(ctx, &pb.CreateRequest{Node: node, APIKey: adminKey})
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+	ids := [][]byte{[]byte("slow"), []byte("unknown"), []byte("fast"), []byte("unreliable"), []byte("medium")}
+
+	for i, tt := range []struct {
+		minStats *pb.NodeStats
+		passed   []string
+		failed   []string
+	}{
+		{ // the nodes passing are ordered by latency
+			minStats: &pb.NodeStats{},
+			passed:   []string{"unreliable", "fast", "medium", "slow"},
+			failed:   []string{"unknown"},
+		},
+		{
+			minStats: &pb.NodeStats{AuditSuccessRatio: 0.99},
+			passed:   []string{"fast", "medium", "slow"},
+			failed:   []string{"unknown", "unreliable"},
+		},
+		{
+			minStats: &pb.NodeStats{AuditSuccessRatio: 0.99, Latency_90: 300},
+			passed:   []string{"fast", "medium"},
+			failed:   []string{"slow", "unknown", "unreliable"},
+		},
+	} {
+		resp, err := s.FindValidNodes(ctx, &pb.FindValidNodesRequest{
+			NodeIds:  ids,
+			MinStats: tt.minStats,
+			APIKey:   apiKey,
+		})
+		if assert.NoError(t, err, i) {
+			assert.Equal(t, tt.passed, toStrings(resp.PassedIds), i)
+			assert.Equal(t, tt.failed, toStrings(resp.FailedIds), i)
+		}
+	}
+
+	// the unknown nodes pass after the others if allowed
+	passed, failed, err := s.ValidNodes(ctx, ids, &pb.NodeStats{Latency_90: 300}, true)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"unreliable", "fast", "medium", "unknown"}, toStrings(passed))
+		assert.Equal(t, []string{"slow"}, toStrings(failed))
+	}
+
+	_, err = s.FindValidNodes(ctx, &pb.FindValidNodesRequest{NodeIds: ids, APIKey: []byte("wrong key")})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func toStrings(ids [][]byte) []string {
+	var strs []string
+	for _, id := range ids {
+		strs = append(strs, string(id))
+	}
+	return strs
+}
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	buckets "storj.io/storj/pkg/storage/buckets"
+	lifecycle "storj.io/storj/pkg/storage/lifecycle"
 	objects "storj.io/storj/pkg/storage/objects"
 )
 
@@ -100,3 +101,55 @@ func (m *MockStore) Put(arg0 context.Context, arg1 string) (buckets.Meta, error)
 func (mr *MockStoreMockRecorder) Put(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStore)(nil).Put), arg0, arg1)
 }
+
+// SetLifecycle mocks base method
+func (m *MockStore) SetLifecycle(arg0 context.Context, arg1 string, arg2 []lifecycle.Rule) (buckets.Meta, error) {
+	ret := m.ctrl.Call(m, "SetLifecycle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(buckets.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLifecycle indicates an expected call of SetLifecycle
+func (mr *MockStoreMockRecorder) SetLifecycle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLifecycle", reflect.TypeOf((*MockStore)(nil).SetLifecycle), arg0, arg1, arg2)
+}
+
+// SetCompression mocks base method
+func (m *MockStore) SetCompression(arg0 context.Context, arg1, arg2 string) (buckets.Meta, error) {
+	ret := m.ctrl.Call(m, "SetCompression", arg0, arg1, arg2)
+	ret0, _ := ret[0].(buckets.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCompression indicates an expected call of SetCompression
+func (mr *MockStoreMockRecorder) SetCompression(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompression", reflect.TypeOf((*MockStore)(nil).SetCompression), arg0, arg1, arg2)
+}
+
+// SetRedundancy mocks base method
+func (m *MockStore) SetRedundancy(arg0 context.Context, arg1, arg2 string) (buckets.Meta, error) {
+	ret := m.ctrl.Call(m, "SetRedundancy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(buckets.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRedundancy indicates an expected call of SetRedundancy
+func (mr *MockStoreMockRecorder) SetRedundancy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRedundancy", reflect.TypeOf((*MockStore)(nil).SetRedundancy), arg0, arg1, arg2)
+}
+
+// SetVersioning mocks base method
+func (m *MockStore) SetVersioning(arg0 context.Context, arg1 string, arg2 bool) (buckets.Meta, error) {
+	ret := m.ctrl.Call(m, "SetVersioning", arg0, arg1, arg2)
+	ret0, _ := ret[0].(buckets.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetVersioning indicates an expected call of SetVersioning
+func (mr *MockStoreMockRecorder) SetVersioning(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVersioning", reflect.TypeOf((*MockStore)(nil).SetVersioning), arg0, arg1, arg2)
+}
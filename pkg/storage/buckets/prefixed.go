@@ -64,6 +64,40 @@ func (o *prefixedObjStore) Delete(ctx context.Context, path paths.Path) (err err
 	return o.o.Delete(ctx, path.Prepend(o.prefix))
 }
 
+func (o *prefixedObjStore) Move(ctx context.Context, from, to paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(from) == 0 || len(to) == 0 {
+		return objects.NoPathError.New("")
+	}
+
+	return o.o.Move(ctx, from.Prepend(o.prefix), to.Prepend(o.prefix))
+}
+
+func (o *prefixedObjStore) MoveBatch(ctx context.Context, from, to []paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	prefixed := func(batch []paths.Path) ([]paths.Path, error) {
+		result := make([]paths.Path, len(batch))
+		for i, path := range batch {
+			if len(path) == 0 {
+				return nil, objects.NoPathError.New("")
+			}
+			result[i] = path.Prepend(o.prefix)
+		}
+		return result, nil
+	}
+	from, err = prefixed(from)
+	if err != nil {
+		return err
+	}
+	to, err = prefixed(to)
+	if err != nil {
+		return err
+	}
+	return o.o.MoveBatch(ctx, from, to)
+}
+
 func (o *prefixedObjStore) List(ctx context.Context, prefix, startAfter,
 	endBefore paths.Path, recursive bool, limit int, metaFlags uint32) (
 	items []objects.ListItem, more bool, err error) {
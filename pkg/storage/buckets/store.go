@@ -6,14 +6,18 @@ package buckets
 import (
 	"bytes"
 	"context"
+	"io"
+	"strings"
 	"time"
 
 	minio "github.com/minio/minio/cmd"
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/storage/lifecycle"
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/pkg/storage/objects"
 	"storj.io/storj/storage"
@@ -24,11 +28,32 @@ var mon = monkit.Package()
 // NoBucketError is an error class for missing bucket name
 var NoBucketError = errs.Class("no bucket specified")
 
+// UnknownSchemeError is an error class for redundancy schemes the store
+// has no objects store for
+var UnknownSchemeError = errs.Class("unknown redundancy scheme")
+
+const (
+	// versioningKey is the user-defined metadata of the bucket object
+	// telling whether versioning is enabled
+	versioningKey     = "storj-versioning"
+	versioningEnabled = "enabled"
+	// redundancyKey is the user-defined metadata of the bucket object
+	// naming the redundancy scheme of its objects
+	redundancyKey = "storj-redundancy"
+	// compressionKey is the user-defined metadata of the bucket object
+	// naming the default compression of its objects
+	compressionKey = "storj-default-compression"
+)
+
 // Store creates an interface for interacting with buckets
 type Store interface {
 	Get(ctx context.Context, bucket string) (meta Meta, err error)
 	Put(ctx context.Context, bucket string) (meta Meta, err error)
 	Delete(ctx context.Context, bucket string) (err error)
+	SetVersioning(ctx context.Context, bucket string, enabled bool) (meta Meta, err error)
+	SetLifecycle(ctx context.Context, bucket string, rules []lifecycle.Rule) (meta Meta, err error)
+	SetRedundancy(ctx context.Context, bucket string, scheme string) (meta Meta, err error)
+	SetCompression(ctx context.Context, bucket string, compression string) (meta Meta, err error)
 	List(ctx context.Context, startAfter, endBefore string, limit int) (items []ListItem, more bool, err error)
 	GetObjectStore(ctx context.Context, bucketName string) (store objects.Store, err error)
 }
@@ -41,12 +66,21 @@ type ListItem struct {
 
 // BucketStore contains objects store
 type BucketStore struct {
-	o objects.Store
+	o       objects.Store
+	schemes map[string]objects.Store
 }
 
 // Meta is the bucket metadata struct
 type Meta struct {
-	Created time.Time
+	Created    time.Time
+	Versioning bool
+	Lifecycle  []lifecycle.Rule
+	// Redundancy is the redundancy scheme of the objects, empty for the
+	// default one
+	Redundancy string
+	// Compression is the compression of the objects put without one, empty
+	// for none
+	Compression string
 }
 
 // NewStore instantiates BucketStore
@@ -54,26 +88,58 @@ func NewStore(obj objects.Store) Store {
 	return &BucketStore{o: obj}
 }
 
-// GetObjectStore returns an implementation of objects.Store
+// NewStoreWithSchemes instantiates a BucketStore whose buckets can select
+// one of the redundancy schemes. schemes maps the scheme names to the
+// objects stores storing with them, and obj is used for the buckets
+// without a scheme and for the bucket objects themselves.
+func NewStoreWithSchemes(obj objects.Store, schemes map[string]objects.Store) Store {
+	return &BucketStore{o: obj, schemes: schemes}
+}
+
+// GetObjectStore returns an implementation of objects.Store. The store of a
+// bucket with versioning enabled is an objects.VersionedStore.
 func (b *BucketStore) GetObjectStore(ctx context.Context, bucket string) (objects.Store, error) {
 	if bucket == "" {
 		return nil, NoBucketError.New("")
 	}
 
-	_, err := b.Get(ctx, bucket)
+	m, err := b.Get(ctx, bucket)
 	if err != nil {
 		if storage.ErrKeyNotFound.Has(err) {
 			return nil, minio.BucketNotFound{Bucket: bucket}
 		}
 		return nil, err
 	}
+	o, err := b.schemeStore(m.Redundancy)
+	if err != nil {
+		return nil, err
+	}
+	if m.Compression != "" {
+		o = &compressedObjStore{Store: o, compression: m.Compression}
+	}
+	if m.Versioning {
+		return objects.NewVersionedStore(o, paths.New(bucket),
+			paths.New(bucket, objects.VersionsDir)), nil
+	}
 	prefixed := prefixedObjStore{
-		o:      b.o,
+		o:      o,
 		prefix: bucket,
 	}
 	return &prefixed, nil
 }
 
+// schemeStore returns the objects store of the redundancy scheme
+func (b *BucketStore) schemeStore(scheme string) (objects.Store, error) {
+	if scheme == "" {
+		return b.o, nil
+	}
+	o, ok := b.schemes[scheme]
+	if !ok {
+		return nil, UnknownSchemeError.New(scheme)
+	}
+	return o, nil
+}
+
 // Get calls objects store Get
 func (b *BucketStore) Get(ctx context.Context, bucket string) (meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -95,11 +161,114 @@ func (b *BucketStore) Put(ctx context.Context, bucket string) (meta Meta, err er
 	if bucket == "" {
 		return Meta{}, NoBucketError.New("")
 	}
+	return b.put(ctx, bucket, objects.SerializableMeta{})
+}
+
+// SetVersioning enables or disables versioning for the bucket. When it's
+// disabled, the objects put or deleted replace the latest version, but the
+// previous versions are kept, and listed under objects.VersionsDir until
+// versioning is enabled again. The bucket object is put again, so the
+// creation date of the bucket is updated.
+func (b *BucketStore) SetVersioning(ctx context.Context, bucket string, enabled bool) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return b.updateMetadata(ctx, bucket, func(userDefined map[string]string) error {
+		delete(userDefined, versioningKey)
+		if enabled {
+			userDefined[versioningKey] = versioningEnabled
+		}
+		return nil
+	})
+}
+
+// SetLifecycle replaces the lifecycle rules of the bucket. No rules remove
+// the lifecycle configuration. Like SetVersioning, it updates the creation
+// date of the bucket.
+func (b *BucketStore) SetLifecycle(ctx context.Context, bucket string, rules []lifecycle.Rule) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return b.updateMetadata(ctx, bucket, func(userDefined map[string]string) error {
+		delete(userDefined, lifecycle.MetadataKey)
+		if len(rules) == 0 {
+			return nil
+		}
+		value, err := lifecycle.Encode(rules)
+		if err != nil {
+			return err
+		}
+		userDefined[lifecycle.MetadataKey] = value
+		return nil
+	})
+}
+
+// SetRedundancy selects the redundancy scheme of the objects put in the
+// bucket from now on. The objects already put keep theirs, as the scheme is
+// recorded with every segment. An empty scheme selects the default one.
+// Like SetVersioning, it updates the creation date of the bucket.
+func (b *BucketStore) SetRedundancy(ctx context.Context, bucket string, scheme string) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+	scheme = strings.ToUpper(scheme)
+	if _, err := b.schemeStore(scheme); err != nil {
+		return Meta{}, err
+	}
+	return b.updateMetadata(ctx, bucket, func(userDefined map[string]string) error {
+		delete(userDefined, redundancyKey)
+		if scheme != "" {
+			userDefined[redundancyKey] = scheme
+		}
+		return nil
+	})
+}
+
+// SetCompression selects the compression of the objects put in the bucket
+// from now on without one of their own. The objects already put keep
+// theirs, as the compression is recorded with every stream. An empty
+// compression selects none. Like SetVersioning, it updates the creation date
+// of the bucket.
+func (b *BucketStore) SetCompression(ctx context.Context, bucket string, compression string) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+	compression = strings.ToLower(compression)
+	if _, err := objects.ParseCompression(compression); err != nil {
+		return Meta{}, err
+	}
+	return b.updateMetadata(ctx, bucket, func(userDefined map[string]string) error {
+		delete(userDefined, compressionKey)
+		if compression != "" {
+			userDefined[compressionKey] = compression
+		}
+		return nil
+	})
+}
+
+// updateMetadata puts the bucket object again with the user-defined
+// metadata changed by update
+func (b *BucketStore) updateMetadata(ctx context.Context, bucket string, update func(userDefined map[string]string) error) (Meta, error) {
+	if bucket == "" {
+		return Meta{}, NoBucketError.New("")
+	}
+
+	objMeta, err := b.o.Meta(ctx, paths.New(bucket))
+	if err != nil {
+		return Meta{}, err
+	}
 
+	metadata := objMeta.SerializableMeta
+	userDefined := make(map[string]string, len(metadata.UserDefined)+1)
+	for k, v := range metadata.UserDefined {
+		userDefined[k] = v
+	}
+	if err := update(userDefined); err != nil {
+		return Meta{}, err
+	}
+	metadata.UserDefined = userDefined
+
+	return b.put(ctx, bucket, metadata)
+}
+
+// put puts the bucket object with metadata
+func (b *BucketStore) put(ctx context.Context, bucket string, metadata objects.SerializableMeta) (Meta, error) {
 	p := paths.New(bucket)
 	r := bytes.NewReader(nil)
 	var exp time.Time
-	m, err := b.o.Put(ctx, p, r, objects.SerializableMeta{}, exp)
+	m, err := b.o.Put(ctx, p, r, metadata, exp)
 	if err != nil {
 		return Meta{}, err
 	}
@@ -140,7 +309,35 @@ func (b *BucketStore) List(ctx context.Context, startAfter, endBefore string, li
 
 // convertMeta converts stream metadata to object metadata
 func convertMeta(m objects.Meta) Meta {
+	rules, err := lifecycle.Decode(m.UserDefined[lifecycle.MetadataKey])
+	if err != nil {
+		zap.S().Warnf("Failed decoding lifecycle rules: %v", err)
+	}
 	return Meta{
-		Created: m.Modified,
+		Created:     m.Modified,
+		Versioning:  m.UserDefined[versioningKey] == versioningEnabled,
+		Lifecycle:   rules,
+		Redundancy:  m.UserDefined[redundancyKey],
+		Compression: m.UserDefined[compressionKey],
+	}
+}
+
+// compressedObjStore puts the objects without a compression of their own
+// with the default compression of the bucket
+type compressedObjStore struct {
+	objects.Store
+	compression string
+}
+
+func (o *compressedObjStore) Put(ctx context.Context, path paths.Path, data io.Reader,
+	metadata objects.SerializableMeta, expiration time.Time) (meta objects.Meta, err error) {
+	if !objects.HasCompression(metadata.UserDefined) {
+		userDefined := make(map[string]string, len(metadata.UserDefined)+1)
+		for k, v := range metadata.UserDefined {
+			userDefined[k] = v
+		}
+		userDefined[objects.CompressionKey] = o.compression
+		metadata.UserDefined = userDefined
 	}
+	return o.Store.Put(ctx, path, data, metadata, expiration)
 }
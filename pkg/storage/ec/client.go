@@ -30,7 +30,7 @@ type Client interface {
 	Put(ctx context.Context, nodes []*proto.Node, rs eestream.RedundancyStrategy,
 		pieceID client.PieceID, data io.Reader, expiration time.Time) error
 	Get(ctx context.Context, nodes []*proto.Node, es eestream.ErasureScheme,
-		pieceID client.PieceID, size int64) (ranger.Ranger, error)
+		pieceID client.PieceID, size int64, report *eestream.PieceReport) (ranger.Ranger, error)
 	Delete(ctx context.Context, nodes []*proto.Node, pieceID client.PieceID) error
 }
 
@@ -50,18 +50,50 @@ func (d *defaultDialer) dial(ctx context.Context, node *proto.Node) (ps client.P
 		return nil, err
 	}
 
-	return client.NewPSClient(c, 0, d.identity.Key)
+	ps, err = client.NewPSClient(c, 0, d.identity.Key)
+	if err != nil {
+		return nil, utils.CombineErrors(err, transport.Release(d.t, c))
+	}
+	return &releasedPSClient{PSClient: ps, release: func() error {
+		return transport.Release(d.t, c)
+	}}, nil
+}
+
+// releasedPSClient releases its connection to the transport client on Close
+// instead of closing it, as it may be shared through the connection pool
+type releasedPSClient struct {
+	client.PSClient
+	release func() error
+}
+
+// Close implements io.Closer
+func (ps *releasedPSClient) Close() error { return ps.release() }
+
+// LatencyRecorder is notified of the latency of storage nodes, which is the
+// time to first byte of the piece downloads, dialing excluded
+type LatencyRecorder interface {
+	RecordLatency(nodeID string, latency time.Duration)
 }
 
 type ecClient struct {
 	d   dialer
 	mbm int
+	lr  LatencyRecorder
 }
 
-// NewClient from the given TransportClient and max buffer memory
-func NewClient(identity *provider.FullIdentity, t transport.Client, mbm int) Client {
+// NewClient from the given TransportClient and max buffer memory. The
+// connections dialed with t are released to it with transport.Release.
+// If lr is not nil, it receives the latency of every successful piece
+// download.
+func NewClient(identity *provider.FullIdentity, t transport.Client, mbm int, lr LatencyRecorder) Client {
 	d := defaultDialer{identity: identity, t: t}
-	return &ecClient{d: &d, mbm: mbm}
+	return &ecClient{d: &d, mbm: mbm, lr: lr}
+}
+
+func (ec *ecClient) recordLatency(n *proto.Node, latency time.Duration) {
+	if ec.lr != nil {
+		ec.lr.RecordLatency(n.GetId(), latency)
+	}
 }
 
 func (ec *ecClient) Put(ctx context.Context, nodes []*proto.Node, rs eestream.RedundancyStrategy,
@@ -74,6 +106,10 @@ func (ec *ecClient) Put(ctx context.Context, nodes []*proto.Node, rs eestream.Re
 	if !unique(nodes) {
 		return Error.New("duplicated nodes are not allowed")
 	}
+	// the readers of the failed pieces are abandoned, so cancel them on return
+	// to give their buffers back
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	padded := eestream.PadReader(ioutil.NopCloser(data), rs.DecodedBlockSize())
 	readers, err := eestream.EncodeReader(ctx, padded, rs, ec.mbm)
 	if err != nil {
@@ -116,8 +152,11 @@ func (ec *ecClient) Put(ctx context.Context, nodes []*proto.Node, rs eestream.Re
 	return nil
 }
 
+// Get returns a ranger of the data erasure coded to the pieces on nodes.
+// report, if not nil, collects the numbers of the pieces, and so the
+// indexes of the nodes, that were corrupted, missing or slow while reading.
 func (ec *ecClient) Get(ctx context.Context, nodes []*proto.Node, es eestream.ErasureScheme,
-	pieceID client.PieceID, size int64) (rr ranger.Ranger, err error) {
+	pieceID client.PieceID, size int64, report *eestream.PieceReport) (rr ranger.Ranger, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if len(nodes) != es.TotalCount() {
@@ -142,11 +181,12 @@ func (ec *ecClient) Get(ctx context.Context, nodes []*proto.Node, es eestream.Er
 			}
 
 			rr := &lazyPieceRanger{
-				dialer: ec.d,
-				node:   n,
-				id:     derivedPieceID,
-				size:   pieceSize,
-				pba:    &pb.PayerBandwidthAllocation{},
+				dialer:   ec.d,
+				node:     n,
+				id:       derivedPieceID,
+				size:     pieceSize,
+				pba:      &pb.PayerBandwidthAllocation{},
+				recorder: ec.recordLatency,
 			}
 
 			ch <- rangerInfo{i: i, rr: rr, err: nil}
@@ -158,7 +198,7 @@ func (ec *ecClient) Get(ctx context.Context, nodes []*proto.Node, es eestream.Er
 			rrs[rri.i] = rri.rr
 		}
 	}
-	rr, err = eestream.Decode(rrs, es, ec.mbm)
+	rr, err = eestream.Decode(rrs, es, ec.mbm, report)
 	if err != nil {
 		return nil, err
 	}
@@ -242,12 +282,13 @@ func calcPadded(size int64, blockSize int) int64 {
 }
 
 type lazyPieceRanger struct {
-	ranger ranger.Ranger
 	dialer dialer
 	node   *proto.Node
 	id     client.PieceID
 	size   int64
 	pba    *pb.PayerBandwidthAllocation
+
+	recorder func(n *proto.Node, latency time.Duration)
 }
 
 // Size implements Ranger.Size
@@ -255,18 +296,61 @@ func (lr *lazyPieceRanger) Size() int64 {
 	return lr.size
 }
 
-// Range implements Ranger.Range to be lazily connected
-func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
-	if lr.ranger == nil {
-		ps, err := lr.dialer.dial(ctx, lr.node)
+// Range implements Ranger.Range to be lazily connected. The connection is
+// released once the returned reader is closed.
+func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	ps, err := lr.dialer.dial(ctx, lr.node)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
 		if err != nil {
-			return nil, err
+			err = utils.CombineErrors(err, ps.Close())
 		}
-		ranger, err := ps.Get(ctx, lr.id, lr.size, lr.pba)
-		if err != nil {
-			return nil, err
+	}()
+	rr, err := ps.Get(ctx, lr.id, lr.size, lr.pba)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rc, err := rr.Range(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	r := &pieceReader{ReadCloser: rc, ps: ps}
+	if lr.recorder != nil && length > 0 {
+		r.onFirstByte = func() {
+			lr.recorder(lr.node, time.Since(start))
 		}
-		lr.ranger = ranger
 	}
-	return lr.ranger.Range(ctx, offset, length)
+	return r, nil
+}
+
+// pieceReader reads a piece from ps, calling onFirstByte when the first
+// byte is read, and closes ps once closed
+type pieceReader struct {
+	io.ReadCloser
+	ps          client.PSClient
+	onFirstByte func()
+}
+
+// Read implements io.Reader
+func (r *pieceReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	if n > 0 && r.onFirstByte != nil {
+		r.onFirstByte()
+		r.onFirstByte = nil
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (r *pieceReader) Close() error {
+	err := r.ReadCloser.Close()
+	if r.ps == nil {
+		return err
+	}
+	ps := r.ps
+	r.ps = nil
+	return utils.CombineErrors(err, ps.Close())
 }
@@ -4,6 +4,7 @@
 package ecclient
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -11,18 +12,25 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/vivint/infectious"
+	"google.golang.org/grpc"
 
 	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/pool"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/transport"
 	proto "storj.io/storj/protos/overlay"
+	pb "storj.io/storj/protos/piecestore"
 )
 
 const (
@@ -64,7 +72,7 @@ func TestNewECClient(t *testing.T) {
 
 	privKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	identity := &provider.FullIdentity{Key: privKey}
-	ec := NewClient(identity, tc, mbm)
+	ec := NewClient(identity, tc, mbm, nil)
 	assert.NotNil(t, ec)
 
 	ecc, ok := ec.(*ecClient)
@@ -195,6 +203,70 @@ TestLoop:
 	}
 }
 
+type mockRecorder struct {
+	mu        sync.Mutex
+	latencies map[string]time.Duration
+}
+
+func (r *mockRecorder) RecordLatency(nodeID string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies[nodeID] = latency
+}
+
+// slowDialer takes delay to dial the nodes
+type slowDialer struct {
+	dialer
+	delay time.Duration
+}
+
+func (d *slowDialer) dial(ctx context.Context, node *proto.Node) (client.PSClient, error) {
+	time.Sleep(d.delay)
+	return d.dialer.dial(ctx, node)
+}
+
+func TestGetRecordsLatency(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := client.NewPieceID()
+	data := []byte("some piece data")
+	ps := NewMockPSClient(ctrl)
+	gomock.InOrder(
+		ps.EXPECT().Get(gomock.Any(), id, int64(len(data)), gomock.Any()).Return(ranger.ByteRanger(data), nil),
+		ps.EXPECT().Close().Return(nil),
+	)
+
+	recorder := &mockRecorder{latencies: map[string]time.Duration{}}
+	delay := 100 * time.Millisecond
+	rr := &lazyPieceRanger{
+		dialer:   &slowDialer{dialer: &mockDialer{m: map[*proto.Node]client.PSClient{node0: ps}}, delay: delay},
+		node:     node0,
+		id:       id,
+		size:     int64(len(data)),
+		pba:      &pb.PayerBandwidthAllocation{},
+		recorder: (&ecClient{lr: recorder}).recordLatency,
+	}
+
+	r, err := rr.Range(ctx, 0, int64(len(data)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the latency is measured up to the first byte read
+	assert.Empty(t, recorder.latencies)
+
+	read, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, read)
+	assert.NoError(t, r.Close())
+
+	// the dial isn't part of the latency
+	if assert.Contains(t, recorder.latencies, node0.GetId()) {
+		assert.True(t, recorder.latencies[node0.GetId()] < delay)
+	}
+}
+
 func TestGet(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
@@ -249,12 +321,15 @@ TestLoop:
 					continue TestLoop
 				}
 				ps := NewMockPSClient(ctrl)
-				ps.EXPECT().Get(gomock.Any(), derivedID, int64(size/k), gomock.Any()).Return(ranger.ByteRanger(nil), errs[n])
+				gomock.InOrder(
+					ps.EXPECT().Get(gomock.Any(), derivedID, int64(size/k), gomock.Any()).Return(ranger.ByteRanger(nil), errs[n]),
+					ps.EXPECT().Close().Return(nil),
+				)
 				m[n] = ps
 			}
 		}
 		ec := ecClient{d: &mockDialer{m: m}, mbm: tt.mbm}
-		rr, err := ec.Get(ctx, tt.nodes, es, id, int64(size))
+		rr, err := ec.Get(ctx, tt.nodes, es, id, int64(size), nil)
 		if err == nil {
 			_, err := rr.Range(ctx, 0, 0)
 			assert.NoError(t, err, errTag)
@@ -268,6 +343,138 @@ TestLoop:
 	}
 }
 
+// insecureTransport dials the nodes without tls
+type insecureTransport struct{}
+
+func (insecureTransport) DialNode(ctx context.Context, node *proto.Node) (*grpc.ClientConn, error) {
+	return grpc.Dial(node.GetAddress().GetAddress(), grpc.WithInsecure())
+}
+
+func (insecureTransport) DialUnauthenticated(ctx context.Context, addr proto.NodeAddress) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr.Address, grpc.WithInsecure())
+}
+
+// memoryPSClient is a piece store client keeping the pieces in memory
+type memoryPSClient struct {
+	client.PSClient
+	mu     sync.Mutex
+	pieces map[client.PieceID][]byte
+	err    error
+}
+
+func (ps *memoryPSClient) Put(ctx context.Context, id client.PieceID, data io.Reader,
+	ttl time.Time, ba *pb.PayerBandwidthAllocation) error {
+	piece, err := ioutil.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.pieces[id] = piece
+	return nil
+}
+
+func (ps *memoryPSClient) Get(ctx context.Context, id client.PieceID, size int64,
+	ba *pb.PayerBandwidthAllocation) (ranger.Ranger, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.err != nil {
+		return nil, ps.err
+	}
+	return ranger.ByteRanger(ps.pieces[id]), nil
+}
+
+// poolDialer dials the nodes through a transport client like the default
+// dialer, handing out the in-memory piece store client of each node
+type poolDialer struct {
+	t      transport.Client
+	stores map[string]*memoryPSClient
+}
+
+func (d *poolDialer) dial(ctx context.Context, node *proto.Node) (client.PSClient, error) {
+	conn, err := d.t.DialNode(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return &releasedPSClient{PSClient: d.stores[node.GetId()], release: func() error {
+		return transport.Release(d.t, conn)
+	}}, nil
+}
+
+func TestGetReleasesConnections(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	server := grpc.NewServer()
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	// only one set of nodes may be connected at once, so the connections
+	// to the others must be released before they are evicted
+	cm := pool.NewConnectionManager(insecureTransport{}, pool.Config{MaxOpen: 4})
+	defer func() { assert.NoError(t, cm.Close()) }()
+
+	d := &poolDialer{t: cm, stores: map[string]*memoryPSClient{}}
+	nodeSet := func(name string) []*proto.Node {
+		var nodes []*proto.Node
+		for i := 0; i < 4; i++ {
+			id := fmt.Sprintf("%s-%d", name, i)
+			d.stores[id] = &memoryPSClient{pieces: map[client.PieceID][]byte{}}
+			nodes = append(nodes, &proto.Node{
+				Id:      id,
+				Address: &proto.NodeAddress{Address: lis.Addr().String()},
+			})
+		}
+		return nodes
+	}
+	a, b := nodeSet("a"), nodeSet("b")
+	// a piece that can't be read releases its connection too
+	d.stores["b-1"].err = ErrOpFailed
+
+	fc, err := infectious.NewFEC(2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	es := eestream.NewRSScheme(fc, 1024)
+	rs, err := eestream.NewRedundancyStrategy(es, 0, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ec := &ecClient{d: d, mbm: 64 * 1024}
+
+	data := make([]byte, 32*1024)
+	_, err = rand.Read(data)
+	assert.NoError(t, err)
+
+	for i, nodes := range [][]*proto.Node{a, b, a, b} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		id := client.NewPieceID()
+
+		err := ec.Put(ctx, nodes, rs, id, bytes.NewReader(data), time.Time{})
+		if !assert.NoError(t, err, errTag) {
+			cancel()
+			return
+		}
+		rr, err := ec.Get(ctx, nodes, es, id, int64(len(data)), nil)
+		if !assert.NoError(t, err, errTag) {
+			cancel()
+			return
+		}
+		r, err := rr.Range(ctx, 0, rr.Size())
+		if !assert.NoError(t, err, errTag) {
+			cancel()
+			return
+		}
+		read, err := ioutil.ReadAll(r)
+		assert.NoError(t, err, errTag)
+		assert.Equal(t, data, read, errTag)
+		assert.NoError(t, r.Close(), errTag)
+		cancel()
+	}
+}
+
 func TestDelete(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
@@ -106,6 +106,18 @@ func (mr *MockPSClientMockRecorder) Put(arg0, arg1, arg2, arg3, arg4 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockPSClient)(nil).Put), arg0, arg1, arg2, arg3, arg4)
 }
 
+// Retain mocks base method
+func (m *MockPSClient) Retain(arg0 context.Context, arg1 time.Time, arg2 []byte) error {
+	ret := m.ctrl.Call(m, "Retain", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retain indicates an expected call of Retain
+func (mr *MockPSClientMockRecorder) Retain(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retain", reflect.TypeOf((*MockPSClient)(nil).Retain), arg0, arg1, arg2)
+}
+
 // Stats mocks base method
 func (m *MockPSClient) Stats(arg0 context.Context) error {
 	ret := m.ctrl.Call(m, "Stats", arg0)
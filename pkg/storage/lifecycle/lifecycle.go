@@ -0,0 +1,119 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package lifecycle
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storage/objects"
+	streamspb "storj.io/storj/protos/streams"
+)
+
+// MetadataKey is the key of the lifecycle rules in the user-defined
+// metadata of the bucket objects
+const MetadataKey = "storj-lifecycle"
+
+// Day is the unit of the ages in the rules
+const Day = 24 * time.Hour
+
+// Error is the errs class of invalid lifecycle rules
+var Error = errs.Class("lifecycle error")
+
+// Rule is a lifecycle rule of the objects of a bucket with paths starting
+// with Prefix. A zero number of days disables the action.
+type Rule struct {
+	ID     string `json:"id,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// ExpirationDays is the age in days after which the objects are
+	// deleted, and the number of days the previous versions of the objects
+	// of versioned buckets are kept after they're replaced
+	ExpirationDays int `json:"expiration_days,omitempty"`
+	// AbortIncompleteUploadDays is the age in days after which the
+	// segments of uploads that never completed are deleted
+	AbortIncompleteUploadDays int `json:"abort_incomplete_upload_days,omitempty"`
+}
+
+// Validate checks that the rule has a valid action
+func (r Rule) Validate() error {
+	if r.ExpirationDays < 0 || r.AbortIncompleteUploadDays < 0 {
+		return Error.New("rule %q: negative number of days", r.ID)
+	}
+	if r.ExpirationDays == 0 && r.AbortIncompleteUploadDays == 0 {
+		return Error.New("rule %q: no action", r.ID)
+	}
+	return nil
+}
+
+// Matches tells whether the rule applies to the object at path in the
+// bucket
+func (r Rule) Matches(path string) bool {
+	return strings.HasPrefix(path, r.Prefix)
+}
+
+// Expired tells whether an object created at created has expired at now
+func (r Rule) Expired(created, now time.Time) bool {
+	return r.ExpirationDays > 0 &&
+		!created.After(now.Add(-time.Duration(r.ExpirationDays)*Day))
+}
+
+// Abandoned tells whether an incomplete upload started at started should
+// be aborted at now
+func (r Rule) Abandoned(started, now time.Time) bool {
+	return r.AbortIncompleteUploadDays > 0 &&
+		!started.After(now.Add(-time.Duration(r.AbortIncompleteUploadDays)*Day))
+}
+
+// Validate checks all rules
+func Validate(rules []Rule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encode encodes the rules for the bucket metadata
+func Encode(rules []Rule) (string, error) {
+	if err := Validate(rules); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(rules)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	return string(b), nil
+}
+
+// Decode decodes the rules from the bucket metadata. An empty value has no
+// rules.
+func Decode(value string) ([]Rule, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var rules []Rule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return rules, nil
+}
+
+// FromStreamMetadata decodes the rules from the metadata of the last
+// segment of a bucket object, as stored in the pointer
+func FromStreamMetadata(data []byte) ([]Rule, error) {
+	msi := streamspb.MetaStreamInfo{}
+	if err := proto.Unmarshal(data, &msi); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	meta := objects.SerializableMeta{}
+	if err := proto.Unmarshal(msi.Metadata, &meta); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return Decode(meta.UserDefined[MetadataKey])
+}
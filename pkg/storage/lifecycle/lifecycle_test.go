@@ -0,0 +1,68 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storage/objects"
+	streamspb "storj.io/storj/protos/streams"
+)
+
+func TestRule(t *testing.T) {
+	now := time.Now()
+	rule := Rule{Prefix: "logs/", ExpirationDays: 30}
+
+	assert.NoError(t, rule.Validate())
+	assert.True(t, rule.Matches("logs/a"))
+	assert.False(t, rule.Matches("log"))
+	assert.True(t, rule.Expired(now.Add(-30*Day), now))
+	assert.False(t, rule.Expired(now.Add(-29*Day), now))
+	assert.False(t, rule.Abandoned(now.Add(-100*Day), now))
+
+	for _, invalid := range []Rule{
+		{ID: "none"},
+		{ID: "negative", ExpirationDays: -1},
+		{ID: "negative abort", AbortIncompleteUploadDays: -1},
+	} {
+		assert.Error(t, invalid.Validate(), invalid.ID)
+		_, err := Encode([]Rule{rule, invalid})
+		assert.True(t, Error.Has(err), invalid.ID)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	rules := []Rule{
+		{ID: "logs", Prefix: "logs/", ExpirationDays: 30},
+		{ID: "uploads", AbortIncompleteUploadDays: 7},
+	}
+	value, err := Encode(rules)
+	assert.NoError(t, err)
+
+	decoded, err := Decode(value)
+	assert.NoError(t, err)
+	assert.Equal(t, rules, decoded)
+
+	decoded, err = Decode("")
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	_, err = Decode("{")
+	assert.True(t, Error.Has(err))
+
+	metadata, err := proto.Marshal(&objects.SerializableMeta{
+		UserDefined: map[string]string{MetadataKey: value},
+	})
+	assert.NoError(t, err)
+	data, err := proto.Marshal(&streamspb.MetaStreamInfo{Metadata: metadata})
+	assert.NoError(t, err)
+
+	decoded, err = FromStreamMetadata(data)
+	assert.NoError(t, err)
+	assert.Equal(t, rules, decoded)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package objects
+
+import (
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	streamspb "storj.io/storj/protos/streams"
+)
+
+// CompressionKey is the user-defined metadata of an object selecting the
+// compression of its data: "zstd" or "none". It can also be sent as the
+// x-amz-meta-storj-compression header of an S3 upload.
+const CompressionKey = "storj-compression"
+
+// UnknownCompressionError is an error class for unsupported compressions
+var UnknownCompressionError = errs.Class("unknown compression")
+
+// ParseCompression returns the compression named by value. An empty value
+// is no compression.
+func ParseCompression(value string) (streamspb.CompressionType, error) {
+	if value == "" {
+		return streamspb.CompressionType_NONE, nil
+	}
+	compression, ok := streamspb.CompressionType_value[strings.ToUpper(value)]
+	if !ok {
+		return streamspb.CompressionType_NONE, UnknownCompressionError.New(value)
+	}
+	return streamspb.CompressionType(compression), nil
+}
+
+// compressionValue returns the value of the compression in the user-defined
+// metadata, whose key may have the prefix of the S3 metadata headers
+func compressionValue(userDefined map[string]string) (value string, ok bool) {
+	for k, v := range userDefined {
+		k = strings.ToLower(k)
+		if k == CompressionKey || k == "x-amz-meta-"+CompressionKey {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// HasCompression tells whether the user-defined metadata selects a
+// compression, including none
+func HasCompression(userDefined map[string]string) bool {
+	_, ok := compressionValue(userDefined)
+	return ok
+}
@@ -23,18 +23,23 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 // SerializableMeta is the object metadata that will be stored serialized
 type SerializableMeta struct {
-	ContentType          string            `protobuf:"bytes,1,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
-	UserDefined          map[string]string `protobuf:"bytes,2,rep,name=UserDefined,proto3" json:"UserDefined,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	ContentType string            `protobuf:"bytes,1,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
+	UserDefined map[string]string `protobuf:"bytes,2,rep,name=UserDefined,proto3" json:"UserDefined,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// the version of the object in a versioned bucket
+	VersionID string `protobuf:"bytes,3,opt,name=VersionID,proto3" json:"VersionID,omitempty"`
+	// whether the object is a delete marker in the history of a versioned
+	// bucket
+	DeleteMarker         bool     `protobuf:"varint,4,opt,name=DeleteMarker,proto3" json:"DeleteMarker,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SerializableMeta) Reset()         { *m = SerializableMeta{} }
 func (m *SerializableMeta) String() string { return proto.CompactTextString(m) }
 func (*SerializableMeta) ProtoMessage()    {}
 func (*SerializableMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meta_5a7fa61f937f80b3, []int{0}
+	return fileDescriptor_meta_5ea88946de7736d7, []int{0}
 }
 func (m *SerializableMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SerializableMeta.Unmarshal(m, b)
@@ -68,24 +73,41 @@ func (m *SerializableMeta) GetUserDefined() map[string]string {
 	return nil
 }
 
+func (m *SerializableMeta) GetVersionID() string {
+	if m != nil {
+		return m.VersionID
+	}
+	return ""
+}
+
+func (m *SerializableMeta) GetDeleteMarker() bool {
+	if m != nil {
+		return m.DeleteMarker
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*SerializableMeta)(nil), "objects.SerializableMeta")
 	proto.RegisterMapType((map[string]string)(nil), "objects.SerializableMeta.UserDefinedEntry")
 }
 
-func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_5a7fa61f937f80b3) }
+func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_5ea88946de7736d7) }
 
-var fileDescriptor_meta_5a7fa61f937f80b3 = []byte{
-	// 172 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_meta_5ea88946de7736d7 = []byte{
+	// 213 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xca, 0x4d, 0x2d, 0x49,
 	0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0xcf, 0x4f, 0xca, 0x4a, 0x4d, 0x2e, 0x29, 0x56,
-	0x3a, 0xc4, 0xc8, 0x25, 0x10, 0x9c, 0x5a, 0x94, 0x99, 0x98, 0x93, 0x59, 0x95, 0x98, 0x94, 0x93,
-	0xea, 0x9b, 0x5a, 0x92, 0x28, 0xa4, 0xc0, 0xc5, 0xed, 0x9c, 0x9f, 0x57, 0x92, 0x9a, 0x57, 0x12,
-	0x52, 0x59, 0x90, 0x2a, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x19, 0x84, 0x2c, 0x24, 0xe4, 0xc3, 0xc5,
-	0x1d, 0x5a, 0x9c, 0x5a, 0xe4, 0x92, 0x9a, 0x96, 0x99, 0x97, 0x9a, 0x22, 0xc1, 0xa4, 0xc0, 0xac,
-	0xc1, 0x6d, 0xa4, 0xa5, 0x07, 0x35, 0x55, 0x0f, 0xdd, 0x44, 0x3d, 0x24, 0xc5, 0xae, 0x79, 0x25,
-	0x45, 0x95, 0x41, 0xc8, 0xda, 0xa5, 0xec, 0xb8, 0x04, 0xd0, 0x15, 0x08, 0x09, 0x70, 0x31, 0x67,
-	0xa7, 0x56, 0x42, 0xed, 0x06, 0x31, 0x85, 0x44, 0xb8, 0x58, 0xcb, 0x12, 0x73, 0x4a, 0x53, 0x25,
-	0x98, 0xc0, 0x62, 0x10, 0x8e, 0x15, 0x93, 0x05, 0x63, 0x12, 0x1b, 0xd8, 0x53, 0xc6, 0x80, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x65, 0x5b, 0x93, 0x65, 0xe2, 0x00, 0x00, 0x00,
+	0x6a, 0x61, 0xe2, 0x12, 0x08, 0x4e, 0x2d, 0xca, 0x4c, 0xcc, 0xc9, 0xac, 0x4a, 0x4c, 0xca, 0x49,
+	0xf5, 0x4d, 0x2d, 0x49, 0x14, 0x52, 0xe0, 0xe2, 0x76, 0xce, 0xcf, 0x2b, 0x49, 0xcd, 0x2b, 0x09,
+	0xa9, 0x2c, 0x48, 0x95, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x42, 0x16, 0x12, 0xf2, 0xe1, 0xe2,
+	0x0e, 0x2d, 0x4e, 0x2d, 0x72, 0x49, 0x4d, 0xcb, 0xcc, 0x4b, 0x4d, 0x91, 0x60, 0x52, 0x60, 0xd6,
+	0xe0, 0x36, 0xd2, 0xd2, 0x83, 0x9a, 0xaa, 0x87, 0x6e, 0xa2, 0x1e, 0x92, 0x62, 0xd7, 0xbc, 0x92,
+	0xa2, 0xca, 0x20, 0x64, 0xed, 0x42, 0x32, 0x5c, 0x9c, 0x61, 0xa9, 0x45, 0xc5, 0x99, 0xf9, 0x79,
+	0x9e, 0x2e, 0x12, 0xcc, 0x60, 0xdb, 0x10, 0x02, 0x42, 0x4a, 0x5c, 0x3c, 0x2e, 0xa9, 0x39, 0xa9,
+	0x25, 0xa9, 0xbe, 0x89, 0x45, 0xd9, 0xa9, 0x45, 0x12, 0x2c, 0x0a, 0x8c, 0x1a, 0x1c, 0x41, 0x28,
+	0x62, 0x52, 0x76, 0x5c, 0x02, 0xe8, 0x56, 0x08, 0x09, 0x70, 0x31, 0x67, 0xa7, 0x56, 0x42, 0x5d,
+	0x0f, 0x62, 0x0a, 0x89, 0x70, 0xb1, 0x96, 0x25, 0xe6, 0x94, 0xa6, 0x4a, 0x30, 0x81, 0xc5, 0x20,
+	0x1c, 0x2b, 0x26, 0x0b, 0xc6, 0x24, 0x36, 0x70, 0xb0, 0x18, 0x03, 0x06, 0x00, 0xb1, 0xb2, 0x1d,
+	0x7b, 0x24, 0x01, 0x00, 0x00,
 }
@@ -48,6 +48,8 @@ type Store interface {
 	Put(ctx context.Context, path paths.Path, data io.Reader,
 		metadata SerializableMeta, expiration time.Time) (meta Meta, err error)
 	Delete(ctx context.Context, path paths.Path) (err error)
+	Move(ctx context.Context, from, to paths.Path) (err error)
+	MoveBatch(ctx context.Context, from, to []paths.Path) (err error)
 	List(ctx context.Context, prefix, startAfter, endBefore paths.Path,
 		recursive bool, limit int, metaFlags uint32) (items []ListItem,
 		more bool, err error)
@@ -97,12 +99,18 @@ func (o *objStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 	// TODO(kaloyan): autodetect content type
 	// if metadata.GetContentType() == "" {}
 
+	value, _ := compressionValue(metadata.UserDefined)
+	compression, err := ParseCompression(value)
+	if err != nil {
+		return Meta{}, err
+	}
+
 	// TODO(kaloyan): encrypt metadata.UserDefined before serializing
 	b, err := proto.Marshal(&metadata)
 	if err != nil {
 		return Meta{}, err
 	}
-	m, err := o.s.Put(ctx, path, data, b, expiration)
+	m, err := o.s.Put(ctx, path, data, b, expiration, compression)
 	return convertMeta(m), err
 }
 
@@ -116,6 +124,32 @@ func (o *objStore) Delete(ctx context.Context, path paths.Path) (err error) {
 	return o.s.Delete(ctx, path)
 }
 
+// Move moves the object at from to to without copying its data. An object
+// at to is replaced.
+func (o *objStore) Move(ctx context.Context, from, to paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(from) == 0 || len(to) == 0 {
+		return NoPathError.New("")
+	}
+
+	return o.s.Move(ctx, from, to)
+}
+
+// MoveBatch moves the objects at from to the paths at the same index of to,
+// atomically. A path may be moved from and to in the same batch.
+func (o *objStore) MoveBatch(ctx context.Context, from, to []paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, path := range append(append([]paths.Path(nil), from...), to...) {
+		if len(path) == 0 {
+			return NoPathError.New("")
+		}
+	}
+
+	return o.s.MoveBatch(ctx, from, to)
+}
+
 func (o *objStore) List(ctx context.Context, prefix, startAfter,
 	endBefore paths.Path, recursive bool, limit int, metaFlags uint32) (
 	items []ListItem, more bool, err error) {
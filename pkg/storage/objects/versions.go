@@ -0,0 +1,591 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package objects
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+// NullVersion is the version ID of the objects put before versioning was
+// enabled
+const NullVersion = "null"
+
+// versionIDLength is the length of the version IDs
+const versionIDLength = 24
+
+// VersionsDir is the directory of the buckets with versioning keeping the
+// previous versions of their objects, under
+// <bucket>/<VersionsDir>/<path>/<versionID>. Keeping them in the bucket
+// lets the api keys restricted to the bucket reach them.
+const VersionsDir = ".versions"
+
+var (
+	// ErrDeleteMarker is an error class for reading a delete marker
+	ErrDeleteMarker = errs.Class("object is a delete marker")
+	// ErrInvalidVersion is an error class for malformed version IDs
+	ErrInvalidVersion = errs.Class("invalid version ID")
+	// ErrReservedPath is an error class for the paths of a VersionedStore
+	// keeping the previous versions
+	ErrReservedPath = errs.Class("reserved path")
+)
+
+// Version is a single version of an object in a listing
+type Version struct {
+	Path      paths.Path
+	VersionID string
+	Meta      Meta
+	// IsLatest is set for the version returned when no version is given
+	IsLatest bool
+	// DeleteMarker is set for the versions created by deleting the object
+	DeleteMarker bool
+}
+
+// VersionedStore is a Store keeping the previous versions of the objects.
+// Every Put creates a new version and Delete creates a delete marker, the
+// older versions can still be read or deleted by their version ID. An
+// empty version means the latest one.
+type VersionedStore interface {
+	Store
+	MetaVersion(ctx context.Context, path paths.Path, version string) (
+		meta Meta, err error)
+	GetVersion(ctx context.Context, path paths.Path, version string) (
+		rr ranger.Ranger, meta Meta, err error)
+	DeleteVersion(ctx context.Context, path paths.Path, version string) (
+		err error)
+	ListVersions(ctx context.Context, prefix, startAfter paths.Path,
+		limit int) (items []Version, more bool, err error)
+}
+
+// versionedStore keeps the latest version of the object at path in
+// <root>/<path> and the previous versions in <history>/<path>/<versionID>.
+// Archiving a version moves its pointers without copying the data. The
+// history may be under root, in which case it's hidden from the listings
+// and can't be written to directly. The new versions are written to the
+// history and then replace the latest version with a single batch move, so
+// the object never goes missing while they're written.
+type versionedStore struct {
+	o       Store
+	root    paths.Path
+	history paths.Path
+}
+
+// NewVersionedStore returns a VersionedStore of the objects under root in
+// store, keeping the previous versions under history. The paths used with
+// the returned store are relative to root.
+func NewVersionedStore(store Store, root, history paths.Path) VersionedStore {
+	return &versionedStore{o: store, root: root, history: history}
+}
+
+func (v *versionedStore) Meta(ctx context.Context, path paths.Path) (
+	meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return v.MetaVersion(ctx, path, "")
+}
+
+func (v *versionedStore) Get(ctx context.Context, path paths.Path) (
+	rr ranger.Ranger, meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return v.GetVersion(ctx, path, "")
+}
+
+func (v *versionedStore) Put(ctx context.Context, path paths.Path,
+	data io.Reader, metadata SerializableMeta, expiration time.Time) (
+	meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err = v.checkPath(path); err != nil {
+		return Meta{}, err
+	}
+
+	metadata.VersionID, err = newVersionID(time.Now())
+	if err != nil {
+		return Meta{}, err
+	}
+	metadata.DeleteMarker = false
+	written := v.version(path, metadata.VersionID)
+	meta, err = v.o.Put(ctx, written, data, metadata, expiration)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	from, to, err := v.archiveMove(ctx, path)
+	if err == nil {
+		err = v.o.MoveBatch(ctx, append(from, written),
+			append(to, v.current(path)))
+	}
+	if err != nil {
+		return Meta{}, utils.CombineErrors(err, v.o.Delete(ctx, written))
+	}
+	return meta, nil
+}
+
+// Delete archives the latest version of the object and puts a delete
+// marker on top of it
+func (v *versionedStore) Delete(ctx context.Context, path paths.Path) (
+	err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err = v.checkPath(path); err != nil {
+		return err
+	}
+
+	from, to, err := v.archiveMove(ctx, path)
+	if err != nil {
+		return err
+	}
+	if len(from) == 0 {
+		return storage.ErrKeyNotFound.New(path.String())
+	}
+
+	// the marker is put first, so that the latest version isn't archived
+	// without it
+	id, err := newVersionID(time.Now())
+	if err != nil {
+		return err
+	}
+	marker := v.version(path, id)
+	_, err = v.o.Put(ctx, marker, bytes.NewReader(nil),
+		SerializableMeta{VersionID: id, DeleteMarker: true}, time.Time{})
+	if err != nil {
+		return err
+	}
+	if err = v.o.MoveBatch(ctx, from, to); err != nil {
+		return utils.CombineErrors(err, v.o.Delete(ctx, marker))
+	}
+	return nil
+}
+
+// Move moves the latest version of the object at from to to, archiving
+// the latest version at to. The previous versions of from stay in place.
+func (v *versionedStore) Move(ctx context.Context, from, to paths.Path) (
+	err error) {
+	defer mon.Task()(&ctx)(&err)
+	return v.MoveBatch(ctx, []paths.Path{from}, []paths.Path{to})
+}
+
+// MoveBatch moves the latest versions of the objects at from to the paths
+// at the same index of to, archiving the latest versions at to, all at
+// once. The paths must be distinct.
+func (v *versionedStore) MoveBatch(ctx context.Context, from,
+	to []paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(from) != len(to) {
+		return errs.New("moving %d objects to %d paths", len(from), len(to))
+	}
+
+	var batchFrom, batchTo []paths.Path
+	for i := range from {
+		if err = v.checkPath(from[i]); err != nil {
+			return err
+		}
+		if err = v.checkPath(to[i]); err != nil {
+			return err
+		}
+		archiveFrom, archiveTo, err := v.archiveMove(ctx, to[i])
+		if err != nil {
+			return err
+		}
+		batchFrom = append(append(batchFrom, archiveFrom...), v.current(from[i]))
+		batchTo = append(append(batchTo, archiveTo...), v.current(to[i]))
+	}
+	return v.o.MoveBatch(ctx, batchFrom, batchTo)
+}
+
+// List lists the latest versions of the objects
+func (v *versionedStore) List(ctx context.Context, prefix, startAfter,
+	endBefore paths.Path, recursive bool, limit int, metaFlags uint32) (
+	items []ListItem, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		var page []ListItem
+		page, more, err = v.o.List(ctx, prefix.Prepend(v.root...), startAfter,
+			endBefore, recursive, limit, metaFlags)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, item := range page {
+			if v.inHistory(item.Path.Prepend(prefix...)) {
+				continue
+			}
+			item.Meta = currentMeta(item.Meta)
+			items = append(items, item)
+		}
+		if len(items) > 0 || !more || len(page) == 0 || len(endBefore) > 0 {
+			return items, more, nil
+		}
+		// the page only had the history, the objects may be after it
+		startAfter = page[len(page)-1].Path
+	}
+}
+
+func (v *versionedStore) MetaVersion(ctx context.Context, path paths.Path,
+	version string) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, meta, err = v.locate(ctx, path, version)
+	return meta, err
+}
+
+func (v *versionedStore) GetVersion(ctx context.Context, path paths.Path,
+	version string) (rr ranger.Ranger, meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	p, meta, err := v.locate(ctx, path, version)
+	if err != nil {
+		return nil, Meta{}, err
+	}
+	if meta.DeleteMarker {
+		return nil, Meta{}, ErrDeleteMarker.New("%s@%s", path, meta.VersionID)
+	}
+
+	rr, _, err = v.o.Get(ctx, p)
+	if err != nil {
+		return nil, Meta{}, err
+	}
+	return rr, meta, nil
+}
+
+// DeleteVersion deletes a version of the object permanently. When the
+// latest version is deleted, the newest previous version becomes the
+// latest one, unless it's a delete marker. An empty version is the same as
+// Delete.
+func (v *versionedStore) DeleteVersion(ctx context.Context, path paths.Path,
+	version string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if version == "" {
+		return v.Delete(ctx, path)
+	}
+
+	p, _, err := v.locate(ctx, path, version)
+	if err != nil {
+		return err
+	}
+	if err = v.o.Delete(ctx, p); err != nil {
+		return err
+	}
+	return v.promote(ctx, path)
+}
+
+// ListVersions lists all versions of the objects under prefix, with the
+// paths after startAfter. The versions of an object are listed newest
+// first and are never split between pages, so a page can have more than
+// limit versions.
+func (v *versionedStore) ListVersions(ctx context.Context, prefix,
+	startAfter paths.Path, limit int) (items []Version, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if limit <= 0 || limit > storage.LookupLimit {
+		limit = storage.LookupLimit
+	}
+
+	keys, more, err := v.listKeys(ctx, prefix, startAfter, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, key := range keys {
+		versions, err := v.versions(ctx, key.Prepend(prefix...))
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, versions...)
+	}
+	return items, more, nil
+}
+
+// locate returns the path and the metadata of the version of the object
+func (v *versionedStore) locate(ctx context.Context, path paths.Path,
+	version string) (paths.Path, Meta, error) {
+	if err := v.checkPath(path); err != nil {
+		return nil, Meta{}, err
+	}
+
+	current := v.current(path)
+	m, err := v.o.Meta(ctx, current)
+	switch {
+	case err == nil:
+		m = currentMeta(m)
+		if version == "" || version == m.VersionID {
+			return current, m, nil
+		}
+	case !storage.ErrKeyNotFound.Has(err) || version == "":
+		return nil, Meta{}, err
+	}
+
+	if version == NullVersion {
+		// an archived null version has got a regular version ID
+		return nil, Meta{}, storage.ErrKeyNotFound.New("%s@%s", path, version)
+	}
+	if !validVersionID(version) {
+		return nil, Meta{}, ErrInvalidVersion.New("%q", version)
+	}
+
+	p := v.version(path, version)
+	m, err = v.o.Meta(ctx, p)
+	if err != nil {
+		return nil, Meta{}, err
+	}
+	return p, archivedMeta(m, version), nil
+}
+
+// archiveMove returns the move archiving the latest version of the object
+// to the history, to be done in a batch with the moves replacing it. There
+// is no move if there is no latest version.
+func (v *versionedStore) archiveMove(ctx context.Context, path paths.Path) (
+	from, to []paths.Path, err error) {
+	current := v.current(path)
+	m, err := v.o.Meta(ctx, current)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	id := m.VersionID
+	if id == "" {
+		// put before versioning was enabled
+		id, err = newVersionID(m.Modified)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return []paths.Path{current}, []paths.Path{v.version(path, id)}, nil
+}
+
+// promote makes the newest previous version of the object the latest one
+// when there is no latest version, unless it's a delete marker
+func (v *versionedStore) promote(ctx context.Context, path paths.Path) error {
+	_, err := v.o.Meta(ctx, v.current(path))
+	if err == nil || !storage.ErrKeyNotFound.Has(err) {
+		return err
+	}
+
+	versions, err := v.archived(ctx, path)
+	if err != nil || len(versions) == 0 || versions[0].DeleteMarker {
+		return err
+	}
+	return v.o.Move(ctx, v.version(path, versions[0].VersionID),
+		v.current(path))
+}
+
+// versions returns the latest and the previous versions of the object,
+// newest first
+func (v *versionedStore) versions(ctx context.Context, path paths.Path) (
+	[]Version, error) {
+	var versions []Version
+	m, err := v.o.Meta(ctx, v.current(path))
+	switch {
+	case err == nil:
+		m = currentMeta(m)
+		versions = append(versions, Version{
+			Path:      path,
+			VersionID: m.VersionID,
+			Meta:      m,
+			IsLatest:  true,
+		})
+	case !storage.ErrKeyNotFound.Has(err):
+		return nil, err
+	}
+
+	archived, err := v.archived(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 && len(archived) > 0 {
+		archived[0].IsLatest = true
+	}
+	return append(versions, archived...), nil
+}
+
+// archived returns the previous versions of the object, newest first
+func (v *versionedStore) archived(ctx context.Context, path paths.Path) (
+	versions []Version, err error) {
+	var startAfter paths.Path
+	for {
+		items, more, err := v.o.List(ctx, path.Prepend(v.history...),
+			startAfter, nil, false, 0, meta.All)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			id := item.Path.String()
+			// prefixes are the histories of the objects below path
+			if item.IsPrefix || !validVersionID(id) {
+				continue
+			}
+			versions = append(versions, Version{
+				Path:         path,
+				VersionID:    id,
+				Meta:         archivedMeta(item.Meta, id),
+				DeleteMarker: item.Meta.DeleteMarker,
+			})
+		}
+		if !more || len(items) == 0 {
+			return versions, nil
+		}
+		startAfter = items[len(items)-1].Path
+	}
+}
+
+// listKeys lists the paths, relative to prefix, of up to limit objects
+// having a latest or a previous version
+func (v *versionedStore) listKeys(ctx context.Context, prefix,
+	startAfter paths.Path, limit int) (keys []paths.Path, more bool,
+	err error) {
+	found := map[string]paths.Path{}
+
+	items, more, err := v.List(ctx, prefix, startAfter, nil, true, limit,
+		meta.None)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, item := range items {
+		found[item.Path.String()] = item.Path
+	}
+
+	// the history has an entry per version, so it's read until it has more
+	// objects than the page or ends
+	var history int
+	cursor := startAfter
+	for history <= limit {
+		items, moreHistory, err := v.o.List(ctx, prefix.Prepend(v.history...),
+			cursor, nil, true, 0, meta.None)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, item := range items {
+			if len(item.Path) < 2 || !validVersionID(item.Path[len(item.Path)-1]) {
+				continue
+			}
+			key := item.Path[:len(item.Path)-1]
+			if len(startAfter) > 0 && key.String() <= startAfter.String() {
+				continue
+			}
+			if _, ok := found[key.String()]; !ok {
+				found[key.String()] = key
+				history++
+			}
+		}
+		if !moreHistory || len(items) == 0 {
+			break
+		}
+		cursor = items[len(items)-1].Path
+	}
+
+	for _, key := range found {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, k int) bool {
+		return keys[i].String() < keys[k].String()
+	})
+	if len(keys) > limit {
+		keys, more = keys[:limit], true
+	}
+	return keys, more, nil
+}
+
+// checkPath checks that path is the path of an object, rather than of the
+// history
+func (v *versionedStore) checkPath(path paths.Path) error {
+	if len(path) == 0 {
+		return NoPathError.New("")
+	}
+	if v.inHistory(path) {
+		return ErrReservedPath.New(path.String())
+	}
+	return nil
+}
+
+// inHistory returns whether path, relative to root, is in the history
+func (v *versionedStore) inHistory(path paths.Path) bool {
+	return path.Prepend(v.root...).HasPrefix(v.history)
+}
+
+// current returns the path of the latest version of the object
+func (v *versionedStore) current(path paths.Path) paths.Path {
+	return path.Prepend(v.root...)
+}
+
+// version returns the path of a previous version of the object
+func (v *versionedStore) version(path paths.Path, id string) paths.Path {
+	return path.Prepend(v.history...).Append(id)
+}
+
+// currentMeta returns the metadata of a latest version. The objects put
+// before versioning was enabled have got the null version.
+func currentMeta(m Meta) Meta {
+	if m.VersionID == "" {
+		m.VersionID = NullVersion
+	}
+	return m
+}
+
+// archivedMeta returns the metadata of a previous version. The
+// modification date is the creation of the version the ID tells, as the
+// versions put before versioning was enabled only get an ID when archived.
+func archivedMeta(m Meta, id string) Meta {
+	m.VersionID = id
+	m.Modified = VersionTime(id)
+	return m
+}
+
+// newVersionID returns a new version ID for a version created at t. The
+// IDs of newer versions sort before the IDs of older ones.
+func newVersionID(t time.Time) (string, error) {
+	if t.Before(time.Unix(0, 0)) {
+		t = time.Now()
+	}
+	var random [4]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x%08x", uint64(math.MaxInt64-t.UnixNano()),
+		binary.BigEndian.Uint32(random[:])), nil
+}
+
+// VersionTime returns the creation time of the version with the ID id, the
+// zero time if id isn't a version ID
+func VersionTime(id string) time.Time {
+	if !validVersionID(id) {
+		return time.Time{}
+	}
+	n, err := strconv.ParseUint(id[:16], 16, 63)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, math.MaxInt64-int64(n))
+}
+
+// validVersionID checks that id has the format of the version IDs
+func validVersionID(id string) bool {
+	if len(id) != versionIDLength {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
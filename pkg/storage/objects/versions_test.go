@@ -0,0 +1,439 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package objects
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/storage"
+)
+
+var ctx = context.Background()
+
+type memObject struct {
+	data []byte
+	meta Meta
+}
+
+// memStore is an in-memory Store
+type memStore struct {
+	objects map[string]memObject
+}
+
+func newMemStore() *memStore {
+	return &memStore{objects: map[string]memObject{}}
+}
+
+func (m *memStore) Meta(ctx context.Context, path paths.Path) (Meta, error) {
+	obj, ok := m.objects[path.String()]
+	if !ok {
+		return Meta{}, storage.ErrKeyNotFound.New(path.String())
+	}
+	return obj.meta, nil
+}
+
+func (m *memStore) Get(ctx context.Context, path paths.Path) (ranger.Ranger, Meta, error) {
+	obj, ok := m.objects[path.String()]
+	if !ok {
+		return nil, Meta{}, storage.ErrKeyNotFound.New(path.String())
+	}
+	return ranger.ByteRanger(obj.data), obj.meta, nil
+}
+
+func (m *memStore) Put(ctx context.Context, path paths.Path, data io.Reader,
+	metadata SerializableMeta, expiration time.Time) (Meta, error) {
+	b, err := ioutil.ReadAll(data)
+	if err != nil {
+		return Meta{}, err
+	}
+	meta := Meta{
+		SerializableMeta: metadata,
+		Modified:         time.Now(),
+		Expiration:       expiration,
+		Size:             int64(len(b)),
+	}
+	m.objects[path.String()] = memObject{data: b, meta: meta}
+	return meta, nil
+}
+
+func (m *memStore) Delete(ctx context.Context, path paths.Path) error {
+	if _, ok := m.objects[path.String()]; !ok {
+		return storage.ErrKeyNotFound.New(path.String())
+	}
+	delete(m.objects, path.String())
+	return nil
+}
+
+func (m *memStore) Move(ctx context.Context, from, to paths.Path) error {
+	obj, ok := m.objects[from.String()]
+	if !ok {
+		return storage.ErrKeyNotFound.New(from.String())
+	}
+	delete(m.objects, from.String())
+	m.objects[to.String()] = obj
+	return nil
+}
+
+func (m *memStore) MoveBatch(ctx context.Context, from, to []paths.Path) error {
+	moved := make([]memObject, len(from))
+	for i, path := range from {
+		obj, ok := m.objects[path.String()]
+		if !ok {
+			return storage.ErrKeyNotFound.New(path.String())
+		}
+		moved[i] = obj
+	}
+	for _, path := range from {
+		delete(m.objects, path.String())
+	}
+	for i, path := range to {
+		m.objects[path.String()] = moved[i]
+	}
+	return nil
+}
+
+func (m *memStore) List(ctx context.Context, prefix, startAfter,
+	endBefore paths.Path, recursive bool, limit int, metaFlags uint32) (
+	items []ListItem, more bool, err error) {
+	var keys []string
+	for key := range m.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if limit <= 0 {
+		limit = storage.LookupLimit
+	}
+	for _, key := range keys {
+		p := paths.New(key)
+		if !p.HasPrefix(prefix) || len(p) == len(prefix) {
+			continue
+		}
+		rel := p[len(prefix):]
+		isPrefix := !recursive && len(rel) > 1
+		if isPrefix {
+			rel = rel[:1]
+		}
+		if len(startAfter) > 0 && rel.String() <= startAfter.String() {
+			continue
+		}
+		if len(items) > 0 && items[len(items)-1].Path.String() == rel.String() {
+			continue
+		}
+		if len(items) == limit {
+			return items, true, nil
+		}
+		item := ListItem{Path: rel, IsPrefix: isPrefix}
+		if !isPrefix {
+			item.Meta = m.objects[key].meta
+		}
+		items = append(items, item)
+	}
+	return items, false, nil
+}
+
+func newTestVersionedStore() (*memStore, VersionedStore) {
+	store := newMemStore()
+	return store, NewVersionedStore(store, paths.New("bucket"), paths.New("bucket", VersionsDir))
+}
+
+func put(t *testing.T, store Store, path, data string) Meta {
+	m, err := store.Put(ctx, paths.New(path), strings.NewReader(data), SerializableMeta{}, time.Time{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return m
+}
+
+func read(t *testing.T, rr ranger.Ranger) string {
+	r, err := rr.Range(ctx, 0, rr.Size())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestVersionID(t *testing.T) {
+	older := time.Unix(1500000000, 1)
+	newer := older.Add(time.Nanosecond)
+
+	olderID, err := newVersionID(older)
+	assert.NoError(t, err)
+	newerID, err := newVersionID(newer)
+	assert.NoError(t, err)
+
+	assert.True(t, validVersionID(olderID))
+	assert.True(t, newerID < olderID)
+	assert.Equal(t, older, VersionTime(olderID))
+	assert.Equal(t, newer, VersionTime(newerID))
+
+	for _, id := range []string{"", NullVersion, strings.ToUpper(olderID), olderID[1:], olderID + "0"} {
+		assert.False(t, validVersionID(id), id)
+	}
+}
+
+func TestVersionedPut(t *testing.T) {
+	_, vs := newTestVersionedStore()
+
+	first := put(t, vs, "a/b", "first")
+	second := put(t, vs, "a/b", "second")
+	assert.True(t, validVersionID(first.VersionID))
+	assert.True(t, validVersionID(second.VersionID))
+	assert.NotEqual(t, first.VersionID, second.VersionID)
+
+	rr, m, err := vs.Get(ctx, paths.New("a/b"))
+	assert.NoError(t, err)
+	assert.Equal(t, "second", read(t, rr))
+	assert.Equal(t, second.VersionID, m.VersionID)
+
+	rr, m, err = vs.GetVersion(ctx, paths.New("a/b"), first.VersionID)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", read(t, rr))
+	assert.Equal(t, first.VersionID, m.VersionID)
+	assert.Equal(t, VersionTime(first.VersionID), m.Modified)
+
+	_, err = vs.MetaVersion(ctx, paths.New("a/b"), "invalid")
+	assert.True(t, ErrInvalidVersion.Has(err))
+
+	items, _, err := vs.List(ctx, nil, nil, nil, true, 0, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, paths.New("a/b"), items[0].Path)
+	}
+
+	versions, more, err := vs.ListVersions(ctx, nil, nil, 0)
+	assert.NoError(t, err)
+	assert.False(t, more)
+	if assert.Len(t, versions, 2) {
+		assert.Equal(t, second.VersionID, versions[0].VersionID)
+		assert.True(t, versions[0].IsLatest)
+		assert.Equal(t, first.VersionID, versions[1].VersionID)
+		assert.False(t, versions[1].IsLatest)
+	}
+}
+
+// checkingReader calls check before its first read, and fails after it if
+// err is set
+type checkingReader struct {
+	io.Reader
+	check func()
+	err   error
+}
+
+func (r *checkingReader) Read(p []byte) (int, error) {
+	if r.check != nil {
+		r.check()
+		r.check = nil
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Reader.Read(p)
+}
+
+func TestVersionedPutKeepsLatest(t *testing.T) {
+	store, vs := newTestVersionedStore()
+	first := put(t, vs, "a", "first")
+
+	// the latest version is readable while the next one is uploaded
+	checkFirst := func() {
+		rr, m, err := vs.Get(ctx, paths.New("a"))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "first", read(t, rr))
+			assert.Equal(t, first.VersionID, m.VersionID)
+		}
+	}
+	second, err := vs.Put(ctx, paths.New("a"), &checkingReader{
+		Reader: strings.NewReader("second"), check: checkFirst,
+	}, SerializableMeta{}, time.Time{})
+	assert.NoError(t, err)
+	rr, m, err := vs.Get(ctx, paths.New("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "second", read(t, rr))
+	assert.Equal(t, second.VersionID, m.VersionID)
+
+	// a failed upload leaves the latest version in place
+	_, err = vs.Put(ctx, paths.New("a"), &checkingReader{
+		Reader: strings.NewReader("third"), err: io.ErrUnexpectedEOF,
+	}, SerializableMeta{}, time.Time{})
+	assert.Error(t, err)
+	rr, _, err = vs.Get(ctx, paths.New("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "second", read(t, rr))
+	assert.Len(t, store.objects, 2)
+}
+
+func TestVersionedDelete(t *testing.T) {
+	_, vs := newTestVersionedStore()
+
+	assert.True(t, storage.ErrKeyNotFound.Has(vs.Delete(ctx, paths.New("a"))))
+
+	first := put(t, vs, "a", "first")
+	assert.NoError(t, vs.Delete(ctx, paths.New("a")))
+
+	_, _, err := vs.Get(ctx, paths.New("a"))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	versions, _, err := vs.ListVersions(ctx, nil, nil, 0)
+	assert.NoError(t, err)
+	if !assert.Len(t, versions, 2) {
+		return
+	}
+	marker := versions[0]
+	assert.True(t, marker.DeleteMarker)
+	assert.True(t, marker.IsLatest)
+	assert.Equal(t, first.VersionID, versions[1].VersionID)
+	assert.False(t, versions[1].DeleteMarker)
+
+	_, _, err = vs.GetVersion(ctx, paths.New("a"), marker.VersionID)
+	assert.True(t, ErrDeleteMarker.Has(err))
+
+	// deleting the delete marker restores the object
+	assert.NoError(t, vs.DeleteVersion(ctx, paths.New("a"), marker.VersionID))
+	rr, m, err := vs.Get(ctx, paths.New("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "first", read(t, rr))
+	assert.Equal(t, first.VersionID, m.VersionID)
+}
+
+func TestVersionedDeleteVersion(t *testing.T) {
+	_, vs := newTestVersionedStore()
+
+	first := put(t, vs, "a", "first")
+	second := put(t, vs, "a", "second")
+
+	// deleting the latest version makes the previous one the latest
+	assert.NoError(t, vs.DeleteVersion(ctx, paths.New("a"), second.VersionID))
+	rr, m, err := vs.Get(ctx, paths.New("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "first", read(t, rr))
+	assert.Equal(t, first.VersionID, m.VersionID)
+
+	assert.NoError(t, vs.DeleteVersion(ctx, paths.New("a"), first.VersionID))
+	_, err = vs.Meta(ctx, paths.New("a"))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	versions, _, err := vs.ListVersions(ctx, nil, nil, 0)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 0)
+}
+
+func TestVersionedNullVersion(t *testing.T) {
+	store, vs := newTestVersionedStore()
+
+	// put before versioning was enabled
+	put(t, store, "bucket/a", "null")
+
+	m, err := vs.MetaVersion(ctx, paths.New("a"), NullVersion)
+	assert.NoError(t, err)
+	assert.Equal(t, NullVersion, m.VersionID)
+
+	put(t, vs, "a", "versioned")
+
+	_, err = vs.MetaVersion(ctx, paths.New("a"), NullVersion)
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	versions, _, err := vs.ListVersions(ctx, nil, nil, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, versions, 2) {
+		rr, _, err := vs.GetVersion(ctx, paths.New("a"), versions[1].VersionID)
+		assert.NoError(t, err)
+		assert.Equal(t, "null", read(t, rr))
+	}
+}
+
+func TestVersionedListVersions(t *testing.T) {
+	_, vs := newTestVersionedStore()
+
+	put(t, vs, "a", "a")
+	put(t, vs, "b/c", "b/c")
+	put(t, vs, "b/c", "b/c")
+	put(t, vs, "d", "d")
+	assert.NoError(t, vs.Delete(ctx, paths.New("d")))
+	put(t, vs, "e", "e")
+
+	for i, tt := range []struct {
+		prefix, startAfter string
+		limit              int
+		paths              []string
+		more               bool
+	}{
+		{"", "", 0, []string{"a", "b/c", "b/c", "d", "d", "e"}, false},
+		{"", "", 2, []string{"a", "b/c", "b/c"}, true},
+		{"", "b/c", 2, []string{"d", "d", "e"}, false},
+		{"", "d", 1, []string{"e"}, false},
+		{"b", "", 0, []string{"b/c", "b/c"}, false},
+	} {
+		versions, more, err := vs.ListVersions(ctx, paths.New(tt.prefix), paths.New(tt.startAfter), tt.limit)
+		if !assert.NoError(t, err, i) {
+			continue
+		}
+		var got []string
+		for _, version := range versions {
+			got = append(got, version.Path.String())
+		}
+		assert.Equal(t, tt.paths, got, i)
+		assert.Equal(t, tt.more, more, i)
+	}
+}
+
+func TestVersionedMove(t *testing.T) {
+	_, vs := newTestVersionedStore()
+
+	put(t, vs, "a", "a")
+	put(t, vs, "b", "b")
+	assert.NoError(t, vs.Move(ctx, paths.New("a"), paths.New("b")))
+
+	rr, _, err := vs.Get(ctx, paths.New("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", read(t, rr))
+
+	versions, _, err := vs.ListVersions(ctx, nil, nil, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, versions, 2) {
+		assert.Equal(t, "b", versions[1].Path.String())
+		rr, _, err := vs.GetVersion(ctx, paths.New("b"), versions[1].VersionID)
+		assert.NoError(t, err)
+		assert.Equal(t, "b", read(t, rr))
+	}
+}
+
+func TestVersionedHistory(t *testing.T) {
+	store, vs := newTestVersionedStore()
+
+	first := put(t, vs, "a", "first")
+	put(t, vs, "a", "second")
+	_, err := store.Meta(ctx, paths.New("bucket", ".versions", "a", first.VersionID))
+	assert.NoError(t, err)
+
+	// the history is in the bucket, but hidden
+	for _, recursive := range []bool{false, true} {
+		items, more, err := vs.List(ctx, nil, nil, nil, recursive, 1, 0)
+		assert.NoError(t, err)
+		assert.False(t, more)
+		if assert.Len(t, items, 1, "recursive: %v", recursive) {
+			assert.Equal(t, paths.New("a"), items[0].Path)
+		}
+	}
+
+	path := paths.New(".versions", "a", first.VersionID)
+	_, err = vs.Meta(ctx, path)
+	assert.True(t, ErrReservedPath.Has(err))
+	_, err = vs.Put(ctx, path, strings.NewReader("data"), SerializableMeta{}, time.Time{})
+	assert.True(t, ErrReservedPath.Has(err))
+	assert.True(t, ErrReservedPath.Has(vs.Delete(ctx, path)))
+	assert.True(t, ErrReservedPath.Has(vs.Move(ctx, paths.New("a"), path)))
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package segments
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"storj.io/storj/pkg/eestream"
+	opb "storj.io/storj/protos/overlay"
+	ppb "storj.io/storj/protos/pointerdb"
+)
+
+// NodeReporter is notified of the storage nodes that misbehaved while the
+// remote segments were read. The slow nodes aren't failures, so they're
+// left out.
+type NodeReporter interface {
+	// RecordFailures records the IDs of the nodes that returned corrupted
+	// data and of the nodes that failed to return their pieces
+	RecordFailures(corrupted, missing []string)
+}
+
+// NodeReport lists the storage nodes that returned corrupted, missing or
+// slow pieces while a remote segment was read, so that they can be
+// reported to statdb. The zero value is an empty report ready to use.
+type NodeReport struct {
+	mu     sync.Mutex
+	nodes  map[int]string // node IDs by piece number
+	pieces eestream.PieceReport
+}
+
+// Corrupted returns the IDs of the nodes that returned corrupted data
+func (r *NodeReport) Corrupted() []string {
+	return r.nodeIDs(r.pieces.Corrupted())
+}
+
+// Missing returns the IDs of the nodes that failed to return their pieces
+func (r *NodeReport) Missing() []string {
+	return r.nodeIDs(r.pieces.Missing())
+}
+
+// Slow returns the IDs of the nodes that were too slow to be used
+func (r *NodeReport) Slow() []string {
+	return r.nodeIDs(r.pieces.Slow())
+}
+
+// setPieces records which node stores which piece of the segment
+func (r *NodeReport) setPieces(pieces []*ppb.RemotePiece) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nodes = make(map[int]string, len(pieces))
+	for _, piece := range pieces {
+		r.nodes[int(piece.GetPieceNum())] = piece.GetNodeId()
+	}
+}
+
+// nodeIDs returns the IDs of the nodes storing the pieces
+func (r *NodeReport) nodeIDs(pieces []int) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, num := range pieces {
+		if id, ok := r.nodes[num]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// reportedRanger reads a remote segment and reports the nodes that
+// misbehaved during each read once it's closed
+type reportedRanger struct {
+	segments *segmentStore
+	pointer  *ppb.Pointer
+	nodes    []*opb.Node
+	es       eestream.ErasureScheme
+}
+
+// Size implements Ranger.Size
+func (rr *reportedRanger) Size() int64 {
+	return rr.pointer.GetSize()
+}
+
+// Range implements Ranger.Range
+func (rr *reportedRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	report := &NodeReport{}
+	decoded, err := rr.segments.decode(ctx, rr.pointer, rr.nodes, rr.es, report)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := decoded.Range(ctx, offset, length)
+	if err != nil {
+		rr.segments.reporter.RecordFailures(report.Corrupted(), report.Missing())
+		return nil, err
+	}
+	return &reportedReadCloser{ReadCloser: rc, report: report, reporter: rr.segments.reporter}, nil
+}
+
+// reportedReadCloser reports the nodes that misbehaved during the read
+// when it's closed
+type reportedReadCloser struct {
+	io.ReadCloser
+	report   *NodeReport
+	reporter NodeReporter
+	once     sync.Once
+}
+
+// Close implements io.Closer
+func (rc *reportedReadCloser) Close() error {
+	err := rc.ReadCloser.Close()
+	rc.once.Do(func() {
+		rc.reporter.RecordFailures(rc.report.Corrupted(), rc.report.Missing())
+	})
+	return err
+}
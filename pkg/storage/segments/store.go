@@ -25,6 +25,7 @@ import (
 	"storj.io/storj/pkg/storage/ec"
 	opb "storj.io/storj/protos/overlay"
 	ppb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
 )
 
 var (
@@ -49,11 +50,18 @@ type ListItem struct {
 // Store for segments
 type Store interface {
 	Meta(ctx context.Context, path paths.Path) (meta Meta, err error)
-	Get(ctx context.Context, path paths.Path) (rr ranger.Ranger,
-		meta Meta, err error)
+	Get(ctx context.Context, path paths.Path, report *NodeReport) (
+		rr ranger.Ranger, meta Meta, err error)
 	Put(ctx context.Context, path paths.Path, data io.Reader, metadata []byte,
 		expiration time.Time) (meta Meta, err error)
+	PutReferences(ctx context.Context, path paths.Path, references []paths.Path,
+		metadata []byte, expiration time.Time) (meta Meta, err error)
 	Delete(ctx context.Context, path paths.Path) (err error)
+	DeleteUnreferenced(ctx context.Context, paths []paths.Path) (err error)
+	GetBatch(ctx context.Context, paths []paths.Path) (rrs []ranger.Ranger,
+		metas []Meta, err error)
+	DeleteBatch(ctx context.Context, paths []paths.Path) (err error)
+	MoveBatch(ctx context.Context, from, to []paths.Path) (err error)
 	List(ctx context.Context, prefix, startAfter, endBefore paths.Path,
 		recursive bool, limit int, metaFlags uint32) (items []ListItem,
 		more bool, err error)
@@ -64,13 +72,20 @@ type segmentStore struct {
 	ec            ecclient.Client
 	pdb           pdbclient.Client
 	rs            eestream.RedundancyStrategy
+	schemeType    ppb.RedundancyScheme_SchemeType
 	thresholdSize int
+	reporter      NodeReporter
 }
 
-// NewSegmentStore creates a new instance of segmentStore
+// NewSegmentStore creates a new instance of segmentStore. The remote
+// segments are stored with rs, which must be of the scheme type st, so that
+// the downloads pick the right decoder from the pointers. The nodes that
+// misbehave while the segments are read are reported to nr, if not nil.
 func NewSegmentStore(oc overlay.Client, ec ecclient.Client,
-	pdb pdbclient.Client, rs eestream.RedundancyStrategy, t int) Store {
-	return &segmentStore{oc: oc, ec: ec, pdb: pdb, rs: rs, thresholdSize: t}
+	pdb pdbclient.Client, rs eestream.RedundancyStrategy,
+	st ppb.RedundancyScheme_SchemeType, t int, nr NodeReporter) Store {
+	return &segmentStore{oc: oc, ec: ec, pdb: pdb, rs: rs, schemeType: st,
+		thresholdSize: t, reporter: nr}
 }
 
 // Meta retrieves the metadata of the segment
@@ -134,6 +149,13 @@ func (s *segmentStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 	// puts pointer to pointerDB
 	err = s.pdb.Put(ctx, path, p)
 	if err != nil {
+		if p.GetType() == ppb.Pointer_REMOTE && pdbclient.ErrChunkExists.Has(err) {
+			// the existing chunk is kept, so nothing references the pieces
+			// just uploaded
+			if delErr := s.deleteRemote(ctx, p.GetRemote()); delErr != nil {
+				zap.S().Warnf("Failed deleting the pieces of the rejected segment %s: %v", path, delErr)
+			}
+		}
 		return Meta{}, Error.Wrap(err)
 	}
 
@@ -145,6 +167,40 @@ func (s *segmentStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 	return m, nil
 }
 
+// PutReferences puts a segment holding no data of its own, made of the data
+// of the chunk segments at references, in order. The chunks are referenced
+// until the segment is deleted or replaced.
+func (s *segmentStore) PutReferences(ctx context.Context, path paths.Path, references []paths.Path,
+	metadata []byte, expiration time.Time) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	exp, err := ptypes.TimestampProto(expiration)
+	if err != nil {
+		return Meta{}, Error.Wrap(err)
+	}
+
+	p := &ppb.Pointer{
+		Type:           ppb.Pointer_INLINE,
+		ExpirationDate: exp,
+		Metadata:       metadata,
+		References:     make([]string, len(references)),
+	}
+	for i, reference := range references {
+		p.References[i] = reference.String()
+	}
+
+	err = s.pdb.Put(ctx, path, p)
+	if err != nil {
+		return Meta{}, Error.Wrap(err)
+	}
+
+	m, err := s.Meta(ctx, path)
+	if err != nil {
+		return Meta{}, Error.Wrap(err)
+	}
+	return m, nil
+}
+
 // makeRemotePointer creates a pointer of type remote
 func (s *segmentStore) makeRemotePointer(nodes []*opb.Node, pieceID client.PieceID, readerSize int64,
 	exp *timestamp.Timestamp, metadata []byte) (pointer *ppb.Pointer, err error) {
@@ -160,7 +216,7 @@ func (s *segmentStore) makeRemotePointer(nodes []*opb.Node, pieceID client.Piece
 		Type: ppb.Pointer_REMOTE,
 		Remote: &ppb.RemoteSegment{
 			Redundancy: &ppb.RedundancyScheme{
-				Type:             ppb.RedundancyScheme_RS,
+				Type:             s.schemeType,
 				MinReq:           int32(s.rs.RequiredCount()),
 				Total:            int32(s.rs.TotalCount()),
 				RepairThreshold:  int32(s.rs.Min),
@@ -177,8 +233,11 @@ func (s *segmentStore) makeRemotePointer(nodes []*opb.Node, pieceID client.Piece
 	return pointer, nil
 }
 
-// Get retrieves a segment using erasure code, overlay, and pointerdb clients
-func (s *segmentStore) Get(ctx context.Context, path paths.Path) (
+// Get retrieves a segment using erasure code, overlay, and pointerdb clients.
+// report, if not nil, collects the nodes that misbehaved while the remote
+// segment is read. Otherwise they're reported to the NodeReporter of the
+// store after every read.
+func (s *segmentStore) Get(ctx context.Context, path paths.Path, report *NodeReport) (
 	rr ranger.Ranger, meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -188,22 +247,10 @@ func (s *segmentStore) Get(ctx context.Context, path paths.Path) (
 	}
 
 	if pr.GetType() == ppb.Pointer_REMOTE {
-		seg := pr.GetRemote()
-		pid := client.PieceID(seg.PieceId)
-		nodes, err := s.lookupNodes(ctx, seg)
-		if err != nil {
-			return nil, Meta{}, Error.Wrap(err)
-		}
-
-		es, err := makeErasureScheme(pr.GetRemote().GetRedundancy())
+		rr, err = s.remoteRanger(ctx, pr, report)
 		if err != nil {
 			return nil, Meta{}, err
 		}
-
-		rr, err = s.ec.Get(ctx, nodes, es, pid, pr.GetSize())
-		if err != nil {
-			return nil, Meta{}, Error.Wrap(err)
-		}
 	} else {
 		rr = ranger.ByteRanger(pr.InlineSegment)
 	}
@@ -211,13 +258,127 @@ func (s *segmentStore) Get(ctx context.Context, path paths.Path) (
 	return rr, convertMeta(pr), nil
 }
 
-func makeErasureScheme(rs *ppb.RedundancyScheme) (eestream.ErasureScheme, error) {
-	fc, err := infectious.NewFEC(int(rs.GetMinReq()), int(rs.GetTotal()))
+// GetBatch retrieves several segments with a single pointerdb lookup. The
+// remote segments are only requested from the piece stores once they are
+// read.
+func (s *segmentStore) GetBatch(ctx context.Context, paths []paths.Path) (
+	rrs []ranger.Ranger, metas []Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointers, err := s.pdb.BatchGet(ctx, paths)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	for i, pr := range pointers {
+		if pr == nil {
+			return nil, nil, Error.Wrap(storage.ErrKeyNotFound.New(paths[i].String()))
+		}
+		if pr.GetType() == ppb.Pointer_REMOTE {
+			rrs = append(rrs, &lazyRemoteRanger{segments: s, pointer: pr})
+		} else {
+			rrs = append(rrs, ranger.ByteRanger(pr.InlineSegment))
+		}
+		metas = append(metas, convertMeta(pr))
+	}
+
+	return rrs, metas, nil
+}
+
+// remoteRanger returns a ranger of the remote segment of the pointer. The
+// nodes that misbehave while it's read are collected in report if it's not
+// nil, or reported to the NodeReporter of the store.
+func (s *segmentStore) remoteRanger(ctx context.Context, pr *ppb.Pointer, report *NodeReport) (rr ranger.Ranger, err error) {
+	seg := pr.GetRemote()
+	nodes, err := s.lookupNodes(ctx, seg)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
-	es := eestream.NewRSScheme(fc, int(rs.GetErasureShareSize()))
-	return es, nil
+
+	es, err := makeErasureScheme(seg.GetRedundancy())
+	if err != nil {
+		return nil, err
+	}
+
+	if report == nil && s.reporter != nil {
+		return &reportedRanger{segments: s, pointer: pr, nodes: nodes, es: es}, nil
+	}
+	return s.decode(ctx, pr, nodes, es, report)
+}
+
+// decode returns a ranger decoding the pieces of the remote segment of the
+// pointer stored on nodes, collecting the nodes that misbehave in report
+// if it's not nil
+func (s *segmentStore) decode(ctx context.Context, pr *ppb.Pointer, nodes []*opb.Node,
+	es eestream.ErasureScheme, report *NodeReport) (ranger.Ranger, error) {
+	var pieces *eestream.PieceReport
+	if report != nil {
+		report.setPieces(pr.GetRemote().GetRemotePieces())
+		pieces = &report.pieces
+	}
+
+	rr, err := s.ec.Get(ctx, nodes, es, client.PieceID(pr.GetRemote().GetPieceId()), pr.GetSize(), pieces)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return rr, nil
+}
+
+// lazyRemoteRanger looks up the nodes of a remote segment on the first
+// read
+type lazyRemoteRanger struct {
+	ranger   ranger.Ranger
+	segments *segmentStore
+	pointer  *ppb.Pointer
+}
+
+// Size implements Ranger.Size
+func (lr *lazyRemoteRanger) Size() int64 {
+	return lr.pointer.GetSize()
+}
+
+// Range implements Ranger.Range to be lazily connected
+func (lr *lazyRemoteRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	if lr.ranger == nil {
+		rr, err := lr.segments.remoteRanger(ctx, lr.pointer, nil)
+		if err != nil {
+			return nil, err
+		}
+		lr.ranger = rr
+	}
+	return lr.ranger.Range(ctx, offset, length)
+}
+
+func makeErasureScheme(rs *ppb.RedundancyScheme) (eestream.ErasureScheme, error) {
+	return NewErasureScheme(rs.GetType(), int(rs.GetMinReq()), int(rs.GetTotal()),
+		int(rs.GetErasureShareSize()))
+}
+
+// NewErasureScheme returns the ErasureScheme of the scheme type, requiring
+// required of total pieces of shareSize bytes per stripe
+func NewErasureScheme(st ppb.RedundancyScheme_SchemeType, required, total, shareSize int) (
+	eestream.ErasureScheme, error) {
+	switch st {
+	case ppb.RedundancyScheme_RS:
+		fc, err := infectious.NewFEC(required, total)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		return eestream.NewRSScheme(fc, shareSize), nil
+	case ppb.RedundancyScheme_REPLICATION:
+		if required != 1 {
+			return nil, Error.New("replication requires 1 piece, not %d", required)
+		}
+		return eestream.NewReplicationScheme(total, shareSize), nil
+	case ppb.RedundancyScheme_CAUCHY:
+		es, err := eestream.NewCauchyScheme(required, total, shareSize)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		return es, nil
+	default:
+		return nil, Error.New("unknown redundancy scheme type %v", st)
+	}
 }
 
 // Delete tells piece stores to delete a segment and deletes pointer from pointerdb
@@ -230,22 +391,110 @@ func (s *segmentStore) Delete(ctx context.Context, path paths.Path) (err error)
 	}
 
 	if pr.GetType() == ppb.Pointer_REMOTE {
-		seg := pr.GetRemote()
-		pid := client.PieceID(seg.PieceId)
-		nodes, err := s.lookupNodes(ctx, seg)
+		err = s.deleteRemote(ctx, pr.GetRemote())
 		if err != nil {
-			return Error.Wrap(err)
+			return err
+		}
+	}
+
+	// deletes pointer from pointerdb
+	return s.pdb.Delete(ctx, path)
+}
+
+// DeleteBatch tells piece stores to delete several segments and deletes
+// their pointers from pointerdb at once. Missing segments are ignored.
+func (s *segmentStore) DeleteBatch(ctx context.Context, paths []paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointers, err := s.pdb.BatchGet(ctx, paths)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	for _, pr := range pointers {
+		if pr.GetType() == ppb.Pointer_REMOTE {
+			err = s.deleteRemote(ctx, pr.GetRemote())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// deletes the pointers from pointerdb
+	err = s.pdb.BatchDelete(ctx, paths)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
+// DeleteUnreferenced deletes the chunk segments at paths that no segment
+// references anymore. The referenced chunks and the missing ones are left
+// alone. The pointer of a chunk is deleted before its pieces, so a chunk
+// referenced again in the meantime keeps its data.
+func (s *segmentStore) DeleteUnreferenced(ctx context.Context, paths []paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointers, err := s.pdb.BatchGet(ctx, paths)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	seen := make(map[string]bool, len(paths))
+	for i, pr := range pointers {
+		if pr == nil || pr.GetReferenceCount() > 0 || seen[paths[i].String()] {
+			continue
 		}
+		seen[paths[i].String()] = true
 
-		// ecclient sends delete request
-		err = s.ec.Delete(ctx, nodes, pid)
+		err = s.pdb.Delete(ctx, paths[i])
 		if err != nil {
+			if pdbclient.ErrChunkReferenced.Has(err) || storage.ErrKeyNotFound.Has(err) {
+				continue
+			}
 			return Error.Wrap(err)
 		}
+		if pr.GetType() == ppb.Pointer_REMOTE {
+			err = s.deleteRemote(ctx, pr.GetRemote())
+			if err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
 
-	// deletes pointer from pointerdb
-	return s.pdb.Delete(ctx, path)
+// MoveBatch moves the segments at from to the paths at the same index of
+// to, atomically. Only the pointers are moved, the pieces stay on the
+// storage nodes.
+func (s *segmentStore) MoveBatch(ctx context.Context, from, to []paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(from) != len(to) {
+		return Error.New("moving %d segments to %d paths", len(from), len(to))
+	}
+
+	err = s.pdb.BatchMove(ctx, from, to)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
+// deleteRemote tells the piece stores to delete the pieces of seg
+func (s *segmentStore) deleteRemote(ctx context.Context, seg *ppb.RemoteSegment) error {
+	pid := client.PieceID(seg.PieceId)
+	nodes, err := s.lookupNodes(ctx, seg)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	// ecclient sends delete request
+	err = s.ec.Delete(ctx, nodes, pid)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
 }
 
 // lookupNodes calls Lookup to get node addresses from the overlay
@@ -5,6 +5,8 @@ package segments
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"strings"
 	"testing"
 	"time"
@@ -19,9 +21,11 @@ import (
 	"storj.io/storj/pkg/paths"
 	pdb "storj.io/storj/pkg/pointerdb/pdbclient"
 	mock_pointerdb "storj.io/storj/pkg/pointerdb/pdbclient/mocks"
+	"storj.io/storj/pkg/ranger"
 	mock_ecclient "storj.io/storj/pkg/storage/ec/mocks"
 	opb "storj.io/storj/protos/overlay"
 	ppb "storj.io/storj/protos/pointerdb"
+	"storj.io/storj/storage"
 )
 
 var (
@@ -39,7 +43,7 @@ func TestNewSegmentStore(t *testing.T) {
 		ErasureScheme: mock_eestream.NewMockErasureScheme(ctrl),
 	}
 
-	ss := NewSegmentStore(mockOC, mockEC, mockPDB, rs, 10)
+	ss := NewSegmentStore(mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, 10, nil)
 	assert.NotNil(t, ss)
 }
 
@@ -54,7 +58,7 @@ func TestSegmentStoreMeta(t *testing.T) {
 		ErasureScheme: mock_eestream.NewMockErasureScheme(ctrl),
 	}
 
-	ss := segmentStore{mockOC, mockEC, mockPDB, rs, 10}
+	ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, 10, nil}
 	assert.NotNil(t, ss)
 
 	var mExp time.Time
@@ -105,7 +109,7 @@ func TestSegmentStorePutRemote(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, tt.thresholdSize, nil}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
@@ -160,7 +164,7 @@ func TestSegmentStorePutInline(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, tt.thresholdSize, nil}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
@@ -207,7 +211,7 @@ func TestSegmentStoreGetInline(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, tt.thresholdSize, nil}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
@@ -226,7 +230,7 @@ func TestSegmentStoreGetInline(t *testing.T) {
 		}
 		gomock.InOrder(calls...)
 
-		_, _, err := ss.Get(ctx, p)
+		_, _, err := ss.Get(ctx, p, nil)
 		assert.NoError(t, err)
 	}
 }
@@ -256,10 +260,11 @@ func TestSegmentStoreGetRemote(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, tt.thresholdSize, nil}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
+		var report NodeReport
 
 		calls := []*gomock.Call{
 			mockPDB.EXPECT().Get(
@@ -284,16 +289,100 @@ func TestSegmentStoreGetRemote(t *testing.T) {
 			}, nil),
 			mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
 			mockEC.EXPECT().Get(
-				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), &report.pieces,
 			),
 		}
 		gomock.InOrder(calls...)
 
-		_, _, err := ss.Get(ctx, p)
+		_, _, err := ss.Get(ctx, p, &report)
 		assert.NoError(t, err)
 	}
 }
 
+type recordedFailures struct {
+	corrupted, missing []string
+}
+
+type fakeReporter struct {
+	calls []recordedFailures
+}
+
+func (r *fakeReporter) RecordFailures(corrupted, missing []string) {
+	r.calls = append(r.calls, recordedFailures{corrupted, missing})
+}
+
+func TestSegmentStoreGetReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOC := mock_overlay.NewMockClient(ctrl)
+	mockEC := mock_ecclient.NewMockClient(ctrl)
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	rs := eestream.RedundancyStrategy{
+		ErasureScheme: mock_eestream.NewMockErasureScheme(ctrl),
+	}
+	reporter := &fakeReporter{}
+
+	ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, 10, reporter}
+
+	gomock.InOrder(
+		mockPDB.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&ppb.Pointer{
+			Type: ppb.Pointer_REMOTE,
+			Remote: &ppb.RemoteSegment{
+				Redundancy: &ppb.RedundancyScheme{
+					Type:   ppb.RedundancyScheme_RS,
+					MinReq: 1,
+					Total:  2,
+				},
+				PieceId:      "here's my piece id",
+				RemotePieces: []*ppb.RemotePiece{},
+			},
+			Size: 3,
+		}, nil),
+		mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
+	)
+	rr, _, err := ss.Get(ctx, paths.New("s0/path"), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(3), rr.Size())
+
+	// every read collects its own report and sends it when it's closed
+	var reports []*eestream.PieceReport
+	collect := func(_, _, _, _, _ interface{}, report *eestream.PieceReport) {
+		reports = append(reports, report)
+	}
+	mockEC.EXPECT().Get(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Do(collect).Return(ranger.ByteRanger([]byte("abc")), nil).Times(2)
+
+	for i := 1; i <= 2; i++ {
+		rc, err := rr.Range(ctx, 0, 3)
+		if !assert.NoError(t, err) {
+			return
+		}
+		data, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abc"), data)
+		assert.Len(t, reporter.calls, i-1)
+		assert.NoError(t, rc.Close())
+		assert.NoError(t, rc.Close())
+		assert.Len(t, reporter.calls, i)
+	}
+	if assert.Len(t, reports, 2) {
+		assert.NotNil(t, reports[0])
+		assert.True(t, reports[0] != reports[1])
+	}
+
+	// a failed range is reported right away
+	mockEC.EXPECT().Get(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(ranger.ByteRanger([]byte("abc")), nil)
+	_, err = rr.Range(ctx, 2, 5)
+	assert.Error(t, err)
+	assert.Len(t, reporter.calls, 3)
+}
+
 func TestSegmentStoreDeleteInline(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -320,7 +409,7 @@ func TestSegmentStoreDeleteInline(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, tt.thresholdSize, nil}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
@@ -372,7 +461,7 @@ func TestSegmentStoreDeleteRemote(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, tt.thresholdSize, nil}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
@@ -435,7 +524,7 @@ func TestSegmentStoreList(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, tt.thresholdSize, nil}
 		assert.NotNil(t, ss)
 
 		prefix := paths.New(tt.prefixInput)
@@ -470,3 +559,216 @@ func TestSegmentStoreList(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestSegmentStoreGetBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOC := mock_overlay.NewMockClient(ctrl)
+	mockEC := mock_ecclient.NewMockClient(ctrl)
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	rs := eestream.RedundancyStrategy{
+		ErasureScheme: mock_eestream.NewMockErasureScheme(ctrl),
+	}
+
+	ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, 10, nil}
+
+	ps := []paths.Path{paths.New("s0/path"), paths.New("s1/path")}
+	mockPDB.EXPECT().BatchGet(gomock.Any(), ps).Return([]*ppb.Pointer{
+		{
+			Type:          ppb.Pointer_INLINE,
+			InlineSegment: []byte("inline"),
+			Size:          6,
+		},
+		{
+			Type: ppb.Pointer_REMOTE,
+			Remote: &ppb.RemoteSegment{
+				Redundancy: &ppb.RedundancyScheme{
+					Type:   ppb.RedundancyScheme_RS,
+					MinReq: 1,
+					Total:  2,
+				},
+				PieceId:      "here's my piece id",
+				RemotePieces: []*ppb.RemotePiece{},
+			},
+			Size:     3,
+			Metadata: []byte("metadata"),
+		},
+	}, nil)
+
+	// the remote segment isn't looked up before it's read
+	rrs, metas, err := ss.GetBatch(ctx, ps)
+	assert.NoError(t, err)
+	if !assert.Len(t, rrs, 2) {
+		return
+	}
+	assert.Equal(t, int64(6), rrs[0].Size())
+	assert.Equal(t, int64(3), rrs[1].Size())
+	assert.Equal(t, []byte("metadata"), metas[1].Data)
+
+	gomock.InOrder(
+		mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
+		mockEC.EXPECT().Get(
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Return(ranger.ByteRanger([]byte("abc")), nil),
+	)
+	rc, err := rrs[1].Range(ctx, 0, 3)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), data)
+
+	// missing segments fail
+	mockPDB.EXPECT().BatchGet(gomock.Any(), ps).Return([]*ppb.Pointer{nil, nil}, nil)
+	_, _, err = ss.GetBatch(ctx, ps)
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+}
+
+func TestSegmentStoreDeleteBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOC := mock_overlay.NewMockClient(ctrl)
+	mockEC := mock_ecclient.NewMockClient(ctrl)
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	rs := eestream.RedundancyStrategy{
+		ErasureScheme: mock_eestream.NewMockErasureScheme(ctrl),
+	}
+
+	ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, 10, nil}
+
+	ps := []paths.Path{paths.New("s0/path"), paths.New("s1/path"), paths.New("s2/path")}
+	gomock.InOrder(
+		mockPDB.EXPECT().BatchGet(gomock.Any(), ps).Return([]*ppb.Pointer{
+			{Type: ppb.Pointer_INLINE, InlineSegment: []byte("inline")},
+			{
+				Type: ppb.Pointer_REMOTE,
+				Remote: &ppb.RemoteSegment{
+					PieceId:      "here's my piece id",
+					RemotePieces: []*ppb.RemotePiece{},
+				},
+			},
+			nil,
+		}, nil),
+		mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
+		mockEC.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()),
+		mockPDB.EXPECT().BatchDelete(gomock.Any(), ps),
+	)
+
+	err := ss.DeleteBatch(ctx, ps)
+	assert.NoError(t, err)
+}
+
+func TestSegmentStorePutReferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	ss := segmentStore{pdb: mockPDB}
+
+	gomock.InOrder(
+		mockPDB.EXPECT().Put(gomock.Any(), paths.New("l/path"), gomock.Any()).
+			Do(func(ctx context.Context, path paths.Path, pointer *ppb.Pointer) {
+				assert.Equal(t, ppb.Pointer_INLINE, pointer.GetType())
+				assert.Equal(t, []string{"c/a", "c/b", "c/a"}, pointer.GetReferences())
+				assert.Equal(t, []byte("metadata"), pointer.GetMetadata())
+			}),
+		mockPDB.EXPECT().Get(gomock.Any(), paths.New("l/path")).Return(&ppb.Pointer{}, nil),
+	)
+
+	refs := []paths.Path{paths.New("c/a"), paths.New("c/b"), paths.New("c/a")}
+	_, err := ss.PutReferences(ctx, paths.New("l/path"), refs, []byte("metadata"), time.Time{})
+	assert.NoError(t, err)
+}
+
+func TestSegmentStoreDeleteUnreferenced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOC := mock_overlay.NewMockClient(ctrl)
+	mockEC := mock_ecclient.NewMockClient(ctrl)
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB}
+
+	ps := []paths.Path{paths.New("c/a"), paths.New("c/b"), paths.New("c/c"), paths.New("c/d"), paths.New("c/a")}
+	remote := &ppb.Pointer{
+		Type: ppb.Pointer_REMOTE,
+		Remote: &ppb.RemoteSegment{
+			PieceId:      "here's my piece id",
+			RemotePieces: []*ppb.RemotePiece{},
+		},
+	}
+	gomock.InOrder(
+		mockPDB.EXPECT().BatchGet(gomock.Any(), ps).Return([]*ppb.Pointer{
+			remote,
+			{Type: ppb.Pointer_INLINE, ReferenceCount: 1},
+			nil,
+			{Type: ppb.Pointer_INLINE},
+			remote,
+		}, nil),
+		// the pointer is deleted before the pieces
+		mockPDB.EXPECT().Delete(gomock.Any(), paths.New("c/a")),
+		mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
+		mockEC.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()),
+		// referenced again since it was looked up
+		mockPDB.EXPECT().Delete(gomock.Any(), paths.New("c/d")).
+			Return(pdb.ErrChunkReferenced.New("c/d")),
+	)
+
+	err := ss.DeleteUnreferenced(ctx, ps)
+	assert.NoError(t, err)
+}
+
+func TestSegmentStoreMoveBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOC := mock_overlay.NewMockClient(ctrl)
+	mockEC := mock_ecclient.NewMockClient(ctrl)
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	rs := eestream.RedundancyStrategy{
+		ErasureScheme: mock_eestream.NewMockErasureScheme(ctrl),
+	}
+
+	ss := segmentStore{mockOC, mockEC, mockPDB, rs, ppb.RedundancyScheme_RS, 10, nil}
+
+	from := []paths.Path{paths.New("s0/from"), paths.New("l/from")}
+	to := []paths.Path{paths.New("s0/to"), paths.New("l/to")}
+	mockPDB.EXPECT().BatchMove(gomock.Any(), from, to)
+	assert.NoError(t, ss.MoveBatch(ctx, from, to))
+
+	// nothing is moved when a segment is missing
+	mockPDB.EXPECT().BatchMove(gomock.Any(), from, to).
+		Return(storage.ErrKeyNotFound.New(from[1].String()))
+	err := ss.MoveBatch(ctx, from, to)
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	assert.Error(t, ss.MoveBatch(ctx, from, to[:1]))
+}
+
+func TestNewErasureScheme(t *testing.T) {
+	for i, tt := range []struct {
+		schemeType ppb.RedundancyScheme_SchemeType
+		required   int
+		total      int
+		errString  string
+	}{
+		{ppb.RedundancyScheme_RS, 2, 4, ""},
+		{ppb.RedundancyScheme_REPLICATION, 1, 3, ""},
+		{ppb.RedundancyScheme_REPLICATION, 2, 3, "segment error: replication requires 1 piece, not 2"},
+		{ppb.RedundancyScheme_CAUCHY, 2, 4, ""},
+		{ppb.RedundancyScheme_SchemeType(42), 2, 4, "segment error: unknown redundancy scheme type 42"},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		es, err := NewErasureScheme(tt.schemeType, tt.required, tt.total, 1024)
+		if tt.errString != "" {
+			assert.EqualError(t, err, tt.errString, errTag)
+			continue
+		}
+		if assert.NoError(t, err, errTag) {
+			assert.Equal(t, tt.required, es.RequiredCount(), errTag)
+			assert.Equal(t, tt.total, es.TotalCount(), errTag)
+			assert.Equal(t, 1024, es.EncodedBlockSize(), errTag)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"io"
+)
+
+// the bounds and the average of the sizes of the chunks of the
+// deduplicated streams
+const (
+	minChunkSize = 512 * 1024
+	avgChunkSize = 2 * 1024 * 1024
+	maxChunkSize = 8 * 1024 * 1024
+)
+
+// gear maps every byte to a random value for the rolling hash of the
+// chunker. It is generated from a fixed seed and must never change, or the
+// boundaries of the chunks, hence their IDs, would change with it.
+var gear = func() (table [256]uint64) {
+	// splitmix64
+	state := uint64(0x53544f524a434443) // "STORJCDC"
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// chunker splits a stream in content-defined chunks. A chunk ends where the
+// gear hash of its last bytes has its top bits unset, so the boundaries
+// follow the content: inserting or removing data only changes the chunks
+// around the change, and the other ones are deduplicated.
+type chunker struct {
+	r        io.Reader
+	min, max int
+	mask     uint64
+	buf      []byte
+	err      error
+}
+
+// newChunker returns a chunker splitting r in chunks of min to max bytes,
+// of about min+avg bytes on average. avg must be a power of 2.
+func newChunker(r io.Reader, min, avg, max int) *chunker {
+	bits := uint(0)
+	for 1<<bits < avg {
+		bits++
+	}
+	return &chunker{
+		r:    r,
+		min:  min,
+		max:  max,
+		mask: ^uint64(0) << (64 - bits),
+		buf:  make([]byte, 0, max),
+	}
+}
+
+// Next returns the next chunk, or io.EOF after the last one
+func (c *chunker) Next() ([]byte, error) {
+	for len(c.buf) < c.max && c.err == nil {
+		var n int
+		n, c.err = c.r.Read(c.buf[len(c.buf):c.max])
+		c.buf = c.buf[:len(c.buf)+n]
+	}
+	if c.err != nil && c.err != io.EOF {
+		return nil, c.err
+	}
+	if len(c.buf) == 0 {
+		return nil, io.EOF
+	}
+
+	n := c.boundary(c.buf)
+	chunk := make([]byte, n)
+	copy(chunk, c.buf[:n])
+	c.buf = c.buf[:copy(c.buf, c.buf[n:])]
+	return chunk, nil
+}
+
+// boundary returns the size of the chunk at the start of data
+func (c *chunker) boundary(data []byte) int {
+	if len(data) <= c.min {
+		return len(data)
+	}
+	var hash uint64
+	for i := c.min; i < len(data); i++ {
+		hash = hash<<1 + gear[data[i]]
+		if hash&c.mask == 0 {
+			return i + 1
+		}
+	}
+	return len(data)
+}
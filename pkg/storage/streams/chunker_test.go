@@ -0,0 +1,104 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/paths"
+	streamspb "storj.io/storj/protos/streams"
+)
+
+// splitChunks splits data with a chunker of small sizes
+func splitChunks(t *testing.T, r io.Reader) [][]byte {
+	c := newChunker(r, 1024, 4096, 16*1024)
+	var chunks [][]byte
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			return chunks
+		}
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	data := make([]byte, 1024*1024)
+	_, _ = rand.New(rand.NewSource(1)).Read(data)
+
+	split := splitChunks(t, bytes.NewReader(data))
+	assert.Equal(t, data, bytes.Join(split, nil))
+	for i, chunk := range split {
+		assert.True(t, len(chunk) <= 16*1024)
+		if i < len(split)-1 {
+			assert.True(t, len(chunk) >= 1024)
+		}
+	}
+	// the boundaries only depend on the data, not on the reads
+	assert.Equal(t, split, splitChunks(t, iotest.OneByteReader(bytes.NewReader(data))))
+
+	// constant data has no boundaries, it is split at the maximum size
+	for _, chunk := range splitChunks(t, bytes.NewReader(make([]byte, 100*1024))) {
+		assert.True(t, len(chunk) == 16*1024 || len(chunk) == 100*1024%(16*1024))
+	}
+
+	assert.Empty(t, splitChunks(t, bytes.NewReader(nil)))
+}
+
+func TestChunkerInsertion(t *testing.T) {
+	data := make([]byte, 1024*1024)
+	_, _ = rand.New(rand.NewSource(2)).Read(data)
+	inserted := append(append(append([]byte{}, data[:500*1024]...),
+		[]byte("inserted")...), data[500*1024:]...)
+
+	before := make(map[string]bool)
+	for _, chunk := range splitChunks(t, bytes.NewReader(data)) {
+		before[string(chunk)] = true
+	}
+	after := splitChunks(t, bytes.NewReader(inserted))
+	changed := 0
+	for _, chunk := range after {
+		if !before[string(chunk)] {
+			changed++
+		}
+	}
+	// only the chunks around the insertion change
+	assert.True(t, changed <= 2, "%d of %d chunks changed", changed, len(after))
+}
+
+func TestChunkKeys(t *testing.T) {
+	key := DedupKey("secret")
+	data := []byte("some data")
+
+	id := chunkID(key, data)
+	assert.Equal(t, id, chunkID(DedupKey("secret"), data))
+	assert.NotEqual(t, id, chunkID(DedupKey("other"), data))
+	assert.NotEqual(t, id, chunkID(key, []byte("other data")))
+
+	assert.Equal(t, chunkKey(key, id), chunkKey(DedupKey("secret"), id))
+	assert.NotEqual(t, chunkKey(key, id), chunkKey(DedupKey("other"), id))
+	assert.NotEqual(t, *key, *RootKey("secret"))
+}
+
+func TestChunkPaths(t *testing.T) {
+	bucket, err := streamBucket(paths.New("bucket", "a", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bucket", bucket)
+	_, err = streamBucket(paths.New("bucket"))
+	assert.Error(t, err)
+
+	// the chunks are in the keyspace of their bucket
+	chunks := []*streamspb.Chunk{{Id: "x"}, {Id: "y"}}
+	assert.Equal(t, []paths.Path{paths.New("c", "bucket", "x"), paths.New("c", "bucket", "y")},
+		chunkPaths(bucket, chunks))
+}
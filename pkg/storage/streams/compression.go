@@ -0,0 +1,211 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/DataDog/zstd"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/internal/pkg/readcloser"
+	ranger "storj.io/storj/pkg/ranger"
+)
+
+// compressionFrameSize is the size of the data compressed in every frame.
+// The frames are decompressed independently, so a range read only
+// decompresses the frames covering it. The size is recorded with every
+// stream, so it can change without breaking the streams already stored.
+const compressionFrameSize = 256 * 1024
+
+// compressedReader compresses the data read from r in independent zstd
+// frames of frameSize bytes of data, the last one being shorter
+type compressedReader struct {
+	r          io.Reader
+	frameSize  int
+	inbuf      []byte
+	outbuf     []byte
+	out        []byte  // unread part of outbuf
+	frameSizes []int32 // sizes of the compressed frames read so far
+	err        error
+}
+
+// compressSegment returns a reader of the data of a segment compressed in
+// frames. The compressed sizes of the frames are available from the reader
+// once it's read to the end.
+func compressSegment(data io.Reader, frameSize int) *compressedReader {
+	return &compressedReader{
+		r:         data,
+		frameSize: frameSize,
+		inbuf:     make([]byte, frameSize),
+		outbuf:    make([]byte, 0, zstd.CompressBound(frameSize)),
+	}
+}
+
+func (c *compressedReader) Read(p []byte) (n int, err error) {
+	if len(c.out) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		// compress the next frame
+		n, err := io.ReadFull(c.r, c.inbuf)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// the last frame, if any
+			c.err = io.EOF
+		} else if err != nil {
+			c.err = err
+			return 0, c.err
+		}
+		if n == 0 {
+			return 0, c.err
+		}
+		c.out, err = zstd.Compress(c.outbuf, c.inbuf[:n])
+		if err != nil {
+			c.err = errs.New("failed to compress: %v", err)
+			return 0, c.err
+		}
+		c.frameSizes = append(c.frameSizes, int32(len(c.out)))
+	}
+
+	n = copy(p, c.out)
+	c.out = c.out[n:]
+	return n, nil
+}
+
+// FrameSizes returns the sizes of the compressed frames read so far
+func (c *compressedReader) FrameSizes() []int32 {
+	return c.frameSizes
+}
+
+// frameCount returns the number of compression frames of size bytes of data
+func frameCount(size int64, frameSize int) int64 {
+	return (size + int64(frameSize) - 1) / int64(frameSize)
+}
+
+type decompressedRanger struct {
+	rr        ranger.Ranger
+	frameSize int
+	offsets   []int64 // offsets of the frames in rr, and the end of the last
+	size      int64
+}
+
+// decompressSegment returns a ranger of the size bytes of data compressed
+// in rr in frames of frameSize bytes of data. frameSizes are the compressed
+// sizes of the frames.
+func decompressSegment(rr ranger.Ranger, frameSize int, frameSizes []int32, size int64) (ranger.Ranger, error) {
+	if int64(len(frameSizes)) != frameCount(size, frameSize) {
+		return nil, errs.New("%d compression frames for %d bytes of data",
+			len(frameSizes), size)
+	}
+	offsets := make([]int64, len(frameSizes)+1)
+	for i, frameSize := range frameSizes {
+		offsets[i+1] = offsets[i] + int64(frameSize)
+	}
+	if offsets[len(frameSizes)] > rr.Size() {
+		return nil, errs.New("compression frames larger than the segment")
+	}
+	return &decompressedRanger{
+		rr:        rr,
+		frameSize: frameSize,
+		offsets:   offsets,
+		size:      size,
+	}, nil
+}
+
+func (d *decompressedRanger) Size() int64 {
+	return d.size
+}
+
+func (d *decompressedRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, errs.New("negative offset")
+	}
+	if length < 0 {
+		return nil, errs.New("negative length")
+	}
+	if offset+length > d.size {
+		return nil, errs.New("range beyond end")
+	}
+	if length == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+	// only read the frames covering the range
+	first := offset / int64(d.frameSize)
+	last := (offset + length - 1) / int64(d.frameSize)
+	r, err := d.rr.Range(ctx, d.offsets[first], d.offsets[last+1]-d.offsets[first])
+	if err != nil {
+		return nil, err
+	}
+	fr := &frameReader{
+		r:      r,
+		d:      d,
+		frame:  first,
+		end:    last + 1,
+		inbuf:  make([]byte, 0, zstd.CompressBound(d.frameSize)),
+		outbuf: make([]byte, d.frameSize),
+	}
+	// the range may start in the middle of the first frame
+	_, err = io.CopyN(ioutil.Discard, fr, offset-first*int64(d.frameSize))
+	if err != nil {
+		_ = fr.Close()
+		return nil, err
+	}
+	return readcloser.LimitReadCloser(fr, length), nil
+}
+
+// frameReader decompresses the frames read from r, from frame until end
+type frameReader struct {
+	r      io.ReadCloser
+	d      *decompressedRanger
+	frame  int64
+	end    int64
+	inbuf  []byte
+	outbuf []byte
+	out    []byte // unread part of outbuf
+}
+
+func (f *frameReader) Read(p []byte) (n int, err error) {
+	if len(f.out) == 0 {
+		if f.frame >= f.end {
+			return 0, io.EOF
+		}
+		size := f.d.offsets[f.frame+1] - f.d.offsets[f.frame]
+		if int64(cap(f.inbuf)) < size {
+			f.inbuf = make([]byte, size)
+		}
+		f.inbuf = f.inbuf[:size]
+		_, err := io.ReadFull(f.r, f.inbuf)
+		if err != nil {
+			if err == io.EOF {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		// the last frame of the data may be shorter
+		expected := f.d.size - f.frame*int64(f.d.frameSize)
+		if expected > int64(f.d.frameSize) {
+			expected = int64(f.d.frameSize)
+		}
+		f.out, err = zstd.Decompress(f.outbuf, f.inbuf)
+		if err != nil {
+			return 0, errs.New("failed to decompress frame %d: %v", f.frame, err)
+		}
+		if int64(len(f.out)) != expected {
+			return 0, errs.New("frame %d decompressed to %d bytes instead of %d",
+				f.frame, len(f.out), expected)
+		}
+		f.frame++
+	}
+
+	n = copy(p, f.out)
+	f.out = f.out[n:]
+	return n, nil
+}
+
+func (f *frameReader) Close() error {
+	return f.r.Close()
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ranger "storj.io/storj/pkg/ranger"
+)
+
+func TestCompressedSegmentRange(t *testing.T) {
+	ctx := context.Background()
+	frameSize := 1000
+
+	for _, size := range []int64{0, 1, 999, 1000, 1001, 12345} {
+		// half random, half zeros, to both compress and not
+		data := make([]byte, size)
+		_, err := rand.Read(data[:size/2])
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		c := compressSegment(bytes.NewReader(data), frameSize)
+		compressed, err := ioutil.ReadAll(c)
+		if !assert.NoError(t, err) {
+			return
+		}
+		frameSizes := c.FrameSizes()
+		assert.Equal(t, frameCount(size, frameSize), int64(len(frameSizes)))
+
+		// the frame index must match the data
+		_, err = decompressSegment(ranger.ByteRanger(compressed), frameSize, frameSizes, size+int64(frameSize))
+		assert.Error(t, err)
+
+		for _, rng := range []struct{ offset, length int64 }{
+			{0, size},
+			{0, size / 2},
+			{size / 2, size - size/2},
+			{size / 3, size / 3},
+			{size - 1, 1},
+			{size / 2, 0},
+		} {
+			if rng.offset < 0 || rng.length < 0 {
+				continue
+			}
+			tag := fmt.Sprintf("size %d, offset %d, length %d", size, rng.offset, rng.length)
+
+			counting := &countingRanger{Ranger: ranger.ByteRanger(compressed)}
+			rr, err := decompressSegment(counting, frameSize, frameSizes, size)
+			if !assert.NoError(t, err, tag) {
+				return
+			}
+			assert.Equal(t, size, rr.Size(), tag)
+
+			got, err := readRange(ctx, rr, rng.offset, rng.length)
+			if !assert.NoError(t, err, tag) {
+				return
+			}
+			assert.Equal(t, data[rng.offset:rng.offset+rng.length], got, tag)
+
+			// only the frames covering the range are read
+			requested := int64(0)
+			if rng.length > 0 {
+				first := rng.offset / int64(frameSize)
+				last := (rng.offset + rng.length - 1) / int64(frameSize)
+				for _, frame := range frameSizes[first : last+1] {
+					requested += int64(frame)
+				}
+			}
+			assert.Equal(t, requested, counting.requested, tag)
+		}
+	}
+}
+
+func TestCorruptedCompressedSegment(t *testing.T) {
+	data := bytes.Repeat([]byte("storj"), 1000)
+	c := compressSegment(bytes.NewReader(data), 1024)
+	compressed, err := ioutil.ReadAll(c)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// break the magic number of the frame
+	compressed[0] ^= 0xff
+
+	rr, err := decompressSegment(ranger.ByteRanger(compressed), 1024, c.FrameSizes(), int64(len(data)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = readRange(context.Background(), rr, 0, rr.Size())
+	assert.Error(t, err)
+}
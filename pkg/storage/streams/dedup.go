@@ -0,0 +1,259 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+
+	proto "github.com/gogo/protobuf/proto"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
+	ranger "storj.io/storj/pkg/ranger"
+	streamspb "storj.io/storj/protos/streams"
+	"storj.io/storj/storage"
+)
+
+// chunkPrefix is the first element of the paths of the chunks, which are
+// shared by the deduplicated streams of their bucket
+const chunkPrefix = "c"
+
+// DedupKey derives the key identifying and encrypting the chunks of the
+// deduplicated streams from the configured secret. The same data is only
+// deduplicated between the streams put with the same secret.
+func DedupKey(secret string) *[32]byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("storj-dedup"))
+	var key [32]byte
+	copy(key[:], mac.Sum(nil))
+	return &key
+}
+
+// chunkID returns the ID of the chunk holding data, a keyed hash of it so
+// that it doesn't disclose the data to whoever doesn't know the key
+func chunkID(dedupKey *[32]byte, data []byte) string {
+	mac := hmac.New(sha256.New, dedupKey[:])
+	_, _ = mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// chunkKey returns the key encrypting the chunk with the given ID. It only
+// depends on the data, so that the same data is always encrypted the same
+// way and can be deduplicated.
+func chunkKey(dedupKey *[32]byte, id string) *[32]byte {
+	mac := hmac.New(sha256.New, dedupKey[:])
+	_, _ = mac.Write([]byte("key:" + id))
+	var key [32]byte
+	copy(key[:], mac.Sum(nil))
+	return &key
+}
+
+// chunkPath returns the path of the chunk of the bucket with the given ID
+func chunkPath(bucket, id string) paths.Path {
+	return paths.New(chunkPrefix, bucket, id)
+}
+
+// chunkPaths returns the paths of the chunks of a stream of the bucket
+func chunkPaths(bucket string, chunks []*streamspb.Chunk) []paths.Path {
+	chunkPaths := make([]paths.Path, len(chunks))
+	for i, chunk := range chunks {
+		chunkPaths[i] = chunkPath(bucket, chunk.GetId())
+	}
+	return chunkPaths
+}
+
+// streamBucket returns the bucket of the stream at path, its first element
+func streamBucket(path paths.Path) (string, error) {
+	if len(path) < 2 {
+		return "", errs.New("invalid stream path %s", path)
+	}
+	return path[0], nil
+}
+
+// putDedup splits data in content-defined chunks, puts the chunks not
+// stored yet at c/<bucket>/<id>, and puts l/<path> referencing all of
+// them. The chunks of the stream replaced, if any, are deleted if no other
+// stream references them anymore.
+func (s *streamStore) putDedup(ctx context.Context, path paths.Path, data io.Reader,
+	metadata []byte, expiration time.Time,
+	compression streamspb.CompressionType) (m Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucket, err := streamBucket(path)
+	if err != nil {
+		return Meta{}, err
+	}
+	lastSegmentPath := path.Prepend("l")
+	ctx = pdbclient.WithReferrer(ctx, lastSegmentPath)
+
+	var replaced []*streamspb.Chunk
+	lastSegmentMeta, err := s.segments.Meta(ctx, lastSegmentPath)
+	if err == nil {
+		msi := streamspb.MetaStreamInfo{}
+		if err = proto.Unmarshal(lastSegmentMeta.Data, &msi); err != nil {
+			return Meta{}, err
+		}
+		replaced = msi.Chunks
+	} else if !storage.ErrKeyNotFound.Has(err) {
+		return Meta{}, err
+	}
+
+	var chunks []*streamspb.Chunk
+	var totalSize int64
+	put := make(map[string]bool)
+	splitter := newChunker(data, minChunkSize, avgChunkSize, maxChunkSize)
+	for {
+		chunk, err := splitter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Meta{}, err
+		}
+		id := chunkID(s.dedupKey, chunk)
+		if !put[id] {
+			if err = s.putChunk(ctx, bucket, id, chunk, compression); err != nil {
+				return Meta{}, err
+			}
+			put[id] = true
+		}
+		chunks = append(chunks, &streamspb.Chunk{Id: id, Size: int64(len(chunk))})
+		totalSize += int64(len(chunk))
+	}
+
+	md := streamspb.MetaStreamInfo{
+		Metadata: metadata,
+		Chunks:   chunks,
+	}
+	lastSegmentMetadata, err := proto.Marshal(&md)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	// a chunk deleted meanwhile, as an orphan or with the last stream
+	// referencing it, fails the put with pdbclient.ErrMissingChunk. The
+	// data of the chunks isn't kept, so the stream has to be put again.
+	putMeta, err := s.segments.PutReferences(ctx, lastSegmentPath, chunkPaths(bucket, chunks),
+		lastSegmentMetadata, expiration)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	if len(replaced) > 0 {
+		err = s.segments.DeleteUnreferenced(ctx, chunkPaths(bucket, replaced))
+		if err != nil {
+			// the stream is put, the chunks are only left behind
+			zap.S().Warnf("failed to delete the replaced chunks of %s: %v", path, err)
+		}
+	}
+
+	return Meta{
+		Modified:   putMeta.Modified,
+		Expiration: expiration,
+		Size:       totalSize,
+		Data:       metadata,
+	}, nil
+}
+
+// putChunk puts the chunk of the bucket with the given ID and data at
+// c/<bucket>/<id>, unless it is already stored. Chunks never expire, they
+// are deleted with the last stream referencing them.
+func (s *streamStore) putChunk(ctx context.Context, bucket, id string, data []byte,
+	compression streamspb.CompressionType) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path := chunkPath(bucket, id)
+	_, err = s.segments.Meta(ctx, path)
+	if err == nil {
+		return nil
+	}
+	if !storage.ErrKeyNotFound.Has(err) {
+		return err
+	}
+
+	var chunkData io.Reader = bytes.NewReader(data)
+	var compressed *compressedReader
+	if compression == streamspb.CompressionType_ZSTD {
+		compressed = compressSegment(chunkData, compressionFrameSize)
+		chunkData = compressed
+	}
+	chunkData, err = encryptSegment(chunkData, chunkKey(s.dedupKey, id), 0, s.encBlockSize)
+	if err != nil {
+		return err
+	}
+
+	// the sizes of the compressed frames are only known once the data is
+	// read, so a compressed chunk is read before its metadata is marshaled
+	md := streamspb.MetaStreamInfo{
+		NumberOfSegments:    1,
+		SegmentsSize:        int64(len(data)),
+		LastSegmentSize:     int64(len(data)),
+		EncryptionBlockSize: int32(s.encBlockSize),
+	}
+	if compressed != nil {
+		var buf bytes.Buffer
+		if _, err = io.Copy(&buf, chunkData); err != nil {
+			return err
+		}
+		chunkData = &buf
+		md.Compression = compression
+		md.CompressionFrameSize = compressionFrameSize
+		md.CompressedFrameSizes = compressed.FrameSizes()
+	}
+	metadata, err := proto.Marshal(&md)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.segments.Put(ctx, path, chunkData, metadata, time.Time{})
+	if pdbclient.ErrChunkExists.Has(err) {
+		// put meanwhile by another stream
+		return nil
+	}
+	return err
+}
+
+// chunkRangers returns the rangers of the data of the chunks of the
+// deduplicated stream at path
+func (s *streamStore) chunkRangers(ctx context.Context, path paths.Path,
+	chunks []*streamspb.Chunk) (_ []ranger.Ranger, err error) {
+	if s.dedupKey == nil {
+		return nil, errs.New("stream is deduplicated, but no deduplication secret is configured")
+	}
+	bucket, err := streamBucket(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = pdbclient.WithReferrer(ctx, path.Prepend("l"))
+	rangers, metas, err := s.segments.GetBatch(ctx, chunkPaths(bucket, chunks))
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]ranger.Ranger, len(rangers))
+	for i, chunk := range chunks {
+		msi := streamspb.MetaStreamInfo{}
+		if err = proto.Unmarshal(metas[i].Data, &msi); err != nil {
+			return nil, err
+		}
+		if msi.NumberOfSegments != 1 || msi.LastSegmentSize != chunk.GetSize() {
+			return nil, errs.New("invalid chunk %s", chunk.GetId())
+		}
+		chunkData, err := dataRangers(rangers[i:i+1], &msi, chunkKey(s.dedupKey, chunk.GetId()))
+		if err != nil {
+			return nil, err
+		}
+		data[i] = chunkData[0]
+	}
+	return data, nil
+}
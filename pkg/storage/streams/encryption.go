@@ -0,0 +1,118 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/eestream"
+	ranger "storj.io/storj/pkg/ranger"
+)
+
+// RootKey derives the key encrypting the keys of the streams from the
+// configured secret
+func RootKey(secret string) *[32]byte {
+	key := sha256.Sum256([]byte(secret))
+	return &key
+}
+
+// newStreamKey returns a random key to encrypt the data of a stream. Every
+// stream has its own key, so the nonces of the segments never repeat under
+// the same key.
+func newStreamKey() (*[32]byte, error) {
+	var key [32]byte
+	_, err := io.ReadFull(rand.Reader, key[:])
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// encryptStreamKey encrypts the stream key with the root key, under a
+// random nonce
+func encryptStreamKey(key, rootKey *[32]byte) (encrypted, nonce []byte, err error) {
+	aead, err := newAEAD(rootKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead.Seal(nil, nonce, key[:], nil), nonce, nil
+}
+
+// decryptStreamKey decrypts a stream key encrypted by encryptStreamKey
+func decryptStreamKey(encrypted, nonce []byte, rootKey *[32]byte) (*[32]byte, error) {
+	aead, err := newAEAD(rootKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errs.New("invalid stream key nonce")
+	}
+	plain, err := aead.Open(nil, nonce, encrypted, nil)
+	if err != nil {
+		return nil, errs.New("failed to decrypt the stream key: %v", err)
+	}
+	var key [32]byte
+	if copy(key[:], plain) != len(key) {
+		return nil, errs.New("invalid stream key")
+	}
+	return &key, nil
+}
+
+func newAEAD(key *[32]byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// segmentNonce returns the starting nonce of the encryption blocks of the
+// segment. The segment index takes the most significant bytes, leaving the
+// least significant ones to the block numbers within the segment, so any
+// block can be decrypted knowing only its segment and position.
+func segmentNonce(segment int64) *[12]byte {
+	var nonce [12]byte
+	binary.BigEndian.PutUint64(nonce[:8], uint64(segment))
+	return &nonce
+}
+
+// encryptSegment returns a reader of the data of the segment encrypted with
+// the stream key. The data is padded to a whole number of blocks.
+func encryptSegment(data io.Reader, key *[32]byte, segment int64, blockSize int) (io.Reader, error) {
+	encrypter, err := eestream.NewAESGCMEncrypter(key, segmentNonce(segment), blockSize)
+	if err != nil {
+		return nil, err
+	}
+	padded := eestream.PadReader(ioutil.NopCloser(data), encrypter.InBlockSize())
+	return eestream.TransformReader(padded, encrypter, 0), nil
+}
+
+// decryptSegment returns a ranger of the size bytes of data of the segment
+// encrypted in rr. A range of it only reads and decrypts the encryption
+// blocks covering the range.
+func decryptSegment(rr ranger.Ranger, key *[32]byte, segment int64, blockSize int, size int64) (ranger.Ranger, error) {
+	decrypter, err := eestream.NewAESGCMDecrypter(key, segmentNonce(segment), blockSize)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := eestream.Transform(rr, decrypter)
+	if err != nil {
+		return nil, err
+	}
+	// cut the padding off
+	return ranger.Subrange(decrypted, 0, size)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ranger "storj.io/storj/pkg/ranger"
+)
+
+func TestStreamKey(t *testing.T) {
+	key, err := newStreamKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	encrypted, nonce, err := encryptStreamKey(key, RootKey("secret"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, key[:], encrypted)
+
+	decrypted, err := decryptStreamKey(encrypted, nonce, RootKey("secret"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, key, decrypted)
+	}
+
+	_, err = decryptStreamKey(encrypted, nonce, RootKey("other secret"))
+	assert.Error(t, err)
+}
+
+func TestSegmentNonce(t *testing.T) {
+	assert.NotEqual(t, segmentNonce(0), segmentNonce(1))
+	// the block numbers of a segment don't reach the nonces of the next one
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0}, segmentNonce(1)[:])
+}
+
+// countingRanger counts the bytes requested from a ranger
+type countingRanger struct {
+	ranger.Ranger
+	requested int64
+}
+
+func (r *countingRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	r.requested += length
+	return r.Ranger.Range(ctx, offset, length)
+}
+
+func TestEncryptedSegmentRange(t *testing.T) {
+	ctx := context.Background()
+	key, err := newStreamKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+	blockSize := 1024
+
+	for _, size := range []int64{0, 1, 1000, 1024, 64 * 1024, 100000} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		r, err := encryptSegment(bytes.NewReader(data), key, 3, blockSize)
+		if !assert.NoError(t, err) {
+			return
+		}
+		encrypted, err := ioutil.ReadAll(r)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 0, len(encrypted)%blockSize)
+
+		// decrypting with the nonce of another segment fails
+		rr, err := decryptSegment(ranger.ByteRanger(encrypted), key, 4, blockSize, size)
+		if assert.NoError(t, err) && size > 0 {
+			_, err = readRange(ctx, rr, 0, size)
+			assert.Error(t, err)
+		}
+
+		for _, rng := range []struct{ offset, length int64 }{
+			{0, size},
+			{0, size / 2},
+			{size / 2, size - size/2},
+			{size / 3, size / 3},
+			{size - 1, 1},
+			{size / 2, 0},
+		} {
+			if rng.offset < 0 || rng.length < 0 {
+				continue
+			}
+			tag := fmt.Sprintf("size %d, offset %d, length %d", size, rng.offset, rng.length)
+
+			counting := &countingRanger{Ranger: ranger.ByteRanger(encrypted)}
+			rr, err := decryptSegment(counting, key, 3, blockSize, size)
+			if !assert.NoError(t, err, tag) {
+				return
+			}
+			assert.Equal(t, size, rr.Size(), tag)
+
+			got, err := readRange(ctx, rr, rng.offset, rng.length)
+			if !assert.NoError(t, err, tag) {
+				return
+			}
+			assert.Equal(t, data[rng.offset:rng.offset+rng.length], got, tag)
+
+			// only the encryption blocks covering the range are read
+			blocks := int64(0)
+			if rng.length > 0 {
+				first := rng.offset / int64(blockSize-16)
+				last := (rng.offset + rng.length - 1) / int64(blockSize-16)
+				blocks = last - first + 1
+			}
+			assert.Equal(t, blocks*int64(blockSize), counting.requested, tag)
+		}
+	}
+}
+
+func readRange(ctx context.Context, rr ranger.Ranger, offset, length int64) ([]byte, error) {
+	r, err := rr.Range(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return ioutil.ReadAll(r)
+}
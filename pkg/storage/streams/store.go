@@ -11,9 +11,11 @@ import (
 
 	proto "github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
 	ranger "storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/pkg/storage/segments"
@@ -38,10 +40,19 @@ func convertMeta(segmentMeta segments.Meta) (Meta, error) {
 		return Meta{}, err
 	}
 
+	size := ((msi.NumberOfSegments - 1) * msi.SegmentsSize) + msi.LastSegmentSize
+	if msi.NumberOfSegments == 0 {
+		// a deduplicated stream, made of chunks
+		size = 0
+		for _, chunk := range msi.Chunks {
+			size += chunk.GetSize()
+		}
+	}
+
 	return Meta{
 		Modified:   segmentMeta.Modified,
 		Expiration: segmentMeta.Expiration,
-		Size:       ((msi.NumberOfSegments - 1) * msi.SegmentsSize) + msi.LastSegmentSize,
+		Size:       size,
 		Data:       msi.Metadata,
 	}, nil
 }
@@ -51,8 +62,11 @@ type Store interface {
 	Meta(ctx context.Context, path paths.Path) (Meta, error)
 	Get(ctx context.Context, path paths.Path) (ranger.Ranger, Meta, error)
 	Put(ctx context.Context, path paths.Path, data io.Reader,
-		metadata []byte, expiration time.Time) (Meta, error)
+		metadata []byte, expiration time.Time,
+		compression streamspb.CompressionType) (Meta, error)
 	Delete(ctx context.Context, path paths.Path) error
+	Move(ctx context.Context, from, to paths.Path) error
+	MoveBatch(ctx context.Context, from, to []paths.Path) error
 	List(ctx context.Context, prefix, startAfter, endBefore paths.Path,
 		recursive bool, limit int, metaFlags uint32) (items []ListItem,
 		more bool, err error)
@@ -60,42 +74,105 @@ type Store interface {
 
 // streamStore is a store for streams
 type streamStore struct {
-	segments    segments.Store
-	segmentSize int64
+	segments     segments.Store
+	segmentSize  int64
+	rootKey      *[32]byte
+	dedupKey     *[32]byte
+	encBlockSize int
 }
 
 // NewStreamStore stuff
-func NewStreamStore(segments segments.Store, segmentSize int64) (Store, error) {
+//
+// rootKey, if not nil, encrypts the keys of the new streams, which encrypt
+// their data in blocks of encBlockSize bytes. The encrypted streams can only
+// be read with the same rootKey.
+//
+// dedupKey, if not nil, deduplicates the new streams: they are split in
+// content-defined chunks, shared by the streams of the project put with the
+// same dedupKey, and encrypted with keys derived from it and their data.
+func NewStreamStore(segments segments.Store, segmentSize int64, rootKey *[32]byte,
+	dedupKey *[32]byte, encBlockSize int) (Store, error) {
 	if segmentSize <= 0 {
 		return nil, errs.New("segment size must be larger than 0")
 	}
-	return &streamStore{segments: segments, segmentSize: segmentSize}, nil
+	if (rootKey != nil || dedupKey != nil) && encBlockSize <= 0 {
+		return nil, errs.New("encryption block size must be larger than 0")
+	}
+	return &streamStore{
+		segments:     segments,
+		segmentSize:  segmentSize,
+		rootKey:      rootKey,
+		dedupKey:     dedupKey,
+		encBlockSize: encBlockSize,
+	}, nil
 }
 
 // Put breaks up data as it comes in into s.segmentSize length pieces, then
 // store the first piece at s0/<path>, second piece at s1/<path>, and the
 // *last* piece at l/<path>. Store the given metadata, along with the number
 // of segments, in a new protobuf, in the metadata of l/<path>.
+//
+// With a root key, every segment is encrypted with a new key of the stream,
+// and a nonce derived from the index of the segment. The data of every
+// segment is compressed before, in frames indexed in the metadata of
+// l/<path>, if a compression is given.
+//
+// With a deduplication key, the data is stored in chunks instead, see
+// putDedup.
 func (s *streamStore) Put(ctx context.Context, path paths.Path, data io.Reader,
-	metadata []byte, expiration time.Time) (m Meta, err error) {
+	metadata []byte, expiration time.Time,
+	compression streamspb.CompressionType) (m Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if _, ok := streamspb.CompressionType_name[int32(compression)]; !ok {
+		return Meta{}, errs.New("unknown compression %d", compression)
+	}
+	if s.dedupKey != nil {
+		return s.putDedup(ctx, path, data, metadata, expiration, compression)
+	}
+
 	var totalSegments int64
 	var totalSize int64
 	var lastSegmentSize int64
+	var frameSizes []int32
+
+	var streamKey *[32]byte
+	if s.rootKey != nil {
+		streamKey, err = newStreamKey()
+		if err != nil {
+			return Meta{}, err
+		}
+	}
 
 	awareLimitReader := EOFAwareReader(data)
 
 	for !awareLimitReader.isEOF() && !awareLimitReader.hasError() {
 		segmentPath := path.Prepend(fmt.Sprintf("s%d", totalSegments))
-		segmentData := io.LimitReader(awareLimitReader, s.segmentSize)
+		sizedReader := segments.SizeReader(io.LimitReader(awareLimitReader, s.segmentSize))
+
+		var segmentData io.Reader = sizedReader
+		var compressed *compressedReader
+		if compression == streamspb.CompressionType_ZSTD {
+			compressed = compressSegment(segmentData, compressionFrameSize)
+			segmentData = compressed
+		}
+		if streamKey != nil {
+			segmentData, err = encryptSegment(segmentData, streamKey, totalSegments, s.encBlockSize)
+			if err != nil {
+				return Meta{}, err
+			}
+		}
 
-		putMeta, err := s.segments.Put(ctx, segmentPath, segmentData, nil, expiration)
+		_, err = s.segments.Put(ctx, segmentPath, segmentData, nil, expiration)
 		if err != nil {
 			return Meta{}, err
 		}
-		lastSegmentSize = putMeta.Size
-		totalSize = totalSize + putMeta.Size
+		if compressed != nil {
+			frameSizes = append(frameSizes, compressed.FrameSizes()...)
+		}
+		// the sizes of the data, which don't count the encryption overhead
+		lastSegmentSize = sizedReader.Size()
+		totalSize = totalSize + sizedReader.Size()
 		totalSegments = totalSegments + 1
 	}
 	if awareLimitReader.hasError() {
@@ -110,6 +187,18 @@ func (s *streamStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 		LastSegmentSize:  lastSegmentSize,
 		Metadata:         metadata,
 	}
+	if compression != streamspb.CompressionType_NONE {
+		md.Compression = compression
+		md.CompressionFrameSize = compressionFrameSize
+		md.CompressedFrameSizes = frameSizes
+	}
+	if streamKey != nil {
+		md.EncryptionBlockSize = int32(s.encBlockSize)
+		md.EncryptedKey, md.EncryptedKeyNonce, err = encryptStreamKey(streamKey, s.rootKey)
+		if err != nil {
+			return Meta{}, err
+		}
+	}
 	lastSegmentMetadata, err := proto.Marshal(&md)
 	if err != nil {
 		return Meta{}, err
@@ -134,12 +223,15 @@ func (s *streamStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 
 // Get returns a ranger that knows what the overall size is (from l/<path>)
 // and then returns the appropriate data from segments s0/<path>, s1/<path>,
-// ..., l/<path>.
+// ..., l/<path>. The pointers of all segments are looked up at once. The
+// ranges of encrypted streams only fetch and decrypt the encryption blocks
+// covering them. The data of the deduplicated streams is in the chunks
+// they reference instead.
 func (s *streamStore) Get(ctx context.Context, path paths.Path) (
 	rr ranger.Ranger, meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	lastRangerCloser, lastSegmentMeta, err := s.segments.Get(ctx, path.Prepend("l"))
+	lastRangerCloser, lastSegmentMeta, err := s.segments.Get(ctx, path.Prepend("l"), nil)
 	if err != nil {
 		return nil, Meta{}, err
 	}
@@ -156,19 +248,26 @@ func (s *streamStore) Get(ctx context.Context, path paths.Path) (
 	}
 
 	var rangers []ranger.Ranger
-
-	for i := int64(0); i < msi.NumberOfSegments; i++ {
-		currentPath := fmt.Sprintf("s%d", i)
-		size := msi.SegmentsSize
-		if i == msi.NumberOfSegments-1 {
-			size = msi.LastSegmentSize
+	if len(msi.Chunks) > 0 {
+		rangers, err = s.chunkRangers(ctx, path, msi.Chunks)
+		if err != nil {
+			return nil, Meta{}, err
+		}
+	} else {
+		rangers, _, err = s.segments.GetBatch(ctx, segmentPaths(path, msi.NumberOfSegments))
+		if err != nil {
+			return nil, Meta{}, err
 		}
-		rr := &lazySegmentRanger{
-			segments: s.segments,
-			path:     path.Prepend(currentPath),
-			size:     size,
+		if msi.EncryptionBlockSize > 0 || msi.Compression != streamspb.CompressionType_NONE {
+			key, err := s.streamKey(&msi)
+			if err != nil {
+				return nil, Meta{}, err
+			}
+			rangers, err = dataRangers(rangers, &msi, key)
+			if err != nil {
+				return nil, Meta{}, err
+			}
 		}
-		rangers = append(rangers, rr)
 	}
 
 	rangers = append(rangers, lastRangerCloser)
@@ -178,6 +277,71 @@ func (s *streamStore) Get(ctx context.Context, path paths.Path) (
 	return catRangers, newMeta, nil
 }
 
+// streamKey returns the key encrypting the segments of a stream, nil if
+// the stream isn't encrypted
+func (s *streamStore) streamKey(msi *streamspb.MetaStreamInfo) (*[32]byte, error) {
+	if msi.EncryptionBlockSize <= 0 {
+		return nil, nil
+	}
+	if s.rootKey == nil {
+		return nil, errs.New("stream is encrypted, but no encryption key is configured")
+	}
+	return decryptStreamKey(msi.EncryptedKey, msi.EncryptedKeyNonce, s.rootKey)
+}
+
+// dataRangers returns the rangers of the data of the segments s0/<path>,
+// s1/<path>, ... of a stream, or of a chunk, decrypted with key if not nil
+// and decompressed
+func dataRangers(rangers []ranger.Ranger, msi *streamspb.MetaStreamInfo,
+	key *[32]byte) (_ []ranger.Ranger, err error) {
+	compressed := msi.Compression == streamspb.CompressionType_ZSTD
+	if !compressed && msi.Compression != streamspb.CompressionType_NONE {
+		return nil, errs.New("unknown compression %d", msi.Compression)
+	}
+	frameSize := int(msi.CompressionFrameSize)
+	if compressed && frameSize <= 0 {
+		return nil, errs.New("invalid compression frame size %d", frameSize)
+	}
+	frameSizes := msi.CompressedFrameSizes
+
+	data := make([]ranger.Ranger, len(rangers))
+	for i, rr := range rangers {
+		size := msi.SegmentsSize
+		if int64(i) == msi.NumberOfSegments-1 {
+			size = msi.LastSegmentSize
+		}
+		// the size of the data stored, before the encryption padding
+		storedSize := size
+		var frames []int32
+		if compressed {
+			count := frameCount(size, frameSize)
+			if count > int64(len(frameSizes)) {
+				return nil, errs.New("missing compression frames")
+			}
+			frames, frameSizes = frameSizes[:count], frameSizes[count:]
+			storedSize = 0
+			for _, frame := range frames {
+				storedSize += int64(frame)
+			}
+		}
+		if key != nil {
+			rr, err = decryptSegment(rr, key, int64(i),
+				int(msi.EncryptionBlockSize), storedSize)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if compressed {
+			rr, err = decompressSegment(rr, frameSize, frames, size)
+			if err != nil {
+				return nil, err
+			}
+		}
+		data[i] = rr
+	}
+	return data, nil
+}
+
 // Meta implements Store.Meta
 func (s *streamStore) Meta(ctx context.Context, path paths.Path) (Meta, error) {
 	segmentMeta, err := s.segments.Meta(ctx, path.Prepend("l"))
@@ -193,7 +357,9 @@ func (s *streamStore) Meta(ctx context.Context, path paths.Path) (Meta, error) {
 	return meta, nil
 }
 
-// Delete all the segments, with the last one last
+// Delete all the segments, with the last one last. The chunks of a
+// deduplicated stream are deleted after it, unless other streams still
+// reference them.
 func (s *streamStore) Delete(ctx context.Context, path paths.Path) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -208,15 +374,73 @@ func (s *streamStore) Delete(ctx context.Context, path paths.Path) (err error) {
 		return err
 	}
 
-	for i := 0; i < int(msi.NumberOfSegments); i++ {
-		currentPath := fmt.Sprintf("s%d", i)
-		err := s.segments.Delete(ctx, path.Prepend(currentPath))
+	err = s.segments.DeleteBatch(ctx, segmentPaths(path, msi.NumberOfSegments))
+	if err != nil {
+		return err
+	}
+
+	err = s.segments.Delete(ctx, path.Prepend("l"))
+	if err != nil {
+		return err
+	}
+
+	if len(msi.Chunks) > 0 {
+		ctx = pdbclient.WithReferrer(ctx, path.Prepend("l"))
+		err = s.segments.DeleteUnreferenced(ctx, chunkPaths(path[0], msi.Chunks))
+		if err != nil {
+			// the stream is deleted, the chunks are only left behind
+			zap.S().Warnf("failed to delete the chunks of %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// Move moves the stream at from to to. Only the pointers of the segments
+// are moved, the data isn't copied.
+func (s *streamStore) Move(ctx context.Context, from, to paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.MoveBatch(ctx, []paths.Path{from}, []paths.Path{to})
+}
+
+// MoveBatch moves the streams at from to the paths at the same index of to,
+// atomically. A path may be moved from and to in the same batch, e.g. to
+// replace a stream with another one while keeping it elsewhere.
+func (s *streamStore) MoveBatch(ctx context.Context, from, to []paths.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(from) != len(to) {
+		return errs.New("moving %d streams to %d paths", len(from), len(to))
+	}
+
+	var fromPaths, toPaths []paths.Path
+	for i := range from {
+		lastSegmentMeta, err := s.segments.Meta(ctx, from[i].Prepend("l"))
+		if err != nil {
+			return err
+		}
+
+		msi := streamspb.MetaStreamInfo{}
+		err = proto.Unmarshal(lastSegmentMeta.Data, &msi)
 		if err != nil {
 			return err
 		}
+
+		fromPaths = append(fromPaths, segmentPaths(from[i], msi.NumberOfSegments)...)
+		toPaths = append(toPaths, segmentPaths(to[i], msi.NumberOfSegments)...)
+		fromPaths = append(fromPaths, from[i].Prepend("l"))
+		toPaths = append(toPaths, to[i].Prepend("l"))
 	}
+	return s.segments.MoveBatch(ctx, fromPaths, toPaths)
+}
 
-	return s.segments.Delete(ctx, path.Prepend("l"))
+// segmentPaths returns the paths s0/<path>, s1/<path>, ... of the count
+// segments before the last one
+func segmentPaths(path paths.Path, count int64) []paths.Path {
+	segmentPaths := make([]paths.Path, count)
+	for i := range segmentPaths {
+		segmentPaths[i] = path.Prepend(fmt.Sprintf("s%d", i))
+	}
+	return segmentPaths
 }
 
 // ListItem is a single item in a listing
@@ -254,27 +478,3 @@ func (s *streamStore) List(ctx context.Context, prefix, startAfter, endBefore pa
 
 	return items, more, nil
 }
-
-type lazySegmentRanger struct {
-	ranger   ranger.Ranger
-	segments segments.Store
-	path     paths.Path
-	size     int64
-}
-
-// Size implements Ranger.Size
-func (lr *lazySegmentRanger) Size() int64 {
-	return lr.size
-}
-
-// Range implements Ranger.Range to be lazily connected
-func (lr *lazySegmentRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
-	if lr.ranger == nil {
-		rr, _, err := lr.segments.Get(ctx, lr.path)
-		if err != nil {
-			return nil, err
-		}
-		lr.ranger = rr
-	}
-	return lr.ranger.Range(ctx, offset, length)
-}
@@ -0,0 +1,392 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package receiver
+
+import (
+	"context"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+)
+
+// Error is the error class of the receiver
+var Error = errs.Class("metric receiver error")
+
+// Options configures an Aggregator
+type Options struct {
+	// Interval is the duration of the windows
+	Interval time.Duration
+	// Windows is the number of windows kept per series
+	Windows int
+	// Rate is the number of metrics per second accepted from a remote
+	// address, which may send up to Burst at once
+	Rate  float64
+	Burst int
+	// MaxSources is the number of remote addresses tracked at once. The
+	// metrics of the addresses beyond are dropped.
+	MaxSources int
+	// MaxSeries is the number of series tracked per application instance,
+	// MaxTotalSeries the number of series tracked in all, and MaxBytes the
+	// memory they may use, roughly. The metrics of the series beyond are
+	// dropped.
+	MaxSeries      int
+	MaxTotalSeries int
+	MaxBytes       int64
+	// Output, if not nil, is written the windows once they end
+	Output Output
+}
+
+// Source identifies an instance of an application sending metrics
+type Source struct {
+	Application string
+	Instance    string
+}
+
+// SenderStats are the stats of the metrics sent from a remote address
+type SenderStats struct {
+	Addr     string    `json:"addr"`
+	Received int64     `json:"received"`
+	Dropped  int64     `json:"dropped"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// SourceStats are the stats of the metrics of a source
+type SourceStats struct {
+	Application string    `json:"application"`
+	Instance    string    `json:"instance"`
+	Series      int       `json:"series"`
+	Received    int64     `json:"received"`
+	Dropped     int64     `json:"dropped"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// Point is a window of a series, as written to the output
+type Point struct {
+	SeriesKey
+	Window
+}
+
+// SeriesWindows are the windows of a series
+type SeriesWindows struct {
+	Application string   `json:"application"`
+	Instance    string   `json:"instance"`
+	Key         string   `json:"key"`
+	Windows     []Window `json:"windows"`
+}
+
+// sender is a remote address sending metrics
+type sender struct {
+	limiter  *limiter
+	received int64
+	dropped  int64
+	lastSeen time.Time
+}
+
+type source struct {
+	series   map[string]*series
+	received int64
+	dropped  int64
+	lastSeen time.Time
+}
+
+// the memory used by a source besides its names, by a series besides its
+// key and by a window, roughly
+const (
+	sourceSize = 128
+	seriesSize = 128
+	windowSize = 64
+)
+
+// Aggregator aggregates the metrics per source and series in rolling
+// windows. It implements telemetry.AddrHandler.
+type Aggregator struct {
+	opts Options
+	now  func() time.Time
+
+	mu             sync.Mutex
+	senders        map[string]*sender
+	sources        map[Source]*source
+	droppedSources int64
+	// series and bytes are the number of series tracked and the memory
+	// they use
+	series int
+	bytes  int64
+}
+
+// NewAggregator returns an Aggregator configured by opts
+func NewAggregator(opts Options) (*Aggregator, error) {
+	if opts.Interval <= 0 || opts.Windows <= 0 {
+		return nil, Error.New("invalid windows: %d of %s", opts.Windows, opts.Interval)
+	}
+	if opts.Rate <= 0 || opts.Burst <= 0 {
+		return nil, Error.New("invalid rate: %v per second, burst of %d", opts.Rate, opts.Burst)
+	}
+	if opts.MaxSources <= 0 || opts.MaxSeries <= 0 || opts.MaxTotalSeries <= 0 || opts.MaxBytes <= 0 {
+		return nil, Error.New("invalid limits: %d sources, %d series per source, %d series, %d bytes",
+			opts.MaxSources, opts.MaxSeries, opts.MaxTotalSeries, opts.MaxBytes)
+	}
+	return &Aggregator{
+		opts:    opts,
+		now:     time.Now,
+		senders: make(map[string]*sender),
+		sources: make(map[Source]*source),
+	}, nil
+}
+
+// Metric aggregates a value received from a source, the values received
+// without an address being limited together
+func (a *Aggregator) Metric(application, instance string, key []byte, val float64) {
+	a.MetricFrom(nil, application, instance, key, val)
+}
+
+// MetricFrom aggregates a value received from a source at addr, unless
+// addr exceeds its rate or the limits are reached
+func (a *Aggregator) MetricFrom(addr net.Addr, application, instance string, key []byte, val float64) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		// e.g. the quantiles of empty distributions, which the outputs
+		// can't represent
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// the application and the instance are whatever the sender claims, so
+	// the sender is told by its address, regardless of its port
+	now := a.now()
+	host := addrHost(addr)
+	snd, ok := a.senders[host]
+	if !ok {
+		if len(a.senders) >= a.opts.MaxSources {
+			a.droppedSources++
+			return
+		}
+		snd = &sender{limiter: newLimiter(a.opts.Rate, a.opts.Burst, now)}
+		a.senders[host] = snd
+	}
+	snd.lastSeen = now
+
+	if !snd.limiter.allow(now) {
+		snd.dropped++
+		return
+	}
+
+	id := Source{Application: application, Instance: instance}
+	src := a.sources[id]
+	var s *series
+	if src != nil {
+		src.lastSeen = now
+		s = src.series[string(key)]
+	}
+	if s == nil {
+		size := a.seriesSize(string(key))
+		if src == nil {
+			size += sourceBytes(id)
+		}
+		if src != nil && len(src.series) >= a.opts.MaxSeries ||
+			a.series >= a.opts.MaxTotalSeries || a.bytes+size > a.opts.MaxBytes {
+			snd.dropped++
+			if src != nil {
+				src.dropped++
+			}
+			return
+		}
+		if src == nil {
+			src = &source{series: make(map[string]*series), lastSeen: now}
+			a.sources[id] = src
+		}
+		s = &series{}
+		src.series[string(key)] = s
+		a.series++
+		a.bytes += size
+	}
+	snd.received++
+	src.received++
+	s.add(now, a.opts.Interval, a.opts.Windows, val)
+}
+
+// seriesSize returns the memory used by the series of key, roughly, once
+// it holds all its windows
+func (a *Aggregator) seriesSize(key string) int64 {
+	return seriesSize + int64(len(key)) + int64(a.opts.Windows)*windowSize
+}
+
+// sourceBytes returns the memory used by the source id besides its
+// series, roughly
+func sourceBytes(id Source) int64 {
+	return sourceSize + int64(len(id.Application)+len(id.Instance))
+}
+
+// addrHost returns the host of addr, or an empty string without an address
+func addrHost(addr net.Addr) string {
+	switch addr := addr.(type) {
+	case nil:
+		return ""
+	case *net.UDPAddr:
+		if addr == nil {
+			return ""
+		}
+		return addr.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// Flush writes the windows ended since the last flush to the output, and
+// forgets the series, the sources and the senders idle for longer than all
+// the windows
+func (a *Aggregator) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	now := a.now()
+	maxIdle := time.Duration(a.opts.Windows) * a.opts.Interval
+	var points []Point
+	for id, src := range a.sources {
+		for key, s := range src.series {
+			for _, w := range s.completed(now, a.opts.Interval) {
+				points = append(points, Point{
+					SeriesKey: SeriesKey{Application: id.Application, Instance: id.Instance, Key: key},
+					Window:    w,
+				})
+			}
+			if !s.expire(now, a.opts.Interval, a.opts.Windows) {
+				delete(src.series, key)
+				a.series--
+				a.bytes -= a.seriesSize(key)
+			}
+		}
+		if len(src.series) == 0 && now.Sub(src.lastSeen) > maxIdle {
+			delete(a.sources, id)
+			a.bytes -= sourceBytes(id)
+		}
+	}
+	for host, snd := range a.senders {
+		if now.Sub(snd.lastSeen) > maxIdle {
+			delete(a.senders, host)
+		}
+	}
+	a.mu.Unlock()
+
+	if a.opts.Output == nil || len(points) == 0 {
+		return nil
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Start.Before(points[j].Start)
+	})
+	return a.opts.Output.Write(points)
+}
+
+// Run flushes the windows as they end, until ctx is canceled
+func (a *Aggregator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := a.Flush(ctx); err != nil {
+				zap.S().Errorf("failed to write the metrics: %v", err)
+			}
+		}
+	}
+}
+
+// Query returns the windows of the series of the application, the instance
+// and the keys starting with keyPrefix. An empty application or instance
+// matches all of them.
+func (a *Aggregator) Query(application, instance, keyPrefix string) []SeriesWindows {
+	a.mu.Lock()
+	var result []SeriesWindows
+	for id, src := range a.sources {
+		if application != "" && id.Application != application ||
+			instance != "" && id.Instance != instance {
+			continue
+		}
+		for key, s := range src.series {
+			if !strings.HasPrefix(key, keyPrefix) {
+				continue
+			}
+			result = append(result, SeriesWindows{
+				Application: id.Application,
+				Instance:    id.Instance,
+				Key:         key,
+				Windows:     append([]Window(nil), s.windows...),
+			})
+		}
+	}
+	a.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Application != result[j].Application {
+			return result[i].Application < result[j].Application
+		}
+		if result[i].Instance != result[j].Instance {
+			return result[i].Instance < result[j].Instance
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Senders returns the stats of the remote addresses tracked
+func (a *Aggregator) Senders() (senders []SenderStats) {
+	a.mu.Lock()
+	for host, snd := range a.senders {
+		senders = append(senders, SenderStats{
+			Addr:     host,
+			Received: snd.received,
+			Dropped:  snd.dropped,
+			LastSeen: snd.lastSeen,
+		})
+	}
+	a.mu.Unlock()
+
+	sort.Slice(senders, func(i, j int) bool {
+		return senders[i].Addr < senders[j].Addr
+	})
+	return senders
+}
+
+// Usage returns the number of series tracked and the memory they use,
+// roughly
+func (a *Aggregator) Usage() (series int, bytes int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.series, a.bytes
+}
+
+// Sources returns the stats of the sources tracked, and the number of
+// metrics dropped from the remote addresses beyond the limit
+func (a *Aggregator) Sources() (sources []SourceStats, droppedSources int64) {
+	a.mu.Lock()
+	for id, src := range a.sources {
+		sources = append(sources, SourceStats{
+			Application: id.Application,
+			Instance:    id.Instance,
+			Series:      len(src.series),
+			Received:    src.received,
+			Dropped:     src.dropped,
+			LastSeen:    src.lastSeen,
+		})
+	}
+	droppedSources = a.droppedSources
+	a.mu.Unlock()
+
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Application != sources[j].Application {
+			return sources[i].Application < sources[j].Application
+		}
+		return sources[i].Instance < sources[j].Instance
+	})
+	return sources, droppedSources
+}
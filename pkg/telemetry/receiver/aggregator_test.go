@@ -0,0 +1,233 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package receiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryOutput keeps the points written
+type memoryOutput struct {
+	points []Point
+}
+
+func (o *memoryOutput) Write(points []Point) error {
+	o.points = append(o.points, points...)
+	return nil
+}
+
+func (o *memoryOutput) Close() error { return nil }
+
+func newTestAggregator(t *testing.T, opts Options) (*Aggregator, *time.Time) {
+	a, err := NewAggregator(opts)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	now := time.Date(2018, time.October, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	return a, &now
+}
+
+func TestAggregatorWindows(t *testing.T) {
+	ctx := context.Background()
+	output := &memoryOutput{}
+	a, now := newTestAggregator(t, Options{
+		Interval: time.Minute, Windows: 3,
+		Rate: 1000, Burst: 1000, MaxSources: 10, MaxSeries: 10, MaxTotalSeries: 100, MaxBytes: 1 << 20,
+		Output: output,
+	})
+
+	for _, val := range []float64{3, 1, 2, math.NaN()} {
+		a.Metric("app", "inst", []byte("key"), val)
+	}
+	a.Metric("app", "other", []byte("key"), 5)
+
+	series := a.Query("app", "inst", "")
+	if assert.Len(t, series, 1) && assert.Len(t, series[0].Windows, 1) {
+		assert.Equal(t, Window{Start: *now, Count: 3, Sum: 6, Min: 1, Max: 3, Last: 2},
+			series[0].Windows[0])
+	}
+	assert.Len(t, a.Query("", "", "key"), 2)
+	assert.Len(t, a.Query("", "", "other"), 0)
+
+	// the current window isn't written before it ends
+	assert.NoError(t, a.Flush(ctx))
+	assert.Len(t, output.points, 0)
+
+	*now = now.Add(time.Minute)
+	a.Metric("app", "inst", []byte("key"), 4)
+	assert.NoError(t, a.Flush(ctx))
+	assert.Len(t, output.points, 2)
+	assert.NoError(t, a.Flush(ctx))
+	assert.Len(t, output.points, 2)
+
+	// at most 3 windows are kept, and the series without any are forgotten
+	for i := 0; i < 3; i++ {
+		*now = now.Add(time.Minute)
+		a.Metric("app", "inst", []byte("key"), 4)
+	}
+	assert.NoError(t, a.Flush(ctx))
+	assert.Len(t, a.Query("app", "inst", "")[0].Windows, 3)
+	assert.Len(t, a.Query("app", "other", ""), 0)
+
+	*now = now.Add(time.Hour)
+	assert.NoError(t, a.Flush(ctx))
+	sources, _ := a.Sources()
+	assert.Len(t, sources, 0)
+}
+
+func TestAggregatorLimits(t *testing.T) {
+	a, now := newTestAggregator(t, Options{
+		Interval: time.Minute, Windows: 3,
+		Rate: 10, Burst: 5, MaxSources: 2, MaxSeries: 2, MaxTotalSeries: 100, MaxBytes: 1 << 20,
+	})
+	noisy := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1000}
+	quiet := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 1000}
+	third := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 3), Port: 1000}
+
+	// the rate is limited per address, whatever the instances and the ports
+	for i := 0; i < 10; i++ {
+		a.MetricFrom(noisy, "app", fmt.Sprintf("noisy%d", i%2), []byte("key"), 1)
+	}
+	a.MetricFrom(&net.UDPAddr{IP: noisy.IP, Port: 2000}, "app", "noisy2", []byte("key"), 1)
+	a.MetricFrom(quiet, "app", "quiet", []byte("a"), 1)
+	a.MetricFrom(quiet, "app", "quiet", []byte("b"), 1)
+	a.MetricFrom(quiet, "app", "quiet", []byte("c"), 1)
+	a.MetricFrom(third, "app", "third", []byte("a"), 1)
+
+	sources, droppedSources := a.Sources()
+	assert.Equal(t, int64(1), droppedSources)
+	assert.Equal(t, []SourceStats{
+		{Application: "app", Instance: "noisy0", Series: 1, Received: 3, LastSeen: *now},
+		{Application: "app", Instance: "noisy1", Series: 1, Received: 2, LastSeen: *now},
+		{Application: "app", Instance: "quiet", Series: 2, Received: 2, Dropped: 1, LastSeen: *now},
+	}, sources)
+	assert.Equal(t, []SenderStats{
+		{Addr: "10.0.0.1", Received: 5, Dropped: 6, LastSeen: *now},
+		{Addr: "10.0.0.2", Received: 2, Dropped: 1, LastSeen: *now},
+	}, a.Senders())
+
+	// the rate refills the bucket
+	*now = now.Add(200 * time.Millisecond)
+	a.MetricFrom(noisy, "app", "noisy0", []byte("key"), 1)
+	a.MetricFrom(noisy, "app", "noisy0", []byte("key"), 1)
+	a.MetricFrom(noisy, "app", "noisy0", []byte("key"), 1)
+	senders := a.Senders()
+	assert.Equal(t, int64(7), senders[0].Received)
+	assert.Equal(t, int64(7), senders[0].Dropped)
+
+	// the senders are forgotten once idle, making room for others
+	*now = now.Add(time.Hour)
+	assert.NoError(t, a.Flush(context.Background()))
+	assert.Len(t, a.Senders(), 0)
+	a.MetricFrom(third, "app", "third", []byte("a"), 1)
+	assert.Len(t, a.Senders(), 1)
+}
+
+func TestAggregatorTotalLimits(t *testing.T) {
+	opts := Options{
+		Interval: time.Minute, Windows: 3,
+		Rate: 100, Burst: 100, MaxSources: 10, MaxSeries: 10, MaxTotalSeries: 3, MaxBytes: 1 << 20,
+	}
+	a, now := newTestAggregator(t, opts)
+
+	// the series are limited in all, whatever the instances
+	for i := 0; i < 5; i++ {
+		a.Metric("app", fmt.Sprintf("inst%d", i), []byte("key"), 1)
+	}
+	series, bytes := a.Usage()
+	assert.Equal(t, 3, series)
+	assert.Equal(t, 3*(sourceBytes(Source{"app", "inst0"})+a.seriesSize("key")), bytes)
+	senders := a.Senders()
+	if assert.Len(t, senders, 1) {
+		assert.Equal(t, int64(3), senders[0].Received)
+		assert.Equal(t, int64(2), senders[0].Dropped)
+	}
+	// the values of the series tracked are still aggregated
+	a.Metric("app", "inst0", []byte("key"), 2)
+	assert.Len(t, a.Query("app", "inst0", "")[0].Windows, 1)
+
+	// the series forgotten make room for others
+	*now = now.Add(time.Hour)
+	assert.NoError(t, a.Flush(context.Background()))
+	series, bytes = a.Usage()
+	assert.Equal(t, 0, series)
+	assert.Equal(t, int64(0), bytes)
+
+	// and so is the memory they use, long keys counting more
+	opts.MaxTotalSeries = 100
+	opts.MaxBytes = sourceBytes(Source{"app", "inst"}) + 2*a.seriesSize("key")
+	a, _ = newTestAggregator(t, opts)
+	a.Metric("app", "inst", []byte("key"), 1)
+	a.Metric("app", "inst", []byte("long key"), 1)
+	a.Metric("app", "inst", []byte("abc"), 1)
+	sources, _ := a.Sources()
+	if assert.Len(t, sources, 1) {
+		assert.Equal(t, 2, sources[0].Series)
+		assert.Equal(t, int64(1), sources[0].Dropped)
+	}
+	assert.Len(t, a.Query("app", "inst", "long"), 0)
+}
+
+func TestAggregatorHandler(t *testing.T) {
+	a, _ := newTestAggregator(t, Options{
+		Interval: time.Minute, Windows: 3,
+		Rate: 10, Burst: 10, MaxSources: 2, MaxSeries: 2, MaxTotalSeries: 100, MaxBytes: 1 << 20,
+	})
+	a.Metric("app", "inst", []byte("scope.key"), 1)
+	a.Metric("app", "inst", []byte("other"), 2)
+
+	w := httptest.NewRecorder()
+	a.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/query?application=app&key=scope.", nil))
+	var series []SeriesWindows
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&series))
+	if assert.Len(t, series, 1) {
+		assert.Equal(t, "scope.key", series[0].Key)
+		assert.Len(t, series[0].Windows, 1)
+	}
+
+	w = httptest.NewRecorder()
+	a.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/sources", nil))
+	var sources struct {
+		Sources []SourceStats `json:"sources"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sources))
+	if assert.Len(t, sources.Sources, 1) {
+		assert.Equal(t, int64(2), sources.Sources[0].Received)
+	}
+}
+
+func TestNewAggregatorErrors(t *testing.T) {
+	valid := Options{
+		Interval: time.Minute, Windows: 1,
+		Rate: 1, Burst: 1, MaxSources: 1, MaxSeries: 1, MaxTotalSeries: 1, MaxBytes: 1,
+	}
+	_, err := NewAggregator(valid)
+	assert.NoError(t, err)
+
+	for _, change := range []func(*Options){
+		func(o *Options) { o.Interval = 0 },
+		func(o *Options) { o.Windows = 0 },
+		func(o *Options) { o.Rate = 0 },
+		func(o *Options) { o.Burst = 0 },
+		func(o *Options) { o.MaxSources = 0 },
+		func(o *Options) { o.MaxSeries = 0 },
+		func(o *Options) { o.MaxTotalSeries = 0 },
+		func(o *Options) { o.MaxBytes = 0 },
+	} {
+		opts := valid
+		change(&opts)
+		_, err := NewAggregator(opts)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package receiver
+
+import (
+	"time"
+)
+
+// limiter is a token bucket limiting the rate of the metrics of a source
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns a limiter allowing rate metrics per second, and up to
+// burst at once
+func newLimiter(rate float64, burst int, now time.Time) *limiter {
+	return &limiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// allow returns whether a metric received at now is allowed
+func (l *limiter) allow(now time.Time) bool {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package receiver
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxDatagram is the size of the UDP datagrams the points are sent in
+const maxDatagram = 1400
+
+// Output is where the windows are written once they end
+type Output interface {
+	Write(points []Point) error
+	Close() error
+}
+
+// OpenOutput opens the output at rawurl. file:///path appends the points
+// to a local file, influx+udp://host:port and influx+tcp://host:port send
+// them to InfluxDB, all in the InfluxDB line protocol. graphite://host:port
+// sends them in the Graphite plaintext protocol over TCP.
+func OpenOutput(rawurl string) (Output, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	switch u.Scheme {
+	case "file":
+		f, err := os.OpenFile(u.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		return &fileOutput{f: f}, nil
+	case "influx+udp":
+		return &netOutput{network: "udp", addr: u.Host, format: writeLineProtocol}, nil
+	case "influx+tcp":
+		return &netOutput{network: "tcp", addr: u.Host, format: writeLineProtocol}, nil
+	case "graphite":
+		return &netOutput{network: "tcp", addr: u.Host, format: writeGraphite}, nil
+	default:
+		return nil, Error.New("unknown output %q", rawurl)
+	}
+}
+
+// fileOutput appends the points to a file
+type fileOutput struct {
+	f *os.File
+}
+
+func (o *fileOutput) Write(points []Point) error {
+	var buf bytes.Buffer
+	for _, p := range points {
+		writeLineProtocol(&buf, p)
+	}
+	_, err := o.f.Write(buf.Bytes())
+	return Error.Wrap(err)
+}
+
+func (o *fileOutput) Close() error {
+	return Error.Wrap(o.f.Close())
+}
+
+// netOutput sends the points to a server, reconnecting after the errors
+type netOutput struct {
+	network, addr string
+	format        func(w *bytes.Buffer, p Point)
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (o *netOutput) Write(points []Point) (err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.conn == nil {
+		o.conn, err = net.Dial(o.network, o.addr)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, p := range points {
+		n := buf.Len()
+		o.format(&buf, p)
+		if o.network == "udp" && buf.Len() > maxDatagram && n > 0 {
+			// the last point is sent in the next datagram
+			if err = o.send(buf.Bytes()[:n]); err != nil {
+				return err
+			}
+			buf.Next(n)
+		}
+	}
+	return o.send(buf.Bytes())
+}
+
+func (o *netOutput) send(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := o.conn.Write(data)
+	if err != nil {
+		_ = o.conn.Close()
+		o.conn = nil
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
+func (o *netOutput) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.conn == nil {
+		return nil
+	}
+	err := o.conn.Close()
+	o.conn = nil
+	return Error.Wrap(err)
+}
+
+// writeLineProtocol writes a point in the InfluxDB line protocol: the key
+// is the measurement, tagged with the application and the instance
+func writeLineProtocol(w *bytes.Buffer, p Point) {
+	w.WriteString(lineEscaper.Replace(p.Key))
+	w.WriteString(",application=")
+	w.WriteString(tagEscaper.Replace(p.Application))
+	w.WriteString(",instance=")
+	w.WriteString(tagEscaper.Replace(p.Instance))
+	fmt.Fprintf(w, " count=%di,sum=%s,min=%s,max=%s,last=%s %d\n",
+		p.Count, formatFloat(p.Sum), formatFloat(p.Min), formatFloat(p.Max),
+		formatFloat(p.Last), p.Start.UnixNano())
+}
+
+// writeGraphite writes a point in the Graphite plaintext protocol, a line
+// per field at application.instance.key.field
+func writeGraphite(w *bytes.Buffer, p Point) {
+	path := graphiteName(p.Application) + "." + graphiteName(p.Instance) + "." +
+		graphiteKey(p.Key)
+	for _, field := range []struct {
+		name  string
+		value float64
+	}{
+		{"count", float64(p.Count)},
+		{"sum", p.Sum},
+		{"min", p.Min},
+		{"max", p.Max},
+		{"last", p.Last},
+	} {
+		fmt.Fprintf(w, "%s.%s %s %d\n", path, field.name, formatFloat(field.value), p.Start.Unix())
+	}
+}
+
+var (
+	lineEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	tagEscaper  = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`, "\n", `\n`)
+)
+
+// graphiteName replaces the characters of s other than letters, digits,
+// dashes and underscores, which would split or break the Graphite paths
+func graphiteName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// graphiteKey is graphiteName keeping the dots, which split the keys in
+// their scope and names
+func graphiteKey(s string) string {
+	parts := strings.Split(s, ".")
+	for i, part := range parts {
+		parts[i] = graphiteName(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
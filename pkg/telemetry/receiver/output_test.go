@@ -0,0 +1,97 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package receiver
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPoint = Point{
+	SeriesKey: SeriesKey{Application: "app name", Instance: "inst,1", Key: "storj.io/pkg.Put times"},
+	Window: Window{
+		Start: time.Unix(1538395200, 0),
+		Count: 2, Sum: 3.5, Min: 1, Max: 2.5, Last: 2.5,
+	},
+}
+
+func TestWriteLineProtocol(t *testing.T) {
+	var buf bytes.Buffer
+	writeLineProtocol(&buf, testPoint)
+	assert.Equal(t, `storj.io/pkg.Put\ times,application=app\ name,instance=inst\,1 `+
+		"count=2i,sum=3.5,min=1,max=2.5,last=2.5 1538395200000000000\n", buf.String())
+}
+
+func TestWriteGraphite(t *testing.T) {
+	var buf bytes.Buffer
+	writeGraphite(&buf, testPoint)
+	assert.Equal(t, ""+
+		"app_name.inst_1.storj.io_pkg.Put_times.count 2 1538395200\n"+
+		"app_name.inst_1.storj.io_pkg.Put_times.sum 3.5 1538395200\n"+
+		"app_name.inst_1.storj.io_pkg.Put_times.min 1 1538395200\n"+
+		"app_name.inst_1.storj.io_pkg.Put_times.max 2.5 1538395200\n"+
+		"app_name.inst_1.storj.io_pkg.Put_times.last 2.5 1538395200\n", buf.String())
+}
+
+func TestFileOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metric-receiver")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "metrics")
+
+	for i := 0; i < 2; i++ {
+		output, err := OpenOutput("file://" + path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, output.Write([]Point{testPoint}))
+		assert.NoError(t, output.Close())
+	}
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+}
+
+func TestNetOutput(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = ln.Close() }()
+
+	lines := make(chan string)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	output, err := OpenOutput("graphite://" + ln.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, output.Close()) }()
+	assert.NoError(t, output.Write([]Point{testPoint}))
+	assert.Equal(t, "app_name.inst_1.storj.io_pkg.Put_times.count 2 1538395200", <-lines)
+
+	_, err = OpenOutput("ftp://localhost")
+	assert.Error(t, err)
+}
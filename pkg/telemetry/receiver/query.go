@@ -0,0 +1,42 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package receiver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// Handler returns the HTTP handler querying the aggregator:
+// /query?application=&instance=&key= returns the windows of the matching
+// series, key being a prefix of their keys, and /sources the stats of the
+// sources and of the remote addresses sending them.
+func (a *Aggregator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		writeJSON(w, a.Query(q.Get("application"), q.Get("instance"), q.Get("key")))
+	})
+	mux.HandleFunc("/sources", func(w http.ResponseWriter, r *http.Request) {
+		sources, dropped := a.Sources()
+		series, bytes := a.Usage()
+		writeJSON(w, struct {
+			Sources        []SourceStats `json:"sources"`
+			Senders        []SenderStats `json:"senders"`
+			DroppedSources int64         `json:"dropped_sources"`
+			Series         int           `json:"series"`
+			Bytes          int64         `json:"bytes"`
+		}{sources, a.Senders(), dropped, series, bytes})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		zap.S().Warnf("failed to write the response: %v", err)
+	}
+}
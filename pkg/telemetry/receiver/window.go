@@ -0,0 +1,91 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package receiver
+
+import (
+	"time"
+)
+
+// SeriesKey identifies the values of a metric sent by an instance of an
+// application
+type SeriesKey struct {
+	Application string
+	Instance    string
+	Key         string
+}
+
+// Window aggregates the values of a series received during Interval from
+// Start
+type Window struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+	Sum   float64   `json:"sum"`
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Last  float64   `json:"last"`
+}
+
+// add aggregates a value in the window
+func (w *Window) add(val float64) {
+	if w.Count == 0 || val < w.Min {
+		w.Min = val
+	}
+	if w.Count == 0 || val > w.Max {
+		w.Max = val
+	}
+	w.Count++
+	w.Sum += val
+	w.Last = val
+}
+
+// series keeps the rolling windows of a series, the oldest first
+type series struct {
+	windows []Window
+	// flushed is the start of the last window written to the output
+	flushed time.Time
+}
+
+// add aggregates a value received at now in the window of interval
+// containing it, keeping at most count windows
+func (s *series) add(now time.Time, interval time.Duration, count int, val float64) {
+	start := now.Truncate(interval)
+	n := len(s.windows)
+	if n == 0 || s.windows[n-1].Start.Before(start) {
+		s.windows = append(s.windows, Window{Start: start})
+		if len(s.windows) > count {
+			s.windows = append(s.windows[:0], s.windows[len(s.windows)-count:]...)
+		}
+		n = len(s.windows)
+	}
+	// a value of an older window, e.g. after the clock went back, is
+	// aggregated in the last one
+	s.windows[n-1].add(val)
+}
+
+// expire drops the windows which ended before the count windows of
+// interval before now, and returns whether there are windows left
+func (s *series) expire(now time.Time, interval time.Duration, count int) bool {
+	oldest := now.Truncate(interval).Add(-time.Duration(count-1) * interval)
+	i := 0
+	for i < len(s.windows) && s.windows[i].Start.Before(oldest) {
+		i++
+	}
+	s.windows = append(s.windows[:0], s.windows[i:]...)
+	return len(s.windows) > 0
+}
+
+// completed returns the windows which ended by now and weren't flushed yet
+func (s *series) completed(now time.Time, interval time.Duration) []Window {
+	current := now.Truncate(interval)
+	var windows []Window
+	for _, w := range s.windows {
+		if w.Start.After(s.flushed) && w.Start.Before(current) {
+			windows = append(windows, w)
+		}
+	}
+	if len(windows) > 0 {
+		s.flushed = windows[len(windows)-1].Start
+	}
+	return windows
+}
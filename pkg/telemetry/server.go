@@ -23,6 +23,14 @@ type Handler interface {
 	Metric(application, instance string, key []byte, val float64)
 }
 
+// AddrHandler is a Handler which is also told the address the metrics
+// come from. The server calls MetricFrom instead of Metric when the Handler
+// implements it.
+type AddrHandler interface {
+	Handler
+	MetricFrom(addr net.Addr, application, instance string, key []byte, val float64)
+}
+
 // HandlerFunc turns a func into a Handler
 type HandlerFunc func(application, instance string, key []byte, val float64)
 
@@ -107,6 +115,7 @@ func (h handlerWrapper) Handle(ctx context.Context, m *admission.Message) {
 		return
 	}
 	application, instance := string(applicationB), string(instanceB)
+	addrHandler, _ := h.h.(AddrHandler)
 	var key []byte
 	var value float64
 	for len(data) > 0 {
@@ -115,7 +124,11 @@ func (h handlerWrapper) Handle(ctx context.Context, m *admission.Message) {
 			finish(&err)
 			return
 		}
-		h.h.Metric(application, instance, key, value)
+		if addrHandler != nil {
+			addrHandler.MetricFrom(m.Addr, application, instance, key, value)
+		} else {
+			h.h.Metric(application, instance, key, value)
+		}
 	}
 
 	finish(nil)
@@ -24,3 +24,18 @@ type Client interface {
 	DialUnauthenticated(ctx context.Context, addr proto.NodeAddress) (*grpc.ClientConn, error)
 	DialNode(ctx context.Context, node *proto.Node) (*grpc.ClientConn, error)
 }
+
+// Releaser is a Client owning the connections it dials, e.g. to share them.
+// The callers release the connections instead of closing them.
+type Releaser interface {
+	Release(conn *grpc.ClientConn) error
+}
+
+// Release releases conn dialed with c once the caller is done with it. conn
+// is handed back if c is a Releaser and closed otherwise.
+func Release(c Client, conn *grpc.ClientConn) error {
+	if r, ok := c.(Releaser); ok {
+		return r.Release(conn)
+	}
+	return conn.Close()
+}
@@ -5,6 +5,10 @@ package transport
 
 import (
 	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -12,29 +16,77 @@ import (
 	proto "storj.io/storj/protos/overlay"
 )
 
+// handshakeTimeout bounds dialing a node and verifying its identity when the
+// context doesn't set an earlier deadline, so that unresponsive nodes can't
+// block the dialers
+var handshakeTimeout = 20 * time.Second
+
 // Transport interface structure
 type Transport struct {
-	identity *provider.FullIdentity
+	identity      *provider.FullIdentity
+	minDifficulty uint16
 }
 
-// NewClient returns a newly instantiated Transport Client
-func NewClient(identity *provider.FullIdentity) *Transport {
-	return &Transport{identity: identity}
+// NewClient returns a newly instantiated Transport Client. Nodes with node
+// IDs below minDifficulty are refused.
+func NewClient(identity *provider.FullIdentity, minDifficulty uint16) *Transport {
+	return &Transport{identity: identity, minDifficulty: minDifficulty}
 }
 
-// DialNode using the authenticated mode
+// DialNode using the authenticated mode. The node has to prove that it owns
+// node.Id before DialNode returns, otherwise the error is a
+// provider.ErrNodeID or provider.ErrDifficulty.
 func (o *Transport) DialNode(ctx context.Context, node *proto.Node) (conn *grpc.ClientConn, err error) {
 	defer mon.Task()(&ctx)(&err)
-
 	if node.Address == nil || node.Address.Address == "" {
 		return nil, Error.New("no address")
 	}
+	if node.Id == "" {
+		return nil, Error.New("no node id")
+	}
+
+	tlsConfig, err := o.identity.NodeTLSConfig(node.Id, o.minDifficulty)
+	if err != nil {
+		return nil, err
+	}
 
-	dialOpt, err := o.identity.DialOption()
+	hsCtx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	first, err := handshake(hsCtx, node.Address.Address, tlsConfig)
+	cancel()
 	if err != nil {
 		return nil, err
 	}
-	return grpc.Dial(node.Address.Address, dialOpt)
+
+	// the first connection is the verified one, reconnects verify again
+	var mu sync.Mutex
+	dialer := func(addr string, timeout time.Duration) (net.Conn, error) {
+		mu.Lock()
+		c := first
+		first = nil
+		mu.Unlock()
+		if c != nil {
+			return c, nil
+		}
+
+		if timeout <= 0 || timeout > handshakeTimeout {
+			timeout = handshakeTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return handshake(ctx, addr, tlsConfig)
+	}
+
+	// the dialer already secures the connection with tls
+	conn, err = grpc.Dial(node.Address.Address, grpc.WithInsecure(), grpc.WithDialer(dialer))
+	if err != nil {
+		mu.Lock()
+		if first != nil {
+			_ = first.Close()
+		}
+		mu.Unlock()
+		return nil, Error.Wrap(err)
+	}
+	return conn, nil
 }
 
 // DialUnauthenticated using unauthenticated mode
@@ -47,3 +99,35 @@ func (o *Transport) DialUnauthenticated(ctx context.Context, addr proto.NodeAddr
 
 	return grpc.Dial(addr.Address, grpc.WithInsecure())
 }
+
+// handshake opens a tls connection to addr. Node ID verification errors are
+// returned unwrapped.
+func handshake(ctx context.Context, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	var d net.Dialer
+	raw, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := raw.SetDeadline(deadline); err != nil {
+			_ = raw.Close()
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	conn := tls.Client(raw, tlsConfig)
+	if err := conn.Handshake(); err != nil {
+		_ = raw.Close()
+		if provider.ErrNodeID.Has(err) || provider.ErrDifficulty.Has(err) {
+			return nil, err
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	if err := raw.SetDeadline(time.Time{}); err != nil {
+		_ = raw.Close()
+		return nil, Error.Wrap(err)
+	}
+	return conn, nil
+}
@@ -1,12 +1,18 @@
 // Copyright (C) 2018 Storj Labs, Inc.
 // See LICENSE for copying information.
+
 package transport
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zeebo/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 
 	"storj.io/storj/pkg/provider"
 	proto "storj.io/storj/protos/overlay"
@@ -14,14 +20,17 @@ import (
 
 var ctx = context.Background()
 
-func TestDialNode(t *testing.T) {
+func newTestIdentity(t *testing.T) *provider.FullIdentity {
 	ca, err := provider.NewCA(ctx, 12, 4)
 	assert.NoError(t, err)
 	identity, err := ca.NewIdentity()
 	assert.NoError(t, err)
+	return identity
+}
 
+func TestDialNode(t *testing.T) {
 	oc := Transport{
-		identity: identity,
+		identity: newTestIdentity(t),
 	}
 
 	// node.Address.Address == "" condition test
@@ -45,15 +54,97 @@ func TestDialNode(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, conn)
 
-	// node is valid argument condition test
+	// node.Id == "" condition test
 	node = proto.Node{
-		Id: "DUMMYID3",
 		Address: &proto.NodeAddress{
 			Transport: proto.NodeTransport_TCP,
-			Address:   "127.0.0.0:9000",
+			Address:   "127.0.0.1:9000",
 		},
 	}
 	conn, err = oc.DialNode(ctx, &node)
+	assert.Error(t, err)
+	assert.Nil(t, conn)
+}
+
+func TestDialNodeVerifiesID(t *testing.T) {
+	serverIdentity := newTestIdentity(t)
+	serverOpt, err := serverIdentity.ServerOption()
 	assert.NoError(t, err)
-	assert.NotNil(t, conn)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer(serverOpt)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	address := &proto.NodeAddress{
+		Transport: proto.NodeTransport_TCP,
+		Address:   lis.Addr().String(),
+	}
+	difficulty := serverIdentity.ID.Difficulty()
+
+	for i, tt := range []struct {
+		id            string
+		minDifficulty uint16
+		errClass      *errs.Class
+	}{
+		{serverIdentity.ID.String(), 0, nil},
+		{serverIdentity.ID.String(), difficulty, nil},
+		{serverIdentity.ID.String(), difficulty + 1, &provider.ErrDifficulty},
+		{newTestIdentity(t).ID.String(), 0, &provider.ErrNodeID},
+	} {
+		oc := NewClient(newTestIdentity(t), tt.minDifficulty)
+
+		conn, err := oc.DialNode(ctx, &proto.Node{Id: tt.id, Address: address})
+		if tt.errClass != nil {
+			assert.True(t, tt.errClass.Has(err), i)
+			assert.Nil(t, conn, i)
+			continue
+		}
+		assert.NoError(t, err, i)
+		if assert.NotNil(t, conn, i) {
+			assert.NoError(t, conn.Close(), i)
+		}
+	}
+}
+
+func TestDialNodeTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { handshakeTimeout = timeout }(handshakeTimeout)
+	handshakeTimeout = 100 * time.Millisecond
+
+	// the listener accepts the connections but never completes a handshake
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = lis.Close() }()
+
+	node := &proto.Node{
+		Id:      newTestIdentity(t).ID.String(),
+		Address: &proto.NodeAddress{Transport: proto.NodeTransport_TCP, Address: lis.Addr().String()},
+	}
+
+	start := time.Now()
+	conn, err := NewClient(newTestIdentity(t), 0).DialNode(ctx, node)
+	assert.Error(t, err)
+	assert.Nil(t, conn)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestRelease(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = lis.Close() }()
+
+	oc := NewClient(newTestIdentity(t), 0)
+	conn, err := oc.DialUnauthenticated(ctx, proto.NodeAddress{Address: lis.Addr().String()})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the connections of a plain transport client aren't shared
+	assert.NoError(t, Release(oc, conn))
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
 }
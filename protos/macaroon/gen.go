@@ -0,0 +1,6 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+//go:generate protoc --go_out=plugins=grpc:. macaroon.proto
@@ -38,7 +38,7 @@ func (m *PayerBandwidthAllocation) Reset()         { *m = PayerBandwidthAllocati
 func (m *PayerBandwidthAllocation) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocation) ProtoMessage()    {}
 func (*PayerBandwidthAllocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{0}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{0}
 }
 func (m *PayerBandwidthAllocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocation.Unmarshal(m, b)
@@ -87,7 +87,7 @@ func (m *PayerBandwidthAllocation_Data) Reset()         { *m = PayerBandwidthAll
 func (m *PayerBandwidthAllocation_Data) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocation_Data) ProtoMessage()    {}
 func (*PayerBandwidthAllocation_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{0, 0}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{0, 0}
 }
 func (m *PayerBandwidthAllocation_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocation_Data.Unmarshal(m, b)
@@ -154,7 +154,7 @@ func (m *RenterBandwidthAllocation) Reset()         { *m = RenterBandwidthAlloca
 func (m *RenterBandwidthAllocation) String() string { return proto.CompactTextString(m) }
 func (*RenterBandwidthAllocation) ProtoMessage()    {}
 func (*RenterBandwidthAllocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{1}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{1}
 }
 func (m *RenterBandwidthAllocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RenterBandwidthAllocation.Unmarshal(m, b)
@@ -200,7 +200,7 @@ func (m *RenterBandwidthAllocation_Data) Reset()         { *m = RenterBandwidthA
 func (m *RenterBandwidthAllocation_Data) String() string { return proto.CompactTextString(m) }
 func (*RenterBandwidthAllocation_Data) ProtoMessage()    {}
 func (*RenterBandwidthAllocation_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{1, 0}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{1, 0}
 }
 func (m *RenterBandwidthAllocation_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RenterBandwidthAllocation_Data.Unmarshal(m, b)
@@ -246,7 +246,7 @@ func (m *PieceStore) Reset()         { *m = PieceStore{} }
 func (m *PieceStore) String() string { return proto.CompactTextString(m) }
 func (*PieceStore) ProtoMessage()    {}
 func (*PieceStore) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{2}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{2}
 }
 func (m *PieceStore) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStore.Unmarshal(m, b)
@@ -293,7 +293,7 @@ func (m *PieceStore_PieceData) Reset()         { *m = PieceStore_PieceData{} }
 func (m *PieceStore_PieceData) String() string { return proto.CompactTextString(m) }
 func (*PieceStore_PieceData) ProtoMessage()    {}
 func (*PieceStore_PieceData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{2, 0}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{2, 0}
 }
 func (m *PieceStore_PieceData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStore_PieceData.Unmarshal(m, b)
@@ -345,7 +345,7 @@ func (m *PieceId) Reset()         { *m = PieceId{} }
 func (m *PieceId) String() string { return proto.CompactTextString(m) }
 func (*PieceId) ProtoMessage()    {}
 func (*PieceId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{3}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{3}
 }
 func (m *PieceId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceId.Unmarshal(m, b)
@@ -385,7 +385,7 @@ func (m *PieceSummary) Reset()         { *m = PieceSummary{} }
 func (m *PieceSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceSummary) ProtoMessage()    {}
 func (*PieceSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{4}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{4}
 }
 func (m *PieceSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceSummary.Unmarshal(m, b)
@@ -438,7 +438,7 @@ func (m *PieceRetrieval) Reset()         { *m = PieceRetrieval{} }
 func (m *PieceRetrieval) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrieval) ProtoMessage()    {}
 func (*PieceRetrieval) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{5}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{5}
 }
 func (m *PieceRetrieval) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrieval.Unmarshal(m, b)
@@ -485,7 +485,7 @@ func (m *PieceRetrieval_PieceData) Reset()         { *m = PieceRetrieval_PieceDa
 func (m *PieceRetrieval_PieceData) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrieval_PieceData) ProtoMessage()    {}
 func (*PieceRetrieval_PieceData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{5, 0}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{5, 0}
 }
 func (m *PieceRetrieval_PieceData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrieval_PieceData.Unmarshal(m, b)
@@ -538,7 +538,7 @@ func (m *PieceRetrievalStream) Reset()         { *m = PieceRetrievalStream{} }
 func (m *PieceRetrievalStream) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrievalStream) ProtoMessage()    {}
 func (*PieceRetrievalStream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{6}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{6}
 }
 func (m *PieceRetrievalStream) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrievalStream.Unmarshal(m, b)
@@ -583,7 +583,7 @@ func (m *PieceDelete) Reset()         { *m = PieceDelete{} }
 func (m *PieceDelete) String() string { return proto.CompactTextString(m) }
 func (*PieceDelete) ProtoMessage()    {}
 func (*PieceDelete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{7}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{7}
 }
 func (m *PieceDelete) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceDelete.Unmarshal(m, b)
@@ -621,7 +621,7 @@ func (m *PieceDeleteSummary) Reset()         { *m = PieceDeleteSummary{} }
 func (m *PieceDeleteSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceDeleteSummary) ProtoMessage()    {}
 func (*PieceDeleteSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{8}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{8}
 }
 func (m *PieceDeleteSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceDeleteSummary.Unmarshal(m, b)
@@ -660,7 +660,7 @@ func (m *PieceStoreSummary) Reset()         { *m = PieceStoreSummary{} }
 func (m *PieceStoreSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceStoreSummary) ProtoMessage()    {}
 func (*PieceStoreSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{9}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{9}
 }
 func (m *PieceStoreSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStoreSummary.Unmarshal(m, b)
@@ -704,7 +704,7 @@ func (m *StatsReq) Reset()         { *m = StatsReq{} }
 func (m *StatsReq) String() string { return proto.CompactTextString(m) }
 func (*StatsReq) ProtoMessage()    {}
 func (*StatsReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{10}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{10}
 }
 func (m *StatsReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatsReq.Unmarshal(m, b)
@@ -736,7 +736,7 @@ func (m *StatSummary) Reset()         { *m = StatSummary{} }
 func (m *StatSummary) String() string { return proto.CompactTextString(m) }
 func (*StatSummary) ProtoMessage()    {}
 func (*StatSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piece_store_c23e358761ca6810, []int{11}
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{11}
 }
 func (m *StatSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummary.Unmarshal(m, b)
@@ -770,6 +770,98 @@ func (m *StatSummary) GetAvailableSpace() int64 {
 	return 0
 }
 
+type RetainRequest struct {
+	CreationUnixSec      int64    `protobuf:"varint,1,opt,name=creation_unix_sec,json=creationUnixSec,proto3" json:"creation_unix_sec,omitempty"`
+	Filter               []byte   `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetainRequest) Reset()         { *m = RetainRequest{} }
+func (m *RetainRequest) String() string { return proto.CompactTextString(m) }
+func (*RetainRequest) ProtoMessage()    {}
+func (*RetainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{12}
+}
+func (m *RetainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetainRequest.Unmarshal(m, b)
+}
+func (m *RetainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetainRequest.Marshal(b, m, deterministic)
+}
+func (dst *RetainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetainRequest.Merge(dst, src)
+}
+func (m *RetainRequest) XXX_Size() int {
+	return xxx_messageInfo_RetainRequest.Size(m)
+}
+func (m *RetainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetainRequest proto.InternalMessageInfo
+
+func (m *RetainRequest) GetCreationUnixSec() int64 {
+	if m != nil {
+		return m.CreationUnixSec
+	}
+	return 0
+}
+
+func (m *RetainRequest) GetFilter() []byte {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *RetainRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type RetainSummary struct {
+	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetainSummary) Reset()         { *m = RetainSummary{} }
+func (m *RetainSummary) String() string { return proto.CompactTextString(m) }
+func (*RetainSummary) ProtoMessage()    {}
+func (*RetainSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piece_store_02b6858adb47b720, []int{13}
+}
+func (m *RetainSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetainSummary.Unmarshal(m, b)
+}
+func (m *RetainSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetainSummary.Marshal(b, m, deterministic)
+}
+func (dst *RetainSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetainSummary.Merge(dst, src)
+}
+func (m *RetainSummary) XXX_Size() int {
+	return xxx_messageInfo_RetainSummary.Size(m)
+}
+func (m *RetainSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetainSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetainSummary proto.InternalMessageInfo
+
+func (m *RetainSummary) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*PayerBandwidthAllocation)(nil), "piecestoreroutes.PayerBandwidthAllocation")
 	proto.RegisterType((*PayerBandwidthAllocation_Data)(nil), "piecestoreroutes.PayerBandwidthAllocation.Data")
@@ -787,6 +879,8 @@ func init() {
 	proto.RegisterType((*PieceStoreSummary)(nil), "piecestoreroutes.PieceStoreSummary")
 	proto.RegisterType((*StatsReq)(nil), "piecestoreroutes.StatsReq")
 	proto.RegisterType((*StatSummary)(nil), "piecestoreroutes.StatSummary")
+	proto.RegisterType((*RetainRequest)(nil), "piecestoreroutes.RetainRequest")
+	proto.RegisterType((*RetainSummary)(nil), "piecestoreroutes.RetainSummary")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -806,6 +900,7 @@ type PieceStoreRoutesClient interface {
 	Store(ctx context.Context, opts ...grpc.CallOption) (PieceStoreRoutes_StoreClient, error)
 	Delete(ctx context.Context, in *PieceDelete, opts ...grpc.CallOption) (*PieceDeleteSummary, error)
 	Stats(ctx context.Context, in *StatsReq, opts ...grpc.CallOption) (*StatSummary, error)
+	Retain(ctx context.Context, in *RetainRequest, opts ...grpc.CallOption) (*RetainSummary, error)
 }
 
 type pieceStoreRoutesClient struct {
@@ -908,6 +1003,15 @@ func (c *pieceStoreRoutesClient) Stats(ctx context.Context, in *StatsReq, opts .
 	return out, nil
 }
 
+func (c *pieceStoreRoutesClient) Retain(ctx context.Context, in *RetainRequest, opts ...grpc.CallOption) (*RetainSummary, error) {
+	out := new(RetainSummary)
+	err := c.cc.Invoke(ctx, "/piecestoreroutes.PieceStoreRoutes/Retain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PieceStoreRoutesServer is the server API for PieceStoreRoutes service.
 type PieceStoreRoutesServer interface {
 	Piece(context.Context, *PieceId) (*PieceSummary, error)
@@ -915,6 +1019,7 @@ type PieceStoreRoutesServer interface {
 	Store(PieceStoreRoutes_StoreServer) error
 	Delete(context.Context, *PieceDelete) (*PieceDeleteSummary, error)
 	Stats(context.Context, *StatsReq) (*StatSummary, error)
+	Retain(context.Context, *RetainRequest) (*RetainSummary, error)
 }
 
 func RegisterPieceStoreRoutesServer(s *grpc.Server, srv PieceStoreRoutesServer) {
@@ -1027,6 +1132,24 @@ func _PieceStoreRoutes_Stats_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PieceStoreRoutes_Retain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PieceStoreRoutesServer).Retain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestoreroutes.PieceStoreRoutes/Retain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PieceStoreRoutesServer).Retain(ctx, req.(*RetainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "piecestoreroutes.PieceStoreRoutes",
 	HandlerType: (*PieceStoreRoutesServer)(nil),
@@ -1043,6 +1166,10 @@ var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Stats",
 			Handler:    _PieceStoreRoutes_Stats_Handler,
 		},
+		{
+			MethodName: "Retain",
+			Handler:    _PieceStoreRoutes_Retain_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1060,51 +1187,55 @@ var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 	Metadata: "piece_store.proto",
 }
 
-func init() { proto.RegisterFile("piece_store.proto", fileDescriptor_piece_store_c23e358761ca6810) }
-
-var fileDescriptor_piece_store_c23e358761ca6810 = []byte{
-	// 676 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdb, 0x4e, 0xdb, 0x40,
-	0x10, 0xc5, 0xce, 0x8d, 0x4c, 0x02, 0x85, 0x05, 0x21, 0xc7, 0x82, 0x2a, 0x32, 0x08, 0x45, 0x54,
-	0x8a, 0x2a, 0xfa, 0x05, 0xad, 0x22, 0xb5, 0xbc, 0x50, 0xb4, 0x16, 0x2f, 0x95, 0xaa, 0x68, 0x63,
-	0x0f, 0x74, 0x25, 0x5f, 0x52, 0xef, 0x86, 0x06, 0x1e, 0xfb, 0x15, 0x95, 0xfa, 0xda, 0x2f, 0xe9,
-	0x2f, 0xf5, 0x07, 0x2a, 0xef, 0xfa, 0x12, 0x48, 0x1c, 0x5e, 0xda, 0xb7, 0x9d, 0x33, 0xbb, 0x67,
-	0xce, 0xcc, 0x99, 0x38, 0xb0, 0x3b, 0xe5, 0xe8, 0xe1, 0x58, 0xc8, 0x38, 0xc1, 0xe1, 0x34, 0x89,
-	0x65, 0x4c, 0x76, 0x14, 0xa4, 0x90, 0x24, 0x9e, 0x49, 0x14, 0xce, 0x1f, 0x03, 0xac, 0x2b, 0x76,
-	0x8f, 0xc9, 0x3b, 0x16, 0xf9, 0xdf, 0xb8, 0x2f, 0xbf, 0xbc, 0x0d, 0x82, 0xd8, 0x63, 0x92, 0xc7,
-	0x11, 0x39, 0x84, 0xb6, 0xe0, 0xb7, 0x11, 0x93, 0xb3, 0x04, 0x2d, 0xa3, 0x6f, 0x0c, 0xba, 0xb4,
-	0x04, 0x08, 0x81, 0xba, 0xcf, 0x24, 0xb3, 0x4c, 0x95, 0x50, 0x67, 0xfb, 0x97, 0x01, 0xf5, 0x11,
-	0x93, 0x8c, 0xec, 0x43, 0x63, 0x9a, 0xd2, 0x66, 0xcf, 0x74, 0x40, 0x0e, 0xa0, 0x99, 0x60, 0x24,
-	0x31, 0xc9, 0x1e, 0x65, 0x11, 0xe9, 0xc1, 0x66, 0xc8, 0xe6, 0x63, 0xc1, 0x1f, 0xd0, 0xaa, 0xf5,
-	0x8d, 0x41, 0x8d, 0xb6, 0x42, 0x36, 0x77, 0xf9, 0x03, 0x92, 0x21, 0xec, 0xe1, 0x7c, 0xca, 0x13,
-	0xa5, 0x68, 0x3c, 0x8b, 0xf8, 0x7c, 0x2c, 0xd0, 0xb3, 0xea, 0xea, 0xd6, 0x6e, 0x99, 0xba, 0x8e,
-	0xf8, 0xdc, 0x45, 0x8f, 0x1c, 0xc3, 0x96, 0xc0, 0x84, 0xb3, 0x60, 0x1c, 0xcd, 0xc2, 0x09, 0x26,
-	0x56, 0xa3, 0x6f, 0x0c, 0xda, 0xb4, 0xab, 0xc1, 0x4b, 0x85, 0x39, 0xbf, 0x0d, 0xe8, 0x51, 0x55,
-	0xfa, 0xdf, 0xb4, 0x2d, 0xb2, 0xae, 0xaf, 0x61, 0x47, 0x35, 0x3a, 0x66, 0x05, 0x9b, 0x22, 0xe8,
-	0x9c, 0x9f, 0x0d, 0x9f, 0x8e, 0x7e, 0x58, 0x35, 0x76, 0xfa, 0x42, 0x71, 0x2c, 0x08, 0xda, 0x87,
-	0x86, 0x8c, 0x25, 0x0b, 0x54, 0xcd, 0x1a, 0xd5, 0x81, 0xf3, 0xc3, 0x04, 0xb8, 0x4a, 0x49, 0xdd,
-	0x94, 0x94, 0x7c, 0x86, 0xbd, 0x49, 0x4e, 0xb6, 0x54, 0xfe, 0xd5, 0x72, 0xf9, 0xca, 0xfe, 0xe9,
-	0x2a, 0x1e, 0x32, 0x82, 0xb6, 0xa2, 0x28, 0x7a, 0xef, 0x9c, 0x9f, 0xae, 0xe8, 0xa9, 0xd0, 0xa3,
-	0x8f, 0xe9, 0x54, 0x68, 0xf9, 0xd0, 0x46, 0x68, 0x17, 0x38, 0xd9, 0x06, 0x93, 0xfb, 0x4a, 0x60,
-	0x9b, 0x9a, 0xdc, 0xaf, 0xb2, 0xda, 0xac, 0xb2, 0xda, 0x82, 0x96, 0x17, 0x47, 0x12, 0x23, 0xa9,
-	0x96, 0xa6, 0x4b, 0xf3, 0xd0, 0xe9, 0x41, 0x4b, 0x95, 0xb9, 0xf0, 0x9f, 0x16, 0x71, 0x26, 0xd0,
-	0xd5, 0x22, 0x67, 0x61, 0xc8, 0x92, 0xfb, 0x25, 0x11, 0x04, 0xea, 0x6a, 0x0d, 0x75, 0x55, 0x75,
-	0xae, 0x12, 0x56, 0xab, 0x10, 0xe6, 0x7c, 0x37, 0x61, 0x5b, 0x15, 0xa1, 0x28, 0x13, 0x8e, 0x77,
-	0x2c, 0xf8, 0xdf, 0xee, 0x7c, 0xc8, 0xdc, 0x19, 0x95, 0xee, 0x9c, 0x55, 0xb8, 0x53, 0x68, 0x5a,
-	0x72, 0x28, 0x3d, 0xda, 0xef, 0xd7, 0x39, 0xb4, 0x6a, 0x38, 0x07, 0xd0, 0x8c, 0x6f, 0x6e, 0x04,
-	0xca, 0x6c, 0x1e, 0x59, 0xe4, 0x8c, 0x60, 0xff, 0x71, 0x3d, 0x57, 0x26, 0xc8, 0xc2, 0x82, 0xc3,
-	0x58, 0xe0, 0x58, 0x70, 0xd2, 0x7c, 0xec, 0xe4, 0x11, 0x74, 0xb4, 0x1c, 0x0c, 0x50, 0xe2, 0x92,
-	0x9b, 0x43, 0x20, 0x0b, 0xe9, 0xdc, 0x53, 0x0b, 0x5a, 0x21, 0x0a, 0xc1, 0x6e, 0x31, 0xbb, 0x9a,
-	0x87, 0x8e, 0x0b, 0xbb, 0xe5, 0x8a, 0x3e, 0x7b, 0x9d, 0x9c, 0xc0, 0x96, 0xfa, 0xad, 0x51, 0xf4,
-	0x90, 0xdf, 0xa1, 0x9f, 0x35, 0xfe, 0x18, 0x74, 0x00, 0x36, 0x5d, 0xc9, 0xa4, 0xa0, 0xf8, 0xd5,
-	0x71, 0xa1, 0x93, 0x9e, 0x73, 0xea, 0x43, 0x68, 0xcf, 0x04, 0xfa, 0xee, 0x94, 0x79, 0x79, 0xc7,
-	0x25, 0x40, 0x4e, 0x61, 0x9b, 0xdd, 0x31, 0x1e, 0xb0, 0x49, 0x80, 0xfa, 0x8a, 0xe6, 0x7f, 0x82,
-	0x9e, 0xff, 0xac, 0xc1, 0x4e, 0x29, 0x9b, 0x2a, 0x33, 0xc9, 0x08, 0x1a, 0x0a, 0x23, 0xbd, 0x0a,
-	0xa3, 0x2f, 0x7c, 0xfb, 0x65, 0xd5, 0x2f, 0x54, 0xcb, 0x73, 0x36, 0xc8, 0x27, 0xd8, 0xcc, 0x0c,
-	0x42, 0xd2, 0x7f, 0x6e, 0x63, 0xec, 0xd3, 0xe7, 0x6e, 0x68, 0x8f, 0x9d, 0x8d, 0x81, 0xf1, 0xda,
-	0x20, 0x97, 0xd0, 0xd0, 0x9f, 0xa6, 0xc3, 0x75, 0x1f, 0x0a, 0xfb, 0x78, 0x5d, 0xb6, 0x50, 0x3a,
-	0x30, 0xc8, 0x47, 0x68, 0x66, 0x6b, 0x70, 0x54, 0xf1, 0x44, 0xa7, 0xed, 0x93, 0xb5, 0xe9, 0xb2,
-	0xf9, 0x51, 0x2a, 0x90, 0x49, 0x41, 0xec, 0xe5, 0x07, 0xb9, 0xa3, 0xf6, 0xd1, 0xea, 0x5c, 0xc1,
-	0x32, 0x69, 0xaa, 0xff, 0xd6, 0x37, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x6c, 0x0c, 0xa2, 0x5f,
-	0x70, 0x07, 0x00, 0x00,
+func init() { proto.RegisterFile("piece_store.proto", fileDescriptor_piece_store_02b6858adb47b720) }
+
+var fileDescriptor_piece_store_02b6858adb47b720 = []byte{
+	// 740 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xdb, 0x6a, 0xdb, 0x4c,
+	0x10, 0x8e, 0x7c, 0x8c, 0xc7, 0xce, 0xc1, 0x9b, 0x10, 0x64, 0x91, 0xfc, 0xbf, 0x51, 0x42, 0x70,
+	0x53, 0x30, 0x25, 0x7d, 0x82, 0x16, 0x43, 0x1b, 0x28, 0x69, 0x58, 0x91, 0x9b, 0x42, 0x31, 0x6b,
+	0x69, 0x92, 0x2e, 0xc8, 0x92, 0xa3, 0x5d, 0xa7, 0x4e, 0x2e, 0xfb, 0x14, 0x7d, 0x80, 0x3e, 0x49,
+	0xdf, 0xa6, 0xd7, 0x7d, 0x81, 0xe2, 0x5d, 0x1d, 0x7c, 0x92, 0x7d, 0xd3, 0xde, 0xed, 0xcc, 0xec,
+	0x7e, 0x73, 0xf8, 0x3e, 0x0d, 0x82, 0xe6, 0x88, 0xa3, 0x8b, 0x7d, 0x21, 0xc3, 0x08, 0xbb, 0xa3,
+	0x28, 0x94, 0x21, 0xd9, 0x57, 0x2e, 0xe5, 0x89, 0xc2, 0xb1, 0x44, 0x61, 0xff, 0x36, 0xc0, 0xbc,
+	0x61, 0x4f, 0x18, 0xbd, 0x65, 0x81, 0xf7, 0x95, 0x7b, 0xf2, 0xcb, 0x1b, 0xdf, 0x0f, 0x5d, 0x26,
+	0x79, 0x18, 0x90, 0x63, 0xa8, 0x09, 0x7e, 0x1f, 0x30, 0x39, 0x8e, 0xd0, 0x34, 0xda, 0x46, 0xa7,
+	0x41, 0x33, 0x07, 0x21, 0x50, 0xf2, 0x98, 0x64, 0x66, 0x41, 0x05, 0xd4, 0xd9, 0xfa, 0x61, 0x40,
+	0xa9, 0xc7, 0x24, 0x23, 0x87, 0x50, 0x1e, 0x4d, 0x61, 0xe3, 0x67, 0xda, 0x20, 0x47, 0x50, 0x89,
+	0x30, 0x90, 0x18, 0xc5, 0x8f, 0x62, 0x8b, 0xb4, 0x60, 0x7b, 0xc8, 0x26, 0x7d, 0xc1, 0x9f, 0xd1,
+	0x2c, 0xb6, 0x8d, 0x4e, 0x91, 0x56, 0x87, 0x6c, 0xe2, 0xf0, 0x67, 0x24, 0x5d, 0x38, 0xc0, 0xc9,
+	0x88, 0x47, 0xaa, 0xa2, 0xfe, 0x38, 0xe0, 0x93, 0xbe, 0x40, 0xd7, 0x2c, 0xa9, 0x5b, 0xcd, 0x2c,
+	0x74, 0x1b, 0xf0, 0x89, 0x83, 0x2e, 0x39, 0x85, 0x1d, 0x81, 0x11, 0x67, 0x7e, 0x3f, 0x18, 0x0f,
+	0x07, 0x18, 0x99, 0xe5, 0xb6, 0xd1, 0xa9, 0xd1, 0x86, 0x76, 0x5e, 0x2b, 0x9f, 0xfd, 0xd3, 0x80,
+	0x16, 0x55, 0xa9, 0xff, 0x4e, 0xdb, 0x22, 0xee, 0xfa, 0x16, 0xf6, 0x55, 0xa3, 0x7d, 0x96, 0xa2,
+	0x29, 0x80, 0xfa, 0xe5, 0x45, 0x77, 0x71, 0xf4, 0xdd, 0xbc, 0xb1, 0xd3, 0x3d, 0x85, 0x31, 0x53,
+	0xd0, 0x21, 0x94, 0x65, 0x28, 0x99, 0xaf, 0x72, 0x16, 0xa9, 0x36, 0xec, 0xef, 0x05, 0x80, 0x9b,
+	0x29, 0xa8, 0x33, 0x05, 0x25, 0x9f, 0xe1, 0x60, 0x90, 0x80, 0x2d, 0xa5, 0x7f, 0xb9, 0x9c, 0x3e,
+	0xb7, 0x7f, 0xba, 0x0a, 0x87, 0xf4, 0xa0, 0xa6, 0x20, 0xd2, 0xde, 0xeb, 0x97, 0xe7, 0x2b, 0x7a,
+	0x4a, 0xeb, 0xd1, 0xc7, 0xe9, 0x54, 0x68, 0xf6, 0xd0, 0x42, 0xa8, 0xa5, 0x7e, 0xb2, 0x0b, 0x05,
+	0xee, 0xa9, 0x02, 0x6b, 0xb4, 0xc0, 0xbd, 0x3c, 0xaa, 0x0b, 0x79, 0x54, 0x9b, 0x50, 0x75, 0xc3,
+	0x40, 0x62, 0x20, 0x95, 0x68, 0x1a, 0x34, 0x31, 0xed, 0x16, 0x54, 0x55, 0x9a, 0x2b, 0x6f, 0x31,
+	0x89, 0x3d, 0x80, 0x86, 0x2e, 0x72, 0x3c, 0x1c, 0xb2, 0xe8, 0x69, 0xa9, 0x08, 0x02, 0x25, 0x25,
+	0x43, 0x9d, 0x55, 0x9d, 0xf3, 0x0a, 0x2b, 0xe6, 0x14, 0x66, 0x7f, 0x2b, 0xc0, 0xae, 0x4a, 0x42,
+	0x51, 0x46, 0x1c, 0x1f, 0x99, 0xff, 0xaf, 0xd9, 0x79, 0x1f, 0xb3, 0xd3, 0xcb, 0xd8, 0xb9, 0xc8,
+	0x61, 0x27, 0xad, 0x69, 0x89, 0xa1, 0xe9, 0xd1, 0x7a, 0xb7, 0x8e, 0xa1, 0x55, 0xc3, 0x39, 0x82,
+	0x4a, 0x78, 0x77, 0x27, 0x50, 0xc6, 0xf3, 0x88, 0x2d, 0xbb, 0x07, 0x87, 0xf3, 0xf9, 0x1c, 0x19,
+	0x21, 0x1b, 0xa6, 0x18, 0xc6, 0x0c, 0xc6, 0x0c, 0x93, 0x85, 0x79, 0x26, 0x4f, 0xa0, 0xae, 0xcb,
+	0x41, 0x1f, 0x25, 0x2e, 0xb1, 0xd9, 0x05, 0x32, 0x13, 0x4e, 0x38, 0x35, 0xa1, 0x3a, 0x44, 0x21,
+	0xd8, 0x3d, 0xc6, 0x57, 0x13, 0xd3, 0x76, 0xa0, 0x99, 0x49, 0x74, 0xe3, 0x75, 0x72, 0x06, 0x3b,
+	0xea, 0x5b, 0xa3, 0xe8, 0x22, 0x7f, 0x44, 0x2f, 0x6e, 0x7c, 0xde, 0x69, 0x03, 0x6c, 0x3b, 0x92,
+	0x49, 0x41, 0xf1, 0xc1, 0x76, 0xa0, 0x3e, 0x3d, 0x27, 0xd0, 0xc7, 0x50, 0x1b, 0x0b, 0xf4, 0x9c,
+	0x11, 0x73, 0x93, 0x8e, 0x33, 0x07, 0x39, 0x87, 0x5d, 0xf6, 0xc8, 0xb8, 0xcf, 0x06, 0x3e, 0xea,
+	0x2b, 0x1a, 0x7f, 0xc1, 0x6b, 0x3f, 0xc0, 0x0e, 0x45, 0xc9, 0x78, 0x40, 0xf1, 0x61, 0x8c, 0x42,
+	0x92, 0x0b, 0x68, 0xba, 0x11, 0x2e, 0xc8, 0x51, 0xc3, 0xef, 0x25, 0x81, 0xe4, 0x2b, 0x39, 0x82,
+	0xca, 0x1d, 0xf7, 0x67, 0x76, 0xae, 0xb6, 0xe6, 0xb7, 0x5c, 0x71, 0x61, 0xcb, 0xd9, 0x2f, 0x92,
+	0x94, 0x1b, 0x87, 0x74, 0xf9, 0xab, 0x08, 0xfb, 0xd9, 0x50, 0xa9, 0x92, 0x1a, 0xe9, 0x41, 0x59,
+	0xf9, 0x48, 0x2b, 0x47, 0x86, 0x57, 0x9e, 0xf5, 0x5f, 0x4e, 0x28, 0x4e, 0x69, 0x6f, 0x91, 0x4f,
+	0xb0, 0x1d, 0xcb, 0x07, 0x49, 0x7b, 0x93, 0x9e, 0xad, 0xf3, 0x4d, 0x37, 0xb4, 0x02, 0xed, 0xad,
+	0x8e, 0xf1, 0xca, 0x20, 0xd7, 0x50, 0xd6, 0x8b, 0xf3, 0x78, 0xdd, 0x1a, 0xb3, 0x4e, 0xd7, 0x45,
+	0xd3, 0x4a, 0x3b, 0x06, 0xf9, 0x08, 0x95, 0x58, 0xa4, 0x27, 0x39, 0x4f, 0x74, 0xd8, 0x3a, 0x5b,
+	0x1b, 0xce, 0x9a, 0xef, 0x4d, 0x0b, 0x64, 0x52, 0x10, 0x6b, 0xf9, 0x41, 0xa2, 0x37, 0xeb, 0x64,
+	0x75, 0x2c, 0x43, 0xf9, 0x00, 0x15, 0x4d, 0x24, 0xf9, 0x7f, 0xd5, 0x96, 0x99, 0x51, 0x95, 0x95,
+	0x7b, 0x21, 0x45, 0x1b, 0x54, 0xd4, 0x7f, 0xc4, 0xeb, 0x3f, 0x03, 0x00, 0x96, 0xcb, 0xdc, 0xd1,
+	0x5c, 0x08, 0x00, 0x00,
 }
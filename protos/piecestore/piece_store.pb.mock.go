@@ -75,6 +75,24 @@ func (mr *MockPieceStoreRoutesClientMockRecorder) Piece(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Piece", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Piece), varargs...)
 }
 
+// Retain mocks base method
+func (m *MockPieceStoreRoutesClient) Retain(arg0 context.Context, arg1 *RetainRequest, arg2 ...grpc.CallOption) (*RetainSummary, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Retain", varargs...)
+	ret0, _ := ret[0].(*RetainSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retain indicates an expected call of Retain
+func (mr *MockPieceStoreRoutesClientMockRecorder) Retain(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retain", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Retain), varargs...)
+}
+
 // Retrieve mocks base method
 func (m *MockPieceStoreRoutesClient) Retrieve(arg0 context.Context, arg1 ...grpc.CallOption) (PieceStoreRoutes_RetrieveClient, error) {
 	varargs := []interface{}{arg0}
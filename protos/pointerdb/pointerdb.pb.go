@@ -28,20 +28,28 @@ type RedundancyScheme_SchemeType int32
 
 const (
 	RedundancyScheme_RS RedundancyScheme_SchemeType = 0
+	// plain copies of the data, min_req is 1
+	RedundancyScheme_REPLICATION RedundancyScheme_SchemeType = 1
+	// systematic Reed-Solomon with a Cauchy matrix
+	RedundancyScheme_CAUCHY RedundancyScheme_SchemeType = 2
 )
 
 var RedundancyScheme_SchemeType_name = map[int32]string{
 	0: "RS",
+	1: "REPLICATION",
+	2: "CAUCHY",
 }
 var RedundancyScheme_SchemeType_value = map[string]int32{
-	"RS": 0,
+	"RS":          0,
+	"REPLICATION": 1,
+	"CAUCHY":      2,
 }
 
 func (x RedundancyScheme_SchemeType) String() string {
 	return proto.EnumName(RedundancyScheme_SchemeType_name, int32(x))
 }
 func (RedundancyScheme_SchemeType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{0, 0}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{0, 0}
 }
 
 type EncryptionScheme_EncryptionType int32
@@ -64,7 +72,7 @@ func (x EncryptionScheme_EncryptionType) String() string {
 	return proto.EnumName(EncryptionScheme_EncryptionType_name, int32(x))
 }
 func (EncryptionScheme_EncryptionType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{1, 0}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{1, 0}
 }
 
 type Pointer_DataType int32
@@ -87,12 +95,12 @@ func (x Pointer_DataType) String() string {
 	return proto.EnumName(Pointer_DataType_name, int32(x))
 }
 func (Pointer_DataType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{4, 0}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{4, 0}
 }
 
 type RedundancyScheme struct {
 	Type RedundancyScheme_SchemeType `protobuf:"varint,1,opt,name=type,proto3,enum=pointerdb.RedundancyScheme_SchemeType" json:"type,omitempty"`
-	// these values apply to RS encoding
+	// these values apply to all scheme types
 	MinReq               int32    `protobuf:"varint,2,opt,name=min_req,json=minReq,proto3" json:"min_req,omitempty"`
 	Total                int32    `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
 	RepairThreshold      int32    `protobuf:"varint,4,opt,name=repair_threshold,json=repairThreshold,proto3" json:"repair_threshold,omitempty"`
@@ -107,7 +115,7 @@ func (m *RedundancyScheme) Reset()         { *m = RedundancyScheme{} }
 func (m *RedundancyScheme) String() string { return proto.CompactTextString(m) }
 func (*RedundancyScheme) ProtoMessage()    {}
 func (*RedundancyScheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{0}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{0}
 }
 func (m *RedundancyScheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RedundancyScheme.Unmarshal(m, b)
@@ -182,7 +190,7 @@ func (m *EncryptionScheme) Reset()         { *m = EncryptionScheme{} }
 func (m *EncryptionScheme) String() string { return proto.CompactTextString(m) }
 func (*EncryptionScheme) ProtoMessage()    {}
 func (*EncryptionScheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{1}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{1}
 }
 func (m *EncryptionScheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EncryptionScheme.Unmarshal(m, b)
@@ -235,7 +243,7 @@ func (m *RemotePiece) Reset()         { *m = RemotePiece{} }
 func (m *RemotePiece) String() string { return proto.CompactTextString(m) }
 func (*RemotePiece) ProtoMessage()    {}
 func (*RemotePiece) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{2}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{2}
 }
 func (m *RemotePiece) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemotePiece.Unmarshal(m, b)
@@ -283,7 +291,7 @@ func (m *RemoteSegment) Reset()         { *m = RemoteSegment{} }
 func (m *RemoteSegment) String() string { return proto.CompactTextString(m) }
 func (*RemoteSegment) ProtoMessage()    {}
 func (*RemoteSegment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{3}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{3}
 }
 func (m *RemoteSegment) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoteSegment.Unmarshal(m, b)
@@ -332,23 +340,29 @@ func (m *RemoteSegment) GetMerkleRoot() []byte {
 }
 
 type Pointer struct {
-	Type                 Pointer_DataType     `protobuf:"varint,1,opt,name=type,proto3,enum=pointerdb.Pointer_DataType" json:"type,omitempty"`
-	InlineSegment        []byte               `protobuf:"bytes,3,opt,name=inline_segment,json=inlineSegment,proto3" json:"inline_segment,omitempty"`
-	Remote               *RemoteSegment       `protobuf:"bytes,4,opt,name=remote,proto3" json:"remote,omitempty"`
-	Size                 int64                `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
-	CreationDate         *timestamp.Timestamp `protobuf:"bytes,6,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
-	ExpirationDate       *timestamp.Timestamp `protobuf:"bytes,7,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
-	Metadata             []byte               `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Type           Pointer_DataType     `protobuf:"varint,1,opt,name=type,proto3,enum=pointerdb.Pointer_DataType" json:"type,omitempty"`
+	InlineSegment  []byte               `protobuf:"bytes,3,opt,name=inline_segment,json=inlineSegment,proto3" json:"inline_segment,omitempty"`
+	Remote         *RemoteSegment       `protobuf:"bytes,4,opt,name=remote,proto3" json:"remote,omitempty"`
+	Size           int64                `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	CreationDate   *timestamp.Timestamp `protobuf:"bytes,6,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	ExpirationDate *timestamp.Timestamp `protobuf:"bytes,7,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
+	Metadata       []byte               `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// paths of the chunk segments c/<id> of the project holding the data of
+	// this segment, in order. The data of a chunk is shared by all the
+	// segments referencing it.
+	References []string `protobuf:"bytes,9,rep,name=references,proto3" json:"references,omitempty"`
+	// number of references to this chunk segment, maintained by the server
+	ReferenceCount       int64    `protobuf:"varint,10,opt,name=reference_count,json=referenceCount,proto3" json:"reference_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Pointer) Reset()         { *m = Pointer{} }
 func (m *Pointer) String() string { return proto.CompactTextString(m) }
 func (*Pointer) ProtoMessage()    {}
 func (*Pointer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{4}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{4}
 }
 func (m *Pointer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pointer.Unmarshal(m, b)
@@ -417,11 +431,28 @@ func (m *Pointer) GetMetadata() []byte {
 	return nil
 }
 
+func (m *Pointer) GetReferences() []string {
+	if m != nil {
+		return m.References
+	}
+	return nil
+}
+
+func (m *Pointer) GetReferenceCount() int64 {
+	if m != nil {
+		return m.ReferenceCount
+	}
+	return 0
+}
+
 // PutRequest is a request message for the Put rpc call
 type PutRequest struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Pointer              *Pointer `protobuf:"bytes,2,opt,name=pointer,proto3" json:"pointer,omitempty"`
-	APIKey               []byte   `protobuf:"bytes,3,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	Path    string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Pointer *Pointer `protobuf:"bytes,2,opt,name=pointer,proto3" json:"pointer,omitempty"`
+	APIKey  []byte   `protobuf:"bytes,3,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	// segment referencing the chunk segments of the request, which authorizes
+	// them instead of their own paths
+	Referrer             string   `protobuf:"bytes,4,opt,name=referrer,proto3" json:"referrer,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -431,7 +462,7 @@ func (m *PutRequest) Reset()         { *m = PutRequest{} }
 func (m *PutRequest) String() string { return proto.CompactTextString(m) }
 func (*PutRequest) ProtoMessage()    {}
 func (*PutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{5}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{5}
 }
 func (m *PutRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutRequest.Unmarshal(m, b)
@@ -472,10 +503,20 @@ func (m *PutRequest) GetAPIKey() []byte {
 	return nil
 }
 
+func (m *PutRequest) GetReferrer() string {
+	if m != nil {
+		return m.Referrer
+	}
+	return ""
+}
+
 // GetRequest is a request message for the Get rpc call
 type GetRequest struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	APIKey               []byte   `protobuf:"bytes,2,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	Path   string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	APIKey []byte `protobuf:"bytes,2,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	// segment referencing the chunk segments of the request, which authorizes
+	// them instead of their own paths
+	Referrer             string   `protobuf:"bytes,3,opt,name=referrer,proto3" json:"referrer,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -485,7 +526,7 @@ func (m *GetRequest) Reset()         { *m = GetRequest{} }
 func (m *GetRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRequest) ProtoMessage()    {}
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{6}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{6}
 }
 func (m *GetRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
@@ -519,6 +560,13 @@ func (m *GetRequest) GetAPIKey() []byte {
 	return nil
 }
 
+func (m *GetRequest) GetReferrer() string {
+	if m != nil {
+		return m.Referrer
+	}
+	return ""
+}
+
 // ListRequest is a request message for the List rpc call
 type ListRequest struct {
 	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
@@ -537,7 +585,7 @@ func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{7}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{7}
 }
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
@@ -617,7 +665,7 @@ func (m *PutResponse) Reset()         { *m = PutResponse{} }
 func (m *PutResponse) String() string { return proto.CompactTextString(m) }
 func (*PutResponse) ProtoMessage()    {}
 func (*PutResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{8}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{8}
 }
 func (m *PutResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutResponse.Unmarshal(m, b)
@@ -649,7 +697,7 @@ func (m *GetResponse) Reset()         { *m = GetResponse{} }
 func (m *GetResponse) String() string { return proto.CompactTextString(m) }
 func (*GetResponse) ProtoMessage()    {}
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{9}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{9}
 }
 func (m *GetResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
@@ -689,7 +737,7 @@ func (m *ListResponse) Reset()         { *m = ListResponse{} }
 func (m *ListResponse) String() string { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()    {}
 func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{10}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{10}
 }
 func (m *ListResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
@@ -736,7 +784,7 @@ func (m *ListResponse_Item) Reset()         { *m = ListResponse_Item{} }
 func (m *ListResponse_Item) String() string { return proto.CompactTextString(m) }
 func (*ListResponse_Item) ProtoMessage()    {}
 func (*ListResponse_Item) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{10, 0}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{10, 0}
 }
 func (m *ListResponse_Item) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListResponse_Item.Unmarshal(m, b)
@@ -789,7 +837,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{11}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{11}
 }
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
@@ -834,7 +882,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_138b3541b1f95735, []int{12}
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{12}
 }
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
@@ -854,191 +902,1145 @@ func (m *DeleteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*RedundancyScheme)(nil), "pointerdb.RedundancyScheme")
-	proto.RegisterType((*EncryptionScheme)(nil), "pointerdb.EncryptionScheme")
-	proto.RegisterType((*RemotePiece)(nil), "pointerdb.RemotePiece")
-	proto.RegisterType((*RemoteSegment)(nil), "pointerdb.RemoteSegment")
-	proto.RegisterType((*Pointer)(nil), "pointerdb.Pointer")
-	proto.RegisterType((*PutRequest)(nil), "pointerdb.PutRequest")
-	proto.RegisterType((*GetRequest)(nil), "pointerdb.GetRequest")
-	proto.RegisterType((*ListRequest)(nil), "pointerdb.ListRequest")
-	proto.RegisterType((*PutResponse)(nil), "pointerdb.PutResponse")
-	proto.RegisterType((*GetResponse)(nil), "pointerdb.GetResponse")
-	proto.RegisterType((*ListResponse)(nil), "pointerdb.ListResponse")
-	proto.RegisterType((*ListResponse_Item)(nil), "pointerdb.ListResponse.Item")
-	proto.RegisterType((*DeleteRequest)(nil), "pointerdb.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "pointerdb.DeleteResponse")
-	proto.RegisterEnum("pointerdb.RedundancyScheme_SchemeType", RedundancyScheme_SchemeType_name, RedundancyScheme_SchemeType_value)
-	proto.RegisterEnum("pointerdb.EncryptionScheme_EncryptionType", EncryptionScheme_EncryptionType_name, EncryptionScheme_EncryptionType_value)
-	proto.RegisterEnum("pointerdb.Pointer_DataType", Pointer_DataType_name, Pointer_DataType_value)
+// UsageRequest is a request message for the Usage rpc call
+type UsageRequest struct {
+	APIKey               []byte   `protobuf:"bytes,1,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *UsageRequest) Reset()         { *m = UsageRequest{} }
+func (m *UsageRequest) String() string { return proto.CompactTextString(m) }
+func (*UsageRequest) ProtoMessage()    {}
+func (*UsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{13}
+}
+func (m *UsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageRequest.Unmarshal(m, b)
+}
+func (m *UsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageRequest.Marshal(b, m, deterministic)
+}
+func (dst *UsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageRequest.Merge(dst, src)
+}
+func (m *UsageRequest) XXX_Size() int {
+	return xxx_messageInfo_UsageRequest.Size(m)
+}
+func (m *UsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_UsageRequest proto.InternalMessageInfo
 
-// PointerDBClient is the client API for PointerDB service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PointerDBClient interface {
-	// Put formats and hands off a file path to be saved to boltdb
-	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
-	// Get formats and hands off a file path to get a small value from boltdb
-	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
-	// List calls the bolt client's List function and returns all file paths
-	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
-	// Delete formats and hands off a file path to delete from boltdb
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+func (m *UsageRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
 }
 
-type pointerDBClient struct {
-	cc *grpc.ClientConn
+// UsageResponse is a response message for the Usage rpc call
+type UsageResponse struct {
+	StoredBytes          int64    `protobuf:"varint,1,opt,name=stored_bytes,json=storedBytes,proto3" json:"stored_bytes,omitempty"`
+	ObjectCount          int64    `protobuf:"varint,2,opt,name=object_count,json=objectCount,proto3" json:"object_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewPointerDBClient(cc *grpc.ClientConn) PointerDBClient {
-	return &pointerDBClient{cc}
+func (m *UsageResponse) Reset()         { *m = UsageResponse{} }
+func (m *UsageResponse) String() string { return proto.CompactTextString(m) }
+func (*UsageResponse) ProtoMessage()    {}
+func (*UsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{14}
 }
-
-func (c *pointerDBClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
-	out := new(PutResponse)
-	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Put", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *UsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageResponse.Unmarshal(m, b)
 }
-
-func (c *pointerDBClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
-	out := new(GetResponse)
-	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Get", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *UsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageResponse.Marshal(b, m, deterministic)
+}
+func (dst *UsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageResponse.Merge(dst, src)
+}
+func (m *UsageResponse) XXX_Size() int {
+	return xxx_messageInfo_UsageResponse.Size(m)
+}
+func (m *UsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageResponse.DiscardUnknown(m)
 }
 
-func (c *pointerDBClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
-	out := new(ListResponse)
-	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/List", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_UsageResponse proto.InternalMessageInfo
+
+func (m *UsageResponse) GetStoredBytes() int64 {
+	if m != nil {
+		return m.StoredBytes
 	}
-	return out, nil
+	return 0
 }
 
-func (c *pointerDBClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Delete", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *UsageResponse) GetObjectCount() int64 {
+	if m != nil {
+		return m.ObjectCount
 	}
-	return out, nil
+	return 0
 }
 
-// PointerDBServer is the server API for PointerDB service.
-type PointerDBServer interface {
-	// Put formats and hands off a file path to be saved to boltdb
-	Put(context.Context, *PutRequest) (*PutResponse, error)
-	// Get formats and hands off a file path to get a small value from boltdb
-	Get(context.Context, *GetRequest) (*GetResponse, error)
-	// List calls the bolt client's List function and returns all file paths
-	List(context.Context, *ListRequest) (*ListResponse, error)
-	// Delete formats and hands off a file path to delete from boltdb
-	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+// IterateExpiringRequest is a request message for the IterateExpiring rpc call
+type IterateExpiringRequest struct {
+	Before               *timestamp.Timestamp `protobuf:"bytes,1,opt,name=before,proto3" json:"before,omitempty"`
+	StartAfter           string               `protobuf:"bytes,2,opt,name=start_after,json=startAfter,proto3" json:"start_after,omitempty"`
+	Limit                int32                `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	APIKey               []byte               `protobuf:"bytes,4,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func RegisterPointerDBServer(s *grpc.Server, srv PointerDBServer) {
-	s.RegisterService(&_PointerDB_serviceDesc, srv)
+func (m *IterateExpiringRequest) Reset()         { *m = IterateExpiringRequest{} }
+func (m *IterateExpiringRequest) String() string { return proto.CompactTextString(m) }
+func (*IterateExpiringRequest) ProtoMessage()    {}
+func (*IterateExpiringRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{15}
+}
+func (m *IterateExpiringRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IterateExpiringRequest.Unmarshal(m, b)
+}
+func (m *IterateExpiringRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IterateExpiringRequest.Marshal(b, m, deterministic)
+}
+func (dst *IterateExpiringRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IterateExpiringRequest.Merge(dst, src)
+}
+func (m *IterateExpiringRequest) XXX_Size() int {
+	return xxx_messageInfo_IterateExpiringRequest.Size(m)
+}
+func (m *IterateExpiringRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IterateExpiringRequest.DiscardUnknown(m)
 }
 
-func _PointerDB_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PutRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(PointerDBServer).Put(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pointerdb.PointerDB/Put",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PointerDBServer).Put(ctx, req.(*PutRequest))
+var xxx_messageInfo_IterateExpiringRequest proto.InternalMessageInfo
+
+func (m *IterateExpiringRequest) GetBefore() *timestamp.Timestamp {
+	if m != nil {
+		return m.Before
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _PointerDB_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(PointerDBServer).Get(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pointerdb.PointerDB/Get",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PointerDBServer).Get(ctx, req.(*GetRequest))
+func (m *IterateExpiringRequest) GetStartAfter() string {
+	if m != nil {
+		return m.StartAfter
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _PointerDB_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(PointerDBServer).List(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pointerdb.PointerDB/List",
+func (m *IterateExpiringRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PointerDBServer).List(ctx, req.(*ListRequest))
+	return 0
+}
+
+func (m *IterateExpiringRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _PointerDB_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+// IterateByNodeRequest is a request message for the IterateByNode rpc call
+type IterateByNodeRequest struct {
+	NodeId               string   `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	StartAfter           string   `protobuf:"bytes,2,opt,name=start_after,json=startAfter,proto3" json:"start_after,omitempty"`
+	Limit                int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	APIKey               []byte   `protobuf:"bytes,4,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IterateByNodeRequest) Reset()         { *m = IterateByNodeRequest{} }
+func (m *IterateByNodeRequest) String() string { return proto.CompactTextString(m) }
+func (*IterateByNodeRequest) ProtoMessage()    {}
+func (*IterateByNodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{16}
+}
+func (m *IterateByNodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IterateByNodeRequest.Unmarshal(m, b)
+}
+func (m *IterateByNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IterateByNodeRequest.Marshal(b, m, deterministic)
+}
+func (dst *IterateByNodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IterateByNodeRequest.Merge(dst, src)
+}
+func (m *IterateByNodeRequest) XXX_Size() int {
+	return xxx_messageInfo_IterateByNodeRequest.Size(m)
+}
+func (m *IterateByNodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IterateByNodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IterateByNodeRequest proto.InternalMessageInfo
+
+func (m *IterateByNodeRequest) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
 	}
-	if interceptor == nil {
-		return srv.(PointerDBServer).Delete(ctx, in)
+	return ""
+}
+
+func (m *IterateByNodeRequest) GetStartAfter() string {
+	if m != nil {
+		return m.StartAfter
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pointerdb.PointerDB/Delete",
+	return ""
+}
+
+func (m *IterateByNodeRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PointerDBServer).Delete(ctx, req.(*DeleteRequest))
+	return 0
+}
+
+func (m *IterateByNodeRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _PointerDB_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "pointerdb.PointerDB",
-	HandlerType: (*PointerDBServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Put",
-			Handler:    _PointerDB_Put_Handler,
-		},
-		{
-			MethodName: "Get",
-			Handler:    _PointerDB_Get_Handler,
+// IterateResponse is a response message for the IterateExpiring,
+// IterateByNode and Iterate rpc calls
+type IterateResponse struct {
+	Items                []*IterateResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	More                 bool                    `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *IterateResponse) Reset()         { *m = IterateResponse{} }
+func (m *IterateResponse) String() string { return proto.CompactTextString(m) }
+func (*IterateResponse) ProtoMessage()    {}
+func (*IterateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{17}
+}
+func (m *IterateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IterateResponse.Unmarshal(m, b)
+}
+func (m *IterateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IterateResponse.Marshal(b, m, deterministic)
+}
+func (dst *IterateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IterateResponse.Merge(dst, src)
+}
+func (m *IterateResponse) XXX_Size() int {
+	return xxx_messageInfo_IterateResponse.Size(m)
+}
+func (m *IterateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IterateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IterateResponse proto.InternalMessageInfo
+
+func (m *IterateResponse) GetItems() []*IterateResponse_Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *IterateResponse) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+type IterateResponse_Item struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Pointer              *Pointer `protobuf:"bytes,2,opt,name=pointer,proto3" json:"pointer,omitempty"`
+	Cursor               string   `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IterateResponse_Item) Reset()         { *m = IterateResponse_Item{} }
+func (m *IterateResponse_Item) String() string { return proto.CompactTextString(m) }
+func (*IterateResponse_Item) ProtoMessage()    {}
+func (*IterateResponse_Item) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{17, 0}
+}
+func (m *IterateResponse_Item) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IterateResponse_Item.Unmarshal(m, b)
+}
+func (m *IterateResponse_Item) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IterateResponse_Item.Marshal(b, m, deterministic)
+}
+func (dst *IterateResponse_Item) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IterateResponse_Item.Merge(dst, src)
+}
+func (m *IterateResponse_Item) XXX_Size() int {
+	return xxx_messageInfo_IterateResponse_Item.Size(m)
+}
+func (m *IterateResponse_Item) XXX_DiscardUnknown() {
+	xxx_messageInfo_IterateResponse_Item.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IterateResponse_Item proto.InternalMessageInfo
+
+func (m *IterateResponse_Item) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *IterateResponse_Item) GetPointer() *Pointer {
+	if m != nil {
+		return m.Pointer
+	}
+	return nil
+}
+
+func (m *IterateResponse_Item) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+// IterateRequest is a request message for the Iterate rpc call
+type IterateRequest struct {
+	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	StartAfter           string   `protobuf:"bytes,2,opt,name=start_after,json=startAfter,proto3" json:"start_after,omitempty"`
+	Limit                int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	APIKey               []byte   `protobuf:"bytes,4,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IterateRequest) Reset()         { *m = IterateRequest{} }
+func (m *IterateRequest) String() string { return proto.CompactTextString(m) }
+func (*IterateRequest) ProtoMessage()    {}
+func (*IterateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{18}
+}
+func (m *IterateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IterateRequest.Unmarshal(m, b)
+}
+func (m *IterateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IterateRequest.Marshal(b, m, deterministic)
+}
+func (dst *IterateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IterateRequest.Merge(dst, src)
+}
+func (m *IterateRequest) XXX_Size() int {
+	return xxx_messageInfo_IterateRequest.Size(m)
+}
+func (m *IterateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IterateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IterateRequest proto.InternalMessageInfo
+
+func (m *IterateRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *IterateRequest) GetStartAfter() string {
+	if m != nil {
+		return m.StartAfter
+	}
+	return ""
+}
+
+func (m *IterateRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *IterateRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+// BatchGetRequest is a request message for the BatchGet rpc call
+type BatchGetRequest struct {
+	Paths  []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	APIKey []byte   `protobuf:"bytes,2,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	// segment referencing the chunk segments of the request, which authorizes
+	// them instead of their own paths
+	Referrer             string   `protobuf:"bytes,3,opt,name=referrer,proto3" json:"referrer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchGetRequest) Reset()         { *m = BatchGetRequest{} }
+func (m *BatchGetRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchGetRequest) ProtoMessage()    {}
+func (*BatchGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{19}
+}
+func (m *BatchGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetRequest.Unmarshal(m, b)
+}
+func (m *BatchGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetRequest.Marshal(b, m, deterministic)
+}
+func (dst *BatchGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetRequest.Merge(dst, src)
+}
+func (m *BatchGetRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchGetRequest.Size(m)
+}
+func (m *BatchGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetRequest proto.InternalMessageInfo
+
+func (m *BatchGetRequest) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+func (m *BatchGetRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+func (m *BatchGetRequest) GetReferrer() string {
+	if m != nil {
+		return m.Referrer
+	}
+	return ""
+}
+
+// BatchGetResponse is a response message for the BatchGet rpc call
+type BatchGetResponse struct {
+	Items                []*BatchGetResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *BatchGetResponse) Reset()         { *m = BatchGetResponse{} }
+func (m *BatchGetResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchGetResponse) ProtoMessage()    {}
+func (*BatchGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{20}
+}
+func (m *BatchGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetResponse.Unmarshal(m, b)
+}
+func (m *BatchGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetResponse.Marshal(b, m, deterministic)
+}
+func (dst *BatchGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetResponse.Merge(dst, src)
+}
+func (m *BatchGetResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchGetResponse.Size(m)
+}
+func (m *BatchGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetResponse proto.InternalMessageInfo
+
+func (m *BatchGetResponse) GetItems() []*BatchGetResponse_Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+type BatchGetResponse_Item struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Pointer              *Pointer `protobuf:"bytes,2,opt,name=pointer,proto3" json:"pointer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchGetResponse_Item) Reset()         { *m = BatchGetResponse_Item{} }
+func (m *BatchGetResponse_Item) String() string { return proto.CompactTextString(m) }
+func (*BatchGetResponse_Item) ProtoMessage()    {}
+func (*BatchGetResponse_Item) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{20, 0}
+}
+func (m *BatchGetResponse_Item) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetResponse_Item.Unmarshal(m, b)
+}
+func (m *BatchGetResponse_Item) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetResponse_Item.Marshal(b, m, deterministic)
+}
+func (dst *BatchGetResponse_Item) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetResponse_Item.Merge(dst, src)
+}
+func (m *BatchGetResponse_Item) XXX_Size() int {
+	return xxx_messageInfo_BatchGetResponse_Item.Size(m)
+}
+func (m *BatchGetResponse_Item) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetResponse_Item.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetResponse_Item proto.InternalMessageInfo
+
+func (m *BatchGetResponse_Item) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *BatchGetResponse_Item) GetPointer() *Pointer {
+	if m != nil {
+		return m.Pointer
+	}
+	return nil
+}
+
+// BatchDeleteRequest is a request message for the BatchDelete rpc call
+type BatchDeleteRequest struct {
+	Paths  []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	APIKey []byte   `protobuf:"bytes,2,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	// segment referencing the chunk segments of the request, which authorizes
+	// them instead of their own paths
+	Referrer             string   `protobuf:"bytes,3,opt,name=referrer,proto3" json:"referrer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchDeleteRequest) Reset()         { *m = BatchDeleteRequest{} }
+func (m *BatchDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteRequest) ProtoMessage()    {}
+func (*BatchDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{21}
+}
+func (m *BatchDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteRequest.Unmarshal(m, b)
+}
+func (m *BatchDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteRequest.Marshal(b, m, deterministic)
+}
+func (dst *BatchDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteRequest.Merge(dst, src)
+}
+func (m *BatchDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteRequest.Size(m)
+}
+func (m *BatchDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteRequest proto.InternalMessageInfo
+
+func (m *BatchDeleteRequest) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+func (m *BatchDeleteRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+func (m *BatchDeleteRequest) GetReferrer() string {
+	if m != nil {
+		return m.Referrer
+	}
+	return ""
+}
+
+// BatchDeleteResponse is a response message for the BatchDelete rpc call
+type BatchDeleteResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchDeleteResponse) Reset()         { *m = BatchDeleteResponse{} }
+func (m *BatchDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteResponse) ProtoMessage()    {}
+func (*BatchDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{22}
+}
+func (m *BatchDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteResponse.Unmarshal(m, b)
+}
+func (m *BatchDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteResponse.Marshal(b, m, deterministic)
+}
+func (dst *BatchDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteResponse.Merge(dst, src)
+}
+func (m *BatchDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteResponse.Size(m)
+}
+func (m *BatchDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteResponse proto.InternalMessageInfo
+
+// BatchMoveRequest is a request message for the BatchMove rpc call
+type BatchMoveRequest struct {
+	FromPaths            []string `protobuf:"bytes,1,rep,name=from_paths,json=fromPaths,proto3" json:"from_paths,omitempty"`
+	ToPaths              []string `protobuf:"bytes,2,rep,name=to_paths,json=toPaths,proto3" json:"to_paths,omitempty"`
+	APIKey               []byte   `protobuf:"bytes,3,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchMoveRequest) Reset()         { *m = BatchMoveRequest{} }
+func (m *BatchMoveRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchMoveRequest) ProtoMessage()    {}
+func (*BatchMoveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{23}
+}
+func (m *BatchMoveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchMoveRequest.Unmarshal(m, b)
+}
+func (m *BatchMoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchMoveRequest.Marshal(b, m, deterministic)
+}
+func (dst *BatchMoveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchMoveRequest.Merge(dst, src)
+}
+func (m *BatchMoveRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchMoveRequest.Size(m)
+}
+func (m *BatchMoveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchMoveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchMoveRequest proto.InternalMessageInfo
+
+func (m *BatchMoveRequest) GetFromPaths() []string {
+	if m != nil {
+		return m.FromPaths
+	}
+	return nil
+}
+
+func (m *BatchMoveRequest) GetToPaths() []string {
+	if m != nil {
+		return m.ToPaths
+	}
+	return nil
+}
+
+func (m *BatchMoveRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+// BatchMoveResponse is a response message for the BatchMove rpc call
+type BatchMoveResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchMoveResponse) Reset()         { *m = BatchMoveResponse{} }
+func (m *BatchMoveResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchMoveResponse) ProtoMessage()    {}
+func (*BatchMoveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_fd24c88cb81f60a2, []int{24}
+}
+func (m *BatchMoveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchMoveResponse.Unmarshal(m, b)
+}
+func (m *BatchMoveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchMoveResponse.Marshal(b, m, deterministic)
+}
+func (dst *BatchMoveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchMoveResponse.Merge(dst, src)
+}
+func (m *BatchMoveResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchMoveResponse.Size(m)
+}
+func (m *BatchMoveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchMoveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchMoveResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*RedundancyScheme)(nil), "pointerdb.RedundancyScheme")
+	proto.RegisterType((*EncryptionScheme)(nil), "pointerdb.EncryptionScheme")
+	proto.RegisterType((*RemotePiece)(nil), "pointerdb.RemotePiece")
+	proto.RegisterType((*RemoteSegment)(nil), "pointerdb.RemoteSegment")
+	proto.RegisterType((*Pointer)(nil), "pointerdb.Pointer")
+	proto.RegisterType((*PutRequest)(nil), "pointerdb.PutRequest")
+	proto.RegisterType((*GetRequest)(nil), "pointerdb.GetRequest")
+	proto.RegisterType((*ListRequest)(nil), "pointerdb.ListRequest")
+	proto.RegisterType((*PutResponse)(nil), "pointerdb.PutResponse")
+	proto.RegisterType((*GetResponse)(nil), "pointerdb.GetResponse")
+	proto.RegisterType((*ListResponse)(nil), "pointerdb.ListResponse")
+	proto.RegisterType((*ListResponse_Item)(nil), "pointerdb.ListResponse.Item")
+	proto.RegisterType((*DeleteRequest)(nil), "pointerdb.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "pointerdb.DeleteResponse")
+	proto.RegisterType((*UsageRequest)(nil), "pointerdb.UsageRequest")
+	proto.RegisterType((*UsageResponse)(nil), "pointerdb.UsageResponse")
+	proto.RegisterType((*IterateExpiringRequest)(nil), "pointerdb.IterateExpiringRequest")
+	proto.RegisterType((*IterateByNodeRequest)(nil), "pointerdb.IterateByNodeRequest")
+	proto.RegisterType((*IterateResponse)(nil), "pointerdb.IterateResponse")
+	proto.RegisterType((*IterateResponse_Item)(nil), "pointerdb.IterateResponse.Item")
+	proto.RegisterType((*IterateRequest)(nil), "pointerdb.IterateRequest")
+	proto.RegisterType((*BatchGetRequest)(nil), "pointerdb.BatchGetRequest")
+	proto.RegisterType((*BatchGetResponse)(nil), "pointerdb.BatchGetResponse")
+	proto.RegisterType((*BatchGetResponse_Item)(nil), "pointerdb.BatchGetResponse.Item")
+	proto.RegisterType((*BatchDeleteRequest)(nil), "pointerdb.BatchDeleteRequest")
+	proto.RegisterType((*BatchDeleteResponse)(nil), "pointerdb.BatchDeleteResponse")
+	proto.RegisterType((*BatchMoveRequest)(nil), "pointerdb.BatchMoveRequest")
+	proto.RegisterType((*BatchMoveResponse)(nil), "pointerdb.BatchMoveResponse")
+	proto.RegisterEnum("pointerdb.RedundancyScheme_SchemeType", RedundancyScheme_SchemeType_name, RedundancyScheme_SchemeType_value)
+	proto.RegisterEnum("pointerdb.EncryptionScheme_EncryptionType", EncryptionScheme_EncryptionType_name, EncryptionScheme_EncryptionType_value)
+	proto.RegisterEnum("pointerdb.Pointer_DataType", Pointer_DataType_name, Pointer_DataType_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PointerDBClient is the client API for PointerDB service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PointerDBClient interface {
+	// Put formats and hands off a file path to be saved to boltdb
+	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	// Get formats and hands off a file path to get a small value from boltdb
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	// List calls the bolt client's List function and returns all file paths
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Delete formats and hands off a file path to delete from boltdb
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Usage returns the storage usage of the project of the api key
+	Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error)
+	// IterateExpiring returns the segments of the project of the api key
+	// expiring before a given time, ordered by expiration
+	IterateExpiring(ctx context.Context, in *IterateExpiringRequest, opts ...grpc.CallOption) (*IterateResponse, error)
+	// IterateByNode returns the segments of the project of the api key with a
+	// piece on a given node
+	IterateByNode(ctx context.Context, in *IterateByNodeRequest, opts ...grpc.CallOption) (*IterateResponse, error)
+	// Iterate streams the full pointers under a prefix in batches
+	Iterate(ctx context.Context, in *IterateRequest, opts ...grpc.CallOption) (PointerDB_IterateClient, error)
+	// BatchGet returns the pointers at several paths at once
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	// BatchDelete deletes the pointers at several paths at once
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+	// BatchMove moves the pointers at several paths at once, atomically
+	BatchMove(ctx context.Context, in *BatchMoveRequest, opts ...grpc.CallOption) (*BatchMoveResponse, error)
+}
+
+type pointerDBClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPointerDBClient(cc *grpc.ClientConn) PointerDBClient {
+	return &pointerDBClient{cc}
+}
+
+func (c *pointerDBClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Put", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error) {
+	out := new(UsageResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Usage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) IterateExpiring(ctx context.Context, in *IterateExpiringRequest, opts ...grpc.CallOption) (*IterateResponse, error) {
+	out := new(IterateResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/IterateExpiring", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) IterateByNode(ctx context.Context, in *IterateByNodeRequest, opts ...grpc.CallOption) (*IterateResponse, error) {
+	out := new(IterateResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/IterateByNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) Iterate(ctx context.Context, in *IterateRequest, opts ...grpc.CallOption) (PointerDB_IterateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PointerDB_serviceDesc.Streams[0], "/pointerdb.PointerDB/Iterate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pointerDBIterateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PointerDB_IterateClient interface {
+	Recv() (*IterateResponse, error)
+	grpc.ClientStream
+}
+
+type pointerDBIterateClient struct {
+	grpc.ClientStream
+}
+
+func (x *pointerDBIterateClient) Recv() (*IterateResponse, error) {
+	m := new(IterateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pointerDBClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/BatchGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/BatchDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) BatchMove(ctx context.Context, in *BatchMoveRequest, opts ...grpc.CallOption) (*BatchMoveResponse, error) {
+	out := new(BatchMoveResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/BatchMove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PointerDBServer is the server API for PointerDB service.
+type PointerDBServer interface {
+	// Put formats and hands off a file path to be saved to boltdb
+	Put(context.Context, *PutRequest) (*PutResponse, error)
+	// Get formats and hands off a file path to get a small value from boltdb
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	// List calls the bolt client's List function and returns all file paths
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Delete formats and hands off a file path to delete from boltdb
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Usage returns the storage usage of the project of the api key
+	Usage(context.Context, *UsageRequest) (*UsageResponse, error)
+	// IterateExpiring returns the segments of the project of the api key
+	// expiring before a given time, ordered by expiration
+	IterateExpiring(context.Context, *IterateExpiringRequest) (*IterateResponse, error)
+	// IterateByNode returns the segments of the project of the api key with a
+	// piece on a given node
+	IterateByNode(context.Context, *IterateByNodeRequest) (*IterateResponse, error)
+	// Iterate streams the full pointers under a prefix in batches
+	Iterate(*IterateRequest, PointerDB_IterateServer) error
+	// BatchGet returns the pointers at several paths at once
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	// BatchDelete deletes the pointers at several paths at once
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
+	// BatchMove moves the pointers at several paths at once, atomically
+	BatchMove(context.Context, *BatchMoveRequest) (*BatchMoveResponse, error)
+}
+
+func RegisterPointerDBServer(s *grpc.Server, srv PointerDBServer) {
+	s.RegisterService(&_PointerDB_serviceDesc, srv)
+}
+
+func _PointerDB_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).Put(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/Put",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).Put(ctx, req.(*PutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/Usage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).Usage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_IterateExpiring_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IterateExpiringRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).IterateExpiring(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/IterateExpiring",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).IterateExpiring(ctx, req.(*IterateExpiringRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_IterateByNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IterateByNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).IterateByNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/IterateByNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).IterateByNode(ctx, req.(*IterateByNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_Iterate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IterateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PointerDBServer).Iterate(m, &pointerDBIterateServer{stream})
+}
+
+type PointerDB_IterateServer interface {
+	Send(*IterateResponse) error
+	grpc.ServerStream
+}
+
+type pointerDBIterateServer struct {
+	grpc.ServerStream
+}
+
+func (x *pointerDBIterateServer) Send(m *IterateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PointerDB_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/BatchGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/BatchDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_BatchMove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchMoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).BatchMove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/BatchMove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).BatchMove(ctx, req.(*BatchMoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PointerDB_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pointerdb.PointerDB",
+	HandlerType: (*PointerDBServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Put",
+			Handler:    _PointerDB_Put_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _PointerDB_Get_Handler,
 		},
 		{
 			MethodName: "List",
@@ -1048,75 +2050,133 @@ var _PointerDB_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _PointerDB_Delete_Handler,
 		},
+		{
+			MethodName: "Usage",
+			Handler:    _PointerDB_Usage_Handler,
+		},
+		{
+			MethodName: "IterateExpiring",
+			Handler:    _PointerDB_IterateExpiring_Handler,
+		},
+		{
+			MethodName: "IterateByNode",
+			Handler:    _PointerDB_IterateByNode_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _PointerDB_BatchGet_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _PointerDB_BatchDelete_Handler,
+		},
+		{
+			MethodName: "BatchMove",
+			Handler:    _PointerDB_BatchMove_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Iterate",
+			Handler:       _PointerDB_Iterate_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pointerdb.proto",
 }
 
-func init() { proto.RegisterFile("pointerdb.proto", fileDescriptor_pointerdb_138b3541b1f95735) }
-
-var fileDescriptor_pointerdb_138b3541b1f95735 = []byte{
-	// 982 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xdb, 0x6e, 0xdb, 0x46,
-	0x10, 0x35, 0x25, 0x5b, 0x12, 0x47, 0x96, 0xcd, 0x2e, 0x52, 0x87, 0x91, 0x53, 0xc4, 0x20, 0xd0,
-	0xd6, 0x6d, 0x02, 0xb9, 0x50, 0x03, 0xf4, 0x92, 0x5e, 0xe0, 0x8b, 0x6a, 0x08, 0x49, 0x1c, 0x61,
-	0xe5, 0x87, 0xbe, 0x11, 0xb4, 0x38, 0x96, 0x16, 0x11, 0x2f, 0xde, 0x5d, 0x16, 0x51, 0xfe, 0xa4,
-	0x1f, 0xd3, 0xc7, 0x7e, 0x40, 0x3f, 0xa4, 0xe8, 0x43, 0x7f, 0xa0, 0xd8, 0x5d, 0x52, 0x24, 0xed,
-	0x36, 0x05, 0x8a, 0xbe, 0x48, 0x3b, 0x67, 0xcf, 0xcc, 0xee, 0x9c, 0x39, 0x5c, 0xd8, 0x4d, 0x13,
-	0x16, 0x4b, 0xe4, 0xe1, 0xd5, 0x20, 0xe5, 0x89, 0x4c, 0x88, 0xbd, 0x06, 0xfa, 0x8f, 0xe6, 0x49,
-	0x32, 0x5f, 0xe2, 0x91, 0xde, 0xb8, 0xca, 0xae, 0x8f, 0x24, 0x8b, 0x50, 0xc8, 0x20, 0x4a, 0x0d,
-	0xd7, 0xfb, 0xb9, 0x01, 0x0e, 0xc5, 0x30, 0x8b, 0xc3, 0x20, 0x9e, 0xad, 0xa6, 0xb3, 0x05, 0x46,
-	0x48, 0xbe, 0x86, 0x4d, 0xb9, 0x4a, 0xd1, 0xb5, 0x0e, 0xac, 0xc3, 0x9d, 0xe1, 0x47, 0x83, 0xf2,
-	0x80, 0xdb, 0xd4, 0x81, 0xf9, 0xbb, 0x5c, 0xa5, 0x48, 0x75, 0x0e, 0xb9, 0x0f, 0xed, 0x88, 0xc5,
-	0x3e, 0xc7, 0x1b, 0xb7, 0x71, 0x60, 0x1d, 0x6e, 0xd1, 0x56, 0xc4, 0x62, 0x8a, 0x37, 0xe4, 0x1e,
-	0x6c, 0xc9, 0x44, 0x06, 0x4b, 0xb7, 0xa9, 0x61, 0x13, 0x90, 0x4f, 0xc0, 0xe1, 0x98, 0x06, 0x8c,
-	0xfb, 0x72, 0xc1, 0x51, 0x2c, 0x92, 0x65, 0xe8, 0x6e, 0x6a, 0xc2, 0xae, 0xc1, 0x2f, 0x0b, 0x98,
-	0x3c, 0x86, 0xf7, 0x44, 0x36, 0x9b, 0xa1, 0x10, 0x15, 0xee, 0x96, 0xe6, 0x3a, 0xf9, 0x46, 0x49,
-	0x7e, 0x02, 0x04, 0x79, 0x20, 0x32, 0x8e, 0xbe, 0x58, 0x04, 0xea, 0x97, 0xbd, 0x45, 0xb7, 0x65,
-	0xd8, 0xf9, 0xce, 0x54, 0x6d, 0x4c, 0xd9, 0x5b, 0xf4, 0xee, 0x01, 0x94, 0x8d, 0x90, 0x16, 0x34,
-	0xe8, 0xd4, 0xd9, 0xf0, 0xfe, 0xb4, 0xc0, 0x19, 0xc5, 0x33, 0xbe, 0x4a, 0x25, 0x4b, 0xe2, 0x5c,
-	0x9b, 0xef, 0x6a, 0xda, 0x7c, 0x5a, 0xd1, 0xe6, 0x36, 0xb5, 0x02, 0x54, 0xf4, 0xf9, 0x12, 0x5c,
-	0x34, 0x38, 0x86, 0x3e, 0xae, 0x19, 0xfe, 0x6b, 0x5c, 0x69, 0xc1, 0xb6, 0xe9, 0xde, 0x7a, 0xbf,
-	0x2c, 0xf0, 0x1c, 0x57, 0xf5, 0x4c, 0x21, 0x03, 0x2e, 0x59, 0x3c, 0xf7, 0xe3, 0x24, 0x9e, 0xa1,
-	0xd6, 0xb4, 0x9a, 0x39, 0xcd, 0xb7, 0x2f, 0xd4, 0xae, 0xf7, 0x18, 0x76, 0xea, 0x77, 0x21, 0x00,
-	0xad, 0xe3, 0xd1, 0xf4, 0xfc, 0xf4, 0xa5, 0xb3, 0x41, 0x7a, 0x60, 0x4f, 0x47, 0xa7, 0x74, 0x74,
-	0x79, 0xf2, 0xea, 0x47, 0xc7, 0xf2, 0x4e, 0xa1, 0x4b, 0x31, 0x4a, 0x24, 0x4e, 0x18, 0xce, 0x90,
-	0xec, 0x83, 0x9d, 0xaa, 0x85, 0x1f, 0x67, 0x91, 0x6e, 0x7a, 0x8b, 0x76, 0x34, 0x70, 0x91, 0x45,
-	0x6a, 0xd8, 0x71, 0x12, 0xa2, 0xcf, 0x42, 0x7d, 0x77, 0x9b, 0xb6, 0x54, 0x38, 0x0e, 0xbd, 0x5f,
-	0x2d, 0xe8, 0x99, 0x2a, 0x53, 0x9c, 0x47, 0x18, 0x4b, 0xf2, 0x0c, 0x80, 0xaf, 0xcd, 0xa3, 0x0b,
-	0x75, 0x87, 0xfb, 0xef, 0x70, 0x16, 0xad, 0xd0, 0xc9, 0x03, 0x30, 0x67, 0x96, 0x07, 0xb5, 0x75,
-	0x3c, 0x0e, 0xc9, 0x33, 0xe8, 0x71, 0x7d, 0x90, 0xaf, 0x11, 0xe1, 0x36, 0x0f, 0x9a, 0x87, 0xdd,
-	0xe1, 0x5e, 0xad, 0xf4, 0xba, 0x1d, 0xba, 0xcd, 0xcb, 0x40, 0x90, 0x47, 0xd0, 0x8d, 0x90, 0xbf,
-	0x5e, 0xa2, 0xcf, 0x93, 0x44, 0x6a, 0xe3, 0x6d, 0x53, 0x30, 0x10, 0x4d, 0x12, 0xe9, 0xfd, 0xde,
-	0x80, 0xf6, 0xc4, 0x14, 0x22, 0x47, 0xb5, 0xc9, 0x57, 0xef, 0x9e, 0x33, 0x06, 0x67, 0x81, 0x0c,
-	0x2a, 0xa3, 0xfe, 0x10, 0x76, 0x58, 0xbc, 0x64, 0x31, 0xfa, 0xc2, 0x88, 0x90, 0x8f, 0xa9, 0x67,
-	0xd0, 0x42, 0x99, 0xcf, 0xa0, 0x65, 0x2e, 0xa5, 0xcf, 0xef, 0x0e, 0xdd, 0x3b, 0x57, 0xcf, 0x99,
-	0x34, 0xe7, 0x11, 0x02, 0x9b, 0xda, 0xce, 0xca, 0xfc, 0x4d, 0xaa, 0xd7, 0xe4, 0x7b, 0xe8, 0xcd,
-	0x38, 0x06, 0xda, 0x4b, 0x61, 0x20, 0x8d, 0xd7, 0xbb, 0xc3, 0xfe, 0xc0, 0xbc, 0x00, 0x83, 0xe2,
-	0x05, 0x18, 0x5c, 0x16, 0x2f, 0x00, 0xdd, 0x2e, 0x12, 0xce, 0x02, 0x89, 0xe4, 0x14, 0x76, 0xf1,
-	0x4d, 0xca, 0x78, 0xa5, 0x44, 0xfb, 0x5f, 0x4b, 0xec, 0x94, 0x29, 0xba, 0x48, 0x1f, 0x3a, 0x11,
-	0xca, 0x20, 0x0c, 0x64, 0xe0, 0x76, 0x74, 0xb3, 0xeb, 0xd8, 0xf3, 0xa0, 0x53, 0x08, 0xa4, 0xfc,
-	0x37, 0xbe, 0x78, 0x31, 0xbe, 0x18, 0x39, 0x1b, 0x6a, 0x4d, 0x47, 0x2f, 0x5f, 0x5d, 0x8e, 0x1c,
-	0xcb, 0x9b, 0x03, 0x4c, 0x32, 0x49, 0xf1, 0x26, 0x43, 0x21, 0x55, 0x9f, 0x69, 0x20, 0x17, 0x5a,
-	0x71, 0x9b, 0xea, 0x35, 0x79, 0x02, 0xed, 0x5c, 0x1e, 0xed, 0x84, 0xee, 0x90, 0xdc, 0x1d, 0x04,
-	0x2d, 0x28, 0xca, 0xa0, 0xc7, 0x93, 0xb1, 0xfe, 0xb8, 0x8c, 0xf6, 0xad, 0xe3, 0xc9, 0xf8, 0x39,
-	0xae, 0xbc, 0xaf, 0x00, 0xce, 0xf1, 0x9d, 0x07, 0x55, 0x52, 0x1b, 0xb5, 0xd4, 0xdf, 0x2c, 0xe8,
-	0xbe, 0x60, 0x62, 0x9d, 0xbc, 0x07, 0xad, 0x94, 0xe3, 0x35, 0x7b, 0x93, 0xa7, 0xe7, 0x91, 0x32,
-	0x97, 0xfe, 0x4a, 0xfd, 0xe0, 0xba, 0xb8, 0xad, 0x4d, 0x41, 0x43, 0xc7, 0x0a, 0x21, 0x1f, 0x00,
-	0x60, 0x1c, 0xfa, 0x57, 0x78, 0x9d, 0x70, 0xf3, 0x09, 0xdb, 0xd4, 0xc6, 0x38, 0x3c, 0xd1, 0x00,
-	0x79, 0x08, 0x36, 0xc7, 0x59, 0xc6, 0x05, 0xfb, 0xc9, 0x58, 0xa3, 0x43, 0x4b, 0x40, 0x3d, 0xa7,
-	0x4b, 0x16, 0x31, 0x99, 0xbf, 0x80, 0x26, 0x50, 0x25, 0x95, 0xde, 0xfe, 0xf5, 0x32, 0x98, 0x0b,
-	0x6d, 0x81, 0x36, 0xb5, 0x15, 0xf2, 0x83, 0x02, 0xaa, 0x3d, 0xb5, 0x6b, 0x3d, 0xf5, 0xa0, 0xab,
-	0x75, 0x17, 0x69, 0x12, 0x0b, 0xf4, 0x3e, 0x86, 0xae, 0x56, 0xc7, 0x84, 0xc4, 0x2d, 0x35, 0xb7,
-	0x74, 0x5a, 0x11, 0x7a, 0xbf, 0x58, 0xb0, 0x6d, 0xb4, 0xc8, 0xa9, 0x43, 0xd8, 0x62, 0x12, 0x23,
-	0xe1, 0x5a, 0xfa, 0x33, 0x7c, 0x58, 0x19, 0x4e, 0x95, 0x37, 0x18, 0x4b, 0x8c, 0xa8, 0xa1, 0x2a,
-	0xf5, 0x23, 0xa5, 0x40, 0x43, 0xf7, 0xa8, 0xd7, 0x7d, 0x84, 0x4d, 0x45, 0xf9, 0x1f, 0x2c, 0xb0,
-	0x0f, 0x36, 0x13, 0x7e, 0x3e, 0xa1, 0xa6, 0x3e, 0xa2, 0xc3, 0xc4, 0x44, 0xc7, 0xde, 0x37, 0xd0,
-	0x3b, 0xc3, 0x25, 0x4a, 0xfc, 0x4f, 0x4e, 0x70, 0x60, 0xa7, 0xc8, 0x36, 0x6d, 0x0d, 0xff, 0xb0,
-	0xc0, 0xce, 0x6f, 0x70, 0x76, 0x42, 0x9e, 0x42, 0x73, 0x92, 0x49, 0xf2, 0x7e, 0xf5, 0x7a, 0x6b,
-	0x77, 0xf7, 0xf7, 0x6e, 0xc3, 0xb9, 0x84, 0x4f, 0xa1, 0x79, 0x8e, 0xf5, 0xac, 0xd2, 0xaa, 0xb5,
-	0xac, 0xea, 0x8c, 0xbe, 0x80, 0x4d, 0x25, 0x30, 0xd9, 0xbb, 0xa3, 0xb8, 0xc9, 0xbb, 0xff, 0x0f,
-	0x93, 0x20, 0xdf, 0x42, 0xcb, 0x34, 0x41, 0xaa, 0x0f, 0x4f, 0x4d, 0x95, 0xfe, 0x83, 0xbf, 0xd9,
-	0x31, 0xe9, 0x57, 0x2d, 0xfd, 0x2a, 0x7c, 0xfe, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 0x61,
-	0xbc, 0x67, 0x86, 0x08, 0x00, 0x00,
+func init() { proto.RegisterFile("pointerdb.proto", fileDescriptor_pointerdb_fd24c88cb81f60a2) }
+
+var fileDescriptor_pointerdb_fd24c88cb81f60a2 = []byte{
+	// 1431 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xcb, 0x6e, 0xdb, 0x46,
+	0x17, 0x0e, 0x25, 0xeb, 0xc2, 0x23, 0xcb, 0x52, 0x26, 0x89, 0x23, 0xcb, 0xb9, 0x38, 0x04, 0xfe,
+	0x3f, 0x6e, 0x13, 0x28, 0xa9, 0x9a, 0x5e, 0x90, 0xf4, 0x02, 0x5b, 0x56, 0x1c, 0xa1, 0x8e, 0x23,
+	0x8c, 0x6c, 0xa0, 0x05, 0x0a, 0xb0, 0xb4, 0x78, 0x2c, 0xb3, 0x11, 0x49, 0x85, 0x1c, 0x05, 0x51,
+	0x36, 0x5d, 0xf4, 0x15, 0xba, 0xec, 0x73, 0x64, 0xd7, 0xee, 0xfa, 0x00, 0x7d, 0x8d, 0x6e, 0xfb,
+	0x02, 0xc5, 0x5c, 0x44, 0x0d, 0xe5, 0x1b, 0x9a, 0x7a, 0x63, 0xf3, 0x9c, 0xf9, 0xce, 0xfd, 0x9b,
+	0x33, 0x82, 0xca, 0x28, 0xf4, 0x02, 0x86, 0x91, 0x7b, 0xd0, 0x18, 0x45, 0x21, 0x0b, 0x89, 0x99,
+	0x28, 0xea, 0xb7, 0x07, 0x61, 0x38, 0x18, 0xe2, 0x03, 0x71, 0x70, 0x30, 0x3e, 0x7c, 0xc0, 0x3c,
+	0x1f, 0x63, 0xe6, 0xf8, 0x23, 0x89, 0xb5, 0xde, 0x65, 0xa0, 0x4a, 0xd1, 0x1d, 0x07, 0xae, 0x13,
+	0xf4, 0x27, 0xbd, 0xfe, 0x11, 0xfa, 0x48, 0x1e, 0xc3, 0x02, 0x9b, 0x8c, 0xb0, 0x66, 0xac, 0x19,
+	0xeb, 0x4b, 0xcd, 0xff, 0x37, 0x66, 0x01, 0xe6, 0xa1, 0x0d, 0xf9, 0x6f, 0x6f, 0x32, 0x42, 0x2a,
+	0x6c, 0xc8, 0x75, 0x28, 0xf8, 0x5e, 0x60, 0x47, 0xf8, 0xaa, 0x96, 0x59, 0x33, 0xd6, 0x73, 0x34,
+	0xef, 0x7b, 0x01, 0xc5, 0x57, 0xe4, 0x2a, 0xe4, 0x58, 0xc8, 0x9c, 0x61, 0x2d, 0x2b, 0xd4, 0x52,
+	0x20, 0x1f, 0x40, 0x35, 0xc2, 0x91, 0xe3, 0x45, 0x36, 0x3b, 0x8a, 0x30, 0x3e, 0x0a, 0x87, 0x6e,
+	0x6d, 0x41, 0x00, 0x2a, 0x52, 0xbf, 0x37, 0x55, 0x93, 0x7b, 0x70, 0x39, 0x1e, 0xf7, 0xfb, 0x18,
+	0xc7, 0x1a, 0x36, 0x27, 0xb0, 0x55, 0x75, 0x30, 0x03, 0xdf, 0x07, 0x82, 0x91, 0x13, 0x8f, 0x23,
+	0xb4, 0xe3, 0x23, 0x87, 0xff, 0xf5, 0xde, 0x62, 0x2d, 0x2f, 0xd1, 0xea, 0xa4, 0xc7, 0x0f, 0x7a,
+	0xde, 0x5b, 0xb4, 0x3e, 0x02, 0x98, 0x15, 0x42, 0xf2, 0x90, 0xa1, 0xbd, 0xea, 0x25, 0x52, 0x81,
+	0x12, 0x6d, 0x77, 0x77, 0x3a, 0xad, 0x8d, 0xbd, 0xce, 0x8b, 0xdd, 0xaa, 0x41, 0x00, 0xf2, 0xad,
+	0x8d, 0xfd, 0xd6, 0xb3, 0xef, 0xaa, 0x19, 0xeb, 0x6f, 0x03, 0xaa, 0xed, 0xa0, 0x1f, 0x4d, 0x46,
+	0xcc, 0x0b, 0x03, 0xd5, 0xb8, 0xaf, 0x52, 0x8d, 0xfb, 0x50, 0x6b, 0xdc, 0x3c, 0x54, 0x53, 0x68,
+	0xcd, 0xfb, 0x1c, 0x6a, 0x28, 0xf5, 0xe8, 0xda, 0x98, 0x20, 0xec, 0x97, 0x38, 0x11, 0xdd, 0x5c,
+	0xa4, 0xcb, 0xc9, 0xf9, 0xcc, 0xc1, 0x37, 0x38, 0x49, 0x5b, 0xc6, 0xcc, 0x89, 0x98, 0x17, 0x0c,
+	0xec, 0x20, 0x0c, 0xfa, 0x58, 0xcb, 0xce, 0x59, 0xf6, 0xd4, 0xf1, 0x2e, 0x3f, 0xb5, 0xee, 0xc1,
+	0x52, 0x3a, 0x17, 0x5e, 0xe6, 0x46, 0xbb, 0xb7, 0xdd, 0x7a, 0x5e, 0xbd, 0x44, 0xca, 0x60, 0xf6,
+	0xda, 0x2d, 0xda, 0xde, 0xdb, 0x7c, 0xf1, 0x6d, 0xd5, 0xb0, 0x5a, 0x50, 0xa2, 0xe8, 0x87, 0x0c,
+	0xbb, 0x1e, 0xf6, 0x91, 0xac, 0x82, 0x39, 0xe2, 0x1f, 0x76, 0x30, 0xf6, 0x45, 0xd1, 0x39, 0x5a,
+	0x14, 0x8a, 0xdd, 0xb1, 0xcf, 0x99, 0x10, 0x84, 0x2e, 0xda, 0x9e, 0x2b, 0x72, 0x37, 0x69, 0x9e,
+	0x8b, 0x1d, 0xd7, 0xfa, 0xc3, 0x80, 0xb2, 0xf4, 0xd2, 0xc3, 0x81, 0x8f, 0x01, 0x23, 0x4f, 0x00,
+	0xa2, 0x84, 0x59, 0xc2, 0x51, 0xa9, 0xb9, 0x7a, 0x06, 0xed, 0xa8, 0x06, 0x27, 0x2b, 0x20, 0x63,
+	0xce, 0x02, 0x15, 0x84, 0xdc, 0x71, 0xc9, 0x13, 0x28, 0x47, 0x22, 0x90, 0x2d, 0x34, 0x71, 0x2d,
+	0xbb, 0x96, 0x5d, 0x2f, 0x35, 0x97, 0x53, 0xae, 0x93, 0x72, 0xe8, 0x62, 0x34, 0x13, 0x62, 0x72,
+	0x1b, 0x4a, 0x3e, 0x46, 0x2f, 0x87, 0x68, 0x47, 0x61, 0xc8, 0x04, 0x2b, 0x17, 0x29, 0x48, 0x15,
+	0x0d, 0x43, 0x66, 0xbd, 0xcb, 0x42, 0xa1, 0x2b, 0x1d, 0x91, 0x07, 0xa9, 0xc9, 0xeb, 0xb9, 0x2b,
+	0x44, 0x63, 0xcb, 0x61, 0x8e, 0x36, 0xea, 0xff, 0xc1, 0x92, 0x17, 0x0c, 0xbd, 0x00, 0xed, 0x58,
+	0x36, 0x41, 0x8d, 0xa9, 0x2c, 0xb5, 0xd3, 0xce, 0x3c, 0x84, 0xbc, 0x4c, 0x4a, 0xc4, 0x2f, 0x35,
+	0x6b, 0xc7, 0x52, 0x57, 0x48, 0xaa, 0x70, 0x84, 0xc0, 0x82, 0xe0, 0x3a, 0xbf, 0x19, 0x59, 0x2a,
+	0xbe, 0xc9, 0xd7, 0x50, 0xee, 0x47, 0xe8, 0x08, 0x2e, 0xb9, 0x0e, 0x93, 0x17, 0xa1, 0xd4, 0xac,
+	0x37, 0xe4, 0x7a, 0x68, 0x4c, 0xd7, 0x43, 0x63, 0x6f, 0xba, 0x1e, 0xe8, 0xe2, 0xd4, 0x60, 0xcb,
+	0x61, 0x48, 0x5a, 0x50, 0xc1, 0x37, 0x23, 0x2f, 0xd2, 0x5c, 0x14, 0xce, 0x75, 0xb1, 0x34, 0x33,
+	0x11, 0x4e, 0xea, 0x50, 0xf4, 0x91, 0x39, 0xae, 0xc3, 0x9c, 0x5a, 0x51, 0x14, 0x9b, 0xc8, 0xe4,
+	0x16, 0x67, 0xc0, 0x21, 0x46, 0x18, 0xf0, 0x31, 0x99, 0x6b, 0xd9, 0x75, 0x93, 0x6a, 0x1a, 0x72,
+	0x17, 0x2a, 0x89, 0x64, 0xf7, 0xc3, 0x71, 0xc0, 0x6a, 0x20, 0x0a, 0x5c, 0x4a, 0xd4, 0x2d, 0xae,
+	0xb5, 0x2c, 0x28, 0x4e, 0x3b, 0xcd, 0x89, 0xdc, 0xd9, 0xdd, 0xe9, 0xec, 0xb6, 0xab, 0x97, 0xf8,
+	0x37, 0x6d, 0x3f, 0x7f, 0xb1, 0xd7, 0xae, 0x1a, 0xd6, 0xcf, 0x06, 0x40, 0x77, 0xcc, 0x28, 0xbe,
+	0x1a, 0x63, 0xcc, 0x78, 0xc7, 0x46, 0x0e, 0x3b, 0x12, 0xb3, 0x33, 0xa9, 0xf8, 0x26, 0xf7, 0xa1,
+	0xa0, 0x1a, 0x2d, 0x38, 0x55, 0x6a, 0x92, 0xe3, 0x23, 0xa5, 0x53, 0x08, 0xa7, 0xfa, 0x46, 0xb7,
+	0x23, 0xae, 0xa9, 0x9c, 0x62, 0x7e, 0xa3, 0xdb, 0xe1, 0xd7, 0xb2, 0x0e, 0x45, 0x91, 0x5f, 0x84,
+	0x91, 0x18, 0xa0, 0x49, 0x13, 0xd9, 0xda, 0x07, 0xd8, 0xc6, 0x33, 0x93, 0xd0, 0xdc, 0x66, 0x4e,
+	0x75, 0x9b, 0x9d, 0x73, 0xfb, 0xa7, 0x01, 0xa5, 0x1d, 0x2f, 0x4e, 0x1c, 0x2f, 0x43, 0x7e, 0x14,
+	0xe1, 0xa1, 0xf7, 0x46, 0xb9, 0x56, 0x12, 0xa7, 0xb7, 0xd8, 0x13, 0xb6, 0x73, 0x38, 0xad, 0xd2,
+	0xa4, 0x20, 0x54, 0x1b, 0x5c, 0x43, 0x6e, 0x02, 0x60, 0xe0, 0xda, 0x07, 0x78, 0x18, 0x46, 0xa8,
+	0xc2, 0x98, 0x18, 0xb8, 0x9b, 0x42, 0x41, 0x6e, 0x80, 0x19, 0x61, 0x7f, 0x1c, 0xc5, 0xde, 0x6b,
+	0x49, 0xce, 0x22, 0x9d, 0x29, 0xf8, 0xb6, 0x1f, 0x7a, 0xbe, 0xc7, 0xd4, 0x82, 0x96, 0x02, 0x77,
+	0xc9, 0x27, 0x6e, 0x1f, 0x0e, 0x9d, 0x41, 0x2c, 0x48, 0x58, 0xa0, 0x26, 0xd7, 0x3c, 0xe5, 0x0a,
+	0xbd, 0xde, 0x82, 0x5e, 0xaf, 0x55, 0x86, 0x92, 0x98, 0x57, 0x3c, 0x0a, 0x83, 0x18, 0xad, 0xbb,
+	0x50, 0xda, 0xc6, 0x44, 0x24, 0xb5, 0xd9, 0xac, 0x0c, 0x61, 0x36, 0x15, 0xad, 0xdf, 0x0c, 0x58,
+	0x94, 0xbd, 0x50, 0xd0, 0x26, 0xe4, 0x3c, 0x86, 0x7e, 0x5c, 0x33, 0xc4, 0x22, 0xb8, 0xa1, 0x0d,
+	0x55, 0xc7, 0x35, 0x3a, 0x0c, 0x7d, 0x2a, 0xa1, 0x7c, 0x32, 0x3e, 0xef, 0x40, 0x46, 0xd4, 0x28,
+	0xbe, 0xeb, 0x08, 0x0b, 0x1c, 0x72, 0x01, 0xd4, 0x59, 0x05, 0xd3, 0x8b, 0x6d, 0x35, 0xa1, 0xac,
+	0x08, 0x51, 0xf4, 0xe2, 0xae, 0x90, 0xad, 0x2f, 0xa0, 0xbc, 0x85, 0x43, 0x64, 0xf8, 0x3e, 0x2c,
+	0xb1, 0xaa, 0xb0, 0x34, 0xb5, 0x4e, 0x1a, 0xb7, 0xb8, 0x1f, 0x3b, 0x83, 0xc4, 0x9d, 0x66, 0x6a,
+	0xa4, 0x4c, 0xf7, 0xa1, 0xac, 0x80, 0xaa, 0x71, 0x77, 0x60, 0x31, 0x66, 0x61, 0x84, 0xae, 0x7d,
+	0x30, 0x61, 0x18, 0x0b, 0x78, 0x96, 0x96, 0xa4, 0x6e, 0x93, 0xab, 0x38, 0x24, 0x3c, 0xf8, 0x11,
+	0xfb, 0x4c, 0xdd, 0xcf, 0x8c, 0x84, 0x48, 0x9d, 0xbc, 0x9c, 0xbf, 0x1a, 0xb0, 0xdc, 0x61, 0x18,
+	0x39, 0x0c, 0xdb, 0x7c, 0x37, 0x78, 0xc1, 0x60, 0x9a, 0x4a, 0x13, 0xf2, 0x8a, 0x69, 0xc6, 0xb9,
+	0x8b, 0x45, 0x21, 0xcf, 0xa7, 0x70, 0xc2, 0xc2, 0xac, 0xce, 0x42, 0xad, 0xea, 0x85, 0x54, 0xd5,
+	0x3f, 0xc1, 0x55, 0x95, 0xdd, 0xe6, 0x64, 0x37, 0x74, 0xf5, 0x36, 0x4d, 0x5f, 0x32, 0x43, 0x7f,
+	0xc9, 0x2e, 0x3c, 0x81, 0xdf, 0x0d, 0xa8, 0xa8, 0x0c, 0x92, 0xce, 0x7f, 0x92, 0xa6, 0xec, 0x6d,
+	0x8d, 0x4c, 0x73, 0xd0, 0x73, 0x59, 0xfb, 0xc3, 0x85, 0xb1, 0x76, 0x19, 0xf2, 0xfc, 0xa6, 0x87,
+	0xd3, 0xf5, 0xa3, 0x24, 0xeb, 0x0d, 0x2c, 0x25, 0x49, 0xfd, 0xc7, 0xf5, 0xf3, 0x2f, 0x5b, 0xf7,
+	0x3d, 0x54, 0x36, 0x1d, 0xd6, 0x3f, 0xd2, 0x56, 0xea, 0x55, 0xc8, 0xf1, 0xd2, 0x64, 0xe7, 0x4c,
+	0x2a, 0x85, 0xf7, 0x5b, 0xaa, 0xbf, 0x18, 0x50, 0x9d, 0xb9, 0x57, 0x93, 0xf9, 0x34, 0x3d, 0x99,
+	0x35, 0xad, 0x61, 0xf3, 0x58, 0x7d, 0x34, 0xf5, 0x67, 0x17, 0x35, 0x06, 0xcb, 0x06, 0x22, 0x22,
+	0xa5, 0x97, 0xc4, 0x05, 0xd6, 0x7d, 0x0d, 0xae, 0xa4, 0x02, 0xa8, 0x3d, 0x82, 0xaa, 0x1b, 0xcf,
+	0xc3, 0xd7, 0x49, 0xd4, 0x9b, 0x00, 0x87, 0x51, 0xe8, 0xdb, 0x7a, 0x68, 0x93, 0x6b, 0xba, 0x22,
+	0xfc, 0x0a, 0x14, 0x59, 0xa8, 0x0e, 0x33, 0xe2, 0xb0, 0xc0, 0xc2, 0xee, 0x7c, 0x66, 0xa9, 0xd7,
+	0xd3, 0xba, 0x02, 0x97, 0xb5, 0x30, 0x32, 0x76, 0xf3, 0xaf, 0x1c, 0x98, 0xaa, 0x11, 0x5b, 0x9b,
+	0xe4, 0x11, 0x64, 0xbb, 0x63, 0x46, 0xae, 0xe9, 0x5d, 0x4a, 0x5e, 0xf6, 0xfa, 0xf2, 0xbc, 0x5a,
+	0x4d, 0xee, 0x11, 0x64, 0xb7, 0x31, 0x6d, 0xb5, 0x8d, 0x27, 0x5a, 0xe9, 0xf3, 0xfe, 0x0c, 0x16,
+	0xf8, 0x23, 0x41, 0x96, 0x8f, 0xbd, 0x1a, 0xd2, 0xee, 0xfa, 0x29, 0xaf, 0x09, 0xf9, 0x12, 0xf2,
+	0xb2, 0x81, 0x44, 0xff, 0xf9, 0x96, 0x1a, 0x5a, 0x7d, 0xe5, 0x84, 0x13, 0x65, 0xfe, 0x18, 0x72,
+	0x62, 0x19, 0x13, 0x3d, 0x80, 0xbe, 0xc7, 0xeb, 0xb5, 0xe3, 0x07, 0xca, 0xb6, 0x0b, 0x95, 0xb9,
+	0x85, 0x4b, 0xee, 0x1c, 0xdf, 0x20, 0x73, 0xcb, 0xb8, 0x5e, 0x3f, 0x7d, 0xc9, 0x90, 0x1d, 0x28,
+	0xa7, 0x96, 0x24, 0x39, 0x61, 0x23, 0xa5, 0xd6, 0xe7, 0x99, 0xde, 0x36, 0xa1, 0xa0, 0x54, 0x64,
+	0xe5, 0x24, 0xd8, 0xb9, 0x1e, 0x1e, 0x1a, 0xa4, 0x05, 0xc5, 0xe9, 0x7d, 0x23, 0xf5, 0x13, 0x2f,
+	0xa1, 0xf4, 0xb2, 0x7a, 0xc6, 0x05, 0x25, 0x3b, 0x50, 0xd2, 0x98, 0x4e, 0x6e, 0xce, 0x63, 0xd3,
+	0xd3, 0xba, 0x75, 0xda, 0xb1, 0xf2, 0xf6, 0x14, 0xcc, 0x84, 0xb9, 0xe4, 0x58, 0x5c, 0xed, 0xda,
+	0xd4, 0x6f, 0x9c, 0x7c, 0x28, 0xfd, 0x1c, 0xe4, 0xc5, 0xeb, 0xf7, 0xf1, 0x3f, 0x03, 0x00, 0x0e,
+	0xda, 0xee, 0x5d, 0xe4, 0x0f, 0x00, 0x00,
 }
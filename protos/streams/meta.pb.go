@@ -18,11 +18,43 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type CompressionType int32
+
+const (
+	CompressionType_NONE CompressionType = 0
+	CompressionType_ZSTD CompressionType = 1
+)
+
+var CompressionType_name = map[int32]string{
+	0: "NONE",
+	1: "ZSTD",
+}
+var CompressionType_value = map[string]int32{
+	"NONE": 0,
+	"ZSTD": 1,
+}
+
+func (x CompressionType) String() string {
+	return proto.EnumName(CompressionType_name, int32(x))
+}
+func (CompressionType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_meta_14c232539da50fa3, []int{0}
+}
+
 type MetaStreamInfo struct {
-	NumberOfSegments     int64    `protobuf:"varint,1,opt,name=number_of_segments,json=numberOfSegments" json:"number_of_segments,omitempty"`
-	SegmentsSize         int64    `protobuf:"varint,2,opt,name=segments_size,json=segmentsSize" json:"segments_size,omitempty"`
-	LastSegmentSize      int64    `protobuf:"varint,3,opt,name=last_segment_size,json=lastSegmentSize" json:"last_segment_size,omitempty"`
-	Metadata             []byte   `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	NumberOfSegments     int64           `protobuf:"varint,1,opt,name=number_of_segments,json=numberOfSegments,proto3" json:"number_of_segments,omitempty"`
+	SegmentsSize         int64           `protobuf:"varint,2,opt,name=segments_size,json=segmentsSize,proto3" json:"segments_size,omitempty"`
+	LastSegmentSize      int64           `protobuf:"varint,3,opt,name=last_segment_size,json=lastSegmentSize,proto3" json:"last_segment_size,omitempty"`
+	Metadata             []byte          `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	EncryptionBlockSize  int32           `protobuf:"varint,5,opt,name=encryption_block_size,json=encryptionBlockSize,proto3" json:"encryption_block_size,omitempty"`
+	EncryptedKey         []byte          `protobuf:"bytes,6,opt,name=encrypted_key,json=encryptedKey,proto3" json:"encrypted_key,omitempty"`
+	EncryptedKeyNonce    []byte          `protobuf:"bytes,7,opt,name=encrypted_key_nonce,json=encryptedKeyNonce,proto3" json:"encrypted_key_nonce,omitempty"`
+	Compression          CompressionType `protobuf:"varint,8,opt,name=compression,proto3,enum=streams.CompressionType" json:"compression,omitempty"`
+	CompressionFrameSize int32           `protobuf:"varint,9,opt,name=compression_frame_size,json=compressionFrameSize,proto3" json:"compression_frame_size,omitempty"`
+	CompressedFrameSizes []int32         `protobuf:"varint,10,rep,packed,name=compressed_frame_sizes,json=compressedFrameSizes,proto3" json:"compressed_frame_sizes,omitempty"`
+	// the chunks holding the data of a deduplicated stream, in order,
+	// instead of the segments s0/<path>, s1/<path>, ...
+	Chunks               []*Chunk `protobuf:"bytes,11,rep,name=chunks,proto3" json:"chunks,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -32,7 +64,7 @@ func (m *MetaStreamInfo) Reset()         { *m = MetaStreamInfo{} }
 func (m *MetaStreamInfo) String() string { return proto.CompactTextString(m) }
 func (*MetaStreamInfo) ProtoMessage()    {}
 func (*MetaStreamInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meta_1e6a51dbfd2db316, []int{0}
+	return fileDescriptor_meta_14c232539da50fa3, []int{0}
 }
 func (m *MetaStreamInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MetaStreamInfo.Unmarshal(m, b)
@@ -80,23 +112,133 @@ func (m *MetaStreamInfo) GetMetadata() []byte {
 	return nil
 }
 
+func (m *MetaStreamInfo) GetEncryptionBlockSize() int32 {
+	if m != nil {
+		return m.EncryptionBlockSize
+	}
+	return 0
+}
+
+func (m *MetaStreamInfo) GetEncryptedKey() []byte {
+	if m != nil {
+		return m.EncryptedKey
+	}
+	return nil
+}
+
+func (m *MetaStreamInfo) GetEncryptedKeyNonce() []byte {
+	if m != nil {
+		return m.EncryptedKeyNonce
+	}
+	return nil
+}
+
+func (m *MetaStreamInfo) GetCompression() CompressionType {
+	if m != nil {
+		return m.Compression
+	}
+	return CompressionType_NONE
+}
+
+func (m *MetaStreamInfo) GetCompressionFrameSize() int32 {
+	if m != nil {
+		return m.CompressionFrameSize
+	}
+	return 0
+}
+
+func (m *MetaStreamInfo) GetCompressedFrameSizes() []int32 {
+	if m != nil {
+		return m.CompressedFrameSizes
+	}
+	return nil
+}
+
+func (m *MetaStreamInfo) GetChunks() []*Chunk {
+	if m != nil {
+		return m.Chunks
+	}
+	return nil
+}
+
+type Chunk struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Size                 int64    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chunk) Reset()         { *m = Chunk{} }
+func (m *Chunk) String() string { return proto.CompactTextString(m) }
+func (*Chunk) ProtoMessage()    {}
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meta_14c232539da50fa3, []int{1}
+}
+func (m *Chunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chunk.Unmarshal(m, b)
+}
+func (m *Chunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chunk.Marshal(b, m, deterministic)
+}
+func (dst *Chunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chunk.Merge(dst, src)
+}
+func (m *Chunk) XXX_Size() int {
+	return xxx_messageInfo_Chunk.Size(m)
+}
+func (m *Chunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chunk proto.InternalMessageInfo
+
+func (m *Chunk) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Chunk) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*MetaStreamInfo)(nil), "streams.MetaStreamInfo")
+	proto.RegisterType((*Chunk)(nil), "streams.Chunk")
+	proto.RegisterEnum("streams.CompressionType", CompressionType_name, CompressionType_value)
 }
 
-func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_1e6a51dbfd2db316) }
-
-var fileDescriptor_meta_1e6a51dbfd2db316 = []byte{
-	// 165 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xca, 0x4d, 0x2d, 0x49,
-	0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x2f, 0x2e, 0x29, 0x4a, 0x4d, 0xcc, 0x2d, 0x56,
-	0x5a, 0xcd, 0xc8, 0xc5, 0xe7, 0x9b, 0x5a, 0x92, 0x18, 0x0c, 0xe6, 0x7b, 0xe6, 0xa5, 0xe5, 0x0b,
-	0xe9, 0x70, 0x09, 0xe5, 0x95, 0xe6, 0x26, 0xa5, 0x16, 0xc5, 0xe7, 0xa7, 0xc5, 0x17, 0xa7, 0xa6,
-	0xe7, 0xa6, 0xe6, 0x95, 0x14, 0x4b, 0x30, 0x2a, 0x30, 0x6a, 0x30, 0x07, 0x09, 0x40, 0x64, 0xfc,
-	0xd3, 0x82, 0xa1, 0xe2, 0x42, 0xca, 0x5c, 0xbc, 0x30, 0x35, 0xf1, 0xc5, 0x99, 0x55, 0xa9, 0x12,
-	0x4c, 0x60, 0x85, 0x3c, 0x30, 0xc1, 0xe0, 0xcc, 0xaa, 0x54, 0x21, 0x2d, 0x2e, 0xc1, 0x9c, 0xc4,
-	0xe2, 0x12, 0x98, 0x69, 0x10, 0x85, 0xcc, 0x60, 0x85, 0xfc, 0x20, 0x09, 0xa8, 0x69, 0x60, 0xb5,
-	0x52, 0x5c, 0x1c, 0x20, 0x87, 0xa6, 0x24, 0x96, 0x24, 0x4a, 0xb0, 0x28, 0x30, 0x6a, 0xf0, 0x04,
-	0xc1, 0xf9, 0x49, 0x6c, 0x60, 0xd7, 0x1b, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0x9a, 0x56, 0xdb,
-	0x89, 0xcb, 0x00, 0x00, 0x00,
+func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_14c232539da50fa3) }
+
+var fileDescriptor_meta_14c232539da50fa3 = []byte{
+	// 371 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0xcf, 0xaf, 0x9a, 0x40,
+	0x10, 0xc7, 0x8b, 0xe0, 0xaf, 0x51, 0x51, 0xd7, 0xb6, 0xd9, 0xf4, 0x44, 0x34, 0x6d, 0x88, 0x6d,
+	0x38, 0xd8, 0x9e, 0x7a, 0xec, 0xaf, 0xa4, 0x69, 0xaa, 0x09, 0x78, 0xea, 0x85, 0x20, 0x0c, 0x7d,
+	0x44, 0xd9, 0x25, 0xec, 0x7a, 0xc0, 0xf3, 0xfb, 0xc3, 0x5f, 0x58, 0x50, 0xf7, 0xbd, 0xdb, 0xce,
+	0xf7, 0xf3, 0x99, 0xc9, 0x0e, 0x0b, 0x40, 0x8e, 0x32, 0xf2, 0x8a, 0x92, 0x4b, 0x4e, 0xfa, 0x42,
+	0x96, 0x18, 0xe5, 0x62, 0xf9, 0x68, 0x81, 0xfd, 0x17, 0x65, 0x14, 0xa8, 0xfa, 0x37, 0x4b, 0x39,
+	0xf9, 0x04, 0x84, 0x9d, 0xf3, 0x03, 0x96, 0x21, 0x4f, 0x43, 0x81, 0xff, 0x73, 0x64, 0x52, 0x50,
+	0xc3, 0x31, 0x5c, 0xd3, 0x9f, 0x35, 0x64, 0x97, 0x06, 0x6d, 0x4e, 0x56, 0x30, 0xb9, 0x3a, 0xa1,
+	0xc8, 0x2e, 0x48, 0x3b, 0x4a, 0x1c, 0x5f, 0xc3, 0x20, 0xbb, 0x20, 0x59, 0xc3, 0xfc, 0x14, 0x09,
+	0x79, 0x9d, 0xd6, 0x88, 0xa6, 0x12, 0xa7, 0x35, 0x68, 0xa7, 0x29, 0xf7, 0x1d, 0x0c, 0xea, 0x8b,
+	0x26, 0x91, 0x8c, 0xa8, 0xe5, 0x18, 0xee, 0xd8, 0xbf, 0xd5, 0x64, 0x03, 0x6f, 0x90, 0xc5, 0x65,
+	0x55, 0xc8, 0x8c, 0xb3, 0xf0, 0x70, 0xe2, 0xf1, 0xb1, 0x99, 0xd5, 0x75, 0x0c, 0xb7, 0xeb, 0x2f,
+	0xee, 0xf0, 0x5b, 0xcd, 0xd4, 0xbc, 0x15, 0x4c, 0xda, 0x18, 0x93, 0xf0, 0x88, 0x15, 0xed, 0xa9,
+	0xa1, 0xe3, 0x5b, 0xf8, 0x07, 0x2b, 0xe2, 0xc1, 0xe2, 0x99, 0x14, 0x32, 0xce, 0x62, 0xa4, 0x7d,
+	0xa5, 0xce, 0x75, 0x75, 0x5b, 0x03, 0xf2, 0x15, 0x46, 0x31, 0xcf, 0x8b, 0x12, 0x85, 0xc8, 0x38,
+	0xa3, 0x03, 0xc7, 0x70, 0xed, 0x0d, 0xf5, 0xda, 0xaf, 0xea, 0x7d, 0xbf, 0xb3, 0x7d, 0x55, 0xa0,
+	0xaf, 0xcb, 0xe4, 0x0b, 0xbc, 0xd5, 0xca, 0x30, 0x2d, 0xa3, 0x1c, 0x9b, 0x2d, 0x86, 0x6a, 0x8b,
+	0xd7, 0x1a, 0xfd, 0x55, 0x43, 0xb5, 0x86, 0xd6, 0x85, 0x89, 0xd6, 0x24, 0x28, 0x38, 0xa6, 0xde,
+	0x85, 0xc9, 0xad, 0x49, 0x90, 0x0f, 0xd0, 0x8b, 0x1f, 0xce, 0xec, 0x28, 0xe8, 0xc8, 0x31, 0xdd,
+	0xd1, 0xc6, 0xbe, 0x5f, 0xb1, 0x8e, 0xfd, 0x96, 0x2e, 0x3f, 0x42, 0x57, 0x05, 0xc4, 0x86, 0x4e,
+	0x96, 0xa8, 0xc7, 0x1e, 0xfa, 0x9d, 0x2c, 0x21, 0x04, 0x2c, 0xed, 0x55, 0xd5, 0x79, 0xfd, 0x1e,
+	0xa6, 0x2f, 0x16, 0x24, 0x03, 0xb0, 0xb6, 0xbb, 0xed, 0xcf, 0xd9, 0xab, 0xfa, 0xf4, 0x2f, 0xd8,
+	0xff, 0x98, 0x19, 0x87, 0x9e, 0xfa, 0xd5, 0x3e, 0x3f, 0x0d, 0x00, 0x63, 0xc6, 0xb5, 0x94, 0x78,
+	0x02, 0x00, 0x00,
 }
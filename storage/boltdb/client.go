@@ -5,6 +5,8 @@ package boltdb
 
 import (
 	"bytes"
+	"context"
+	"sync/atomic"
 	"time"
 
 	"storj.io/storj/pkg/utils"
@@ -18,6 +20,9 @@ type Client struct {
 	db     *bolt.DB
 	Path   string
 	Bucket []byte
+
+	// referenceCount is shared by all clients of db
+	referenceCount *int32
 }
 
 const (
@@ -28,14 +33,29 @@ const (
 
 // New instantiates a new BoltDB client given db file path, and a bucket name
 func New(path, bucket string) (*Client, error) {
+	clients, err := NewShared(path, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return clients[0], nil
+}
+
+// NewShared instantiates a BoltDB client for each bucket, all of them using
+// the db file at path. The file is closed once all clients are closed.
+func NewShared(path string, buckets ...string) ([]*Client, error) {
 	db, err := bolt.Open(path, fileMode, &bolt.Options{Timeout: defaultTimeout})
 	if err != nil {
 		return nil, err
 	}
 
 	err = db.Update(func(tx *bolt.Tx) error {
-		_, err = tx.CreateBucketIfNotExists([]byte(bucket))
-		return err
+		for _, bucket := range buckets {
+			_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 
 	if err != nil {
@@ -45,11 +65,17 @@ func New(path, bucket string) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{
-		db:     db,
-		Path:   path,
-		Bucket: []byte(bucket),
-	}, nil
+	referenceCount := int32(len(buckets))
+	clients := make([]*Client, 0, len(buckets))
+	for _, bucket := range buckets {
+		clients = append(clients, &Client{
+			db:             db,
+			Path:           path,
+			Bucket:         []byte(bucket),
+			referenceCount: &referenceCount,
+		})
+	}
+	return clients, nil
 }
 
 func (client *Client) update(fn func(*bolt.Bucket) error) error {
@@ -65,7 +91,8 @@ func (client *Client) view(fn func(*bolt.Bucket) error) error {
 }
 
 // Put adds a value to the provided key in boltdb, returning an error on failure.
-func (client *Client) Put(key storage.Key, value storage.Value) error {
+func (client *Client) Put(ctx context.Context, key storage.Key, value storage.Value) (err error) {
+	defer mon.Task()(&ctx)(&err)
 	if len(key) == 0 {
 		return Error.New("invalid key")
 	}
@@ -75,51 +102,128 @@ func (client *Client) Put(key storage.Key, value storage.Value) error {
 }
 
 // Get looks up the provided key from boltdb returning either an error or the result.
-func (client *Client) Get(key storage.Key) (storage.Value, error) {
+func (client *Client) Get(ctx context.Context, key storage.Key) (_ storage.Value, err error) {
+	defer mon.Task()(&ctx)(&err)
 	var value storage.Value
-	err := client.view(func(bucket *bolt.Bucket) error {
-		data := bucket.Get([]byte(key))
-		if len(data) == 0 {
-			return storage.ErrKeyNotFound.New(key.String())
-		}
-		value = storage.CloneValue(storage.Value(data))
-		return nil
+	err = client.view(func(bucket *bolt.Bucket) error {
+		var err error
+		value, err = get(bucket, key)
+		return err
 	})
 	return value, err
 }
 
+func get(bucket *bolt.Bucket, key storage.Key) (storage.Value, error) {
+	data := bucket.Get([]byte(key))
+	if len(data) == 0 {
+		return nil, storage.ErrKeyNotFound.New(key.String())
+	}
+	return storage.CloneValue(storage.Value(data)), nil
+}
+
 // Delete deletes a key/value pair from boltdb, for a given the key
-func (client *Client) Delete(key storage.Key) error {
+func (client *Client) Delete(ctx context.Context, key storage.Key) (err error) {
+	defer mon.Task()(&ctx)(&err)
 	return client.update(func(bucket *bolt.Bucket) error {
 		return bucket.Delete(key)
 	})
 }
 
+// PutBatch adds all items to boltdb in a single transaction
+func (client *Client) PutBatch(ctx context.Context, items storage.Items) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	for _, item := range items {
+		if len(item.Key) == 0 {
+			return Error.New("invalid key")
+		}
+	}
+	return client.update(func(bucket *bolt.Bucket) error {
+		for _, item := range items {
+			if err := bucket.Put(item.Key, item.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteBatch deletes all keys from boltdb in a single transaction
+func (client *Client) DeleteBatch(ctx context.Context, keys storage.Keys) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.update(func(bucket *bolt.Bucket) error {
+		for _, key := range keys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Txn runs fn in a boltdb read-write transaction. Those are serialized, so
+// transactions never conflict.
+func (client *Client) Txn(ctx context.Context, fn func(storage.Txn) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.update(func(bucket *bolt.Bucket) error {
+		return fn(txn{bucket})
+	})
+}
+
+// txn is a storage.Txn of a boltdb read-write transaction
+type txn struct {
+	bucket *bolt.Bucket
+}
+
+// Get looks up key within the transaction
+func (txn txn) Get(key storage.Key) (storage.Value, error) {
+	return get(txn.bucket, key)
+}
+
+// Put adds a value to key within the transaction
+func (txn txn) Put(key storage.Key, value storage.Value) error {
+	if len(key) == 0 {
+		return Error.New("invalid key")
+	}
+	// bolt keeps the slices until the transaction ends
+	return txn.bucket.Put(storage.CloneKey(key), storage.CloneValue(value))
+}
+
+// Delete deletes key within the transaction
+func (txn txn) Delete(key storage.Key) error {
+	return txn.bucket.Delete(key)
+}
+
 // List returns either a list of keys for which boltdb has values or an error.
-func (client *Client) List(first storage.Key, limit int) (storage.Keys, error) {
-	return storage.ListKeys(client, first, limit)
+func (client *Client) List(ctx context.Context, first storage.Key, limit int) (_ storage.Keys, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return storage.ListKeys(ctx, client, first, limit)
 }
 
 // ReverseList returns either a list of keys for which boltdb has values or an error.
 // Starts from first and iterates backwards
-func (client *Client) ReverseList(first storage.Key, limit int) (storage.Keys, error) {
-	return storage.ReverseListKeys(client, first, limit)
+func (client *Client) ReverseList(ctx context.Context, first storage.Key, limit int) (_ storage.Keys, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return storage.ReverseListKeys(ctx, client, first, limit)
 }
 
 // Close closes a BoltDB client
 func (client *Client) Close() error {
-	return client.db.Close()
+	if atomic.AddInt32(client.referenceCount, -1) == 0 {
+		return client.db.Close()
+	}
+	return nil
 }
 
 // GetAll finds all values for the provided keys up to 100 keys
 // if more keys are provided than the maximum an error will be returned.
-func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
+func (client *Client) GetAll(ctx context.Context, keys storage.Keys) (_ storage.Values, err error) {
+	defer mon.Task()(&ctx)(&err)
 	if len(keys) > storage.LookupLimit {
 		return nil, storage.ErrLimitExceeded
 	}
 
 	vals := make(storage.Values, 0, len(keys))
-	err := client.view(func(bucket *bolt.Bucket) error {
+	err = client.view(func(bucket *bolt.Bucket) error {
 		for _, key := range keys {
 			val := bucket.Get([]byte(key))
 			if val == nil {
@@ -134,7 +238,8 @@ func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 }
 
 // Iterate iterates over items based on opts
-func (client *Client) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+func (client *Client) Iterate(ctx context.Context, opts storage.IterateOptions, fn func(storage.Iterator) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
 	return client.view(func(bucket *bolt.Bucket) error {
 		var cursor advancer
 		if !opts.Reverse {
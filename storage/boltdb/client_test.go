@@ -4,11 +4,13 @@
 package boltdb
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"storj.io/storj/storage"
 	"storj.io/storj/storage/testsuite"
 )
 
@@ -53,3 +55,37 @@ func BenchmarkSuite(b *testing.B) {
 
 	testsuite.RunBenchmarks(b, store)
 }
+
+func TestShared(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "storj-bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tempdir) }()
+
+	dbname := filepath.Join(tempdir, "bolt.db")
+	stores, err := NewShared(dbname, "a", "b")
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	a, b := stores[0], stores[1]
+	ctx := context.Background()
+
+	if err := a.Put(ctx, storage.Key("key"), storage.Value("a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get(ctx, storage.Key("key")); !storage.ErrKeyNotFound.Has(err) {
+		t.Fatalf("buckets should be separate, got: %v", err)
+	}
+
+	// the file stays open until every client is closed
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put(ctx, storage.Key("key"), storage.Value("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,8 +5,11 @@ package boltdb
 
 import (
 	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 )
 
+var mon = monkit.Package()
+
 // Error is the default boltdb errs class
 var Error = errs.Class("boltdb error")
 
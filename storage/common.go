@@ -5,6 +5,7 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"errors"
 
 	"github.com/zeebo/errs"
@@ -22,6 +23,14 @@ var ErrEmptyKey = errors.New("empty key")
 // ErrLimitExceeded is returned when request limit is exceeded
 var ErrLimitExceeded = errors.New("limit exceeded")
 
+// ErrTxnConflict is returned by Txn when a key read by the transaction was
+// changed by someone else before the transaction was committed
+var ErrTxnConflict = errs.Class("transaction conflict")
+
+// ErrValueChanged is returned by CompareAndSwap when the current value
+// isn't the expected one
+var ErrValueChanged = errs.Class("value changed")
+
 // Key is the type for the keys in a `KeyValueStore`
 type Key []byte
 
@@ -50,23 +59,47 @@ type ListItem struct {
 // KeyValueStore is an interface describing key/value stores like redis and boltdb
 type KeyValueStore interface {
 	// Put adds a value to store
-	Put(Key, Value) error
+	Put(context.Context, Key, Value) error
 	// Get gets a value to store
-	Get(Key) (Value, error)
+	Get(context.Context, Key) (Value, error)
 	// GetAll gets all values from the store
-	GetAll(Keys) (Values, error)
+	GetAll(context.Context, Keys) (Values, error)
 	// Delete deletes key and the value
-	Delete(Key) error
+	Delete(context.Context, Key) error
 	// List lists all keys starting from start and upto limit items
-	List(start Key, limit int) (Keys, error)
+	List(ctx context.Context, start Key, limit int) (Keys, error)
 	// ReverseList lists all keys in revers order
-	ReverseList(Key, int) (Keys, error)
+	ReverseList(context.Context, Key, int) (Keys, error)
 	// Iterate iterates over items based on opts
-	Iterate(opts IterateOptions, fn func(Iterator) error) error
+	Iterate(ctx context.Context, opts IterateOptions, fn func(Iterator) error) error
+	// PutBatch adds all items to the store at once, either all of them are
+	// stored or none
+	PutBatch(context.Context, Items) error
+	// DeleteBatch deletes all keys from the store at once, either all of
+	// them are deleted or none
+	DeleteBatch(context.Context, Keys) error
+	// Txn runs fn in a transaction, see Txn for the guarantees
+	Txn(ctx context.Context, fn func(Txn) error) error
 	// Close closes the store
 	Close() error
 }
 
+// Txn is a transaction of a KeyValueStore. Its writes are only seen by the
+// transaction itself until the function run by KeyValueStore.Txn returns
+// nil, then all of them are applied atomically. When the function returns
+// an error nothing is applied. When a key read by the transaction is
+// changed by someone else before the commit, nothing is applied and
+// KeyValueStore.Txn returns an ErrTxnConflict error, so reads can be used
+// to compare and swap. The store itself must not be used by the function.
+type Txn interface {
+	// Get gets a value
+	Get(Key) (Value, error)
+	// Put adds a value
+	Put(Key, Value) error
+	// Delete deletes key and the value
+	Delete(Key) error
+}
+
 // IterateOptions contains options for iterator
 type IterateOptions struct {
 	// Prefix ensure
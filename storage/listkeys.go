@@ -3,15 +3,17 @@
 
 package storage
 
+import "context"
+
 // ListKeys returns keys starting from first and upto limit
 // limit is capped to LookupLimit
-func ListKeys(store KeyValueStore, first Key, limit int) (Keys, error) {
+func ListKeys(ctx context.Context, store KeyValueStore, first Key, limit int) (Keys, error) {
 	if limit <= 0 || limit > LookupLimit {
 		limit = LookupLimit
 	}
 
 	keys := make(Keys, 0, limit)
-	err := store.Iterate(IterateOptions{
+	err := store.Iterate(ctx, IterateOptions{
 		First:   first,
 		Recurse: true,
 	}, func(it Iterator) error {
@@ -30,13 +32,13 @@ func ListKeys(store KeyValueStore, first Key, limit int) (Keys, error) {
 
 // ReverseListKeys returns keys starting from first and upto limit in reverse order
 // limit is capped to LookupLimit
-func ReverseListKeys(store KeyValueStore, first Key, limit int) (Keys, error) {
+func ReverseListKeys(ctx context.Context, store KeyValueStore, first Key, limit int) (Keys, error) {
 	if limit <= 0 || limit > LookupLimit {
 		limit = LookupLimit
 	}
 
 	keys := make(Keys, 0, limit)
-	err := store.Iterate(IterateOptions{
+	err := store.Iterate(ctx, IterateOptions{
 		First:   first,
 		Recurse: true,
 		Reverse: true,
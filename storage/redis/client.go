@@ -4,15 +4,19 @@
 package redis
 
 import (
+	"context"
 	"sort"
 	"time"
 
 	"github.com/go-redis/redis"
 	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
 	"storj.io/storj/storage"
 )
 
 var (
+	mon = monkit.Package()
 	// Error is a redis error
 	Error = errs.Class("redis error")
 )
@@ -45,8 +49,18 @@ func NewClient(address, password string, db int) (*Client, error) {
 }
 
 // Get looks up the provided key from redis returning either an error or the result.
-func (client *Client) Get(key storage.Key) (storage.Value, error) {
-	value, err := client.db.Get(string(key)).Bytes()
+func (client *Client) Get(ctx context.Context, key storage.Key) (_ storage.Value, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return get(client.db, key)
+}
+
+// getter is implemented by both redis clients and transactions
+type getter interface {
+	Get(key string) *redis.StringCmd
+}
+
+func get(cmd getter, key storage.Key) (storage.Value, error) {
+	value, err := cmd.Get(string(key)).Bytes()
 	if err == redis.Nil {
 		return nil, storage.ErrKeyNotFound.New(key.String())
 	}
@@ -57,11 +71,12 @@ func (client *Client) Get(key storage.Key) (storage.Value, error) {
 }
 
 // Put adds a value to the provided key in redis, returning an error on failure.
-func (client *Client) Put(key storage.Key, value storage.Value) error {
+func (client *Client) Put(ctx context.Context, key storage.Key, value storage.Value) (err error) {
+	defer mon.Task()(&ctx)(&err)
 	if len(key) == 0 {
 		return Error.New("invalid key")
 	}
-	err := client.db.Set(key.String(), []byte(value), client.TTL).Err()
+	err = client.db.Set(key.String(), []byte(value), client.TTL).Err()
 	if err != nil {
 		return Error.New("put error: %v", err)
 	}
@@ -69,25 +84,131 @@ func (client *Client) Put(key storage.Key, value storage.Value) error {
 }
 
 // List returns either a list of keys for which boltdb has values or an error.
-func (client *Client) List(first storage.Key, limit int) (storage.Keys, error) {
-	return storage.ListKeys(client, first, limit)
+func (client *Client) List(ctx context.Context, first storage.Key, limit int) (_ storage.Keys, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return storage.ListKeys(ctx, client, first, limit)
 }
 
 // ReverseList returns either a list of keys for which redis has values or an error.
 // Starts from first and iterates backwards
-func (client *Client) ReverseList(first storage.Key, limit int) (storage.Keys, error) {
-	return storage.ReverseListKeys(client, first, limit)
+func (client *Client) ReverseList(ctx context.Context, first storage.Key, limit int) (_ storage.Keys, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return storage.ReverseListKeys(ctx, client, first, limit)
 }
 
 // Delete deletes a key/value pair from redis, for a given the key
-func (client *Client) Delete(key storage.Key) error {
-	err := client.db.Del(key.String()).Err()
+func (client *Client) Delete(ctx context.Context, key storage.Key) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = client.db.Del(key.String()).Err()
 	if err != nil {
 		return Error.New("delete error: %v", err)
 	}
 	return nil
 }
 
+// PutBatch adds all items to redis in a single MULTI/EXEC transaction
+func (client *Client) PutBatch(ctx context.Context, items storage.Items) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	for _, item := range items {
+		if len(item.Key) == 0 {
+			return Error.New("invalid key")
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	_, err = client.db.TxPipelined(func(pipe redis.Pipeliner) error {
+		for _, item := range items {
+			pipe.Set(item.Key.String(), []byte(item.Value), client.TTL)
+		}
+		return nil
+	})
+	if err != nil {
+		return Error.New("put batch error: %v", err)
+	}
+	return nil
+}
+
+// DeleteBatch deletes all keys from redis with a single command
+func (client *Client) DeleteBatch(ctx context.Context, keys storage.Keys) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if len(keys) == 0 {
+		return nil
+	}
+	err = client.db.Del(keys.Strings()...).Err()
+	if err != nil {
+		return Error.New("delete batch error: %v", err)
+	}
+	return nil
+}
+
+// Txn runs fn in an optimistic transaction. Keys are watched when they are
+// read and the writes are applied in a MULTI/EXEC transaction, which redis
+// aborts when a watched key changed.
+func (client *Client) Txn(ctx context.Context, fn func(storage.Txn) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = client.db.Watch(func(tx *redis.Tx) error {
+		txn := &txn{tx: tx}
+		if err := fn(txn); err != nil {
+			return err
+		}
+
+		puts, deletes := txn.writes.Split()
+		if len(puts) == 0 && len(deletes) == 0 {
+			return nil
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			for _, item := range puts {
+				pipe.Set(item.Key.String(), []byte(item.Value), client.TTL)
+			}
+			if len(deletes) > 0 {
+				pipe.Del(deletes.Strings()...)
+			}
+			return nil
+		})
+		return err
+	})
+	if err == redis.TxFailedErr {
+		return storage.ErrTxnConflict.New("watched key changed")
+	}
+	return err
+}
+
+// txn is a storage.Txn keeping the writes until they are applied
+type txn struct {
+	tx     *redis.Tx
+	writes storage.WriteSet
+}
+
+// Get watches key and looks it up
+func (txn *txn) Get(key storage.Key) (storage.Value, error) {
+	if value, found, ok := txn.writes.Lookup(key); ok {
+		if !found {
+			return nil, storage.ErrKeyNotFound.New(key.String())
+		}
+		return value, nil
+	}
+	if err := txn.tx.Watch(key.String()).Err(); err != nil {
+		return nil, Error.New("watch error: %v", err)
+	}
+	return get(txn.tx, key)
+}
+
+// Put adds a value to key when the transaction is applied
+func (txn *txn) Put(key storage.Key, value storage.Value) error {
+	if len(key) == 0 {
+		return Error.New("invalid key")
+	}
+	txn.writes.Put(key, value)
+	return nil
+}
+
+// Delete deletes key when the transaction is applied
+func (txn *txn) Delete(key storage.Key) error {
+	txn.writes.Delete(key)
+	return nil
+}
+
 // Close closes a redis client
 func (client *Client) Close() error {
 	return client.db.Close()
@@ -96,7 +217,8 @@ func (client *Client) Close() error {
 // GetAll is the bulk method for gets from the redis data store
 // The maximum keys returned will be 100. If more than that is requested an
 // error will be returned
-func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
+func (client *Client) GetAll(ctx context.Context, keys storage.Keys) (_ storage.Values, err error) {
+	defer mon.Task()(&ctx)(&err)
 	if len(keys) > storage.LookupLimit {
 		return nil, storage.ErrLimitExceeded
 	}
@@ -127,9 +249,9 @@ func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 }
 
 // Iterate iterates over items based on opts
-func (client *Client) Iterate(opts storage.IterateOptions, fn func(it storage.Iterator) error) error {
+func (client *Client) Iterate(ctx context.Context, opts storage.IterateOptions, fn func(it storage.Iterator) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
 	var all storage.Items
-	var err error
 	if !opts.Reverse {
 		all, err = client.allPrefixedItems(opts.Prefix, opts.First, nil)
 	} else {
@@ -4,8 +4,10 @@
 package redis
 
 import (
+	"context"
 	"testing"
 
+	"storj.io/storj/storage"
 	"storj.io/storj/storage/redis/redisserver"
 	"storj.io/storj/storage/testsuite"
 )
@@ -25,6 +27,47 @@ func TestSuite(t *testing.T) {
 	testsuite.RunTests(t, client)
 }
 
+func TestTxnConflict(t *testing.T) {
+	addr, cleanup, err := redisserver.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	client, err := NewClient(addr, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := storage.Key("key")
+
+	if err := client.Put(ctx, key, storage.Value("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Txn(ctx, func(txn storage.Txn) error {
+		if _, err := txn.Get(key); err != nil {
+			return err
+		}
+		// someone else changes the key before the transaction is applied
+		if err := client.Put(ctx, key, storage.Value("b")); err != nil {
+			return err
+		}
+		return txn.Put(key, storage.Value("c"))
+	})
+	if !storage.ErrTxnConflict.Has(err) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	value, err := client.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "b" {
+		t.Fatalf("conflicting transaction was applied: got %q", value)
+	}
+}
+
 func TestInvalidConnection(t *testing.T) {
 	_, err := NewClient("", "", 1)
 	if err == nil {
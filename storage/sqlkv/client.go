@@ -0,0 +1,414 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sqlkv
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is a sqlkv error
+	Error = errs.Class("sqlkv error")
+
+	validTable = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+)
+
+// busyTimeout is how long SQLite waits for the locks held by other
+// processes before a query fails
+const busyTimeout = 10 * time.Second
+
+// dialect contains the queries that differ between databases
+type dialect struct {
+	driver string
+	// source returns the data source name the database is opened with
+	source func(source string) string
+	// maxOpenConns limits the connections to the database, 0 is unlimited
+	maxOpenConns int
+	// numbered is whether placeholders are $1, $2, ... instead of ?
+	numbered bool
+	schema   string
+	put      string
+	// isolation is the isolation level of transactions
+	isolation sql.IsolationLevel
+	// conflict returns whether err is caused by concurrent transactions
+	conflict func(err error) bool
+}
+
+var (
+	postgres = dialect{
+		driver:   "postgres",
+		source:   func(source string) string { return source },
+		numbered: true,
+		schema:   `CREATE TABLE IF NOT EXISTS %s (key BYTEA PRIMARY KEY, value BYTEA NOT NULL)`,
+		put: `INSERT INTO %s (key, value) VALUES (?, ?)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value`,
+		isolation: sql.LevelSerializable,
+		conflict: func(err error) bool {
+			// serialization_failure
+			pqErr, ok := err.(*pq.Error)
+			return ok && pqErr.Code == "40001"
+		},
+	}
+	sqlite = dialect{
+		driver: "sqlite3",
+		// the write lock is taken when a transaction begins, so that a
+		// transaction waits for the other writers instead of failing when
+		// it starts writing
+		source: func(path string) string {
+			sep := "?"
+			if strings.Contains(path, "?") {
+				sep = "&"
+			}
+			return path + sep + "_busy_timeout=" + strconv.Itoa(int(busyTimeout/time.Millisecond)) + "&_txlock=immediate"
+		},
+		// SQLite only allows a writer at a time, a single connection keeps
+		// the clients of the process from finding the database busy
+		maxOpenConns: 1,
+		schema:       `CREATE TABLE IF NOT EXISTS %s (key BLOB PRIMARY KEY, value BLOB NOT NULL)`,
+		put:          `INSERT OR REPLACE INTO %s (key, value) VALUES (?, ?)`,
+		// sqlite transactions are always serializable, a writer waiting
+		// on another process for longer than the busy timeout finds the
+		// database busy instead
+		isolation: sql.LevelDefault,
+		conflict: func(err error) bool {
+			sqliteErr, ok := err.(sqlite3.Error)
+			return ok && sqliteErr.Code == sqlite3.ErrBusy
+		},
+	}
+)
+
+// Client is a storage.KeyValueStore keeping its items in a table of a SQL
+// database
+type Client struct {
+	db      *sql.DB
+	dialect dialect
+	source  string
+	table   string
+}
+
+// handles are the databases opened by the clients of the process. The
+// clients of the same database share a handle, as the connection limit of
+// the dialect applies to the whole process.
+var handles = struct {
+	mu  sync.Mutex
+	dbs map[string]*handle
+}{dbs: map[string]*handle{}}
+
+// handle is a database shared by refs clients
+type handle struct {
+	db   *sql.DB
+	refs int
+}
+
+// openHandle returns the database at source, opening it if no client of
+// the process has
+func openHandle(dialect dialect, source string) (*sql.DB, error) {
+	handles.mu.Lock()
+	defer handles.mu.Unlock()
+
+	key := dialect.driver + ":" + source
+	if h, ok := handles.dbs[key]; ok {
+		h.refs++
+		return h.db, nil
+	}
+
+	db, err := sql.Open(dialect.driver, dialect.source(source))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(dialect.maxOpenConns)
+	handles.dbs[key] = &handle{db: db, refs: 1}
+	return db, nil
+}
+
+// closeHandle closes the database at source once no client uses it
+func closeHandle(dialect dialect, source string) error {
+	handles.mu.Lock()
+	defer handles.mu.Unlock()
+
+	key := dialect.driver + ":" + source
+	h, ok := handles.dbs[key]
+	if !ok {
+		return Error.New("database %s isn't open", source)
+	}
+	h.refs--
+	if h.refs > 0 {
+		return nil
+	}
+	delete(handles.dbs, key)
+	return h.db.Close()
+}
+
+// New instantiates a client storing items in table of the PostgreSQL
+// database at url, creating the table if needed
+func New(url, table string) (*Client, error) {
+	return open(postgres, url, table)
+}
+
+// NewSQLite instantiates a client storing items in table of the SQLite
+// database at path, creating the table if needed
+func NewSQLite(path, table string) (*Client, error) {
+	return open(sqlite, path, table)
+}
+
+// OpenURL opens a client for a postgres://, postgresql:// or sqlite3://
+// database url
+func OpenURL(databaseURL, table string) (*Client, error) {
+	switch {
+	case strings.HasPrefix(databaseURL, "postgres://"),
+		strings.HasPrefix(databaseURL, "postgresql://"):
+		return New(databaseURL, table)
+	case strings.HasPrefix(databaseURL, "sqlite3://"):
+		return NewSQLite(strings.TrimPrefix(databaseURL, "sqlite3://"), table)
+	default:
+		return nil, Error.New("unsupported database url: %s", databaseURL)
+	}
+}
+
+func open(dialect dialect, source, table string) (*Client, error) {
+	if !validTable.MatchString(table) {
+		return nil, Error.New("invalid table name: %q", table)
+	}
+
+	db, err := openHandle(dialect, source)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	client := &Client{
+		db:      db,
+		dialect: dialect,
+		source:  source,
+		table:   table,
+	}
+
+	_, err = db.Exec(client.query(dialect.schema))
+	if err != nil {
+		return nil, Error.Wrap(utils.CombineErrors(err, closeHandle(dialect, source)))
+	}
+
+	return client, nil
+}
+
+// query fills in the table name and the placeholders of the dialect
+func (client *Client) query(query string) string {
+	query = strings.Replace(query, "%s", client.table, -1)
+	if !client.dialect.numbered {
+		return query
+	}
+
+	var numbered bytes.Buffer
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			numbered.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		numbered.WriteRune(r)
+	}
+	return numbered.String()
+}
+
+// queryer is implemented by both databases and transactions
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Put adds a value to the provided key, returning an error on failure
+func (client *Client) Put(ctx context.Context, key storage.Key, value storage.Value) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.put(ctx, client.db, key, value)
+}
+
+func (client *Client) put(ctx context.Context, db queryer, key storage.Key, value storage.Value) error {
+	if len(key) == 0 {
+		return Error.New("invalid key")
+	}
+	if value == nil {
+		value = storage.Value{}
+	}
+
+	_, err := db.ExecContext(ctx, client.query(client.dialect.put), []byte(key), []byte(value))
+	if err != nil {
+		return client.wrap(err)
+	}
+	return nil
+}
+
+// Get looks up the provided key, returning either an error or the result
+func (client *Client) Get(ctx context.Context, key storage.Key) (_ storage.Value, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.get(ctx, client.db, key)
+}
+
+func (client *Client) get(ctx context.Context, db queryer, key storage.Key) (storage.Value, error) {
+	var value []byte
+	err := db.QueryRowContext(ctx, client.query(`SELECT value FROM %s WHERE key = ?`), []byte(key)).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrKeyNotFound.New(key.String())
+	}
+	if err != nil {
+		return nil, client.wrap(err)
+	}
+	return storage.Value(value), nil
+}
+
+// GetAll finds all values for the provided keys. Values are nil for keys
+// without an item. If more keys than storage.LookupLimit are provided an
+// error is returned.
+func (client *Client) GetAll(ctx context.Context, keys storage.Keys) (_ storage.Values, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if len(keys) > storage.LookupLimit {
+		return nil, storage.ErrLimitExceeded
+	}
+
+	values := make(storage.Values, 0, len(keys))
+	for _, key := range keys {
+		value, err := client.get(ctx, client.db, key)
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// Delete deletes the item with the provided key
+func (client *Client) Delete(ctx context.Context, key storage.Key) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.delete(ctx, client.db, key)
+}
+
+func (client *Client) delete(ctx context.Context, db queryer, key storage.Key) error {
+	_, err := db.ExecContext(ctx, client.query(`DELETE FROM %s WHERE key = ?`), []byte(key))
+	if err != nil {
+		return client.wrap(err)
+	}
+	return nil
+}
+
+// PutBatch adds all items in a single transaction
+func (client *Client) PutBatch(ctx context.Context, items storage.Items) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.withTx(ctx, sql.LevelDefault, func(tx *sql.Tx) error {
+		for _, item := range items {
+			if err := client.put(ctx, tx, item.Key, item.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteBatch deletes all keys in a single transaction
+func (client *Client) DeleteBatch(ctx context.Context, keys storage.Keys) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.withTx(ctx, sql.LevelDefault, func(tx *sql.Tx) error {
+		for _, key := range keys {
+			if err := client.delete(ctx, tx, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Txn runs fn in a serializable database transaction
+func (client *Client) Txn(ctx context.Context, fn func(storage.Txn) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return client.withTx(ctx, client.dialect.isolation, func(tx *sql.Tx) error {
+		return fn(&txn{ctx: ctx, client: client, tx: tx})
+	})
+}
+
+// withTx runs fn in a database transaction, committing it when fn succeeds
+func (client *Client) withTx(ctx context.Context, isolation sql.IsolationLevel, fn func(*sql.Tx) error) error {
+	tx, err := client.db.BeginTx(ctx, &sql.TxOptions{Isolation: isolation})
+	if err != nil {
+		return client.wrap(err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return utils.CombineErrors(err, client.wrap(rollbackErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return client.wrap(err)
+	}
+	return nil
+}
+
+// wrap wraps database errors, errors caused by concurrent transactions
+// become storage.ErrTxnConflict errors
+func (client *Client) wrap(err error) error {
+	if client.dialect.conflict(err) {
+		return storage.ErrTxnConflict.Wrap(err)
+	}
+	return Error.Wrap(err)
+}
+
+// txn is a storage.Txn of a database transaction
+type txn struct {
+	ctx    context.Context
+	client *Client
+	tx     *sql.Tx
+}
+
+// Get looks up key within the transaction
+func (txn *txn) Get(key storage.Key) (storage.Value, error) {
+	return txn.client.get(txn.ctx, txn.tx, key)
+}
+
+// Put adds a value to key within the transaction
+func (txn *txn) Put(key storage.Key, value storage.Value) error {
+	return txn.client.put(txn.ctx, txn.tx, key, value)
+}
+
+// Delete deletes key within the transaction
+func (txn *txn) Delete(key storage.Key) error {
+	return txn.client.delete(txn.ctx, txn.tx, key)
+}
+
+// List returns either a list of keys for which the table has values or an
+// error
+func (client *Client) List(ctx context.Context, first storage.Key, limit int) (_ storage.Keys, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return storage.ListKeys(ctx, client, first, limit)
+}
+
+// ReverseList returns either a list of keys for which the table has values
+// or an error. Starts from first and iterates backwards.
+func (client *Client) ReverseList(ctx context.Context, first storage.Key, limit int) (_ storage.Keys, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return storage.ReverseListKeys(ctx, client, first, limit)
+}
+
+// Close closes the database once the other clients of the process using it
+// are closed
+func (client *Client) Close() error {
+	if err := closeHandle(client.dialect, client.source); err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
+}
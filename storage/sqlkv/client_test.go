@@ -0,0 +1,191 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sqlkv
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/testsuite"
+)
+
+var postgresTestDB = flag.String("postgres-test-db", os.Getenv("STORJ_POSTGRES_TEST"),
+	"PostgreSQL test database connection string, the postgres tests are skipped when empty")
+
+func newPostgresTestClient(t testing.TB) (*Client, func()) {
+	if *postgresTestDB == "" {
+		t.Skip("postgres test database not configured")
+	}
+
+	client, err := New(*postgresTestDB, "storj_test")
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	// start from an empty table in case a previous run was interrupted
+	if _, err := client.db.Exec(client.query(`DELETE FROM %s`)); err != nil {
+		t.Fatal(err)
+	}
+
+	return client, func() {
+		if _, err := client.db.Exec(client.query(`DROP TABLE %s`)); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Close(); err != nil {
+			t.Fatalf("failed to close db: %v", err)
+		}
+	}
+}
+
+func newSQLiteTestClient(t testing.TB) (*Client, func()) {
+	tempdir, err := ioutil.TempDir("", "storj-sqlkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewSQLite(filepath.Join(tempdir, "kv.db"), "storj_test")
+	if err != nil {
+		_ = os.RemoveAll(tempdir)
+		t.Fatalf("failed to create db: %v", err)
+	}
+
+	return client, func() {
+		defer func() { _ = os.RemoveAll(tempdir) }()
+		if err := client.Close(); err != nil {
+			t.Fatalf("failed to close db: %v", err)
+		}
+	}
+}
+
+func TestSuite(t *testing.T) {
+	client, cleanup := newPostgresTestClient(t)
+	defer cleanup()
+
+	testsuite.RunTests(t, client)
+}
+
+func TestSuiteSQLite(t *testing.T) {
+	client, cleanup := newSQLiteTestClient(t)
+	defer cleanup()
+
+	testsuite.RunTests(t, client)
+}
+
+func BenchmarkSuite(b *testing.B) {
+	client, cleanup := newPostgresTestClient(b)
+	defer cleanup()
+
+	testsuite.RunBenchmarks(b, client)
+}
+
+func BenchmarkSuiteSQLite(b *testing.B) {
+	client, cleanup := newSQLiteTestClient(b)
+	defer cleanup()
+
+	testsuite.RunBenchmarks(b, client)
+}
+
+func TestOpenURL(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "storj-sqlkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tempdir) }()
+
+	client, err := OpenURL("sqlite3://"+filepath.Join(tempdir, "kv.db"), "storj_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{"bolt://kv.db", "mysql://localhost/kv", "kv.db"} {
+		if _, err := OpenURL(url, "storj_test"); err == nil {
+			t.Errorf("expected an error for %q", url)
+		}
+	}
+	if _, err := OpenURL("sqlite3://"+filepath.Join(tempdir, "kv.db"), "drop table"); err == nil {
+		t.Error("expected an error for an invalid table name")
+	}
+}
+
+func TestSQLiteShared(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "storj-sqlkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tempdir) }()
+
+	path := filepath.Join(tempdir, "kv.db")
+	a, err := NewSQLite(path, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSQLite(path, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.db != b.db {
+		t.Fatal("the clients of a database don't share a handle")
+	}
+
+	// the writers of the clients wait for each other
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 50; i++ {
+		key := storage.Key(fmt.Sprintf("key%d", i))
+		for _, client := range []*Client{a, b} {
+			wg.Add(1)
+			go func(client *Client) {
+				defer wg.Done()
+				errs <- client.Put(ctx, key, storage.Value("value"))
+			}(client)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the handle stays open for the remaining client
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get(ctx, storage.Key("key0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err == nil {
+		t.Fatal("expected an error closing a closed client")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	client := &Client{dialect: postgres, table: "pointers"}
+	query := client.query(`SELECT key, value FROM %s WHERE key >= ? ORDER BY key ASC LIMIT ?`)
+	expected := `SELECT key, value FROM pointers WHERE key >= $1 ORDER BY key ASC LIMIT $2`
+	if query != expected {
+		t.Errorf("expected %q, got %q", expected, query)
+	}
+
+	client = &Client{dialect: sqlite, table: "pointers"}
+	query = client.query(`SELECT value FROM %s WHERE key = ?`)
+	expected = `SELECT value FROM pointers WHERE key = ?`
+	if query != expected {
+		t.Errorf("expected %q, got %q", expected, query)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sqlkv
+
+import (
+	"bytes"
+	"context"
+
+	"storj.io/storj/storage"
+)
+
+// batchSize is the number of rows a cursor fetches at once
+const batchSize = 100
+
+// Iterate iterates over items based on opts
+func (client *Client) Iterate(ctx context.Context, opts storage.IterateOptions, fn func(storage.Iterator) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	cursor := &cursor{ctx: ctx, client: client, desc: opts.Reverse}
+
+	start := true
+	lastPrefix := []byte{}
+	wasPrefix := false
+
+	err = fn(storage.IteratorFunc(func(item *storage.ListItem) bool {
+		var key, value []byte
+		if start {
+			key, value = cursor.positionToFirst(opts.Prefix, opts.First)
+			start = false
+		} else {
+			key, value = cursor.advance()
+		}
+
+		if !opts.Recurse {
+			// when non-recursive skip all items that have the same prefix
+			if wasPrefix && bytes.HasPrefix(key, lastPrefix) {
+				key, value = cursor.skipPrefix(lastPrefix)
+				wasPrefix = false
+			}
+		}
+
+		if len(key) == 0 || !bytes.HasPrefix(key, opts.Prefix) {
+			return false
+		}
+
+		if !opts.Recurse {
+			// check whether the entry is a proper prefix
+			if p := bytes.IndexByte(key[len(opts.Prefix):], storage.Delimiter); p >= 0 {
+				key = key[:len(opts.Prefix)+p+1]
+				lastPrefix = append(lastPrefix[:0], key...)
+
+				item.Key = append(item.Key[:0], storage.Key(lastPrefix)...)
+				item.Value = item.Value[:0]
+				item.IsPrefix = true
+
+				wasPrefix = true
+				return true
+			}
+		}
+
+		item.Key = append(item.Key[:0], storage.Key(key)...)
+		item.Value = append(item.Value[:0], storage.Value(value)...)
+		item.IsPrefix = false
+
+		return true
+	}))
+	if err != nil {
+		return err
+	}
+	return cursor.err
+}
+
+// row is a single key/value pair read by a cursor
+type row struct {
+	key, value []byte
+}
+
+// cursor walks the table in key order, or reverse key order when desc is
+// set, fetching batchSize rows at a time. The first error stops the cursor
+// and is kept in err.
+type cursor struct {
+	ctx    context.Context
+	client *Client
+	desc   bool
+
+	batch []row
+	pos   int
+	more  bool
+
+	err error
+}
+
+// positionToFirst positions the cursor on the first item to return, the
+// same way the boltdb cursors do
+func (cursor *cursor) positionToFirst(prefix, first storage.Key) (key, value []byte) {
+	if !cursor.desc {
+		if first.IsZero() || first.Less(prefix) {
+			return cursor.seek(prefix, true)
+		}
+		return cursor.seek(first, true)
+	}
+
+	if prefix.IsZero() {
+		if first.IsZero() {
+			// no prefix and no first item, so start from the end
+			return cursor.seek(nil, true)
+		}
+	} else if first.IsZero() || storage.AfterPrefix(prefix).Less(first) {
+		// first is after the prefix, so start from the last prefixed item
+		return cursor.seek(storage.AfterPrefix(prefix), false)
+	}
+	return cursor.seek(first, true)
+}
+
+// skipPrefix positions the cursor on the first item past all items with
+// prefix
+func (cursor *cursor) skipPrefix(prefix storage.Key) (key, value []byte) {
+	if !cursor.desc {
+		return cursor.seek(storage.AfterPrefix(prefix), true)
+	}
+	return cursor.seek(prefix, false)
+}
+
+// advance moves the cursor to the next item
+func (cursor *cursor) advance() (key, value []byte) {
+	if cursor.pos >= len(cursor.batch) {
+		return nil, nil
+	}
+	cursor.pos++
+	if cursor.pos < len(cursor.batch) {
+		return cursor.current()
+	}
+	if !cursor.more {
+		return nil, nil
+	}
+	return cursor.seek(cursor.batch[len(cursor.batch)-1].key, false)
+}
+
+// current returns the item the cursor is positioned on
+func (cursor *cursor) current() (key, value []byte) {
+	if cursor.pos >= len(cursor.batch) {
+		return nil, nil
+	}
+	current := cursor.batch[cursor.pos]
+	return current.key, current.value
+}
+
+// seek fetches the batch starting at bound, which is included when
+// inclusive is set. A nil bound starts from the beginning of the table, or
+// the end when the cursor is descending.
+func (cursor *cursor) seek(bound storage.Key, inclusive bool) (key, value []byte) {
+	cursor.batch, cursor.pos, cursor.more = cursor.batch[:0], 0, false
+	if cursor.err != nil {
+		return nil, nil
+	}
+
+	op, order := ">", "ASC"
+	if cursor.desc {
+		op, order = "<", "DESC"
+	}
+	if inclusive {
+		op += "="
+	}
+
+	query := `SELECT key, value FROM %s ORDER BY key ` + order + ` LIMIT ?`
+	args := []interface{}{batchSize}
+	if !bound.IsZero() {
+		query = `SELECT key, value FROM %s WHERE key ` + op + ` ? ORDER BY key ` + order + ` LIMIT ?`
+		args = []interface{}{[]byte(bound), batchSize}
+	}
+
+	rows, err := cursor.client.db.QueryContext(cursor.ctx, cursor.client.query(query), args...)
+	if err != nil {
+		cursor.err = Error.Wrap(err)
+		return nil, nil
+	}
+	for rows.Next() {
+		var next row
+		if err := rows.Scan(&next.key, &next.value); err != nil {
+			cursor.err = Error.Wrap(err)
+			break
+		}
+		cursor.batch = append(cursor.batch, next)
+	}
+	if err := rows.Err(); err != nil && cursor.err == nil {
+		cursor.err = Error.Wrap(err)
+	}
+	if err := rows.Close(); err != nil && cursor.err == nil {
+		cursor.err = Error.Wrap(err)
+	}
+	if cursor.err != nil {
+		cursor.batch = cursor.batch[:0]
+		return nil, nil
+	}
+
+	cursor.more = len(cursor.batch) == batchSize
+	return cursor.current()
+}
@@ -4,6 +4,7 @@
 package storelogger
 
 import (
+	"context"
 	"strconv"
 	"sync/atomic"
 
@@ -28,52 +29,52 @@ func New(log *zap.Logger, store storage.KeyValueStore) *Logger {
 }
 
 // Put adds a value to store
-func (store *Logger) Put(key storage.Key, value storage.Value) error {
+func (store *Logger) Put(ctx context.Context, key storage.Key, value storage.Value) error {
 	store.log.Debug("Put", zap.String("key", string(key)), zap.Binary("value", []byte(value)))
-	return store.store.Put(key, value)
+	return store.store.Put(ctx, key, value)
 }
 
 // Get gets a value to store
-func (store *Logger) Get(key storage.Key) (storage.Value, error) {
+func (store *Logger) Get(ctx context.Context, key storage.Key) (storage.Value, error) {
 	store.log.Debug("Get", zap.String("key", string(key)))
-	return store.store.Get(key)
+	return store.store.Get(ctx, key)
 }
 
 // GetAll gets all values from the store corresponding to keys
-func (store *Logger) GetAll(keys storage.Keys) (storage.Values, error) {
+func (store *Logger) GetAll(ctx context.Context, keys storage.Keys) (storage.Values, error) {
 	store.log.Debug("GetAll", zap.Any("keys", keys))
-	return store.store.GetAll(keys)
+	return store.store.GetAll(ctx, keys)
 }
 
 // Delete deletes key and the value
-func (store *Logger) Delete(key storage.Key) error {
+func (store *Logger) Delete(ctx context.Context, key storage.Key) error {
 	store.log.Debug("Delete", zap.String("key", string(key)))
-	return store.store.Delete(key)
+	return store.store.Delete(ctx, key)
 }
 
 // List lists all keys starting from first and upto limit items
-func (store *Logger) List(first storage.Key, limit int) (storage.Keys, error) {
-	keys, err := store.store.List(first, limit)
+func (store *Logger) List(ctx context.Context, first storage.Key, limit int) (storage.Keys, error) {
+	keys, err := store.store.List(ctx, first, limit)
 	store.log.Debug("List", zap.String("first", string(first)), zap.Int("limit", limit), zap.Any("keys", keys.Strings()))
 	return keys, err
 }
 
 // ReverseList lists all keys in reverse order, starting from first
-func (store *Logger) ReverseList(first storage.Key, limit int) (storage.Keys, error) {
-	keys, err := store.store.ReverseList(first, limit)
+func (store *Logger) ReverseList(ctx context.Context, first storage.Key, limit int) (storage.Keys, error) {
+	keys, err := store.store.ReverseList(ctx, first, limit)
 	store.log.Debug("ReverseList", zap.String("first", string(first)), zap.Int("limit", limit), zap.Any("keys", keys.Strings()))
 	return keys, err
 }
 
 // Iterate iterates over items based on opts
-func (store *Logger) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+func (store *Logger) Iterate(ctx context.Context, opts storage.IterateOptions, fn func(storage.Iterator) error) error {
 	store.log.Debug("Iterate",
 		zap.String("prefix", string(opts.Prefix)),
 		zap.String("first", string(opts.First)),
 		zap.Bool("recurse", opts.Recurse),
 		zap.Bool("reverse", opts.Reverse),
 	)
-	return store.store.Iterate(opts, func(it storage.Iterator) error {
+	return store.store.Iterate(ctx, opts, func(it storage.Iterator) error {
 		return fn(storage.IteratorFunc(func(item *storage.ListItem) bool {
 			ok := it.Next(item)
 			if ok {
@@ -84,6 +85,55 @@ func (store *Logger) Iterate(opts storage.IterateOptions, fn func(storage.Iterat
 	})
 }
 
+// PutBatch adds all items to the store at once
+func (store *Logger) PutBatch(ctx context.Context, items storage.Items) error {
+	store.log.Debug("PutBatch", zap.Int("count", len(items)))
+	for _, item := range items {
+		store.log.Debug("  ", zap.String("key", string(item.Key)), zap.Binary("value", item.Value))
+	}
+	return store.store.PutBatch(ctx, items)
+}
+
+// DeleteBatch deletes all keys from the store at once
+func (store *Logger) DeleteBatch(ctx context.Context, keys storage.Keys) error {
+	store.log.Debug("DeleteBatch", zap.Any("keys", keys.Strings()))
+	return store.store.DeleteBatch(ctx, keys)
+}
+
+// Txn runs fn in a transaction, logging everything it does
+func (store *Logger) Txn(ctx context.Context, fn func(storage.Txn) error) error {
+	store.log.Debug("Txn")
+	err := store.store.Txn(ctx, func(txn storage.Txn) error {
+		return fn(&loggedTxn{store.log, txn})
+	})
+	store.log.Debug("Txn done", zap.Error(err))
+	return err
+}
+
+// loggedTxn logs the operations of a transaction
+type loggedTxn struct {
+	log *zap.Logger
+	txn storage.Txn
+}
+
+// Get gets a value within the transaction
+func (txn *loggedTxn) Get(key storage.Key) (storage.Value, error) {
+	txn.log.Debug("  Get", zap.String("key", string(key)))
+	return txn.txn.Get(key)
+}
+
+// Put adds a value within the transaction
+func (txn *loggedTxn) Put(key storage.Key, value storage.Value) error {
+	txn.log.Debug("  Put", zap.String("key", string(key)), zap.Binary("value", []byte(value)))
+	return txn.txn.Put(key, value)
+}
+
+// Delete deletes key within the transaction
+func (txn *loggedTxn) Delete(key storage.Key) error {
+	txn.log.Debug("  Delete", zap.String("key", string(key)))
+	return txn.txn.Delete(key)
+}
+
 // Close closes the store
 func (store *Logger) Close() error {
 	store.log.Debug("Close")
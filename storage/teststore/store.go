@@ -5,16 +5,21 @@ package teststore
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"sort"
+	"sync"
 
 	"storj.io/storj/storage"
 )
 
 var errInternal = errors.New("internal error")
 
-// Client implements in-memory key value store
+// Client implements in-memory key value store. It is safe for concurrent
+// use, but the exported fields must only be used while it isn't used.
 type Client struct {
+	mu sync.Mutex
+
 	Items      []storage.ListItem
 	ForceError int
 
@@ -27,6 +32,9 @@ type Client struct {
 		Delete      int
 		Close       int
 		Iterate     int
+		PutBatch    int
+		DeleteBatch int
+		Txn         int
 	}
 
 	version int
@@ -56,8 +64,9 @@ func (store *Client) forcedError() bool {
 }
 
 // Put adds a value to store
-func (store *Client) Put(key storage.Key, value storage.Value) error {
-	store.version++
+func (store *Client) Put(ctx context.Context, key storage.Key, value storage.Value) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
 	store.CallCount.Put++
 	if store.forcedError() {
 		return errInternal
@@ -67,11 +76,17 @@ func (store *Client) Put(key storage.Key, value storage.Value) error {
 		return storage.ErrEmptyKey
 	}
 
+	store.put(key, value)
+	return nil
+}
+
+func (store *Client) put(key storage.Key, value storage.Value) {
+	store.version++
 	keyIndex, found := store.indexOf(key)
 	if found {
 		kv := &store.Items[keyIndex]
 		kv.Value = storage.CloneValue(value)
-		return nil
+		return
 	}
 
 	store.Items = append(store.Items, storage.ListItem{})
@@ -80,18 +95,22 @@ func (store *Client) Put(key storage.Key, value storage.Value) error {
 		Key:   storage.CloneKey(key),
 		Value: storage.CloneValue(value),
 	}
-
-	return nil
 }
 
 // Get gets a value to store
-func (store *Client) Get(key storage.Key) (storage.Value, error) {
+func (store *Client) Get(ctx context.Context, key storage.Key) (storage.Value, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
 	store.CallCount.Get++
 
 	if store.forcedError() {
 		return nil, errors.New("internal error")
 	}
 
+	return store.get(key)
+}
+
+func (store *Client) get(key storage.Key) (storage.Value, error) {
 	keyIndex, found := store.indexOf(key)
 	if !found {
 		return nil, storage.ErrKeyNotFound.New(key.String())
@@ -101,7 +120,9 @@ func (store *Client) Get(key storage.Key) (storage.Value, error) {
 }
 
 // GetAll gets all values from the store
-func (store *Client) GetAll(keys storage.Keys) (storage.Values, error) {
+func (store *Client) GetAll(ctx context.Context, keys storage.Keys) (storage.Values, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
 	store.CallCount.GetAll++
 	if len(keys) > storage.LookupLimit {
 		return nil, storage.ErrLimitExceeded
@@ -124,7 +145,9 @@ func (store *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 }
 
 // Delete deletes key and the value
-func (store *Client) Delete(key storage.Key) error {
+func (store *Client) Delete(ctx context.Context, key storage.Key) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
 	store.version++
 	store.CallCount.Delete++
 
@@ -132,36 +155,145 @@ func (store *Client) Delete(key storage.Key) error {
 		return errInternal
 	}
 
+	if !store.delete(key) {
+		return storage.ErrKeyNotFound.New(key.String())
+	}
+	return nil
+}
+
+// delete deletes key and returns whether it existed
+func (store *Client) delete(key storage.Key) bool {
 	keyIndex, found := store.indexOf(key)
 	if !found {
-		return storage.ErrKeyNotFound.New(key.String())
+		return false
 	}
 
+	store.version++
 	copy(store.Items[keyIndex:], store.Items[keyIndex+1:])
 	store.Items = store.Items[:len(store.Items)-1]
+	return true
+}
+
+// PutBatch adds all items to the store
+func (store *Client) PutBatch(ctx context.Context, items storage.Items) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.CallCount.PutBatch++
+	if store.forcedError() {
+		return errInternal
+	}
+
+	for _, item := range items {
+		if item.Key.IsZero() {
+			return storage.ErrEmptyKey
+		}
+	}
+	for _, item := range items {
+		store.put(item.Key, item.Value)
+	}
+	return nil
+}
+
+// DeleteBatch deletes all keys from the store, missing keys are ignored
+func (store *Client) DeleteBatch(ctx context.Context, keys storage.Keys) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.CallCount.DeleteBatch++
+	if store.forcedError() {
+		return errInternal
+	}
+
+	for _, key := range keys {
+		store.delete(key)
+	}
+	return nil
+}
+
+// Txn runs fn keeping its writes until it succeeds. The store is locked
+// while fn runs, so transactions never conflict.
+func (store *Client) Txn(ctx context.Context, fn func(storage.Txn) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.CallCount.Txn++
+	if store.forcedError() {
+		return errInternal
+	}
+
+	txn := &txn{store: store}
+	if err := fn(txn); err != nil {
+		return err
+	}
+
+	puts, deletes := txn.writes.Split()
+	for _, item := range puts {
+		store.put(item.Key, item.Value)
+	}
+	for _, key := range deletes {
+		store.delete(key)
+	}
+	return nil
+}
+
+// txn is a storage.Txn of the store
+type txn struct {
+	store  *Client
+	writes storage.WriteSet
+}
+
+// Get gets a value as seen by the transaction
+func (txn *txn) Get(key storage.Key) (storage.Value, error) {
+	if value, found, ok := txn.writes.Lookup(key); ok {
+		if !found {
+			return nil, storage.ErrKeyNotFound.New(key.String())
+		}
+		return value, nil
+	}
+	return txn.store.get(key)
+}
+
+// Put adds a value when the transaction succeeds
+func (txn *txn) Put(key storage.Key, value storage.Value) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey
+	}
+	txn.writes.Put(key, value)
+	return nil
+}
+
+// Delete deletes key when the transaction succeeds
+func (txn *txn) Delete(key storage.Key) error {
+	txn.writes.Delete(key)
 	return nil
 }
 
 // List lists all keys starting from start and upto limit items
-func (store *Client) List(first storage.Key, limit int) (storage.Keys, error) {
+func (store *Client) List(ctx context.Context, first storage.Key, limit int) (storage.Keys, error) {
+	store.mu.Lock()
 	store.CallCount.List++
-	if store.forcedError() {
+	forced := store.forcedError()
+	store.mu.Unlock()
+	if forced {
 		return nil, errors.New("internal error")
 	}
-	return storage.ListKeys(store, first, limit)
+	return storage.ListKeys(ctx, store, first, limit)
 }
 
 // ReverseList lists all keys in revers order
-func (store *Client) ReverseList(first storage.Key, limit int) (storage.Keys, error) {
+func (store *Client) ReverseList(ctx context.Context, first storage.Key, limit int) (storage.Keys, error) {
+	store.mu.Lock()
 	store.CallCount.ReverseList++
-	if store.forcedError() {
+	forced := store.forcedError()
+	store.mu.Unlock()
+	if forced {
 		return nil, errors.New("internal error")
 	}
-	return storage.ReverseListKeys(store, first, limit)
+	return storage.ReverseListKeys(ctx, store, first, limit)
 }
 
 // Close closes the store
 func (store *Client) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
 	store.CallCount.Close++
 	if store.forcedError() {
 		return errInternal
@@ -170,9 +302,11 @@ func (store *Client) Close() error {
 }
 
 // Iterate iterates over items based on opts
-func (store *Client) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+func (store *Client) Iterate(ctx context.Context, opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+	store.mu.Lock()
 	store.CallCount.Iterate++
 	if store.forcedError() {
+		store.mu.Unlock()
 		return errInternal
 	}
 
@@ -184,10 +318,15 @@ func (store *Client) Iterate(opts storage.IterateOptions, fn func(storage.Iterat
 	}
 
 	cursor.PositionToFirst(opts.Prefix, opts.First)
+	store.mu.Unlock()
 	var lastPrefix storage.Key
 	var wasPrefix bool
 
+	// the store is only locked while the cursor moves, so fn may use it
 	return fn(storage.IteratorFunc(func(item *storage.ListItem) bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
 		next, ok := cursor.Advance()
 		if !ok {
 			return false
@@ -43,7 +43,7 @@ func RunBenchmarks(b *testing.B, store storage.KeyValueStore) {
 		b.SetBytes(int64(len(items)))
 		for k := 0; k < b.N; k++ {
 			for _, item := range items {
-				err := store.Put(item.Key, item.Value)
+				err := store.Put(ctx, item.Key, item.Value)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -55,7 +55,7 @@ func RunBenchmarks(b *testing.B, store storage.KeyValueStore) {
 		b.SetBytes(int64(len(items)))
 		for k := 0; k < b.N; k++ {
 			for _, item := range items {
-				_, err := store.Get(item.Key)
+				_, err := store.Get(ctx, item.Key)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -66,7 +66,7 @@ func RunBenchmarks(b *testing.B, store storage.KeyValueStore) {
 	b.Run("ListV2 5", func(b *testing.B) {
 		b.SetBytes(int64(len(items)))
 		for k := 0; k < b.N; k++ {
-			_, _, err := storage.ListV2(store, storage.ListOptions{
+			_, _, err := storage.ListV2(ctx, store, storage.ListOptions{
 				StartAfter: storage.Key("gamma"),
 				Limit:      5,
 			})
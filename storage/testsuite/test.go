@@ -22,6 +22,9 @@ func RunTests(t *testing.T, store storage.KeyValueStore) {
 
 	t.Run("List", func(t *testing.T) { testList(t, store) })
 	t.Run("ListV2", func(t *testing.T) { testListV2(t, store) })
+
+	t.Run("Batch", func(t *testing.T) { testBatch(t, store) })
+	t.Run("Txn", func(t *testing.T) { testTxn(t, store) })
 }
 
 func testConstraints(t *testing.T, store storage.KeyValueStore) {
@@ -34,7 +37,7 @@ func testConstraints(t *testing.T, store storage.KeyValueStore) {
 	}
 
 	for _, item := range items {
-		if err := store.Put(item.Key, item.Value); err != nil {
+		if err := store.Put(ctx, item.Key, item.Value); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -43,41 +46,41 @@ func testConstraints(t *testing.T, store storage.KeyValueStore) {
 	t.Run("Put Empty", func(t *testing.T) {
 		var key storage.Key
 		var val storage.Value
-		defer func() { _ = store.Delete(key) }()
+		defer func() { _ = store.Delete(ctx, key) }()
 
-		err := store.Put(key, val)
+		err := store.Put(ctx, key, val)
 		if err == nil {
 			t.Fatal("putting empty key should fail")
 		}
 	})
 
 	t.Run("GetAll limit", func(t *testing.T) {
-		_, err := store.GetAll(items[:storage.LookupLimit].GetKeys())
+		_, err := store.GetAll(ctx, items[:storage.LookupLimit].GetKeys())
 		if err != nil {
 			t.Fatalf("GetAll LookupLimit should succeed: %v", err)
 		}
 
-		_, err = store.GetAll(items[:storage.LookupLimit+1].GetKeys())
+		_, err = store.GetAll(ctx, items[:storage.LookupLimit+1].GetKeys())
 		if err == nil && err == storage.ErrLimitExceeded {
 			t.Fatalf("GetAll LookupLimit+1 should fail: %v", err)
 		}
 	})
 
 	t.Run("List limit", func(t *testing.T) {
-		keys, err := store.List(nil, storage.LookupLimit)
+		keys, err := store.List(ctx, nil, storage.LookupLimit)
 		if err != nil || len(keys) != storage.LookupLimit {
 			t.Fatalf("List LookupLimit should succeed: %v / got %d", err, len(keys))
 		}
-		keys, err = store.ReverseList(nil, storage.LookupLimit)
+		keys, err = store.ReverseList(ctx, nil, storage.LookupLimit)
 		if err != nil || len(keys) != storage.LookupLimit {
 			t.Fatalf("ReverseList LookupLimit should succeed: %v / got %d", err, len(keys))
 		}
 
-		_, err = store.List(nil, storage.LookupLimit+1)
+		_, err = store.List(ctx, nil, storage.LookupLimit+1)
 		if err != nil || len(keys) != storage.LookupLimit {
 			t.Fatalf("List LookupLimit+1 shouldn't fail: %v / got %d", err, len(keys))
 		}
-		_, err = store.ReverseList(nil, storage.LookupLimit+1)
+		_, err = store.ReverseList(ctx, nil, storage.LookupLimit+1)
 		if err != nil || len(keys) != storage.LookupLimit {
 			t.Fatalf("ReverseList LookupLimit+1 shouldn't fail: %v / got %d", err, len(keys))
 		}
@@ -0,0 +1,84 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"bytes"
+	"testing"
+
+	"storj.io/storj/storage"
+)
+
+func testBatch(t *testing.T, store storage.KeyValueStore) {
+	items := storage.Items{
+		newItem("batch/a", "a", false),
+		newItem("batch/b", "b", false),
+		newItem("batch/c", "c", false),
+		newItem("batch/d/e", "d/e", false),
+	}
+	defer cleanupItems(store, items)
+
+	t.Run("PutBatch", func(t *testing.T) {
+		if err := store.PutBatch(ctx, items); err != nil {
+			t.Fatalf("failed to put batch: %v", err)
+		}
+		for _, item := range items {
+			value, err := store.Get(ctx, item.Key)
+			if err != nil {
+				t.Fatalf("failed to get %q: %v", item.Key, err)
+			}
+			if !bytes.Equal(value, item.Value) {
+				t.Fatalf("invalid value for %q = %v: got %v", item.Key, item.Value, value)
+			}
+		}
+	})
+
+	t.Run("PutBatch Empty Key", func(t *testing.T) {
+		invalid := storage.Items{
+			newItem("batch/a", "updated", false),
+			newItem("", "empty", false),
+		}
+		if err := store.PutBatch(ctx, invalid); err == nil {
+			t.Fatal("putting a batch with an empty key should fail")
+		}
+		value, err := store.Get(ctx, storage.Key("batch/a"))
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		if string(value) != "a" {
+			t.Fatalf("failed batch shouldn't change anything: got %q", value)
+		}
+	})
+
+	t.Run("DeleteBatch", func(t *testing.T) {
+		deleted := storage.Keys{
+			storage.Key("batch/a"),
+			storage.Key("batch/c"),
+			storage.Key("batch/missing"),
+		}
+		if err := store.DeleteBatch(ctx, deleted); err != nil {
+			t.Fatalf("failed to delete batch: %v", err)
+		}
+
+		testIterations(t, store, []iterationTest{
+			{"after delete batch",
+				storage.IterateOptions{
+					Prefix:  storage.Key("batch/"),
+					Recurse: true,
+				}, storage.Items{
+					newItem("batch/b", "b", false),
+					newItem("batch/d/e", "d/e", false),
+				}},
+		})
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		if err := store.PutBatch(ctx, nil); err != nil {
+			t.Fatalf("putting an empty batch failed: %v", err)
+		}
+		if err := store.DeleteBatch(ctx, nil); err != nil {
+			t.Fatalf("deleting an empty batch failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"storj.io/storj/storage"
+)
+
+func testTxn(t *testing.T, store storage.KeyValueStore) {
+	items := storage.Items{
+		newItem("txn/a", "a", false),
+		newItem("txn/b", "b", false),
+	}
+	defer cleanupItems(store, append(items,
+		newItem("txn/c", "", false),
+		newItem("txn/conflict", "", false),
+		newItem("txn/counter", "", false)))
+	if err := storage.PutAll(ctx, store, items...); err != nil {
+		t.Fatalf("failed to setup: %v", err)
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		err := store.Txn(ctx, func(txn storage.Txn) error {
+			value, err := txn.Get(storage.Key("txn/a"))
+			if err != nil {
+				return err
+			}
+			if err := txn.Put(storage.Key("txn/c"), value); err != nil {
+				return err
+			}
+			if err := txn.Delete(storage.Key("txn/b")); err != nil {
+				return err
+			}
+
+			// the transaction sees its own writes
+			value, err = txn.Get(storage.Key("txn/c"))
+			if err != nil {
+				return err
+			}
+			if string(value) != "a" {
+				t.Errorf("transaction doesn't see its put: got %q", value)
+			}
+			if _, err := txn.Get(storage.Key("txn/b")); !storage.ErrKeyNotFound.Has(err) {
+				t.Errorf("transaction doesn't see its delete: got %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to run txn: %v", err)
+		}
+
+		testIterations(t, store, []iterationTest{
+			{"after commit",
+				storage.IterateOptions{
+					Prefix:  storage.Key("txn/"),
+					Recurse: true,
+				}, storage.Items{
+					newItem("txn/a", "a", false),
+					newItem("txn/c", "a", false),
+				}},
+		})
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		err := store.Txn(ctx, func(txn storage.Txn) error {
+			if err := txn.Put(storage.Key("txn/a"), storage.Value("updated")); err != nil {
+				return err
+			}
+			if err := txn.Delete(storage.Key("txn/c")); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if err != errAbort {
+			t.Fatalf("expected the error of the txn function, got %v", err)
+		}
+
+		testIterations(t, store, []iterationTest{
+			{"after rollback",
+				storage.IterateOptions{
+					Prefix:  storage.Key("txn/"),
+					Recurse: true,
+				}, storage.Items{
+					newItem("txn/a", "a", false),
+					newItem("txn/c", "a", false),
+				}},
+		})
+	})
+
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		key := storage.Key("txn/a")
+
+		err := storage.CompareAndSwap(ctx, store, key, storage.Value("wrong"), storage.Value("x"))
+		if !storage.ErrValueChanged.Has(err) {
+			t.Fatalf("expected value changed error, got %v", err)
+		}
+		err = storage.CompareAndSwap(ctx, store, key, nil, storage.Value("x"))
+		if !storage.ErrValueChanged.Has(err) {
+			t.Fatalf("expected value changed error for an existing key, got %v", err)
+		}
+
+		err = storage.CompareAndSwap(ctx, store, key, storage.Value("a"), storage.Value("x"))
+		if err != nil {
+			t.Fatalf("failed to swap: %v", err)
+		}
+		value, err := store.Get(ctx, key)
+		if err != nil || string(value) != "x" {
+			t.Fatalf("invalid value after swap: got %q, %v", value, err)
+		}
+
+		err = storage.CompareAndSwap(ctx, store, key, storage.Value("x"), nil)
+		if err != nil {
+			t.Fatalf("failed to swap to delete: %v", err)
+		}
+		if _, err := store.Get(ctx, key); !storage.ErrKeyNotFound.Has(err) {
+			t.Fatalf("expected deleted key, got %v", err)
+		}
+
+		err = storage.CompareAndSwap(ctx, store, key, nil, storage.Value("a"))
+		if err != nil {
+			t.Fatalf("failed to swap a missing key: %v", err)
+		}
+		value, err = store.Get(ctx, key)
+		if err != nil || string(value) != "a" {
+			t.Fatalf("invalid value after swap: got %q, %v", value, err)
+		}
+	})
+	t.Run("Conflict", func(t *testing.T) { testTxnConflict(t, store) })
+	t.Run("Concurrent", func(t *testing.T) { testTxnConcurrent(t, store) })
+}
+
+// testTxnConflict checks that a transaction whose read is changed by
+// another transaction before it commits fails with an ErrTxnConflict error.
+// Stores serializing their transactions only run the other transaction
+// once the first one is done, which is checked as well.
+func testTxnConflict(t *testing.T, store storage.KeyValueStore) {
+	key := storage.Key("txn/conflict")
+	if err := store.Put(ctx, key, storage.Value("v")); err != nil {
+		t.Fatalf("failed to setup: %v", err)
+	}
+
+	// appendTxn appends suffix to the value of key in a transaction,
+	// calling between after reading the value
+	appendTxn := func(suffix string, between func()) error {
+		return store.Txn(ctx, func(txn storage.Txn) error {
+			value, err := txn.Get(key)
+			if err != nil {
+				return err
+			}
+			between()
+			return txn.Put(key, append(value, suffix...))
+		})
+	}
+
+	other := make(chan error, 1)
+	err := appendTxn("a", func() {
+		go func() { other <- appendTxn("b", func() {}) }()
+		select {
+		case err := <-other:
+			// the other transaction committed in between
+			other <- err
+		case <-time.After(500 * time.Millisecond):
+			// the other transaction waits for this one
+		}
+	})
+	otherErr := <-other
+	if otherErr != nil {
+		t.Fatalf("failed to run the other txn: %v", otherErr)
+	}
+
+	value, getErr := store.Get(ctx, key)
+	if getErr != nil {
+		t.Fatalf("failed to get: %v", getErr)
+	}
+	switch {
+	case storage.ErrTxnConflict.Has(err):
+		if string(value) != "vb" {
+			t.Fatalf("expected the value of the other txn after a conflict, got %q", value)
+		}
+	case err == nil:
+		// the other transaction must have run after this one, otherwise
+		// its update was lost
+		if string(value) != "vab" {
+			t.Fatalf("expected the txns to be serialized, got %q", value)
+		}
+	default:
+		t.Fatalf("expected a conflict or a serialized txn, got %v", err)
+	}
+}
+
+// testTxnConcurrent checks that concurrent transactions incrementing a
+// counter, retried on conflicts, lose no increment
+func testTxnConcurrent(t *testing.T, store storage.KeyValueStore) {
+	key := storage.Key("txn/counter")
+	if err := store.Put(ctx, key, storage.Value("0")); err != nil {
+		t.Fatalf("failed to setup: %v", err)
+	}
+
+	const workers, increments = 4, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < increments; k++ {
+				err := storage.RetryTxn(ctx, store, func(txn storage.Txn) error {
+					value, err := txn.Get(key)
+					if err != nil {
+						return err
+					}
+					n, err := strconv.Atoi(string(value))
+					if err != nil {
+						return err
+					}
+					return txn.Put(key, storage.Value(strconv.Itoa(n+1)))
+				})
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("failed to increment: %v", err)
+	}
+
+	value, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if string(value) != strconv.Itoa(workers*increments) {
+		t.Fatalf("expected %d increments, got %s", workers*increments, value)
+	}
+}
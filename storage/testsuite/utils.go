@@ -4,6 +4,7 @@
 package testsuite
 
 import (
+	"context"
 	"testing"
 
 	"storj.io/storj/storage"
@@ -12,6 +13,8 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
+var ctx = context.Background()
+
 func newItem(key, value string, isPrefix bool) storage.ListItem {
 	return storage.ListItem{
 		Key:      storage.Key(key),
@@ -22,7 +25,7 @@ func newItem(key, value string, isPrefix bool) storage.ListItem {
 
 func cleanupItems(store storage.KeyValueStore, items storage.Items) {
 	for _, item := range items {
-		_ = store.Delete(item.Key)
+		_ = store.Delete(ctx, item.Key)
 	}
 }
 
@@ -36,7 +39,7 @@ func testIterations(t *testing.T, store storage.KeyValueStore, tests []iteration
 	t.Helper()
 	for _, test := range tests {
 		collect := &collector{}
-		err := store.Iterate(test.Options, collect.include)
+		err := store.Iterate(ctx, test.Options, collect.include)
 		if err != nil {
 			t.Errorf("%s: %v", test.Name, err)
 			continue
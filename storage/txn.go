@@ -0,0 +1,108 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+)
+
+// WriteSet collects the writes of a transaction until they are applied.
+// Later writes to a key replace earlier ones.
+type WriteSet struct {
+	writes map[string]write
+}
+
+type write struct {
+	value   Value
+	deleted bool
+}
+
+// Put records putting value at key
+func (set *WriteSet) Put(key Key, value Value) {
+	if set.writes == nil {
+		set.writes = map[string]write{}
+	}
+	set.writes[string(key)] = write{value: CloneValue(value)}
+}
+
+// Delete records deleting key
+func (set *WriteSet) Delete(key Key) {
+	if set.writes == nil {
+		set.writes = map[string]write{}
+	}
+	set.writes[string(key)] = write{deleted: true}
+}
+
+// Lookup returns the value written at key. ok is false when key wasn't
+// written, found is false when it was deleted.
+func (set *WriteSet) Lookup(key Key) (value Value, found, ok bool) {
+	w, ok := set.writes[string(key)]
+	if !ok {
+		return nil, false, false
+	}
+	if w.deleted {
+		return nil, false, true
+	}
+	return CloneValue(w.value), true, true
+}
+
+// Split returns the items to put and the keys to delete, sorted by key
+func (set *WriteSet) Split() (puts Items, deletes Keys) {
+	for key, w := range set.writes {
+		if w.deleted {
+			deletes = append(deletes, Key(key))
+			continue
+		}
+		puts = append(puts, ListItem{Key: Key(key), Value: w.value})
+	}
+	sort.Sort(puts)
+	sort.Slice(deletes, func(i, k int) bool { return deletes[i].Less(deletes[k]) })
+	return puts, deletes
+}
+
+// CompareAndSwap replaces the value at key with new if the current value is
+// old, otherwise it returns an ErrValueChanged error. A nil old value
+// expects the key not to exist and a nil new value deletes the key.
+func CompareAndSwap(ctx context.Context, store KeyValueStore, key Key, old, new Value) error {
+	return store.Txn(ctx, func(txn Txn) error {
+		current, err := txn.Get(key)
+		if err != nil && !ErrKeyNotFound.Has(err) {
+			return err
+		}
+		found := err == nil
+		if found != (old != nil) || !bytes.Equal(current, old) {
+			return ErrValueChanged.New(key.String())
+		}
+
+		if new == nil {
+			if !found {
+				return nil
+			}
+			return txn.Delete(key)
+		}
+		return txn.Put(key, new)
+	})
+}
+
+// TxnRetries is how many times RetryTxn runs a transaction conflicting with
+// other transactions before giving up
+const TxnRetries = 10
+
+// RetryTxn runs fn in a transaction of store like KeyValueStore.Txn, running
+// it again while the transaction conflicts with other transactions, at most
+// TxnRetries times. fn must not have side effects outside the transaction.
+func RetryTxn(ctx context.Context, store KeyValueStore, fn func(Txn) error) (err error) {
+	for i := 0; i < TxnRetries; i++ {
+		err = store.Txn(ctx, fn)
+		if !ErrTxnConflict.Has(err) {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return err
+}